@@ -8,38 +8,13 @@ import (
 	"path/filepath"
 
 	"github.com/dumu-tech/destination-cocktails/internal/config"
+	"github.com/dumu-tech/destination-cocktails/internal/seed"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"github.com/google/uuid"
-	"encoding/json"
 	"strings"
 )
 
-// MenuItem represents a product in the seed data JSON
-type MenuItem struct {
-	Name     string  `json:"name"`
-	Price    float64 `json:"price"`
-	Category string  `json:"category"`
-	Stock    int     `json:"stock"`
-}
-
-// MenuData holds the Chasers products to be seeded
-var ChasersData = []byte(`[
-  { "name": "Ice Cubes (Packet)", "price": 20, "category": "Chasers", "stock": 100 },
-  { "name": "Coca-Cola (Soda)", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Fanta Orange (Soda)", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Fanta Blackcurrant (Soda)", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Fanta Passion (Soda)", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Sprite (Soda)", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Krest Bitter Lemon", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Stoney Tangawizi", "price": 150, "category": "Chasers", "stock": 100 },
-  { "name": "Schweppes Tonic Water", "price": 200, "category": "Chasers", "stock": 100 },
-  { "name": "Power Play (Energy Drink)", "price": 250, "category": "Chasers", "stock": 100 },
-  { "name": "Red Bull (Energy Drink)", "price": 300, "category": "Chasers", "stock": 100 },
-  { "name": "Water (500ml)", "price": 50, "category": "Chasers", "stock": 100 }
-]`)
-
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -140,10 +115,9 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Parse Chasers data
-	var menuItems []MenuItem
-	if err := json.Unmarshal(ChasersData, &menuItems); err != nil {
-		log.Fatalf("Failed to parse Chasers data: %v", err)
+	menuItems, err := seed.LoadEmbedded(seed.ChasersFile)
+	if err != nil {
+		log.Fatalf("Failed to load Chasers data: %v", err)
 	}
 
 	if len(menuItems) == 0 {
@@ -151,64 +125,16 @@ func main() {
 		return
 	}
 
-	inserted := 0
-	updated := 0
-
-	// Upsert Chasers products
-	for _, item := range menuItems {
-		productID := uuid.New().String()
-
-		// Check if product with this name already exists
-		var existingID string
-		result := db.WithContext(ctx).Table("products").
-			Select("id").
-			Where("name = ?", item.Name).
-			Limit(1).
-			Scan(&existingID)
-
-		if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
-			log.Fatalf("Failed to check existing product %s: %v", item.Name, result.Error)
-		}
-
-		if existingID != "" {
-			// Update existing product
-			if err := db.WithContext(ctx).Table("products").
-				Where("id = ?", existingID).
-				Updates(map[string]interface{}{
-					"price":          item.Price,
-					"stock_quantity": item.Stock,
-					"category":       item.Category,
-					"is_active":      true,
-					"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
-				}).Error; err != nil {
-				log.Fatalf("Failed to update product %s: %v", item.Name, err)
-			}
-			updated++
-			log.Printf("  Updated: %s", item.Name)
-		} else {
-			// Insert new product
-			productMap := map[string]interface{}{
-				"id":             productID,
-				"name":           item.Name,
-				"description":    "",
-				"price":          item.Price,
-				"category":       item.Category,
-				"stock_quantity": item.Stock,
-				"image_url":      "",
-				"is_active":      true,
-			}
-			if err := db.WithContext(ctx).Table("products").Create(productMap).Error; err != nil {
-				log.Fatalf("Failed to insert product %s: %v", item.Name, err)
-			}
-			inserted++
-			log.Printf("  Inserted: %s", item.Name)
-		}
+	// Restore re-activates Chasers that were archived by an earlier run
+	result, err := seed.Upsert(ctx, db, menuItems, seed.Options{Restore: true})
+	if err != nil {
+		log.Fatalf("Seeder failed: %v", err)
 	}
 
 	log.Println("")
 	log.Println("=" + strings.Repeat("=", 60))
 	log.Printf("✓ Seeder completed: %d products processed (%d inserted, %d updated)", 
-		len(menuItems), inserted, updated)
+		len(menuItems), result.Inserted, result.Updated)
 	log.Println("=" + strings.Repeat("=", 60))
 	log.Println("")
 	log.Println("✅ All changes applied successfully!")
@@ -0,0 +1,342 @@
+// Command devtools prepares a local database for development: wipe and re-migrate it, load the menu,
+// create the test admin and fill the dashboard with historical orders. It refuses to touch anything
+// that doesn't look like a local or Docker database.
+//
+//	go run ./cmd/devtools -reset -admin -orders 300
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/postgres"
+	"github.com/dumu-tech/destination-cocktails/internal/config"
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/seed"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/dumu-tech/destination-cocktails/internal/testkit"
+	"github.com/jackc/pgx/v5/pgxpool"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// localHosts are database hosts devtools will write to: this machine and the docker-compose service names
+var localHosts = map[string]bool{
+	"localhost":            true,
+	"127.0.0.1":            true,
+	"::1":                  true,
+	"postgres":             true,
+	"db":                   true,
+	"host.docker.internal": true,
+}
+
+// nairobi is the bar's timezone; fake orders fall in its evening trading hours
+var nairobi = time.FixedZone("EAT", 3*60*60)
+
+func main() {
+	reset := flag.Bool("reset", false, "drop every table, re-run migrations/*.sql and load the menu")
+	seedMenu := flag.Bool("seed", false, "load the bundled menu (implied by -reset)")
+	admin := flag.Bool("admin", false, fmt.Sprintf("create the test manager %s with OTP %s", service.TestAdminPhone, service.TestAdminOTP))
+	orders := flag.Int("orders", 0, "number of fake historical orders to generate")
+	days := flag.Int("days", 30, "spread generated orders over this many days before today")
+	migrationsDir := flag.String("migrations", "migrations", "directory holding the SQL migrations")
+	flag.Parse()
+
+	if !*reset && !*seedMenu && !*admin && *orders <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	dbURL := cfg.DBURL
+	if err := checkLocalDatabase(dbURL, cfg.AppEnv); err != nil {
+		log.Fatalf("Refusing to run: %v", err)
+	}
+	log.Printf("Using local database %s", redactPassword(dbURL))
+
+	ctx := context.Background()
+
+	if *reset {
+		if err := resetSchema(ctx, dbURL, *migrationsDir); err != nil {
+			log.Fatalf("Reset failed: %v", err)
+		}
+	}
+
+	if *reset || *seedMenu {
+		if err := loadMenu(ctx, dbURL); err != nil {
+			log.Fatalf("Seeding menu failed: %v", err)
+		}
+	}
+
+	repo, err := postgres.NewRepository(dbURL, postgres.PoolConfig{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if *admin {
+		if err := ensureTestAdmin(ctx, repo); err != nil {
+			log.Fatalf("Creating test admin failed: %v", err)
+		}
+	}
+
+	if *orders > 0 {
+		if err := generateOrders(ctx, repo, *orders, *days); err != nil {
+			log.Fatalf("Generating orders failed: %v", err)
+		}
+	}
+
+	log.Println("✓ Done")
+}
+
+// checkLocalDatabase rejects anything but a non-production database on this machine or in Docker
+func checkLocalDatabase(dbURL string, appEnv string) error {
+	if strings.EqualFold(appEnv, "production") {
+		return fmt.Errorf("APP_ENV is production")
+	}
+	if os.Getenv("RAILWAY_ENVIRONMENT") != "" {
+		return fmt.Errorf("running on Railway")
+	}
+
+	parsed, err := url.Parse(dbURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("can't tell where DB_URL points")
+	}
+
+	lowered := strings.ToLower(dbURL)
+	if strings.Contains(lowered, "railway") || strings.Contains(lowered, "rlwy.net") {
+		return fmt.Errorf("DB_URL points at Railway")
+	}
+	if host := strings.ToLower(parsed.Hostname()); !localHosts[host] {
+		return fmt.Errorf("DB_URL host %q isn't a local or Docker database", host)
+	}
+	return nil
+}
+
+// resetSchema drops the public schema and re-applies every migration in filename order
+func resetSchema(ctx context.Context, dbURL string, migrationsDir string) error {
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", migrationsDir)
+	}
+	sort.Strings(files)
+
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	if _, err := pool.Exec(ctx, "DROP SCHEMA IF EXISTS public CASCADE; CREATE SCHEMA public;"); err != nil {
+		return fmt.Errorf("failed to drop schema: %w", err)
+	}
+	log.Println("✓ Dropped all tables")
+
+	for _, file := range files {
+		sqlContent, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if _, err := pool.Exec(ctx, string(sqlContent)); err != nil {
+			return fmt.Errorf("failed to apply %s: %w", filepath.Base(file), err)
+		}
+	}
+	log.Printf("✓ Applied %d migrations", len(files))
+	return nil
+}
+
+// loadMenu upserts the bundled menu the same way cmd/seeder does
+func loadMenu(ctx context.Context, dbURL string) error {
+	items, err := seed.LoadEmbedded(seed.MenuFile)
+	if err != nil {
+		return fmt.Errorf("failed to load seed data: %w", err)
+	}
+
+	db, err := gorm.Open(gormpostgres.Open(dbURL), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	result, err := seed.Upsert(ctx, db, items, seed.Options{})
+	if err != nil {
+		return err
+	}
+	log.Printf("✓ Menu seeded (%d inserted, %d updated)", result.Inserted, result.Updated)
+	return nil
+}
+
+// ensureTestAdmin makes the test admin an active manager and stores a long-lived OTP for it,
+// so the dashboard login works without WhatsApp
+func ensureTestAdmin(ctx context.Context, repo *postgres.Repository) error {
+	admins := repo.AdminUserRepository()
+	ids := core.UUIDGenerator{}
+	now := time.Now()
+
+	existing, err := admins.GetByPhone(ctx, service.TestAdminPhone)
+	if err != nil {
+		if err := admins.Create(ctx, &core.AdminUser{
+			ID:          ids.NewID(),
+			PhoneNumber: service.TestAdminPhone,
+			Name:        "Dev Manager",
+			Role:        core.AdminRoleManager,
+			IsActive:    true,
+			CreatedAt:   now,
+		}); err != nil {
+			return err
+		}
+	} else {
+		existing.Role = core.AdminRoleManager
+		existing.IsActive = true
+		if err := admins.Update(ctx, existing); err != nil {
+			return err
+		}
+	}
+
+	if err := repo.OTPRepository().Create(ctx, &core.OTPCode{
+		ID:          ids.NewID(),
+		PhoneNumber: service.TestAdminPhone,
+		Code:        service.TestAdminOTP,
+		ExpiresAt:   now.Add(30 * 24 * time.Hour),
+		CreatedAt:   now,
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("✓ Test admin ready: phone %s, OTP %s", service.TestAdminPhone, service.TestAdminOTP)
+	return nil
+}
+
+// generateOrders creates settled, failed and cancelled orders spread over the evenings of the last days,
+// with status history and M-Pesa ledger entries dated as if they'd happened then
+func generateOrders(ctx context.Context, repo *postgres.Repository, count int, days int) error {
+	if days < 1 {
+		days = 1
+	}
+
+	products, err := repo.ProductRepository().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(products) == 0 {
+		return fmt.Errorf("no products on the menu; run with -seed first")
+	}
+
+	clock := testkit.NewFakeClock(time.Now())
+	repo.SetClock(clock)
+	defer repo.SetClock(core.SystemClock{})
+
+	ids := core.UUIDGenerator{}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	now := time.Now().In(nairobi)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, nairobi)
+
+	for i := 0; i < count; i++ {
+		// Opening hours 17:00-02:00
+		day := today.AddDate(0, 0, -1-rng.Intn(days))
+		createdAt := day.Add(17*time.Hour + time.Duration(rng.Intn(9*60))*time.Minute)
+		clock.Set(createdAt)
+
+		phone := fmt.Sprintf("2547990%05d", rng.Intn(60))
+		user, err := repo.UserRepository().GetOrCreateByPhone(ctx, phone)
+		if err != nil {
+			return err
+		}
+
+		order := fakeOrder(rng, ids, products, user.ID, phone, createdAt)
+		if err := repo.OrderRepository().CreateOrder(ctx, order); err != nil {
+			return err
+		}
+
+		if order.PaymentMethod == string(core.PaymentMethodMpesa) && order.AmountPaid > 0 {
+			if err := repo.PaymentRepository().Create(ctx, &core.Payment{
+				ID:        ids.NewID(),
+				Provider:  core.PaymentProviderKopoKopo,
+				Reference: order.PaymentRef,
+				Phone:     "+" + phone,
+				PayerName: "Dev Customer",
+				Amount:    order.AmountPaid,
+				Currency:  "KES",
+				Status:    "Success",
+				OrderID:   order.ID,
+				CreatedAt: createdAt.Add(time.Minute),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Printf("✓ Generated %d orders over the last %d days", count, days)
+	return nil
+}
+
+// fakeOrder builds an order in a final state: mostly completed, some failed or cancelled
+func fakeOrder(rng *rand.Rand, ids core.IDGenerator, products []*core.Product, userID string, phone string, createdAt time.Time) *core.Order {
+	order := &core.Order{
+		ID:            ids.NewID(),
+		UserID:        userID,
+		CustomerPhone: phone,
+		PaymentMethod: string(core.PaymentMethodMpesa),
+		PickupCode:    fmt.Sprintf("%04d", rng.Intn(10000)),
+		CreatedAt:     createdAt,
+	}
+
+	lines := 1 + rng.Intn(3)
+	for j := 0; j < lines; j++ {
+		product := products[rng.Intn(len(products))]
+		quantity := 1 + rng.Intn(3)
+		order.Items = append(order.Items, core.OrderItem{
+			ID:          ids.NewID(),
+			OrderID:     order.ID,
+			ProductID:   product.ID,
+			Quantity:    quantity,
+			PriceAtTime: product.Price,
+		})
+		order.TotalAmount += product.Price * float64(quantity)
+	}
+	order.TotalAmount = math.Round(order.TotalAmount*100) / 100
+
+	switch roll := rng.Intn(100); {
+	case roll < 8:
+		order.Status = core.OrderStatusFailed
+	case roll < 12:
+		order.Status = core.OrderStatusCancelled
+	default:
+		if roll >= 85 {
+			order.PaymentMethod = string(core.PaymentMethodCash)
+		} else {
+			order.PaymentRef = fmt.Sprintf("DEV%08d", rng.Intn(100000000))
+		}
+		readyAt := createdAt.Add(time.Duration(3+rng.Intn(12)) * time.Minute)
+		completedAt := readyAt.Add(time.Duration(1+rng.Intn(10)) * time.Minute)
+		order.Status = core.OrderStatusCompleted
+		order.AmountPaid = order.TotalAmount
+		order.ReadyAt = &readyAt
+		order.CompletedAt = &completedAt
+	}
+	return order
+}
+
+// redactPassword hides the password in a database URL for logging
+func redactPassword(dbURL string) string {
+	parsed, err := url.Parse(dbURL)
+	if err != nil {
+		return "<unparseable>"
+	}
+	return parsed.Redacted()
+}
@@ -4,37 +4,78 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/adapters/http"
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/nlu"
 	"github.com/dumu-tech/destination-cocktails/internal/adapters/payment"
 	"github.com/dumu-tech/destination-cocktails/internal/adapters/postgres"
 	"github.com/dumu-tech/destination-cocktails/internal/adapters/redis"
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/sentry"
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/sheets"
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/sms"
 	"github.com/dumu-tech/destination-cocktails/internal/adapters/whatsapp"
 	"github.com/dumu-tech/destination-cocktails/internal/config"
+	"github.com/dumu-tech/destination-cocktails/internal/core"
 	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/logging"
 	"github.com/dumu-tech/destination-cocktails/internal/middleware"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
 	"github.com/dumu-tech/destination-cocktails/internal/service"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	goredis "github.com/redis/go-redis/v9"
 )
 
 func main() {
+	// Structured logs carry the request ID from the context; plain log.Printf output stays as it was
+	slog.SetDefault(slog.New(logging.NewRequestIDHandler(slog.NewTextHandler(os.Stderr, nil))))
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.LstdFlags)
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Report errors from fire-and-forget goroutines and recovered panics (they're always logged too)
+	if cfg.SentryDSN != "" {
+		reporter, err := sentry.NewReporter(cfg.SentryDSN, cfg.AppRelease, cfg.AppEnv)
+		if err != nil {
+			log.Printf("Error reporting disabled: %v", err)
+		} else {
+			reporting.SetReporter(reporter)
+			log.Printf("✓ Error reporting enabled (environment: %s, release: %s)", cfg.AppEnv, cfg.AppRelease)
+		}
+	}
+
 	// Initialize database connection
-	db, err := postgres.NewRepository(cfg.DBURL)
+	dbPool := postgres.PoolConfig{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+	}
+	db, err := postgres.NewRepository(cfg.DBURL, dbPool)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	log.Println("✓ Database connected")
 
+	// Analytics and report queries go to the read replica when one is configured
+	if cfg.DBReadReplicaURL != "" {
+		if err := db.SetReadReplica(cfg.DBReadReplicaURL, dbPool); err != nil {
+			log.Fatalf("Failed to connect to read replica: %v", err)
+		}
+		log.Println("✓ Read replica connected")
+	}
+
 	// Initialize Redis client
 	redisOpts, err := goredis.ParseURL(cfg.RedisURL)
 	if err != nil {
@@ -56,25 +97,102 @@ func main() {
 
 	// Initialize Redis session repository
 	sessionRepo := redis.NewRepository(redisClient)
+	sessionRepo.ConfigureTTL(cfg.SessionTTL, cfg.SessionSlidingTTL)
 
 	// Initialize WhatsApp client
 	whatsappClient := whatsapp.NewClient(
 		cfg.WhatsAppPhoneNumberID,
 		cfg.WhatsAppToken,
 	)
+	whatsappClient.SetRateLimit(cfg.WhatsAppRateLimit)
+	var outboundStore *redis.OutboundMessageStore
+	if cfg.WhatsAppRetryEnabled {
+		outboundStore = redis.NewOutboundMessageStore(redisClient)
+		whatsappClient.EnableRetries(outboundStore, cfg.WhatsAppRetryMaxAttempts)
+		go whatsappClient.RunRetryWorker(context.Background())
+	}
 	log.Println("✓ WhatsApp client initialized")
 
+	// SMS fallback for login codes and payment confirmations
+	var smsGateway core.SMSGateway
+	switch strings.ToLower(cfg.SMSProvider) {
+	case "":
+	case "africastalking":
+		smsClient, err := sms.NewAfricasTalkingClient(cfg.AfricasTalkingUsername, cfg.AfricasTalkingAPIKey, cfg.AfricasTalkingSenderID)
+		if err != nil {
+			log.Fatalf("Failed to initialize Africa's Talking SMS gateway: %v", err)
+		}
+		smsGateway = smsClient
+		log.Println("✓ SMS gateway initialized (Africa's Talking)")
+	default:
+		log.Fatalf("Unsupported SMS_PROVIDER %q: use africastalking or leave it empty", cfg.SMSProvider)
+	}
+	paymentRoute, err := service.ParseMessageRoute(cfg.SMSPaymentRoute)
+	if err != nil {
+		log.Fatalf("Invalid SMS_PAYMENT_ROUTE: %v", err)
+	}
+	criticalMessenger := service.NewCriticalMessenger(whatsappClient, smsGateway, paymentRoute)
+
+	// Login codes go out over OTP_CHANNEL; the console sender only logs them and is for local development
+	otpSender, err := service.NewOTPSender(cfg.OTPChannel, whatsappClient, smsGateway)
+	if err != nil {
+		log.Fatalf("Invalid OTP_CHANNEL: %v", err)
+	}
+	if strings.EqualFold(cfg.OTPChannel, service.OTPChannelConsole) && !cfg.IsDevelopment() {
+		log.Fatalf("OTP_CHANNEL=console only works with APP_ENV=development")
+	}
+
 	// Initialize Kopo Kopo payment gateway
 	paymentGateway, err := payment.NewClient()
 	if err != nil {
 		log.Fatalf("Failed to initialize payment gateway: %v", err)
 	}
+	var stkQueue *redis.STKPushQueue
+	if cfg.STKQueuePersistent {
+		stkQueue = redis.NewSTKPushQueue(redisClient)
+		paymentGateway.UsePersistentQueue(stkQueue, cfg.STKQueueMaxAttempts, cfg.STKQueueVisibility)
+	}
 	log.Println("✓ Payment gateway initialized")
 
 	// Initialize repositories
 	productRepo := db.ProductRepository()
 	orderRepo := db.OrderRepository()
 	userRepo := db.UserRepository()
+	stkAttemptRepo := db.STKAttemptRepository()
+	paymentGateway.SetAttemptRepository(stkAttemptRepo)
+
+	// Initialize EventBus (wired to the handler and dashboard below)
+	eventBus := events.NewEventBus()
+	eventBus.SetReplaySize(cfg.EventBusReplaySize)
+	eventBus.SetSubscriberLimits(cfg.EventBusSubscriberBuffer, cfg.EventBusStallTimeout)
+	if strings.EqualFold(cfg.EventBusBackend, "redis") {
+		eventBus.UseBroker(context.Background(), redis.NewEventBroker(redisClient, cfg.EventBusChannel))
+		log.Printf("✓ Event bus using Redis pub/sub (channel: %s)", cfg.EventBusChannel)
+	}
+
+	// Runtime settings: cached from the settings table, reloaded when any replica changes one
+	settingsService := service.NewSettingsService(db.SettingsRepository(), eventBus)
+	if cfg.SessionTTL > 0 {
+		if err := settingsService.SetDefault(service.SettingSessionTTL, strconv.Itoa(int(cfg.SessionTTL/time.Second))); err != nil {
+			log.Printf("Ignoring SESSION_TTL as the session.ttl_seconds default: %v", err)
+		}
+	}
+	go settingsService.Run(context.Background())
+
+	// WhatsApp numbers besides WHATSAPP_PHONE_NUMBER_ID: customers are answered from the number they messaged,
+	// with its token from the whatsapp_numbers table, and see only its venue's categories
+	whatsAppNumbers := service.NewWhatsAppNumbers(db.WhatsAppNumberRepository(), eventBus)
+	go whatsAppNumbers.Run(context.Background())
+	whatsappClient.SetSenderResolver(whatsAppNumbers)
+
+	// Menu cache: the bot reads the menu and searches products on most messages
+	var productCache *service.ProductCache
+	if cfg.ProductCacheTTL > 0 {
+		productCache = service.NewProductCache(productRepo, eventBus, cfg.ProductCacheTTL)
+		go productCache.Run(context.Background())
+		productRepo = productCache
+		log.Printf("✓ Product cache enabled (TTL: %s)", cfg.ProductCacheTTL)
+	}
 
 	// Initialize bot service
 	botService := service.NewBotService(
@@ -85,6 +203,89 @@ func main() {
 		orderRepo,
 		userRepo,
 	)
+	botService.Repo = service.NewVenueCatalog(productRepo, whatsAppNumbers)
+	botService.PickupCodes = service.NewPickupCodeGenerator(orderRepo, cfg.PickupCodeFormat, cfg.PickupCodeLength)
+	productOptionRepo := db.ProductOptionRepository()
+	botService.Options = productOptionRepo
+	bundleRepo := db.BundleRepository()
+	botService.Bundles = bundleRepo
+	recipeRepo := db.RecipeRepository()
+	botService.Recipes = recipeRepo
+	botService.Tax = core.TaxPolicy{Rate: cfg.VATRate, Inclusive: cfg.VATPricesInclusive}
+	botService.TipsEnabled = cfg.TipsEnabled
+	botService.NotesEnabled = cfg.OrderNotesEnabled
+	botService.CheckoutFlowID = cfg.WhatsAppCheckoutFlowID
+	botService.Ordering = settingsService
+	switch strings.ToLower(cfg.NLUProvider) {
+	case "":
+	case "openai":
+		// Free-text messages the intent parser can't place are mapped to actions by the model, checked against the menu
+		interpreter, err := nlu.NewOpenAIInterpreter(cfg.NLUBaseURL, cfg.NLUAPIKey, cfg.NLUModel, cfg.NLUTimeout)
+		if err != nil {
+			// Ordering still works without the assistant, through the menus and intent parser
+			log.Printf("LLM ordering assistant disabled: %v", err)
+			break
+		}
+		botService.Interpreter = interpreter
+		log.Printf("✓ LLM ordering assistant enabled (model: %s)", cfg.NLUModel)
+	default:
+		log.Fatalf("Unsupported NLU_PROVIDER %q: use openai or leave it empty", cfg.NLUProvider)
+	}
+	botService.Settings = settingsService
+	var sessionLocks *service.SessionLocker
+	if cfg.SessionLockTTL > 0 {
+		// Two replicas handling the same customer's messages at once would overwrite each other's session
+		sessionLocks = service.NewSessionLocker(redis.NewSessionLockStore(redisClient), cfg.SessionLockTTL, cfg.SessionLockWait)
+		botService.Locks = sessionLocks
+	}
+	blocklist := service.NewBlocklist(db.BlockedCustomerRepository(), cfg.BlocklistFlagFailedPayments, cfg.BlocklistFlagWindow)
+	botService.Blocklist = blocklist
+	botService.BlockedReply = cfg.BlockedCustomerReply
+	tabRepo := db.TabRepository()
+	if cfg.TabsEnabled {
+		botService.Tabs = tabRepo
+	}
+	botService.AgeGate = cfg.AgeGateEnabled
+	// Per-order caps ("max 2 Spirits") are managed from the dashboard; with none set nothing is limited
+	purchaseLimiter := service.NewPurchaseLimiter(db.PurchaseLimitRepository(), productRepo)
+	botService.Limits = purchaseLimiter
+	if cfg.FavoritesEnabled {
+		botService.Favorites = db.FavoriteRepository()
+	}
+	var reservationService *service.ReservationService
+	if cfg.ReservationsEnabled {
+		reservationService = service.NewReservationService(db.ReservationRepository(), userRepo, whatsappClient, paymentGateway, service.ReservationPolicy{
+			TableDeposit: cfg.ReservationTableDeposit,
+			VIPDeposit:   cfg.ReservationVIPDeposit,
+			MaxPartySize: cfg.ReservationMaxParty,
+			MaxDaysAhead: cfg.ReservationMaxDaysAhead,
+			ReminderHour: cfg.ReservationReminderHour,
+		})
+		botService.Reservations = reservationService
+		go reservationService.Run(context.Background())
+	}
+	// "People also add" chasers after a cocktail; managers switch it off with the bot.suggestions_enabled setting
+	botService.Suggestions = service.NewSuggester(db.AnalyticsRepository())
+	// Sold-out products offer "Notify me"; managers switch it off with the menu.restock_alerts setting
+	botService.RestockAlerts = db.RestockAlertRepository()
+	restockNotifier := service.NewRestockNotifier(productRepo, db.RestockAlertRepository(), userRepo, whatsappClient, settingsService, eventBus)
+	go restockNotifier.Run(context.Background())
+	botService.PreOrders = cfg.ScheduledOrdersEnabled
+	botService.PreOrderLead = cfg.ScheduledOrderLeadTime
+	botService.PreOrderWindow = cfg.ScheduledOrderMaxAhead
+	botService.Delivery = cfg.DeliveryEnabled
+	botService.DeliveryFee = cfg.DeliveryFee
+	if cfg.SessionTTL > 0 {
+		botService.SessionTTL = int(cfg.SessionTTL / time.Second)
+	}
+	if cfg.CartReminderEnabled {
+		cartReminder := service.NewCartReminder(sessionRepo, sessionRepo, userRepo, whatsappClient, cfg.CartReminderIdle, cfg.CartReminderCap)
+		go cartReminder.Run(context.Background())
+	}
+	if cfg.PendingOrderExpiry > 0 {
+		pendingOrderReaper := service.NewPendingOrderReaper(orderRepo, botService, cfg.PendingOrderExpiry)
+		go pendingOrderReaper.Run(context.Background())
+	}
 	log.Println("✓ Bot service initialized")
 
 	// Initialize HTTP handler
@@ -94,12 +295,74 @@ func main() {
 		orderRepo,
 		whatsappClient,
 	)
+	httpHandler.SetLanguageResolver(botService)
+	httpHandler.SetWhatsAppNumbers(whatsAppNumbers)
+	httpHandler.SetFailedPaymentRecorder(blocklist)
+	httpHandler.SetPaymentConfirmationSender(criticalMessenger)
+	if cfg.FavoritesEnabled {
+		httpHandler.SetFavoriteOffer(botService)
+	}
+	if reservationService != nil {
+		httpHandler.SetReservationPayments(reservationService)
+	}
+	if cfg.WhatsAppSendReceipts {
+		httpHandler.SetReceiptSender(service.NewReceiptSender(whatsappClient))
+	}
 	log.Println("✓ HTTP handler initialized")
 
-	// Initialize EventBus and wire it to handler and dashboard
-	eventBus := events.NewEventBus()
 	httpHandler.SetEventBus(eventBus)
 
+	// Bar staff roster: paid orders go to on-shift bartenders (BAR_STAFF_PHONE is the fallback)
+	barStaffRepo := db.BarStaffRepository()
+	staffNotifier := service.NewBarStaffNotifier(
+		barStaffRepo,
+		orderRepo,
+		whatsappClient,
+		cfg.BarStaffNotifyMode,
+		cfg.BarStaffPhone,
+	)
+	httpHandler.SetBarStaffNotifier(staffNotifier)
+	httpHandler.SetLatePaymentAlerts(service.NewLatePaymentAlerts(db.AdminUserRepository(), whatsappClient, eventBus))
+	if cfg.PayAtBarEnabled {
+		botService.BarStaff = staffNotifier
+	}
+
+	// Shifts: bartenders clock in/out from the dashboard or by WhatsApp; managers get each shift's summary
+	shiftService := service.NewShiftService(db.ShiftRepository(), barStaffRepo, db.AdminUserRepository(), whatsappClient)
+	httpHandler.SetShiftCommandHandler(shiftService)
+	if cfg.WhatsAppDedupTTL > 0 {
+		httpHandler.SetProcessedMessageStore(redis.NewProcessedMessageStore(redisClient), cfg.WhatsAppDedupTTL)
+	}
+
+	// Riders roster: dispatched delivery orders are offered to available riders; the first to accept takes it
+	riderRepo := db.RiderRepository()
+	riderNotifier := service.NewRiderNotifier(riderRepo, orderRepo, whatsappClient, eventBus)
+	httpHandler.SetRiderNotifier(riderNotifier)
+
+	if cfg.PickupReminderEnabled {
+		pickupReminder := service.NewPickupReminder(orderRepo, userRepo, whatsappClient, staffNotifier, eventBus, cfg.PickupReminderAfter, cfg.PickupEscalationAfter)
+		go pickupReminder.Run(context.Background())
+	}
+
+	if cfg.PrepSLA > 0 {
+		prepSLAMonitor := service.NewPrepSLAMonitor(orderRepo, eventBus, cfg.PrepSLA)
+		go prepSLAMonitor.Run(context.Background())
+	}
+
+	// Paid pre-orders wait as SCHEDULED; release them to the bar queue even when new pre-orders are switched off
+	scheduledReleaser := service.NewScheduledOrderReleaser(orderRepo, staffNotifier, eventBus, cfg.ScheduledOrderLeadTime)
+	go scheduledReleaser.Run(context.Background())
+
+	// Outbox: paid-order side effects are stored with the payment and delivered (and retried) from there
+	outboxDispatcher := service.NewOutboxDispatcher(db.OutboxRepository(), cfg.OutboxPollInterval, cfg.OutboxMaxAttempts)
+	httpHandler.RegisterOutboxHandlers(outboxDispatcher)
+
+	// Payments ledger: every confirmed webhook transaction, matched or orphaned
+	paymentRepo := db.PaymentRepository()
+	httpHandler.SetPaymentRepository(paymentRepo)
+	httpHandler.SetSTKAttemptRepository(stkAttemptRepo)
+	httpHandler.SetWebhookEventRepository(db.WebhookEventRepository())
+
 	// Initialize DashboardService and DashboardHandler
 	dashboardService := service.NewDashboardService(
 		db.AdminUserRepository(),
@@ -111,9 +374,82 @@ func main() {
 		eventBus,
 		cfg.JWTSecret,
 	)
+	dashboardService.SetBarStaffRepository(barStaffRepo)
+	dashboardService.SetOTPSender(otpSender)
+	dashboardService.SetOTPResendCooldown(cfg.OTPResendCooldown)
+	dashboardService.SetTestAdminOTP(cfg.IsDevelopment())
+	dashboardService.SetPaymentRepository(paymentRepo)
+	dashboardService.SetSTKAttemptRepository(stkAttemptRepo)
+	dashboardService.SetPaymentWebhookSubscriptions(paymentGateway)
+	dashboardService.SetProductOptionRepository(productOptionRepo)
+	dashboardService.SetBundleRepository(bundleRepo)
+	dashboardService.SetRecipeRepository(recipeRepo)
+	dashboardService.SetStocktakeRepository(db.StocktakeRepository())
+	dashboardService.SetPurchasingRepositories(db.SupplierRepository(), db.PurchaseOrderRepository())
+	dashboardService.SetAuditLogRepository(db.AuditLogRepository())
+	dashboardService.SetWebhookEventRepository(db.WebhookEventRepository())
+	dashboardService.SetSessionStore(sessionRepo, sessionRepo)
+	broadcastRepo := db.BroadcastRepository()
+	dashboardService.SetBroadcastRepository(broadcastRepo)
+	feedbackRepo := db.FeedbackRepository()
+	dashboardService.SetFeedbackRepository(feedbackRepo, cfg.FeedbackAlertMaxRating)
+	dashboardService.SetPrepSLA(cfg.PrepSLA)
+	dashboardService.SetBarStaffNotifier(staffNotifier)
+	dashboardService.SetOutbox(outboxDispatcher)
+	dashboardService.SetShiftService(shiftService)
+	dashboardService.SetRefreshTokenStore(redis.NewRefreshTokenStore(redisClient))
+	dashboardService.SetOrderingStatusStore(settingsService)
+	dashboardService.SetSettingsService(settingsService)
+	dashboardService.SetWhatsAppNumbers(whatsAppNumbers)
+	dashboardService.SetBlocklist(blocklist)
+	dashboardService.SetTabRepository(tabRepo)
+	dashboardService.SetReservationService(reservationService)
+	dashboardService.SetPurchaseLimiter(purchaseLimiter)
+	dashboardService.SetRiderRepository(riderRepo)
+	dashboardService.SetDeliveryNotifier(riderNotifier)
+	dashboardService.SetTokenLifetimes(cfg.JWTAccessTTL, cfg.JWTRefreshTTL)
+	// Ingredients poured for paid orders go out as stock_updated/low_stock like any other stock change
+	outboxDispatcher.Handle(core.OutboxTopicPaidStock, dashboardService.PublishOrderDrawdown)
+	go outboxDispatcher.Run(context.Background())
+	if outboundStore != nil {
+		dashboardService.SetOutboundMessageStore(outboundStore)
+	}
+	if stkQueue != nil {
+		dashboardService.SetSTKPushQueue(stkQueue)
+	}
+	if cfg.GoogleSheetsSpreadsheetID != "" {
+		// The owner's sales spreadsheet gets a row per closed business day
+		sheetClient, err := sheets.NewGoogleSheetsClient(cfg.GoogleSheetsCredentials, cfg.GoogleSheetsSpreadsheetID, cfg.GoogleSheetsSheetName)
+		if err != nil {
+			log.Fatalf("Invalid Google Sheets configuration: %v", err)
+		}
+		salesSheetSync := service.NewSalesSheetSync(dashboardService, sheetClient, db.SalesSheetSyncRepository())
+		dashboardService.SetSalesSheetSync(salesSheetSync)
+		go salesSheetSync.Run(context.Background())
+		log.Printf("✓ Google Sheets sales sync enabled (sheet: %s)", cfg.GoogleSheetsSheetName)
+	}
 	dashboardHandler := http.NewDashboardHandler(dashboardService)
+	if cfg.BroadcastEnabled {
+		broadcastSender := service.NewBroadcastSender(broadcastRepo, userRepo, whatsappClient, cfg.BroadcastRatePerMinute)
+		go broadcastSender.Run(context.Background())
+	}
+	if cfg.FeedbackEnabled {
+		feedbackCollector := service.NewFeedbackCollector(feedbackRepo, userRepo, db.AdminUserRepository(), whatsappClient, cfg.FeedbackRequestDelay, cfg.FeedbackAlertMaxRating)
+		botService.Feedback = feedbackCollector
+		go feedbackCollector.Run(context.Background())
+	}
 	log.Println("✓ Dashboard API initialized")
 
+	// Payments only mark orders paid if Kopo Kopo posts to our callback; check in the background so startup isn't blocked
+	if cfg.KopoKopoCallbackURL != "" {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			defer reporting.Recover(ctx, "payment.webhook_subscription_check")
+			dashboardService.CheckPaymentWebhookSubscription(ctx)
+		}()
+	}
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
@@ -122,67 +458,89 @@ func main() {
 				code = e.Code
 			}
 			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
+				"error":      err.Error(),
+				"request_id": middleware.RequestIDOf(c),
 			})
 		},
 	})
 
 	// Middleware
-	app.Use(recover.New())
-	app.Use(logger.New())
-	allowedOrigin := cfg.AllowedOrigin
-	if allowedOrigin == "" {
-		allowedOrigin = "*"
-	}
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     allowedOrigin,
-		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
-		ExposeHeaders:    "Content-Disposition",
-		AllowCredentials: allowedOrigin != "*",
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e interface{}) {
+			reporting.CapturePanic(c.UserContext(), c.Method()+" "+c.Route().Path, e)
+		},
+	}))
+	app.Use(middleware.RequestID())
+	app.Use(logger.New(logger.Config{
+		Format: "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${respHeader:X-Request-ID} | ${error}\n",
 	}))
+	app.Use(middleware.CORS(cfg.CORSAllowedOrigins))
 
-	// Health check
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"service": "destination-cocktails",
-		})
-	})
+	// Health checks: live is a cheap process check, ready pings Postgres and Redis (and optionally WhatsApp)
+	healthProbes := []http.HealthProbe{
+		{Name: "postgres", Check: db.Ping},
+		{Name: "redis", Check: func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }},
+	}
+	if db.HasReadReplica() {
+		// Optional: only reports read from the replica, so a lagging or down replica shouldn't pull the API out of rotation
+		healthProbes = append(healthProbes, http.HealthProbe{Name: "postgres_replica", Check: db.PingReadReplica, Optional: true})
+	}
+	if cfg.HealthCheckWhatsApp {
+		healthProbes = append(healthProbes, http.HealthProbe{Name: "whatsapp", Check: whatsappClient.Ping, Optional: true})
+	}
+	healthHandler := http.NewHealthHandler(cfg.HealthCheckTimeout, healthProbes...)
+	app.Get("/health", healthHandler.Live)
+	app.Get("/health/live", healthHandler.Live)
+	app.Get("/health/ready", healthHandler.Ready)
+
+	// Prometheus metrics: database connection pool, cache, session lock and event subscriber statistics
+	metricsHandler := http.NewMetricsHandler(db.PoolStats)
+	metricsHandler.SetEventBus(eventBus)
+	if productCache != nil {
+		metricsHandler.AddCache("products", productCache)
+	}
+	if sessionLocks != nil {
+		metricsHandler.SetSessionLocks(sessionLocks)
+	}
+	app.Get("/metrics", metricsHandler.Metrics)
 
 	// WhatsApp webhook routes
 	app.Get("/api/webhooks/whatsapp", httpHandler.VerifyWebhook)
-	app.Post("/api/webhooks/whatsapp", httpHandler.ReceiveMessage)
+	// Every inbound webhook is archived in webhook_events with its headers and verification result
+	webhookEventRepo := db.WebhookEventRepository()
+	app.Post("/api/webhooks/whatsapp", middleware.WebhookArchive(webhookEventRepo, core.WebhookSourceWhatsApp), httpHandler.ReceiveMessage)
 
 	// Payment webhook routes (Kopo Kopo)
-	app.Post("/api/webhooks/payment", httpHandler.HandlePaymentWebhook)
+	app.Post("/api/webhooks/payment", middleware.WebhookArchive(webhookEventRepo, core.WebhookSourcePayment), httpHandler.HandlePaymentWebhook)
 
 	// Dashboard API - Auth (public)
 	app.Post("/api/admin/auth/request-otp", dashboardHandler.RequestOTP)
+	app.Post("/api/admin/auth/resend-otp", dashboardHandler.ResendOTP)
 	app.Post("/api/admin/auth/verify-otp", dashboardHandler.VerifyOTP)
 	app.Post("/api/admin/auth/bartender-login", dashboardHandler.BartenderLogin)
+	app.Post("/api/admin/auth/verify-pin", dashboardHandler.BartenderLogin) // alias used by the PIN pad
+	app.Post("/api/admin/auth/refresh", dashboardHandler.RefreshSession)
 	app.Post("/api/admin/auth/logout", dashboardHandler.Logout)
 
+	// WebSocket event stream authenticates itself (token query param or first message),
+	// so it is registered ahead of the AuthMiddleware group. It also accepts the auth cookie,
+	// so cross-site handshakes from origins outside CORS_ALLOWED_ORIGINS are refused.
+	app.Get("/api/admin/ws", middleware.WebSocketOrigin(cfg.CORSAllowedOrigins), dashboardHandler.WebSocketEvents)
+
 	// Dashboard API - Protected routes
 	admin := app.Group("/api/admin", middleware.AuthMiddleware(dashboardService))
-	admin.Get("/auth/me", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetMe)
-
-	// Manager-only routes (inventory + analytics).
-	admin.Get("/products", middleware.RequireRoles("MANAGER"), dashboardHandler.GetProducts)
-	admin.Patch("/products/:id/stock", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateStock)
-	admin.Patch("/products/:id/price", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdatePrice)
-	admin.Get("/analytics/overview", middleware.RequireRoles("MANAGER"), dashboardHandler.GetAnalyticsOverview)
-	admin.Get("/analytics/revenue", middleware.RequireRoles("MANAGER"), dashboardHandler.GetRevenueTrend)
-	admin.Get("/analytics/top-products", middleware.RequireRoles("MANAGER"), dashboardHandler.GetTopProducts)
-	admin.Get("/analytics/reports/daily", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportDailySalesReportPDF)
-	admin.Get("/analytics/reports/last-30-days", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportLast30DaysSalesReportPDF)
-
-	// Shared order-management routes (manager + bartender).
-	admin.Get("/orders", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrders)
-	admin.Get("/orders/history", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderHistory)
-	admin.Post("/orders/:id/ready", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.MarkOrderReady)
-	admin.Post("/orders/:id/complete", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.MarkOrderComplete)
-	admin.Get("/events", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.SSEEvents)
+	// Retried POST/PATCH requests carrying the same Idempotency-Key get the first response back
+	admin.Use(middleware.Idempotency(redis.NewIdempotencyStore(redisClient), cfg.IdempotencyKeyTTL))
+	// Every mutation that gets past replay is recorded in audit_logs with the acting user
+	admin.Use(middleware.AuditLog(db.AuditLogRepository()))
+	registerAdminRoutes(admin, dashboardHandler, httpHandler)
+
+	// API docs (OpenAPI spec generated from the routes above, plus Swagger UI) for the dashboard team
+	docsHandler := http.NewDocsHandler(app, cfg.AppRelease)
+	docs := app.Group("/api/docs", middleware.AuthMiddleware(dashboardService), middleware.RequireRoles("MANAGER"))
+	docs.Get("/", docsHandler.SwaggerUI)
+	docs.Get("/openapi.json", docsHandler.OpenAPISpec)
 
 	// Start server
 	port := cfg.AppPort
@@ -194,10 +552,12 @@ func main() {
 	log.Printf("   WhatsApp Webhook: http://localhost:%s/api/webhooks/whatsapp", port)
 	log.Printf("   Payment Webhook:  http://localhost:%s/api/webhooks/payment", port)
 	log.Printf("   Dashboard API:    http://localhost:%s/api/admin/*", port)
-	log.Printf("   Health Check:     http://localhost:%s/health", port)
-	log.Printf("   CORS AllowOrigin: %s", allowedOrigin)
+	log.Printf("   Health Check:     http://localhost:%s/health/ready", port)
+	log.Printf("   API Docs:         http://localhost:%s/api/docs", port)
+	log.Printf("   CORS Origins:     %s", strings.Join(cfg.CORSAllowedOrigins, ", "))
 
 	if err := app.Listen(fmt.Sprintf(":%s", port)); err != nil {
+		reporting.Flush(5 * time.Second)
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
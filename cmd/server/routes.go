@@ -0,0 +1,139 @@
+package main
+
+import (
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/http"
+	"github.com/dumu-tech/destination-cocktails/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// registerAdminRoutes adds the dashboard API to admin, the /api/admin group behind AuthMiddleware.
+// Each route names the roles allowed to call it.
+func registerAdminRoutes(admin fiber.Router, dashboardHandler *http.DashboardHandler, httpHandler *http.Handler) {
+	admin.Get("/auth/me", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetMe)
+
+	// Manager-only routes (inventory + analytics).
+	admin.Get("/products", middleware.RequireRoles("MANAGER"), dashboardHandler.GetProducts)
+	admin.Get("/products/export", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportProducts)
+	admin.Post("/products/import", middleware.RequireRoles("MANAGER"), dashboardHandler.ImportProducts)
+	admin.Patch("/products/:id/stock", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateStock)
+	admin.Post("/products/:id/stock-adjustments", middleware.RequireRoles("MANAGER"), dashboardHandler.AdjustStock)
+	admin.Patch("/products/:id/price", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdatePrice)
+	admin.Get("/products/:id/price-history", middleware.RequireRoles("MANAGER"), dashboardHandler.GetPriceHistory)
+	admin.Patch("/products/:id/bottle-size", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateBottleSize)
+	admin.Patch("/products/:id/cost-price", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateCostPrice)
+	admin.Patch("/products/:id/archive", middleware.RequireRoles("MANAGER"), dashboardHandler.ArchiveProduct)
+	admin.Patch("/products/:id/unarchive", middleware.RequireRoles("MANAGER"), dashboardHandler.UnarchiveProduct)
+	admin.Patch("/products/:id/sold-out-visibility", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateSoldOutVisibility)
+	admin.Get("/products/:id/options", middleware.RequireRoles("MANAGER"), dashboardHandler.ListProductOptions)
+	admin.Post("/products/:id/options", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateProductOption)
+	admin.Patch("/products/:id/options/:optionId", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateProductOption)
+	admin.Delete("/products/:id/options/:optionId", middleware.RequireRoles("MANAGER"), dashboardHandler.DeleteProductOption)
+	admin.Get("/bundles", middleware.RequireRoles("MANAGER"), dashboardHandler.ListBundles)
+	admin.Post("/bundles", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateBundle)
+	admin.Put("/bundles/:id/components", middleware.RequireRoles("MANAGER"), dashboardHandler.SetBundleComponents)
+	admin.Get("/recipes", middleware.RequireRoles("MANAGER"), dashboardHandler.ListRecipes)
+	admin.Put("/recipes/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.SetRecipe)
+	admin.Get("/purchase-limits", middleware.RequireRoles("MANAGER"), dashboardHandler.ListPurchaseLimits)
+	admin.Put("/purchase-limits", middleware.RequireRoles("MANAGER"), dashboardHandler.SetPurchaseLimit)
+	admin.Delete("/purchase-limits/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeletePurchaseLimit)
+	admin.Post("/stocktakes", middleware.RequireRoles("MANAGER"), dashboardHandler.StartStocktake)
+	admin.Get("/stocktakes", middleware.RequireRoles("MANAGER"), dashboardHandler.ListStocktakes)
+	admin.Get("/stocktakes/:id", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetStocktake)
+	admin.Put("/stocktakes/:id/lines", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.RecordStocktakeCounts)
+	admin.Post("/stocktakes/:id/apply", middleware.RequireRoles("MANAGER"), dashboardHandler.ApplyStocktake)
+	admin.Post("/stocktakes/:id/cancel", middleware.RequireRoles("MANAGER"), dashboardHandler.CancelStocktake)
+	admin.Get("/stocktakes/:id/variance", middleware.RequireRoles("MANAGER"), dashboardHandler.GetStocktakeVariance)
+	admin.Get("/suppliers", middleware.RequireRoles("MANAGER"), dashboardHandler.ListSuppliers)
+	admin.Post("/suppliers", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateSupplier)
+	admin.Patch("/suppliers/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateSupplier)
+	admin.Delete("/suppliers/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeactivateSupplier)
+	admin.Get("/purchase-orders", middleware.RequireRoles("MANAGER"), dashboardHandler.ListPurchaseOrders)
+	admin.Post("/purchase-orders", middleware.RequireRoles("MANAGER"), dashboardHandler.CreatePurchaseOrder)
+	admin.Get("/purchase-orders/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.GetPurchaseOrder)
+	admin.Post("/purchase-orders/:id/receive", middleware.RequireRoles("MANAGER"), dashboardHandler.ReceivePurchaseOrder)
+	admin.Post("/purchase-orders/:id/cancel", middleware.RequireRoles("MANAGER"), dashboardHandler.CancelPurchaseOrder)
+	admin.Get("/analytics/overview", middleware.RequireRoles("MANAGER"), dashboardHandler.GetAnalyticsOverview)
+	admin.Get("/analytics/revenue", middleware.RequireRoles("MANAGER"), dashboardHandler.GetRevenueTrend)
+	admin.Get("/analytics/top-products", middleware.RequireRoles("MANAGER"), dashboardHandler.GetTopProducts)
+	admin.Get("/analytics/margins", middleware.RequireRoles("MANAGER"), dashboardHandler.GetMarginReport)
+	admin.Get("/analytics/prep-times", middleware.RequireRoles("MANAGER"), dashboardHandler.GetPrepTimeReport)
+	admin.Get("/analytics/staff", middleware.RequireRoles("MANAGER"), dashboardHandler.GetStaffReport)
+	admin.Get("/feedback", middleware.RequireRoles("MANAGER"), dashboardHandler.GetFeedbackReport)
+	admin.Get("/reports/daily", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportDailySalesReport)
+	admin.Get("/reports/last-30-days", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportLast30DaysSalesReport)
+	admin.Get("/reports/weekly", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportWeeklySalesReport)
+	admin.Get("/reports/monthly", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportMonthlySalesReport)
+	admin.Get("/reports/inventory-valuation", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportInventoryValuation)
+	admin.Get("/reports/accounting-export", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportAccounting)
+	admin.Post("/integrations/google-sheets/sync", middleware.RequireRoles("MANAGER"), dashboardHandler.SyncSalesSheet)
+	admin.Post("/reconciliation/import", middleware.RequireRoles("MANAGER"), dashboardHandler.ImportReconciliation)
+	admin.Get("/analytics/reports/daily", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportDailySalesReport)
+	admin.Get("/analytics/reports/last-30-days", middleware.RequireRoles("MANAGER"), dashboardHandler.ExportLast30DaysSalesReport)
+	admin.Get("/staff", middleware.RequireRoles("MANAGER"), dashboardHandler.ListBarStaff)
+	admin.Post("/staff", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateBarStaff)
+	admin.Patch("/staff/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateBarStaff)
+	admin.Delete("/staff/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeleteBarStaff)
+	admin.Get("/shifts", middleware.RequireRoles("MANAGER"), dashboardHandler.ListShifts)
+	admin.Post("/shifts/clock-in", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.ClockIn)
+	admin.Post("/shifts/clock-out", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.ClockOut)
+	admin.Get("/shifts/:id/report", middleware.RequireRoles("MANAGER"), dashboardHandler.GetShiftReport)
+	admin.Get("/riders", middleware.RequireRoles("MANAGER"), dashboardHandler.ListRiders)
+	admin.Post("/riders", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateRider)
+	admin.Patch("/riders/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateRider)
+	admin.Delete("/riders/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeleteRider)
+	admin.Get("/users", middleware.RequireRoles("MANAGER"), dashboardHandler.ListAdminUsers)
+	admin.Post("/users", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateAdminUser)
+	admin.Patch("/users/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateAdminUser)
+	admin.Delete("/users/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeleteAdminUser)
+	admin.Put("/users/:id/pin", middleware.RequireRoles("MANAGER"), dashboardHandler.SetAdminUserPIN)
+	admin.Get("/audit-logs", middleware.RequireRoles("MANAGER"), dashboardHandler.ListAuditLogs)
+	admin.Get("/customers/blocked", middleware.RequireRoles("MANAGER"), dashboardHandler.ListBlockedCustomers)
+	admin.Post("/customers/blocked", middleware.RequireRoles("MANAGER"), dashboardHandler.BlockCustomer)
+	admin.Delete("/customers/blocked/:phone", middleware.RequireRoles("MANAGER"), dashboardHandler.UnblockCustomer)
+	admin.Get("/sessions/:phone", middleware.RequireRoles("MANAGER"), dashboardHandler.GetCustomerSession)
+	admin.Delete("/sessions/:phone", middleware.RequireRoles("MANAGER"), dashboardHandler.ResetCustomerSession)
+	admin.Get("/broadcasts", middleware.RequireRoles("MANAGER"), dashboardHandler.ListBroadcasts)
+	admin.Get("/broadcasts/audience", middleware.RequireRoles("MANAGER"), dashboardHandler.PreviewBroadcastAudience)
+	admin.Post("/broadcasts", middleware.RequireRoles("MANAGER"), dashboardHandler.CreateBroadcast)
+	admin.Get("/broadcasts/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.GetBroadcast)
+	admin.Post("/broadcasts/:id/cancel", middleware.RequireRoles("MANAGER"), dashboardHandler.CancelBroadcast)
+	admin.Get("/settings", middleware.RequireRoles("MANAGER"), dashboardHandler.ListSettings)
+	admin.Patch("/settings", middleware.RequireRoles("MANAGER"), dashboardHandler.UpdateSettings)
+	admin.Post("/settings/ordering", middleware.RequireRoles("MANAGER"), dashboardHandler.SetOrderingStatus)
+	admin.Get("/whatsapp/dead-letters", middleware.RequireRoles("MANAGER"), dashboardHandler.ListWhatsAppDeadLetters)
+	admin.Get("/whatsapp/webhook-stats", middleware.RequireRoles("MANAGER"), httpHandler.GetWebhookStats)
+	admin.Get("/whatsapp/numbers", middleware.RequireRoles("MANAGER"), dashboardHandler.ListWhatsAppNumbers)
+	admin.Put("/whatsapp/numbers/:phone_number_id", middleware.RequireRoles("MANAGER"), dashboardHandler.SaveWhatsAppNumber)
+	admin.Delete("/whatsapp/numbers/:phone_number_id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeleteWhatsAppNumber)
+	admin.Get("/webhooks", middleware.RequireRoles("MANAGER"), dashboardHandler.ListWebhookEvents)
+	admin.Post("/webhooks/:id/replay", middleware.RequireRoles("MANAGER"), httpHandler.ReplayWebhookEvent)
+	admin.Get("/payments", middleware.RequireRoles("MANAGER"), dashboardHandler.ListPayments)
+	admin.Get("/payments/orphans", middleware.RequireRoles("MANAGER"), dashboardHandler.ListOrphanPayments)
+	admin.Get("/payments/webhook-subscriptions", middleware.RequireRoles("MANAGER"), dashboardHandler.ListPaymentWebhookSubscriptions)
+	admin.Post("/payments/webhook-subscriptions", middleware.RequireRoles("MANAGER"), dashboardHandler.CreatePaymentWebhookSubscription)
+	admin.Delete("/payments/webhook-subscriptions/:id", middleware.RequireRoles("MANAGER"), dashboardHandler.DeletePaymentWebhookSubscription)
+	admin.Get("/payments/stk-dead-letters", middleware.RequireRoles("MANAGER"), dashboardHandler.ListSTKPushDeadLetters)
+	admin.Post("/payments/:id/attach-order", middleware.RequireRoles("MANAGER"), dashboardHandler.AttachPaymentToOrder)
+
+	// Shared order-management routes (manager + bartender).
+	admin.Get("/settings/ordering", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderingStatus)
+	admin.Get("/tabs", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.ListOpenTabs)
+	admin.Post("/tabs/:id/close", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.CloseTab)
+	admin.Get("/reservations", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.ListReservations)
+	admin.Get("/reservations/calendar", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetReservationCalendar)
+	admin.Patch("/reservations/:id/status", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.UpdateReservationStatus)
+	admin.Get("/orders", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrders)
+	admin.Get("/orders/history", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderHistory)
+	admin.Get("/orders/board", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderBoard)
+	admin.Get("/orders/:id", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderDetail)
+	admin.Get("/orders/:id/history", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderStatusHistory)
+	admin.Get("/orders/:id/payment-attempts", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderPaymentAttempts)
+	admin.Post("/orders/:id/confirm-payment", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.ConfirmBarPayment)
+	admin.Post("/orders/:id/ready", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.MarkOrderReady)
+	admin.Post("/orders/:id/complete", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.MarkOrderComplete)
+	admin.Post("/orders/:id/dispatch", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.DispatchOrder)
+	admin.Post("/orders/:id/delivered", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.MarkOrderDelivered)
+	admin.Post("/orders/:id/refund", middleware.RequireRoles("MANAGER"), dashboardHandler.RefundOrder)
+	admin.Get("/orders/:id/receipt", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.GetOrderReceipt)
+	admin.Get("/events", middleware.RequireRoles("MANAGER", "BARTENDER"), dashboardHandler.SSEEvents)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/http"
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/middleware"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/dumu-tech/destination-cocktails/internal/testkit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "route-test-secret"
+
+// adminRoutes is the /api/admin group wired to in-memory repositories, with one user per role
+type adminRoutes struct {
+	app       *fiber.App
+	orders    *testkit.OrderRepository
+	manager   *core.AdminUser
+	bartender *core.AdminUser
+}
+
+func newAdminRoutes(t *testing.T) *adminRoutes {
+	t.Helper()
+
+	clock := core.SystemClock{}
+	ids := core.UUIDGenerator{}
+	manager := &core.AdminUser{PhoneNumber: "254700000001", Name: "Manager", Role: core.AdminRoleManager, IsActive: true}
+	bartender := &core.AdminUser{PhoneNumber: "254700000002", Name: "Bartender", Role: core.AdminRoleBartender, IsActive: true}
+	admins := testkit.NewAdminUserRepository(clock, ids, manager, bartender)
+	orders := testkit.NewOrderRepository(clock, ids)
+	products := testkit.NewProductRepository(clock, ids, testkit.SampleMenu()...)
+
+	dashboardService := service.NewDashboardService(admins, nil, products, orders, nil, testkit.NewWhatsAppGateway(), events.NewEventBus(), testJWTSecret)
+	dashboardService.SetClock(clock)
+
+	app := fiber.New()
+	admin := app.Group("/api/admin", middleware.AuthMiddleware(dashboardService))
+	// The webhook routes are never called here, so the WhatsApp handler isn't needed
+	registerAdminRoutes(admin, http.NewDashboardHandler(dashboardService), nil)
+
+	return &adminRoutes{app: app, orders: orders, manager: manager, bartender: bartender}
+}
+
+// token signs an access token for user; an empty role leaves the role claim out
+func (r *adminRoutes) token(t *testing.T, user *core.AdminUser, role string) string {
+	t.Helper()
+
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"phone":   user.PhoneNumber,
+		"name":    user.Name,
+		"ver":     user.TokenVersion,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+	if role != "" {
+		claims["role"] = role
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (r *adminRoutes) do(t *testing.T, method string, path string, token string, body string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := r.app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestManagerOnlyRoutesRejectBartenders(t *testing.T) {
+	routes := newAdminRoutes(t)
+	token := routes.token(t, routes.bartender, core.AdminRoleBartender)
+
+	for _, route := range []struct{ method, path, body string }{
+		{method: nethttp.MethodGet, path: "/api/admin/products"},
+		{method: nethttp.MethodPatch, path: "/api/admin/products/00000000-0000-0000-0000-000000000001/stock", body: `{"stock":10}`},
+		{method: nethttp.MethodPatch, path: "/api/admin/products/00000000-0000-0000-0000-000000000001/price", body: `{"price":1}`},
+		{method: nethttp.MethodGet, path: "/api/admin/analytics/overview"},
+		{method: nethttp.MethodGet, path: "/api/admin/analytics/revenue"},
+		{method: nethttp.MethodGet, path: "/api/admin/analytics/top-products"},
+		{method: nethttp.MethodGet, path: "/api/admin/reports/last-30-days"},
+		{method: nethttp.MethodGet, path: "/api/admin/analytics/reports/daily"},
+	} {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			status, body := routes.do(t, route.method, route.path, token, route.body)
+			if status != fiber.StatusForbidden || !strings.Contains(body, "insufficient permissions") {
+				t.Errorf("bartender got %d %s, want 403 insufficient permissions", status, body)
+			}
+		})
+	}
+}
+
+func TestTokenWithoutRoleIsForbidden(t *testing.T) {
+	routes := newAdminRoutes(t)
+
+	for _, user := range []*core.AdminUser{routes.manager, routes.bartender} {
+		token := routes.token(t, user, "")
+		for _, path := range []string{"/api/admin/orders", "/api/admin/products"} {
+			status, body := routes.do(t, nethttp.MethodGet, path, token, "")
+			if status != fiber.StatusForbidden || !strings.Contains(body, "role not found") {
+				t.Errorf("%s without a role claim got %d %s on %s, want 403 role not found", user.Name, status, body, path)
+			}
+		}
+	}
+}
+
+func TestOrderWorkflowRoutesAllowBothRoles(t *testing.T) {
+	ctx := context.Background()
+
+	for _, role := range []string{core.AdminRoleManager, core.AdminRoleBartender} {
+		t.Run(role, func(t *testing.T) {
+			routes := newAdminRoutes(t)
+			user := routes.manager
+			if role == core.AdminRoleBartender {
+				user = routes.bartender
+			}
+			token := routes.token(t, user, role)
+
+			order := &core.Order{CustomerPhone: "254711000000", PickupCode: "4821", Status: core.OrderStatusPaid, PaymentMethod: string(core.PaymentMethodMpesa), TotalAmount: 600, AmountPaid: 600}
+			if err := routes.orders.CreateOrder(ctx, order); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, route := range []struct{ method, path string }{
+				{method: nethttp.MethodGet, path: "/api/admin/orders"},
+				{method: nethttp.MethodGet, path: "/api/admin/orders/" + order.ID},
+				{method: nethttp.MethodGet, path: "/api/admin/orders/" + order.ID + "/history"},
+				{method: nethttp.MethodPost, path: "/api/admin/orders/" + order.ID + "/ready"},
+				{method: nethttp.MethodPost, path: "/api/admin/orders/" + order.ID + "/complete"},
+			} {
+				status, body := routes.do(t, route.method, route.path, token, "")
+				if status != fiber.StatusOK {
+					t.Fatalf("%s %s got %d %s, want 200", route.method, route.path, status, body)
+				}
+			}
+
+			stored, err := routes.orders.GetByID(ctx, order.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != core.OrderStatusCompleted {
+				t.Errorf("order is %s after ready and complete, want COMPLETED", stored.Status)
+			}
+		})
+	}
+}
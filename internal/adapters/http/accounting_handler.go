@@ -0,0 +1,70 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportAccounting exports settled orders and refunds as a QuickBooks/Xero-compatible journal CSV
+// GET /api/admin/reports/accounting-export?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *DashboardHandler) ExportAccounting(c *fiber.Ctx) error {
+	data, filename, err := h.dashboardService.GenerateAccountingExport(c.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(strings.ToLower(err.Error()), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "text/csv; charset=utf-8")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(data)
+}
+
+// refundOrderRequest is the body of POST /api/admin/orders/:id/refund
+type refundOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RefundOrder records that a paid order's money was returned to the customer and cancels it
+// POST /api/admin/orders/:id/refund
+func (h *DashboardHandler) RefundOrder(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	var req refundOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	order, err := h.dashboardService.RefundOrder(c.Context(), orderID, actorUserID, req.Reason)
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		status := fiber.StatusInternalServerError
+		switch {
+		case strings.Contains(msg, "not found"):
+			status = fiber.StatusNotFound
+		case strings.Contains(msg, "only paid orders"):
+			status = fiber.StatusConflict
+		case strings.Contains(msg, "is required"):
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(order)
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListAdminUsers returns all dashboard users (managers and bartenders)
+// GET /api/admin/users
+func (h *DashboardHandler) ListAdminUsers(c *fiber.Ctx) error {
+	users, err := h.dashboardService.ListAdminUsers(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get users",
+		})
+	}
+
+	return c.JSON(users)
+}
+
+// createAdminUserRequest is the body of POST /api/admin/users
+type createAdminUserRequest struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Role        string `json:"role"`
+	PIN         string `json:"pin"`
+}
+
+// CreateAdminUser adds a manager or bartender account (role defaults to BARTENDER)
+// POST /api/admin/users
+func (h *DashboardHandler) CreateAdminUser(c *fiber.Ctx) error {
+	var req createAdminUserRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	user, err := h.dashboardService.CreateAdminUser(c.Context(), service.AdminUserInput{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Role:        req.Role,
+		PIN:         req.PIN,
+	})
+	if err != nil {
+		return c.Status(adminUserErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+// updateAdminUserRequest is the body of PATCH /api/admin/users/:id; omitted fields are left unchanged
+type updateAdminUserRequest struct {
+	Name        *string `json:"name"`
+	PhoneNumber *string `json:"phone_number"`
+	Role        *string `json:"role"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// UpdateAdminUser updates name, phone, role or active status for a dashboard user
+// PATCH /api/admin/users/:id
+func (h *DashboardHandler) UpdateAdminUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "user ID is required",
+		})
+	}
+
+	var req updateAdminUserRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	user, err := h.dashboardService.UpdateAdminUser(c.Context(), userID, actorUserID, service.AdminUserUpdate{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Role:        req.Role,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		return c.Status(adminUserErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(user)
+}
+
+// DeleteAdminUser deactivates a dashboard user (order audit history is preserved)
+// DELETE /api/admin/users/:id
+func (h *DashboardHandler) DeleteAdminUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "user ID is required",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	if err := h.dashboardService.DeactivateAdminUser(c.Context(), userID, actorUserID); err != nil {
+		return c.Status(adminUserErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "user deactivated",
+	})
+}
+
+// setAdminUserPINRequest is the body of PUT /api/admin/users/:id/pin
+type setAdminUserPINRequest struct {
+	PIN string `json:"pin"`
+}
+
+// SetAdminUserPIN sets or resets a user's 4-digit login PIN; an empty pin disables PIN login
+// PUT /api/admin/users/:id/pin
+func (h *DashboardHandler) SetAdminUserPIN(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "user ID is required",
+		})
+	}
+
+	var req setAdminUserPINRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := h.dashboardService.SetAdminUserPIN(c.Context(), userID, strings.TrimSpace(req.PIN)); err != nil {
+		return c.Status(adminUserErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	message := "PIN updated"
+	if strings.TrimSpace(req.PIN) == "" {
+		message = "PIN removed"
+	}
+	return c.JSON(fiber.Map{
+		"message": message,
+	})
+}
+
+func adminUserErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.HasPrefix(msg, "forbidden"):
+		return fiber.StatusForbidden
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"), strings.Contains(msg, "must be"):
+		return fiber.StatusBadRequest
+	case strings.Contains(msg, "already in use"), strings.Contains(msg, "duplicate key"):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
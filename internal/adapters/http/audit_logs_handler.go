@@ -0,0 +1,38 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListAuditLogs returns audited admin changes, newest first
+// GET /api/admin/audit-logs?actor=<user id>&entity_type=products&entity_id=<id>&from=2026-03-01&to=2026-03-07&limit=100
+func (h *DashboardHandler) ListAuditLogs(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	entries, err := h.dashboardService.ListAuditLogs(c.Context(), service.AuditLogQuery{
+		Actor:      c.Query("actor", ""),
+		EntityType: c.Query("entity_type", ""),
+		EntityID:   c.Query("entity_id", ""),
+		From:       c.Query("from", ""),
+		To:         c.Query("to", ""),
+		Limit:      limit,
+	})
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(strings.ToLower(err.Error()), "invalid date format") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(entries)
+}
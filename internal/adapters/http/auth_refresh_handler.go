@@ -0,0 +1,112 @@
+package http
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	authCookieName    = "auth_token"
+	refreshCookieName = "refresh_token"
+	// refreshCookiePath keeps the refresh token off every request except refresh and logout
+	refreshCookiePath = "/api/admin/auth"
+)
+
+// RefreshSession exchanges a refresh token (cookie or JSON body) for a new access/refresh pair.
+// The presented refresh token is single-use.
+// POST /api/admin/auth/refresh
+func (h *DashboardHandler) RefreshSession(c *fiber.Ctx) error {
+	refreshToken := readRefreshToken(c)
+	if refreshToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "unauthorized: refresh token required",
+		})
+	}
+
+	tokens, err := h.dashboardService.RefreshSession(c.Context(), refreshToken)
+	if err != nil {
+		if strings.Contains(err.Error(), "unauthorized") {
+			clearAuthCookies(c)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to refresh session",
+		})
+	}
+
+	return c.JSON(loginResponse(c, "session refreshed", tokens))
+}
+
+// refreshSessionRequest is the body fallback for POST /api/admin/auth/refresh when the cookie is missing
+type refreshSessionRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// readRefreshToken takes the refresh token from its cookie, falling back to {"refresh_token": "..."}
+func readRefreshToken(c *fiber.Ctx) string {
+	if token := strings.TrimSpace(c.Cookies(refreshCookieName)); token != "" {
+		return token
+	}
+
+	var req refreshSessionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(req.RefreshToken)
+}
+
+// loginResponse sets the auth cookies and builds the body shared by login and refresh
+func loginResponse(c *fiber.Ctx, message string, tokens *service.AuthTokens) fiber.Map {
+	// Use Secure=true and SameSite=None for cross-origin (dashboard on different subdomain)
+	c.Cookie(&fiber.Cookie{
+		Name:     authCookieName,
+		Value:    tokens.AccessToken,
+		Expires:  tokens.AccessExpiresAt,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     refreshCookieName,
+		Value:    tokens.RefreshToken,
+		Path:     refreshCookiePath,
+		Expires:  tokens.RefreshExpiresAt,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+	})
+
+	return fiber.Map{
+		"message":       message,
+		"token":         tokens.AccessToken,
+		"expires_in":    int(time.Until(tokens.AccessExpiresAt).Seconds()),
+		"refresh_token": tokens.RefreshToken,
+		"role":          tokens.Role,
+	}
+}
+
+// clearAuthCookies expires both auth cookies (settings must match the ones used to set them)
+func clearAuthCookies(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     authCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-1 * time.Hour),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     refreshCookieName,
+		Value:    "",
+		Path:     refreshCookiePath,
+		Expires:  time.Now().Add(-1 * time.Hour),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+	})
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListBarStaff returns the bar staff notification roster
+// GET /api/admin/staff
+func (h *DashboardHandler) ListBarStaff(c *fiber.Ctx) error {
+	staff, err := h.dashboardService.ListBarStaff(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get bar staff",
+		})
+	}
+
+	return c.JSON(staff)
+}
+
+// createBarStaffRequest is the body of POST /api/admin/staff
+type createBarStaffRequest struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	IsOnShift   bool   `json:"is_on_shift"`
+}
+
+// CreateBarStaff adds a bartender phone to the roster
+// POST /api/admin/staff
+func (h *DashboardHandler) CreateBarStaff(c *fiber.Ctx) error {
+	var req createBarStaffRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	staff, err := h.dashboardService.CreateBarStaff(c.Context(), req.Name, req.PhoneNumber, req.IsOnShift)
+	if err != nil {
+		return c.Status(barStaffErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(staff)
+}
+
+// updateBarStaffRequest is the body of PATCH /api/admin/staff/:id; omitted fields are left unchanged
+type updateBarStaffRequest struct {
+	Name        *string `json:"name"`
+	PhoneNumber *string `json:"phone_number"`
+	IsOnShift   *bool   `json:"is_on_shift"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// UpdateBarStaff updates name, phone, on-shift or active status for a bartender
+// PATCH /api/admin/staff/:id
+func (h *DashboardHandler) UpdateBarStaff(c *fiber.Ctx) error {
+	staffID := c.Params("id")
+	if staffID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "staff ID is required",
+		})
+	}
+
+	var req updateBarStaffRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	staff, err := h.dashboardService.UpdateBarStaff(c.Context(), staffID, service.BarStaffUpdate{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		IsOnShift:   req.IsOnShift,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		return c.Status(barStaffErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(staff)
+}
+
+// DeleteBarStaff deactivates a bartender (acceptance history is preserved)
+// DELETE /api/admin/staff/:id
+func (h *DashboardHandler) DeleteBarStaff(c *fiber.Ctx) error {
+	staffID := c.Params("id")
+	if staffID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "staff ID is required",
+		})
+	}
+
+	if err := h.dashboardService.DeactivateBarStaff(c.Context(), staffID); err != nil {
+		return c.Status(barStaffErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "bar staff deactivated",
+	})
+}
+
+func barStaffErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid phone"), strings.Contains(msg, "is required"):
+		return fiber.StatusBadRequest
+	case strings.Contains(msg, "duplicate key"):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// blockCustomerRequest is the body of POST /api/admin/customers/blocked
+type blockCustomerRequest struct {
+	Phone  string `json:"phone"`
+	Reason string `json:"reason"`
+}
+
+// ListBlockedCustomers returns blocked phones and phones flagged after repeated failed payments
+// GET /api/admin/customers/blocked
+func (h *DashboardHandler) ListBlockedCustomers(c *fiber.Ctx) error {
+	customers, err := h.dashboardService.ListBlockedCustomers(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list blocked customers",
+		})
+	}
+
+	return c.JSON(customers)
+}
+
+// BlockCustomer stops the bot serving a phone
+// POST /api/admin/customers/blocked {"phone": "0712345678", "reason": "..."}
+func (h *DashboardHandler) BlockCustomer(c *fiber.Ctx) error {
+	var req blockCustomerRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	customer, err := h.dashboardService.BlockCustomer(c.Context(), req.Phone, req.Reason, actorUserID)
+	if err != nil {
+		return c.Status(blocklistErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(customer)
+}
+
+// UnblockCustomer lets a blocked or flagged phone order again
+// DELETE /api/admin/customers/blocked/:phone
+func (h *DashboardHandler) UnblockCustomer(c *fiber.Ctx) error {
+	phone := c.Params("phone")
+	if phone == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "phone is required",
+		})
+	}
+
+	if err := h.dashboardService.UnblockCustomer(c.Context(), phone); err != nil {
+		return c.Status(blocklistErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "customer unblocked",
+	})
+}
+
+func blocklistErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "must be"), strings.Contains(msg, "is required"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
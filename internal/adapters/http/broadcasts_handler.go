@@ -0,0 +1,126 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// createBroadcastRequest is the body of POST /api/admin/broadcasts
+type createBroadcastRequest struct {
+	Name             string `json:"name"`
+	Message          string `json:"message"`
+	TemplateName     string `json:"template_name"`
+	TemplateLanguage string `json:"template_language"`
+	Segment          string `json:"segment"`
+	Days             int    `json:"days"`
+}
+
+// ListBroadcasts returns recent campaigns with delivery stats, newest first
+// GET /api/admin/broadcasts?limit=50
+func (h *DashboardHandler) ListBroadcasts(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	campaigns, err := h.dashboardService.ListBroadcasts(c.Context(), limit)
+	if err != nil {
+		return c.Status(broadcastErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(campaigns)
+}
+
+// PreviewBroadcastAudience counts the opted-in customers a segment reaches
+// GET /api/admin/broadcasts/audience?segment=recent&days=30
+func (h *DashboardHandler) PreviewBroadcastAudience(c *fiber.Ctx) error {
+	days, err := strconv.Atoi(c.Query("days", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid days",
+		})
+	}
+
+	audience, err := h.dashboardService.PreviewBroadcastAudience(c.Context(), c.Query("segment"), days)
+	if err != nil {
+		return c.Status(broadcastErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(audience)
+}
+
+// CreateBroadcast queues a campaign to opted-in customers in a segment
+// POST /api/admin/broadcasts
+func (h *DashboardHandler) CreateBroadcast(c *fiber.Ctx) error {
+	var req createBroadcastRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	campaign, err := h.dashboardService.CreateBroadcast(c.Context(), service.BroadcastInput{
+		Name:             req.Name,
+		Message:          req.Message,
+		TemplateName:     req.TemplateName,
+		TemplateLanguage: req.TemplateLanguage,
+		Segment:          req.Segment,
+		Days:             req.Days,
+	}, actorUserID)
+	if err != nil {
+		return c.Status(broadcastErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(campaign)
+}
+
+// GetBroadcast returns a campaign with its delivery stats
+// GET /api/admin/broadcasts/:id
+func (h *DashboardHandler) GetBroadcast(c *fiber.Ctx) error {
+	campaign, err := h.dashboardService.GetBroadcast(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(broadcastErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(campaign)
+}
+
+// CancelBroadcast stops a campaign that hasn't finished sending
+// POST /api/admin/broadcasts/:id/cancel
+func (h *DashboardHandler) CancelBroadcast(c *fiber.Ctx) error {
+	campaign, err := h.dashboardService.CancelBroadcast(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(broadcastErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(campaign)
+}
+
+func broadcastErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"):
+		return fiber.StatusBadRequest
+	case strings.Contains(msg, "already finished"):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// bundleComponentRequest is one component in a bundle request body
+type bundleComponentRequest struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+func toBundleComponentInputs(components []bundleComponentRequest) []service.BundleComponentInput {
+	inputs := make([]service.BundleComponentInput, len(components))
+	for i, component := range components {
+		inputs[i] = service.BundleComponentInput{
+			ProductID: component.ProductID,
+			Quantity:  component.Quantity,
+		}
+	}
+	return inputs
+}
+
+// ListBundles returns every combo with its components and how many can be made from current stock
+// GET /api/admin/bundles
+func (h *DashboardHandler) ListBundles(c *fiber.Ctx) error {
+	bundles, err := h.dashboardService.ListBundles(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get bundles",
+		})
+	}
+
+	return c.JSON(bundles)
+}
+
+// createBundleRequest is the body of POST /api/admin/bundles
+type createBundleRequest struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Price       float64                  `json:"price"`
+	ImageURL    string                   `json:"image_url"`
+	Components  []bundleComponentRequest `json:"components"`
+}
+
+// CreateBundle adds a combo product (listed under "Combos" in the bot) at its own price
+// POST /api/admin/bundles
+func (h *DashboardHandler) CreateBundle(c *fiber.Ctx) error {
+	var req createBundleRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	bundle, err := h.dashboardService.CreateBundle(c.Context(), service.BundleInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		ImageURL:    req.ImageURL,
+		Components:  toBundleComponentInputs(req.Components),
+	})
+	if err != nil {
+		return c.Status(bundleErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(bundle)
+}
+
+// setBundleComponentsRequest is the body of PUT /api/admin/bundles/:id/components
+type setBundleComponentsRequest struct {
+	Components []bundleComponentRequest `json:"components"`
+}
+
+// SetBundleComponents replaces the component products in a combo
+// PUT /api/admin/bundles/:id/components
+func (h *DashboardHandler) SetBundleComponents(c *fiber.Ctx) error {
+	bundleID := c.Params("id")
+	if bundleID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "bundle ID is required",
+		})
+	}
+
+	var req setBundleComponentsRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	bundle, err := h.dashboardService.SetBundleComponents(c.Context(), bundleID, toBundleComponentInputs(req.Components))
+	if err != nil {
+		return c.Status(bundleErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(bundle)
+}
+
+func bundleErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"), strings.Contains(msg, "must be"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -27,12 +28,15 @@ func NewDashboardHandler(dashboardService *service.DashboardService) *DashboardH
 	}
 }
 
+// requestOTPRequest is the body of POST /api/admin/auth/request-otp
+type requestOTPRequest struct {
+	Phone string `json:"phone"`
+}
+
 // RequestOTP handles OTP request
 // POST /api/admin/auth/request-otp
 func (h *DashboardHandler) RequestOTP(c *fiber.Ctx) error {
-	var req struct {
-		Phone string `json:"phone"`
-	}
+	var req requestOTPRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -47,6 +51,11 @@ func (h *DashboardHandler) RequestOTP(c *fiber.Ctx) error {
 	}
 
 	if err := h.dashboardService.RequestOTP(c.Context(), req.Phone); err != nil {
+		if strings.Contains(err.Error(), "too many") {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -57,13 +66,58 @@ func (h *DashboardHandler) RequestOTP(c *fiber.Ctx) error {
 	})
 }
 
+// ResendOTP sends a new login code once OTP_RESEND_COOLDOWN has passed since the last one
+// POST /api/admin/auth/resend-otp
+func (h *DashboardHandler) ResendOTP(c *fiber.Ctx) error {
+	var req requestOTPRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if req.Phone == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "phone number is required",
+		})
+	}
+
+	wait, err := h.dashboardService.ResendOTP(c.Context(), req.Phone)
+	if err != nil {
+		if wait > 0 {
+			retryAfter := int((wait + time.Second - 1) / time.Second)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":       err.Error(),
+				"retry_after": retryAfter,
+			})
+		}
+		if strings.Contains(err.Error(), "too many") {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "OTP sent successfully",
+	})
+}
+
+// verifyOTPRequest is the body of POST /api/admin/auth/verify-otp
+type verifyOTPRequest struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
 // VerifyOTP handles OTP verification
 // POST /api/admin/auth/verify-otp
 func (h *DashboardHandler) VerifyOTP(c *fiber.Ctx) error {
-	var req struct {
-		Phone string `json:"phone"`
-		Code  string `json:"code"`
-	}
+	var req verifyOTPRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -77,37 +131,32 @@ func (h *DashboardHandler) VerifyOTP(c *fiber.Ctx) error {
 		})
 	}
 
-	token, err := h.dashboardService.VerifyOTP(c.Context(), req.Phone, req.Code)
+	tokens, err := h.dashboardService.VerifyOTP(c.Context(), req.Phone, req.Code)
 	if err != nil {
+		if strings.Contains(err.Error(), "too many") {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	// Set JWT token in HTTP-only cookie
-	// Use Secure=true and SameSite=None for cross-origin (dashboard on different subdomain)
-	c.Cookie(&fiber.Cookie{
-		Name:     "auth_token",
-		Value:    token,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   true,   // Required for SameSite=None (HTTPS)
-		SameSite: "None", // Allow cross-origin cookie for dashboard
-	})
+	// Access and refresh tokens go in HTTP-only cookies and the body
+	return c.JSON(loginResponse(c, "login successful", tokens))
+}
 
-	return c.JSON(fiber.Map{
-		"message": "login successful",
-		"token":   token,
-		"role":    core.AdminRoleManager,
-	})
+// bartenderLoginRequest is the body of POST /api/admin/auth/bartender-login
+type bartenderLoginRequest struct {
+	PIN string `json:"pin"`
 }
 
 // BartenderLogin handles bartender PIN login.
 // POST /api/admin/auth/bartender-login
+// POST /api/admin/auth/verify-pin
 func (h *DashboardHandler) BartenderLogin(c *fiber.Ctx) error {
-	var req struct {
-		PIN string `json:"pin"`
-	}
+	var req bartenderLoginRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -121,7 +170,7 @@ func (h *DashboardHandler) BartenderLogin(c *fiber.Ctx) error {
 		})
 	}
 
-	token, err := h.dashboardService.VerifyBartenderPIN(c.Context(), req.PIN)
+	tokens, err := h.dashboardService.VerifyBartenderPIN(c.Context(), req.PIN)
 	if err != nil {
 		msg := err.Error()
 		if strings.Contains(msg, "PIN must be exactly 4 digits") {
@@ -135,35 +184,19 @@ func (h *DashboardHandler) BartenderLogin(c *fiber.Ctx) error {
 		})
 	}
 
-	// Set JWT token in HTTP-only cookie
-	c.Cookie(&fiber.Cookie{
-		Name:     "auth_token",
-		Value:    token,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "None",
-	})
-
-	return c.JSON(fiber.Map{
-		"message": "login successful",
-		"token":   token,
-		"role":    core.AdminRoleBartender,
-	})
+	return c.JSON(loginResponse(c, "login successful", tokens))
 }
 
-// Logout handles user logout
+// Logout revokes the session's refresh token and clears the auth cookies
 // POST /api/admin/auth/logout
 func (h *DashboardHandler) Logout(c *fiber.Ctx) error {
-	// Clear auth cookie (match settings from verify-otp for cross-origin)
-	c.Cookie(&fiber.Cookie{
-		Name:     "auth_token",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "None",
-	})
+	if err := h.dashboardService.RevokeRefreshToken(c.Context(), readRefreshToken(c)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to log out",
+		})
+	}
+
+	clearAuthCookies(c)
 
 	return c.JSON(fiber.Map{
 		"message": "logged out successfully",
@@ -185,10 +218,15 @@ func (h *DashboardHandler) GetMe(c *fiber.Ctx) error {
 	return c.JSON(adminUser) // Returns full AdminUser struct
 }
 
-// GetProducts retrieves all products
-// GET /api/admin/products
+// GetProducts retrieves all active products, or archived ones with ?archived=true
+// GET /api/admin/products?archived=true
 func (h *DashboardHandler) GetProducts(c *fiber.Ctx) error {
-	products, err := h.dashboardService.GetProducts(c.Context())
+	getProducts := h.dashboardService.GetProducts
+	if c.QueryBool("archived") {
+		getProducts = h.dashboardService.GetArchivedProducts
+	}
+
+	products, err := getProducts(c.Context())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to get products",
@@ -198,7 +236,12 @@ func (h *DashboardHandler) GetProducts(c *fiber.Ctx) error {
 	return c.JSON(products)
 }
 
-// UpdateStock updates product stock
+// updateStockRequest is the body of PATCH /api/admin/products/:id/stock
+type updateStockRequest struct {
+	StockQuantity int `json:"stock_quantity"`
+}
+
+// UpdateStock sets product stock to a counted number, recorded as a correction
 // PATCH /api/admin/products/:id/stock
 func (h *DashboardHandler) UpdateStock(c *fiber.Ctx) error {
 	productID := c.Params("id")
@@ -208,9 +251,7 @@ func (h *DashboardHandler) UpdateStock(c *fiber.Ctx) error {
 		})
 	}
 
-	var req struct {
-		StockQuantity int `json:"stock_quantity"`
-	}
+	var req updateStockRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -218,7 +259,8 @@ func (h *DashboardHandler) UpdateStock(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.dashboardService.UpdateStock(c.Context(), productID, req.StockQuantity); err != nil {
+	actorUserID, _ := c.Locals("user_id").(string)
+	if err := h.dashboardService.UpdateStock(c.Context(), productID, req.StockQuantity, actorUserID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -229,6 +271,11 @@ func (h *DashboardHandler) UpdateStock(c *fiber.Ctx) error {
 	})
 }
 
+// updatePriceRequest is the body of PATCH /api/admin/products/:id/price
+type updatePriceRequest struct {
+	Price float64 `json:"price"`
+}
+
 // UpdatePrice updates product price
 // PATCH /api/admin/products/:id/price
 func (h *DashboardHandler) UpdatePrice(c *fiber.Ctx) error {
@@ -239,9 +286,7 @@ func (h *DashboardHandler) UpdatePrice(c *fiber.Ctx) error {
 		})
 	}
 
-	var req struct {
-		Price float64 `json:"price"`
-	}
+	var req updatePriceRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -255,7 +300,8 @@ func (h *DashboardHandler) UpdatePrice(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.dashboardService.UpdatePrice(c.Context(), productID, req.Price); err != nil {
+	actorUserID, _ := c.Locals("user_id").(string)
+	if err := h.dashboardService.UpdatePrice(c.Context(), productID, req.Price, actorUserID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -266,19 +312,53 @@ func (h *DashboardHandler) UpdatePrice(c *fiber.Ctx) error {
 	})
 }
 
+// GetPriceHistory returns a product's price changes with who made them, newest first
+// GET /api/admin/products/:id/price-history?limit=50
+func (h *DashboardHandler) GetPriceHistory(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	history, err := h.dashboardService.GetPriceHistory(c.Context(), c.Params("id"), limit)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(history)
+}
+
 // GetOrders retrieves orders with optional filters
-// GET /api/admin/orders?status=PAID&limit=50
+// GET /api/admin/orders?status=PAID&pickup_code=0031&phone=0712&payment_method=MPESA&min_amount=500&max_amount=2000&from=2026-03-01&to=2026-03-07&limit=50
 func (h *DashboardHandler) GetOrders(c *fiber.Ctx) error {
-	status := c.Query("status", "")
-	limitStr := c.Query("limit", "100")
-
-	limit, err := strconv.Atoi(limitStr)
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
 	if err != nil {
 		limit = 100
 	}
 
-	orders, err := h.dashboardService.GetOrders(c.Context(), status, limit)
+	orders, err := h.dashboardService.GetOrders(c.Context(), service.OrderQuery{
+		Status:        c.Query("status", ""),
+		PickupCode:    c.Query("pickup_code", ""),
+		Phone:         c.Query("phone", ""),
+		PaymentMethod: c.Query("payment_method", ""),
+		MinAmount:     c.Query("min_amount", ""),
+		MaxAmount:     c.Query("max_amount", ""),
+		From:          c.Query("from", ""),
+		To:            c.Query("to", ""),
+		Limit:         limit,
+	})
 	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "invalid") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to get orders",
 		})
@@ -288,6 +368,9 @@ func (h *DashboardHandler) GetOrders(c *fiber.Ctx) error {
 }
 
 // GetOrderHistory retrieves completed orders for bartender/manager dispute checks.
+// pickup_code matches partially (ILIKE). phone is compared on digits only, so 0712345678,
+// 254712345678 and +254 712 345 678 find the same orders once 9+ digits are given; shorter
+// input matches any number containing those digits. The X-Phone-Match header reports which rule applied.
 // GET /api/admin/orders/history?pickup_code=0031&phone=2547&limit=50
 func (h *DashboardHandler) GetOrderHistory(c *fiber.Ctx) error {
 	pickupCode := strings.TrimSpace(c.Query("pickup_code", ""))
@@ -306,9 +389,113 @@ func (h *DashboardHandler) GetOrderHistory(c *fiber.Ctx) error {
 		})
 	}
 
+	if phone != "" {
+		c.Set("X-Phone-Match", phoneMatchRule(phone))
+	}
 	return c.JSON(orders)
 }
 
+// phoneMatchRule names how GetCompletedHistory matches a phone search, mirroring the repository:
+// "last-9-digits" for a full number in any format, "digits-contains" for partial digits and
+// "text-contains" when the input has no digits at all
+func phoneMatchRule(phone string) string {
+	digits := 0
+	for _, char := range phone {
+		if char >= '0' && char <= '9' {
+			digits++
+		}
+	}
+	switch {
+	case digits >= 9:
+		return "last-9-digits"
+	case digits > 0:
+		return "digits-contains"
+	default:
+		return "text-contains"
+	}
+}
+
+// GetOrderDetail returns one order with items, modifiers, status timeline and ready/completed actor names
+// GET /api/admin/orders/:id
+func (h *DashboardHandler) GetOrderDetail(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	detail, err := h.dashboardService.GetOrderDetail(c.Context(), orderID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "order not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get order",
+		})
+	}
+
+	return c.JSON(detail)
+}
+
+// GetOrderStatusHistory returns the status timeline (who changed what, and when) for one order
+// GET /api/admin/orders/:id/history
+func (h *DashboardHandler) GetOrderStatusHistory(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	history, err := h.dashboardService.GetOrderStatusHistory(c.Context(), orderID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "order not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get order status history",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"order_id": orderID,
+		"history":  history,
+	})
+}
+
+// GetOrderPaymentAttempts returns the STK pushes sent for an order: when, Kopo Kopo's response and the callback outcome
+// GET /api/admin/orders/:id/payment-attempts
+func (h *DashboardHandler) GetOrderPaymentAttempts(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	attempts, err := h.dashboardService.GetOrderPaymentAttempts(c.Context(), orderID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "order not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get payment attempts",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"order_id": orderID,
+		"attempts": attempts,
+	})
+}
+
 // MarkOrderReady updates an order status from PAID to READY and notifies the customer.
 // POST /api/admin/orders/:id/ready
 func (h *DashboardHandler) MarkOrderReady(c *fiber.Ctx) error {
@@ -325,7 +512,7 @@ func (h *DashboardHandler) MarkOrderReady(c *fiber.Ctx) error {
 		switch {
 		case strings.Contains(strings.ToLower(msg), "not found"):
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
-		case strings.Contains(msg, "only PAID orders can be marked READY"):
+		case strings.Contains(msg, "only PAID orders can be marked READY"), strings.Contains(msg, "delivery orders are dispatched"):
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": msg})
@@ -365,11 +552,115 @@ func (h *DashboardHandler) MarkOrderComplete(c *fiber.Ctx) error {
 	})
 }
 
+// DispatchOrder hands a PAID delivery order to the rider and tells the customer it's on its way.
+// POST /api/admin/orders/:id/dispatch
+func (h *DashboardHandler) DispatchOrder(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	if err := h.dashboardService.DispatchOrder(c.Context(), orderID, actorUserID); err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(strings.ToLower(msg), "not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+		case strings.Contains(msg, "only delivery orders can be dispatched"), strings.Contains(msg, "only PAID orders can be dispatched"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": msg})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "order marked as OUT_FOR_DELIVERY",
+	})
+}
+
+// MarkOrderDelivered updates a delivery order status from OUT_FOR_DELIVERY to DELIVERED.
+// POST /api/admin/orders/:id/delivered
+func (h *DashboardHandler) MarkOrderDelivered(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	if err := h.dashboardService.MarkOrderDelivered(c.Context(), orderID, actorUserID); err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(strings.ToLower(msg), "not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+		case strings.Contains(msg, "only OUT_FOR_DELIVERY orders can be marked DELIVERED"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": msg})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "order marked as DELIVERED",
+	})
+}
+
+// confirmBarPaymentRequest is the optional body of POST /api/admin/orders/:id/confirm-payment
+type confirmBarPaymentRequest struct {
+	Method string `json:"method"`
+}
+
+// ConfirmBarPayment marks a pay-at-the-bar order PAID once cash or card has been taken.
+// POST /api/admin/orders/:id/confirm-payment {"method": "CASH"|"CARD"}
+func (h *DashboardHandler) ConfirmBarPayment(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	var req confirmBarPaymentRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid request body",
+			})
+		}
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	order, err := h.dashboardService.ConfirmBarPayment(c.Context(), orderID, req.Method, actorUserID)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(strings.ToLower(msg), "not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+		case errors.Is(err, core.ErrOrderNotAwaitingCash):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+		case strings.Contains(msg, "invalid payment method"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": msg})
+		}
+	}
+
+	return c.JSON(order)
+}
+
 // GetAnalyticsOverview retrieves dashboard overview metrics
-// GET /api/admin/analytics/overview
+// GET /api/admin/analytics/overview?from=YYYY-MM-DD&to=YYYY-MM-DD
 func (h *DashboardHandler) GetAnalyticsOverview(c *fiber.Ctx) error {
-	analytics, err := h.dashboardService.GetAnalyticsOverview(c.Context())
+	analytics, err := h.dashboardService.GetAnalyticsOverview(c.Context(), c.Query("from", ""), c.Query("to", ""))
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to get analytics",
 		})
@@ -379,7 +670,7 @@ func (h *DashboardHandler) GetAnalyticsOverview(c *fiber.Ctx) error {
 }
 
 // GetRevenueTrend retrieves revenue trend data
-// GET /api/admin/analytics/revenue?days=30
+// GET /api/admin/analytics/revenue?days=30 or ?from=YYYY-MM-DD&to=YYYY-MM-DD
 func (h *DashboardHandler) GetRevenueTrend(c *fiber.Ctx) error {
 	daysStr := c.Query("days", "30")
 	days, err := strconv.Atoi(daysStr)
@@ -387,8 +678,13 @@ func (h *DashboardHandler) GetRevenueTrend(c *fiber.Ctx) error {
 		days = 30
 	}
 
-	trends, err := h.dashboardService.GetRevenueTrend(c.Context(), days)
+	trends, err := h.dashboardService.GetRevenueTrend(c.Context(), days, c.Query("from", ""), c.Query("to", ""))
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to get revenue trend",
 		})
@@ -398,7 +694,7 @@ func (h *DashboardHandler) GetRevenueTrend(c *fiber.Ctx) error {
 }
 
 // GetTopProducts retrieves top-selling products
-// GET /api/admin/analytics/top-products?limit=10
+// GET /api/admin/analytics/top-products?limit=10&from=YYYY-MM-DD&to=YYYY-MM-DD
 func (h *DashboardHandler) GetTopProducts(c *fiber.Ctx) error {
 	limitStr := c.Query("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
@@ -406,8 +702,13 @@ func (h *DashboardHandler) GetTopProducts(c *fiber.Ctx) error {
 		limit = 10
 	}
 
-	products, err := h.dashboardService.GetTopProducts(c.Context(), limit)
+	products, err := h.dashboardService.GetTopProducts(c.Context(), limit, c.Query("from", ""), c.Query("to", ""))
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to get top products",
 		})
@@ -416,15 +717,17 @@ func (h *DashboardHandler) GetTopProducts(c *fiber.Ctx) error {
 	return c.JSON(products)
 }
 
-// ExportDailySalesReportPDF exports a single operational business-day sales report as PDF.
-// GET /api/admin/analytics/reports/daily?date=YYYY-MM-DD
-func (h *DashboardHandler) ExportDailySalesReportPDF(c *fiber.Ctx) error {
+// ExportDailySalesReport exports a single operational business-day sales report as PDF (default), CSV or XLSX.
+// GET /api/admin/reports/daily?date=YYYY-MM-DD&format=pdf|csv|xlsx
+// GET /api/admin/analytics/reports/daily (legacy path)
+func (h *DashboardHandler) ExportDailySalesReport(c *fiber.Ctx) error {
 	dateParam := strings.TrimSpace(c.Query("date", ""))
+	format := strings.TrimSpace(c.Query("format", service.ReportFormatPDF))
 
-	pdfBytes, filename, err := h.dashboardService.GenerateDailySalesReportPDF(c.Context(), dateParam)
+	data, filename, err := h.dashboardService.GenerateDailySalesReport(c.Context(), dateParam, format)
 	if err != nil {
 		status := fiber.StatusInternalServerError
-		if strings.Contains(strings.ToLower(err.Error()), "invalid date format") {
+		if strings.Contains(strings.ToLower(err.Error()), "invalid") {
 			status = fiber.StatusBadRequest
 		}
 
@@ -433,58 +736,177 @@ func (h *DashboardHandler) ExportDailySalesReportPDF(c *fiber.Ctx) error {
 		})
 	}
 
-	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Type", service.ReportContentType(format))
 	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-	return c.Send(pdfBytes)
+	return c.Send(data)
 }
 
-// ExportLast30DaysSalesReportPDF exports previous 30 completed operational business days as PDF.
-// GET /api/admin/analytics/reports/last-30-days
-func (h *DashboardHandler) ExportLast30DaysSalesReportPDF(c *fiber.Ctx) error {
-	pdfBytes, filename, err := h.dashboardService.GenerateLast30DaysSalesReportPDF(c.Context())
+// ExportLast30DaysSalesReport exports previous 30 completed operational business days as PDF (default), CSV or XLSX.
+// GET /api/admin/reports/last-30-days?format=pdf|csv|xlsx
+// GET /api/admin/analytics/reports/last-30-days (legacy path)
+func (h *DashboardHandler) ExportLast30DaysSalesReport(c *fiber.Ctx) error {
+	format := strings.TrimSpace(c.Query("format", service.ReportFormatPDF))
+
+	data, filename, err := h.dashboardService.GenerateLast30DaysSalesReport(c.Context(), format)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid report format") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to generate 30-day report",
 		})
 	}
 
-	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Type", service.ReportContentType(format))
 	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-	return c.Send(pdfBytes)
+	return c.Send(data)
 }
 
-// SSEEvents handles Server-Sent Events for real-time updates
+// ExportWeeklySalesReport exports a Monday-to-Sunday business week compared with the week before as PDF (default), CSV or XLSX.
+// GET /api/admin/reports/weekly?date=YYYY-MM-DD&format=pdf|csv|xlsx
+func (h *DashboardHandler) ExportWeeklySalesReport(c *fiber.Ctx) error {
+	dateParam := strings.TrimSpace(c.Query("date", ""))
+	format := strings.TrimSpace(c.Query("format", service.ReportFormatPDF))
+
+	data, filename, err := h.dashboardService.GenerateWeeklySalesReport(c.Context(), dateParam, format)
+	return sendSalesReport(c, format, data, filename, err)
+}
+
+// ExportMonthlySalesReport exports a month of business days compared with the month before as PDF (default), CSV or XLSX.
+// GET /api/admin/reports/monthly?month=YYYY-MM&format=pdf|csv|xlsx
+func (h *DashboardHandler) ExportMonthlySalesReport(c *fiber.Ctx) error {
+	monthParam := strings.TrimSpace(c.Query("month", ""))
+	format := strings.TrimSpace(c.Query("format", service.ReportFormatPDF))
+
+	data, filename, err := h.dashboardService.GenerateMonthlySalesReport(c.Context(), monthParam, format)
+	return sendSalesReport(c, format, data, filename, err)
+}
+
+// sendSalesReport sends a generated report as an attachment, or its error
+func sendSalesReport(c *fiber.Ctx, format string, data []byte, filename string, err error) error {
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(strings.ToLower(err.Error()), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", service.ReportContentType(format))
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(data)
+}
+
+// SSEEvents handles Server-Sent Events for real-time updates.
+// Resume: a reconnecting client's Last-Event-ID header (or ?last_event_id=) replays the events it missed
+// that are still buffered. Filter: ?types=new_order,stock_updated. A client that reads too slowly gets
+// resync_required after missing events, and is disconnected once it stops reading altogether.
 // GET /api/admin/events
 func (h *DashboardHandler) SSEEvents(c *fiber.Ctx) error {
+	var lastEventID uint64
+	if raw := strings.TrimSpace(c.Get("Last-Event-ID", c.Query("last_event_id"))); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid Last-Event-ID",
+			})
+		}
+		lastEventID = id
+	}
+
+	types := make(map[events.EventType]bool)
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types[events.EventType(t)] = true
+		}
+	}
+
 	// Set headers for SSE
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
 	c.Set("Connection", "keep-alive")
 	c.Set("Transfer-Encoding", "chunked")
 
-	// Create context with timeout
-	ctx, cancel := context.WithCancel(c.Context())
-	defer cancel()
+	// The subscription lives as long as the stream, which outlasts this handler; the stream writer
+	// cancels it when the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
 
-	// Subscribe to event bus
+	// Subscribe to event bus before reading the replay buffer so nothing falls between the two
 	subscriberID := uuid.New().String()
-	eventChan := h.dashboardService.GetEventBus().Subscribe(ctx, subscriberID)
-
-	// Send initial connection message
-	c.Write([]byte("event: connected\ndata: {\"message\":\"connected\"}\n\n"))
+	eventBus := h.dashboardService.GetEventBus()
+	eventChan := eventBus.Subscribe(ctx, subscriberID)
+
+	// A client the buffer can't catch up (e.g. after a deploy, or away too long) is told to reload, and
+	// gets every live event from here on
+	var missed []events.Event
+	resync := false
+	if lastEventID > 0 {
+		var complete bool
+		if missed, complete = eventBus.Since(lastEventID, types); !complete {
+			resync = true
+			lastEventID = 0
+		}
+	}
 
 	// Stream events
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
 		// Send heartbeat every 30 seconds
 		ticker := time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
 
+		// Send initial connection message
+		if _, err := w.Write([]byte("event: connected\ndata: {\"message\":\"connected\"}\n\n")); err != nil {
+			return
+		}
+
+		if resync {
+			sseData, err := events.FormatSSE(events.Event{
+				Type:          events.EventResyncRequired,
+				Data:          events.ResyncRequiredPayload{Reason: events.ResyncReplayUnavailable},
+				SchemaVersion: events.SchemaVersion,
+			})
+			if err == nil {
+				if _, err := w.Write([]byte(sseData)); err != nil {
+					return
+				}
+			}
+		}
+
+		// Replay what the client missed; events also received live below are skipped by ID
+		for _, event := range missed {
+			sseData, err := events.FormatSSE(event)
+			if err != nil {
+				fmt.Printf("Error formatting SSE: %v\n", err)
+				continue
+			}
+			if _, err := w.Write([]byte(sseData)); err != nil {
+				return
+			}
+			lastEventID = event.ID
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
 		for {
 			select {
 			case event, ok := <-eventChan:
 				if !ok {
 					return
 				}
+				if len(types) > 0 && !types[event.Type] && event.Type != events.EventResyncRequired {
+					continue
+				}
+				if event.ID > 0 && event.ID <= lastEventID {
+					continue
+				}
 
 				// Format and send event
 				sseData, err := events.FormatSSE(event)
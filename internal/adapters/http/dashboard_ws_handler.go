@@ -0,0 +1,261 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 75 * time.Second
+	wsAuthWait     = 10 * time.Second
+	wsWriteWait    = 10 * time.Second
+
+	wsMaxMessageSize = 64 * 1024
+)
+
+// wsClientMessage is a control message sent by dashboard WebSocket clients.
+//
+//	{"type":"auth","token":"<jwt>"}
+//	{"type":"subscribe","events":["new_order","order_ready"]}  (empty list = all events)
+type wsClientMessage struct {
+	Type   string   `json:"type"`
+	Token  string   `json:"token"`
+	Events []string `json:"events"`
+}
+
+// wsEventFilter tracks which event types a WebSocket client wants
+type wsEventFilter struct {
+	mu    sync.RWMutex
+	types map[events.EventType]struct{}
+}
+
+func newWSEventFilter(types []string) *wsEventFilter {
+	f := &wsEventFilter{}
+	f.set(types)
+	return f
+}
+
+func (f *wsEventFilter) set(types []string) {
+	selected := make(map[events.EventType]struct{}, len(types))
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" {
+			selected[events.EventType(t)] = struct{}{}
+		}
+	}
+
+	f.mu.Lock()
+	f.types = selected
+	f.mu.Unlock()
+}
+
+func (f *wsEventFilter) allows(eventType events.EventType) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if len(f.types) == 0 {
+		return true
+	}
+	_, ok := f.types[eventType]
+	return ok
+}
+
+// WebSocketEvents streams the same events as SSEEvents over a WebSocket.
+// Auth: auth_token cookie, Bearer header, ?token= query param, or a first {"type":"auth"} message.
+// Filter: ?events=new_order,order_ready or a {"type":"subscribe"} message.
+// GET /api/admin/ws
+func (h *DashboardHandler) WebSocketEvents(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{
+			"error": "websocket upgrade required",
+		})
+	}
+
+	token := c.Cookies("auth_token")
+	if token == "" {
+		parts := strings.Split(c.Get("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			token = parts[1]
+		}
+	}
+	if token == "" {
+		token = strings.TrimSpace(c.Query("token"))
+	}
+
+	// Reject bad tokens before upgrading so clients get a proper HTTP status.
+	if token != "" {
+		if err := h.authorizeWebSocketToken(token); err != nil {
+			status := fiber.StatusUnauthorized
+			if strings.HasPrefix(err.Error(), "forbidden") {
+				status = fiber.StatusForbidden
+			}
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	var initialTypes []string
+	if raw := c.Query("events"); raw != "" {
+		initialTypes = strings.Split(raw, ",")
+	}
+
+	return websocket.New(func(ws *websocket.Conn) {
+		h.serveWebSocketEvents(ws, token != "", initialTypes)
+	})(c)
+}
+
+// serveWebSocketEvents runs the event loop for an upgraded dashboard connection
+func (h *DashboardHandler) serveWebSocketEvents(ws *websocket.Conn, authenticated bool, initialTypes []string) {
+	ws.SetReadLimit(wsMaxMessageSize)
+	if !authenticated {
+		if err := h.awaitWebSocketAuth(ws); err != nil {
+			closeWebSocket(ws, 4001, err.Error())
+			return
+		}
+	}
+
+	filter := newWSEventFilter(initialTypes)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriberID := uuid.New().String()
+	eventChan := h.dashboardService.GetEventBus().Subscribe(ctx, subscriberID)
+
+	if err := writeWebSocketText(ws, []byte(`{"type":"connected","data":{"message":"connected"}}`)); err != nil {
+		return
+	}
+
+	// Reader: handles subscribe messages and detects dead clients. Every message,
+	// and every pong to our pings, extends the read deadline.
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		defer cancel()
+		for {
+			if err := ws.SetReadDeadline(time.Now().Add(wsPongWait)); err != nil {
+				return
+			}
+			_, payload, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var msg wsClientMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				continue
+			}
+			if strings.EqualFold(msg.Type, "subscribe") {
+				filter.set(msg.Events)
+			}
+		}
+	}()
+
+	// The connection is recycled once this handler returns, so the reader must stop first.
+	defer func() {
+		_ = ws.Close()
+		<-readerDone
+	}()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			if !filter.allows(event.Type) && event.Type != events.EventResyncRequired {
+				continue
+			}
+
+			payload, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Error formatting WebSocket event: %v", err)
+				continue
+			}
+			if err := writeWebSocketText(ws, payload); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+
+		case <-ctx.Done():
+			closeWebSocket(ws, websocket.CloseNormalClosure, "")
+			return
+		}
+	}
+}
+
+// awaitWebSocketAuth waits for a {"type":"auth","token":"..."} first message
+func (h *DashboardHandler) awaitWebSocketAuth(ws *websocket.Conn) error {
+	if err := ws.SetReadDeadline(time.Now().Add(wsAuthWait)); err != nil {
+		return fmt.Errorf("unauthorized: no token provided")
+	}
+	_, payload, err := ws.ReadMessage()
+	if err != nil {
+		return fmt.Errorf("unauthorized: no token provided")
+	}
+
+	var msg wsClientMessage
+	if err := json.Unmarshal(payload, &msg); err != nil || !strings.EqualFold(msg.Type, "auth") {
+		return fmt.Errorf("unauthorized: expected auth message")
+	}
+
+	return h.authorizeWebSocketToken(strings.TrimSpace(msg.Token))
+}
+
+// authorizeWebSocketToken validates the JWT (including revocation) and enforces the same roles as SSEEvents
+func (h *DashboardHandler) authorizeWebSocketToken(token string) error {
+	if token == "" {
+		return fmt.Errorf("unauthorized: no token provided")
+	}
+
+	claims, err := h.dashboardService.AuthorizeToken(context.Background(), token)
+	if err != nil {
+		return fmt.Errorf("unauthorized: invalid token")
+	}
+
+	role, ok := claims["role"].(string)
+	if !ok {
+		return fmt.Errorf("forbidden: insufficient permissions")
+	}
+	role = strings.ToUpper(strings.TrimSpace(role))
+	if role != "MANAGER" && role != "BARTENDER" {
+		return fmt.Errorf("forbidden: insufficient permissions")
+	}
+	return nil
+}
+
+// writeWebSocketText sends one text message; only the serveWebSocketEvents loop writes data frames
+func writeWebSocketText(ws *websocket.Conn, payload []byte) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	return ws.WriteMessage(websocket.TextMessage, payload)
+}
+
+// closeWebSocket sends a close frame with code and reason, then closes the connection
+func closeWebSocket(ws *websocket.Conn, code int, reason string) {
+	_ = ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteWait))
+	_ = ws.Close()
+}
@@ -0,0 +1,200 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/http"
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/dumu-tech/destination-cocktails/internal/testkit"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "ws-test-secret"
+
+// wsServer serves WebSocketEvents on a real listener, since upgrades need a hijackable connection
+type wsServer struct {
+	url     string
+	app     *fiber.App
+	bus     *events.EventBus
+	manager *core.AdminUser
+}
+
+func newWSServer(t *testing.T) *wsServer {
+	t.Helper()
+
+	clock := core.SystemClock{}
+	ids := core.UUIDGenerator{}
+	manager := &core.AdminUser{PhoneNumber: "254700000001", Name: "Manager", Role: core.AdminRoleManager, IsActive: true}
+	admins := testkit.NewAdminUserRepository(clock, ids, manager)
+	bus := events.NewEventBus()
+
+	dashboardService := service.NewDashboardService(admins, nil, nil, nil, nil, nil, bus, testJWTSecret)
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/api/admin/ws", http.NewDashboardHandler(dashboardService).WebSocketEvents)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	return &wsServer{url: "ws://" + ln.Addr().String() + "/api/admin/ws", app: app, bus: bus, manager: manager}
+}
+
+// token signs an access token for the manager with the given role claim; nil leaves it out
+func (s *wsServer) token(t *testing.T, role interface{}) string {
+	t.Helper()
+
+	claims := jwt.MapClaims{
+		"user_id": s.manager.ID,
+		"phone":   s.manager.PhoneNumber,
+		"ver":     s.manager.TokenVersion,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+	if role != nil {
+		claims["role"] = role
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (s *wsServer) dial(t *testing.T, query string, header nethttp.Header) (*websocket.Conn, int, error) {
+	t.Helper()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(s.url+query, header)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+		resp.Body.Close()
+	}
+	if conn != nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, status, err
+}
+
+// readType reads the next message and returns its "type"
+func readType(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var msg struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatalf("decode %s: %v", payload, err)
+	}
+	return msg.Type
+}
+
+func TestWebSocketEventsStreamsFilteredEvents(t *testing.T) {
+	s := newWSServer(t)
+
+	header := nethttp.Header{"Authorization": {"Bearer " + s.token(t, "MANAGER")}}
+	conn, _, err := s.dial(t, "?events=order_completed", header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readType(t, conn); got != "connected" {
+		t.Fatalf("first message %q, want connected", got)
+	}
+
+	s.bus.PublishStockUpdated(context.Background(), "p1", 3)
+	s.bus.PublishOrderCompleted(context.Background(), "o1")
+	if got := readType(t, conn); got != string(events.EventOrderCompleted) {
+		t.Errorf("got %q, want only the subscribed order_completed", got)
+	}
+}
+
+func TestWebSocketEventsAuthMessage(t *testing.T) {
+	s := newWSServer(t)
+
+	conn, _, err := s.dial(t, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteJSON(map[string]string{"type": "auth", "token": s.token(t, "bartender")}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readType(t, conn); got != "connected" {
+		t.Errorf("got %q, want connected", got)
+	}
+}
+
+func TestWebSocketEventsRejectsBadAuthMessage(t *testing.T) {
+	s := newWSServer(t)
+
+	for name, msg := range map[string]interface{}{
+		"not auth":       map[string]string{"type": "subscribe"},
+		"role not text":  map[string]interface{}{"type": "auth", "token": s.token(t, 1)},
+		"invalid token":  map[string]string{"type": "auth", "token": "nope"},
+		"no role in jwt": map[string]string{"type": "auth", "token": s.token(t, nil)},
+	} {
+		conn, _, err := s.dial(t, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != 4001 {
+			t.Errorf("%s: got %v, want close 4001", name, err)
+		}
+	}
+}
+
+func TestWebSocketEventsRejectsTokensBeforeUpgrade(t *testing.T) {
+	s := newWSServer(t)
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "invalid", token: "nope", want: fiber.StatusUnauthorized},
+		{name: "role not text", token: s.token(t, []string{"MANAGER"}), want: fiber.StatusForbidden},
+		{name: "no role", token: s.token(t, nil), want: fiber.StatusForbidden},
+		{name: "rider", token: s.token(t, "RIDER"), want: fiber.StatusForbidden},
+	} {
+		_, status, err := s.dial(t, "?token="+tc.token, nil)
+		if !errors.Is(err, websocket.ErrBadHandshake) || status != tc.want {
+			t.Errorf("%s: got %d (%v), want %d", tc.name, status, err, tc.want)
+		}
+	}
+}
+
+func TestWebSocketEventsRequiresUpgrade(t *testing.T) {
+	s := newWSServer(t)
+
+	resp, err := s.app.Test(httptest.NewRequest(fiber.MethodGet, "/api/admin/ws", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusUpgradeRequired {
+		t.Errorf("got %d, want %d", resp.StatusCode, fiber.StatusUpgradeRequired)
+	}
+}
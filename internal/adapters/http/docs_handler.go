@@ -0,0 +1,60 @@
+package http
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// docsPath is where the API docs are served; the spec itself is at docsPath + "/openapi.json"
+const docsPath = "/api/docs"
+
+// swaggerUIPage loads Swagger UI from the CDN and points it at the generated spec
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Destination Cocktails API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "` + docsPath + `/openapi.json", dom_id: "#swagger-ui", withCredentials: true });
+  </script>
+</body>
+</html>`
+
+// DocsHandler serves the OpenAPI spec for the app's routes and a Swagger UI page
+type DocsHandler struct {
+	app     *fiber.App
+	version string
+
+	once sync.Once
+	spec fiber.Map
+}
+
+// NewDocsHandler creates a docs handler. The spec is built on first request, after every route is registered.
+func NewDocsHandler(app *fiber.App, version string) *DocsHandler {
+	if version == "" {
+		version = "dev"
+	}
+	return &DocsHandler{app: app, version: version}
+}
+
+// SwaggerUI serves the interactive API docs
+// GET /api/docs
+func (h *DocsHandler) SwaggerUI(c *fiber.Ctx) error {
+	c.Type("html", "utf-8")
+	return c.SendString(swaggerUIPage)
+}
+
+// OpenAPISpec serves the generated OpenAPI 3 document
+// GET /api/docs/openapi.json
+func (h *DocsHandler) OpenAPISpec(c *fiber.Ctx) error {
+	h.once.Do(func() {
+		h.spec = BuildOpenAPISpec(h.app.GetRoutes(true), h.version)
+	})
+	return c.JSON(h.spec)
+}
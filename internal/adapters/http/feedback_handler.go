@@ -0,0 +1,30 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetFeedbackReport returns the average rating, response rate, daily trend and latest low ratings
+// GET /api/admin/feedback?from=2026-03-01&to=2026-03-31&limit=50
+func (h *DashboardHandler) GetFeedbackReport(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	report, err := h.dashboardService.GetFeedbackReport(c.Context(), c.Query("from"), c.Query("to"), limit)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
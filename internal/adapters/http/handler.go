@@ -5,17 +5,23 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/adapters/whatsapp"
 	"github.com/dumu-tech/destination-cocktails/internal/config"
 	"github.com/dumu-tech/destination-cocktails/internal/core"
 	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // Handler handles HTTP requests for WhatsApp webhooks and payment webhooks
@@ -27,6 +33,26 @@ type Handler struct {
 	orderRepo       OrderRepositoryHandler
 	whatsappGateway WhatsAppGatewayHandler
 	eventBus        *events.EventBus
+	staffNotifier   BarStaffNotifierHandler
+	riderNotifier   RiderNotifierHandler
+	paymentRepo     PaymentRecorderHandler
+	languages       CustomerLanguageResolver
+	receipts        ReceiptSenderHandler
+	stkAttempts     STKAttemptHandler
+	failedPayments  FailedPaymentRecorderHandler
+	confirmations   PaymentConfirmationSender
+	shiftCommands   ShiftCommandHandler
+	favorites       FavoriteOfferHandler
+	reservations    ReservationPaymentHandler
+	rejections      webhookRejections
+	webhookEvents   core.WebhookEventRepository
+	outbox          *service.OutboxDispatcher
+	whatsAppNumbers WhatsAppNumberRouter
+	latePayments    LatePaymentAlerter
+
+	processedMessages core.ProcessedMessageStore
+	dedupTTL          time.Duration
+	messageQueues     phoneQueues
 }
 
 // PaymentGatewayHandler defines the interface for payment gateway
@@ -37,21 +63,94 @@ type PaymentGatewayHandler interface {
 
 // OrderRepositoryHandler defines the interface for order repository
 type OrderRepositoryHandler interface {
-	UpdateStatus(ctx context.Context, id string, status core.OrderStatus) error
+	UpdateStatusWithNote(ctx context.Context, id string, status core.OrderStatus, actor string, note string) error
 	GetByID(ctx context.Context, id string) (*core.Order, error)
 	FindPendingByPhoneAndAmount(ctx context.Context, phone string, amount float64) (*core.Order, error)
 	FindPendingByHashedPhoneAndAmount(ctx context.Context, hashedPhone string, amount float64) (*core.Order, error)
 	FindPendingByAmount(ctx context.Context, amount float64) (*core.Order, error)
+	ApplyPayment(ctx context.Context, orderID string, phone string, amount float64, reference string, actor string, note string) (*core.PaymentApplication, error)
+	FailPaymentShare(ctx context.Context, orderID string, phone string, amount float64) (*core.PaymentShare, error)
 }
 
 // WhatsAppGatewayHandler defines the interface for WhatsApp gateway
 type WhatsAppGatewayHandler interface {
 	SendText(ctx context.Context, phone string, message string) error
+	SendMenuButtons(ctx context.Context, phone string, text string, buttons []core.Button) error
+}
+
+// BarStaffNotifierHandler defines the interface for the bar staff roster dispatcher
+type BarStaffNotifierHandler interface {
+	NotifyPaidOrder(ctx context.Context, order *core.Order) error
+	AcceptOrder(ctx context.Context, staffPhone string, orderID string) error
+	ConfirmBarPayment(ctx context.Context, staffPhone string, orderID string, method core.PaymentMethod) (*core.Order, error)
+}
+
+// RiderNotifierHandler handles the Accept and Delivered buttons riders tap on delivery orders
+type RiderNotifierHandler interface {
+	AcceptDelivery(ctx context.Context, riderPhone string, orderID string) error
+	ConfirmDelivered(ctx context.Context, riderPhone string, orderID string) error
+}
+
+// PaymentRecorderHandler defines the interface for the payments ledger
+type PaymentRecorderHandler interface {
+	Create(ctx context.Context, payment *core.Payment) error
+}
+
+// STKAttemptHandler defines the interface for matching STK push callbacks to the attempt that started them
+type STKAttemptHandler interface {
+	FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*core.STKAttempt, error)
+	UpdateResult(ctx context.Context, paymentRequestID string, status string, errMsg string) error
+}
+
+// CustomerLanguageResolver looks up a customer's preferred bot language by user ID
+type CustomerLanguageResolver interface {
+	CustomerLanguage(ctx context.Context, userID string) string
+}
+
+// ReceiptSenderHandler defines the interface for sending PDF receipts to customers
+type ReceiptSenderHandler interface {
+	SendReceipt(ctx context.Context, order *core.Order, lang string) error
+}
+
+// LatePaymentAlerter tells managers about a payment for an order staff had already cancelled
+type LatePaymentAlerter interface {
+	AlertRefundRequired(ctx context.Context, order *core.Order, amount float64, reference string)
+}
+
+// FailedPaymentRecorderHandler defines the interface for flagging customers with repeated failed payments
+type FailedPaymentRecorderHandler interface {
+	RecordFailedPayment(ctx context.Context, phone string) error
+}
+
+// PaymentConfirmationSender delivers payment confirmations over WhatsApp with an SMS fallback
+type PaymentConfirmationSender interface {
+	SendPaymentConfirmation(ctx context.Context, phone string, message string) error
+}
+
+// ShiftCommandHandler handles "clock in"/"clock out" from bar staff; false means the message is for the bot
+type ShiftCommandHandler interface {
+	HandleCommand(ctx context.Context, phone string, message string) bool
+}
+
+// FavoriteOfferHandler offers to save a paid order as a favorite after its confirmation
+type FavoriteOfferHandler interface {
+	OfferFavorite(ctx context.Context, order *core.Order, lang string) error
+}
+
+// ReservationPaymentHandler applies deposit payments for reservations; false means the payment is for an order
+type ReservationPaymentHandler interface {
+	ApplyReservationPayment(ctx context.Context, reservationID string, reference string, success bool) (bool, error)
+}
+
+// WhatsAppNumberRouter records which WhatsApp number each customer messaged; false means the number is
+// deactivated and its messages are ignored
+type WhatsAppNumberRouter interface {
+	RouteInbound(ctx context.Context, phoneNumberID string, phone string) bool
 }
 
 // BotServiceHandler defines the interface for bot service
 type BotServiceHandler interface {
-	HandleIncomingMessage(phone string, message string, messageType string) error
+	HandleIncomingMessage(ctx context.Context, phone string, message string, messageType string) error
 }
 
 // NewHandler creates a new HTTP handler
@@ -67,9 +166,14 @@ func NewHandler(botService BotServiceHandler, paymentGateway PaymentGatewayHandl
 		len(verifyToken),
 		maskToken(verifyToken))
 
+	appSecret := strings.TrimSpace(cfg.WhatsAppAppSecret)
+	if appSecret == "" {
+		log.Printf("WARNING: WHATSAPP_APP_SECRET is not set - WhatsApp webhook signatures are NOT verified and anyone can post fake customer messages")
+	}
+
 	return &Handler{
 		verifyToken:     verifyToken,
-		appSecret:       "", // TODO: Add APP_SECRET to config if available
+		appSecret:       appSecret,
 		botService:      botService,
 		paymentGateway:  paymentGateway,
 		orderRepo:       orderRepo,
@@ -83,6 +187,79 @@ func (h *Handler) SetEventBus(eventBus *events.EventBus) {
 	h.eventBus = eventBus
 }
 
+// SetPaymentRepository enables recording webhook-confirmed payments in the ledger
+func (h *Handler) SetPaymentRepository(paymentRepo PaymentRecorderHandler) {
+	h.paymentRepo = paymentRepo
+}
+
+// SetSTKAttemptRepository enables matching STK push callbacks by Kopo Kopo payment request ID
+func (h *Handler) SetSTKAttemptRepository(stkAttempts STKAttemptHandler) {
+	h.stkAttempts = stkAttempts
+}
+
+// SetLanguageResolver enables translated payment notifications for customers
+func (h *Handler) SetLanguageResolver(resolver CustomerLanguageResolver) {
+	h.languages = resolver
+}
+
+// SetBarStaffNotifier sets the roster-based dispatcher used for paid-order notifications
+func (h *Handler) SetBarStaffNotifier(notifier BarStaffNotifierHandler) {
+	h.staffNotifier = notifier
+}
+
+// SetLatePaymentAlerts enables alerting managers about payments for cancelled orders
+func (h *Handler) SetLatePaymentAlerts(alerts LatePaymentAlerter) {
+	h.latePayments = alerts
+}
+
+// SetRiderNotifier enables the riders' Accept and Delivered buttons
+func (h *Handler) SetRiderNotifier(notifier RiderNotifierHandler) {
+	h.riderNotifier = notifier
+}
+
+// SetReceiptSender enables sending a PDF receipt after each confirmed payment
+func (h *Handler) SetReceiptSender(receipts ReceiptSenderHandler) {
+	h.receipts = receipts
+}
+
+// SetFailedPaymentRecorder enables flagging customers whose payments keep failing
+func (h *Handler) SetFailedPaymentRecorder(recorder FailedPaymentRecorderHandler) {
+	h.failedPayments = recorder
+}
+
+// SetPaymentConfirmationSender routes payment confirmations through sender instead of WhatsApp only
+func (h *Handler) SetPaymentConfirmationSender(sender PaymentConfirmationSender) {
+	h.confirmations = sender
+}
+
+// SetShiftCommandHandler lets bar staff clock in and out over WhatsApp
+func (h *Handler) SetShiftCommandHandler(shifts ShiftCommandHandler) {
+	h.shiftCommands = shifts
+}
+
+// SetFavoriteOffer enables the "Save as favorite" button after each paid order's confirmation
+func (h *Handler) SetFavoriteOffer(favorites FavoriteOfferHandler) {
+	h.favorites = favorites
+}
+
+// SetReservationPayments lets payment callbacks confirm or cancel reservation deposits
+func (h *Handler) SetReservationPayments(reservations ReservationPaymentHandler) {
+	h.reservations = reservations
+}
+
+// SetWhatsAppNumbers routes messages by the number they were sent to, for several numbers on one server
+func (h *Handler) SetWhatsAppNumbers(numbers WhatsAppNumberRouter) {
+	h.whatsAppNumbers = numbers
+}
+
+// sendPaymentConfirmation tells the customer their payment went through, by SMS too when configured
+func (h *Handler) sendPaymentConfirmation(ctx context.Context, phone string, message string) error {
+	if h.confirmations != nil {
+		return h.confirmations.SendPaymentConfirmation(ctx, phone, message)
+	}
+	return h.whatsappGateway.SendText(ctx, phone, message)
+}
+
 // VerifyWebhook handles GET requests for webhook verification
 func (h *Handler) VerifyWebhook(c *fiber.Ctx) error {
 	mode := c.Query("hub.mode")
@@ -130,33 +307,37 @@ func maskToken(token string) string {
 
 // ReceiveMessage handles POST requests for incoming WhatsApp messages
 func (h *Handler) ReceiveMessage(c *fiber.Ctx) error {
-	// Verify X-Hub-Signature-256 if app secret is configured
+	// Verify X-Hub-Signature-256 (HMAC-SHA256 of the raw body with the app secret) when configured
 	if h.appSecret != "" {
 		signature := c.Get("X-Hub-Signature-256")
 		if signature == "" {
-			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Missing signature",
-			})
+			c.Locals("webhook_signature_valid", false)
+			return h.rejectWebhook(c, webhookRejectMissingSignature)
 		}
 
-		body := c.Body()
-		if !h.verifySignature(signature, body) {
-			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid signature",
-			})
+		valid := h.verifySignature(signature, c.Body())
+		c.Locals("webhook_signature_valid", valid)
+		if !valid {
+			return h.rejectWebhook(c, webhookRejectInvalidSignature)
 		}
-	} else {
-		// TODO: Implement proper HMAC-SHA256 verification when APP_SECRET is available
-		// For now, we skip verification if app secret is not set
 	}
 
+	return h.processWhatsAppWebhook(c, c.Body(), false)
+}
+
+// processWhatsAppWebhook hands each message in a verified WhatsApp webhook body to the bot, staff or rider
+// flows. It also re-runs archived webhooks on replay, which skips the redelivery check.
+func (h *Handler) processWhatsAppWebhook(c *fiber.Ctx, body []byte, replay bool) error {
 	var payload whatsapp.WebhookPayload
-	if err := c.BodyParser(&payload); err != nil {
+	if err := json.Unmarshal(body, &payload); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid payload",
 		})
 	}
 
+	// Messages are handled after the response is sent, so they get their own context carrying the request ID
+	ctx := core.DetachRequestID(c.Context())
+
 	// Process each entry
 	for _, entry := range payload.Entry {
 		for _, change := range entry.Changes {
@@ -166,6 +347,20 @@ func (h *Handler) ReceiveMessage(c *fiber.Ctx) error {
 
 			value := change.Value
 			for _, msg := range value.Messages {
+				// Meta redelivers messages it isn't sure we received; handle each message ID once
+				if !replay && h.alreadyProcessed(ctx, msg.ID) {
+					continue
+				}
+
+				// Replies go out from the number the message was sent to, and the bot shows that venue's menu
+				ctx := core.WithWhatsAppNumber(ctx, value.Metadata.PhoneNumberID)
+				if h.whatsAppNumbers != nil {
+					if !h.whatsAppNumbers.RouteInbound(ctx, value.Metadata.PhoneNumberID, msg.From) {
+						log.Printf("Ignoring message %s to inactive WhatsApp number %s", msg.ID, value.Metadata.PhoneNumberID)
+						continue
+					}
+				}
+
 				phone := msg.From
 				messageType := msg.Type
 
@@ -183,7 +378,24 @@ func (h *Handler) ReceiveMessage(c *fiber.Ctx) error {
 					case "list_reply":
 						interactiveID = msg.Interactive.ListReply.ID
 						messageText = msg.Interactive.ListReply.Title
+					case "nfm_reply":
+						// Submitted WhatsApp Flow form: the bot parses the JSON fields
+						messageType = service.FlowReplyMessageType
+						messageText = msg.Interactive.NfmReply.ResponseJSON
+					}
+				case "location":
+					// Shared location pin, e.g. a delivery address: the bot parses the JSON
+					location, err := json.Marshal(service.SharedLocation{
+						Latitude:  msg.Location.Latitude,
+						Longitude: msg.Location.Longitude,
+						Name:      msg.Location.Name,
+						Address:   msg.Location.Address,
+					})
+					if err != nil {
+						continue
 					}
+					messageType = service.LocationMessageType
+					messageText = string(location)
 				default:
 					// Unsupported message type
 					continue
@@ -195,20 +407,68 @@ func (h *Handler) ReceiveMessage(c *fiber.Ctx) error {
 					messageToProcess = messageText
 				}
 
+				// Check if this is an "Accept" button from bar staff
+				if strings.HasPrefix(messageToProcess, "accept_") && h.staffNotifier != nil {
+					orderID := strings.TrimPrefix(messageToProcess, "accept_")
+					h.messageQueues.Go(ctx, phone, "bar_staff.accept_order", func(ctx context.Context) error {
+						if err := h.staffNotifier.AcceptOrder(ctx, phone, orderID); err != nil {
+							return fmt.Errorf("failed to accept order %s: %w", orderID, err)
+						}
+						return nil
+					})
+					continue
+				}
+
+				// Check if this is a "Cash Received" / "Card Paid" button for a pay-at-the-bar order
+				if strings.HasPrefix(messageToProcess, "barpaid_") && h.staffNotifier != nil {
+					payload := strings.TrimPrefix(messageToProcess, "barpaid_")
+					h.messageQueues.Go(ctx, phone, "bar_staff.confirm_payment", func(ctx context.Context) error {
+						h.handleBarPayment(ctx, phone, payload)
+						return nil
+					})
+					continue
+				}
+
+				// Check if this is an "Accept" or "Delivered" button from a rider
+				if strings.HasPrefix(messageToProcess, service.RiderAcceptPrefix) && h.riderNotifier != nil {
+					orderID := strings.TrimPrefix(messageToProcess, service.RiderAcceptPrefix)
+					h.messageQueues.Go(ctx, phone, "rider.accept_delivery", func(ctx context.Context) error {
+						if err := h.riderNotifier.AcceptDelivery(ctx, phone, orderID); err != nil {
+							return fmt.Errorf("failed to accept delivery %s: %w", orderID, err)
+						}
+						return nil
+					})
+					continue
+				}
+				if strings.HasPrefix(messageToProcess, service.RiderDeliveredPrefix) && h.riderNotifier != nil {
+					orderID := strings.TrimPrefix(messageToProcess, service.RiderDeliveredPrefix)
+					h.messageQueues.Go(ctx, phone, "rider.confirm_delivered", func(ctx context.Context) error {
+						if err := h.riderNotifier.ConfirmDelivered(ctx, phone, orderID); err != nil {
+							return fmt.Errorf("failed to confirm delivery %s: %w", orderID, err)
+						}
+						return nil
+					})
+					continue
+				}
+
 				// Check if this is a "Mark Done" button from bar staff
 				if strings.HasPrefix(messageToProcess, "complete_") {
 					orderID := strings.TrimPrefix(messageToProcess, "complete_")
-					go h.handleOrderCompletion(c.Context(), phone, orderID)
+					h.messageQueues.Go(ctx, phone, "bar_staff.complete_order", func(ctx context.Context) error {
+						h.handleOrderCompletion(ctx, phone, orderID)
+						return nil
+					})
 					continue
 				}
 
-				// Handle message asynchronously (fire and forget for webhook response)
-				go func(phoneNum, msgText, msgType string) {
-					if err := h.botService.HandleIncomingMessage(phoneNum, msgText, msgType); err != nil {
-						// Log error (in production, use proper logging)
-						fmt.Printf("Error handling message: %v\n", err)
+				// Handle message asynchronously (fire and forget for webhook response), after the phone's earlier messages
+				h.messageQueues.Go(ctx, phone, "bot.handle_message", func(ctx context.Context) error {
+					// Bar staff clock in/out by text; anything else, or from a customer, goes to the bot
+					if h.shiftCommands != nil && messageType == "text" && h.shiftCommands.HandleCommand(ctx, phone, messageToProcess) {
+						return nil
 					}
-				}(phone, messageToProcess, messageType)
+					return h.botService.HandleIncomingMessage(ctx, phone, messageToProcess, messageType)
+				})
 			}
 		}
 	}
@@ -246,18 +506,29 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 	// Verify X-KopoKopo-Signature header
 	signature := c.Get("X-KopoKopo-Signature")
 	if signature == "" {
+		c.Locals("webhook_signature_valid", false)
 		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Missing signature",
 		})
 	}
 
 	body := c.Body()
-	if !h.paymentGateway.VerifyWebhook(ctx, signature, body) {
+	valid := h.paymentGateway.VerifyWebhook(ctx, signature, body)
+	c.Locals("webhook_signature_valid", valid)
+	if !valid {
 		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Invalid signature",
 		})
 	}
 
+	return h.processPaymentWebhook(c, body)
+}
+
+// processPaymentWebhook applies a verified payment webhook body to its order. It also re-runs archived
+// webhooks on replay; payments already applied are recognised by reference and not counted twice.
+func (h *Handler) processPaymentWebhook(c *fiber.Ctx, body []byte) error {
+	ctx := c.Context()
+
 	// Process webhook
 	result, err := h.paymentGateway.ProcessWebhook(ctx, body)
 	if err != nil {
@@ -265,14 +536,38 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 			"error": "Failed to process webhook",
 		})
 	}
+	if result.RequestID != "" {
+		// Ties the callback to the request that sent the STK push
+		slog.InfoContext(ctx, "Payment callback received",
+			"origin_request_id", result.RequestID,
+			"payment_request_id", result.PaymentRequestID,
+			"order_id", result.OrderID)
+	}
+
+	// Reservation deposits carry the reservation ID where orders carry theirs
+	if h.reservations != nil && result.OrderID != "" {
+		handled, err := h.reservations.ApplyReservationPayment(ctx, result.OrderID, result.Reference, result.Success)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error applying reservation payment",
+				"order_id", result.OrderID,
+				"error", err)
+		}
+		if handled {
+			return c.Status(http.StatusOK).JSON(fiber.Map{"status": "ok"})
+		}
+	}
+
+	// Record the STK push outcome and resolve its order from the stored attempt
+	attemptOrder, attempt := h.resolveSTKAttempt(ctx, result)
+	payerPhone, payerAmount := callbackPayer(result, attempt)
 
 	// Handle payment status
 	if result.Success {
-		var order *core.Order
+		order := attemptOrder
 		var err error
 
 		// Strategy 1: Use OrderID if available (from incoming_payment webhook)
-		if result.OrderID != "" {
+		if order == nil && result.OrderID != "" {
 			order, err = h.orderRepo.GetByID(ctx, result.OrderID)
 			if err != nil {
 				fmt.Printf("Error finding order by ID %s: %v\n", result.OrderID, err)
@@ -310,7 +605,7 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 				fmt.Printf("Error finding order by amount: %v\n", err)
 			} else if order != nil {
 				// Log as warning since this is a risky fallback that can cause mismatches
-				slog.Warn("Payment matched using amount-only fallback (potential mismatch risk)",
+				slog.WarnContext(ctx, "Payment matched using amount-only fallback (potential mismatch risk)",
 					"matched_order_id", order.ID,
 					"matched_phone", order.CustomerPhone,
 					"webhook_phone", result.Phone,
@@ -321,15 +616,18 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 
 		// If no order found, log as orphaned payment (only if we had identifiers)
 		if order == nil {
+			// Record the transaction in the ledger as orphaned
+			h.recordPayment(ctx, result, nil)
+
 			if result.OrderID != "" || result.Phone != "" {
-				slog.Warn("Orphaned Payment Received - No matching order found",
+				slog.WarnContext(ctx, "Orphaned Payment Received - No matching order found",
 					"order_id", result.OrderID,
 					"amount", result.Amount,
 					"phone", result.Phone,
 					"reference", result.Reference,
 					"status", result.Status)
 			} else {
-				slog.Info("Payment webhook received without identifiers",
+				slog.InfoContext(ctx, "Payment webhook received without identifiers",
 					"amount", result.Amount,
 					"reference", result.Reference,
 					"status", result.Status)
@@ -343,8 +641,9 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 		}
 
 		// If already paid/completed, skip duplicate confirmation
-		if order.Status == core.OrderStatusPaid || order.Status == core.OrderStatusCompleted {
-			slog.Info("Payment webhook already processed for order",
+		if order.Status == core.OrderStatusPaid || order.Status == core.OrderStatusScheduled || order.Status == core.OrderStatusCompleted {
+			h.recordPayment(ctx, result, order)
+			slog.InfoContext(ctx, "Payment webhook already processed for order",
 				"order_id", order.ID,
 				"status", order.Status)
 			return c.Status(http.StatusOK).JSON(fiber.Map{
@@ -353,45 +652,71 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 			})
 		}
 
-		// Update order status to PAID
-		if err := h.orderRepo.UpdateStatus(ctx, order.ID, core.OrderStatusPaid); err != nil {
-			// Log error but don't fail the webhook (idempotency)
-			fmt.Printf("Error updating order status: %v\n", err)
-		} else {
-			// Reflect PAID in-memory so notifyBarStaff and SSE receive correct status
-			order.Status = core.OrderStatusPaid
-
-			// Send WhatsApp notification to customer with pickup code
-			message := fmt.Sprintf("✅ *Payment Received!*\n\n"+
-				"Your order has been confirmed 🍹\n\n"+
-				"*Pickup Code:* %s\n"+
-				"*Total:* KES %.0f\n\n"+
-				"Show this code to the bartender when collecting your drinks!\n\n"+
-				"_Type 'Menu' to order more._",
-				order.PickupCode, order.TotalAmount)
-			go func(phone, msg string) {
-				if err := h.whatsappGateway.SendText(ctx, phone, msg); err != nil {
-					fmt.Printf("Error sending payment confirmation: %v\n", err)
-				}
-			}(order.CustomerPhone, message)
+		// Callbacks without an amount predate split bills: the push was for everything still owed
+		if payerAmount <= 0 {
+			payerAmount = order.AmountDue()
+		}
+
+		// Add the payment to the order; it becomes PAID once payments cover the total
+		note := fmt.Sprintf("payment of KES %.0f confirmed (ref %s)", payerAmount, result.Reference)
+		application, err := h.orderRepo.ApplyPayment(ctx, order.ID, payerPhone, payerAmount, result.Reference, core.OrderActorWebhook, note)
 
-			// Send notification to bar staff (only when order is PAID)
-			go h.notifyBarStaff(ctx, order)
+		// Record the transaction in the ledger; a payment the cancelled order didn't take is orphaned so
+		// it shows up for a refund or to be attached to another order
+		if err == nil && application.RefundRequired {
+			h.recordPayment(ctx, result, nil)
+		} else {
+			h.recordPayment(ctx, result, order)
+		}
 
-			// Emit new_order event for dashboard SSE
-			if h.eventBus != nil {
-				h.eventBus.PublishNewOrder(order)
+		if err != nil {
+			// Log error but don't fail the webhook (idempotency)
+			slog.ErrorContext(ctx, "Error applying payment to order",
+				"reference", result.Reference,
+				"order_id", order.ID,
+				"error", err)
+		} else if application.RefundRequired {
+			slog.ErrorContext(ctx, "Payment received for a cancelled order; refund required",
+				"order_id", order.ID,
+				"amount", payerAmount,
+				"phone", payerPhone,
+				"reference", result.Reference)
+			if h.latePayments != nil {
+				amount, reference := payerAmount, result.Reference
+				reporting.Go(core.DetachRequestID(ctx), "payment.alert_refund_required", func(ctx context.Context) error {
+					h.latePayments.AlertRefundRequired(ctx, order, amount, reference)
+					return nil
+				})
 			}
+			return c.Status(http.StatusOK).JSON(fiber.Map{
+				"status": "ok",
+				"note":   "order was cancelled; payment recorded for refund",
+			})
+		} else if application.Duplicate {
+			slog.InfoContext(ctx, "Payment webhook already applied to order",
+				"order_id", order.ID,
+				"reference", result.Reference)
+			return c.Status(http.StatusOK).JSON(fiber.Map{
+				"status": "ok",
+				"note":   "payment already processed",
+			})
+		} else if application.Status == core.OrderStatusPartiallyPaid {
+			order.Status = application.Status
+			order.AmountPaid = application.AmountPaid
+			h.notifyPartialPayment(ctx, order, application)
+		} else if application.Status == core.OrderStatusPaid || application.Status == core.OrderStatusScheduled {
+			// The confirmation, bar staff message and SSE event were queued in the outbox with the payment
+			h.wakeOutbox()
 		}
 	} else {
 		// Payment failed or cancelled
 		fmt.Printf("[DEBUG] Payment failed/cancelled - OrderID: %s, Status: %s\n", result.OrderID, result.Status)
 
-		var order *core.Order
+		order := attemptOrder
 		var err error
 
 		// Try to find order by ID first (from incoming_payment webhook)
-		if result.OrderID != "" {
+		if order == nil && result.OrderID != "" {
 			order, err = h.orderRepo.GetByID(ctx, result.OrderID)
 			if err != nil {
 				fmt.Printf("Error finding failed order by ID: %v\n", err)
@@ -422,26 +747,31 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 			}
 		}
 
-		if order != nil {
-			if err := h.orderRepo.UpdateStatus(ctx, order.ID, core.OrderStatusFailed); err != nil {
+		if order != nil && h.failSplitShare(ctx, order, payerPhone, payerAmount) {
+			// The rest of the split bill stays open; the organiser was asked to resend the prompt
+		} else if order != nil && order.Status == core.OrderStatusPartiallyPaid {
+			slog.WarnContext(ctx, "Payment failed for a partially paid order; keeping payments already received",
+				"order_id", order.ID,
+				"reference", result.Reference)
+		} else if order != nil {
+			note := fmt.Sprintf("payment %s (ref %s)", strings.ToLower(result.Status), result.Reference)
+			if err := h.orderRepo.UpdateStatusWithNote(ctx, order.ID, core.OrderStatusFailed, core.OrderActorWebhook, note); err != nil {
 				fmt.Printf("Error updating order status to FAILED: %v\n", err)
 			} else {
 				// Notify customer of payment failure with helpful message
-				message := fmt.Sprintf("❌ *Payment Not Completed*\n\n"+
-					"Your M-Pesa payment for KES %.0f was cancelled or timed out.\n\n"+
-					"*Common reasons:*\n"+
-					"• PIN entry timed out (you have ~60 seconds)\n"+
-					"• Payment was cancelled\n"+
-					"• Network issues\n\n"+
-					"*To try again:*\n"+
-					"Send 'hi' to start a new order.\n\n"+
-					"_If you completed payment but see this message, please contact support._",
-					order.TotalAmount)
-				go func(phone, msg string) {
-					if err := h.whatsappGateway.SendText(ctx, phone, msg); err != nil {
-						fmt.Printf("Error sending payment failure notification: %v\n", err)
+				message := i18n.Default().T(h.customerLanguage(ctx, order), "payment.failed", order.TotalAmount)
+				reporting.Go(core.DetachRequestID(ctx), "payment.notify_failure", func(ctx context.Context) error {
+					if err := h.whatsappGateway.SendText(ctx, order.CustomerPhone, message); err != nil {
+						return fmt.Errorf("failed to send payment failure notification: %w", err)
 					}
-				}(order.CustomerPhone, message)
+					return nil
+				})
+				if h.failedPayments != nil {
+					phone := order.CustomerPhone
+					reporting.Go(core.DetachRequestID(ctx), "blocklist.record_failed_payment", func(ctx context.Context) error {
+						return h.failedPayments.RecordFailedPayment(ctx, phone)
+					})
+				}
 			}
 		}
 	}
@@ -452,13 +782,155 @@ func (h *Handler) HandlePaymentWebhook(c *fiber.Ctx) error {
 	})
 }
 
+// resolveSTKAttempt marks the STK attempt behind a callback as succeeded or failed and returns it with its order.
+// The payment request ID is assigned by Kopo Kopo, so unlike phone+amount it can't match the wrong order.
+func (h *Handler) resolveSTKAttempt(ctx context.Context, result *core.PaymentWebhook) (*core.Order, *core.STKAttempt) {
+	if h.stkAttempts == nil || result.PaymentRequestID == "" {
+		return nil, nil
+	}
+
+	attempt, err := h.stkAttempts.FindByPaymentRequestID(ctx, result.PaymentRequestID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			slog.ErrorContext(ctx, "Failed to look up STK attempt", "payment_request_id", result.PaymentRequestID, "error", err)
+		}
+		return nil, nil
+	}
+
+	status, errMsg := core.STKAttemptSuccess, ""
+	if !result.Success {
+		status, errMsg = core.STKAttemptFailed, result.Status
+	}
+	if err := h.stkAttempts.UpdateResult(ctx, result.PaymentRequestID, status, errMsg); err != nil {
+		slog.ErrorContext(ctx, "Failed to update STK attempt", "payment_request_id", result.PaymentRequestID, "error", err)
+	}
+
+	order, err := h.orderRepo.GetByID(ctx, attempt.OrderID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load order for STK attempt", "order_id", attempt.OrderID, "error", err)
+		return nil, attempt
+	}
+	return order, attempt
+}
+
+// callbackPayer is the phone and amount a callback was for, taken from the STK attempt when the
+// callback itself leaves them out. Split bill shares are told apart by these.
+func callbackPayer(result *core.PaymentWebhook, attempt *core.STKAttempt) (string, float64) {
+	phone, amount := result.Phone, result.Amount
+	if attempt != nil {
+		if phone == "" {
+			phone = attempt.Phone
+		}
+		if amount <= 0 {
+			amount = attempt.Amount
+		}
+	}
+	return phone, amount
+}
+
+// notifyPartialPayment tells the customer who placed a split bill order how much has come in
+func (h *Handler) notifyPartialPayment(ctx context.Context, order *core.Order, application *core.PaymentApplication) {
+	lang := h.customerLanguage(ctx, order)
+	message := i18n.Default().T(lang, "payment.partial", application.Share.Amount, application.AmountPaid, order.TotalAmount, order.AmountDue())
+	reporting.Go(core.DetachRequestID(ctx), "payment.notify_partial", func(ctx context.Context) error {
+		if err := h.whatsappGateway.SendText(ctx, order.CustomerPhone, message); err != nil {
+			return fmt.Errorf("failed to send partial payment notification: %w", err)
+		}
+		return nil
+	})
+
+	if h.eventBus != nil {
+		h.eventBus.PublishOrderPartiallyPaid(ctx, order.ID, application.AmountPaid, order.TotalAmount)
+	}
+}
+
+// failSplitShare marks a split bill share FAILED and offers the customer who placed the order a retry.
+// Returns false when the order isn't a split bill, so the caller fails the whole order as before.
+func (h *Handler) failSplitShare(ctx context.Context, order *core.Order, phone string, amount float64) bool {
+	if order.Status != core.OrderStatusPending && order.Status != core.OrderStatusPartiallyPaid {
+		return false
+	}
+
+	share, err := h.orderRepo.FailPaymentShare(ctx, order.ID, phone, amount)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			slog.ErrorContext(ctx, "Failed to mark payment share failed", "order_id", order.ID, "error", err)
+		}
+		return false
+	}
+
+	lang := h.customerLanguage(ctx, order)
+	message := i18n.Default().T(lang, "payment.share_failed", share.Phone, share.Amount)
+	buttons := []core.Button{
+		{
+			ID:    "retry_pay_" + order.ID,
+			Title: i18n.Default().T(lang, "button.retry_payment"),
+		},
+	}
+	reporting.Go(core.DetachRequestID(ctx), "payment.notify_share_failure", func(ctx context.Context) error {
+		if err := h.whatsappGateway.SendMenuButtons(ctx, order.CustomerPhone, message, buttons); err != nil {
+			return fmt.Errorf("failed to send split share failure notification: %w", err)
+		}
+		return nil
+	})
+	return true
+}
+
+// customerLanguage returns the ordering customer's bot language (English when unknown)
+func (h *Handler) customerLanguage(ctx context.Context, order *core.Order) string {
+	if h.languages == nil {
+		return i18n.DefaultLanguage
+	}
+	return h.languages.CustomerLanguage(ctx, order.UserID)
+}
+
+// recordPayment stores a confirmed payment in the ledger. Failures are logged and never fail the webhook.
+func (h *Handler) recordPayment(ctx context.Context, result *core.PaymentWebhook, order *core.Order) {
+	if h.paymentRepo == nil {
+		return
+	}
+
+	payment := &core.Payment{
+		ID:          uuid.New().String(),
+		Provider:    core.PaymentProviderKopoKopo,
+		Reference:   result.Reference,
+		Phone:       result.Phone,
+		HashedPhone: result.HashedPhone,
+		PayerName:   result.PayerName,
+		Amount:      result.Amount,
+		Currency:    result.Currency,
+		Status:      result.Status,
+		IsOrphan:    order == nil,
+		CreatedAt:   time.Now(),
+	}
+	if order != nil {
+		payment.OrderID = order.ID
+	}
+
+	if err := h.paymentRepo.Create(ctx, payment); err != nil {
+		slog.ErrorContext(ctx, "Failed to record payment in ledger",
+			"reference", result.Reference,
+			"amount", result.Amount,
+			"error", err)
+	}
+}
+
 // notifyBarStaff sends a WhatsApp notification to bar staff with order details.
 // CRITICAL: Only notifies when order is PAID (payment confirmed). Never notify for PENDING orders.
-func (h *Handler) notifyBarStaff(ctx context.Context, order *core.Order) {
+// An error means nobody was told, so the outbox retries it.
+func (h *Handler) notifyBarStaff(ctx context.Context, order *core.Order) error {
 	if order.Status != core.OrderStatusPaid && order.Status != core.OrderStatusCompleted {
 		log.Printf("[SAFETY] Skipping bar staff notification: order %s has status %s (only PAID/COMPLETED get delivery message)",
 			order.ID, order.Status)
-		return
+		return nil
+	}
+
+	// Prefer the on-shift roster; BAR_STAFF_PHONE remains the fallback inside the notifier.
+	if h.staffNotifier != nil {
+		if err := h.staffNotifier.NotifyPaidOrder(ctx, order); err != nil {
+			return fmt.Errorf("failed to notify bar staff roster: %w", err)
+		}
+		return nil
 	}
 
 	cfg := config.Get()
@@ -469,13 +941,19 @@ func (h *Handler) notifyBarStaff(ctx context.Context, order *core.Order) {
 
 	if barStaffPhone == "" {
 		log.Println("BAR_STAFF_PHONE not configured, skipping bar staff notification")
-		return
+		return nil
 	}
 
 	// Build message with order details
 	message := fmt.Sprintf("🚨 *New Order Paid!*\n\n")
-	message += fmt.Sprintf("*Order #%s*\n\n", order.PickupCode)
-	message += "*Items:*\n"
+	message += fmt.Sprintf("*Order #%s*\n", order.PickupCode)
+	if order.ScheduledFor != nil {
+		message += fmt.Sprintf("🕘 *Pre-order for %s*\n", service.FormatScheduledTime(*order.ScheduledFor))
+	}
+	if order.IsDelivery() {
+		message += fmt.Sprintf("🛵 *Delivery to:* %s\n", order.DeliveryAddress)
+	}
+	message += "\n*Items:*\n"
 
 	for _, item := range order.Items {
 		// Display actual product name (populated by repository JOIN)
@@ -483,7 +961,17 @@ func (h *Handler) notifyBarStaff(ctx context.Context, order *core.Order) {
 		if productName == "" {
 			productName = "Unknown Item"
 		}
+		if len(item.Modifiers) > 0 {
+			productName += " (" + core.FormatModifiers(item.Modifiers) + ")"
+		}
 		message += fmt.Sprintf("• %d x %s\n", item.Quantity, productName)
+		for _, component := range item.Components {
+			message += fmt.Sprintf("   ↳ %d x %s\n", component.Quantity*item.Quantity, component.ProductName)
+		}
+	}
+
+	if order.Notes != "" {
+		message += fmt.Sprintf("\n📝 *Notes:* %s\n", order.Notes)
 	}
 
 	message += fmt.Sprintf("\n*Total:* KES %.0f\n", order.TotalAmount)
@@ -504,16 +992,38 @@ func (h *Handler) notifyBarStaff(ctx context.Context, order *core.Order) {
 			log.Printf("Error sending bar staff notification with buttons: %v", err)
 			// Fallback to plain text if buttons fail
 			if err := h.whatsappGateway.SendText(ctx, barStaffPhone, message); err != nil {
-				log.Printf("Error sending bar staff notification (text fallback): %v", err)
+				return fmt.Errorf("failed to send bar staff notification (text fallback): %w", err)
 			}
 		}
 	} else {
 		// Fallback: send as plain text if SendMenuButtons not available
 		log.Printf("[DEBUG] Sending bar staff notification to %s as plain text (no button support)", barStaffPhone)
 		if err := h.whatsappGateway.SendText(ctx, barStaffPhone, message); err != nil {
-			log.Printf("Error sending bar staff notification: %v", err)
+			return fmt.Errorf("failed to send bar staff notification: %w", err)
 		}
 	}
+	return nil
+}
+
+// handleBarPayment handles the "Cash Received" and "Card Paid" buttons (barpaid_<method>_<orderID>)
+// sent to bar staff for pay-at-the-bar orders
+func (h *Handler) handleBarPayment(ctx context.Context, barStaffPhone string, payload string) {
+	method, orderID, ok := strings.Cut(payload, "_")
+	if !ok {
+		return
+	}
+
+	order, err := h.staffNotifier.ConfirmBarPayment(ctx, barStaffPhone, orderID, core.PaymentMethod(strings.ToUpper(method)))
+	if err != nil {
+		log.Printf("Error confirming bar payment for order %s: %v", orderID, err)
+		return
+	}
+	if order == nil {
+		return
+	}
+
+	h.wakeOutbox()
+	log.Printf("Order %s (pickup: %s) paid at the bar by %s", orderID, order.PickupCode, order.PaymentMethod)
 }
 
 // handleOrderCompletion handles the "Mark Done" button callback from bar staff
@@ -533,7 +1043,8 @@ func (h *Handler) handleOrderCompletion(ctx context.Context, barStaffPhone strin
 	}
 
 	// Update status to COMPLETED
-	if err := h.orderRepo.UpdateStatus(ctx, orderID, core.OrderStatusCompleted); err != nil {
+	note := fmt.Sprintf("marked done via WhatsApp by %s", barStaffPhone)
+	if err := h.orderRepo.UpdateStatusWithNote(ctx, orderID, core.OrderStatusCompleted, core.OrderActorWebhook, note); err != nil {
 		log.Printf("Error updating order status to COMPLETED: %v", err)
 		h.whatsappGateway.SendText(ctx, barStaffPhone, "❌ Failed to update order status")
 		return
@@ -545,7 +1056,7 @@ func (h *Handler) handleOrderCompletion(ctx context.Context, barStaffPhone strin
 
 	// Emit order_completed event for dashboard SSE
 	if h.eventBus != nil {
-		h.eventBus.PublishOrderCompleted(orderID)
+		h.eventBus.PublishOrderCompleted(ctx, orderID)
 	}
 
 	log.Printf("Order %s (pickup: %s) marked as COMPLETED by bar staff", orderID, order.PickupCode)
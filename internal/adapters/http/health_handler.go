@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthProbe checks one dependency for the readiness endpoint
+type HealthProbe struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Optional probes are reported but don't fail readiness, e.g. WhatsApp:
+	// restarting the API won't fix an expired token or a Meta outage
+	Optional bool
+}
+
+// healthStatus is one probe's result in the readiness response
+type healthStatus struct {
+	Status    string `json:"status"` // up or down
+	LatencyMS int64  `json:"latency_ms"`
+	Optional  bool   `json:"optional,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthHandler serves liveness and readiness probes
+type HealthHandler struct {
+	probes  []HealthProbe
+	timeout time.Duration
+}
+
+// NewHealthHandler creates a health handler; timeout bounds each probe
+func NewHealthHandler(timeout time.Duration, probes ...HealthProbe) *HealthHandler {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &HealthHandler{probes: probes, timeout: timeout}
+}
+
+// Live reports that the process is up without touching any dependency
+// GET /health/live (and legacy GET /health)
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status":  "ok",
+		"service": "destination-cocktails",
+	})
+}
+
+// Ready pings every dependency concurrently and returns 503 if a required one is down
+// GET /health/ready
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	results := make(map[string]healthStatus, len(h.probes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, probe := range h.probes {
+		wg.Add(1)
+		go func(probe HealthProbe) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+			defer cancel()
+
+			started := time.Now()
+			err := probe.Check(ctx)
+			result := healthStatus{
+				Status:    "up",
+				LatencyMS: time.Since(started).Milliseconds(),
+				Optional:  probe.Optional,
+			}
+			if err != nil {
+				result.Status = "down"
+				result.Error = truncateHealthError(err.Error())
+			}
+
+			mu.Lock()
+			results[probe.Name] = result
+			mu.Unlock()
+		}(probe)
+	}
+	wg.Wait()
+
+	status := "ok"
+	code := fiber.StatusOK
+	for _, result := range results {
+		if result.Status == "up" {
+			continue
+		}
+		if !result.Optional {
+			status = "unavailable"
+			code = fiber.StatusServiceUnavailable
+			break
+		}
+		status = "degraded"
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"status":       status,
+		"service":      "destination-cocktails",
+		"dependencies": results,
+	})
+}
+
+// truncateHealthError keeps upstream error bodies from bloating the probe response
+func truncateHealthError(msg string) string {
+	const maxLength = 200
+	if len(msg) > maxLength {
+		return msg[:maxLength] + "..."
+	}
+	return msg
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportInventoryValuation exports stock on hand valued at cost per product and category, with dead stock
+// (no sales in dead_stock_days, default 30), as PDF (default), CSV or XLSX
+// GET /api/admin/reports/inventory-valuation?dead_stock_days=30&format=pdf|csv|xlsx
+func (h *DashboardHandler) ExportInventoryValuation(c *fiber.Ctx) error {
+	format := strings.TrimSpace(c.Query("format", service.ReportFormatPDF))
+	deadStockDays := 0
+	if raw := strings.TrimSpace(c.Query("dead_stock_days")); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid dead_stock_days",
+			})
+		}
+		deadStockDays = days
+	}
+
+	data, filename, err := h.dashboardService.GenerateInventoryValuationReport(c.Context(), deadStockDays, format)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(strings.ToLower(err.Error()), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", service.ReportContentType(format))
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(data)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
+)
+
+// maxQueuedPerPhone bounds one phone's backlog of unprocessed messages; more are dropped and logged
+const maxQueuedPerPhone = 20
+
+// phoneQueues runs each phone's messages one at a time, in the order their webhooks arrived, so a
+// customer's rapid messages and double-tapped buttons don't race on the same session. Different
+// phones still run in parallel. Ordering holds per process; replicas each order what they receive.
+type phoneQueues struct {
+	mu      sync.Mutex
+	pending map[string][]func() // A phone has an entry while its worker is running
+}
+
+// Go queues fn behind the phone's earlier messages. A returned error or a panic is logged and
+// reported under operation, like reporting.Go.
+func (q *phoneQueues) Go(ctx context.Context, phone string, operation string, fn func(ctx context.Context) error) {
+	task := func() {
+		defer reporting.Recover(ctx, operation)
+		reporting.CaptureError(ctx, operation, fn(ctx))
+	}
+
+	q.mu.Lock()
+	if q.pending == nil {
+		q.pending = make(map[string][]func())
+	}
+	queued, running := q.pending[phone]
+	if len(queued) >= maxQueuedPerPhone {
+		q.mu.Unlock()
+		log.Printf("WARNING: dropped %s for %s: %d messages already queued", operation, phone, len(queued))
+		return
+	}
+	q.pending[phone] = append(queued, task)
+	q.mu.Unlock()
+
+	if !running {
+		go q.drain(phone)
+	}
+}
+
+// drain runs a phone's queued messages until none are left
+func (q *phoneQueues) drain(phone string) {
+	for {
+		q.mu.Lock()
+		queued := q.pending[phone]
+		if len(queued) == 0 {
+			delete(q.pending, phone)
+			q.mu.Unlock()
+			return
+		}
+		task := queued[0]
+		q.pending[phone] = queued[1:]
+		q.mu.Unlock()
+
+		task()
+	}
+}
+
+// SetProcessedMessageStore turns on deduplication of redelivered WhatsApp messages by message ID;
+// an ID seen within ttl is skipped
+func (h *Handler) SetProcessedMessageStore(store core.ProcessedMessageStore, ttl time.Duration) {
+	h.processedMessages = store
+	h.dedupTTL = ttl
+}
+
+// alreadyProcessed reports whether a WhatsApp message ID was handled before. Without a store, without
+// an ID or when Redis fails, the message is processed: a duplicate beats a lost order.
+func (h *Handler) alreadyProcessed(ctx context.Context, messageID string) bool {
+	if h.processedMessages == nil || messageID == "" {
+		return false
+	}
+	first, err := h.processedMessages.MarkProcessed(ctx, messageID, h.dedupTTL)
+	if err != nil {
+		log.Printf("Failed to check WhatsApp message %s for duplicates, processing it: %v", messageID, err)
+		return false
+	}
+	if !first {
+		log.Printf("Skipping redelivered WhatsApp message %s", messageID)
+	}
+	return !first
+}
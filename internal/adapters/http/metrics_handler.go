@@ -0,0 +1,193 @@
+package http
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheStatsProvider reports an in-memory cache's counters since startup
+type CacheStatsProvider interface {
+	CacheStats() (hits uint64, misses uint64, invalidations uint64)
+}
+
+// SessionLockStatsProvider reports the per-phone session lock counters since startup
+type SessionLockStatsProvider interface {
+	LockStats() (acquired uint64, contended uint64, timeouts uint64, failures uint64, waited time.Duration)
+}
+
+// EventSubscriberStatsProvider reports the dashboard event bus subscriber count and counters since startup
+type EventSubscriberStatsProvider interface {
+	SubscriberStats() (subscribers int, delivered uint64, dropped uint64, resyncs uint64, evicted uint64)
+}
+
+// MetricsHandler serves process metrics in the Prometheus text format
+type MetricsHandler struct {
+	poolStats func() map[string]sql.DBStats
+	caches    map[string]CacheStatsProvider
+	locks     SessionLockStatsProvider
+	events    EventSubscriberStatsProvider
+}
+
+// NewMetricsHandler creates a metrics handler; poolStats returns database pool statistics keyed by pool name
+func NewMetricsHandler(poolStats func() map[string]sql.DBStats) *MetricsHandler {
+	return &MetricsHandler{
+		poolStats: poolStats,
+		caches:    make(map[string]CacheStatsProvider),
+	}
+}
+
+// AddCache reports a cache's hits, misses and invalidations labelled cache="name"
+func (h *MetricsHandler) AddCache(name string, cache CacheStatsProvider) {
+	h.caches[name] = cache
+}
+
+// SetSessionLocks reports session lock contention
+func (h *MetricsHandler) SetSessionLocks(locks SessionLockStatsProvider) {
+	h.locks = locks
+}
+
+// SetEventBus reports event bus subscribers and the events they missed
+func (h *MetricsHandler) SetEventBus(events EventSubscriberStatsProvider) {
+	h.events = events
+}
+
+// dbPoolMetric is one database/sql pool statistic exported as a gauge or counter
+type dbPoolMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(sql.DBStats) float64
+}
+
+var dbPoolMetrics = []dbPoolMetric{
+	{"db_pool_max_open_connections", "gauge", "Maximum number of open connections to the database",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }},
+	{"db_pool_open_connections", "gauge", "Established connections, in use and idle",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) }},
+	{"db_pool_in_use_connections", "gauge", "Connections currently in use",
+		func(s sql.DBStats) float64 { return float64(s.InUse) }},
+	{"db_pool_idle_connections", "gauge", "Idle connections",
+		func(s sql.DBStats) float64 { return float64(s.Idle) }},
+	{"db_pool_wait_count_total", "counter", "Connections waited for because the pool was exhausted",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) }},
+	{"db_pool_wait_duration_seconds_total", "counter", "Time spent waiting for a connection",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }},
+	{"db_pool_max_idle_closed_total", "counter", "Connections closed because of the idle connection limit",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }},
+	{"db_pool_max_idle_time_closed_total", "counter", "Connections closed because of the idle time limit",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }},
+	{"db_pool_max_lifetime_closed_total", "counter", "Connections closed because of the connection lifetime limit",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }},
+}
+
+// Metrics reports database connection pool statistics for the primary and, when configured, the read
+// replica, the counters of every registered cache, session lock contention and event subscribers
+// GET /metrics
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	stats := h.poolStats()
+	pools := make([]string, 0, len(stats))
+	for pool := range stats {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+
+	var b strings.Builder
+	for _, metric := range dbPoolMetrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, pool := range pools {
+			fmt.Fprintf(&b, "%s{pool=%q} %g\n", metric.name, pool, metric.value(stats[pool]))
+		}
+	}
+
+	h.writeCacheMetrics(&b)
+	h.writeSessionLockMetrics(&b)
+	h.writeEventSubscriberMetrics(&b)
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return c.SendString(b.String())
+}
+
+// writeCacheMetrics writes the hit, miss and invalidation counters of every registered cache
+func (h *MetricsHandler) writeCacheMetrics(b *strings.Builder) {
+	if len(h.caches) == 0 {
+		return
+	}
+	names := make([]string, 0, len(h.caches))
+	for name := range h.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type cacheCounts struct{ hits, misses, invalidations uint64 }
+	counts := make([]cacheCounts, len(names))
+	for i, name := range names {
+		counts[i].hits, counts[i].misses, counts[i].invalidations = h.caches[name].CacheStats()
+	}
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(cacheCounts) uint64
+	}{
+		{"cache_hits_total", "Reads served from the cache", func(c cacheCounts) uint64 { return c.hits }},
+		{"cache_misses_total", "Reads that loaded from the database", func(c cacheCounts) uint64 { return c.misses }},
+		{"cache_invalidations_total", "Times the cache was dropped after a change", func(c cacheCounts) uint64 { return c.invalidations }},
+	}
+	for _, metric := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", metric.name, metric.help, metric.name)
+		for i, name := range names {
+			fmt.Fprintf(b, "%s{cache=%q} %d\n", metric.name, name, metric.value(counts[i]))
+		}
+	}
+}
+
+// writeSessionLockMetrics writes the session lock counters when locking is configured
+func (h *MetricsHandler) writeSessionLockMetrics(b *strings.Builder) {
+	if h.locks == nil {
+		return
+	}
+	acquired, contended, timeouts, failures, waited := h.locks.LockStats()
+
+	metrics := []struct {
+		name  string
+		help  string
+		value float64
+	}{
+		{"session_lock_acquired_total", "Messages processed holding their phone's session lock", float64(acquired)},
+		{"session_lock_contended_total", "Messages that had to wait for another message from the same phone", float64(contended)},
+		{"session_lock_timeouts_total", "Messages processed without the lock after waiting too long", float64(timeouts)},
+		{"session_lock_failures_total", "Messages processed without the lock because Redis failed", float64(failures)},
+		{"session_lock_wait_seconds_total", "Time messages spent waiting for session locks", waited.Seconds()},
+	}
+	for _, metric := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n%s %g\n", metric.name, metric.help, metric.name, metric.name, metric.value)
+	}
+}
+
+// writeEventSubscriberMetrics writes the event bus subscriber gauge and counters when the bus is registered
+func (h *MetricsHandler) writeEventSubscriberMetrics(b *strings.Builder) {
+	if h.events == nil {
+		return
+	}
+	subscribers, delivered, dropped, resyncs, evicted := h.events.SubscriberStats()
+	fmt.Fprintf(b, "# HELP event_subscribers Connected SSE/WebSocket clients and internal event listeners\n# TYPE event_subscribers gauge\nevent_subscribers %d\n", subscribers)
+
+	metrics := []struct {
+		name  string
+		help  string
+		value uint64
+	}{
+		{"event_deliveries_total", "Events handed to subscribers", delivered},
+		{"event_drops_total", "Events a subscriber missed because its buffer was full", dropped},
+		{"event_resyncs_total", "resync_required events sent to subscribers that missed events", resyncs},
+		{"event_subscriber_evictions_total", "Subscribers disconnected for not reading events", evicted},
+	}
+	for _, metric := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", metric.name, metric.help, metric.name, metric.name, metric.value)
+	}
+}
@@ -0,0 +1,951 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/payment"
+	"github.com/dumu-tech/destination-cocktails/internal/adapters/whatsapp"
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiOperation documents one route in the OpenAPI spec. Request and Response are zero values of the
+// types the handler parses and returns; their schemas are generated from the structs.
+type apiOperation struct {
+	Tag         string
+	Summary     string
+	Roles       []string // Empty for routes that don't need a dashboard session
+	Query       []apiParam
+	Headers     []apiParam
+	Request     interface{}
+	RequestType string // Content type of Request (default application/json)
+	Status      int    // Success status (default 200)
+	Response    interface{}
+	Produces    string // Content type of a non-JSON success response (PDF, CSV, SSE)
+}
+
+// apiParam is a query or header parameter
+type apiParam struct {
+	Name        string
+	Type        string // OpenAPI type (default string)
+	Description string
+	Required    bool
+}
+
+// oneOf documents a body that may take any of several shapes
+type oneOf []interface{}
+
+// Response shapes handlers build with fiber.Map
+
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type loginResponseBody struct {
+	Message      string `json:"message"`
+	Token        string `json:"token"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds until the access token expires
+	RefreshToken string `json:"refresh_token"`
+	Role         string `json:"role"`
+}
+
+type orderStatusHistoryResponse struct {
+	OrderID string                    `json:"order_id"`
+	History []*core.OrderStatusChange `json:"history"`
+}
+
+type orderPaymentAttemptsResponse struct {
+	OrderID  string             `json:"order_id"`
+	Attempts []*core.STKAttempt `json:"attempts"`
+}
+
+type webhookStatsResponse struct {
+	SignatureVerification    bool       `json:"signature_verification"`
+	RejectedMissingSignature int64      `json:"rejected_missing_signature"`
+	RejectedInvalidSignature int64      `json:"rejected_invalid_signature"`
+	LastRejectedAt           *time.Time `json:"last_rejected_at"`
+}
+
+type webhookAckResponse struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+var (
+	managerOnly     = []string{"MANAGER"}
+	managerAndStaff = []string{"MANAGER", "BARTENDER"}
+
+	dateRangeParams = []apiParam{
+		{Name: "from", Description: "Start date, YYYY-MM-DD"},
+		{Name: "to", Description: "End date, YYYY-MM-DD"},
+	}
+	limitParam = apiParam{Name: "limit", Type: "integer", Description: "Maximum rows to return"}
+	csvBody    = "text/csv"
+	reportFile = "application/pdf, text/csv, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// apiOperations documents every /api route, keyed by "METHOD path" as registered with Fiber
+var apiOperations = map[string]apiOperation{
+	// Webhooks
+	"GET /api/webhooks/whatsapp": {
+		Tag: "Webhooks", Summary: "Meta webhook verification handshake; echoes hub.challenge",
+		Query: []apiParam{
+			{Name: "hub.mode", Required: true},
+			{Name: "hub.verify_token", Required: true},
+			{Name: "hub.challenge", Required: true},
+		},
+		Produces: "text/plain",
+	},
+	"POST /api/webhooks/whatsapp": {
+		Tag: "Webhooks", Summary: "Incoming WhatsApp messages (signed with X-Hub-Signature-256)",
+		Headers:  []apiParam{{Name: "X-Hub-Signature-256", Description: "sha256=<HMAC of the body with WHATSAPP_APP_SECRET>"}},
+		Request:  whatsapp.WebhookPayload{},
+		Response: webhookAckResponse{},
+	},
+	"POST /api/webhooks/payment": {
+		Tag: "Webhooks", Summary: "Kopo Kopo STK push results and till (buygoods) payments",
+		Headers:  []apiParam{{Name: "X-KopoKopo-Signature", Required: true}},
+		Request:  oneOf{payment.IncomingPaymentWebhook{}, payment.PaymentWebhookPayload{}},
+		Response: webhookAckResponse{},
+	},
+
+	// Auth
+	"POST /api/admin/auth/request-otp": {
+		Tag: "Auth", Summary: "Send a login code to a manager's WhatsApp",
+		Request: requestOTPRequest{}, Response: messageResponse{},
+	},
+	"POST /api/admin/auth/resend-otp": {
+		Tag: "Auth", Summary: "Send a new login code; 429 with retry_after inside OTP_RESEND_COOLDOWN",
+		Request: requestOTPRequest{}, Response: messageResponse{},
+	},
+	"POST /api/admin/auth/verify-otp": {
+		Tag: "Auth", Summary: "Log in with a WhatsApp code; sets the auth cookies",
+		Request: verifyOTPRequest{}, Response: loginResponseBody{},
+	},
+	"POST /api/admin/auth/bartender-login": {
+		Tag: "Auth", Summary: "Log in with a 4-digit PIN; sets the auth cookies",
+		Request: bartenderLoginRequest{}, Response: loginResponseBody{},
+	},
+	"POST /api/admin/auth/verify-pin": {
+		Tag: "Auth", Summary: "Alias of bartender-login used by the PIN pad",
+		Request: bartenderLoginRequest{}, Response: loginResponseBody{},
+	},
+	"POST /api/admin/auth/refresh": {
+		Tag: "Auth", Summary: "Exchange the refresh token (cookie or body) for a new session",
+		Request: refreshSessionRequest{}, Response: loginResponseBody{},
+	},
+	"POST /api/admin/auth/logout": {
+		Tag: "Auth", Summary: "Revoke the refresh token and clear the auth cookies",
+		Request: refreshSessionRequest{}, Response: messageResponse{},
+	},
+	"GET /api/admin/auth/me": {
+		Tag: "Auth", Summary: "The logged-in dashboard user",
+		Roles: managerAndStaff, Response: core.AdminUser{},
+	},
+
+	// Products
+	"GET /api/admin/products": {
+		Tag: "Products", Summary: "List products",
+		Roles:    managerOnly,
+		Query:    []apiParam{{Name: "archived", Type: "boolean", Description: "List archived products instead"}},
+		Response: []core.Product{},
+	},
+	"GET /api/admin/products/export": {
+		Tag: "Products", Summary: "Download the menu as CSV",
+		Roles: managerOnly, Produces: csvBody,
+	},
+	"POST /api/admin/products/import": {
+		Tag: "Products", Summary: "Upsert products by name from a CSV (name, price, category, stock, description)",
+		Roles:       managerOnly,
+		Query:       []apiParam{{Name: "dry_run", Type: "boolean", Description: "Validate without writing"}},
+		RequestType: csvBody,
+		Response:    service.ProductImportResult{},
+	},
+	"PATCH /api/admin/products/:id/stock": {
+		Tag: "Products", Summary: "Set a product's stock to a counted number (recorded as a correction)",
+		Roles: managerOnly, Request: updateStockRequest{}, Response: messageResponse{},
+	},
+	"POST /api/admin/products/:id/stock-adjustments": {
+		Tag: "Products", Summary: "Add or remove stock with a reason (sale_correction, breakage, restock, theft); returns the new balance",
+		Roles: managerOnly, Request: adjustStockRequest{}, Response: core.StockAdjustment{},
+	},
+	"PATCH /api/admin/products/:id/price": {
+		Tag: "Products", Summary: "Set a product's price",
+		Roles: managerOnly, Request: updatePriceRequest{}, Response: messageResponse{},
+	},
+	"GET /api/admin/products/:id/price-history": {
+		Tag: "Products", Summary: "Price changes with who made them, newest first",
+		Roles: managerOnly, Query: []apiParam{limitParam}, Response: []core.PriceChange{},
+	},
+	"PATCH /api/admin/products/:id/bottle-size": {
+		Tag: "Products", Summary: "Set the ml in one stock unit of a recipe ingredient",
+		Roles: managerOnly, Request: updateBottleSizeRequest{}, Response: messageResponse{},
+	},
+	"PATCH /api/admin/products/:id/cost-price": {
+		Tag: "Products", Summary: "Set what one stock unit costs the bar (used for orders placed from now on)",
+		Roles: managerOnly, Request: updateCostPriceRequest{}, Response: messageResponse{},
+	},
+	"PATCH /api/admin/products/:id/archive": {
+		Tag: "Products", Summary: "Take a product off the menu, keeping it for order history",
+		Roles: managerOnly, Response: core.Product{},
+	},
+	"PATCH /api/admin/products/:id/unarchive": {
+		Tag: "Products", Summary: "Put an archived product back on the menu",
+		Roles: managerOnly, Response: core.Product{},
+	},
+	"PATCH /api/admin/products/:id/sold-out-visibility": {
+		Tag: "Products", Summary: "Hide or show a product on the bot's menu while it's sold out (null follows menu.hide_out_of_stock)",
+		Roles: managerOnly, Request: updateSoldOutVisibilityRequest{}, Response: core.Product{},
+	},
+	"GET /api/admin/products/:id/options": {
+		Tag: "Products", Summary: "List a product's serving options",
+		Roles: managerOnly, Response: []core.ProductOption{},
+	},
+	"POST /api/admin/products/:id/options": {
+		Tag: "Products", Summary: "Add a serving option",
+		Roles: managerOnly, Request: createProductOptionRequest{}, Status: fiber.StatusCreated, Response: core.ProductOption{},
+	},
+	"PATCH /api/admin/products/:id/options/:optionId": {
+		Tag: "Products", Summary: "Update a serving option",
+		Roles: managerOnly, Request: updateProductOptionRequest{}, Response: core.ProductOption{},
+	},
+	"DELETE /api/admin/products/:id/options/:optionId": {
+		Tag: "Products", Summary: "Delete a serving option",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/bundles": {
+		Tag: "Products", Summary: "List combos with live availability",
+		Roles: managerOnly, Response: []core.Bundle{},
+	},
+	"POST /api/admin/bundles": {
+		Tag: "Products", Summary: "Create a combo",
+		Roles: managerOnly, Request: createBundleRequest{}, Status: fiber.StatusCreated, Response: core.Bundle{},
+	},
+	"PUT /api/admin/bundles/:id/components": {
+		Tag: "Products", Summary: "Replace a combo's components",
+		Roles: managerOnly, Request: setBundleComponentsRequest{}, Response: core.Bundle{},
+	},
+	"GET /api/admin/recipes": {
+		Tag: "Products", Summary: "List cocktail recipes with how many more can be made from bottle stock",
+		Roles: managerOnly, Response: []core.Recipe{},
+	},
+	"PUT /api/admin/recipes/:id": {
+		Tag: "Products", Summary: "Replace a cocktail's recipe (an empty list removes it)",
+		Roles: managerOnly, Request: setRecipeRequest{}, Response: core.Recipe{},
+	},
+	"GET /api/admin/purchase-limits": {
+		Tag: "Products", Summary: "Per-order quantity caps on categories and products",
+		Roles: managerOnly, Response: []core.PurchaseLimit{},
+	},
+	"PUT /api/admin/purchase-limits": {
+		Tag: "Products", Summary: "Cap a category or product per order; the same target again changes its cap",
+		Roles: managerOnly, Request: setPurchaseLimitRequest{}, Response: core.PurchaseLimit{},
+	},
+	"DELETE /api/admin/purchase-limits/:id": {
+		Tag: "Products", Summary: "Remove a purchase limit",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"POST /api/admin/stocktakes": {
+		Tag: "Products", Summary: "Start a stocktake (only one can be open)",
+		Roles: managerOnly, Request: startStocktakeRequest{}, Status: fiber.StatusCreated, Response: core.Stocktake{},
+	},
+	"GET /api/admin/stocktakes": {
+		Tag: "Products", Summary: "Recent stocktakes, newest first",
+		Roles: managerOnly, Query: []apiParam{limitParam}, Response: []core.Stocktake{},
+	},
+	"GET /api/admin/stocktakes/:id": {
+		Tag: "Products", Summary: "Stocktake with the lines counted so far",
+		Roles: managerAndStaff, Response: core.Stocktake{},
+	},
+	"PUT /api/admin/stocktakes/:id/lines": {
+		Tag: "Products", Summary: "Submit counted quantities; variance is against stock when counted",
+		Roles: managerAndStaff, Request: recordStocktakeCountsRequest{}, Response: []core.StocktakeLine{},
+	},
+	"POST /api/admin/stocktakes/:id/apply": {
+		Tag: "Products", Summary: "Adjust stock by the counted variances (audited) and close the stocktake",
+		Roles: managerOnly, Response: []core.StockAdjustment{},
+	},
+	"POST /api/admin/stocktakes/:id/cancel": {
+		Tag: "Products", Summary: "Abandon an open stocktake without changing stock",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/stocktakes/:id/variance": {
+		Tag: "Products", Summary: "Shrinkage and overage by product, valued at menu price",
+		Roles: managerOnly, Response: core.StocktakeVariance{},
+	},
+	"GET /api/admin/suppliers": {
+		Tag: "Products", Summary: "List suppliers, active ones first",
+		Roles: managerOnly, Response: []core.Supplier{},
+	},
+	"POST /api/admin/suppliers": {
+		Tag: "Products", Summary: "Add a supplier",
+		Roles: managerOnly, Request: supplierRequest{}, Status: fiber.StatusCreated, Response: core.Supplier{},
+	},
+	"PATCH /api/admin/suppliers/:id": {
+		Tag: "Products", Summary: "Update a supplier's contact details or active flag",
+		Roles: managerOnly, Request: updateSupplierRequest{}, Response: core.Supplier{},
+	},
+	"DELETE /api/admin/suppliers/:id": {
+		Tag: "Products", Summary: "Deactivate a supplier (its purchase orders are kept)",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/purchase-orders": {
+		Tag: "Products", Summary: "Recent purchase orders, newest first",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "status", Description: "ORDERED, RECEIVED or CANCELLED (default all)"},
+			limitParam,
+		},
+		Response: []core.PurchaseOrder{},
+	},
+	"POST /api/admin/purchase-orders": {
+		Tag: "Products", Summary: "Record stock ordered from a supplier",
+		Roles: managerOnly, Request: createPurchaseOrderRequest{}, Status: fiber.StatusCreated, Response: core.PurchaseOrder{},
+	},
+	"GET /api/admin/purchase-orders/:id": {
+		Tag: "Products", Summary: "Purchase order with its items",
+		Roles: managerOnly, Response: core.PurchaseOrder{},
+	},
+	"POST /api/admin/purchase-orders/:id/receive": {
+		Tag: "Products", Summary: "Receive a delivery into stock (audited) and update cost prices; unlisted items arrived as ordered",
+		Roles: managerOnly, Request: receivePurchaseOrderRequest{}, Response: core.PurchaseOrder{},
+	},
+	"POST /api/admin/purchase-orders/:id/cancel": {
+		Tag: "Products", Summary: "Cancel a purchase order that hasn't been received",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+
+	// Analytics and reports
+	"GET /api/admin/analytics/overview": {
+		Tag: "Analytics", Summary: "Revenue, order and payment totals",
+		Roles: managerOnly, Query: dateRangeParams, Response: core.Analytics{},
+	},
+	"GET /api/admin/analytics/revenue": {
+		Tag: "Analytics", Summary: "Daily revenue trend",
+		Roles:    managerOnly,
+		Query:    append([]apiParam{{Name: "days", Type: "integer", Description: "Trailing days (default 30) when no date range is given"}}, dateRangeParams...),
+		Response: []core.RevenueTrend{},
+	},
+	"GET /api/admin/analytics/top-products": {
+		Tag: "Analytics", Summary: "Best-selling products",
+		Roles: managerOnly, Query: append([]apiParam{limitParam}, dateRangeParams...), Response: []core.TopProduct{},
+	},
+	"GET /api/admin/analytics/margins": {
+		Tag: "Analytics", Summary: "Gross profit by product and category, flagging negative margins (default last 30 days)",
+		Roles: managerOnly, Query: dateRangeParams, Response: core.MarginReport{},
+	},
+	"GET /api/admin/analytics/prep-times": {
+		Tag: "Analytics", Summary: "Paid→ready and ready→completed times (average, p50, p90) overall, per day and per bartender (default last 30 days)",
+		Roles: managerOnly, Query: dateRangeParams, Response: core.PrepTimeReport{},
+	},
+	"GET /api/admin/analytics/staff": {
+		Tag: "Analytics", Summary: "Per dashboard user: orders marked ready and completed, average paid→ready time and tips (default last 30 days)",
+		Roles: managerOnly, Query: dateRangeParams, Response: core.StaffReport{},
+	},
+	"GET /api/admin/feedback": {
+		Tag: "Analytics", Summary: "Customer ratings after completed orders: average, response rate, daily trend and latest low ratings (default last 30 days)",
+		Roles: managerOnly, Query: append([]apiParam{limitParam}, dateRangeParams...), Response: core.FeedbackReport{},
+	},
+	"GET /api/admin/reports/daily": {
+		Tag: "Reports", Summary: "Daily sales report",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "date", Description: "Business date, YYYY-MM-DD (default today)"},
+			{Name: "format", Description: "pdf (default), csv or xlsx"},
+		},
+		Produces: reportFile,
+	},
+	"GET /api/admin/reports/last-30-days": {
+		Tag: "Reports", Summary: "Sales report for the last 30 days",
+		Roles: managerOnly, Query: []apiParam{{Name: "format", Description: "pdf (default), csv or xlsx"}}, Produces: reportFile,
+	},
+	"GET /api/admin/reports/weekly": {
+		Tag: "Reports", Summary: "Weekly sales report with week-over-week changes and top movers",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "date", Description: "Any business date in the Monday-to-Sunday week, YYYY-MM-DD (default last week)"},
+			{Name: "format", Description: "pdf (default), csv or xlsx"},
+		},
+		Produces: reportFile,
+	},
+	"GET /api/admin/reports/monthly": {
+		Tag: "Reports", Summary: "Monthly sales report with month-over-month changes and top movers",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "month", Description: "YYYY-MM (default last month)"},
+			{Name: "format", Description: "pdf (default), csv or xlsx"},
+		},
+		Produces: reportFile,
+	},
+	"GET /api/admin/reports/inventory-valuation": {
+		Tag: "Reports", Summary: "Stock on hand valued at cost per product and category, with dead stock for the monthly review",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "dead_stock_days", Type: "integer", Description: "Days without a sale before a product counts as dead stock (default 30, max 365)"},
+			{Name: "format", Description: "pdf (default), csv or xlsx"},
+		},
+		Produces: reportFile,
+	},
+	"GET /api/admin/reports/accounting-export": {
+		Tag: "Reports", Summary: "Journal-style CSV of settled orders and refunds (date, reference, gross, tax, payment method, fees) for QuickBooks or Xero",
+		Roles: managerOnly, Query: dateRangeParams, Produces: csvBody,
+	},
+	"POST /api/admin/reconciliation/import": {
+		Tag: "Reports", Summary: "Match an M-Pesa till statement CSV against the payments ledger: matched, missing in system, missing in statement",
+		Roles:       managerOnly,
+		RequestType: csvBody,
+		Response:    service.ReconciliationReport{},
+	},
+	"POST /api/admin/integrations/google-sheets/sync": {
+		Tag: "Reports", Summary: "Append a closed business day's summary row to the Google Sheet, even if it was synced before",
+		Roles:    managerOnly,
+		Query:    []apiParam{{Name: "date", Description: "Business date, YYYY-MM-DD (default the last closed business day)"}},
+		Response: core.DailySalesSummary{},
+	},
+	"GET /api/admin/analytics/reports/daily": {
+		Tag: "Reports", Summary: "Legacy path of /api/admin/reports/daily",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "date", Description: "Business date, YYYY-MM-DD (default today)"},
+			{Name: "format", Description: "pdf (default), csv or xlsx"},
+		},
+		Produces: reportFile,
+	},
+	"GET /api/admin/analytics/reports/last-30-days": {
+		Tag: "Reports", Summary: "Legacy path of /api/admin/reports/last-30-days",
+		Roles: managerOnly, Query: []apiParam{{Name: "format", Description: "pdf (default), csv or xlsx"}}, Produces: reportFile,
+	},
+
+	// Staff and users
+	"GET /api/admin/staff": {
+		Tag: "Staff", Summary: "List the bar staff roster",
+		Roles: managerOnly, Response: []core.BarStaff{},
+	},
+	"POST /api/admin/staff": {
+		Tag: "Staff", Summary: "Add a bartender to the roster",
+		Roles: managerOnly, Request: createBarStaffRequest{}, Status: fiber.StatusCreated, Response: core.BarStaff{},
+	},
+	"PATCH /api/admin/staff/:id": {
+		Tag: "Staff", Summary: "Update a bartender (name, phone, shift)",
+		Roles: managerOnly, Request: updateBarStaffRequest{}, Response: core.BarStaff{},
+	},
+	"DELETE /api/admin/staff/:id": {
+		Tag: "Staff", Summary: "Deactivate a bartender",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/shifts": {
+		Tag: "Staff", Summary: "List bartender shifts, newest first",
+		Roles: managerOnly, Response: []core.Shift{},
+		Query: []apiParam{
+			{Name: "staff_id", Description: "Only this bartender's shifts"},
+			{Name: "from", Description: "First start date (YYYY-MM-DD)"},
+			{Name: "to", Description: "Last start date (YYYY-MM-DD)"},
+			limitParam,
+		},
+	},
+	"POST /api/admin/shifts/clock-in": {
+		Tag: "Staff", Summary: "Clock a bartender in and put them on shift",
+		Roles: managerAndStaff, Request: shiftClockRequest{}, Status: fiber.StatusCreated, Response: core.Shift{},
+	},
+	"POST /api/admin/shifts/clock-out": {
+		Tag: "Staff", Summary: "Clock a bartender out and send managers the shift summary",
+		Roles: managerAndStaff, Request: shiftClockRequest{}, Response: core.ShiftReport{},
+	},
+	"GET /api/admin/shifts/:id/report": {
+		Tag: "Staff", Summary: "Shift orders, revenue, collections by payment method and tips",
+		Roles: managerOnly, Response: core.ShiftReport{},
+	},
+	"GET /api/admin/riders": {
+		Tag: "Staff", Summary: "List the delivery riders roster",
+		Roles: managerOnly, Response: []core.Rider{},
+	},
+	"POST /api/admin/riders": {
+		Tag: "Staff", Summary: "Add a delivery rider to the roster",
+		Roles: managerOnly, Request: createRiderRequest{}, Status: fiber.StatusCreated, Response: core.Rider{},
+	},
+	"PATCH /api/admin/riders/:id": {
+		Tag: "Staff", Summary: "Update a rider (name, phone, availability)",
+		Roles: managerOnly, Request: updateRiderRequest{}, Response: core.Rider{},
+	},
+	"DELETE /api/admin/riders/:id": {
+		Tag: "Staff", Summary: "Deactivate a rider",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/users": {
+		Tag: "Staff", Summary: "List dashboard users",
+		Roles: managerOnly, Response: []core.AdminUser{},
+	},
+	"POST /api/admin/users": {
+		Tag: "Staff", Summary: "Create a dashboard user",
+		Roles: managerOnly, Request: createAdminUserRequest{}, Status: fiber.StatusCreated, Response: core.AdminUser{},
+	},
+	"PATCH /api/admin/users/:id": {
+		Tag: "Staff", Summary: "Update a dashboard user",
+		Roles: managerOnly, Request: updateAdminUserRequest{}, Response: core.AdminUser{},
+	},
+	"DELETE /api/admin/users/:id": {
+		Tag: "Staff", Summary: "Deactivate a dashboard user",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"PUT /api/admin/users/:id/pin": {
+		Tag: "Staff", Summary: "Set or clear a user's login PIN",
+		Roles: managerOnly, Request: setAdminUserPINRequest{}, Response: messageResponse{},
+	},
+	"GET /api/admin/audit-logs": {
+		Tag: "Staff", Summary: "Who changed what: every mutating admin request, newest first",
+		Roles: managerOnly,
+		Query: append([]apiParam{
+			{Name: "actor", Description: "Admin user ID"},
+			{Name: "entity_type", Description: "First route segment, e.g. products, orders, users"},
+			{Name: "entity_id", Description: "The route's :id"},
+			limitParam,
+		}, dateRangeParams...),
+		Response: []core.AuditLog{},
+	},
+
+	// Tabs
+	"GET /api/admin/tabs": {
+		Tag: "Tabs", Summary: "Open group tabs by table with members, items and unpaid total",
+		Roles: managerAndStaff, Response: []service.TabSummary{},
+	},
+	"POST /api/admin/tabs/:id/close": {
+		Tag: "Tabs", Summary: "Close a group tab once the table has left",
+		Roles: managerAndStaff, Response: messageResponse{},
+	},
+
+	// Reservations
+	"GET /api/admin/reservations": {
+		Tag: "Reservations", Summary: "Table and VIP booth reservations, earliest first",
+		Roles: managerAndStaff,
+		Query: []apiParam{
+			{Name: "from", Description: "First day, YYYY-MM-DD (Africa/Nairobi)"},
+			{Name: "to", Description: "Last day, YYYY-MM-DD (Africa/Nairobi)"},
+			{Name: "status", Description: "PENDING_DEPOSIT, CONFIRMED, SEATED, NO_SHOW or CANCELLED (default all)"},
+			limitParam,
+		},
+		Response: []core.Reservation{},
+	},
+	"GET /api/admin/reservations/calendar": {
+		Tag: "Reservations", Summary: "Reservations grouped by day with expected guests",
+		Roles: managerAndStaff,
+		Query: []apiParam{
+			{Name: "from", Description: "First day, YYYY-MM-DD (default today)"},
+			{Name: "to", Description: "Last day, YYYY-MM-DD (default six days after from; at most 62 days)"},
+		},
+		Response: []service.ReservationDay{},
+	},
+	"PATCH /api/admin/reservations/:id/status": {
+		Tag: "Reservations", Summary: "Mark a reservation confirmed, seated, a no-show or cancelled; cancelling tells the customer",
+		Roles: managerAndStaff, Request: updateReservationStatusRequest{}, Response: core.Reservation{},
+	},
+
+	// Customers
+	"GET /api/admin/customers/blocked": {
+		Tag: "Customers", Summary: "Blocked phones and phones flagged after repeated failed payments",
+		Roles: managerOnly, Response: []core.BlockedCustomer{},
+	},
+	"POST /api/admin/customers/blocked": {
+		Tag: "Customers", Summary: "Block a phone from ordering; a flagged phone becomes blocked",
+		Roles: managerOnly, Request: blockCustomerRequest{}, Status: fiber.StatusCreated, Response: core.BlockedCustomer{},
+	},
+	"DELETE /api/admin/customers/blocked/:phone": {
+		Tag: "Customers", Summary: "Unblock or clear the flag on a phone",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/sessions/:phone": {
+		Tag: "Customers", Summary: "A customer's bot session (state, cart, pending order, seconds left) without extending it",
+		Roles: managerOnly, Response: service.CustomerSessionView{},
+	},
+	"DELETE /api/admin/sessions/:phone": {
+		Tag: "Customers", Summary: "Force-reset a customer's bot session; their next message starts over",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+
+	"GET /api/admin/broadcasts": {
+		Tag: "Customers", Summary: "Recent broadcast campaigns with delivery stats, newest first",
+		Roles: managerOnly, Query: []apiParam{limitParam}, Response: []core.BroadcastCampaign{},
+	},
+	"GET /api/admin/broadcasts/audience": {
+		Tag: "Customers", Summary: "How many opted-in customers a segment reaches right now",
+		Roles: managerOnly,
+		Query: []apiParam{
+			{Name: "segment", Description: "all, recent (ordered in the last days) or lapsed (ordered before, not in the last days)"},
+			{Name: "days", Type: "integer", Description: "Window for recent and lapsed (default 30)"},
+		},
+		Response: service.BroadcastAudience{},
+	},
+	"POST /api/admin/broadcasts": {
+		Tag: "Customers", Summary: "Queue a marketing message to opted-in customers in a segment; sent at BROADCAST_RATE_PER_MINUTE",
+		Roles: managerOnly, Request: createBroadcastRequest{}, Status: fiber.StatusCreated, Response: core.BroadcastCampaign{},
+	},
+	"GET /api/admin/broadcasts/:id": {
+		Tag: "Customers", Summary: "Broadcast campaign with sent, failed, skipped and pending counts",
+		Roles: managerOnly, Response: core.BroadcastCampaign{},
+	},
+	"POST /api/admin/broadcasts/:id/cancel": {
+		Tag: "Customers", Summary: "Stop a campaign; recipients not yet sent are cancelled",
+		Roles: managerOnly, Response: core.BroadcastCampaign{},
+	},
+
+	// Settings
+	"GET /api/admin/settings": {
+		Tag: "Settings", Summary: "Runtime settings with their values, defaults and allowed ranges",
+		Roles: managerOnly, Response: []service.SettingView{},
+	},
+	"PATCH /api/admin/settings": {
+		Tag: "Settings", Summary: "Change settings by key, all or nothing; emits settings_updated",
+		Roles: managerOnly, Request: map[string]interface{}{}, Response: []service.SettingView{},
+	},
+	"GET /api/admin/settings/ordering": {
+		Tag: "Settings", Summary: "Whether the bot is accepting checkouts",
+		Roles: managerAndStaff, Response: core.OrderingStatus{},
+	},
+	"POST /api/admin/settings/ordering": {
+		Tag: "Settings", Summary: "Pause or resume new checkouts; emits settings_updated",
+		Roles: managerOnly, Request: setOrderingRequest{}, Response: core.OrderingStatus{},
+	},
+
+	// WhatsApp
+	"GET /api/admin/whatsapp/dead-letters": {
+		Tag: "WhatsApp", Summary: "Messages that ran out of delivery retries",
+		Roles: managerOnly, Query: []apiParam{limitParam}, Response: []core.OutboundMessage{},
+	},
+	"GET /api/admin/whatsapp/numbers": {
+		Tag: "WhatsApp", Summary: "WhatsApp numbers served besides the default one, with their venues and categories (tokens are never returned)",
+		Roles: managerOnly, Response: []core.WhatsAppNumber{},
+	},
+	"PUT /api/admin/whatsapp/numbers/:phone_number_id": {
+		Tag: "WhatsApp", Summary: "Register or update a number; omit access_token to keep the saved one. Emits whatsapp_numbers_updated",
+		Roles: managerOnly, Request: saveWhatsAppNumberRequest{}, Response: core.WhatsAppNumber{},
+	},
+	"DELETE /api/admin/whatsapp/numbers/:phone_number_id": {
+		Tag: "WhatsApp", Summary: "Unregister a number; its customers are answered from the default number",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/whatsapp/webhook-stats": {
+		Tag: "WhatsApp", Summary: "Webhook signature verification and rejection counts",
+		Roles: managerOnly, Response: webhookStatsResponse{},
+	},
+	"GET /api/admin/webhooks": {
+		Tag: "Webhooks", Summary: "Archived WhatsApp and payment webhooks with headers, body and verification result, newest first",
+		Roles: managerOnly,
+		Query: append([]apiParam{
+			{Name: "source", Description: "whatsapp or payment"},
+			{Name: "signature_valid", Type: "boolean", Description: "Signature verification result"},
+			{Name: "status", Type: "integer", Description: "Status the webhook endpoint responded with"},
+			{Name: "q", Description: "Text in the raw body, e.g. an M-Pesa reference or phone number"},
+			limitParam,
+		}, dateRangeParams...),
+		Response: []core.WebhookEvent{},
+	},
+	"POST /api/admin/webhooks/:id/replay": {
+		Tag: "Webhooks", Summary: "Re-run processing for an archived webhook; responds with what the webhook endpoint returned",
+		Roles: managerOnly, Response: webhookAckResponse{},
+	},
+
+	// Payments
+	"GET /api/admin/payments": {
+		Tag: "Payments", Summary: "Payments ledger",
+		Roles: managerOnly,
+		Query: append([]apiParam{
+			{Name: "provider"},
+			{Name: "phone"},
+			{Name: "reference"},
+			{Name: "order_id"},
+			{Name: "orphan", Type: "boolean", Description: "Only payments with (true) or without (false) a matching order"},
+			limitParam,
+		}, dateRangeParams...),
+		Response: []core.Payment{},
+	},
+	"GET /api/admin/payments/orphans": {
+		Tag: "Payments", Summary: "Payments that couldn't be matched to an order",
+		Roles: managerOnly, Query: []apiParam{limitParam}, Response: []core.Payment{},
+	},
+	"POST /api/admin/payments/:id/attach-order": {
+		Tag: "Payments", Summary: "Apply an orphaned payment to an order",
+		Roles: managerOnly, Request: attachPaymentRequest{}, Response: core.Order{},
+	},
+	"GET /api/admin/payments/webhook-subscriptions": {
+		Tag: "Payments", Summary: "Kopo Kopo webhook subscriptions and whether our callback is subscribed",
+		Roles: managerOnly, Response: service.PaymentWebhookStatus{},
+	},
+	"POST /api/admin/payments/webhook-subscriptions": {
+		Tag: "Payments", Summary: "Subscribe a callback URL to a Kopo Kopo event",
+		Roles: managerOnly, Request: createWebhookSubscriptionRequest{}, Status: fiber.StatusCreated, Response: core.WebhookSubscription{},
+	},
+	"DELETE /api/admin/payments/webhook-subscriptions/:id": {
+		Tag: "Payments", Summary: "Delete a Kopo Kopo webhook subscription",
+		Roles: managerOnly, Response: messageResponse{},
+	},
+	"GET /api/admin/payments/stk-dead-letters": {
+		Tag: "Payments", Summary: "STK pushes that exhausted their retries",
+		Roles: managerOnly, Query: []apiParam{limitParam}, Response: []core.STKPushJob{},
+	},
+
+	// Orders
+	"GET /api/admin/orders": {
+		Tag: "Orders", Summary: "Search orders",
+		Roles: managerAndStaff,
+		Query: append([]apiParam{
+			{Name: "status", Description: "Order status, e.g. PAID"},
+			{Name: "pickup_code"},
+			{Name: "phone", Description: "Customer phone (partial match)"},
+			{Name: "payment_method", Description: "MPESA, CASH or CARD"},
+			{Name: "min_amount", Type: "number"},
+			{Name: "max_amount", Type: "number"},
+			limitParam,
+		}, dateRangeParams...),
+		Response: []core.Order{},
+	},
+	"GET /api/admin/orders/board": {
+		Tag: "Orders", Summary: "Live orders board: PAID queue, READY and recently COMPLETED with age in status",
+		Roles: managerAndStaff,
+		Query: []apiParam{
+			{Name: "completed_minutes", Type: "integer", Description: "How far back completed orders are shown (default 30, max 240)"},
+		},
+		Response: core.OrderBoard{},
+	},
+	"GET /api/admin/orders/history": {
+		Tag: "Orders", Summary: "Look up past orders by pickup code or phone",
+		Roles:    managerAndStaff,
+		Query:    []apiParam{{Name: "pickup_code"}, {Name: "phone"}, limitParam},
+		Response: []core.Order{},
+	},
+	"GET /api/admin/orders/:id": {
+		Tag: "Orders", Summary: "Order with items, staff names and status history",
+		Roles: managerAndStaff, Response: core.OrderDetail{},
+	},
+	"GET /api/admin/orders/:id/history": {
+		Tag: "Orders", Summary: "Status changes for an order",
+		Roles: managerAndStaff, Response: orderStatusHistoryResponse{},
+	},
+	"GET /api/admin/orders/:id/payment-attempts": {
+		Tag: "Orders", Summary: "STK pushes sent for an order and their outcomes",
+		Roles: managerAndStaff, Response: orderPaymentAttemptsResponse{},
+	},
+	"POST /api/admin/orders/:id/confirm-payment": {
+		Tag: "Orders", Summary: "Mark a pay-at-the-bar order PAID (method defaults to CASH)",
+		Roles: managerAndStaff, Request: confirmBarPaymentRequest{}, Response: core.Order{},
+	},
+	"POST /api/admin/orders/:id/ready": {
+		Tag: "Orders", Summary: "Mark a PAID order READY and notify the customer",
+		Roles: managerAndStaff, Response: messageResponse{},
+	},
+	"POST /api/admin/orders/:id/complete": {
+		Tag: "Orders", Summary: "Mark a READY order COMPLETED",
+		Roles: managerAndStaff, Response: messageResponse{},
+	},
+	"POST /api/admin/orders/:id/dispatch": {
+		Tag: "Orders", Summary: "Hand a PAID delivery order to the rider (OUT_FOR_DELIVERY) and notify the customer",
+		Roles: managerAndStaff, Response: messageResponse{},
+	},
+	"POST /api/admin/orders/:id/delivered": {
+		Tag: "Orders", Summary: "Mark an OUT_FOR_DELIVERY order DELIVERED",
+		Roles: managerAndStaff, Response: messageResponse{},
+	},
+	"POST /api/admin/orders/:id/refund": {
+		Tag: "Orders", Summary: "Record that a paid order's money was returned; cancels it and adds a negative line to the accounting export",
+		Roles: managerOnly, Request: refundOrderRequest{}, Response: core.Order{},
+	},
+	"GET /api/admin/orders/:id/receipt": {
+		Tag: "Orders", Summary: "PDF receipt for a paid order",
+		Roles: managerAndStaff, Produces: "application/pdf",
+	},
+
+	// Live events
+	"GET /api/admin/events": {
+		Tag: "Events", Summary: "Server-Sent Events stream of order and product changes",
+		Roles: managerAndStaff, Produces: "text/event-stream",
+		Query: []apiParam{
+			{Name: "types", Description: "Comma-separated event types to receive"},
+			{Name: "last_event_id", Description: "Replay buffered events after this ID (alternative to the Last-Event-ID header)"},
+		},
+	},
+	"GET /api/admin/ws": {
+		Tag: "Events", Summary: "WebSocket event stream; authenticates with ?token= or its first message",
+		Query: []apiParam{
+			{Name: "token", Description: "Access token (alternatively sent as the first message)"},
+			{Name: "events", Description: "Comma-separated event types to receive"},
+		},
+		Status: fiber.StatusSwitchingProtocols,
+	},
+}
+
+var routeParamPattern = regexp.MustCompile(`:(\w+)\??`)
+
+// BuildOpenAPISpec documents the registered /api routes. Routes without an entry in apiOperations
+// are still listed, so nothing the server accepts is missing from the docs.
+func BuildOpenAPISpec(routes []fiber.Route, version string) fiber.Map {
+	schemas := newSchemaRegistry()
+	paths := fiber.Map{}
+
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/") || strings.HasPrefix(route.Path, docsPath) {
+			continue
+		}
+		method := strings.ToLower(route.Method)
+		switch route.Method {
+		case fiber.MethodGet, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			continue // HEAD mirrors GET; USE entries are middleware
+		}
+
+		path := routeParamPattern.ReplaceAllString(route.Path, "{$1}")
+		item, _ := paths[path].(fiber.Map)
+		if item == nil {
+			item = fiber.Map{}
+			paths[path] = item
+		}
+
+		op, documented := apiOperations[route.Method+" "+route.Path]
+		if !documented {
+			op = apiOperation{Tag: "Other", Summary: route.Method + " " + route.Path}
+		}
+		item[method] = buildOperation(schemas, route, op)
+	}
+
+	return fiber.Map{
+		"openapi": "3.0.3",
+		"info": fiber.Map{
+			"title":   "Destination Cocktails API",
+			"version": version,
+			"description": "Dashboard and webhook API. Dashboard routes accept the auth_token cookie or a Bearer token. " +
+				"Every response carries X-Request-ID, and error bodies include it as request_id. " +
+				"Admin POST/PATCH requests may send an Idempotency-Key header to make retries safe.",
+		},
+		"paths": paths,
+		"components": fiber.Map{
+			"schemas": schemas.components,
+			"securitySchemes": fiber.Map{
+				"cookieAuth": fiber.Map{"type": "apiKey", "in": "cookie", "name": authCookieName},
+				"bearerAuth": fiber.Map{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+func buildOperation(schemas *schemaRegistry, route fiber.Route, op apiOperation) fiber.Map {
+	parameters := []fiber.Map{}
+	for _, name := range routeParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		parameters = append(parameters, fiber.Map{
+			"name": name[1], "in": "path", "required": true, "schema": fiber.Map{"type": "string"},
+		})
+	}
+	for _, param := range op.Query {
+		parameters = append(parameters, paramSpec(param, "query"))
+	}
+	for _, param := range op.Headers {
+		parameters = append(parameters, paramSpec(param, "header"))
+	}
+	if len(op.Roles) > 0 && (route.Method == fiber.MethodPost || route.Method == fiber.MethodPatch) {
+		parameters = append(parameters, paramSpec(apiParam{
+			Name:        "Idempotency-Key",
+			Description: "Replays the first response when the request is retried with the same key",
+		}, "header"))
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+	success := fiber.Map{"description": http.StatusText(status)}
+	switch {
+	case op.Produces != "":
+		content := fiber.Map{}
+		for _, contentType := range strings.Split(op.Produces, ", ") {
+			content[contentType] = fiber.Map{"schema": fiber.Map{"type": "string"}}
+		}
+		success["content"] = content
+	case op.Response != nil:
+		success["content"] = fiber.Map{
+			fiber.MIMEApplicationJSON: fiber.Map{"schema": schemas.schemaFor(reflect.TypeOf(op.Response))},
+		}
+	}
+
+	errorContent := fiber.Map{
+		fiber.MIMEApplicationJSON: fiber.Map{"schema": schemas.schemaFor(reflect.TypeOf(errorResponse{}))},
+	}
+	operation := fiber.Map{
+		"tags":        []string{op.Tag},
+		"summary":     op.Summary,
+		"operationId": operationID(route),
+		"parameters":  parameters,
+		"responses": fiber.Map{
+			strconv.Itoa(status): success,
+			"4XX":                fiber.Map{"description": "Rejected request", "content": errorContent},
+			"5XX":                fiber.Map{"description": "Server error", "content": errorContent},
+		},
+	}
+
+	if body := requestBody(schemas, op); body != nil {
+		operation["requestBody"] = body
+	}
+	if len(op.Roles) > 0 {
+		operation["description"] = "Roles: " + strings.Join(op.Roles, ", ")
+		operation["security"] = []fiber.Map{{"cookieAuth": []string{}}, {"bearerAuth": []string{}}}
+	}
+	return operation
+}
+
+func requestBody(schemas *schemaRegistry, op apiOperation) fiber.Map {
+	if op.RequestType == csvBody {
+		return fiber.Map{
+			"required": true,
+			"content": fiber.Map{
+				fiber.MIMEMultipartForm: fiber.Map{"schema": fiber.Map{
+					"type":       "object",
+					"properties": fiber.Map{"file": fiber.Map{"type": "string", "format": "binary"}},
+				}},
+				csvBody: fiber.Map{"schema": fiber.Map{"type": "string"}},
+			},
+		}
+	}
+
+	var schema fiber.Map
+	switch request := op.Request.(type) {
+	case nil:
+		return nil
+	case oneOf:
+		variants := make([]fiber.Map, len(request))
+		for i, variant := range request {
+			variants[i] = schemas.schemaFor(reflect.TypeOf(variant))
+		}
+		schema = fiber.Map{"oneOf": variants}
+	default:
+		schema = schemas.schemaFor(reflect.TypeOf(request))
+	}
+	return fiber.Map{
+		"content": fiber.Map{fiber.MIMEApplicationJSON: fiber.Map{"schema": schema}},
+	}
+}
+
+func paramSpec(param apiParam, in string) fiber.Map {
+	paramType := param.Type
+	if paramType == "" {
+		paramType = "string"
+	}
+	spec := fiber.Map{"name": param.Name, "in": in, "schema": fiber.Map{"type": paramType}}
+	if param.Description != "" {
+		spec["description"] = param.Description
+	}
+	if param.Required {
+		spec["required"] = true
+	}
+	return spec
+}
+
+// operationID is a stable ID such as get_api_admin_orders_id
+func operationID(route fiber.Route) string {
+	return strings.ToLower(route.Method) + strings.NewReplacer("/", "_", ":", "", "-", "_", "?", "").Replace(route.Path)
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaRegistry turns Go types into OpenAPI schemas, following encoding/json rules.
+// Named structs become shared components referenced with $ref.
+type schemaRegistry struct {
+	components map[string]fiber.Map
+	names      map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: make(map[string]fiber.Map),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema for t, registering components for the named structs it uses
+func (r *schemaRegistry) schemaFor(t reflect.Type) fiber.Map {
+	switch t {
+	case timeType:
+		return fiber.Map{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return fiber.Map{"description": "Any JSON value"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := r.schemaFor(t.Elem())
+		if _, isRef := schema["$ref"]; !isRef { // Siblings of $ref are ignored in OpenAPI 3.0
+			schema["nullable"] = true
+		}
+		return schema
+	case reflect.String:
+		return fiber.Map{"type": "string"}
+	case reflect.Bool:
+		return fiber.Map{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fiber.Map{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return fiber.Map{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return fiber.Map{"type": "string", "format": "byte"}
+		}
+		return fiber.Map{"type": "array", "items": r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return fiber.Map{"type": "object", "additionalProperties": r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return fiber.Map{"$ref": "#/components/schemas/" + r.register(t)}
+	default:
+		// interface{} and anything else encoding/json can't describe up front
+		return fiber.Map{}
+	}
+}
+
+// register adds a named struct to the components (once) and returns its component name
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := componentName(t)
+	if _, taken := r.components[name]; taken {
+		name = componentName(t) + "_" + pathBase(t.PkgPath())
+	}
+	r.names[t] = name
+	r.components[name] = fiber.Map{} // Placeholder so recursive types terminate
+	r.components[name] = r.structSchema(t)
+	return name
+}
+
+// structSchema lists a struct's JSON fields; embedded structs without a JSON name are flattened like encoding/json does
+func (r *schemaRegistry) structSchema(t reflect.Type) fiber.Map {
+	properties := fiber.Map{}
+	r.addFields(t, properties)
+	return fiber.Map{"type": "object", "properties": properties}
+}
+
+func (r *schemaRegistry) addFields(t reflect.Type, properties fiber.Map) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(embedded, properties)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = r.schemaFor(field.Type)
+	}
+}
+
+// componentName is the exported form of the type name, e.g. createBundleRequest -> CreateBundleRequest
+func componentName(t reflect.Type) string {
+	runes := []rune(t.Name())
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func pathBase(pkgPath string) string {
+	if i := strings.LastIndex(pkgPath, "/"); i >= 0 {
+		return pkgPath[i+1:]
+	}
+	return pkgPath
+}
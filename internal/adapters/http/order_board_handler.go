@@ -0,0 +1,29 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetOrderBoard returns the live orders board for a kitchen/bar display: the PAID queue, READY orders
+// and orders completed in the last completed_minutes (default 30), each with its age in status
+// GET /api/admin/orders/board?completed_minutes=30
+func (h *DashboardHandler) GetOrderBoard(c *fiber.Ctx) error {
+	minutes, err := strconv.Atoi(c.Query("completed_minutes", "30"))
+	if err != nil || minutes < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid completed_minutes: must be a non-negative number",
+		})
+	}
+
+	board, err := h.dashboardService.GetOrderBoard(c.Context(), time.Duration(minutes)*time.Minute)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get order board",
+		})
+	}
+
+	return c.JSON(board)
+}
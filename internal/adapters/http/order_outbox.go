@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+)
+
+// RegisterOutboxHandlers delivers the paid-order side effects queued in the outbox: the customer's
+// confirmation and receipt, the bar staff notification and the dashboard event. Payment webhooks
+// and bar payment buttons wake the dispatcher so these go out straight after the payment commits.
+func (h *Handler) RegisterOutboxHandlers(outbox *service.OutboxDispatcher) {
+	h.outbox = outbox
+	outbox.Handle(core.OutboxTopicPaidCustomer, h.outboxOrder(h.sendPaidConfirmation))
+	outbox.Handle(core.OutboxTopicPaidBarStaff, h.outboxOrder(h.notifyBarStaff))
+	outbox.Handle(core.OutboxTopicPaidDashboard, h.outboxOrder(func(ctx context.Context, order *core.Order) error {
+		if h.eventBus != nil {
+			h.eventBus.PublishNewOrder(ctx, order)
+		}
+		return nil
+	}))
+	outbox.Handle(core.OutboxTopicScheduledCustomer, h.outboxOrder(h.sendScheduledConfirmation))
+	outbox.Handle(core.OutboxTopicScheduledDashboard, h.outboxOrder(func(ctx context.Context, order *core.Order) error {
+		if h.eventBus != nil {
+			h.eventBus.PublishOrderScheduled(ctx, order)
+		}
+		return nil
+	}))
+}
+
+// wakeOutbox asks the dispatcher to deliver what the last status change queued
+func (h *Handler) wakeOutbox() {
+	if h.outbox != nil {
+		h.outbox.Wake()
+	}
+}
+
+// outboxOrder adapts an order side effect to an outbox handler, loading the order as it is now
+func (h *Handler) outboxOrder(deliver func(ctx context.Context, order *core.Order) error) service.OutboxHandler {
+	return func(ctx context.Context, message *core.OutboxMessage) error {
+		order, err := h.orderRepo.GetByID(ctx, message.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to load order %s: %w", message.OrderID, err)
+		}
+		return deliver(ctx, order)
+	}
+}
+
+// sendPaidConfirmation sends the customer their pickup code (or delivery details), then the receipt and
+// the offer to save the order as a favorite
+func (h *Handler) sendPaidConfirmation(ctx context.Context, order *core.Order) error {
+	lang := h.customerLanguage(ctx, order)
+	message := i18n.Default().T(lang, "payment.confirmed", order.PickupCode, order.TotalAmount)
+	if order.IsDelivery() {
+		message = i18n.Default().T(lang, "payment.delivery", order.PickupCode, order.DeliveryAddress, order.TotalAmount)
+	}
+	if err := h.sendPaymentConfirmation(ctx, order.CustomerPhone, message); err != nil {
+		return fmt.Errorf("failed to send payment confirmation: %w", err)
+	}
+	h.sendOutboxReceipt(ctx, order, lang)
+	h.offerFavorite(ctx, order, lang)
+	return nil
+}
+
+// sendScheduledConfirmation tells the customer their pre-order is paid and when it will be ready.
+// Bar staff hear about it when the scheduler releases it.
+func (h *Handler) sendScheduledConfirmation(ctx context.Context, order *core.Order) error {
+	if order.ScheduledFor == nil {
+		return h.sendPaidConfirmation(ctx, order)
+	}
+
+	lang := h.customerLanguage(ctx, order)
+	message := i18n.Default().T(lang, "payment.scheduled", order.PickupCode, service.FormatScheduledTime(*order.ScheduledFor), order.TotalAmount)
+	if err := h.sendPaymentConfirmation(ctx, order.CustomerPhone, message); err != nil {
+		return fmt.Errorf("failed to send pre-order confirmation: %w", err)
+	}
+	h.sendOutboxReceipt(ctx, order, lang)
+	h.offerFavorite(ctx, order, lang)
+	return nil
+}
+
+// sendOutboxReceipt follows a confirmation with the receipt. A failed receipt is reported but not
+// retried, since the retry would send the confirmation again.
+func (h *Handler) sendOutboxReceipt(ctx context.Context, order *core.Order, lang string) {
+	if h.receipts == nil {
+		return
+	}
+	if err := h.receipts.SendReceipt(ctx, order, lang); err != nil {
+		reporting.CaptureError(ctx, "payment.send_receipt", fmt.Errorf("failed to send receipt for order %s: %w", order.ID, err))
+	}
+}
+
+// offerFavorite follows a confirmation with the "Save as favorite" button. Like the receipt, a failed
+// offer is reported but not retried.
+func (h *Handler) offerFavorite(ctx context.Context, order *core.Order, lang string) {
+	if h.favorites == nil {
+		return
+	}
+	if err := h.favorites.OfferFavorite(ctx, order, lang); err != nil {
+		reporting.CaptureError(ctx, "payment.offer_favorite", fmt.Errorf("failed to offer favorite for order %s: %w", order.ID, err))
+	}
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setOrderingRequest is the body of POST /api/admin/settings/ordering
+type setOrderingRequest struct {
+	Paused  bool   `json:"paused"`
+	Message string `json:"message"` // Shown to customers while paused; empty uses the default
+}
+
+// GetOrderingStatus reports whether the bot is accepting checkouts
+// GET /api/admin/settings/ordering
+func (h *DashboardHandler) GetOrderingStatus(c *fiber.Ctx) error {
+	status, err := h.dashboardService.GetOrderingStatus(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get ordering status",
+		})
+	}
+
+	return c.JSON(status)
+}
+
+// SetOrderingStatus pauses or resumes new checkouts, e.g. when the bar runs out of ice or M-Pesa is down
+// POST /api/admin/settings/ordering {"paused": true, "message": "..."}
+func (h *DashboardHandler) SetOrderingStatus(c *fiber.Ctx) error {
+	var req setOrderingRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	status, err := h.dashboardService.SetOrderingPaused(c.Context(), req.Paused, req.Message, actorUserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "at most") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update ordering status",
+		})
+	}
+
+	return c.JSON(status)
+}
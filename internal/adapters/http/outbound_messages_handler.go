@@ -0,0 +1,25 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListWhatsAppDeadLetters returns WhatsApp messages that exhausted their retries
+// GET /api/admin/whatsapp/dead-letters?limit=100
+func (h *DashboardHandler) ListWhatsAppDeadLetters(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	messages, err := h.dashboardService.ListWhatsAppDeadLetters(c.Context(), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(messages)
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListPayments returns the payments ledger with optional filters
+// GET /api/admin/payments?provider=&phone=&reference=&order_id=&orphan=true|false&from=YYYY-MM-DD&to=YYYY-MM-DD&limit=100
+func (h *DashboardHandler) ListPayments(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	query := service.PaymentQuery{
+		Provider:  c.Query("provider", ""),
+		Phone:     c.Query("phone", ""),
+		Reference: c.Query("reference", ""),
+		OrderID:   c.Query("order_id", ""),
+		From:      c.Query("from", ""),
+		To:        c.Query("to", ""),
+		Limit:     limit,
+	}
+
+	if orphanParam := strings.TrimSpace(c.Query("orphan", "")); orphanParam != "" {
+		orphan, err := strconv.ParseBool(orphanParam)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "orphan must be true or false",
+			})
+		}
+		query.Orphan = &orphan
+	}
+
+	payments, err := h.dashboardService.ListPayments(c.Context(), query)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(strings.ToLower(err.Error()), "invalid date format") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(payments)
+}
+
+// ListOrphanPayments returns confirmed payments that could not be matched to an order
+// GET /api/admin/payments/orphans
+func (h *DashboardHandler) ListOrphanPayments(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	payments, err := h.dashboardService.ListOrphanPayments(c.Context(), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get orphaned payments",
+		})
+	}
+
+	return c.JSON(payments)
+}
+
+// attachPaymentRequest is the body of POST /api/admin/payments/:id/attach-order
+type attachPaymentRequest struct {
+	OrderID string `json:"order_id"`
+}
+
+// AttachPaymentToOrder manually matches an orphaned payment to an order and marks it PAID
+// POST /api/admin/payments/:id/attach-order
+func (h *DashboardHandler) AttachPaymentToOrder(c *fiber.Ctx) error {
+	paymentID := c.Params("id")
+	if paymentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "payment ID is required",
+		})
+	}
+
+	var req attachPaymentRequest
+	if err := c.BodyParser(&req); err != nil || strings.TrimSpace(req.OrderID) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order_id is required",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	order, err := h.dashboardService.AttachPaymentToOrder(c.Context(), paymentID, strings.TrimSpace(req.OrderID), actorUserID)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(strings.ToLower(msg), "not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+		case strings.Contains(msg, "already matched"):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+		case strings.Contains(msg, "can be matched"), strings.Contains(msg, "still due"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": msg})
+		}
+	}
+
+	return c.JSON(order)
+}
+
+// ListPaymentWebhookSubscriptions returns the Kopo Kopo webhook subscriptions and whether one targets our callback URL
+// GET /api/admin/payments/webhook-subscriptions
+func (h *DashboardHandler) ListPaymentWebhookSubscriptions(c *fiber.Ctx) error {
+	status, err := h.dashboardService.ListPaymentWebhookSubscriptions(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(status)
+}
+
+// createWebhookSubscriptionRequest is the optional body of POST /api/admin/payments/webhook-subscriptions
+type createWebhookSubscriptionRequest struct {
+	EventType string `json:"event_type"`
+	URL       string `json:"url"`
+}
+
+// CreatePaymentWebhookSubscription subscribes a URL to a Kopo Kopo event.
+// Both fields are optional and default to KOPOKOPO_CALLBACK_URL and buygoods_transaction_received.
+// POST /api/admin/payments/webhook-subscriptions {event_type, url}
+func (h *DashboardHandler) CreatePaymentWebhookSubscription(c *fiber.Ctx) error {
+	var req createWebhookSubscriptionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid request body",
+			})
+		}
+	}
+
+	subscription, err := h.dashboardService.CreatePaymentWebhookSubscription(c.Context(), req.EventType, req.URL)
+	if err != nil {
+		status := fiber.StatusBadGateway
+		if strings.Contains(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(subscription)
+}
+
+// DeletePaymentWebhookSubscription removes a Kopo Kopo webhook subscription
+// DELETE /api/admin/payments/webhook-subscriptions/:id
+func (h *DashboardHandler) DeletePaymentWebhookSubscription(c *fiber.Ctx) error {
+	if err := h.dashboardService.DeletePaymentWebhookSubscription(c.Context(), c.Params("id")); err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+		case strings.Contains(msg, "invalid"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+		default:
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": msg})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "webhook subscription deleted",
+	})
+}
+
+// ListSTKPushDeadLetters returns STK push requests that exhausted their retries
+// GET /api/admin/payments/stk-dead-letters?limit=100
+func (h *DashboardHandler) ListSTKPushDeadLetters(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	jobs, err := h.dashboardService.ListSTKPushDeadLetters(c.Context(), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(jobs)
+}
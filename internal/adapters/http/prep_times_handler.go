@@ -0,0 +1,41 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPrepTimeReport returns paid→ready and ready→completed averages and percentiles per day and per bartender
+// GET /api/admin/analytics/prep-times?from=2026-03-01&to=2026-03-31
+func (h *DashboardHandler) GetPrepTimeReport(c *fiber.Ctx) error {
+	report, err := h.dashboardService.GetPrepTimeReport(c.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// GetStaffReport returns orders marked ready/completed, average handling time and tips per dashboard user
+// GET /api/admin/analytics/staff?from=2026-03-01&to=2026-03-31
+func (h *DashboardHandler) GetStaffReport(c *fiber.Ctx) error {
+	report, err := h.dashboardService.GetStaffReport(c.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ArchiveProduct takes a product off the menu without deleting it, so past orders and reports keep their line items
+// PATCH /api/admin/products/:id/archive
+func (h *DashboardHandler) ArchiveProduct(c *fiber.Ctx) error {
+	return h.setProductArchived(c, true)
+}
+
+// UnarchiveProduct puts an archived product back on the menu
+// PATCH /api/admin/products/:id/unarchive
+func (h *DashboardHandler) UnarchiveProduct(c *fiber.Ctx) error {
+	return h.setProductArchived(c, false)
+}
+
+func (h *DashboardHandler) setProductArchived(c *fiber.Ctx, archived bool) error {
+	productID := c.Params("id")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID is required",
+		})
+	}
+
+	archive := h.dashboardService.UnarchiveProduct
+	if archived {
+		archive = h.dashboardService.ArchiveProduct
+	}
+
+	product, err := archive(c.Context(), productID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(product)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportProducts upserts products by name from a CSV (name, price, category, stock, description).
+// Send the file as multipart field "file" or as a raw text/csv body; ?dry_run=true validates without writing.
+// POST /api/admin/products/import?dry_run=true
+func (h *DashboardHandler) ImportProducts(c *fiber.Ctx) error {
+	var data io.Reader
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "failed to read uploaded file",
+			})
+		}
+		defer file.Close()
+		data = file
+	} else if len(c.Body()) > 0 && !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		data = bytes.NewReader(c.Body())
+	} else {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "CSV file is required",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	result, err := h.dashboardService.ImportProducts(c.Context(), data, c.QueryBool("dry_run"), actorUserID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if result.Invalid > 0 && !result.DryRun {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":  fmt.Sprintf("%d invalid rows, nothing was imported", result.Invalid),
+			"result": result,
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// ExportProducts downloads the catalogue in the same CSV format the import accepts
+// GET /api/admin/products/export
+func (h *DashboardHandler) ExportProducts(c *fiber.Ctx) error {
+	data, filename, err := h.dashboardService.ExportProducts(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to export products",
+		})
+	}
+
+	c.Set("Content-Type", "text/csv; charset=utf-8")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(data)
+}
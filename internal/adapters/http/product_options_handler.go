@@ -0,0 +1,147 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListProductOptions returns a product's serving options (size, ice, mixer), including inactive ones
+// GET /api/admin/products/:id/options
+func (h *DashboardHandler) ListProductOptions(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID is required",
+		})
+	}
+
+	options, err := h.dashboardService.ListProductOptions(c.Context(), productID)
+	if err != nil {
+		return c.Status(productOptionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(options)
+}
+
+// createProductOptionRequest is the body of POST /api/admin/products/:id/options
+type createProductOptionRequest struct {
+	GroupName  string  `json:"group_name"`
+	Label      string  `json:"label"`
+	PriceDelta float64 `json:"price_delta"`
+	SortOrder  int     `json:"sort_order"`
+}
+
+// CreateProductOption adds a serving option the bot asks for after the product is selected
+// POST /api/admin/products/:id/options
+func (h *DashboardHandler) CreateProductOption(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID is required",
+		})
+	}
+
+	var req createProductOptionRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	option, err := h.dashboardService.CreateProductOption(c.Context(), productID, service.ProductOptionInput{
+		GroupName:  req.GroupName,
+		Label:      req.Label,
+		PriceDelta: req.PriceDelta,
+		SortOrder:  req.SortOrder,
+	})
+	if err != nil {
+		return c.Status(productOptionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(option)
+}
+
+// updateProductOptionRequest is the body of PATCH /api/admin/products/:id/options/:optionId; omitted fields are left unchanged
+type updateProductOptionRequest struct {
+	GroupName  *string  `json:"group_name"`
+	Label      *string  `json:"label"`
+	PriceDelta *float64 `json:"price_delta"`
+	SortOrder  *int     `json:"sort_order"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// UpdateProductOption updates group, label, price delta, order or active status for a product option
+// PATCH /api/admin/products/:id/options/:optionId
+func (h *DashboardHandler) UpdateProductOption(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	optionID := c.Params("optionId")
+	if productID == "" || optionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID and option ID are required",
+		})
+	}
+
+	var req updateProductOptionRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	option, err := h.dashboardService.UpdateProductOption(c.Context(), productID, optionID, service.ProductOptionUpdate{
+		GroupName:  req.GroupName,
+		Label:      req.Label,
+		PriceDelta: req.PriceDelta,
+		SortOrder:  req.SortOrder,
+		IsActive:   req.IsActive,
+	})
+	if err != nil {
+		return c.Status(productOptionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(option)
+}
+
+// DeleteProductOption removes a product option (past orders keep the modifiers they were placed with)
+// DELETE /api/admin/products/:id/options/:optionId
+func (h *DashboardHandler) DeleteProductOption(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	optionID := c.Params("optionId")
+	if productID == "" || optionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID and option ID are required",
+		})
+	}
+
+	if err := h.dashboardService.DeleteProductOption(c.Context(), productID, optionID); err != nil {
+		return c.Status(productOptionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "product option deleted",
+	})
+}
+
+func productOptionErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "is required"), strings.Contains(msg, "must be"), strings.Contains(msg, "must not"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
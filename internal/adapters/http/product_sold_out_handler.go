@@ -0,0 +1,41 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// updateSoldOutVisibilityRequest is the body of PATCH /api/admin/products/:id/sold-out-visibility;
+// a null hide_when_sold_out follows the menu.hide_out_of_stock setting
+type updateSoldOutVisibilityRequest struct {
+	HideWhenSoldOut *bool `json:"hide_when_sold_out"`
+}
+
+// UpdateSoldOutVisibility sets whether a product leaves the bot's menu and search while it has no stock
+// PATCH /api/admin/products/:id/sold-out-visibility
+func (h *DashboardHandler) UpdateSoldOutVisibility(c *fiber.Ctx) error {
+	var req updateSoldOutVisibilityRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	product, err := h.dashboardService.SetProductSoldOutVisibility(c.Context(), c.Params("id"), req.HideWhenSoldOut)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			status = fiber.StatusNotFound
+		case strings.Contains(err.Error(), "is required"):
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(product)
+}
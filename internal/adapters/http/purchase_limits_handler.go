@@ -0,0 +1,80 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// setPurchaseLimitRequest is the body of PUT /api/admin/purchase-limits
+type setPurchaseLimitRequest struct {
+	Scope       string `json:"scope"`
+	Target      string `json:"target"`
+	MaxQuantity int    `json:"max_quantity"`
+}
+
+// ListPurchaseLimits returns the per-order caps on categories and products
+// GET /api/admin/purchase-limits
+func (h *DashboardHandler) ListPurchaseLimits(c *fiber.Ctx) error {
+	limits, err := h.dashboardService.ListPurchaseLimits(c.Context())
+	if err != nil {
+		return c.Status(purchaseLimitErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(limits)
+}
+
+// SetPurchaseLimit caps how many units of a category or product one order can hold; setting the same
+// target again changes its cap
+// PUT /api/admin/purchase-limits
+func (h *DashboardHandler) SetPurchaseLimit(c *fiber.Ctx) error {
+	var req setPurchaseLimitRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	limit, err := h.dashboardService.SetPurchaseLimit(c.Context(), service.PurchaseLimitInput{
+		Scope:       req.Scope,
+		Target:      req.Target,
+		MaxQuantity: req.MaxQuantity,
+	})
+	if err != nil {
+		return c.Status(purchaseLimitErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(limit)
+}
+
+// DeletePurchaseLimit removes a purchase limit
+// DELETE /api/admin/purchase-limits/:id
+func (h *DashboardHandler) DeletePurchaseLimit(c *fiber.Ctx) error {
+	if err := h.dashboardService.DeletePurchaseLimit(c.Context(), c.Params("id")); err != nil {
+		return c.Status(purchaseLimitErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "purchase limit deleted",
+	})
+}
+
+func purchaseLimitErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
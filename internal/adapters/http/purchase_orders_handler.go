@@ -0,0 +1,308 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// supplierRequest is the body of POST /api/admin/suppliers
+type supplierRequest struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Email       string `json:"email"`
+	Notes       string `json:"notes"`
+}
+
+// updateSupplierRequest is the body of PATCH /api/admin/suppliers/:id
+type updateSupplierRequest struct {
+	Name        *string `json:"name"`
+	PhoneNumber *string `json:"phone_number"`
+	Email       *string `json:"email"`
+	Notes       *string `json:"notes"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// ListSuppliers returns every supplier, active ones first
+// GET /api/admin/suppliers
+func (h *DashboardHandler) ListSuppliers(c *fiber.Ctx) error {
+	suppliers, err := h.dashboardService.ListSuppliers(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get suppliers",
+		})
+	}
+
+	return c.JSON(suppliers)
+}
+
+// CreateSupplier adds a supplier
+// POST /api/admin/suppliers
+func (h *DashboardHandler) CreateSupplier(c *fiber.Ctx) error {
+	var req supplierRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	supplier, err := h.dashboardService.CreateSupplier(c.Context(), service.SupplierInput{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Email:       req.Email,
+		Notes:       req.Notes,
+	})
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(supplier)
+}
+
+// UpdateSupplier applies a partial update to a supplier
+// PATCH /api/admin/suppliers/:id
+func (h *DashboardHandler) UpdateSupplier(c *fiber.Ctx) error {
+	var req updateSupplierRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	supplier, err := h.dashboardService.UpdateSupplier(c.Context(), c.Params("id"), service.SupplierUpdate{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Email:       req.Email,
+		Notes:       req.Notes,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(supplier)
+}
+
+// DeactivateSupplier hides a supplier from new purchase orders
+// DELETE /api/admin/suppliers/:id
+func (h *DashboardHandler) DeactivateSupplier(c *fiber.Ctx) error {
+	if err := h.dashboardService.DeactivateSupplier(c.Context(), c.Params("id")); err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "supplier deactivated",
+	})
+}
+
+// purchaseOrderItemRequest is one product in a purchase order request body
+type purchaseOrderItemRequest struct {
+	ProductID string  `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitCost  float64 `json:"unit_cost"`
+}
+
+// createPurchaseOrderRequest is the body of POST /api/admin/purchase-orders
+type createPurchaseOrderRequest struct {
+	SupplierID string                     `json:"supplier_id"`
+	Reference  string                     `json:"reference"`
+	Notes      string                     `json:"notes"`
+	Items      []purchaseOrderItemRequest `json:"items"`
+}
+
+// receivedItemRequest corrects what arrived for one product in a receive request body
+type receivedItemRequest struct {
+	ProductID        string   `json:"product_id"`
+	ReceivedQuantity *int     `json:"received_quantity"`
+	UnitCost         *float64 `json:"unit_cost"`
+}
+
+// receivePurchaseOrderRequest is the body of POST /api/admin/purchase-orders/:id/receive
+type receivePurchaseOrderRequest struct {
+	Items []receivedItemRequest `json:"items"`
+}
+
+// ListPurchaseOrders returns recent purchase orders, newest first
+// GET /api/admin/purchase-orders?status=ORDERED&limit=50
+func (h *DashboardHandler) ListPurchaseOrders(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	orders, err := h.dashboardService.ListPurchaseOrders(c.Context(), c.Query("status"), limit)
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(orders)
+}
+
+// GetPurchaseOrder returns a purchase order with its items
+// GET /api/admin/purchase-orders/:id
+func (h *DashboardHandler) GetPurchaseOrder(c *fiber.Ctx) error {
+	order, err := h.dashboardService.GetPurchaseOrder(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(order)
+}
+
+// CreatePurchaseOrder records stock ordered from a supplier
+// POST /api/admin/purchase-orders
+func (h *DashboardHandler) CreatePurchaseOrder(c *fiber.Ctx) error {
+	var req createPurchaseOrderRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	items := make([]service.PurchaseOrderItemInput, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = service.PurchaseOrderItemInput{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+		}
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	order, err := h.dashboardService.CreatePurchaseOrder(c.Context(), service.PurchaseOrderInput{
+		SupplierID: req.SupplierID,
+		Reference:  req.Reference,
+		Notes:      req.Notes,
+		Items:      items,
+	}, actorUserID)
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(order)
+}
+
+// ReceivePurchaseOrder books a delivery into stock; items left out of the body arrived as ordered
+// POST /api/admin/purchase-orders/:id/receive
+func (h *DashboardHandler) ReceivePurchaseOrder(c *fiber.Ctx) error {
+	var req receivePurchaseOrderRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid request body",
+			})
+		}
+	}
+
+	received := make([]service.ReceivedItemInput, len(req.Items))
+	for i, item := range req.Items {
+		received[i] = service.ReceivedItemInput{
+			ProductID:        item.ProductID,
+			ReceivedQuantity: item.ReceivedQuantity,
+			UnitCost:         item.UnitCost,
+		}
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	order, err := h.dashboardService.ReceivePurchaseOrder(c.Context(), c.Params("id"), received, actorUserID)
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(order)
+}
+
+// CancelPurchaseOrder cancels a purchase order that hasn't been received
+// POST /api/admin/purchase-orders/:id/cancel
+func (h *DashboardHandler) CancelPurchaseOrder(c *fiber.Ctx) error {
+	if err := h.dashboardService.CancelPurchaseOrder(c.Context(), c.Params("id")); err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "purchase order cancelled",
+	})
+}
+
+// updateCostPriceRequest is the body of PATCH /api/admin/products/:id/cost-price
+type updateCostPriceRequest struct {
+	CostPrice float64 `json:"cost_price"` // 0 clears it
+}
+
+// UpdateCostPrice sets what one stock unit of a product costs the bar
+// PATCH /api/admin/products/:id/cost-price
+func (h *DashboardHandler) UpdateCostPrice(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID is required",
+		})
+	}
+
+	var req updateCostPriceRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := h.dashboardService.SetCostPrice(c.Context(), productID, req.CostPrice); err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "cost price updated successfully",
+	})
+}
+
+// GetMarginReport returns revenue, cost and gross margin per product and category
+// GET /api/admin/analytics/margins?from=2026-03-01&to=2026-03-31
+func (h *DashboardHandler) GetMarginReport(c *fiber.Ctx) error {
+	report, err := h.dashboardService.GetMarginReport(c.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return c.Status(purchaseOrderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+func purchaseOrderErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "only ordered"), strings.Contains(msg, "already"):
+		return fiber.StatusConflict
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"), strings.Contains(msg, "must"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
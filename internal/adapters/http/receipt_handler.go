@@ -0,0 +1,37 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetOrderReceipt renders a paid order's receipt as PDF for reprinting
+// GET /api/admin/orders/:id/receipt
+func (h *DashboardHandler) GetOrderReceipt(c *fiber.Ctx) error {
+	orderID := c.Params("id")
+	if orderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "order ID is required",
+		})
+	}
+
+	data, filename, err := h.dashboardService.GenerateOrderReceipt(c.Context(), orderID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		errMsg := strings.ToLower(err.Error())
+		if strings.Contains(errMsg, "not found") {
+			status = fiber.StatusNotFound
+		} else if strings.Contains(errMsg, "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(data)
+}
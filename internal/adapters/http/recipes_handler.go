@@ -0,0 +1,116 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// recipeIngredientRequest is one ingredient in a recipe request body
+type recipeIngredientRequest struct {
+	ProductID string  `json:"product_id"`
+	Measure   float64 `json:"measure"`
+	Unit      string  `json:"unit"` // ml or count
+}
+
+// ListRecipes returns every cocktail with a recipe and how many more can be made from bottle stock
+// GET /api/admin/recipes
+func (h *DashboardHandler) ListRecipes(c *fiber.Ctx) error {
+	recipes, err := h.dashboardService.ListRecipes(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get recipes",
+		})
+	}
+
+	return c.JSON(recipes)
+}
+
+// setRecipeRequest is the body of PUT /api/admin/recipes/:id
+type setRecipeRequest struct {
+	Ingredients []recipeIngredientRequest `json:"ingredients"`
+}
+
+// SetRecipe replaces the ingredients a cocktail is poured from; an empty list removes the recipe
+// PUT /api/admin/recipes/:id
+func (h *DashboardHandler) SetRecipe(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID is required",
+		})
+	}
+
+	var req setRecipeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	inputs := make([]service.RecipeIngredientInput, len(req.Ingredients))
+	for i, ingredient := range req.Ingredients {
+		inputs[i] = service.RecipeIngredientInput{
+			ProductID: ingredient.ProductID,
+			Measure:   ingredient.Measure,
+			Unit:      ingredient.Unit,
+		}
+	}
+
+	recipe, err := h.dashboardService.SetRecipe(c.Context(), productID, inputs)
+	if err != nil {
+		return c.Status(recipeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(recipe)
+}
+
+// updateBottleSizeRequest is the body of PATCH /api/admin/products/:id/bottle-size
+type updateBottleSizeRequest struct {
+	BottleML int `json:"bottle_ml"` // 0 clears it
+}
+
+// UpdateBottleSize sets how many ml one stock unit of an ingredient holds
+// PATCH /api/admin/products/:id/bottle-size
+func (h *DashboardHandler) UpdateBottleSize(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product ID is required",
+		})
+	}
+
+	var req updateBottleSizeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := h.dashboardService.SetBottleSize(c.Context(), productID, req.BottleML); err != nil {
+		return c.Status(recipeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "bottle size updated successfully",
+	})
+}
+
+func recipeErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"), strings.Contains(msg, "must"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
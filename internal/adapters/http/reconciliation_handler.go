@@ -0,0 +1,46 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportReconciliation matches an M-Pesa till statement CSV against the payments ledger and returns
+// the matched, missing-in-system and missing-in-statement transactions. Nothing is written.
+// Send the file as multipart field "file" or as a raw text/csv body.
+// POST /api/admin/reconciliation/import
+func (h *DashboardHandler) ImportReconciliation(c *fiber.Ctx) error {
+	var data io.Reader
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "failed to read uploaded file",
+			})
+		}
+		defer file.Close()
+		data = file
+	} else if len(c.Body()) > 0 && !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		data = bytes.NewReader(c.Body())
+	} else {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "CSV file is required",
+		})
+	}
+
+	report, err := h.dashboardService.ReconcileStatement(c.Context(), data)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
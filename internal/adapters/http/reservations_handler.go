@@ -0,0 +1,83 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// updateReservationStatusRequest is the body of PATCH /api/admin/reservations/:id/status
+type updateReservationStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// ListReservations returns table and VIP booth reservations, earliest first
+// GET /api/admin/reservations?from=2026-03-01&to=2026-03-31&status=CONFIRMED&limit=100
+func (h *DashboardHandler) ListReservations(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	reservations, err := h.dashboardService.ListReservations(c.Context(), service.ReservationQuery{
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+		Status: c.Query("status"),
+		Limit:  limit,
+	})
+	if err != nil {
+		return c.Status(reservationErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(reservations)
+}
+
+// GetReservationCalendar returns reservations grouped by day with each day's expected guests
+// GET /api/admin/reservations/calendar?from=2026-03-01&to=2026-03-07
+func (h *DashboardHandler) GetReservationCalendar(c *fiber.Ctx) error {
+	days, err := h.dashboardService.GetReservationCalendar(c.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return c.Status(reservationErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(days)
+}
+
+// UpdateReservationStatus marks a reservation confirmed, seated, a no-show or cancelled
+// PATCH /api/admin/reservations/:id/status
+func (h *DashboardHandler) UpdateReservationStatus(c *fiber.Ctx) error {
+	var req updateReservationStatusRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	reservation, err := h.dashboardService.UpdateReservationStatus(c.Context(), c.Params("id"), req.Status)
+	if err != nil {
+		return c.Status(reservationErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(reservation)
+}
+
+func reservationErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
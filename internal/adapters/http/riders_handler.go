@@ -0,0 +1,125 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListRiders returns the delivery riders roster
+// GET /api/admin/riders
+func (h *DashboardHandler) ListRiders(c *fiber.Ctx) error {
+	riders, err := h.dashboardService.ListRiders(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get riders",
+		})
+	}
+
+	return c.JSON(riders)
+}
+
+// createRiderRequest is the body of POST /api/admin/riders
+type createRiderRequest struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	IsAvailable bool   `json:"is_available"`
+}
+
+// CreateRider adds a rider phone to the roster
+// POST /api/admin/riders
+func (h *DashboardHandler) CreateRider(c *fiber.Ctx) error {
+	var req createRiderRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	rider, err := h.dashboardService.CreateRider(c.Context(), req.Name, req.PhoneNumber, req.IsAvailable)
+	if err != nil {
+		return c.Status(riderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(rider)
+}
+
+// updateRiderRequest is the body of PATCH /api/admin/riders/:id; omitted fields are left unchanged
+type updateRiderRequest struct {
+	Name        *string `json:"name"`
+	PhoneNumber *string `json:"phone_number"`
+	IsAvailable *bool   `json:"is_available"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// UpdateRider updates name, phone, availability or active status for a rider
+// PATCH /api/admin/riders/:id
+func (h *DashboardHandler) UpdateRider(c *fiber.Ctx) error {
+	riderID := c.Params("id")
+	if riderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "rider ID is required",
+		})
+	}
+
+	var req updateRiderRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	rider, err := h.dashboardService.UpdateRider(c.Context(), riderID, service.RiderUpdate{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		IsAvailable: req.IsAvailable,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		return c.Status(riderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(rider)
+}
+
+// DeleteRider deactivates a rider (delivery history is preserved)
+// DELETE /api/admin/riders/:id
+func (h *DashboardHandler) DeleteRider(c *fiber.Ctx) error {
+	riderID := c.Params("id")
+	if riderID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "rider ID is required",
+		})
+	}
+
+	if err := h.dashboardService.DeactivateRider(c.Context(), riderID); err != nil {
+		return c.Status(riderErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "rider deactivated",
+	})
+}
+
+func riderErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid phone"), strings.Contains(msg, "is required"):
+		return fiber.StatusBadRequest
+	case strings.Contains(msg, "duplicate key"):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SyncSalesSheet appends a closed business day's summary row to the owner's Google Sheet
+// POST /api/admin/integrations/google-sheets/sync?date=YYYY-MM-DD
+func (h *DashboardHandler) SyncSalesSheet(c *fiber.Ctx) error {
+	summary, err := h.dashboardService.SyncSalesSheet(c.Context(), strings.TrimSpace(c.Query("date")))
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		status := fiber.StatusInternalServerError
+		switch {
+		case strings.Contains(msg, "invalid"):
+			status = fiber.StatusBadRequest
+		case strings.Contains(msg, "not configured"):
+			status = fiber.StatusServiceUnavailable
+		case strings.Contains(msg, "google"):
+			status = fiber.StatusBadGateway
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(summary)
+}
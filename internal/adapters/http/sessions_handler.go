@@ -0,0 +1,48 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetCustomerSession returns a customer's bot session: state, cart, pending order and time left
+// GET /api/admin/sessions/:phone
+func (h *DashboardHandler) GetCustomerSession(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(string)
+	view, err := h.dashboardService.GetCustomerSession(c.Context(), c.Params("phone"), actorUserID)
+	if err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(view)
+}
+
+// ResetCustomerSession deletes a customer's bot session so their next message starts over
+// DELETE /api/admin/sessions/:phone
+func (h *DashboardHandler) ResetCustomerSession(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(string)
+	if err := h.dashboardService.ResetCustomerSession(c.Context(), c.Params("phone"), actorUserID); err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "session reset",
+	})
+}
+
+func sessionErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "phone number format"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListSettings returns every runtime setting with its value, default and allowed range
+// GET /api/admin/settings
+func (h *DashboardHandler) ListSettings(c *fiber.Ctx) error {
+	settings, err := h.dashboardService.ListSettings(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get settings",
+		})
+	}
+
+	return c.JSON(settings)
+}
+
+// UpdateSettings changes one or more runtime settings at once, all or nothing
+// PATCH /api/admin/settings {"payment.safety_net_delay_seconds": 60, "ordering.paused": false}
+func (h *DashboardHandler) UpdateSettings(c *fiber.Ctx) error {
+	var values map[string]interface{}
+
+	if err := json.Unmarshal(c.Body(), &values); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	settings, err := h.dashboardService.UpdateSettings(c.Context(), values, actorUserID)
+	if err != nil {
+		msg := err.Error()
+		if strings.Contains(msg, "unknown setting") || strings.Contains(msg, "must ") || strings.Contains(msg, "no settings") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": msg,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update settings",
+		})
+	}
+
+	return c.JSON(settings)
+}
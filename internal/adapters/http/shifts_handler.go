@@ -0,0 +1,106 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListShifts returns bartender shifts, newest first
+// GET /api/admin/shifts?staff_id=...&from=2026-03-01&to=2026-03-07&limit=50
+func (h *DashboardHandler) ListShifts(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	shifts, err := h.dashboardService.ListShifts(c.Context(), service.ShiftQuery{
+		StaffID: c.Query("staff_id"),
+		From:    c.Query("from"),
+		To:      c.Query("to"),
+		Limit:   limit,
+	})
+	if err != nil {
+		return c.Status(shiftErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(shifts)
+}
+
+// shiftClockRequest is the body of POST /api/admin/shifts/clock-in and /clock-out
+type shiftClockRequest struct {
+	BarStaffID string `json:"bar_staff_id"`
+}
+
+// ClockIn opens a shift for a bartender on the roster and puts them on shift
+// POST /api/admin/shifts/clock-in
+func (h *DashboardHandler) ClockIn(c *fiber.Ctx) error {
+	var req shiftClockRequest
+
+	if err := c.BodyParser(&req); err != nil || req.BarStaffID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "bar_staff_id is required",
+		})
+	}
+
+	shift, err := h.dashboardService.ClockIn(c.Context(), req.BarStaffID)
+	if err != nil {
+		return c.Status(shiftErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(shift)
+}
+
+// ClockOut closes a bartender's shift, takes them off shift and sends managers the summary
+// POST /api/admin/shifts/clock-out
+func (h *DashboardHandler) ClockOut(c *fiber.Ctx) error {
+	var req shiftClockRequest
+
+	if err := c.BodyParser(&req); err != nil || req.BarStaffID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "bar_staff_id is required",
+		})
+	}
+
+	report, err := h.dashboardService.ClockOut(c.Context(), req.BarStaffID)
+	if err != nil {
+		return c.Status(shiftErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// GetShiftReport returns a shift's orders, revenue, cash/card/M-Pesa collected and tips
+// GET /api/admin/shifts/:id/report
+func (h *DashboardHandler) GetShiftReport(c *fiber.Ctx) error {
+	report, err := h.dashboardService.GetShiftReport(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(shiftErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+func shiftErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "already clocked in"), strings.Contains(msg, "not clocked in"), strings.Contains(msg, "inactive"):
+		return fiber.StatusConflict
+	case strings.Contains(msg, "invalid"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
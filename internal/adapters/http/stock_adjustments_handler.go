@@ -0,0 +1,43 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// adjustStockRequest is the body of POST /api/admin/products/:id/stock-adjustments
+type adjustStockRequest struct {
+	Delta  int    `json:"delta"`  // Units added, or removed when negative
+	Reason string `json:"reason"` // sale_correction, breakage, restock or theft
+}
+
+// AdjustStock adds or removes stock with a reason, recorded in stock_adjustments, and returns the new balance
+// POST /api/admin/products/:id/stock-adjustments
+func (h *DashboardHandler) AdjustStock(c *fiber.Ctx) error {
+	var req adjustStockRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	adjustment, err := h.dashboardService.AdjustStock(c.Context(), c.Params("id"), req.Delta, req.Reason, actorUserID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "not found"):
+			status = fiber.StatusNotFound
+		case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"):
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(adjustment)
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// startStocktakeRequest is the body of POST /api/admin/stocktakes
+type startStocktakeRequest struct {
+	Note string `json:"note"`
+}
+
+// StartStocktake opens an inventory count
+// POST /api/admin/stocktakes
+func (h *DashboardHandler) StartStocktake(c *fiber.Ctx) error {
+	var req startStocktakeRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid request body",
+			})
+		}
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	stocktake, err := h.dashboardService.StartStocktake(c.Context(), req.Note, actorUserID)
+	if err != nil {
+		return c.Status(stocktakeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(stocktake)
+}
+
+// ListStocktakes returns recent stocktakes, newest first
+// GET /api/admin/stocktakes?limit=20
+func (h *DashboardHandler) ListStocktakes(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		limit = 20
+	}
+
+	stocktakes, err := h.dashboardService.ListStocktakes(c.Context(), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to get stocktakes",
+		})
+	}
+
+	return c.JSON(stocktakes)
+}
+
+// GetStocktake returns a stocktake with the lines counted so far
+// GET /api/admin/stocktakes/:id
+func (h *DashboardHandler) GetStocktake(c *fiber.Ctx) error {
+	stocktake, err := h.dashboardService.GetStocktake(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(stocktakeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(stocktake)
+}
+
+// stocktakeCountRequest is one counted product in a stocktake lines request body
+type stocktakeCountRequest struct {
+	ProductID       string `json:"product_id"`
+	CountedQuantity int    `json:"counted_quantity"`
+}
+
+// recordStocktakeCountsRequest is the body of PUT /api/admin/stocktakes/:id/lines
+type recordStocktakeCountsRequest struct {
+	Lines []stocktakeCountRequest `json:"lines"`
+}
+
+// RecordStocktakeCounts saves counted quantities; each line's variance is against stock when it's counted
+// PUT /api/admin/stocktakes/:id/lines
+func (h *DashboardHandler) RecordStocktakeCounts(c *fiber.Ctx) error {
+	var req recordStocktakeCountsRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	counts := make([]service.StocktakeCountInput, len(req.Lines))
+	for i, line := range req.Lines {
+		counts[i] = service.StocktakeCountInput{
+			ProductID:       line.ProductID,
+			CountedQuantity: line.CountedQuantity,
+		}
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	lines, err := h.dashboardService.RecordStocktakeCounts(c.Context(), c.Params("id"), counts, actorUserID)
+	if err != nil {
+		return c.Status(stocktakeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(lines)
+}
+
+// ApplyStocktake adjusts stock by the counted variances and closes the stocktake
+// POST /api/admin/stocktakes/:id/apply
+func (h *DashboardHandler) ApplyStocktake(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(string)
+	adjustments, err := h.dashboardService.ApplyStocktake(c.Context(), c.Params("id"), actorUserID)
+	if err != nil {
+		return c.Status(stocktakeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(adjustments)
+}
+
+// CancelStocktake abandons an open stocktake without changing stock
+// POST /api/admin/stocktakes/:id/cancel
+func (h *DashboardHandler) CancelStocktake(c *fiber.Ctx) error {
+	if err := h.dashboardService.CancelStocktake(c.Context(), c.Params("id")); err != nil {
+		return c.Status(stocktakeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "stocktake cancelled",
+	})
+}
+
+// GetStocktakeVariance reports shrinkage and overage for a stocktake, valued at menu price
+// GET /api/admin/stocktakes/:id/variance
+func (h *DashboardHandler) GetStocktakeVariance(c *fiber.Ctx) error {
+	report, err := h.dashboardService.StocktakeVariance(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(stocktakeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+func stocktakeErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "already open"), strings.Contains(msg, "only open stocktakes"):
+		return fiber.StatusConflict
+	case strings.Contains(msg, "is required"), strings.Contains(msg, "must"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
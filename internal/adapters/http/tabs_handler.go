@@ -0,0 +1,46 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListOpenTabs returns open group tabs by table, with members, items and what's still unpaid
+// GET /api/admin/tabs
+func (h *DashboardHandler) ListOpenTabs(c *fiber.Ctx) error {
+	tabs, err := h.dashboardService.ListOpenTabs(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list tabs",
+		})
+	}
+
+	return c.JSON(tabs)
+}
+
+// CloseTab closes a group tab once the table has left
+// POST /api/admin/tabs/:id/close
+func (h *DashboardHandler) CloseTab(c *fiber.Ctx) error {
+	tabID := c.Params("id")
+	if tabID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "tab ID is required",
+		})
+	}
+
+	if err := h.dashboardService.CloseTab(c.Context(), tabID); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "open tab not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to close tab",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "tab closed",
+	})
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetWebhookEventRepository wires the webhook archive read by the replay endpoint
+func (h *Handler) SetWebhookEventRepository(webhookEvents core.WebhookEventRepository) {
+	h.webhookEvents = webhookEvents
+}
+
+// ListWebhookEvents returns archived WhatsApp and payment webhooks, newest first
+// GET /api/admin/webhooks?source=payment&signature_valid=true&status=400&q=<reference>&from=2026-03-01&to=2026-03-07&limit=100
+func (h *DashboardHandler) ListWebhookEvents(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+	status, _ := strconv.Atoi(c.Query("status", ""))
+
+	events, err := h.dashboardService.ListWebhookEvents(c.Context(), service.WebhookEventQuery{
+		Source:         c.Query("source", ""),
+		SignatureValid: c.Query("signature_valid", ""),
+		StatusCode:     status,
+		Search:         c.Query("q", ""),
+		From:           c.Query("from", ""),
+		To:             c.Query("to", ""),
+		Limit:          limit,
+	})
+	if err != nil {
+		return c.Status(webhookEventErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(events)
+}
+
+// ReplayWebhookEvent re-runs processing for an archived webhook and responds with what the webhook
+// endpoint returned this time. Payments already applied are skipped by reference; replayed WhatsApp
+// messages reach the bot again, so customers get its replies again.
+// POST /api/admin/webhooks/:id/replay
+func (h *Handler) ReplayWebhookEvent(c *fiber.Ctx) error {
+	if h.webhookEvents == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "webhook archive not configured",
+		})
+	}
+
+	id := c.Params("id")
+	event, err := h.webhookEvents.GetByID(c.Context(), id)
+	if err != nil {
+		return c.Status(webhookEventErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if event.SignatureValid != nil && !*event.SignatureValid {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "webhook failed signature verification and can't be replayed",
+		})
+	}
+
+	actor, _ := c.Locals("user_id").(string)
+	log.Printf("Replaying %s webhook %s (received %s) for %s", event.Source, event.ID, event.ReceivedAt.Format(time.RFC3339), actor)
+
+	var replayErr error
+	switch event.Source {
+	case core.WebhookSourceWhatsApp:
+		replayErr = h.processWhatsAppWebhook(c, []byte(event.Body), true)
+	case core.WebhookSourcePayment:
+		replayErr = h.processPaymentWebhook(c, []byte(event.Body))
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "unknown webhook source " + event.Source,
+		})
+	}
+
+	if err := h.webhookEvents.MarkReplayed(c.Context(), event.ID, time.Now()); err != nil {
+		log.Printf("Failed to count replay of webhook %s: %v", event.ID, err)
+	}
+	return replayErr
+}
+
+// webhookEventErrorStatus maps webhook archive errors to HTTP statuses
+func webhookEventErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Webhook rejection reasons
+const (
+	webhookRejectMissingSignature = "missing_signature"
+	webhookRejectInvalidSignature = "invalid_signature"
+)
+
+// webhookRejections counts WhatsApp webhook requests refused by signature verification.
+// Counters are per process, so each replica reports its own.
+type webhookRejections struct {
+	missingSignature atomic.Int64
+	invalidSignature atomic.Int64
+	lastRejectedAt   atomic.Int64 // Unix seconds, 0 if none yet
+}
+
+// rejectWebhook records a refused webhook and responds 401
+func (h *Handler) rejectWebhook(c *fiber.Ctx, reason string) error {
+	message := "Invalid signature"
+	if reason == webhookRejectMissingSignature {
+		h.rejections.missingSignature.Add(1)
+		message = "Missing signature"
+	} else {
+		h.rejections.invalidSignature.Add(1)
+	}
+	h.rejections.lastRejectedAt.Store(time.Now().Unix())
+
+	log.Printf("WARNING: rejected WhatsApp webhook from %s: %s", c.IP(), reason)
+	return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// GetWebhookStats reports whether webhook signatures are verified and how many requests were rejected
+// GET /api/admin/whatsapp/webhook-stats
+func (h *Handler) GetWebhookStats(c *fiber.Ctx) error {
+	var lastRejectedAt *time.Time
+	if unix := h.rejections.lastRejectedAt.Load(); unix > 0 {
+		at := time.Unix(unix, 0).UTC()
+		lastRejectedAt = &at
+	}
+
+	return c.JSON(fiber.Map{
+		"signature_verification":     h.appSecret != "",
+		"rejected_missing_signature": h.rejections.missingSignature.Load(),
+		"rejected_invalid_signature": h.rejections.invalidSignature.Load(),
+		"last_rejected_at":           lastRejectedAt,
+	})
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/gofiber/fiber/v2"
+)
+
+// saveWhatsAppNumberRequest is the body of PUT /api/admin/whatsapp/numbers/:phone_number_id
+type saveWhatsAppNumberRequest struct {
+	DisplayPhoneNumber string   `json:"display_phone_number"`
+	VenueName          string   `json:"venue_name"`
+	AccessToken        string   `json:"access_token"` // Omit to keep the saved token
+	Categories         []string `json:"categories"`
+	Active             *bool    `json:"active"` // Default true
+}
+
+// ListWhatsAppNumbers returns the WhatsApp numbers served besides the default one, without their tokens
+// GET /api/admin/whatsapp/numbers
+func (h *DashboardHandler) ListWhatsAppNumbers(c *fiber.Ctx) error {
+	numbers, err := h.dashboardService.ListWhatsAppNumbers(c.Context())
+	if err != nil {
+		return c.Status(whatsAppNumberErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(numbers)
+}
+
+// SaveWhatsAppNumber registers a WhatsApp number pointed at this server, or updates it
+// PUT /api/admin/whatsapp/numbers/:phone_number_id
+func (h *DashboardHandler) SaveWhatsAppNumber(c *fiber.Ctx) error {
+	var req saveWhatsAppNumberRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	actorUserID, _ := c.Locals("user_id").(string)
+	number, err := h.dashboardService.SaveWhatsAppNumber(c.Context(), &core.WhatsAppNumber{
+		PhoneNumberID:      c.Params("phone_number_id"),
+		DisplayPhoneNumber: req.DisplayPhoneNumber,
+		VenueName:          req.VenueName,
+		AccessToken:        req.AccessToken,
+		Categories:         req.Categories,
+		Active:             active,
+	}, actorUserID)
+	if err != nil {
+		return c.Status(whatsAppNumberErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(number)
+}
+
+// DeleteWhatsAppNumber unregisters a WhatsApp number; its customers are answered from the default number
+// DELETE /api/admin/whatsapp/numbers/:phone_number_id
+func (h *DashboardHandler) DeleteWhatsAppNumber(c *fiber.Ctx) error {
+	if err := h.dashboardService.DeleteWhatsAppNumber(c.Context(), c.Params("phone_number_id")); err != nil {
+		return c.Status(whatsAppNumberErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "whatsapp number deleted",
+	})
+}
+
+func whatsAppNumberErrorStatus(err error) int {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return fiber.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "is required"):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,146 @@
+package nlu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// openAISystemPrompt tells the model which actions exist and that it must answer with one JSON object
+const openAISystemPrompt = `You map WhatsApp messages from bar customers in Nairobi (English, Swahili or Sheng) to one ordering action.
+Reply with a single JSON object and nothing else:
+{"action": "browse_category" | "add_item" | "view_cart" | "checkout" | "none", "category": "<category>", "product_id": "<id>", "quantity": <integer>}
+- browse_category: the customer wants to see a category; category must be copied exactly from the menu.
+- add_item: the customer wants a specific drink; product_id must be copied exactly from the menu. quantity is how many they asked for, or 0 if they didn't say.
+- view_cart: the customer wants to see what they have ordered so far.
+- checkout: the customer wants to pay or finish the order.
+- none: anything else, or when you are not sure which product or category they mean. Never guess.
+
+Menu (product_id | name | category | price in KES):
+`
+
+// OpenAIInterpreter maps customer messages to bot actions with an OpenAI-compatible chat completions API
+type OpenAIInterpreter struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// chatCompletionRequest is the body of POST /chat/completions
+type chatCompletionRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	MaxTokens      int               `json:"max_tokens"`
+	ResponseFormat map[string]string `json:"response_format"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatCompletionResponse is the part of the chat completions response the interpreter reads
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// NewOpenAIInterpreter creates an interpreter for an OpenAI-compatible API at baseURL (e.g.
+// https://api.openai.com/v1); timeout bounds each call so a slow model doesn't hold up the bot
+func NewOpenAIInterpreter(baseURL string, apiKey string, model string, timeout time.Duration) (*OpenAIInterpreter, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("NLU_API_KEY is required but not set")
+	}
+	if timeout <= 0 {
+		timeout = 4 * time.Second
+	}
+
+	return &OpenAIInterpreter{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+// Interpret asks the model which action a message asks for, given the live menu
+func (c *OpenAIInterpreter) Interpret(ctx context.Context, message string, menu map[string][]*core.Product) (*core.InterpretedMessage, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: openAISystemPrompt + formatMenu(menu)},
+			{Role: "user", Content: message},
+		},
+		Temperature:    0,
+		MaxTokens:      100,
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat completion request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call chat completions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("chat completions API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result chatCompletionResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse chat completion response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
+	}
+
+	var interpreted core.InterpretedMessage
+	content := strings.TrimSpace(result.Choices[0].Message.Content)
+	if err := json.Unmarshal([]byte(content), &interpreted); err != nil {
+		return nil, fmt.Errorf("failed to parse interpreted action %q: %w", content, err)
+	}
+	return &interpreted, nil
+}
+
+// formatMenu lists the menu one product per line, by category then name
+func formatMenu(menu map[string][]*core.Product) string {
+	categories := make([]string, 0, len(menu))
+	for category := range menu {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var b strings.Builder
+	for _, category := range categories {
+		products := append([]*core.Product(nil), menu[category]...)
+		sort.Slice(products, func(i, j int) bool { return products[i].Name < products[j].Name })
+		for _, product := range products {
+			fmt.Fprintf(&b, "%s | %s | %s | %.0f\n", product.ID, product.Name, category, product.Price)
+		}
+	}
+	return b.String()
+}
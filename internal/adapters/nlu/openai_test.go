@@ -0,0 +1,101 @@
+package nlu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+var testMenu = map[string][]*core.Product{
+	"Beer":     {{ID: "tusker", Name: "Tusker", Price: 300}},
+	"Cocktail": {{ID: "mojito", Name: "Mojito", Price: 800}, {ID: "dawa", Name: "Dawa", Price: 700}},
+}
+
+// newTestInterpreter points an interpreter at a server answering every chat completion with status
+// and body, and returns the requests it received
+func newTestInterpreter(t *testing.T, status int, body string) (*OpenAIInterpreter, <-chan chatCompletionRequest) {
+	t.Helper()
+
+	requests := make(chan chatCompletionRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("request to %s with Authorization %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests <- req
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	interpreter, err := NewOpenAIInterpreter(server.URL+"/", "key", "gpt-4o-mini", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return interpreter, requests
+}
+
+func TestInterpret(t *testing.T) {
+	interpreter, requests := newTestInterpreter(t, http.StatusOK, `{"choices":[{"message":{"role":"assistant","content":" {\"action\":\"add_item\",\"product_id\":\"mojito\",\"quantity\":2} "}}]}`)
+
+	interpreted, err := interpreter.Interpret(context.Background(), "two mojitos please", testMenu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interpreted.Action != "add_item" || interpreted.ProductID != "mojito" || interpreted.Quantity != 2 {
+		t.Errorf("interpreted %+v, want add_item of 2 mojito", interpreted)
+	}
+
+	req := <-requests
+	if req.Model != "gpt-4o-mini" || len(req.Messages) != 2 || req.Messages[1].Content != "two mojitos please" {
+		t.Fatalf("request %+v, want the message sent to gpt-4o-mini", req)
+	}
+	// The menu goes in the system prompt by category, then name
+	menu := "tusker | Tusker | Beer | 300\ndawa | Dawa | Cocktail | 700\nmojito | Mojito | Cocktail | 800\n"
+	if !strings.HasSuffix(req.Messages[0].Content, menu) {
+		t.Errorf("system prompt %q doesn't end with the menu %q", req.Messages[0].Content, menu)
+	}
+}
+
+func TestInterpretFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "API error", status: http.StatusTooManyRequests, body: `{"error":{"message":"rate limited"}}`},
+		{name: "no choices", status: http.StatusOK, body: `{"choices":[]}`},
+		{name: "content isn't JSON", status: http.StatusOK, body: `{"choices":[{"message":{"content":"Sure! Two mojitos."}}]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			interpreter, _ := newTestInterpreter(t, tc.status, tc.body)
+			if interpreted, err := interpreter.Interpret(context.Background(), "two mojitos", testMenu); err == nil {
+				t.Errorf("got %+v, want an error", interpreted)
+			}
+		})
+	}
+}
+
+func TestNewOpenAIInterpreter(t *testing.T) {
+	if _, err := NewOpenAIInterpreter("https://api.openai.com/v1", "", "gpt-4o-mini", time.Second); err == nil {
+		t.Error("missing API key: got no error")
+	}
+
+	interpreter, err := NewOpenAIInterpreter("https://api.openai.com/v1/", "key", "gpt-4o-mini", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interpreter.baseURL != "https://api.openai.com/v1" {
+		t.Errorf("base URL %s, want the trailing slash trimmed", interpreter.baseURL)
+	}
+	if interpreter.httpClient.Timeout != 4*time.Second {
+		t.Errorf("timeout %s, want the 4s default", interpreter.httpClient.Timeout)
+	}
+}
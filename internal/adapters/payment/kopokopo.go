@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/config"
@@ -23,9 +24,10 @@ import (
 
 // stkPayload represents a queued STK Push request
 type stkPayload struct {
-	orderID string
-	phone   string
-	amount  float64
+	orderID   string
+	phone     string
+	amount    float64
+	requestID string // Request that queued the push, restored on the worker's context
 }
 
 // Client handles Kopo Kopo payment operations with rate limiting
@@ -43,6 +45,8 @@ type Client struct {
 	tokenMu      sync.Mutex
 	// Rate limiting: queue + worker
 	requestQueue chan stkPayload
+	persistent   atomic.Pointer[persistentQueue] // Redis-backed queue; requestQueue is then only a fallback
+	attempts     atomic.Pointer[stkAttemptRecorder]
 	// In-flight request tracking: prevents duplicate STK pushes for same phone
 	inFlightMu     sync.RWMutex
 	inFlightPhones map[string]time.Time // phone -> timestamp when request was sent
@@ -129,7 +133,8 @@ type STKPushRequest struct {
 		Value    string `json:"value"`
 	} `json:"amount"`
 	Metadata struct {
-		OrderID string `json:"order_id"`
+		OrderID   string `json:"order_id"`
+		RequestID string `json:"request_id,omitempty"` // Echoed back in the callback for log correlation
 	} `json:"metadata"`
 	Links struct {
 		CallbackURL string `json:"callback_url"`
@@ -155,7 +160,7 @@ func (c *Client) InitiateSTKPush(ctx context.Context, orderID string, phone stri
 	if lastRequest, exists := c.inFlightPhones[normalizedPhone]; exists {
 		if time.Since(lastRequest) < 60*time.Second {
 			c.inFlightMu.RUnlock()
-			slog.Warn("Duplicate STK push prevented - request already in flight",
+			slog.WarnContext(ctx, "Duplicate STK push prevented - request already in flight",
 				"phone", phone,
 				"order_id", orderID,
 				"last_request_ago", time.Since(lastRequest).String())
@@ -171,10 +176,15 @@ func (c *Client) InitiateSTKPush(ctx context.Context, orderID string, phone stri
 	c.inFlightPhones[normalizedPhone] = time.Now()
 	c.inFlightMu.Unlock()
 
+	if c.enqueuePersistent(ctx, orderID, phone, amount) {
+		return nil
+	}
+
 	payload := stkPayload{
-		orderID: orderID,
-		phone:   phone,
-		amount:  amount,
+		orderID:   orderID,
+		phone:     phone,
+		amount:    amount,
+		requestID: core.RequestIDFrom(ctx),
 	}
 
 	// Non-blocking send: return error if queue is full
@@ -192,6 +202,7 @@ func (c *Client) InitiateSTKPush(ctx context.Context, orderID string, phone stri
 
 // processQueue is the background worker that processes queued STK push requests
 // with rate limiting (2.1 seconds per request = ~28/min, well below 10/20s limit).
+// With the persistent queue each replica sends at most one push per tick.
 func (c *Client) processQueue() {
 	ticker := time.NewTicker(2100 * time.Millisecond) // 2.1 seconds
 	defer ticker.Stop()
@@ -199,17 +210,17 @@ func (c *Client) processQueue() {
 	for {
 		<-ticker.C // Wait for tick
 
-		// Try to get next item from queue (non-blocking)
+		// Try to get next item from queue (non-blocking); in-memory fallback items go first
 		select {
 		case payload := <-c.requestQueue:
 			// Process this STK push request
-			ctx := context.Background()
+			ctx := core.WithRequestID(context.Background(), payload.requestID)
 			if err := c.sendSTKPush(ctx, payload.orderID, payload.phone, payload.amount); err != nil {
-				slog.Error("STK push failed in worker",
+				slog.ErrorContext(ctx, "STK push failed in worker",
 					"order_id", payload.orderID,
 					"error", err.Error())
 			} else {
-				slog.Info("STK push sent successfully",
+				slog.InfoContext(ctx, "STK push sent successfully",
 					"order_id", payload.orderID)
 			}
 
@@ -220,19 +231,29 @@ func (c *Client) processQueue() {
 			delete(c.inFlightPhones, normalizedPhone)
 			c.inFlightMu.Unlock()
 		default:
-			// Queue is empty, continue waiting
+			if pq := c.persistent.Load(); pq != nil {
+				c.processPersistentQueue(context.Background(), pq)
+			}
 		}
 	}
 }
 
-// sendSTKPush sends an M-Pesa STK Push request to Kopo Kopo API (internal worker method).
+// sendSTKPush sends an M-Pesa STK Push request to Kopo Kopo API (internal worker method)
+// and records the attempt when an attempt repository is configured.
 func (c *Client) sendSTKPush(ctx context.Context, orderID string, phone string, amount float64) error {
+	outcome, err := c.requestSTKPush(ctx, orderID, phone, amount)
+	c.recordSTKAttempt(ctx, orderID, phone, amount, outcome, err)
+	return err
+}
+
+// requestSTKPush makes the incoming_payments request, retrying once on an expired token
+func (c *Client) requestSTKPush(ctx context.Context, orderID string, phone string, amount float64) (stkOutcome, error) {
 	// Validate and sanitize phone number
 	// Use format WITHOUT + prefix (254xxxxxxxxx) as this is more compatible with M-Pesa STK
 	// Some phones/SIM cards have issues with the + prefix causing PIN dialog freezes
 	phone, err := sanitizeAndValidatePhoneWithoutPlus(phone)
 	if err != nil {
-		return fmt.Errorf("invalid phone number: %w", err)
+		return stkOutcome{}, fmt.Errorf("%w: %v", errInvalidSTKPhone, err)
 	}
 
 	// Format amount as integer string (Kopo Kopo expects whole numbers for KES)
@@ -251,15 +272,16 @@ func (c *Client) sendSTKPush(ctx context.Context, orderID string, phone string,
 	payload.Amount.Currency = "KES"
 	payload.Amount.Value = amountStr
 	payload.Metadata.OrderID = orderID
+	payload.Metadata.RequestID = core.RequestIDFrom(ctx)
 	payload.Links.CallbackURL = c.callbackURL
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal STK push request: %w", err)
+		return stkOutcome{}, fmt.Errorf("failed to marshal STK push request: %w", err)
 	}
 
 	// Log the exact request being sent (detailed for debugging STK issues)
-	slog.Info("Sending STK push request",
+	slog.InfoContext(ctx, "Sending STK push request",
 		"order_id", orderID,
 		"phone", phone,
 		"phone_prefix", phone[3:6], // Log the prefix (e.g., "708" or "114") for debugging
@@ -271,60 +293,71 @@ func (c *Client) sendSTKPush(ctx context.Context, orderID string, phone string,
 	// Get fresh OAuth token (force refresh if needed)
 	token, err := c.getAccessTokenWithRefresh(ctx)
 	if err != nil {
-		return fmt.Errorf("get access token: %w", err)
+		return stkOutcome{}, fmt.Errorf("get access token: %w", err)
 	}
 
 	// Make API request (correct Kopo Kopo endpoint)
 	apiURL := fmt.Sprintf("%s/api/v1/incoming_payments", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return stkOutcome{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if requestID := core.RequestIDFrom(ctx); requestID != "" {
+		req.Header.Set(core.RequestIDHeader, requestID)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send STK push request: %w", err)
+		return stkOutcome{}, fmt.Errorf("failed to send STK push request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return stkOutcome{HTTPStatus: resp.StatusCode}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Handle API errors with retry on 401 (token expired)
 	if resp.StatusCode == http.StatusUnauthorized {
-		slog.Warn("Token expired, refreshing and retrying", "order_id", orderID)
+		slog.WarnContext(ctx, "Token expired, refreshing and retrying", "order_id", orderID)
 		c.clearCachedToken()
-		return c.sendSTKPush(ctx, orderID, phone, amount) // Retry once with fresh token
+		return c.requestSTKPush(ctx, orderID, phone, amount) // Retry once with fresh token
 	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		slog.Error("Kopo Kopo API error",
+		slog.ErrorContext(ctx, "Kopo Kopo API error",
 			"status", resp.StatusCode,
 			"body", string(body),
 			"order_id", orderID,
 			"phone", phone)
-		return fmt.Errorf("kopokopo API error: status %d, body: %s", resp.StatusCode, string(body))
+		return stkOutcome{HTTPStatus: resp.StatusCode}, &stkAPIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	// Kopo Kopo may return empty body on success (HTTP 201 with Location header)
+	// Kopo Kopo usually returns an empty body on success (HTTP 201) and the incoming payment's
+	// URL in the Location header; its last segment is the ID the callback reports as data.id
+	outcome := stkOutcome{HTTPStatus: resp.StatusCode, Location: resp.Header.Get("Location")}
+	if outcome.Location != "" {
+		outcome.PaymentRequestID = outcome.Location[strings.LastIndex(outcome.Location, "/")+1:]
+	}
 	if len(body) > 0 {
 		var stkResponse STKPushResponse
 		if err := json.Unmarshal(body, &stkResponse); err != nil {
-			slog.Warn("Failed to parse Kopo Kopo response (request was successful)", "error", err.Error(), "body", string(body))
+			slog.WarnContext(ctx, "Failed to parse Kopo Kopo response (request was successful)", "error", err.Error(), "body", string(body))
 		} else {
-			slog.Info("Kopo Kopo STK response", "reference", stkResponse.Reference, "status", stkResponse.Status)
+			slog.InfoContext(ctx, "Kopo Kopo STK response", "reference", stkResponse.Reference, "status", stkResponse.Status)
+			if outcome.PaymentRequestID == "" {
+				outcome.PaymentRequestID = stkResponse.ID
+			}
 		}
 	} else {
-		slog.Info("Kopo Kopo STK push accepted", "order_id", orderID, "status_code", resp.StatusCode)
+		slog.InfoContext(ctx, "Kopo Kopo STK push accepted", "order_id", orderID, "status_code", resp.StatusCode, "location", outcome.Location)
 	}
 
-	return nil
+	return outcome, nil
 }
 
 // clearCachedToken clears the cached OAuth token to force refresh
@@ -373,7 +406,7 @@ func (c *Client) getAccessTokenWithRefresh(ctx context.Context) (string, error)
 		c.accessToken = token
 		c.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
 		c.tokenMu.Unlock()
-		slog.Info("OAuth token refreshed", "expires_in_seconds", expiresIn)
+		slog.InfoContext(ctx, "OAuth token refreshed", "expires_in_seconds", expiresIn)
 		return token, nil
 	}
 
@@ -476,7 +509,8 @@ type IncomingPaymentWebhook struct {
 				Errors interface{} `json:"errors"` // Can be string or null
 			} `json:"event"`
 			Metadata struct {
-				OrderID string `json:"order_id"`
+				OrderID   string `json:"order_id"`
+				RequestID string `json:"request_id"`
 			} `json:"metadata"`
 			Links struct {
 				CallbackURL string `json:"callback_url"`
@@ -524,15 +558,18 @@ func (c *Client) processIncomingPaymentWebhook(payload []byte) (*core.PaymentWeb
 	isSuccess := strings.ToLower(attrs.Status) == "success"
 
 	result := &core.PaymentWebhook{
-		OrderID: attrs.Metadata.OrderID, // We have the order ID directly!
-		Status:  attrs.Status,
-		Success: isSuccess,
+		OrderID:          attrs.Metadata.OrderID, // We have the order ID directly!
+		PaymentRequestID: webhook.Data.ID,        // Matches the Location returned when the push was sent
+		RequestID:        attrs.Metadata.RequestID,
+		Status:           attrs.Status,
+		Success:          isSuccess,
 	}
 
 	// Extract phone and amount from event.resource if available
 	if attrs.Event.Resource != nil {
 		result.Phone = attrs.Event.Resource.SenderPhoneNumber
 		result.Reference = attrs.Event.Resource.Reference
+		result.Currency = attrs.Event.Resource.Currency
 
 		if attrs.Event.Resource.Amount != "" {
 			var amount float64
@@ -576,6 +613,8 @@ func (c *Client) processBuygoodsWebhook(payload []byte) (*core.PaymentWebhook, e
 		Reference:   webhook.Event.Resource.Reference,
 		Phone:       webhook.Event.Resource.SenderPhoneNumber,
 		HashedPhone: webhook.Event.Resource.HashedSenderPhone, // For buygoods webhooks
+		PayerName:   strings.TrimSpace(webhook.Event.Resource.SenderFirstName + " " + webhook.Event.Resource.SenderLastName),
+		Currency:    webhook.Event.Resource.Currency,
 		Success:     isSuccess,
 	}
 
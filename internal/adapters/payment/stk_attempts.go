@@ -0,0 +1,52 @@
+package payment
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// stkOutcome is what Kopo Kopo told us about one STK push request
+type stkOutcome struct {
+	HTTPStatus       int
+	Location         string
+	PaymentRequestID string
+}
+
+// stkAttemptRecorder holds the attempt repository so it can be swapped in while the worker runs
+type stkAttemptRecorder struct {
+	repo core.STKAttemptRepository
+}
+
+// SetAttemptRepository records every STK push sent (and its Kopo Kopo incoming payment ID) in repo
+func (c *Client) SetAttemptRepository(repo core.STKAttemptRepository) {
+	c.attempts.Store(&stkAttemptRecorder{repo: repo})
+}
+
+// recordSTKAttempt stores the outcome of one push. Failures are logged and never fail the push.
+func (c *Client) recordSTKAttempt(ctx context.Context, orderID string, phone string, amount float64, outcome stkOutcome, sendErr error) {
+	recorder := c.attempts.Load()
+	if recorder == nil {
+		return
+	}
+
+	attempt := &core.STKAttempt{
+		OrderID:          orderID,
+		Phone:            strings.TrimPrefix(phone, "+"),
+		Amount:           amount,
+		Status:           core.STKAttemptSent,
+		PaymentRequestID: outcome.PaymentRequestID,
+		Location:         outcome.Location,
+		HTTPStatus:       outcome.HTTPStatus,
+	}
+	if sendErr != nil {
+		attempt.Status = core.STKAttemptRejected
+		attempt.Error = sendErr.Error()
+	}
+
+	if err := recorder.repo.Create(ctx, attempt); err != nil {
+		slog.ErrorContext(ctx, "Failed to record STK attempt", "order_id", orderID, "error", err.Error())
+	}
+}
@@ -0,0 +1,156 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultSTKMaxAttempts is how many times a queued STK push is sent before it is dead-lettered
+	DefaultSTKMaxAttempts = 3
+	// DefaultSTKVisibilityTimeout must outlast a send (30s HTTP timeout plus a token refresh)
+	DefaultSTKVisibilityTimeout = 60 * time.Second
+
+	stkRetryBaseDelay = 5 * time.Second
+	stkRetryMaxDelay  = time.Minute
+	stkSendTimeout    = 45 * time.Second
+)
+
+// errInvalidSTKPhone marks pushes that can never succeed, so they skip retries
+var errInvalidSTKPhone = errors.New("invalid phone number")
+
+// stkAPIError is a non-2xx response from the Kopo Kopo incoming payments API
+type stkAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *stkAPIError) Error() string {
+	return fmt.Sprintf("kopokopo API error: status %d, body: %s", e.StatusCode, e.Body)
+}
+
+// isRetryableSTKError reports whether a push failure is transient: rate limiting, a Kopo Kopo 5xx,
+// or a network/token error. Other 4xx responses and invalid phones will fail the same way again.
+func isRetryableSTKError(err error) bool {
+	var apiErr *stkAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return !errors.Is(err, errInvalidSTKPhone)
+}
+
+// persistentQueue is the Redis-backed STK push queue and its retry policy
+type persistentQueue struct {
+	store       core.STKPushQueue
+	maxAttempts int
+	visibility  time.Duration
+}
+
+// UsePersistentQueue moves queued STK pushes into store so they survive restarts and are shared
+// across replicas. Transient failures are retried with backoff up to maxAttempts, then dead-lettered.
+// The in-memory queue remains as a fallback when the store is unreachable.
+func (c *Client) UsePersistentQueue(store core.STKPushQueue, maxAttempts int, visibility time.Duration) {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultSTKMaxAttempts
+	}
+	if visibility <= 0 {
+		visibility = DefaultSTKVisibilityTimeout
+	}
+	c.persistent.Store(&persistentQueue{store: store, maxAttempts: maxAttempts, visibility: visibility})
+}
+
+// enqueuePersistent queues a push in the persistent queue; false means the caller should use the in-memory queue
+func (c *Client) enqueuePersistent(ctx context.Context, orderID string, phone string, amount float64) bool {
+	pq := c.persistent.Load()
+	if pq == nil {
+		return false
+	}
+
+	job := &core.STKPushJob{
+		ID:        uuid.New().String(),
+		OrderID:   orderID,
+		Phone:     phone,
+		Amount:    amount,
+		RequestID: core.RequestIDFrom(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := pq.store.Enqueue(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "Failed to persist STK push, using in-memory queue", "order_id", orderID, "error", err.Error())
+		return false
+	}
+	return true
+}
+
+// processPersistentQueue recovers abandoned jobs, then sends the next due push
+func (c *Client) processPersistentQueue(ctx context.Context, pq *persistentQueue) {
+	now := time.Now()
+	if requeued, err := pq.store.RequeueExpired(ctx, now); err != nil {
+		slog.ErrorContext(ctx, "Failed to requeue expired STK pushes", "error", err.Error())
+	} else if requeued > 0 {
+		slog.WarnContext(ctx, "Requeued STK pushes whose worker did not finish", "count", requeued)
+	}
+
+	job, err := pq.store.Claim(ctx, now, pq.visibility)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to claim STK push", "error", err.Error())
+		return
+	}
+	if job == nil {
+		return
+	}
+
+	ctx = core.WithRequestID(ctx, job.RequestID)
+	sendCtx, cancel := context.WithTimeout(ctx, stkSendTimeout)
+	err = c.sendSTKPush(sendCtx, job.OrderID, job.Phone, job.Amount)
+	cancel()
+
+	c.inFlightMu.Lock()
+	delete(c.inFlightPhones, strings.TrimPrefix(job.Phone, "+"))
+	c.inFlightMu.Unlock()
+
+	if err == nil {
+		slog.InfoContext(ctx, "STK push sent successfully", "order_id", job.OrderID, "attempt", job.Attempts)
+		if err := pq.store.Ack(ctx, job); err != nil {
+			slog.ErrorContext(ctx, "Failed to ack STK push", "order_id", job.OrderID, "error", err.Error())
+		}
+		return
+	}
+
+	job.LastError = err.Error()
+	if !isRetryableSTKError(err) || job.Attempts >= pq.maxAttempts {
+		failedAt := time.Now()
+		job.FailedAt = &failedAt
+		if dlqErr := pq.store.DeadLetter(ctx, job); dlqErr != nil {
+			slog.ErrorContext(ctx, "Failed to dead-letter STK push", "order_id", job.OrderID, "error", dlqErr.Error())
+		}
+		slog.ErrorContext(ctx, "STK push dead-lettered", "order_id", job.OrderID, "attempts", job.Attempts, "error", err.Error())
+		return
+	}
+
+	retryAt := time.Now().Add(stkRetryDelay(job.Attempts))
+	if retryErr := pq.store.Retry(ctx, job, retryAt); retryErr != nil {
+		slog.ErrorContext(ctx, "Failed to reschedule STK push", "order_id", job.OrderID, "error", retryErr.Error())
+		return
+	}
+	slog.WarnContext(ctx, "STK push failed, will retry", "order_id", job.OrderID, "attempt", job.Attempts, "retry_at", retryAt.Format(time.RFC3339), "error", err.Error())
+}
+
+// stkRetryDelay is exponential backoff (5s, 10s, 20s, ... capped at 1m); the customer is waiting at the bar
+func stkRetryDelay(attempt int) time.Duration {
+	delay := stkRetryBaseDelay
+	for i := 1; i < attempt && delay < stkRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > stkRetryMaxDelay {
+		delay = stkRetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,210 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// webhookSubscriptionRequest is the Kopo Kopo webhook subscription create payload
+type webhookSubscriptionRequest struct {
+	EventType      string `json:"event_type"`
+	URL            string `json:"url"`
+	Scope          string `json:"scope"`
+	ScopeReference string `json:"scope_reference"`
+}
+
+// webhookSubscriptionResource is one subscription as returned by Kopo Kopo. The list endpoint
+// wraps resources JSON:API style (id + attributes); older responses are flat, so both are read.
+type webhookSubscriptionResource struct {
+	ID             string `json:"id"`
+	EventType      string `json:"event_type"`
+	URL            string `json:"url"`
+	WebhookURI     string `json:"webhook_uri"`
+	Scope          string `json:"scope"`
+	ScopeReference string `json:"scope_reference"`
+	Status         string `json:"status"`
+	Attributes     *struct {
+		EventType      string `json:"event_type"`
+		WebhookURI     string `json:"webhook_uri"`
+		Scope          string `json:"scope"`
+		ScopeReference string `json:"scope_reference"`
+		Status         string `json:"status"`
+	} `json:"attributes"`
+}
+
+func (r webhookSubscriptionResource) toDomain() *core.WebhookSubscription {
+	sub := &core.WebhookSubscription{
+		ID:             r.ID,
+		EventType:      r.EventType,
+		URL:            r.URL,
+		Scope:          r.Scope,
+		ScopeReference: r.ScopeReference,
+		Status:         r.Status,
+	}
+	if sub.URL == "" {
+		sub.URL = r.WebhookURI
+	}
+	if a := r.Attributes; a != nil {
+		sub.EventType = firstNonEmpty(sub.EventType, a.EventType)
+		sub.URL = firstNonEmpty(sub.URL, a.WebhookURI)
+		sub.Scope = firstNonEmpty(sub.Scope, a.Scope)
+		sub.ScopeReference = firstNonEmpty(sub.ScopeReference, a.ScopeReference)
+		sub.Status = firstNonEmpty(sub.Status, a.Status)
+	}
+	return sub
+}
+
+// CallbackURL is the payment webhook URL this server expects Kopo Kopo to call
+func (c *Client) CallbackURL() string {
+	return c.callbackURL
+}
+
+// ListWebhookSubscriptions returns the webhook subscriptions registered for this Kopo Kopo account
+func (c *Client) ListWebhookSubscriptions(ctx context.Context) ([]*core.WebhookSubscription, error) {
+	status, body, _, err := c.doSubscriptionRequest(ctx, "GET", "/api/v1/webhook_subscriptions", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("kopokopo API error: status %d, body: %s", status, string(body))
+	}
+
+	// The API has returned both {"data": [...]} and a bare array
+	var resources []webhookSubscriptionResource
+	var wrapped struct {
+		Data []webhookSubscriptionResource `json:"data"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && wrapped.Data != nil {
+		resources = wrapped.Data
+	} else if err := json.Unmarshal(body, &resources); err != nil {
+		return nil, fmt.Errorf("parse webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]*core.WebhookSubscription, len(resources))
+	for i, resource := range resources {
+		subscriptions[i] = resource.toDomain()
+	}
+	return subscriptions, nil
+}
+
+// CreateWebhookSubscription subscribes url to eventType for the configured till
+func (c *Client) CreateWebhookSubscription(ctx context.Context, eventType string, url string) (*core.WebhookSubscription, error) {
+	request := webhookSubscriptionRequest{
+		EventType:      eventType,
+		URL:            url,
+		Scope:          "till",
+		ScopeReference: c.tillNumber,
+	}
+
+	status, body, header, err := c.doSubscriptionRequest(ctx, "POST", "/api/v1/webhook_subscriptions", request)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusCreated && status != http.StatusOK {
+		return nil, fmt.Errorf("kopokopo API error: status %d, body: %s", status, string(body))
+	}
+
+	subscription := &core.WebhookSubscription{
+		EventType:      request.EventType,
+		URL:            request.URL,
+		Scope:          request.Scope,
+		ScopeReference: request.ScopeReference,
+	}
+
+	// Kopo Kopo usually answers 201 with only a Location header pointing at the new subscription
+	if len(bytes.TrimSpace(body)) > 0 {
+		var resource webhookSubscriptionResource
+		if err := json.Unmarshal(body, &resource); err == nil && resource.ID != "" {
+			subscription = resource.toDomain()
+		}
+	}
+	if subscription.ID == "" {
+		if location := header.Get("Location"); location != "" {
+			subscription.ID = location[strings.LastIndex(location, "/")+1:]
+		}
+	}
+
+	slog.InfoContext(ctx, "Kopo Kopo webhook subscription created", "id", subscription.ID, "event_type", eventType, "url", url)
+	return subscription, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription by ID
+func (c *Client) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	status, body, _, err := c.doSubscriptionRequest(ctx, "DELETE", "/api/v1/webhook_subscriptions/"+id, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("webhook subscription not found")
+	}
+	if status != http.StatusOK && status != http.StatusNoContent && status != http.StatusAccepted {
+		return fmt.Errorf("kopokopo API error: status %d, body: %s", status, string(body))
+	}
+
+	slog.InfoContext(ctx, "Kopo Kopo webhook subscription deleted", "id", id)
+	return nil
+}
+
+// doSubscriptionRequest sends an authenticated request to the Kopo Kopo API, retrying once with a
+// fresh OAuth token on 401
+func (c *Client) doSubscriptionRequest(ctx context.Context, method string, path string, payload interface{}) (int, []byte, http.Header, error) {
+	var data []byte
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		data = encoded
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := c.getAccessTokenWithRefresh(ctx)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("get access token: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+path, bytes.NewReader(data))
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("User-Agent", "destination-cocktails/1.0")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("failed to reach Kopo Kopo API: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			c.clearCachedToken()
+			continue
+		}
+		return resp.StatusCode, body, resp.Header, nil
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
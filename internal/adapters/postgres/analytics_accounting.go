@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// GetAccountingEntries lists settled orders placed in [start, end) with their refunds made in [start, end).
+// A refund is a settled order moved to CANCELLED, so refunded orders still count as sales on the day
+// they were placed, and the refund is its own negative line on the day it was made.
+func (r *analyticsRepository) GetAccountingEntries(ctx context.Context, start time.Time, end time.Time) ([]*core.AccountingEntry, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
+
+	var entries []*core.AccountingEntry
+	if err := r.readDB.WithContext(ctx).Raw(`SELECT * FROM (
+			SELECT orders.created_at AS date, orders.id AS order_id, orders.pickup_code,
+				COALESCE(orders.payment_reference, '') AS payment_ref, COALESCE(orders.payment_method, '') AS payment_method,
+				orders.total_amount AS gross, orders.tax_amount AS tax, FALSE AS refund, '' AS note
+			FROM orders
+			WHERE orders.created_at >= ? AND orders.created_at < ?
+				AND (orders.status IN ? OR EXISTS (
+					SELECT 1 FROM order_status_history
+					WHERE order_status_history.order_id = orders.id
+						AND order_status_history.from_status IN ? AND order_status_history.to_status = 'CANCELLED'
+				))
+			UNION ALL
+			SELECT order_status_history.created_at AS date, orders.id AS order_id, orders.pickup_code,
+				COALESCE(orders.payment_reference, '') AS payment_ref, COALESCE(orders.payment_method, '') AS payment_method,
+				-orders.total_amount AS gross, -orders.tax_amount AS tax, TRUE AS refund, COALESCE(order_status_history.note, '') AS note
+			FROM order_status_history
+			JOIN orders ON orders.id = order_status_history.order_id
+			WHERE order_status_history.created_at >= ? AND order_status_history.created_at < ?
+				AND order_status_history.from_status IN ? AND order_status_history.to_status = 'CANCELLED'
+		) AS entries
+		ORDER BY date, order_id, refund`,
+		start, end, settledStatuses, settledStatuses, start, end, settledStatuses).
+		Scan(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get accounting entries: %w", err)
+	}
+	return entries, nil
+}
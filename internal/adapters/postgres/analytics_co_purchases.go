@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// GetCoPurchases counts the settled orders since since that had both productID and each other active
+// product (only products in category, when set), most shared orders first
+func (r *analyticsRepository) GetCoPurchases(ctx context.Context, productID string, category string, since time.Time, limit int) ([]*core.CoPurchase, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	query := r.readDB.WithContext(ctx).Table("order_items AS base").
+		Select("products.id AS product_id, products.name AS product_name, products.category, COUNT(DISTINCT base.order_id) AS orders").
+		Joins("JOIN orders ON orders.id = base.order_id").
+		Joins("JOIN order_items other ON other.order_id = base.order_id AND other.product_id <> base.product_id").
+		Joins("JOIN products ON products.id = other.product_id").
+		Where("base.product_id = ? AND orders.status IN ? AND orders.created_at >= ?", productID, settledStatuses, since).
+		Where("products.is_active = ? AND products.archived_at IS NULL", true)
+	if category != "" {
+		query = query.Where("products.category = ?", category)
+	}
+
+	var purchases []*core.CoPurchase
+	if err := query.
+		Group("products.id, products.name, products.category").
+		Order("orders DESC, products.name").
+		Limit(limit).
+		Scan(&purchases).Error; err != nil {
+		return nil, fmt.Errorf("failed to get co-purchases: %w", err)
+	}
+	return purchases, nil
+}
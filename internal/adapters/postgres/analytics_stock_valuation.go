@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// GetStockValuation lists active products with stock on hand at their cost and menu price. A product's
+// last sale counts cocktails made from it and combos that include it, so a spirit poured only into
+// cocktails isn't taken for dead stock.
+func (r *analyticsRepository) GetStockValuation(ctx context.Context) ([]*core.StockValuation, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
+
+	var valuations []*core.StockValuation
+	if err := r.readDB.WithContext(ctx).Raw(`SELECT products.id AS product_id, products.name AS product_name, products.category,
+			products.stock_quantity, COALESCE(products.cost_price, 0) AS cost_price, products.price,
+			(SELECT MAX(orders.created_at)
+				FROM order_items
+				JOIN orders ON orders.id = order_items.order_id
+				WHERE orders.status IN ?
+					AND (order_items.product_id = products.id
+						OR order_items.product_id IN (SELECT cocktail_product_id FROM recipes WHERE ingredient_product_id = products.id)
+						OR order_items.product_id IN (SELECT bundle_product_id FROM bundle_items WHERE component_product_id = products.id))
+			) AS last_sold_at
+		FROM products
+		WHERE products.is_active AND products.archived_at IS NULL AND products.stock_quantity > 0
+		ORDER BY products.category, products.name`, settledStatuses).
+		Scan(&valuations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get stock valuation: %w", err)
+	}
+	return valuations, nil
+}
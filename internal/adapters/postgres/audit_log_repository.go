@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// auditLogRepository implements AuditLogRepository methods
+type auditLogRepository struct {
+	*Repository
+}
+
+// AuditLogModel represents the audit_logs table structure
+type AuditLogModel struct {
+	ID         string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Actor      string         `gorm:"column:actor;type:varchar(64);not null"`
+	ActorName  sql.NullString `gorm:"column:actor_name;type:varchar(255)"`
+	Role       sql.NullString `gorm:"column:role;type:varchar(20)"`
+	Method     string         `gorm:"column:method;type:varchar(10);not null"`
+	Route      string         `gorm:"column:route;type:varchar(255);not null"`
+	Path       string         `gorm:"column:path;type:varchar(512);not null"`
+	EntityType sql.NullString `gorm:"column:entity_type;type:varchar(64)"`
+	EntityID   sql.NullString `gorm:"column:entity_id;type:varchar(64)"`
+	StatusCode int            `gorm:"column:status_code;type:integer;not null"`
+	Changes    sql.NullString `gorm:"column:changes;type:jsonb"`
+	IP         sql.NullString `gorm:"column:ip;type:varchar(64)"`
+	RequestID  sql.NullString `gorm:"column:request_id;type:varchar(128)"`
+	CreatedAt  time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (AuditLogModel) TableName() string {
+	return "audit_logs"
+}
+
+// ToDomain converts AuditLogModel to core.AuditLog
+func (m *AuditLogModel) ToDomain() *core.AuditLog {
+	entry := &core.AuditLog{
+		ID:         m.ID,
+		Actor:      m.Actor,
+		ActorName:  m.ActorName.String,
+		Role:       m.Role.String,
+		Method:     m.Method,
+		Route:      m.Route,
+		Path:       m.Path,
+		EntityType: m.EntityType.String,
+		EntityID:   m.EntityID.String,
+		StatusCode: m.StatusCode,
+		IP:         m.IP.String,
+		RequestID:  m.RequestID.String,
+		CreatedAt:  m.CreatedAt,
+	}
+	if m.Changes.Valid {
+		entry.Changes = json.RawMessage(m.Changes.String)
+	}
+	return entry
+}
+
+// Record stores one audited admin request
+func (r *auditLogRepository) Record(ctx context.Context, entry *core.AuditLog) error {
+	if entry.ID == "" {
+		entry.ID = r.ids.NewID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = r.clock.Now()
+	}
+
+	model := &AuditLogModel{
+		ID:         entry.ID,
+		Actor:      entry.Actor,
+		ActorName:  sql.NullString{String: entry.ActorName, Valid: entry.ActorName != ""},
+		Role:       sql.NullString{String: entry.Role, Valid: entry.Role != ""},
+		Method:     entry.Method,
+		Route:      entry.Route,
+		Path:       entry.Path,
+		EntityType: sql.NullString{String: entry.EntityType, Valid: entry.EntityType != ""},
+		EntityID:   sql.NullString{String: entry.EntityID, Valid: entry.EntityID != ""},
+		StatusCode: entry.StatusCode,
+		Changes:    sql.NullString{String: string(entry.Changes), Valid: len(entry.Changes) > 0},
+		IP:         sql.NullString{String: entry.IP, Valid: entry.IP != ""},
+		RequestID:  sql.NullString{String: entry.RequestID, Valid: entry.RequestID != ""},
+		CreatedAt:  entry.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("audit_logs").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
+
+// List retrieves audit log entries matching the filter, newest first
+func (r *auditLogRepository) List(ctx context.Context, filter core.AuditLogFilter) ([]*core.AuditLog, error) {
+	query := r.db.WithContext(ctx).Table("audit_logs")
+
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []AuditLogModel
+	if err := query.Order("created_at DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	entries := make([]*core.AuditLog, len(models))
+	for i := range models {
+		entries[i] = models[i].ToDomain()
+	}
+	return entries, nil
+}
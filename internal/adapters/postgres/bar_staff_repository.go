@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// barStaffRepository implements BarStaffRepository methods
+type barStaffRepository struct {
+	*Repository
+}
+
+// BarStaffModel represents the bar_staff table structure
+type BarStaffModel struct {
+	ID             string       `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name           string       `gorm:"column:name;type:varchar(255);not null"`
+	PhoneNumber    string       `gorm:"column:phone_number;type:varchar(20);not null;uniqueIndex"`
+	IsOnShift      bool         `gorm:"column:is_on_shift;type:boolean;not null;default:false"`
+	IsActive       bool         `gorm:"column:is_active;type:boolean;not null;default:true"`
+	LastNotifiedAt sql.NullTime `gorm:"column:last_notified_at;type:timestamp"`
+	CreatedAt      time.Time    `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time    `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (BarStaffModel) TableName() string {
+	return "bar_staff"
+}
+
+// ToDomain converts BarStaffModel to core.BarStaff
+func (b *BarStaffModel) ToDomain() *core.BarStaff {
+	var lastNotifiedAt *time.Time
+	if b.LastNotifiedAt.Valid {
+		t := b.LastNotifiedAt.Time
+		lastNotifiedAt = &t
+	}
+
+	return &core.BarStaff{
+		ID:             b.ID,
+		Name:           b.Name,
+		PhoneNumber:    b.PhoneNumber,
+		IsOnShift:      b.IsOnShift,
+		IsActive:       b.IsActive,
+		LastNotifiedAt: lastNotifiedAt,
+		CreatedAt:      b.CreatedAt,
+	}
+}
+
+func barStaffModelsToDomain(models []BarStaffModel) []*core.BarStaff {
+	staff := make([]*core.BarStaff, len(models))
+	for i := range models {
+		staff[i] = models[i].ToDomain()
+	}
+	return staff
+}
+
+// GetAll retrieves the full roster, including inactive staff
+func (r *barStaffRepository) GetAll(ctx context.Context) ([]*core.BarStaff, error) {
+	var models []BarStaffModel
+	if err := r.db.WithContext(ctx).Table("bar_staff").
+		Order("is_active DESC, name ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bar staff: %w", err)
+	}
+	return barStaffModelsToDomain(models), nil
+}
+
+// GetByID retrieves a bar staff member by ID
+func (r *barStaffRepository) GetByID(ctx context.Context, id string) (*core.BarStaff, error) {
+	var model BarStaffModel
+	if err := r.db.WithContext(ctx).Table("bar_staff").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("bar staff not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get bar staff: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// GetByPhone retrieves a bar staff member by WhatsApp phone number
+func (r *barStaffRepository) GetByPhone(ctx context.Context, phone string) (*core.BarStaff, error) {
+	var model BarStaffModel
+	if err := r.db.WithContext(ctx).Table("bar_staff").
+		Where("RIGHT(regexp_replace(phone_number, '[^0-9]', '', 'g'), 9) = ?", extractLast9Digits(phone)).
+		First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("bar staff not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get bar staff: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// GetOnShift retrieves active on-shift staff, least recently notified first (round-robin order)
+func (r *barStaffRepository) GetOnShift(ctx context.Context) ([]*core.BarStaff, error) {
+	var models []BarStaffModel
+	if err := r.db.WithContext(ctx).Table("bar_staff").
+		Where("is_active = ? AND is_on_shift = ?", true, true).
+		Order("last_notified_at ASC NULLS FIRST, name ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get on-shift bar staff: %w", err)
+	}
+	return barStaffModelsToDomain(models), nil
+}
+
+// Create adds a bar staff member to the roster
+func (r *barStaffRepository) Create(ctx context.Context, staff *core.BarStaff) error {
+	model := &BarStaffModel{
+		ID:          staff.ID,
+		Name:        staff.Name,
+		PhoneNumber: staff.PhoneNumber,
+		IsOnShift:   staff.IsOnShift,
+		IsActive:    staff.IsActive,
+		CreatedAt:   staff.CreatedAt,
+		UpdatedAt:   staff.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("bar_staff").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create bar staff: %w", err)
+	}
+	return nil
+}
+
+// Update saves name, phone, shift and active flags for a bar staff member
+func (r *barStaffRepository) Update(ctx context.Context, staff *core.BarStaff) error {
+	result := r.db.WithContext(ctx).Table("bar_staff").
+		Where("id = ?", staff.ID).
+		Updates(map[string]interface{}{
+			"name":         staff.Name,
+			"phone_number": staff.PhoneNumber,
+			"is_on_shift":  staff.IsOnShift,
+			"is_active":    staff.IsActive,
+			"updated_at":   gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update bar staff: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bar staff not found")
+	}
+	return nil
+}
+
+// MarkNotified stamps last_notified_at so round-robin rotates to the next bartender
+func (r *barStaffRepository) MarkNotified(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Table("bar_staff").
+		Where("id = ?", id).
+		Update("last_notified_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to mark bar staff notified: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// blockedCustomerRepository implements BlockedCustomerRepository methods
+type blockedCustomerRepository struct {
+	*Repository
+}
+
+// BlockedCustomerModel represents the blocked_customers table structure
+type BlockedCustomerModel struct {
+	ID        string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Phone     string    `gorm:"column:phone;type:varchar(20);not null;uniqueIndex"`
+	Status    string    `gorm:"column:status;type:varchar(20);not null;default:'BLOCKED'"`
+	Reason    string    `gorm:"column:reason;type:text;not null;default:''"`
+	CreatedBy string    `gorm:"column:created_by;type:varchar(100);not null;default:''"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (BlockedCustomerModel) TableName() string {
+	return "blocked_customers"
+}
+
+// ToDomain converts BlockedCustomerModel to core.BlockedCustomer
+func (m *BlockedCustomerModel) ToDomain() *core.BlockedCustomer {
+	return &core.BlockedCustomer{
+		ID:        m.ID,
+		Phone:     m.Phone,
+		Status:    core.BlockedCustomerStatus(m.Status),
+		Reason:    m.Reason,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// GetByPhone retrieves the blocklist entry for a phone, or nil when it isn't listed
+func (r *blockedCustomerRepository) GetByPhone(ctx context.Context, phone string) (*core.BlockedCustomer, error) {
+	var model BlockedCustomerModel
+	if err := r.db.WithContext(ctx).Table("blocked_customers").Where("phone = ?", phone).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get blocked customer: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// List retrieves every blocked or flagged phone, most recently changed first
+func (r *blockedCustomerRepository) List(ctx context.Context) ([]*core.BlockedCustomer, error) {
+	var models []BlockedCustomerModel
+	if err := r.db.WithContext(ctx).Table("blocked_customers").Order("updated_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list blocked customers: %w", err)
+	}
+
+	customers := make([]*core.BlockedCustomer, len(models))
+	for i := range models {
+		customers[i] = models[i].ToDomain()
+	}
+	return customers, nil
+}
+
+// Upsert adds a phone to the blocklist or replaces the status and reason of its entry
+func (r *blockedCustomerRepository) Upsert(ctx context.Context, customer *core.BlockedCustomer) error {
+	now := r.clock.Now()
+	if customer.ID == "" {
+		customer.ID = r.ids.NewID()
+	}
+	if customer.CreatedAt.IsZero() {
+		customer.CreatedAt = now
+	}
+	customer.UpdatedAt = now
+
+	model := &BlockedCustomerModel{
+		ID:        customer.ID,
+		Phone:     customer.Phone,
+		Status:    string(customer.Status),
+		Reason:    customer.Reason,
+		CreatedBy: customer.CreatedBy,
+		CreatedAt: customer.CreatedAt,
+		UpdatedAt: customer.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("blocked_customers").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "phone"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "reason", "created_by", "updated_at"}),
+		}).
+		Create(model).Error; err != nil {
+		return fmt.Errorf("failed to save blocked customer: %w", err)
+	}
+
+	// An existing entry keeps its ID and creation time
+	saved, err := r.GetByPhone(ctx, customer.Phone)
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		*customer = *saved
+	}
+	return nil
+}
+
+// Delete removes a phone from the blocklist
+func (r *blockedCustomerRepository) Delete(ctx context.Context, phone string) error {
+	result := r.db.WithContext(ctx).Table("blocked_customers").Where("phone = ?", phone).Delete(&BlockedCustomerModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete blocked customer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("blocked customer not found")
+	}
+	return nil
+}
+
+// CountFailedOrdersSince counts the phone's FAILED orders created since the given time.
+// Phones are matched on their last 9 digits, so 2547..., +2547... and 07... are the same customer.
+func (r *blockedCustomerRepository) CountFailedOrdersSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("orders").
+		Where("status = ? AND created_at >= ?", string(core.OrderStatusFailed), since).
+		Where("RIGHT(regexp_replace(customer_phone, '[^0-9]', '', 'g'), 9) = ?", extractLast9Digits(phone)).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count failed orders: %w", err)
+	}
+	return int(count), nil
+}
@@ -0,0 +1,407 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// broadcastRepository implements BroadcastRepository methods
+type broadcastRepository struct {
+	*Repository
+}
+
+// BroadcastCampaignModel represents the broadcast_campaigns table structure
+type BroadcastCampaignModel struct {
+	ID               string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name             string         `gorm:"column:name;type:varchar(255);not null"`
+	Message          string         `gorm:"column:message;type:text;not null"`
+	TemplateName     sql.NullString `gorm:"column:template_name;type:varchar(255)"`
+	TemplateLanguage sql.NullString `gorm:"column:template_language;type:varchar(10)"`
+	Segment          string         `gorm:"column:segment;type:varchar(20);not null"`
+	SegmentDays      int            `gorm:"column:segment_days;type:integer;not null;default:0"`
+	Status           string         `gorm:"column:status;type:varchar(20);not null;default:'QUEUED'"`
+	TotalRecipients  int            `gorm:"column:total_recipients;type:integer;not null;default:0"`
+	CreatedBy        string         `gorm:"column:created_by;type:varchar(64);not null"`
+	CreatedAt        time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	StartedAt        sql.NullTime   `gorm:"column:started_at;type:timestamp"`
+	CompletedAt      sql.NullTime   `gorm:"column:completed_at;type:timestamp"`
+}
+
+func (BroadcastCampaignModel) TableName() string {
+	return "broadcast_campaigns"
+}
+
+// ToDomain converts BroadcastCampaignModel to core.BroadcastCampaign (without stats)
+func (m *BroadcastCampaignModel) ToDomain() *core.BroadcastCampaign {
+	campaign := &core.BroadcastCampaign{
+		ID:               m.ID,
+		Name:             m.Name,
+		Message:          m.Message,
+		TemplateName:     m.TemplateName.String,
+		TemplateLanguage: m.TemplateLanguage.String,
+		Segment:          core.BroadcastSegment(m.Segment),
+		SegmentDays:      m.SegmentDays,
+		Status:           core.BroadcastStatus(m.Status),
+		Stats:            core.BroadcastStats{Total: m.TotalRecipients},
+		CreatedBy:        m.CreatedBy,
+		CreatedAt:        m.CreatedAt,
+	}
+	if m.StartedAt.Valid {
+		startedAt := m.StartedAt.Time
+		campaign.StartedAt = &startedAt
+	}
+	if m.CompletedAt.Valid {
+		completedAt := m.CompletedAt.Time
+		campaign.CompletedAt = &completedAt
+	}
+	return campaign
+}
+
+// BroadcastRecipientModel represents the broadcast_recipients table structure
+type BroadcastRecipientModel struct {
+	ID         string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CampaignID string         `gorm:"column:campaign_id;type:uuid;not null"`
+	UserID     string         `gorm:"column:user_id;type:uuid;not null"`
+	Phone      string         `gorm:"column:phone;type:varchar(20);not null"`
+	Status     string         `gorm:"column:status;type:varchar(20);not null;default:'PENDING'"`
+	Error      sql.NullString `gorm:"column:error;type:text"`
+	ClaimedAt  sql.NullTime   `gorm:"column:claimed_at;type:timestamp"`
+	SentAt     sql.NullTime   `gorm:"column:sent_at;type:timestamp"`
+	CreatedAt  time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (BroadcastRecipientModel) TableName() string {
+	return "broadcast_recipients"
+}
+
+// ToDomain converts BroadcastRecipientModel to core.BroadcastRecipient
+func (m *BroadcastRecipientModel) ToDomain() *core.BroadcastRecipient {
+	recipient := &core.BroadcastRecipient{
+		ID:         m.ID,
+		CampaignID: m.CampaignID,
+		UserID:     m.UserID,
+		Phone:      m.Phone,
+		Status:     core.BroadcastRecipientStatus(m.Status),
+		Error:      m.Error.String,
+	}
+	if m.SentAt.Valid {
+		sentAt := m.SentAt.Time
+		recipient.SentAt = &sentAt
+	}
+	return recipient
+}
+
+// broadcastSettledStatuses are the order statuses that count as having ordered
+var broadcastSettledStatuses = []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
+
+// broadcastAudience returns the WHERE clause (on users u) and its arguments for a segment's opted-in,
+// unblocked customers. Blocked phones are stored as +254..., so numbers are matched on their last 9 digits.
+func broadcastAudience(segment core.BroadcastSegment, since time.Time) (string, []interface{}, error) {
+	where := `u.marketing_opt_in AND NOT EXISTS (
+		SELECT 1 FROM blocked_customers b
+		WHERE b.status = 'BLOCKED'
+		AND RIGHT(regexp_replace(b.phone, '[^0-9]', '', 'g'), 9) = RIGHT(regexp_replace(u.phone_number, '[^0-9]', '', 'g'), 9))`
+
+	switch segment {
+	case core.BroadcastSegmentAll:
+		return where, nil, nil
+	case core.BroadcastSegmentRecent:
+		return where + ` AND EXISTS (
+			SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.status IN ? AND o.created_at >= ?)`,
+			[]interface{}{broadcastSettledStatuses, since}, nil
+	case core.BroadcastSegmentLapsed:
+		return where + ` AND EXISTS (
+			SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.status IN ? AND o.created_at < ?)
+			AND NOT EXISTS (
+			SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.status IN ? AND o.created_at >= ?)`,
+			[]interface{}{broadcastSettledStatuses, since, broadcastSettledStatuses, since}, nil
+	default:
+		return "", nil, fmt.Errorf("invalid segment %q", segment)
+	}
+}
+
+// Create stores the campaign and queues its audience as PENDING recipients in one transaction
+func (r *broadcastRepository) Create(ctx context.Context, campaign *core.BroadcastCampaign, since time.Time) error {
+	where, args, err := broadcastAudience(campaign.Segment, since)
+	if err != nil {
+		return err
+	}
+	if campaign.ID == "" {
+		campaign.ID = r.ids.NewID()
+	}
+	if campaign.CreatedAt.IsZero() {
+		campaign.CreatedAt = r.clock.Now()
+	}
+	campaign.Status = core.BroadcastQueued
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model := &BroadcastCampaignModel{
+			ID:               campaign.ID,
+			Name:             campaign.Name,
+			Message:          campaign.Message,
+			TemplateName:     sql.NullString{String: campaign.TemplateName, Valid: campaign.TemplateName != ""},
+			TemplateLanguage: sql.NullString{String: campaign.TemplateLanguage, Valid: campaign.TemplateLanguage != ""},
+			Segment:          string(campaign.Segment),
+			SegmentDays:      campaign.SegmentDays,
+			Status:           string(campaign.Status),
+			CreatedBy:        campaign.CreatedBy,
+			CreatedAt:        campaign.CreatedAt,
+		}
+		if err := tx.Table("broadcast_campaigns").Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create broadcast: %w", err)
+		}
+
+		insert := tx.Exec(`INSERT INTO broadcast_recipients (id, campaign_id, user_id, phone, status, created_at)
+			SELECT uuid_generate_v4(), ?, u.id, u.phone_number, 'PENDING', ?
+			FROM users u WHERE `+where,
+			append([]interface{}{campaign.ID, campaign.CreatedAt}, args...)...)
+		if insert.Error != nil {
+			return fmt.Errorf("failed to queue broadcast recipients: %w", insert.Error)
+		}
+
+		total := int(insert.RowsAffected)
+		if err := tx.Table("broadcast_campaigns").Where("id = ?", campaign.ID).
+			Update("total_recipients", total).Error; err != nil {
+			return fmt.Errorf("failed to count broadcast recipients: %w", err)
+		}
+		campaign.Stats = core.BroadcastStats{Total: total, Pending: total}
+		return nil
+	})
+}
+
+// CountAudience counts the customers a campaign for segment would be sent to right now
+func (r *broadcastRepository) CountAudience(ctx context.Context, segment core.BroadcastSegment, since time.Time) (int, error) {
+	where, args, err := broadcastAudience(segment, since)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Table("users u").Where(where, args...).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count broadcast audience: %w", err)
+	}
+	return int(count), nil
+}
+
+// GetAll retrieves recent campaigns, newest first, with delivery stats
+func (r *broadcastRepository) GetAll(ctx context.Context, limit int) ([]*core.BroadcastCampaign, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var models []BroadcastCampaignModel
+	if err := r.db.WithContext(ctx).Table("broadcast_campaigns").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get broadcasts: %w", err)
+	}
+
+	campaigns := make([]*core.BroadcastCampaign, len(models))
+	ids := make([]string, len(models))
+	for i := range models {
+		campaigns[i] = models[i].ToDomain()
+		ids[i] = models[i].ID
+	}
+	if err := r.loadStats(ctx, campaigns, ids); err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// GetByID retrieves a campaign with its delivery stats
+func (r *broadcastRepository) GetByID(ctx context.Context, id string) (*core.BroadcastCampaign, error) {
+	var model BroadcastCampaignModel
+	if err := r.db.WithContext(ctx).Table("broadcast_campaigns").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("broadcast not found")
+		}
+		return nil, fmt.Errorf("failed to get broadcast: %w", err)
+	}
+
+	campaign := model.ToDomain()
+	if err := r.loadStats(ctx, []*core.BroadcastCampaign{campaign}, []string{id}); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// loadStats fills in recipient counts per status for each campaign
+func (r *broadcastRepository) loadStats(ctx context.Context, campaigns []*core.BroadcastCampaign, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		CampaignID string
+		Status     string
+		Count      int
+	}
+	if err := r.db.WithContext(ctx).Table("broadcast_recipients").
+		Select("campaign_id, status, COUNT(*) AS count").
+		Where("campaign_id IN ?", ids).
+		Group("campaign_id, status").
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("failed to get broadcast stats: %w", err)
+	}
+
+	byID := make(map[string]*core.BroadcastStats, len(campaigns))
+	for _, campaign := range campaigns {
+		byID[campaign.ID] = &campaign.Stats
+	}
+	for _, row := range rows {
+		stats, ok := byID[row.CampaignID]
+		if !ok {
+			continue
+		}
+		switch core.BroadcastRecipientStatus(row.Status) {
+		case core.BroadcastRecipientPending, core.BroadcastRecipientSending:
+			stats.Pending += row.Count
+		case core.BroadcastRecipientSent:
+			stats.Sent += row.Count
+		case core.BroadcastRecipientFailed:
+			stats.Failed += row.Count
+		case core.BroadcastRecipientSkipped:
+			stats.Skipped += row.Count
+		case core.BroadcastRecipientCancelled:
+			stats.Cancelled += row.Count
+		}
+	}
+	return nil
+}
+
+// ClaimPending marks the next PENDING recipients of queued or sending campaigns as SENDING and returns them
+func (r *broadcastRepository) ClaimPending(ctx context.Context, limit int) ([]*core.BroadcastRecipient, error) {
+	var models []BroadcastRecipientModel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`SELECT r.* FROM broadcast_recipients r
+			JOIN broadcast_campaigns c ON c.id = r.campaign_id
+			WHERE r.status = 'PENDING' AND c.status IN ('QUEUED', 'SENDING')
+			ORDER BY c.created_at, r.created_at
+			LIMIT ?
+			FOR UPDATE OF r SKIP LOCKED`, limit).Scan(&models).Error; err != nil {
+			return fmt.Errorf("failed to claim broadcast recipients: %w", err)
+		}
+		if len(models) == 0 {
+			return nil
+		}
+
+		now := r.clock.Now()
+		ids := make([]string, len(models))
+		campaignIDs := make([]string, 0, 1)
+		seen := make(map[string]struct{})
+		for i, model := range models {
+			ids[i] = model.ID
+			if _, ok := seen[model.CampaignID]; !ok {
+				seen[model.CampaignID] = struct{}{}
+				campaignIDs = append(campaignIDs, model.CampaignID)
+			}
+		}
+
+		if err := tx.Table("broadcast_recipients").Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":     string(core.BroadcastRecipientSending),
+			"claimed_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to claim broadcast recipients: %w", err)
+		}
+		if err := tx.Table("broadcast_campaigns").
+			Where("id IN ? AND status = ?", campaignIDs, string(core.BroadcastQueued)).
+			Updates(map[string]interface{}{
+				"status":     string(core.BroadcastSending),
+				"started_at": now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to start broadcast: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]*core.BroadcastRecipient, len(models))
+	for i := range models {
+		models[i].Status = string(core.BroadcastRecipientSending)
+		recipients[i] = models[i].ToDomain()
+	}
+	return recipients, nil
+}
+
+// MarkRecipient records the outcome of sending one recipient their message
+func (r *broadcastRepository) MarkRecipient(ctx context.Context, id string, status core.BroadcastRecipientStatus, errMsg string) error {
+	updates := map[string]interface{}{
+		"status": string(status),
+		"error":  sql.NullString{String: errMsg, Valid: errMsg != ""},
+	}
+	if status == core.BroadcastRecipientSent {
+		updates["sent_at"] = r.clock.Now()
+	}
+
+	if err := r.db.WithContext(ctx).Table("broadcast_recipients").
+		Where("id = ? AND status = ?", id, string(core.BroadcastRecipientSending)).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update broadcast recipient: %w", err)
+	}
+	return nil
+}
+
+// FinishCampaigns fails recipients stuck in SENDING (their sender stopped; resending could message them twice)
+// and completes campaigns that have nothing left to send
+func (r *broadcastRepository) FinishCampaigns(ctx context.Context, staleBefore time.Time) error {
+	db := r.db.WithContext(ctx)
+
+	if err := db.Table("broadcast_recipients").
+		Where("status = ? AND claimed_at < ?", string(core.BroadcastRecipientSending), staleBefore).
+		Updates(map[string]interface{}{
+			"status": string(core.BroadcastRecipientFailed),
+			"error":  "sender stopped before confirming delivery",
+		}).Error; err != nil {
+		return fmt.Errorf("failed to expire broadcast recipients: %w", err)
+	}
+
+	if err := db.Exec(`UPDATE broadcast_campaigns c SET status = 'COMPLETED', completed_at = ?
+		WHERE c.status IN ('QUEUED', 'SENDING')
+		AND NOT EXISTS (
+			SELECT 1 FROM broadcast_recipients r
+			WHERE r.campaign_id = c.id AND r.status IN ('PENDING', 'SENDING'))`, r.clock.Now()).Error; err != nil {
+		return fmt.Errorf("failed to complete broadcasts: %w", err)
+	}
+	return nil
+}
+
+// Cancel stops a queued or sending campaign; recipients already being sent finish normally
+func (r *broadcastRepository) Cancel(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("broadcast_campaigns").
+			Where("id = ? AND status IN ?", id, []string{string(core.BroadcastQueued), string(core.BroadcastSending)}).
+			Updates(map[string]interface{}{
+				"status":       string(core.BroadcastCancelled),
+				"completed_at": r.clock.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to cancel broadcast: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Table("broadcast_campaigns").Where("id = ?", id).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to get broadcast: %w", err)
+			}
+			if count == 0 {
+				return fmt.Errorf("broadcast not found")
+			}
+			return fmt.Errorf("broadcast has already finished")
+		}
+
+		if err := tx.Table("broadcast_recipients").
+			Where("campaign_id = ? AND status = ?", id, string(core.BroadcastRecipientPending)).
+			Update("status", string(core.BroadcastRecipientCancelled)).Error; err != nil {
+			return fmt.Errorf("failed to cancel broadcast recipients: %w", err)
+		}
+		return nil
+	})
+}
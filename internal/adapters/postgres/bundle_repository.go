@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// bundleRepository implements BundleRepository methods
+type bundleRepository struct {
+	*Repository
+}
+
+// BundleItemModel represents the bundle_items table structure
+type BundleItemModel struct {
+	ID                 string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	BundleProductID    string    `gorm:"column:bundle_product_id;type:uuid;not null;index"`
+	ComponentProductID string    `gorm:"column:component_product_id;type:uuid;not null"`
+	Quantity           int       `gorm:"column:quantity;type:integer;not null"`
+	CreatedAt          time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (BundleItemModel) TableName() string {
+	return "bundle_items"
+}
+
+// bundleComponentRow is a bundle item joined with its component product's name and stock
+type bundleComponentRow struct {
+	BundleProductID    string `gorm:"column:bundle_product_id"`
+	ComponentProductID string `gorm:"column:component_product_id"`
+	Quantity           int    `gorm:"column:quantity"`
+	ProductName        string `gorm:"column:product_name"`
+	StockQuantity      int    `gorm:"column:stock_quantity"`
+}
+
+// ToDomain converts bundleComponentRow to core.BundleComponent
+func (b *bundleComponentRow) ToDomain() core.BundleComponent {
+	return core.BundleComponent{
+		ProductID:     b.ComponentProductID,
+		ProductName:   b.ProductName,
+		Quantity:      b.Quantity,
+		StockQuantity: b.StockQuantity,
+	}
+}
+
+// fetchComponents loads components (with live stock) for the given combo products, keyed by combo ID
+func (r *bundleRepository) fetchComponents(ctx context.Context, bundleProductIDs []string) (map[string][]core.BundleComponent, error) {
+	componentsByBundle := make(map[string][]core.BundleComponent, len(bundleProductIDs))
+	if len(bundleProductIDs) == 0 {
+		return componentsByBundle, nil
+	}
+
+	var rows []bundleComponentRow
+	if err := r.db.WithContext(ctx).Table("bundle_items").
+		Select("bundle_items.bundle_product_id, bundle_items.component_product_id, bundle_items.quantity, products.name AS product_name, products.stock_quantity").
+		Joins("JOIN products ON products.id = bundle_items.component_product_id").
+		Where("bundle_items.bundle_product_id IN ?", bundleProductIDs).
+		Order("products.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bundle components: %w", err)
+	}
+
+	for i := range rows {
+		componentsByBundle[rows[i].BundleProductID] = append(componentsByBundle[rows[i].BundleProductID], rows[i].ToDomain())
+	}
+	return componentsByBundle, nil
+}
+
+// GetAll retrieves every combo product (active or not) with its components
+func (r *bundleRepository) GetAll(ctx context.Context) ([]*core.Bundle, error) {
+	var productModels []ProductModel
+	if err := r.db.WithContext(ctx).Table("products").
+		Where("category = ?", core.BundleCategory).
+		Order("is_active DESC, name ASC").
+		Find(&productModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bundles: %w", err)
+	}
+
+	ids := make([]string, len(productModels))
+	for i := range productModels {
+		ids[i] = productModels[i].ID
+	}
+
+	componentsByBundle, err := r.fetchComponents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := make([]*core.Bundle, len(productModels))
+	for i := range productModels {
+		components := componentsByBundle[productModels[i].ID]
+		if components == nil {
+			components = []core.BundleComponent{}
+		}
+		bundles[i] = &core.Bundle{
+			Product:    *productModels[i].ToDomain(),
+			Components: components,
+			Available:  core.BundleAvailability(components),
+		}
+	}
+	return bundles, nil
+}
+
+// GetComponents retrieves a combo's components with live stock; empty for regular products
+func (r *bundleRepository) GetComponents(ctx context.Context, bundleProductID string) ([]core.BundleComponent, error) {
+	componentsByBundle, err := r.fetchComponents(ctx, []string{bundleProductID})
+	if err != nil {
+		return nil, err
+	}
+	return componentsByBundle[bundleProductID], nil
+}
+
+// Create inserts the combo product and its components in one transaction
+func (r *bundleRepository) Create(ctx context.Context, bundle *core.Bundle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productModel := &ProductModel{
+			ID:            bundle.Product.ID,
+			Name:          bundle.Product.Name,
+			Description:   sql.NullString{String: bundle.Product.Description, Valid: bundle.Product.Description != ""},
+			Price:         bundle.Product.Price,
+			Category:      core.BundleCategory,
+			StockQuantity: 0, // Combo stock comes from its components
+			ImageURL:      sql.NullString{String: bundle.Product.ImageURL, Valid: bundle.Product.ImageURL != ""},
+			IsActive:      bundle.Product.IsActive,
+		}
+		if err := tx.Table("products").Create(productModel).Error; err != nil {
+			return fmt.Errorf("failed to create bundle product: %w", err)
+		}
+
+		return r.insertComponents(tx, productModel.ID, bundle.Components)
+	})
+}
+
+// SetComponents replaces a combo's components
+func (r *bundleRepository) SetComponents(ctx context.Context, bundleProductID string, components []core.BundleComponent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("bundle_items").Where("bundle_product_id = ?", bundleProductID).Delete(&BundleItemModel{}).Error; err != nil {
+			return fmt.Errorf("failed to clear bundle components: %w", err)
+		}
+		return r.insertComponents(tx, bundleProductID, components)
+	})
+}
+
+func (r *bundleRepository) insertComponents(tx *gorm.DB, bundleProductID string, components []core.BundleComponent) error {
+	now := r.clock.Now()
+	for _, component := range components {
+		item := &BundleItemModel{
+			ID:                 r.ids.NewID(),
+			BundleProductID:    bundleProductID,
+			ComponentProductID: component.ProductID,
+			Quantity:           component.Quantity,
+			CreatedAt:          now,
+		}
+		if err := tx.Table("bundle_items").Create(item).Error; err != nil {
+			return fmt.Errorf("failed to create bundle component: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// favoriteRepository implements FavoriteRepository methods
+type favoriteRepository struct {
+	*Repository
+}
+
+// FavoriteModel represents the favorites table structure
+type FavoriteModel struct {
+	ID        string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    string    `gorm:"column:user_id;type:uuid;not null;index"`
+	Name      string    `gorm:"column:name;type:varchar(60);not null"`
+	Items     string    `gorm:"column:items;type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (FavoriteModel) TableName() string {
+	return "favorites"
+}
+
+// ToDomain converts FavoriteModel to core.Favorite
+func (m *FavoriteModel) ToDomain() *core.Favorite {
+	var items []core.CartItem
+	if err := json.Unmarshal([]byte(m.Items), &items); err != nil {
+		items = nil
+	}
+
+	return &core.Favorite{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Items:     items,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// Create saves a favorite; the unique index on (user_id, lower(name)) rejects a name the user already used
+func (r *favoriteRepository) Create(ctx context.Context, favorite *core.Favorite) error {
+	items, err := json.Marshal(favorite.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal favorite items: %w", err)
+	}
+	if favorite.ID == "" {
+		favorite.ID = r.ids.NewID()
+	}
+	favorite.CreatedAt = r.clock.Now()
+
+	model := &FavoriteModel{
+		ID:        favorite.ID,
+		UserID:    favorite.UserID,
+		Name:      favorite.Name,
+		Items:     string(items),
+		CreatedAt: favorite.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("favorites").Create(model).Error; err != nil {
+		if strings.Contains(err.Error(), "idx_favorites_user_name") {
+			return fmt.Errorf("favorite name already used")
+		}
+		return fmt.Errorf("failed to create favorite: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a favorite by ID
+func (r *favoriteRepository) GetByID(ctx context.Context, id string) (*core.Favorite, error) {
+	var model FavoriteModel
+	if err := r.db.WithContext(ctx).Table("favorites").Where("id = ?", id).Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("favorite not found")
+		}
+		return nil, fmt.Errorf("failed to get favorite: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// ListByUser retrieves a user's favorites, newest first
+func (r *favoriteRepository) ListByUser(ctx context.Context, userID string, limit int) ([]*core.Favorite, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []FavoriteModel
+	if err := r.db.WithContext(ctx).Table("favorites").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+
+	favorites := make([]*core.Favorite, len(models))
+	for i := range models {
+		favorites[i] = models[i].ToDomain()
+	}
+	return favorites, nil
+}
+
+// Delete removes a favorite
+func (r *favoriteRepository) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Table("favorites").Where("id = ?", id).Delete(&FavoriteModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete favorite: %w", err)
+	}
+	return nil
+}
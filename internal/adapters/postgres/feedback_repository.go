@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// feedbackRepository implements FeedbackRepository methods
+type feedbackRepository struct {
+	*Repository
+}
+
+// FeedbackModel represents the order_feedback table structure
+type FeedbackModel struct {
+	ID            string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderID       string         `gorm:"column:order_id;type:uuid;not null;uniqueIndex"`
+	CustomerPhone string         `gorm:"column:customer_phone;type:varchar(20);not null"`
+	Rating        sql.NullInt32  `gorm:"column:rating;type:smallint"`
+	Comment       sql.NullString `gorm:"column:comment;type:text"`
+	RequestedAt   time.Time      `gorm:"column:requested_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	RatedAt       sql.NullTime   `gorm:"column:rated_at;type:timestamp"`
+	PickupCode    string         `gorm:"column:pickup_code;->"` // Read-only, joined from orders
+}
+
+func (FeedbackModel) TableName() string {
+	return "order_feedback"
+}
+
+// ToDomain converts FeedbackModel to core.Feedback
+func (m *FeedbackModel) ToDomain() *core.Feedback {
+	feedback := &core.Feedback{
+		ID:            m.ID,
+		OrderID:       m.OrderID,
+		PickupCode:    m.PickupCode,
+		CustomerPhone: m.CustomerPhone,
+		Rating:        int(m.Rating.Int32),
+		Comment:       m.Comment.String,
+		RequestedAt:   m.RequestedAt,
+	}
+	if m.RatedAt.Valid {
+		ratedAt := m.RatedAt.Time
+		feedback.RatedAt = &ratedAt
+	}
+	return feedback
+}
+
+// feedbackSelect is the column list for feedback joined with its order's pickup code
+const feedbackSelect = "order_feedback.*, orders.pickup_code"
+
+// ClaimDue records a rating request for completed orders not asked yet. The UNIQUE order_id makes
+// the insert the claim, so two replicas never ask about the same order.
+func (r *feedbackRepository) ClaimDue(ctx context.Context, completedAfter time.Time, completedBefore time.Time, limit int) ([]*core.Feedback, error) {
+	var models []FeedbackModel
+	if err := r.db.WithContext(ctx).Raw(`WITH claimed AS (
+			INSERT INTO order_feedback (order_id, customer_phone, requested_at)
+			SELECT o.id, o.customer_phone, ?
+			FROM orders o
+			WHERE o.status IN ? AND o.completed_at >= ? AND o.completed_at < ?
+				AND NOT EXISTS (SELECT 1 FROM order_feedback f WHERE f.order_id = o.id)
+			ORDER BY o.completed_at
+			LIMIT ?
+			ON CONFLICT (order_id) DO NOTHING
+			RETURNING *
+		)
+		SELECT claimed.*, orders.pickup_code FROM claimed JOIN orders ON orders.id = claimed.order_id`,
+		r.clock.Now(),
+		[]string{string(core.OrderStatusCompleted), string(core.OrderStatusDelivered)},
+		completedAfter, completedBefore, limit,
+	).Scan(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim feedback requests: %w", err)
+	}
+
+	feedback := make([]*core.Feedback, len(models))
+	for i := range models {
+		feedback[i] = models[i].ToDomain()
+	}
+	return feedback, nil
+}
+
+// GetLatestUnrated returns the phone's newest unanswered rating request since since
+func (r *feedbackRepository) GetLatestUnrated(ctx context.Context, phone string, since time.Time) (*core.Feedback, error) {
+	var model FeedbackModel
+	if err := r.db.WithContext(ctx).Table("order_feedback").
+		Select(feedbackSelect).
+		Joins("JOIN orders ON orders.id = order_feedback.order_id").
+		Where("order_feedback.customer_phone = ? AND order_feedback.rating IS NULL AND order_feedback.requested_at >= ?", phone, since).
+		Order("order_feedback.requested_at DESC").
+		Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("feedback request not found")
+		}
+		return nil, fmt.Errorf("failed to get feedback request: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// GetByID retrieves feedback by ID
+func (r *feedbackRepository) GetByID(ctx context.Context, id string) (*core.Feedback, error) {
+	var model FeedbackModel
+	if err := r.db.WithContext(ctx).Table("order_feedback").
+		Select(feedbackSelect).
+		Joins("JOIN orders ON orders.id = order_feedback.order_id").
+		Where("order_feedback.id = ?", id).
+		Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("feedback not found")
+		}
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// Rate stores the customer's rating; a request can only be rated once
+func (r *feedbackRepository) Rate(ctx context.Context, id string, rating int) error {
+	result := r.db.WithContext(ctx).Table("order_feedback").
+		Where("id = ? AND rating IS NULL", id).
+		Updates(map[string]interface{}{
+			"rating":   rating,
+			"rated_at": r.clock.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save rating: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("feedback already rated or not found")
+	}
+	return nil
+}
+
+// SetComment stores the optional comment given after the rating
+func (r *feedbackRepository) SetComment(ctx context.Context, id string, comment string) error {
+	result := r.db.WithContext(ctx).Table("order_feedback").
+		Where("id = ?", id).
+		Update("comment", comment)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save feedback comment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("feedback not found")
+	}
+	return nil
+}
+
+// List retrieves rated feedback matching the filter, newest request first
+func (r *feedbackRepository) List(ctx context.Context, filter core.FeedbackFilter) ([]*core.Feedback, error) {
+	query := r.db.WithContext(ctx).Table("order_feedback").
+		Select(feedbackSelect).
+		Joins("JOIN orders ON orders.id = order_feedback.order_id").
+		Where("order_feedback.rating IS NOT NULL")
+
+	if filter.From != nil {
+		query = query.Where("order_feedback.requested_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("order_feedback.requested_at < ?", *filter.To)
+	}
+	if filter.MaxRating > 0 {
+		query = query.Where("order_feedback.rating <= ?", filter.MaxRating)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []FeedbackModel
+	if err := query.Order("order_feedback.requested_at DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+
+	feedback := make([]*core.Feedback, len(models))
+	for i := range models {
+		feedback[i] = models[i].ToDomain()
+	}
+	return feedback, nil
+}
+
+// CountRatings counts requests sent in the range per rating, with 0 for unanswered
+func (r *feedbackRepository) CountRatings(ctx context.Context, start time.Time, end time.Time) (map[int]int, error) {
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+	if err := r.db.WithContext(ctx).Table("order_feedback").
+		Select("COALESCE(rating, 0) AS rating, COUNT(*) AS count").
+		Where("requested_at >= ? AND requested_at < ?", start, end).
+		Group("COALESCE(rating, 0)").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count ratings: %w", err)
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.Rating] = row.Count
+	}
+	return counts, nil
+}
+
+// GetTrend averages ratings per business day of the request
+func (r *feedbackRepository) GetTrend(ctx context.Context, start time.Time, end time.Time, dayOffset time.Duration) ([]*core.FeedbackTrend, error) {
+	var rows []struct {
+		Date          string
+		Responses     int
+		AverageRating float64
+	}
+	if err := r.db.WithContext(ctx).Table("order_feedback").
+		Select("TO_CHAR(requested_at + ? * INTERVAL '1 second', 'YYYY-MM-DD') AS date, COUNT(*) AS responses, AVG(rating) AS average_rating", int64(dayOffset/time.Second)).
+		Where("rating IS NOT NULL AND requested_at >= ? AND requested_at < ?", start, end).
+		Group("date").
+		Order("date ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get feedback trend: %w", err)
+	}
+
+	trend := make([]*core.FeedbackTrend, len(rows))
+	for i, row := range rows {
+		trend[i] = &core.FeedbackTrend{
+			Date:          row.Date,
+			Responses:     row.Responses,
+			AverageRating: row.AverageRating,
+		}
+	}
+	return trend, nil
+}
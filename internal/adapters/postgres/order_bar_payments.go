@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConfirmBarPayment marks a pay-at-the-bar order PAID in full with the method staff collected.
+// The row is locked so two bartenders confirming the same order record a single transition, and
+// queue the paid order's side effects once.
+func (r *orderRepository) ConfirmBarPayment(ctx context.Context, orderID string, method core.PaymentMethod, actor string, note string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current OrderModel
+		if err := tx.Table("orders").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("id = ?", orderID).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("order not found")
+			}
+			return fmt.Errorf("failed to confirm bar payment: %w", err)
+		}
+
+		if core.OrderStatus(current.Status) != core.OrderStatusAwaitingCash {
+			return fmt.Errorf("%w (order is %s)", core.ErrOrderNotAwaitingCash, current.Status)
+		}
+
+		updates := map[string]interface{}{
+			"status":         string(core.OrderStatusPaid),
+			"payment_method": string(method),
+			"amount_paid":    gorm.Expr("total_amount"),
+			"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+		}
+		if err := tx.Table("orders").Where("id = ?", orderID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to confirm bar payment: %w", err)
+		}
+
+		if err := r.recordStatusChange(tx, orderID, core.OrderStatusAwaitingCash, core.OrderStatusPaid, actor, note); err != nil {
+			return err
+		}
+		return r.enqueueOutbox(tx, orderID, paidOrderOutboxTopics(core.OrderStatusPaid))
+	})
+}
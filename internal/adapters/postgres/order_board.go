@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// boardOrderRow is an order on the live board with its latest status change and items as JSON
+type boardOrderRow struct {
+	ID                string    `gorm:"column:id"`
+	PickupCode        string    `gorm:"column:pickup_code"`
+	TableNumber       string    `gorm:"column:table_number"`
+	Status            string    `gorm:"column:status"`
+	Notes             string    `gorm:"column:notes"`
+	DeliveryAddress   string    `gorm:"column:delivery_address"`
+	AcceptedByStaffID string    `gorm:"column:accepted_by_staff_id"`
+	StatusSince       time.Time `gorm:"column:status_since"`
+	CreatedAt         time.Time `gorm:"column:created_at"`
+	Items             string    `gorm:"column:items"`
+}
+
+// GetBoard retrieves PAID and READY orders plus those COMPLETED since completedSince in one query:
+// when each order entered its status comes from order_status_history (falling back to the order's own
+// timestamps for rows older than the history table) and items are aggregated per order.
+func (r *orderRepository) GetBoard(ctx context.Context, completedSince time.Time) ([]*core.BoardOrder, error) {
+	var rows []boardOrderRow
+	if err := r.db.WithContext(ctx).Raw(`SELECT o.id, o.pickup_code, COALESCE(o.table_number, '') AS table_number, o.status,
+			COALESCE(o.notes, '') AS notes, COALESCE(o.delivery_address, '') AS delivery_address,
+			COALESCE(o.accepted_by_staff_id::text, '') AS accepted_by_staff_id, o.created_at,
+			COALESCE(h.changed_at, o.completed_at, o.ready_at, o.created_at) AS status_since,
+			COALESCE(i.items, '[]') AS items
+		FROM orders o
+		LEFT JOIN LATERAL (
+			SELECT MAX(created_at) AS changed_at FROM order_status_history
+			WHERE order_id = o.id AND to_status = o.status
+		) h ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT json_agg(json_build_object(
+				'product_name', COALESCE(p.name, ''),
+				'quantity', oi.quantity,
+				'modifiers', oi.modifiers
+			) ORDER BY oi.created_at) AS items
+			FROM order_items oi
+			LEFT JOIN products p ON p.id = oi.product_id
+			WHERE oi.order_id = o.id
+		) i ON TRUE
+		WHERE o.status IN ? OR (o.status = ? AND o.completed_at >= ?)
+		ORDER BY status_since ASC`,
+		[]string{string(core.OrderStatusPaid), string(core.OrderStatusReady)},
+		string(core.OrderStatusCompleted), completedSince,
+	).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order board: %w", err)
+	}
+
+	orders := make([]*core.BoardOrder, len(rows))
+	for i, row := range rows {
+		order := &core.BoardOrder{
+			ID:                row.ID,
+			PickupCode:        row.PickupCode,
+			TableNumber:       row.TableNumber,
+			Status:            core.OrderStatus(row.Status),
+			Notes:             row.Notes,
+			IsDelivery:        row.DeliveryAddress != "",
+			AcceptedByStaffID: row.AcceptedByStaffID,
+			StatusSince:       row.StatusSince,
+			CreatedAt:         row.CreatedAt,
+		}
+		if err := json.Unmarshal([]byte(row.Items), &order.Items); err != nil {
+			return nil, fmt.Errorf("failed to parse order board items: %w", err)
+		}
+		orders[i] = order
+	}
+	return orders, nil
+}
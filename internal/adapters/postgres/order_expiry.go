@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// GetExpiredPending retrieves PENDING orders created before createdBefore with nothing paid, oldest first
+func (r *orderRepository) GetExpiredPending(ctx context.Context, createdBefore time.Time, limit int) ([]*core.Order, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var orderModels []OrderModel
+	if err := r.db.WithContext(ctx).Table("orders").
+		Where("status = ? AND created_at < ? AND amount_paid = 0", string(core.OrderStatusPending), createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&orderModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get expired pending orders: %w", err)
+	}
+
+	orders := make([]*core.Order, len(orderModels))
+	for i := range orderModels {
+		orders[i] = orderModels[i].ToDomain()
+	}
+	return orders, nil
+}
+
+// CancelExpiredPending moves a PENDING order nobody has paid towards to CANCELLED. The conditional
+// update makes each order expire once even with several replicas polling, and never cancels one a
+// payment reached first. fromTab reports that the order was paying for tab items, which count as
+// unpaid on the tab again.
+func (r *orderRepository) CancelExpiredPending(ctx context.Context, id string, note string) (cancelled bool, fromTab bool, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("orders").
+			Where("id = ? AND status = ? AND amount_paid = 0", id, string(core.OrderStatusPending)).
+			Updates(map[string]interface{}{
+				"status":     string(core.OrderStatusCancelled),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to cancel expired order: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		cancelled = true
+
+		var tabItems int64
+		if err := tx.Table("tab_items").Where("order_id = ?", id).Count(&tabItems).Error; err != nil {
+			return fmt.Errorf("failed to check tab items of expired order: %w", err)
+		}
+		fromTab = tabItems > 0
+
+		return r.recordStatusChange(tx, id, core.OrderStatusPending, core.OrderStatusCancelled, core.OrderActorSystem, note)
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return cancelled, fromTab, nil
+}
@@ -0,0 +1,342 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paidInFullTolerance absorbs float rounding when comparing the amount paid to the order total
+const paidInFullTolerance = 0.005
+
+// PaymentShareModel represents the order_payment_shares table structure
+type PaymentShareModel struct {
+	ID        string       `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderID   string       `gorm:"column:order_id;type:uuid;not null;index"`
+	Phone     string       `gorm:"column:phone;type:varchar(20);not null;default:''"`
+	Amount    float64      `gorm:"column:amount;type:decimal(10,2);not null"`
+	Status    string       `gorm:"column:status;type:varchar(20);not null;default:'PENDING'"`
+	Reference string       `gorm:"column:reference;type:varchar(255);not null;default:''"`
+	PaidAt    sql.NullTime `gorm:"column:paid_at;type:timestamp"`
+	CreatedAt time.Time    `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time    `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (PaymentShareModel) TableName() string {
+	return "order_payment_shares"
+}
+
+// ToDomain converts PaymentShareModel to core.PaymentShare
+func (m *PaymentShareModel) ToDomain() *core.PaymentShare {
+	var paidAt *time.Time
+	if m.PaidAt.Valid {
+		t := m.PaidAt.Time
+		paidAt = &t
+	}
+
+	return &core.PaymentShare{
+		ID:        m.ID,
+		OrderID:   m.OrderID,
+		Phone:     m.Phone,
+		Amount:    m.Amount,
+		Status:    m.Status,
+		Reference: m.Reference,
+		PaidAt:    paidAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// createPaymentShare inserts a share using the caller's transaction
+func (r *orderRepository) createPaymentShare(tx *gorm.DB, share *core.PaymentShare) error {
+	if share.ID == "" {
+		share.ID = r.ids.NewID()
+	}
+	if share.Status == "" {
+		share.Status = core.PaymentSharePending
+	}
+	if share.CreatedAt.IsZero() {
+		share.CreatedAt = r.clock.Now()
+	}
+
+	model := &PaymentShareModel{
+		ID:        share.ID,
+		OrderID:   share.OrderID,
+		Phone:     share.Phone,
+		Amount:    share.Amount,
+		Status:    share.Status,
+		Reference: share.Reference,
+		CreatedAt: share.CreatedAt,
+		UpdatedAt: share.CreatedAt,
+	}
+	if share.PaidAt != nil {
+		model.PaidAt = sql.NullTime{Time: *share.PaidAt, Valid: true}
+	}
+	if err := tx.Table("order_payment_shares").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create payment share: %w", err)
+	}
+	return nil
+}
+
+// GetPaymentShares retrieves an order's payment shares, oldest first
+func (r *orderRepository) GetPaymentShares(ctx context.Context, orderID string) ([]*core.PaymentShare, error) {
+	var models []PaymentShareModel
+	if err := r.db.WithContext(ctx).Table("order_payment_shares").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payment shares: %w", err)
+	}
+
+	shares := make([]*core.PaymentShare, len(models))
+	for i := range models {
+		shares[i] = models[i].ToDomain()
+	}
+	return shares, nil
+}
+
+// ApplyPayment records a confirmed payment against the order and recomputes its status.
+// The payment settles the open split share it matches (phone and amount, then phone, then amount);
+// otherwise it is recorded as a new PAID share. The order row is locked so concurrent callbacks
+// for different shares add up correctly. Reaching PAID or SCHEDULED queues the confirmation side
+// effects in the outbox. A late payment revives an order the expiry job cancelled; one for an order
+// cancelled any other way is left unapplied and reported as RefundRequired.
+func (r *orderRepository) ApplyPayment(ctx context.Context, orderID string, phone string, amount float64, reference string, actor string, note string) (*core.PaymentApplication, error) {
+	var application *core.PaymentApplication
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current OrderModel
+		if err := tx.Table("orders").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status", "total_amount", "amount_paid", "payment_reference", "scheduled_for").
+			Where("id = ?", orderID).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("order not found")
+			}
+			return fmt.Errorf("failed to apply payment: %w", err)
+		}
+
+		if core.OrderStatus(current.Status) == core.OrderStatusCancelled {
+			expired, err := r.cancelledByExpiry(tx, orderID)
+			if err != nil {
+				return err
+			}
+			if !expired {
+				application = &core.PaymentApplication{
+					AmountPaid:     current.AmountPaid,
+					Status:         core.OrderStatusCancelled,
+					RefundRequired: true,
+				}
+				return nil
+			}
+		}
+
+		// Webhook retries carry the same reference; count it once
+		if reference != "" {
+			var existing PaymentShareModel
+			err := tx.Table("order_payment_shares").
+				Where("order_id = ? AND reference = ?", orderID, reference).
+				First(&existing).Error
+			if err == nil {
+				application = &core.PaymentApplication{
+					Share:      existing.ToDomain(),
+					AmountPaid: current.AmountPaid,
+					Status:     core.OrderStatus(current.Status),
+					Duplicate:  true,
+				}
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to apply payment: %w", err)
+			}
+		}
+
+		// A late success for a share already reported FAILED still counts
+		var open []PaymentShareModel
+		if err := tx.Table("order_payment_shares").
+			Where("order_id = ? AND status IN ?", orderID, []string{core.PaymentSharePending, core.PaymentShareFailed}).
+			Order("created_at ASC").
+			Find(&open).Error; err != nil {
+			return fmt.Errorf("failed to apply payment: %w", err)
+		}
+
+		now := r.clock.Now()
+		var share *core.PaymentShare
+		if matched := matchPaymentShare(open, phone, amount); matched != nil {
+			updates := map[string]interface{}{
+				"status":     core.PaymentSharePaid,
+				"amount":     amount,
+				"reference":  reference,
+				"paid_at":    now,
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}
+			if err := tx.Table("order_payment_shares").Where("id = ?", matched.ID).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update payment share: %w", err)
+			}
+			share = matched.ToDomain()
+			share.Status = core.PaymentSharePaid
+			share.Amount = amount
+			share.Reference = reference
+			share.PaidAt = &now
+		} else {
+			share = &core.PaymentShare{
+				OrderID:   orderID,
+				Phone:     phone,
+				Amount:    amount,
+				Status:    core.PaymentSharePaid,
+				Reference: reference,
+				PaidAt:    &now,
+				CreatedAt: now,
+			}
+			if err := r.createPaymentShare(tx, share); err != nil {
+				return err
+			}
+		}
+
+		paid := math.Round((current.AmountPaid+amount)*100) / 100
+		from := core.OrderStatus(current.Status)
+		status := from
+		switch from {
+		// CANCELLED only gets here when the expiry job cancelled it while this payment was on its way
+		case core.OrderStatusPending, core.OrderStatusPartiallyPaid, core.OrderStatusFailed, core.OrderStatusCancelled:
+			if paid >= current.TotalAmount-paidInFullTolerance {
+				status = core.OrderStatusPaid
+				// A pre-order waits for its time; the scheduler releases it to the bar
+				if current.ScheduledFor.Valid && current.ScheduledFor.Time.After(now) {
+					status = core.OrderStatusScheduled
+				}
+			} else {
+				status = core.OrderStatusPartiallyPaid
+			}
+		}
+
+		updates := map[string]interface{}{
+			"amount_paid": paid,
+			"status":      string(status),
+			"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+		}
+		if current.PaymentRef == "" && reference != "" {
+			updates["payment_reference"] = reference
+		}
+		if err := tx.Table("orders").Where("id = ?", orderID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to apply payment: %w", err)
+		}
+
+		application = &core.PaymentApplication{
+			Share:      share,
+			AmountPaid: paid,
+			Status:     status,
+		}
+
+		if status == from {
+			return nil
+		}
+		if err := r.recordStatusChange(tx, orderID, from, status, actor, note); err != nil {
+			return err
+		}
+		return r.enqueueOutbox(tx, orderID, paidOrderOutboxTopics(status))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// cancelledByExpiry reports whether the order's last status change was the expiry job cancelling it
+// unpaid (PENDING to CANCELLED by the system), rather than a manager or customer cancelling it
+func (r *orderRepository) cancelledByExpiry(tx *gorm.DB, orderID string) (bool, error) {
+	var last OrderStatusHistoryModel
+	err := tx.Table("order_status_history").
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		First(&last).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check how order was cancelled: %w", err)
+	}
+	return last.ToStatus == string(core.OrderStatusCancelled) &&
+		last.FromStatus.String == string(core.OrderStatusPending) &&
+		last.Actor == core.OrderActorSystem, nil
+}
+
+// FailPaymentShare marks the PENDING split share matching a failed payment as FAILED.
+// Returns "payment share not found" when the order has no such share (it isn't a split bill).
+func (r *orderRepository) FailPaymentShare(ctx context.Context, orderID string, phone string, amount float64) (*core.PaymentShare, error) {
+	var pending []PaymentShareModel
+	if err := r.db.WithContext(ctx).Table("order_payment_shares").
+		Where("order_id = ? AND status = ?", orderID, core.PaymentSharePending).
+		Order("created_at ASC").
+		Find(&pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payment shares: %w", err)
+	}
+
+	matched := matchPaymentShare(pending, phone, amount)
+	if matched == nil {
+		return nil, fmt.Errorf("payment share not found")
+	}
+
+	if err := r.db.WithContext(ctx).Table("order_payment_shares").
+		Where("id = ? AND status = ?", matched.ID, core.PaymentSharePending).
+		Updates(map[string]interface{}{
+			"status":     core.PaymentShareFailed,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to update payment share: %w", err)
+	}
+
+	share := matched.ToDomain()
+	share.Status = core.PaymentShareFailed
+	return share, nil
+}
+
+// ResetPaymentShare puts a FAILED share back to PENDING so its prompt can be resent
+func (r *orderRepository) ResetPaymentShare(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Table("order_payment_shares").
+		Where("id = ? AND status = ?", id, core.PaymentShareFailed).
+		Updates(map[string]interface{}{
+			"status":     core.PaymentSharePending,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to reset payment share: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("payment share not found")
+	}
+	return nil
+}
+
+// matchPaymentShare picks the share a payment belongs to: same phone and amount, then same phone,
+// then same amount. Callbacks don't always carry the phone, and payers can't change the amount.
+func matchPaymentShare(shares []PaymentShareModel, phone string, amount float64) *PaymentShareModel {
+	digits := extractLast9Digits(phone)
+	samePhone := func(share *PaymentShareModel) bool {
+		return digits != "" && extractLast9Digits(share.Phone) == digits
+	}
+	sameAmount := func(share *PaymentShareModel) bool {
+		return amount > 0 && math.Abs(share.Amount-amount) < paidInFullTolerance
+	}
+
+	rules := []func(*PaymentShareModel) bool{
+		func(share *PaymentShareModel) bool { return samePhone(share) && sameAmount(share) },
+		samePhone,
+		sameAmount,
+	}
+	for _, rule := range rules {
+		for i := range shares {
+			if rule(&shares[i]) {
+				return &shares[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// paidAtSQL is when an order was (first) paid, from its status history. Scheduled orders count from
+// their release to the bar, which is when they become PAID.
+const paidAtSQL = "(SELECT MIN(h.created_at) FROM order_status_history h WHERE h.order_id = orders.id AND h.to_status = 'PAID')"
+
+// GetPrepOverdue retrieves PAID orders that have waited at least paidFor and haven't been flagged, longest waiting first
+func (r *orderRepository) GetPrepOverdue(ctx context.Context, paidFor time.Duration) ([]*core.Order, error) {
+	var orderModels []OrderModel
+	if err := r.db.WithContext(ctx).Table("orders").
+		Where("status = ? AND prep_sla_alerted_at IS NULL AND "+paidAtSQL+" <= ?", string(core.OrderStatusPaid), r.clock.Now().Add(-paidFor)).
+		Order("created_at ASC").
+		Find(&orderModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get overdue paid orders: %w", err)
+	}
+
+	orders := make([]*core.Order, len(orderModels))
+	for i := range orderModels {
+		orders[i] = orderModels[i].ToDomain()
+	}
+	return orders, nil
+}
+
+// ClaimPrepSLAAlert flags an order still PAID after paidFor. The conditional update makes each
+// warning go out once even with several replicas polling.
+func (r *orderRepository) ClaimPrepSLAAlert(ctx context.Context, id string, paidFor time.Duration) (bool, error) {
+	now := r.clock.Now()
+	result := r.db.WithContext(ctx).Table("orders").
+		Where("id = ? AND status = ? AND prep_sla_alerted_at IS NULL AND "+paidAtSQL+" <= ?", id, string(core.OrderStatusPaid), now.Add(-paidFor)).
+		Updates(map[string]interface{}{
+			"prep_sla_alerted_at": now,
+			"updated_at":          gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to flag overdue paid order: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetPrepTimes computes PAID→READY and READY→COMPLETED durations for orders paid in the range, with
+// averages and percentiles for the whole range, per business date and per bartender in one pass
+func (r *analyticsRepository) GetPrepTimes(ctx context.Context, start time.Time, end time.Time, dayOffset time.Duration, sla time.Duration) (*core.PrepTimeReport, error) {
+	type prepTimeRow struct {
+		GroupingSet         int
+		Date                string
+		BartenderID         string
+		BartenderName       string
+		Orders              int
+		AvgPaidToReady      float64
+		P50PaidToReady      float64
+		P90PaidToReady      float64
+		OverSLA             int `gorm:"column:over_sla"`
+		Collected           int
+		AvgReadyToCompleted float64
+		P50ReadyToCompleted float64
+		P90ReadyToCompleted float64
+	}
+
+	slaSeconds := int64(sla / time.Second)
+	if slaSeconds <= 0 {
+		slaSeconds = -1 // No SLA: nothing counts as over it
+	}
+
+	var rows []prepTimeRow
+	if err := r.readDB.WithContext(ctx).Raw(`WITH timings AS (
+			SELECT TO_CHAR(paid.paid_at + ? * INTERVAL '1 second', 'YYYY-MM-DD') AS date,
+				COALESCE(o.ready_by_admin_user_id::text, '') AS bartender_id,
+				COALESCE(a.name, '') AS bartender_name,
+				EXTRACT(EPOCH FROM (o.ready_at - paid.paid_at)) AS paid_to_ready,
+				EXTRACT(EPOCH FROM (o.completed_at - o.ready_at)) AS ready_to_completed
+			FROM orders o
+			JOIN LATERAL (
+				SELECT MIN(h.created_at) AS paid_at FROM order_status_history h
+				WHERE h.order_id = o.id AND h.to_status = 'PAID'
+			) paid ON paid.paid_at IS NOT NULL
+			LEFT JOIN admin_users a ON a.id = o.ready_by_admin_user_id
+			WHERE paid.paid_at >= ? AND paid.paid_at < ? AND o.ready_at >= paid.paid_at
+		)
+		SELECT GROUPING(date, bartender_id) AS grouping_set,
+			COALESCE(date, '') AS date, COALESCE(bartender_id, '') AS bartender_id, MAX(bartender_name) AS bartender_name,
+			COUNT(*) AS orders,
+			COALESCE(AVG(paid_to_ready), 0) AS avg_paid_to_ready,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY paid_to_ready), 0) AS p50_paid_to_ready,
+			COALESCE(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY paid_to_ready), 0) AS p90_paid_to_ready,
+			COUNT(*) FILTER (WHERE ? > 0 AND paid_to_ready > ?) AS over_sla,
+			COUNT(ready_to_completed) AS collected,
+			COALESCE(AVG(ready_to_completed), 0) AS avg_ready_to_completed,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY ready_to_completed), 0) AS p50_ready_to_completed,
+			COALESCE(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY ready_to_completed), 0) AS p90_ready_to_completed
+		FROM timings
+		GROUP BY GROUPING SETS ((), (date), (bartender_id))
+		ORDER BY date ASC, avg_paid_to_ready ASC`,
+		int64(dayOffset/time.Second), start, end, slaSeconds, slaSeconds,
+	).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get preparation times: %w", err)
+	}
+
+	report := &core.PrepTimeReport{
+		Overall:    &core.PrepTimeStats{},
+		Days:       []*core.PrepTimeStats{},
+		Bartenders: []*core.PrepTimeStats{},
+	}
+	for _, row := range rows {
+		stat := &core.PrepTimeStats{
+			Orders:              row.Orders,
+			AvgPaidToReady:      row.AvgPaidToReady,
+			P50PaidToReady:      row.P50PaidToReady,
+			P90PaidToReady:      row.P90PaidToReady,
+			OverSLA:             row.OverSLA,
+			Collected:           row.Collected,
+			AvgReadyToCompleted: row.AvgReadyToCompleted,
+			P50ReadyToCompleted: row.P50ReadyToCompleted,
+			P90ReadyToCompleted: row.P90ReadyToCompleted,
+		}
+		// GROUPING sets a bit for each column rolled up: 1 is per date, 2 per bartender, 3 the whole range
+		switch row.GroupingSet {
+		case 1:
+			stat.Date = row.Date
+			report.Days = append(report.Days, stat)
+		case 2:
+			stat.BartenderID = row.BartenderID
+			stat.BartenderName = row.BartenderName
+			report.Bartenders = append(report.Bartenders, stat)
+		default:
+			report.Overall = stat
+		}
+	}
+	return report, nil
+}
+
+// GetStaffPerformance counts the orders created in the range that each admin user marked ready or
+// completed, with their average paid→ready time and the tips on the orders they prepared
+func (r *analyticsRepository) GetStaffPerformance(ctx context.Context, start time.Time, end time.Time) ([]*core.StaffPerformance, error) {
+	type staffRow struct {
+		AdminUserID        string
+		Name               string
+		Role               string
+		IsActive           bool
+		OrdersReady        int
+		OrdersCompleted    int
+		AvgHandlingSeconds float64
+		Tips               float64
+	}
+
+	var rows []staffRow
+	if err := r.readDB.WithContext(ctx).Raw(`SELECT a.id AS admin_user_id, a.name, a.role, a.is_active,
+			COUNT(*) FILTER (WHERE o.ready_by_admin_user_id = a.id) AS orders_ready,
+			COUNT(*) FILTER (WHERE o.completed_by_admin_user_id = a.id) AS orders_completed,
+			COALESCE(AVG(EXTRACT(EPOCH FROM (o.ready_at - paid.paid_at)))
+				FILTER (WHERE o.ready_by_admin_user_id = a.id AND o.ready_at >= paid.paid_at), 0) AS avg_handling_seconds,
+			COALESCE(SUM(o.tip_amount)
+				FILTER (WHERE COALESCE(o.ready_by_admin_user_id, o.completed_by_admin_user_id) = a.id), 0) AS tips
+		FROM admin_users a
+		JOIN orders o ON o.ready_by_admin_user_id = a.id OR o.completed_by_admin_user_id = a.id
+		LEFT JOIN LATERAL (
+			SELECT MIN(h.created_at) AS paid_at FROM order_status_history h
+			WHERE h.order_id = o.id AND h.to_status = 'PAID'
+		) paid ON TRUE
+		WHERE o.created_at >= ? AND o.created_at < ?
+		GROUP BY a.id, a.name, a.role, a.is_active
+		ORDER BY COUNT(*) DESC, a.name ASC`,
+		start, end,
+	).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get staff performance: %w", err)
+	}
+
+	staff := make([]*core.StaffPerformance, len(rows))
+	for i, row := range rows {
+		staff[i] = &core.StaffPerformance{
+			AdminUserID:        row.AdminUserID,
+			Name:               row.Name,
+			Role:               row.Role,
+			IsActive:           row.IsActive,
+			OrdersReady:        row.OrdersReady,
+			OrdersCompleted:    row.OrdersCompleted,
+			AvgHandlingSeconds: row.AvgHandlingSeconds,
+			Tips:               row.Tips,
+		}
+	}
+	return staff, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// GetDueScheduled retrieves paid pre-orders wanted at or before dueBy, soonest first
+func (r *orderRepository) GetDueScheduled(ctx context.Context, dueBy time.Time) ([]*core.Order, error) {
+	var orderModels []OrderModel
+	if err := r.db.WithContext(ctx).Table("orders").
+		Where("status = ? AND scheduled_for <= ?", string(core.OrderStatusScheduled), dueBy).
+		Order("scheduled_for ASC").
+		Find(&orderModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled orders: %w", err)
+	}
+
+	orders := make([]*core.Order, len(orderModels))
+	for i := range orderModels {
+		orders[i] = orderModels[i].ToDomain()
+	}
+	return orders, nil
+}
+
+// ReleaseScheduled moves a SCHEDULED pre-order to PAID so it joins the bar queue.
+// The conditional update makes each order go to the bar once even with several replicas polling.
+func (r *orderRepository) ReleaseScheduled(ctx context.Context, id string, note string) (bool, error) {
+	released := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("orders").
+			Where("id = ? AND status = ?", id, string(core.OrderStatusScheduled)).
+			Updates(map[string]interface{}{
+				"status":     string(core.OrderStatusPaid),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to release scheduled order: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		released = true
+		return r.recordStatusChange(tx, id, core.OrderStatusScheduled, core.OrderStatusPaid, core.OrderActorSystem, note)
+	})
+	if err != nil {
+		return false, err
+	}
+	return released, nil
+}
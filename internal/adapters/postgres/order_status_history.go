@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// OrderStatusHistoryModel represents the order_status_history table structure
+type OrderStatusHistoryModel struct {
+	ID         string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderID    string         `gorm:"column:order_id;type:uuid;not null;index"`
+	FromStatus sql.NullString `gorm:"column:from_status;type:varchar(20)"`
+	ToStatus   string         `gorm:"column:to_status;type:varchar(20);not null"`
+	Actor      string         `gorm:"column:actor;type:varchar(64);not null;default:'system'"`
+	Note       sql.NullString `gorm:"column:note;type:text"`
+	CreatedAt  time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (OrderStatusHistoryModel) TableName() string {
+	return "order_status_history"
+}
+
+// orderStatusHistoryRow is a history entry joined with the acting admin user's name
+type orderStatusHistoryRow struct {
+	OrderStatusHistoryModel
+	ActorName sql.NullString `gorm:"column:actor_name"`
+}
+
+// ToDomain converts orderStatusHistoryRow to core.OrderStatusChange
+func (h *orderStatusHistoryRow) ToDomain() *core.OrderStatusChange {
+	return &core.OrderStatusChange{
+		ID:         h.ID,
+		OrderID:    h.OrderID,
+		FromStatus: core.OrderStatus(h.FromStatus.String),
+		ToStatus:   core.OrderStatus(h.ToStatus),
+		Actor:      h.Actor,
+		ActorName:  h.ActorName.String,
+		Note:       h.Note.String,
+		CreatedAt:  h.CreatedAt,
+	}
+}
+
+// recordStatusChange writes a history entry using the caller's transaction
+func (r *orderRepository) recordStatusChange(tx *gorm.DB, orderID string, from core.OrderStatus, to core.OrderStatus, actor string, note string) error {
+	if actor == "" {
+		actor = core.OrderActorSystem
+	}
+
+	entry := &OrderStatusHistoryModel{
+		ID:         r.ids.NewID(),
+		OrderID:    orderID,
+		FromStatus: sql.NullString{String: string(from), Valid: from != ""},
+		ToStatus:   string(to),
+		Actor:      actor,
+		Note:       sql.NullString{String: note, Valid: note != ""},
+		CreatedAt:  r.clock.Now(),
+	}
+	if err := tx.Table("order_status_history").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record order status history: %w", err)
+	}
+
+	// Every payment path records its transition here, so paid orders pour their recipes in the same transaction
+	if to == core.OrderStatusPaid || to == core.OrderStatusScheduled {
+		return r.deductRecipeStock(tx, orderID)
+	}
+	return nil
+}
+
+// GetStatusHistory retrieves an order's status timeline, oldest first
+func (r *orderRepository) GetStatusHistory(ctx context.Context, orderID string) ([]*core.OrderStatusChange, error) {
+	var rows []orderStatusHistoryRow
+	if err := r.db.WithContext(ctx).Table("order_status_history AS h").
+		Select("h.*, a.name AS actor_name").
+		Joins("LEFT JOIN admin_users a ON a.id::text = h.actor").
+		Where("h.order_id = ?", orderID).
+		Order("h.created_at ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order status history: %w", err)
+	}
+
+	history := make([]*core.OrderStatusChange, len(rows))
+	for i := range rows {
+		history[i] = rows[i].ToDomain()
+	}
+
+	return history, nil
+}
+
+// isAdminActor reports whether an actor refers to a dashboard user rather than the system or a webhook
+func isAdminActor(actor string) bool {
+	return actor != "" && actor != core.OrderActorSystem && actor != core.OrderActorWebhook
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// outboxRepository implements OutboxRepository methods
+type outboxRepository struct {
+	*Repository
+}
+
+// OutboxMessageModel represents the outbox_messages table structure
+type OutboxMessageModel struct {
+	ID            string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Topic         string         `gorm:"column:topic;type:varchar(50);not null"`
+	OrderID       string         `gorm:"column:order_id;type:uuid;not null"`
+	Status        string         `gorm:"column:status;type:varchar(20);not null;default:'PENDING'"`
+	Attempts      int            `gorm:"column:attempts;type:integer;not null;default:0"`
+	LastError     sql.NullString `gorm:"column:last_error;type:text"`
+	NextAttemptAt time.Time      `gorm:"column:next_attempt_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	CreatedAt     time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	SentAt        sql.NullTime   `gorm:"column:sent_at;type:timestamp"`
+}
+
+func (OutboxMessageModel) TableName() string {
+	return "outbox_messages"
+}
+
+// ToDomain converts OutboxMessageModel to core.OutboxMessage
+func (m *OutboxMessageModel) ToDomain() *core.OutboxMessage {
+	message := &core.OutboxMessage{
+		ID:            m.ID,
+		Topic:         m.Topic,
+		OrderID:       m.OrderID,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError.String,
+		NextAttemptAt: m.NextAttemptAt,
+		CreatedAt:     m.CreatedAt,
+	}
+	if m.SentAt.Valid {
+		sentAt := m.SentAt.Time
+		message.SentAt = &sentAt
+	}
+	return message
+}
+
+// paidOrderOutboxTopics are the side effects queued when an order moves to status
+func paidOrderOutboxTopics(status core.OrderStatus) []string {
+	switch status {
+	case core.OrderStatusPaid:
+		return []string{core.OutboxTopicPaidCustomer, core.OutboxTopicPaidBarStaff, core.OutboxTopicPaidDashboard}
+	case core.OrderStatusScheduled:
+		return []string{core.OutboxTopicScheduledCustomer, core.OutboxTopicScheduledDashboard}
+	default:
+		return nil
+	}
+}
+
+// enqueueOutbox queues an order's side effects using the caller's transaction, so they're stored
+// exactly when the status change that causes them commits
+func (r *Repository) enqueueOutbox(tx *gorm.DB, orderID string, topics []string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	now := r.clock.Now()
+	models := make([]OutboxMessageModel, len(topics))
+	for i, topic := range topics {
+		models[i] = OutboxMessageModel{
+			ID:            r.ids.NewID(),
+			Topic:         topic,
+			OrderID:       orderID,
+			Status:        core.OutboxStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+	if err := tx.Table("outbox_messages").Create(&models).Error; err != nil {
+		return fmt.Errorf("failed to queue outbox messages: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue takes up to limit due PENDING messages and pushes their next attempt lease into the future.
+// SKIP LOCKED lets replicas claim at the same time without taking the same message.
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*core.OutboxMessage, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var models []OutboxMessageModel
+	if err := r.db.WithContext(ctx).Raw(`UPDATE outbox_messages
+		SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_messages
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), core.OutboxStatusPending, now, limit).Scan(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].CreatedAt.Before(models[j].CreatedAt) })
+	messages := make([]*core.OutboxMessage, len(models))
+	for i := range models {
+		messages[i] = models[i].ToDomain()
+	}
+	return messages, nil
+}
+
+// MarkSent records a delivered message
+func (r *outboxRepository) MarkSent(ctx context.Context, id string, at time.Time) error {
+	if err := r.db.WithContext(ctx).Table("outbox_messages").Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     core.OutboxStatusSent,
+			"sent_at":    at,
+			"last_error": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox message sent: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery and when to retry it, or gives up on it when retryAt is nil
+func (r *outboxRepository) MarkFailed(ctx context.Context, id string, errMsg string, retryAt *time.Time) error {
+	updates := map[string]interface{}{"last_error": errMsg}
+	if retryAt != nil {
+		updates["next_attempt_at"] = *retryAt
+	} else {
+		updates["status"] = core.OutboxStatusFailed
+	}
+	if err := r.db.WithContext(ctx).Table("outbox_messages").Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paymentRepository implements PaymentRepository methods
+type paymentRepository struct {
+	*Repository
+}
+
+// PaymentModel represents the payments table structure
+type PaymentModel struct {
+	ID          string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Provider    string         `gorm:"column:provider;type:varchar(50);not null"`
+	Reference   string         `gorm:"column:reference;type:varchar(100);not null;default:''"`
+	Phone       string         `gorm:"column:phone;type:varchar(20);not null;default:''"`
+	HashedPhone string         `gorm:"column:hashed_phone;type:varchar(128);not null;default:''"`
+	PayerName   string         `gorm:"column:payer_name;type:varchar(255);not null;default:''"`
+	Amount      float64        `gorm:"column:amount;type:decimal(10,2);not null"`
+	Currency    string         `gorm:"column:currency;type:varchar(10);not null;default:'KES'"`
+	Status      string         `gorm:"column:status;type:varchar(50);not null;default:''"`
+	OrderID     sql.NullString `gorm:"column:order_id;type:uuid"`
+	IsOrphan    bool           `gorm:"column:is_orphan;type:boolean;not null;default:false"`
+	CreatedAt   time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (PaymentModel) TableName() string {
+	return "payments"
+}
+
+// ToDomain converts PaymentModel to core.Payment
+func (p *PaymentModel) ToDomain() *core.Payment {
+	return &core.Payment{
+		ID:          p.ID,
+		Provider:    p.Provider,
+		Reference:   p.Reference,
+		Phone:       p.Phone,
+		HashedPhone: p.HashedPhone,
+		PayerName:   p.PayerName,
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Status:      p.Status,
+		OrderID:     p.OrderID.String,
+		IsOrphan:    p.IsOrphan,
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+// Create records a payment; a repeat webhook for the same provider+reference is ignored
+func (r *paymentRepository) Create(ctx context.Context, payment *core.Payment) error {
+	currency := payment.Currency
+	if currency == "" {
+		currency = "KES"
+	}
+
+	model := &PaymentModel{
+		ID:          payment.ID,
+		Provider:    payment.Provider,
+		Reference:   payment.Reference,
+		Phone:       payment.Phone,
+		HashedPhone: payment.HashedPhone,
+		PayerName:   payment.PayerName,
+		Amount:      payment.Amount,
+		Currency:    currency,
+		Status:      payment.Status,
+		OrderID:     sql.NullString{String: payment.OrderID, Valid: payment.OrderID != ""},
+		IsOrphan:    payment.IsOrphan,
+		CreatedAt:   payment.CreatedAt,
+		UpdatedAt:   payment.CreatedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Table("payments").
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "provider"}, {Name: "reference"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "reference <> ''"}}},
+			DoNothing:   true,
+		}).
+		Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+	return nil
+}
+
+// List retrieves payments newest first, narrowed by the given filter
+func (r *paymentRepository) List(ctx context.Context, filter core.PaymentFilter) ([]*core.Payment, error) {
+	query := r.db.WithContext(ctx).Table("payments")
+
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.Reference != "" {
+		query = query.Where("reference ILIKE ?", "%"+filter.Reference+"%")
+	}
+	if filter.Phone != "" {
+		if local := extractLast9Digits(filter.Phone); local != "" {
+			query = query.Where("RIGHT(regexp_replace(phone, '[^0-9]', '', 'g'), 9) = ?", local)
+		}
+	}
+	if filter.OrderID != "" {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Orphan != nil {
+		query = query.Where("is_orphan = ?", *filter.Orphan)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []PaymentModel
+	if err := query.Order("created_at DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	payments := make([]*core.Payment, len(models))
+	for i := range models {
+		payments[i] = models[i].ToDomain()
+	}
+	return payments, nil
+}
+
+// ListBetween retrieves every payment received in [start, end), oldest first, for reconciliation
+func (r *paymentRepository) ListBetween(ctx context.Context, start time.Time, end time.Time) ([]*core.Payment, error) {
+	var models []PaymentModel
+	if err := r.db.WithContext(ctx).Table("payments").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	payments := make([]*core.Payment, len(models))
+	for i := range models {
+		payments[i] = models[i].ToDomain()
+	}
+	return payments, nil
+}
+
+// GetByID retrieves a payment by ID
+func (r *paymentRepository) GetByID(ctx context.Context, id string) (*core.Payment, error) {
+	var model PaymentModel
+	if err := r.db.WithContext(ctx).Table("payments").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("payment not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// AttachOrder links an orphaned payment to an order.
+// Only orphaned payments are updated, so two managers can't attach the same payment twice.
+func (r *paymentRepository) AttachOrder(ctx context.Context, id string, orderID string) (bool, error) {
+	result := r.db.WithContext(ctx).Table("payments").
+		Where("id = ? AND is_orphan = ?", id, true).
+		Updates(map[string]interface{}{
+			"order_id":   orderID,
+			"is_orphan":  false,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to attach payment to order: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// PoolConfig sizes a database connection pool; zero values keep the database/sql defaults
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// openPool opens a GORM connection and sizes its pool
+func openPool(dbURL string, pool PoolConfig) (*gorm.DB, error) {
+	// GORM with pgx driver (postgres driver uses pgx under the hood)
+	db, err := gorm.Open(postgres.Open(dbURL), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if pool.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+	if pool.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	}
+	return db, nil
+}
+
+// SetReadReplica routes analytics and report queries to a read replica, keeping that load off the
+// primary. Reports may lag the primary by the replica's replication delay.
+func (r *Repository) SetReadReplica(replicaURL string, pool PoolConfig) error {
+	db, err := openPool(replicaURL, pool)
+	if err != nil {
+		return fmt.Errorf("failed to connect to read replica: %w", err)
+	}
+	r.readDB = db
+	return nil
+}
+
+// HasReadReplica reports whether report queries go to a read replica
+func (r *Repository) HasReadReplica() bool {
+	return r.readDB != r.db
+}
+
+// PingReadReplica checks the read replica connection is alive (the primary when none is configured)
+func (r *Repository) PingReadReplica(ctx context.Context) error {
+	sqlDB, err := r.readDB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get read replica handle: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PoolStats returns connection pool statistics keyed by pool: "primary", and "replica" when configured
+func (r *Repository) PoolStats() map[string]sql.DBStats {
+	stats := make(map[string]sql.DBStats, 2)
+	if sqlDB, err := r.db.DB(); err == nil {
+		stats["primary"] = sqlDB.Stats()
+	}
+	if r.HasReadReplica() {
+		if sqlDB, err := r.readDB.DB(); err == nil {
+			stats["replica"] = sqlDB.Stats()
+		}
+	}
+	return stats
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PriceHistoryModel represents the price_history table structure
+type PriceHistoryModel struct {
+	ID        string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductID string    `gorm:"column:product_id;type:uuid;not null;index"`
+	OldPrice  float64   `gorm:"column:old_price;type:decimal(10,2);not null"`
+	NewPrice  float64   `gorm:"column:new_price;type:decimal(10,2);not null"`
+	Actor     string    `gorm:"column:actor;type:varchar(64);not null"`
+	Source    string    `gorm:"column:source;type:varchar(20);not null;default:'manual'"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (PriceHistoryModel) TableName() string {
+	return "price_history"
+}
+
+// priceHistoryRow is a price change joined with the acting admin user's name
+type priceHistoryRow struct {
+	PriceHistoryModel
+	ActorName string `gorm:"column:actor_name"`
+}
+
+// ToDomain converts priceHistoryRow to core.PriceChange
+func (h *priceHistoryRow) ToDomain() *core.PriceChange {
+	return &core.PriceChange{
+		ID:        h.ID,
+		ProductID: h.ProductID,
+		OldPrice:  h.OldPrice,
+		NewPrice:  h.NewPrice,
+		Actor:     h.Actor,
+		ActorName: h.ActorName,
+		Source:    h.Source,
+		CreatedAt: h.CreatedAt,
+	}
+}
+
+// UpdatePrice updates the price for a product and records the change in the price history
+func (r *productRepository) UpdatePrice(ctx context.Context, id string, price float64, actor string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var product ProductModel
+		if err := tx.Table("products").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "price").
+			Where("id = ?", id).
+			First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("product not found")
+			}
+			return fmt.Errorf("failed to get product price: %w", err)
+		}
+
+		if err := tx.Table("products").
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"price":      price,
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update price: %w", err)
+		}
+
+		return r.recordPriceChange(tx, id, product.Price, price, actor, core.PriceChangeManual)
+	})
+}
+
+// recordPriceChange writes a price history entry using the caller's transaction; unchanged prices aren't recorded
+func (r *productRepository) recordPriceChange(tx *gorm.DB, productID string, oldPrice float64, newPrice float64, actor string, source string) error {
+	if oldPrice == newPrice {
+		return nil
+	}
+
+	entry := &PriceHistoryModel{
+		ID:        r.ids.NewID(),
+		ProductID: productID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		Actor:     actor,
+		Source:    source,
+		CreatedAt: r.clock.Now(),
+	}
+	if err := tx.Table("price_history").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+// GetPriceHistory retrieves a product's price changes, newest first
+func (r *productRepository) GetPriceHistory(ctx context.Context, id string, limit int) ([]*core.PriceChange, error) {
+	var rows []priceHistoryRow
+	if err := r.db.WithContext(ctx).Table("price_history AS h").
+		Select("h.*, COALESCE(a.name, '') AS actor_name").
+		Joins("LEFT JOIN admin_users a ON a.id::text = h.actor").
+		Where("h.product_id = ?", id).
+		Order("h.created_at DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	history := make([]*core.PriceChange, len(rows))
+	for i := range rows {
+		history[i] = rows[i].ToDomain()
+	}
+	return history, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// GetByNames retrieves products whose name exactly matches one of names, keyed by name
+func (r *productRepository) GetByNames(ctx context.Context, names []string) (map[string]*core.Product, error) {
+	products := make(map[string]*core.Product, len(names))
+	if len(names) == 0 {
+		return products, nil
+	}
+
+	var productModels []ProductModel
+	if err := r.db.WithContext(ctx).Table("products").
+		Where("name IN ?", names).
+		Find(&productModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get products by name: %w", err)
+	}
+
+	for i := range productModels {
+		products[productModels[i].Name] = productModels[i].ToDomain()
+	}
+	return products, nil
+}
+
+// UpsertByName applies a product import in one transaction, matching existing rows by name
+// the same way cmd/seeder does. Existing products keep their ID, image and active/archived state.
+// Price changes to existing products are recorded in price_history under actor.
+func (r *productRepository) UpsertByName(ctx context.Context, products []*core.Product, actor string) (int, int, error) {
+	inserted, updated := 0, 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, product := range products {
+			var existing ProductModel
+			if err := tx.Table("products").
+				Select("id", "price").
+				Where("name = ?", product.Name).
+				Limit(1).
+				Scan(&existing).Error; err != nil {
+				return fmt.Errorf("failed to check existing product %s: %w", product.Name, err)
+			}
+
+			if existingID := existing.ID; existingID != "" {
+				if err := tx.Table("products").
+					Where("id = ?", existingID).
+					Updates(map[string]interface{}{
+						"price":          product.Price,
+						"category":       product.Category,
+						"stock_quantity": product.StockQuantity,
+						"description":    sql.NullString{String: product.Description, Valid: product.Description != ""},
+						"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+					}).Error; err != nil {
+					return fmt.Errorf("failed to update product %s: %w", product.Name, err)
+				}
+				if err := r.recordPriceChange(tx, existingID, existing.Price, product.Price, actor, core.PriceChangeImport); err != nil {
+					return err
+				}
+				product.ID = existingID
+				updated++
+				continue
+			}
+
+			if product.ID == "" {
+				product.ID = r.ids.NewID()
+			}
+			productModel := &ProductModel{
+				ID:            product.ID,
+				Name:          product.Name,
+				Description:   sql.NullString{String: product.Description, Valid: product.Description != ""},
+				Price:         product.Price,
+				Category:      product.Category,
+				StockQuantity: product.StockQuantity,
+				IsActive:      true,
+			}
+			if err := tx.Table("products").Create(productModel).Error; err != nil {
+				return fmt.Errorf("failed to insert product %s: %w", product.Name, err)
+			}
+			inserted++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return inserted, updated, nil
+}
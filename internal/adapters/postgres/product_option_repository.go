@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// productOptionRepository implements ProductOptionRepository methods
+type productOptionRepository struct {
+	*Repository
+}
+
+// ProductOptionModel represents the product_options table structure
+type ProductOptionModel struct {
+	ID         string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductID  string    `gorm:"column:product_id;type:uuid;not null;index"`
+	GroupName  string    `gorm:"column:group_name;type:varchar(50);not null"`
+	Label      string    `gorm:"column:label;type:varchar(20);not null"`
+	PriceDelta float64   `gorm:"column:price_delta;type:decimal(10,2);not null;default:0"`
+	SortOrder  int       `gorm:"column:sort_order;type:integer;not null;default:0"`
+	IsActive   bool      `gorm:"column:is_active;type:boolean;not null;default:true"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (ProductOptionModel) TableName() string {
+	return "product_options"
+}
+
+// ToDomain converts ProductOptionModel to core.ProductOption
+func (o *ProductOptionModel) ToDomain() *core.ProductOption {
+	return &core.ProductOption{
+		ID:         o.ID,
+		ProductID:  o.ProductID,
+		GroupName:  o.GroupName,
+		Label:      o.Label,
+		PriceDelta: o.PriceDelta,
+		SortOrder:  o.SortOrder,
+		IsActive:   o.IsActive,
+		CreatedAt:  o.CreatedAt,
+	}
+}
+
+// GetByProductID retrieves all options for a product, including inactive ones, in display order
+func (r *productOptionRepository) GetByProductID(ctx context.Context, productID string) ([]*core.ProductOption, error) {
+	var models []ProductOptionModel
+	if err := r.db.WithContext(ctx).Table("product_options").
+		Where("product_id = ?", productID).
+		Order("sort_order ASC, group_name ASC, label ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get product options: %w", err)
+	}
+
+	options := make([]*core.ProductOption, len(models))
+	for i := range models {
+		options[i] = models[i].ToDomain()
+	}
+	return options, nil
+}
+
+// GetByID retrieves a product option by ID
+func (r *productOptionRepository) GetByID(ctx context.Context, id string) (*core.ProductOption, error) {
+	var model ProductOptionModel
+	if err := r.db.WithContext(ctx).Table("product_options").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("product option not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get product option: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// Create adds a serving option to a product
+func (r *productOptionRepository) Create(ctx context.Context, option *core.ProductOption) error {
+	model := &ProductOptionModel{
+		ID:         option.ID,
+		ProductID:  option.ProductID,
+		GroupName:  option.GroupName,
+		Label:      option.Label,
+		PriceDelta: option.PriceDelta,
+		SortOrder:  option.SortOrder,
+		IsActive:   option.IsActive,
+		CreatedAt:  option.CreatedAt,
+		UpdatedAt:  option.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("product_options").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create product option: %w", err)
+	}
+	return nil
+}
+
+// Update saves group, label, price delta, order and active flag for a product option
+func (r *productOptionRepository) Update(ctx context.Context, option *core.ProductOption) error {
+	result := r.db.WithContext(ctx).Table("product_options").
+		Where("id = ?", option.ID).
+		Updates(map[string]interface{}{
+			"group_name":  option.GroupName,
+			"label":       option.Label,
+			"price_delta": option.PriceDelta,
+			"sort_order":  option.SortOrder,
+			"is_active":   option.IsActive,
+			"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update product option: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product option not found")
+	}
+	return nil
+}
+
+// Delete removes a product option; past orders keep their own copy of chosen modifiers
+func (r *productOptionRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Table("product_options").Where("id = ?", id).Delete(&ProductOptionModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete product option: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product option not found")
+	}
+	return nil
+}
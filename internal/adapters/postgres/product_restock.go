@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// plainProduct matches products whose availability is their own stock_quantity, unlike cocktails
+// made from a recipe and combos made of other products
+const plainProduct = `NOT EXISTS (SELECT 1 FROM recipes WHERE recipes.cocktail_product_id = products.id)
+	AND NOT EXISTS (SELECT 1 FROM bundle_items WHERE bundle_items.bundle_product_id = products.id)`
+
+// visibleWhenSoldOut leaves sold-out products off the menu and search when they're set to hide, by
+// their own hide_when_sold_out or else the menu.hide_out_of_stock setting (off unless a manager sets it)
+const visibleWhenSoldOut = `NOT (stock_quantity <= 0
+	AND COALESCE(hide_when_sold_out, (SELECT value = 'true' FROM settings WHERE key = 'menu.hide_out_of_stock'), false)
+	AND ` + plainProduct + `)`
+
+// restockAlertRepository implements RestockAlertRepository methods
+type restockAlertRepository struct {
+	*Repository
+}
+
+// SetHideWhenSoldOut sets whether a product leaves the menu while sold out; nil follows the setting
+func (r *productRepository) SetHideWhenSoldOut(ctx context.Context, id string, hide *bool) error {
+	result := r.db.WithContext(ctx).Table("products").
+		Where("id = ?", id).
+		Update("hide_when_sold_out", hide)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update sold-out visibility: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+// ClaimRestocked marks menu products that just ran out of stock, then clears the mark of up to limit
+// products that have stock again and returns them. Stock can change through orders, stocktakes,
+// purchase orders and imports, so the mark is what tells a restock apart from any other stock change;
+// SKIP LOCKED keeps replicas from claiming the same product.
+func (r *productRepository) ClaimRestocked(ctx context.Context, limit int) ([]*core.Product, error) {
+	if err := r.db.WithContext(ctx).Exec(`UPDATE products SET sold_out_at = ?
+		WHERE sold_out_at IS NULL AND stock_quantity <= 0 AND is_active AND archived_at IS NULL AND `+plainProduct,
+		r.clock.Now()).Error; err != nil {
+		return nil, fmt.Errorf("failed to mark sold-out products: %w", err)
+	}
+
+	var models []ProductModel
+	if err := r.db.WithContext(ctx).Raw(`UPDATE products SET sold_out_at = NULL
+		WHERE id IN (
+			SELECT id FROM products
+			WHERE sold_out_at IS NOT NULL AND stock_quantity > 0 AND is_active AND archived_at IS NULL
+			ORDER BY sold_out_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, limit).
+		Scan(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim restocked products: %w", err)
+	}
+
+	products := make([]*core.Product, len(models))
+	for i := range models {
+		products[i] = models[i].ToDomain()
+	}
+	return products, nil
+}
+
+// Subscribe records that phone wants to hear when a product is back in stock
+func (r *restockAlertRepository) Subscribe(ctx context.Context, productID string, phone string) error {
+	if err := r.db.WithContext(ctx).Exec(`INSERT INTO restock_alerts (id, product_id, phone, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (product_id, phone) DO NOTHING`, r.ids.NewID(), productID, phone, r.clock.Now()).Error; err != nil {
+		return fmt.Errorf("failed to save restock alert: %w", err)
+	}
+	return nil
+}
+
+// Claim removes a product's alerts and returns the phones that subscribed, earliest first
+func (r *restockAlertRepository) Claim(ctx context.Context, productID string) ([]string, error) {
+	var phones []string
+	if err := r.db.WithContext(ctx).Raw(`WITH claimed AS (
+			DELETE FROM restock_alerts WHERE product_id = ? RETURNING phone, created_at
+		)
+		SELECT phone FROM claimed ORDER BY created_at`, productID).
+		Scan(&phones).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim restock alerts: %w", err)
+	}
+	return phones, nil
+}
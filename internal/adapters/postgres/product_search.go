@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// productSearchMinSimilarity is the pg_trgm similarity a name needs to match a query it doesn't
+	// contain, e.g. "mohito" → "Mojito" (0.4)
+	productSearchMinSimilarity = 0.3
+	// productSearchMinWordSimilarity matches a misspelt word inside a longer name, e.g. "mohito" → "Virgin Mojito"
+	productSearchMinWordSimilarity = 0.5
+)
+
+// SearchProducts finds menu products matching query, best match first. With pg_trgm (migration 045)
+// names that contain the query rank first, then names within a typo of it by trigram similarity;
+// without the extension it falls back to a case-insensitive substring match ordered by name.
+func (r *productRepository) SearchProducts(ctx context.Context, query string) ([]*core.Product, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	searchPattern := "%" + query + "%"
+
+	db := r.db.WithContext(ctx).Table("products").
+		Where("is_active = ? AND archived_at IS NULL", true).
+		Where(visibleWhenSoldOut)
+	if r.hasTrigram(ctx) {
+		db = db.Where("(LOWER(name) LIKE ? OR similarity(LOWER(name), ?) >= ? OR word_similarity(?, LOWER(name)) >= ?)",
+			searchPattern, query, productSearchMinSimilarity, query, productSearchMinWordSimilarity).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:                "LOWER(name) LIKE ? DESC, GREATEST(similarity(LOWER(name), ?), word_similarity(?, LOWER(name))) DESC, name",
+				Vars:               []interface{}{searchPattern, query, query},
+				WithoutParentheses: true,
+			}})
+	} else {
+		db = db.Where("LOWER(name) LIKE ?", searchPattern).Order("name")
+	}
+
+	var productModels []ProductModel
+	if err := db.Find(&productModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	products := make([]*core.Product, len(productModels))
+	for i, pm := range productModels {
+		products[i] = pm.ToDomain()
+	}
+	return products, nil
+}
+
+// hasTrigram reports whether the pg_trgm extension is installed, checked once per process
+func (r *productRepository) hasTrigram(ctx context.Context) bool {
+	r.trigramOnce.Do(func() {
+		if err := r.db.WithContext(ctx).
+			Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')").
+			Scan(&r.trigram).Error; err != nil {
+			log.Printf("Failed to check for pg_trgm, product search will use LIKE: %v", err)
+			return
+		}
+		if !r.trigram {
+			log.Println("pg_trgm isn't installed, product search will use LIKE (see migration 045)")
+		}
+	})
+	return r.trigram
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AdjustStock adds delta to a product's stock and records the change with its reason
+func (r *productRepository) AdjustStock(ctx context.Context, id string, delta int, reason string, actor string) (*core.StockAdjustment, error) {
+	return r.changeStock(ctx, id, reason, actor, func(current int) (int, error) {
+		if current+delta < 0 {
+			return 0, fmt.Errorf("invalid adjustment: only %d in stock", current)
+		}
+		return current + delta, nil
+	})
+}
+
+// CorrectStock sets a product's stock and records the change as a correction
+func (r *productRepository) CorrectStock(ctx context.Context, id string, quantity int, actor string) (*core.StockAdjustment, error) {
+	return r.changeStock(ctx, id, core.StockAdjustmentCorrection, actor, func(int) (int, error) {
+		return quantity, nil
+	})
+}
+
+// changeStock locks the product, sets its stock to next(current) and writes the stock_adjustments row in
+// the same transaction, so concurrent sales and adjustments can't lose each other's changes
+func (r *productRepository) changeStock(ctx context.Context, id string, reason string, actor string, next func(current int) (int, error)) (*core.StockAdjustment, error) {
+	var adjustment *core.StockAdjustment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var product ProductModel
+		if err := tx.Table("products").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "stock_quantity").
+			Where("id = ?", id).
+			First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("product not found")
+			}
+			return fmt.Errorf("failed to get product stock: %w", err)
+		}
+
+		newQuantity, err := next(product.StockQuantity)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Table("products").Where("id = ?", id).Updates(map[string]interface{}{
+			"stock_quantity": newQuantity,
+			"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to adjust stock: %w", err)
+		}
+
+		audit := &StockAdjustmentModel{
+			ID:          r.ids.NewID(),
+			ProductID:   id,
+			OldQuantity: product.StockQuantity,
+			NewQuantity: newQuantity,
+			Reason:      reason,
+			Actor:       actor,
+			CreatedAt:   r.clock.Now(),
+		}
+		if err := tx.Table("stock_adjustments").Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to record stock adjustment: %w", err)
+		}
+
+		adjustment = &core.StockAdjustment{
+			ProductID:   id,
+			OldQuantity: product.StockQuantity,
+			NewQuantity: newQuantity,
+			Reason:      reason,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustment, nil
+}
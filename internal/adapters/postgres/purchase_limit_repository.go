@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// purchaseLimitRepository implements PurchaseLimitRepository methods
+type purchaseLimitRepository struct {
+	*Repository
+}
+
+// PurchaseLimitModel represents the purchase_limits table structure
+type PurchaseLimitModel struct {
+	ID          string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Scope       string    `gorm:"column:scope;type:varchar(20);not null"`
+	Target      string    `gorm:"column:target;type:varchar(100);not null"`
+	Label       string    `gorm:"column:label;->"` // Category name, or the product's name; read-only
+	MaxQuantity int       `gorm:"column:max_quantity;type:integer;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (PurchaseLimitModel) TableName() string {
+	return "purchase_limits"
+}
+
+// ToDomain converts PurchaseLimitModel to core.PurchaseLimit
+func (m *PurchaseLimitModel) ToDomain() *core.PurchaseLimit {
+	label := m.Label
+	if label == "" {
+		label = m.Target
+	}
+	return &core.PurchaseLimit{
+		ID:          m.ID,
+		Scope:       m.Scope,
+		Target:      m.Target,
+		Label:       label,
+		MaxQuantity: m.MaxQuantity,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// List retrieves every limit, categories first, then products, by label
+func (r *purchaseLimitRepository) List(ctx context.Context) ([]*core.PurchaseLimit, error) {
+	var models []PurchaseLimitModel
+	if err := r.db.WithContext(ctx).Raw(`SELECT pl.*, COALESCE(p.name, pl.target) AS label
+		FROM purchase_limits pl
+		LEFT JOIN products p ON pl.scope = ? AND p.id::text = pl.target
+		ORDER BY pl.scope, LOWER(COALESCE(p.name, pl.target))`, core.PurchaseLimitProduct).
+		Scan(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list purchase limits: %w", err)
+	}
+
+	limits := make([]*core.PurchaseLimit, len(models))
+	for i := range models {
+		limits[i] = models[i].ToDomain()
+	}
+	return limits, nil
+}
+
+// Upsert creates the limit, or replaces the max quantity of the one with the same scope and target
+func (r *purchaseLimitRepository) Upsert(ctx context.Context, limit *core.PurchaseLimit) error {
+	now := r.clock.Now()
+	var saved PurchaseLimitModel
+	if err := r.db.WithContext(ctx).Raw(`INSERT INTO purchase_limits (id, scope, target, max_quantity, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (scope, LOWER(target)) DO UPDATE SET max_quantity = EXCLUDED.max_quantity, updated_at = EXCLUDED.updated_at
+		RETURNING *`, r.ids.NewID(), limit.Scope, limit.Target, limit.MaxQuantity, now, now).
+		Scan(&saved).Error; err != nil {
+		return fmt.Errorf("failed to save purchase limit: %w", err)
+	}
+
+	limit.ID = saved.ID
+	limit.Target = saved.Target
+	limit.CreatedAt = saved.CreatedAt
+	limit.UpdatedAt = saved.UpdatedAt
+	return nil
+}
+
+// Delete removes a limit
+func (r *purchaseLimitRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Table("purchase_limits").Where("id = ?", id).Delete(&PurchaseLimitModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete purchase limit: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("purchase limit not found")
+	}
+	return nil
+}
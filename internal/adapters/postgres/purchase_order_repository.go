@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// purchaseOrderRepository implements PurchaseOrderRepository methods
+type purchaseOrderRepository struct {
+	*Repository
+}
+
+// PurchaseOrderModel represents the purchase_orders table structure
+type PurchaseOrderModel struct {
+	ID         string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SupplierID string         `gorm:"column:supplier_id;type:uuid;not null"`
+	Status     string         `gorm:"column:status;type:varchar(20);not null;default:'ORDERED'"`
+	Reference  sql.NullString `gorm:"column:reference;type:varchar(100)"`
+	Notes      sql.NullString `gorm:"column:notes;type:text"`
+	TotalCost  float64        `gorm:"column:total_cost;type:decimal(12,2);not null;default:0"`
+	CreatedBy  string         `gorm:"column:created_by;type:varchar(64);not null"`
+	ReceivedBy sql.NullString `gorm:"column:received_by;type:varchar(64)"`
+	CreatedAt  time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ReceivedAt sql.NullTime   `gorm:"column:received_at;type:timestamp"`
+}
+
+func (PurchaseOrderModel) TableName() string {
+	return "purchase_orders"
+}
+
+// purchaseOrderRow is a purchase order joined with its supplier's name
+type purchaseOrderRow struct {
+	PurchaseOrderModel
+	SupplierName string `gorm:"column:supplier_name"`
+}
+
+// ToDomain converts purchaseOrderRow to core.PurchaseOrder
+func (r *purchaseOrderRow) ToDomain() *core.PurchaseOrder {
+	order := &core.PurchaseOrder{
+		ID:           r.ID,
+		SupplierID:   r.SupplierID,
+		SupplierName: r.SupplierName,
+		Status:       core.PurchaseOrderStatus(r.Status),
+		Reference:    r.Reference.String,
+		Notes:        r.Notes.String,
+		Items:        []core.PurchaseOrderItem{},
+		TotalCost:    r.TotalCost,
+		CreatedBy:    r.CreatedBy,
+		ReceivedBy:   r.ReceivedBy.String,
+		CreatedAt:    r.CreatedAt,
+	}
+	if r.ReceivedAt.Valid {
+		receivedAt := r.ReceivedAt.Time
+		order.ReceivedAt = &receivedAt
+	}
+	return order
+}
+
+// PurchaseOrderItemModel represents the purchase_order_items table structure
+type PurchaseOrderItemModel struct {
+	ID               string        `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PurchaseOrderID  string        `gorm:"column:purchase_order_id;type:uuid;not null;index"`
+	ProductID        string        `gorm:"column:product_id;type:uuid;not null"`
+	Quantity         int           `gorm:"column:quantity;type:integer;not null"`
+	UnitCost         float64       `gorm:"column:unit_cost;type:decimal(10,2);not null"`
+	ReceivedQuantity sql.NullInt64 `gorm:"column:received_quantity;type:integer"`
+}
+
+func (PurchaseOrderItemModel) TableName() string {
+	return "purchase_order_items"
+}
+
+// purchaseOrderItemRow is a purchase order item joined with its product's name
+type purchaseOrderItemRow struct {
+	PurchaseOrderItemModel
+	ProductName string `gorm:"column:product_name"`
+}
+
+// ToDomain converts purchaseOrderItemRow to core.PurchaseOrderItem
+func (r *purchaseOrderItemRow) ToDomain() core.PurchaseOrderItem {
+	item := core.PurchaseOrderItem{
+		ProductID:   r.ProductID,
+		ProductName: r.ProductName,
+		Quantity:    r.Quantity,
+		UnitCost:    r.UnitCost,
+	}
+	if r.ReceivedQuantity.Valid {
+		received := int(r.ReceivedQuantity.Int64)
+		item.ReceivedQuantity = &received
+	}
+	return item
+}
+
+func (r *purchaseOrderRepository) baseQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Table("purchase_orders").
+		Select("purchase_orders.*, suppliers.name AS supplier_name").
+		Joins("JOIN suppliers ON suppliers.id = purchase_orders.supplier_id")
+}
+
+// fetchItems loads the items for the given purchase orders, keyed by purchase order ID
+func (r *purchaseOrderRepository) fetchItems(ctx context.Context, orderIDs []string) (map[string][]core.PurchaseOrderItem, error) {
+	itemsByOrder := make(map[string][]core.PurchaseOrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return itemsByOrder, nil
+	}
+
+	var rows []purchaseOrderItemRow
+	if err := r.db.WithContext(ctx).Table("purchase_order_items").
+		Select("purchase_order_items.*, products.name AS product_name").
+		Joins("JOIN products ON products.id = purchase_order_items.product_id").
+		Where("purchase_order_items.purchase_order_id IN ?", orderIDs).
+		Order("products.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get purchase order items: %w", err)
+	}
+
+	for i := range rows {
+		itemsByOrder[rows[i].PurchaseOrderID] = append(itemsByOrder[rows[i].PurchaseOrderID], rows[i].ToDomain())
+	}
+	return itemsByOrder, nil
+}
+
+// GetAll retrieves recent purchase orders with their items, newest first
+func (r *purchaseOrderRepository) GetAll(ctx context.Context, status core.PurchaseOrderStatus, limit int) ([]*core.PurchaseOrder, error) {
+	query := r.baseQuery(ctx)
+	if status != "" {
+		query = query.Where("purchase_orders.status = ?", string(status))
+	}
+
+	var rows []purchaseOrderRow
+	if err := query.Order("purchase_orders.created_at DESC").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get purchase orders: %w", err)
+	}
+
+	ids := make([]string, len(rows))
+	for i := range rows {
+		ids[i] = rows[i].ID
+	}
+	itemsByOrder, err := r.fetchItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*core.PurchaseOrder, len(rows))
+	for i := range rows {
+		orders[i] = rows[i].ToDomain()
+		if items := itemsByOrder[rows[i].ID]; items != nil {
+			orders[i].Items = items
+		}
+	}
+	return orders, nil
+}
+
+// GetByID retrieves a purchase order with its items
+func (r *purchaseOrderRepository) GetByID(ctx context.Context, id string) (*core.PurchaseOrder, error) {
+	var rows []purchaseOrderRow
+	if err := r.baseQuery(ctx).Where("purchase_orders.id = ?", id).Limit(1).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("purchase order not found")
+	}
+
+	itemsByOrder, err := r.fetchItems(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	order := rows[0].ToDomain()
+	if items := itemsByOrder[id]; items != nil {
+		order.Items = items
+	}
+	return order, nil
+}
+
+// Create inserts a purchase order and its items in one transaction
+func (r *purchaseOrderRepository) Create(ctx context.Context, order *core.PurchaseOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model := &PurchaseOrderModel{
+			ID:         order.ID,
+			SupplierID: order.SupplierID,
+			Status:     string(order.Status),
+			Reference:  sql.NullString{String: order.Reference, Valid: order.Reference != ""},
+			Notes:      sql.NullString{String: order.Notes, Valid: order.Notes != ""},
+			TotalCost:  order.TotalCost,
+			CreatedBy:  order.CreatedBy,
+			CreatedAt:  order.CreatedAt,
+			UpdatedAt:  order.CreatedAt,
+		}
+		if err := tx.Table("purchase_orders").Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create purchase order: %w", err)
+		}
+
+		for _, item := range order.Items {
+			itemModel := &PurchaseOrderItemModel{
+				ID:              r.ids.NewID(),
+				PurchaseOrderID: order.ID,
+				ProductID:       item.ProductID,
+				Quantity:        item.Quantity,
+				UnitCost:        item.UnitCost,
+			}
+			if err := tx.Table("purchase_order_items").Create(itemModel).Error; err != nil {
+				return fmt.Errorf("failed to create purchase order item: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// lockOrderedPurchaseOrder locks a purchase order row and checks it hasn't been received or cancelled
+func lockOrderedPurchaseOrder(tx *gorm.DB, id string) error {
+	var current PurchaseOrderModel
+	if err := tx.Table("purchase_orders").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		Where("id = ?", id).
+		First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("purchase order not found")
+		}
+		return fmt.Errorf("failed to get purchase order: %w", err)
+	}
+	if core.PurchaseOrderStatus(current.Status) != core.PurchaseOrderOrdered {
+		return fmt.Errorf("purchase order is %s, only ORDERED purchase orders can be changed", current.Status)
+	}
+	return nil
+}
+
+// Receive books a supplier delivery into stock. items overrides the received quantity and unit cost per
+// product (short deliveries, price changes); products it leaves out arrive as ordered.
+func (r *purchaseOrderRepository) Receive(ctx context.Context, id string, items []core.PurchaseOrderItem, actor string) ([]core.StockAdjustment, error) {
+	var adjustments []core.StockAdjustment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOrderedPurchaseOrder(tx, id); err != nil {
+			return err
+		}
+
+		var lines []PurchaseOrderItemModel
+		if err := tx.Table("purchase_order_items").Where("purchase_order_id = ?", id).Order("product_id").Find(&lines).Error; err != nil {
+			return fmt.Errorf("failed to get purchase order items: %w", err)
+		}
+
+		overrides := make(map[string]core.PurchaseOrderItem, len(items))
+		for _, item := range items {
+			overrides[item.ProductID] = item
+		}
+		for productID := range overrides {
+			found := false
+			for _, line := range lines {
+				if line.ProductID == productID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("invalid item: product %s is not on this purchase order", productID)
+			}
+		}
+
+		now := r.clock.Now()
+		totalCost := 0.0
+		for _, line := range lines {
+			received, unitCost := line.Quantity, line.UnitCost
+			if override, ok := overrides[line.ProductID]; ok {
+				if override.ReceivedQuantity != nil {
+					received = *override.ReceivedQuantity
+				}
+				if override.UnitCost > 0 {
+					unitCost = override.UnitCost
+				}
+			}
+			totalCost += float64(received) * unitCost
+
+			if err := tx.Table("purchase_order_items").Where("id = ?", line.ID).Updates(map[string]interface{}{
+				"received_quantity": received,
+				"unit_cost":         unitCost,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to record received quantity: %w", err)
+			}
+			if received == 0 {
+				continue
+			}
+
+			var product ProductModel
+			if err := tx.Table("products").
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "stock_quantity").
+				Where("id = ?", line.ProductID).
+				First(&product).Error; err != nil {
+				return fmt.Errorf("failed to get product stock: %w", err)
+			}
+
+			newQuantity := product.StockQuantity + received
+			if err := tx.Table("products").Where("id = ?", product.ID).Updates(map[string]interface{}{
+				"stock_quantity": newQuantity,
+				"cost_price":     unitCost,
+				"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to add received stock: %w", err)
+			}
+
+			audit := &StockAdjustmentModel{
+				ID:              r.ids.NewID(),
+				ProductID:       product.ID,
+				PurchaseOrderID: sql.NullString{String: id, Valid: true},
+				OldQuantity:     product.StockQuantity,
+				NewQuantity:     newQuantity,
+				Reason:          core.StockAdjustmentPurchaseOrder,
+				Actor:           actor,
+				CreatedAt:       now,
+			}
+			if err := tx.Table("stock_adjustments").Create(audit).Error; err != nil {
+				return fmt.Errorf("failed to record stock adjustment: %w", err)
+			}
+
+			adjustments = append(adjustments, core.StockAdjustment{
+				ProductID:   product.ID,
+				OldQuantity: product.StockQuantity,
+				NewQuantity: newQuantity,
+			})
+		}
+
+		if err := tx.Table("purchase_orders").Where("id = ?", id).Updates(map[string]interface{}{
+			"status":      string(core.PurchaseOrderReceived),
+			"total_cost":  math.Round(totalCost*100) / 100,
+			"received_by": actor,
+			"received_at": now,
+			"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to receive purchase order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// Cancel marks an ORDERED purchase order CANCELLED without touching stock
+func (r *purchaseOrderRepository) Cancel(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOrderedPurchaseOrder(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Table("purchase_orders").Where("id = ?", id).Updates(map[string]interface{}{
+			"status":     string(core.PurchaseOrderCancelled),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to cancel purchase order: %w", err)
+		}
+		return nil
+	})
+}
+
+// snapshotItemCosts records what one unit of each of an order's items cost when it was placed:
+// the product's cost price, or else the cost of its recipe or combo components. Items stay
+// uncosted (NULL) when any part of them has no cost price yet.
+func snapshotItemCosts(tx *gorm.DB, orderID string) error {
+	if err := tx.Exec(`
+		UPDATE order_items SET unit_cost = costs.unit_cost
+		FROM (
+			SELECT p.id, COALESCE(
+				p.cost_price,
+				(SELECT CASE WHEN COUNT(*) = COUNT(line_cost) THEN SUM(line_cost) END
+					FROM (
+						SELECT CASE WHEN r.unit = 'ml' THEN r.measure * i.cost_price / NULLIF(i.bottle_ml, 0) ELSE r.measure * i.cost_price END AS line_cost
+						FROM recipes r JOIN products i ON i.id = r.ingredient_product_id
+						WHERE r.cocktail_product_id = p.id
+					) recipe_lines),
+				(SELECT CASE WHEN COUNT(*) = COUNT(c.cost_price) THEN SUM(b.quantity * c.cost_price) END
+					FROM bundle_items b JOIN products c ON c.id = b.component_product_id
+					WHERE b.bundle_product_id = p.id)
+			) AS unit_cost
+			FROM products p
+			WHERE p.id IN (SELECT product_id FROM order_items WHERE order_id = ?)
+		) costs
+		WHERE order_items.order_id = ? AND order_items.product_id = costs.id`, orderID, orderID).Error; err != nil {
+		return fmt.Errorf("failed to record order item costs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// recipeRepository implements RecipeRepository methods
+type recipeRepository struct {
+	*Repository
+}
+
+// RecipeModel represents the recipes table structure
+type RecipeModel struct {
+	ID                  string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CocktailProductID   string    `gorm:"column:cocktail_product_id;type:uuid;not null;index"`
+	IngredientProductID string    `gorm:"column:ingredient_product_id;type:uuid;not null"`
+	Measure             float64   `gorm:"column:measure;type:decimal(10,2);not null"`
+	Unit                string    `gorm:"column:unit;type:varchar(10);not null"`
+	CreatedAt           time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (RecipeModel) TableName() string {
+	return "recipes"
+}
+
+// recipeIngredientRow is a recipe line joined with its ingredient product's name and stock
+type recipeIngredientRow struct {
+	CocktailProductID   string        `gorm:"column:cocktail_product_id"`
+	IngredientProductID string        `gorm:"column:ingredient_product_id"`
+	Measure             float64       `gorm:"column:measure"`
+	Unit                string        `gorm:"column:unit"`
+	ProductName         string        `gorm:"column:product_name"`
+	StockQuantity       int           `gorm:"column:stock_quantity"`
+	BottleML            sql.NullInt64 `gorm:"column:bottle_ml"`
+	PouredML            float64       `gorm:"column:poured_ml"`
+}
+
+// ToDomain converts recipeIngredientRow to core.RecipeIngredient
+func (r *recipeIngredientRow) ToDomain() core.RecipeIngredient {
+	return core.RecipeIngredient{
+		ProductID:     r.IngredientProductID,
+		ProductName:   r.ProductName,
+		Measure:       r.Measure,
+		Unit:          core.RecipeUnit(r.Unit),
+		StockQuantity: r.StockQuantity,
+		BottleML:      int(r.BottleML.Int64),
+		PouredML:      r.PouredML,
+	}
+}
+
+// fetchIngredients loads ingredients (with live stock) for the given cocktails, keyed by cocktail ID
+func (r *recipeRepository) fetchIngredients(ctx context.Context, cocktailProductIDs []string) (map[string][]core.RecipeIngredient, error) {
+	ingredientsByCocktail := make(map[string][]core.RecipeIngredient, len(cocktailProductIDs))
+	if len(cocktailProductIDs) == 0 {
+		return ingredientsByCocktail, nil
+	}
+
+	var rows []recipeIngredientRow
+	if err := r.db.WithContext(ctx).Table("recipes").
+		Select("recipes.cocktail_product_id, recipes.ingredient_product_id, recipes.measure, recipes.unit, products.name AS product_name, products.stock_quantity, products.bottle_ml, products.poured_ml").
+		Joins("JOIN products ON products.id = recipes.ingredient_product_id").
+		Where("recipes.cocktail_product_id IN ?", cocktailProductIDs).
+		Order("products.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get recipe ingredients: %w", err)
+	}
+
+	for i := range rows {
+		ingredientsByCocktail[rows[i].CocktailProductID] = append(ingredientsByCocktail[rows[i].CocktailProductID], rows[i].ToDomain())
+	}
+	return ingredientsByCocktail, nil
+}
+
+// GetAll retrieves every product with a recipe, its ingredients and how many can be made
+func (r *recipeRepository) GetAll(ctx context.Context) ([]*core.Recipe, error) {
+	var productModels []ProductModel
+	if err := r.db.WithContext(ctx).Table("products").
+		Where("id IN (SELECT cocktail_product_id FROM recipes)").
+		Order("is_active DESC, name ASC").
+		Find(&productModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get recipes: %w", err)
+	}
+
+	ids := make([]string, len(productModels))
+	for i := range productModels {
+		ids[i] = productModels[i].ID
+	}
+
+	ingredientsByCocktail, err := r.fetchIngredients(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*core.Recipe, len(productModels))
+	for i := range productModels {
+		ingredients := ingredientsByCocktail[productModels[i].ID]
+		recipes[i] = &core.Recipe{
+			Product:     *productModels[i].ToDomain(),
+			Ingredients: ingredients,
+			Available:   core.RecipeAvailability(ingredients),
+		}
+	}
+	return recipes, nil
+}
+
+// GetIngredients retrieves a cocktail's ingredients with live stock; empty when it has no recipe
+func (r *recipeRepository) GetIngredients(ctx context.Context, cocktailProductID string) ([]core.RecipeIngredient, error) {
+	ingredientsByCocktail, err := r.fetchIngredients(ctx, []string{cocktailProductID})
+	if err != nil {
+		return nil, err
+	}
+	return ingredientsByCocktail[cocktailProductID], nil
+}
+
+// SetIngredients replaces a cocktail's recipe
+func (r *recipeRepository) SetIngredients(ctx context.Context, cocktailProductID string, ingredients []core.RecipeIngredient) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("recipes").Where("cocktail_product_id = ?", cocktailProductID).Delete(&RecipeModel{}).Error; err != nil {
+			return fmt.Errorf("failed to clear recipe: %w", err)
+		}
+
+		now := r.clock.Now()
+		for _, ingredient := range ingredients {
+			line := &RecipeModel{
+				ID:                  r.ids.NewID(),
+				CocktailProductID:   cocktailProductID,
+				IngredientProductID: ingredient.ProductID,
+				Measure:             ingredient.Measure,
+				Unit:                string(ingredient.Unit),
+				CreatedAt:           now,
+			}
+			if err := tx.Table("recipes").Create(line).Error; err != nil {
+				return fmt.Errorf("failed to create recipe ingredient: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// recipeUsageRow is how much of one ingredient an order's cocktails use
+type recipeUsageRow struct {
+	IngredientProductID string  `gorm:"column:ingredient_product_id"`
+	Unit                string  `gorm:"column:unit"`
+	Amount              float64 `gorm:"column:amount"`
+}
+
+// deductRecipeStock takes the ingredients of an order's cocktails out of stock, once per order.
+// The stock_deducted_at claim keeps a later transition (SCHEDULED -> PAID) from pouring again.
+// Stock level changes are recorded as "recipe" adjustments against the order, and the outbox
+// publishes them once the transaction commits.
+func (r *orderRepository) deductRecipeStock(tx *gorm.DB, orderID string) error {
+	claim := tx.Table("orders").
+		Where("id = ? AND stock_deducted_at IS NULL", orderID).
+		Update("stock_deducted_at", gorm.Expr("CURRENT_TIMESTAMP"))
+	if claim.Error != nil {
+		return fmt.Errorf("failed to deduct recipe stock: %w", claim.Error)
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	var usage []recipeUsageRow
+	if err := tx.Table("order_items").
+		Select("recipes.ingredient_product_id, recipes.unit, SUM(recipes.measure * order_items.quantity) AS amount").
+		Joins("JOIN recipes ON recipes.cocktail_product_id = order_items.product_id").
+		Where("order_items.order_id = ?", orderID).
+		Group("recipes.ingredient_product_id, recipes.unit").
+		Scan(&usage).Error; err != nil {
+		return fmt.Errorf("failed to get recipe usage: %w", err)
+	}
+
+	changed := false
+	for _, used := range usage {
+		var product ProductModel
+		if err := tx.Table("products").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "stock_quantity", "bottle_ml", "poured_ml").
+			Where("id = ?", used.IngredientProductID).
+			First(&product).Error; err != nil {
+			return fmt.Errorf("failed to get ingredient stock: %w", err)
+		}
+
+		stock, poured := core.PourStock(product.StockQuantity, int(product.BottleML.Int64), product.PouredML, core.RecipeUnit(used.Unit), used.Amount)
+		if err := tx.Table("products").Where("id = ?", product.ID).Updates(map[string]interface{}{
+			"stock_quantity": stock,
+			"poured_ml":      poured,
+			"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to deduct ingredient stock: %w", err)
+		}
+
+		// Part of an open bottle doesn't change the stock count
+		if stock == product.StockQuantity {
+			continue
+		}
+		audit := &StockAdjustmentModel{
+			ID:          r.ids.NewID(),
+			ProductID:   product.ID,
+			OrderID:     sql.NullString{String: orderID, Valid: true},
+			OldQuantity: product.StockQuantity,
+			NewQuantity: stock,
+			Reason:      core.StockAdjustmentRecipe,
+			Actor:       core.OrderActorSystem,
+			CreatedAt:   r.clock.Now(),
+		}
+		if err := tx.Table("stock_adjustments").Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to record stock adjustment: %w", err)
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.enqueueOutbox(tx, orderID, []string{core.OutboxTopicPaidStock})
+}
+
+// GetOrderDrawdown returns the stock changes an order's recipes made, from the "recipe" adjustments
+// recorded by deductRecipeStock
+func (r *recipeRepository) GetOrderDrawdown(ctx context.Context, orderID string) ([]core.StockAdjustment, error) {
+	var models []StockAdjustmentModel
+	if err := r.db.WithContext(ctx).Table("stock_adjustments").
+		Where("order_id = ? AND reason = ?", orderID, core.StockAdjustmentRecipe).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order stock drawdown: %w", err)
+	}
+
+	adjustments := make([]core.StockAdjustment, len(models))
+	for i, model := range models {
+		adjustments[i] = core.StockAdjustment{
+			ProductID:   model.ProductID,
+			OldQuantity: model.OldQuantity,
+			NewQuantity: model.NewQuantity,
+			Reason:      model.Reason,
+		}
+	}
+	return adjustments, nil
+}
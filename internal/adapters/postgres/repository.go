@@ -5,31 +5,62 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/core"
-	"github.com/google/uuid"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository implements ProductRepository, OrderRepository, and UserRepository using GORM with pgx driver
 type Repository struct {
-	db                  *gorm.DB
-	productRepository   *productRepository
-	orderRepository     *orderRepository
-	userRepository      *userRepository
-	adminUserRepository *adminUserRepository
-	otpRepository       *otpRepository
-	analyticsRepository *analyticsRepository
+	db                   *gorm.DB
+	readDB               *gorm.DB // Read replica for analytics queries; the primary when none is configured
+	productRepository    *productRepository
+	orderRepository      *orderRepository
+	userRepository       *userRepository
+	adminUserRepository  *adminUserRepository
+	otpRepository        *otpRepository
+	analyticsRepository  *analyticsRepository
+	barStaffRepository   *barStaffRepository
+	paymentRepository    *paymentRepository
+	optionRepository     *productOptionRepository
+	bundleRepository     *bundleRepository
+	stkAttemptRepository *stkAttemptRepository
+	settingsRepository   *settingsRepository
+	blockedRepository    *blockedCustomerRepository
+	tabRepository        *tabRepository
+	riderRepository      *riderRepository
+	recipeRepository     *recipeRepository
+	stocktakeRepository  *stocktakeRepository
+	supplierRepository   *supplierRepository
+	purchaseRepository   *purchaseOrderRepository
+	auditLogRepository   *auditLogRepository
+	broadcastRepository  *broadcastRepository
+	feedbackRepository   *feedbackRepository
+	shiftRepository      *shiftRepository
+	webhookRepository    *webhookEventRepository
+	outboxRepository     *outboxRepository
+	favoriteRepository   *favoriteRepository
+	reservationRepo      *reservationRepository
+	purchaseLimitRepo    *purchaseLimitRepository
+	restockAlertRepo     *restockAlertRepository
+	salesSheetSyncRepo   *salesSheetSyncRepository
+	whatsAppNumberRepo   *whatsAppNumberRepository
+	clock                core.Clock
+	ids                  core.IDGenerator
 }
 
 // productRepository implements ProductRepository methods
 type productRepository struct {
 	*Repository
+	trigramOnce sync.Once
+	trigram     bool // pg_trgm is installed, so search can match typos
 }
 
 // orderRepository implements OrderRepository methods
@@ -57,15 +88,19 @@ type analyticsRepository struct {
 	*Repository
 }
 
-// NewRepository creates a new Postgres repository instance
-func NewRepository(dbURL string) (*Repository, error) {
-	// GORM with pgx driver (postgres driver uses pgx under the hood)
-	db, err := gorm.Open(postgres.Open(dbURL), &gorm.Config{})
+// NewRepository creates a new Postgres repository instance with the given pool sizing
+func NewRepository(dbURL string, pool PoolConfig) (*Repository, error) {
+	db, err := openPool(dbURL, pool)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	repo := &Repository{db: db}
+	repo := &Repository{
+		db:     db,
+		readDB: db,
+		clock:  core.SystemClock{},
+		ids:    core.UUIDGenerator{},
+	}
 	// Set up embedded types
 	repo.productRepository = &productRepository{Repository: repo}
 	repo.orderRepository = &orderRepository{Repository: repo}
@@ -73,9 +108,53 @@ func NewRepository(dbURL string) (*Repository, error) {
 	repo.adminUserRepository = &adminUserRepository{Repository: repo}
 	repo.otpRepository = &otpRepository{Repository: repo}
 	repo.analyticsRepository = &analyticsRepository{Repository: repo}
+	repo.barStaffRepository = &barStaffRepository{Repository: repo}
+	repo.paymentRepository = &paymentRepository{Repository: repo}
+	repo.optionRepository = &productOptionRepository{Repository: repo}
+	repo.bundleRepository = &bundleRepository{Repository: repo}
+	repo.stkAttemptRepository = &stkAttemptRepository{Repository: repo}
+	repo.settingsRepository = &settingsRepository{Repository: repo}
+	repo.blockedRepository = &blockedCustomerRepository{Repository: repo}
+	repo.tabRepository = &tabRepository{Repository: repo}
+	repo.riderRepository = &riderRepository{Repository: repo}
+	repo.recipeRepository = &recipeRepository{Repository: repo}
+	repo.stocktakeRepository = &stocktakeRepository{Repository: repo}
+	repo.supplierRepository = &supplierRepository{Repository: repo}
+	repo.purchaseRepository = &purchaseOrderRepository{Repository: repo}
+	repo.auditLogRepository = &auditLogRepository{Repository: repo}
+	repo.broadcastRepository = &broadcastRepository{Repository: repo}
+	repo.feedbackRepository = &feedbackRepository{Repository: repo}
+	repo.shiftRepository = &shiftRepository{Repository: repo}
+	repo.webhookRepository = &webhookEventRepository{Repository: repo}
+	repo.outboxRepository = &outboxRepository{Repository: repo}
+	repo.favoriteRepository = &favoriteRepository{Repository: repo}
+	repo.reservationRepo = &reservationRepository{Repository: repo}
+	repo.purchaseLimitRepo = &purchaseLimitRepository{Repository: repo}
+	repo.restockAlertRepo = &restockAlertRepository{Repository: repo}
+	repo.salesSheetSyncRepo = &salesSheetSyncRepository{Repository: repo}
+	repo.whatsAppNumberRepo = &whatsAppNumberRepository{Repository: repo}
 	return repo, nil
 }
 
+// Ping checks the database connection is alive
+func (r *Repository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// SetClock overrides the time source used for matching windows and analytics ranges
+func (r *Repository) SetClock(clock core.Clock) {
+	r.clock = clock
+}
+
+// SetIDGenerator overrides how repository-created records get their IDs
+func (r *Repository) SetIDGenerator(ids core.IDGenerator) {
+	r.ids = ids
+}
+
 // ProductRepository returns the ProductRepository interface implementation
 func (r *Repository) ProductRepository() core.ProductRepository {
 	return r.productRepository
@@ -106,6 +185,121 @@ func (r *Repository) AnalyticsRepository() core.AnalyticsRepository {
 	return r.analyticsRepository
 }
 
+// BarStaffRepository returns the BarStaffRepository interface implementation
+func (r *Repository) BarStaffRepository() core.BarStaffRepository {
+	return r.barStaffRepository
+}
+
+// PaymentRepository returns the PaymentRepository interface implementation
+func (r *Repository) PaymentRepository() core.PaymentRepository {
+	return r.paymentRepository
+}
+
+// ProductOptionRepository returns the ProductOptionRepository interface implementation
+func (r *Repository) ProductOptionRepository() core.ProductOptionRepository {
+	return r.optionRepository
+}
+
+// BundleRepository returns the BundleRepository interface implementation
+func (r *Repository) BundleRepository() core.BundleRepository {
+	return r.bundleRepository
+}
+
+// STKAttemptRepository returns the STKAttemptRepository interface implementation
+func (r *Repository) STKAttemptRepository() core.STKAttemptRepository {
+	return r.stkAttemptRepository
+}
+
+// SettingsRepository returns the SettingsRepository interface implementation
+func (r *Repository) SettingsRepository() core.SettingsRepository {
+	return r.settingsRepository
+}
+
+// BlockedCustomerRepository returns the BlockedCustomerRepository interface implementation
+func (r *Repository) BlockedCustomerRepository() core.BlockedCustomerRepository {
+	return r.blockedRepository
+}
+
+// TabRepository returns the TabRepository interface implementation
+func (r *Repository) TabRepository() core.TabRepository {
+	return r.tabRepository
+}
+
+// RiderRepository returns the RiderRepository interface implementation
+func (r *Repository) RiderRepository() core.RiderRepository {
+	return r.riderRepository
+}
+
+// RecipeRepository returns the RecipeRepository interface implementation
+func (r *Repository) RecipeRepository() core.RecipeRepository {
+	return r.recipeRepository
+}
+
+// StocktakeRepository returns the StocktakeRepository interface implementation
+func (r *Repository) StocktakeRepository() core.StocktakeRepository {
+	return r.stocktakeRepository
+}
+
+// SupplierRepository returns the SupplierRepository interface implementation
+func (r *Repository) SupplierRepository() core.SupplierRepository {
+	return r.supplierRepository
+}
+
+// PurchaseOrderRepository returns the PurchaseOrderRepository interface implementation
+func (r *Repository) PurchaseOrderRepository() core.PurchaseOrderRepository {
+	return r.purchaseRepository
+}
+
+// AuditLogRepository returns the AuditLogRepository interface implementation
+func (r *Repository) AuditLogRepository() core.AuditLogRepository {
+	return r.auditLogRepository
+}
+
+// BroadcastRepository returns the BroadcastRepository interface implementation
+func (r *Repository) BroadcastRepository() core.BroadcastRepository {
+	return r.broadcastRepository
+}
+
+// FeedbackRepository returns the FeedbackRepository interface implementation
+func (r *Repository) FeedbackRepository() core.FeedbackRepository {
+	return r.feedbackRepository
+}
+
+// ShiftRepository returns the ShiftRepository interface implementation
+func (r *Repository) ShiftRepository() core.ShiftRepository {
+	return r.shiftRepository
+}
+
+// WebhookEventRepository returns the WebhookEventRepository interface implementation
+func (r *Repository) WebhookEventRepository() core.WebhookEventRepository {
+	return r.webhookRepository
+}
+
+// OutboxRepository returns the OutboxRepository interface implementation
+func (r *Repository) OutboxRepository() core.OutboxRepository {
+	return r.outboxRepository
+}
+
+// FavoriteRepository returns the FavoriteRepository interface implementation
+func (r *Repository) FavoriteRepository() core.FavoriteRepository {
+	return r.favoriteRepository
+}
+
+// ReservationRepository returns the ReservationRepository interface implementation
+func (r *Repository) ReservationRepository() core.ReservationRepository {
+	return r.reservationRepo
+}
+
+// PurchaseLimitRepository returns the PurchaseLimitRepository interface implementation
+func (r *Repository) PurchaseLimitRepository() core.PurchaseLimitRepository {
+	return r.purchaseLimitRepo
+}
+
+// RestockAlertRepository returns the RestockAlertRepository interface implementation
+func (r *Repository) RestockAlertRepository() core.RestockAlertRepository {
+	return r.restockAlertRepo
+}
+
 // ProductRepository implementation
 
 // GetByID retrieves a product by its ID
@@ -124,7 +318,8 @@ func (r *productRepository) GetByID(ctx context.Context, id string) (*core.Produ
 func (r *productRepository) GetByCategory(ctx context.Context, category string) ([]*core.Product, error) {
 	var productModels []ProductModel
 	if err := r.db.WithContext(ctx).Table("products").
-		Where("category = ? AND is_active = ?", category, true).
+		Where("category = ? AND is_active = ? AND archived_at IS NULL", category, true).
+		Where(visibleWhenSoldOut).
 		Find(&productModels).Error; err != nil {
 		return nil, fmt.Errorf("failed to get products by category: %w", err)
 	}
@@ -140,7 +335,7 @@ func (r *productRepository) GetByCategory(ctx context.Context, category string)
 func (r *productRepository) GetAll(ctx context.Context) ([]*core.Product, error) {
 	var productModels []ProductModel
 	if err := r.db.WithContext(ctx).Table("products").
-		Where("is_active = ?", true).
+		Where("is_active = ? AND archived_at IS NULL", true).
 		Find(&productModels).Error; err != nil {
 		return nil, fmt.Errorf("failed to get all products: %w", err)
 	}
@@ -156,7 +351,8 @@ func (r *productRepository) GetAll(ctx context.Context) ([]*core.Product, error)
 func (r *productRepository) GetMenu(ctx context.Context) (map[string][]*core.Product, error) {
 	var productModels []ProductModel
 	if err := r.db.WithContext(ctx).Table("products").
-		Where("is_active = ?", true).
+		Where("is_active = ? AND archived_at IS NULL", true).
+		Where(visibleWhenSoldOut).
 		Order("category, name").
 		Find(&productModels).Error; err != nil {
 		return nil, fmt.Errorf("failed to get menu: %w", err)
@@ -190,15 +386,50 @@ func (r *productRepository) UpdateStock(ctx context.Context, id string, quantity
 	return nil
 }
 
-// SearchProducts searches for products by name (case-insensitive partial match)
-func (r *productRepository) SearchProducts(ctx context.Context, query string) ([]*core.Product, error) {
+// SetBottleSize sets how many ml one stock unit of a product holds; zero clears it
+func (r *productRepository) SetBottleSize(ctx context.Context, id string, bottleML int) error {
+	result := r.db.WithContext(ctx).Table("products").
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"bottle_ml":  sql.NullInt64{Int64: int64(bottleML), Valid: bottleML > 0},
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update bottle size: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+// SetCostPrice sets what one stock unit of a product costs the bar; zero clears it
+func (r *productRepository) SetCostPrice(ctx context.Context, id string, cost float64) error {
+	result := r.db.WithContext(ctx).Table("products").
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"cost_price": sql.NullFloat64{Float64: cost, Valid: cost > 0},
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update cost price: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+// GetArchived retrieves archived products, most recently archived first
+func (r *productRepository) GetArchived(ctx context.Context) ([]*core.Product, error) {
 	var productModels []ProductModel
-	searchPattern := "%" + query + "%"
 	if err := r.db.WithContext(ctx).Table("products").
-		Where("LOWER(name) LIKE LOWER(?) AND is_active = ?", searchPattern, true).
-		Order("name").
+		Where("archived_at IS NOT NULL").
+		Order("archived_at DESC, name").
 		Find(&productModels).Error; err != nil {
-		return nil, fmt.Errorf("failed to search products: %w", err)
+		return nil, fmt.Errorf("failed to get archived products: %w", err)
 	}
 
 	products := make([]*core.Product, len(productModels))
@@ -208,17 +439,25 @@ func (r *productRepository) SearchProducts(ctx context.Context, query string) ([
 	return products, nil
 }
 
-// UpdatePrice updates the price for a product
-func (r *productRepository) UpdatePrice(ctx context.Context, id string, price float64) error {
+// SetArchived archives or restores a product. Archived rows are kept (and deactivated)
+// so historical order items and reports still join to them.
+func (r *productRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	updates := map[string]interface{}{
+		"archived_at": nil,
+		"is_active":   true,
+		"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+	}
+	if archived {
+		updates["archived_at"] = gorm.Expr("COALESCE(archived_at, CURRENT_TIMESTAMP)")
+		updates["is_active"] = false
+	}
+
 	result := r.db.WithContext(ctx).Table("products").
 		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"price":      price,
-			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
-		})
+		Updates(updates)
 
 	if result.Error != nil {
-		return fmt.Errorf("failed to update price: %w", result.Error)
+		return fmt.Errorf("failed to update product archive state: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("product not found")
@@ -246,7 +485,19 @@ func (r *orderRepository) CreateOrder(ctx context.Context, order *core.Order) er
 			}
 		}
 
-		return nil
+		if err := snapshotItemCosts(tx, orderModel.ID); err != nil {
+			return err
+		}
+
+		// Split bill shares, one per payer
+		for _, share := range order.PaymentShares {
+			share.OrderID = orderModel.ID
+			if err := r.createPaymentShare(tx, share); err != nil {
+				return err
+			}
+		}
+
+		return r.recordStatusChange(tx, orderModel.ID, "", order.Status, core.OrderActorSystem, "order created")
 	})
 }
 
@@ -276,6 +527,9 @@ func (r *orderRepository) fetchOrderItemsWithProductNames(ctx context.Context, o
 			ProductID:   item.ProductID,
 			Quantity:    item.Quantity,
 			PriceAtTime: item.PriceAtTime,
+			TaxAmount:   item.TaxAmount,
+			Modifiers:   item.Modifiers,
+			Components:  item.Components,
 			ProductName: iwp.ProductName, // Populated from JOIN
 		}
 	}
@@ -283,6 +537,46 @@ func (r *orderRepository) fetchOrderItemsWithProductNames(ctx context.Context, o
 	return items, nil
 }
 
+// fetchOrderItemsForOrders loads items (with product names) for many orders in a single query, keyed by order ID
+func (r *orderRepository) fetchOrderItemsForOrders(ctx context.Context, orderIDs []string) (map[string][]core.OrderItem, error) {
+	itemsByOrder := make(map[string][]core.OrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return itemsByOrder, nil
+	}
+
+	type OrderItemWithProduct struct {
+		OrderItemModel
+		ProductName string `gorm:"column:product_name"`
+	}
+
+	var itemsWithProducts []OrderItemWithProduct
+	if err := r.db.WithContext(ctx).Table("order_items").
+		Select("order_items.*, products.name as product_name").
+		Joins("LEFT JOIN products ON order_items.product_id = products.id").
+		Where("order_items.order_id IN ?", orderIDs).
+		Order("order_items.created_at ASC").
+		Find(&itemsWithProducts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	for _, iwp := range itemsWithProducts {
+		item := iwp.OrderItemModel.ToDomain()
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], core.OrderItem{
+			ID:          item.ID,
+			OrderID:     item.OrderID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			PriceAtTime: item.PriceAtTime,
+			TaxAmount:   item.TaxAmount,
+			Modifiers:   item.Modifiers,
+			Components:  item.Components,
+			ProductName: iwp.ProductName,
+		})
+	}
+
+	return itemsByOrder, nil
+}
+
 // GetByID retrieves an order by its ID with all items (implements OrderRepository)
 func (r *orderRepository) GetByID(ctx context.Context, id string) (*core.Order, error) {
 	var orderModel OrderModel
@@ -378,91 +672,229 @@ func (r *orderRepository) GetByDateRangeAndStatuses(ctx context.Context, start t
 		return nil, fmt.Errorf("failed to get orders by date range: %w", err)
 	}
 
-	orders := make([]*core.Order, len(orderModels))
+	// Reports can span hundreds of orders, so load all items in one query.
+	orderIDs := make([]string, len(orderModels))
 	for i, om := range orderModels {
-		order := om.ToDomain()
+		orderIDs[i] = om.ID
+	}
 
-		items, err := r.fetchOrderItemsWithProductNames(ctx, om.ID)
-		if err != nil {
-			return nil, err
-		}
-		order.Items = items
+	itemsByOrder, err := r.fetchOrderItemsForOrders(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
 
+	orders := make([]*core.Order, len(orderModels))
+	for i, om := range orderModels {
+		order := om.ToDomain()
+		order.Items = itemsByOrder[om.ID]
 		orders[i] = order
 	}
 
 	return orders, nil
 }
 
-// UpdateStatus updates the status of an order
+// UpdateStatus updates the status of an order (recorded in the history as a system change)
 func (r *orderRepository) UpdateStatus(ctx context.Context, id string, status core.OrderStatus) error {
-	return r.UpdateStatusWithActor(ctx, id, status, "")
+	return r.UpdateStatusWithNote(ctx, id, status, core.OrderActorSystem, "")
 }
 
 // UpdateStatusWithActor updates order status and records audit metadata for bartender workflow actions.
 func (r *orderRepository) UpdateStatusWithActor(ctx context.Context, id string, status core.OrderStatus, actorUserID string) error {
-	result := r.db.WithContext(ctx).Table("orders").
-		Where("id = ?", id)
+	return r.UpdateStatusWithNote(ctx, id, status, actorUserID, "")
+}
 
-	updates := map[string]interface{}{
-		"status":     string(status),
-		"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
-	}
+// UpdateStatusWithNote updates order status and appends the transition to order_status_history.
+// actor is an admin user ID, core.OrderActorSystem or core.OrderActorWebhook.
+func (r *orderRepository) UpdateStatusWithNote(ctx context.Context, id string, status core.OrderStatus, actor string, note string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the row so concurrent transitions are recorded with the right "from" status.
+		var current OrderModel
+		if err := tx.Table("orders").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("id = ?", id).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("order not found")
+			}
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		updates := map[string]interface{}{
+			"status":     string(status),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}
+
+		switch status {
+		case core.OrderStatusReady:
+			updates["ready_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+			if isAdminActor(actor) {
+				updates["ready_by_admin_user_id"] = actor
+			}
+		case core.OrderStatusCompleted, core.OrderStatusDelivered:
+			updates["completed_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+			if isAdminActor(actor) {
+				updates["completed_by_admin_user_id"] = actor
+			}
+		}
 
-	switch status {
-	case core.OrderStatusReady:
-		updates["ready_at"] = gorm.Expr("CURRENT_TIMESTAMP")
-		if actorUserID != "" {
-			updates["ready_by_admin_user_id"] = actorUserID
+		if err := tx.Table("orders").Where("id = ?", id).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
 		}
-	case core.OrderStatusCompleted:
-		updates["completed_at"] = gorm.Expr("CURRENT_TIMESTAMP")
-		if actorUserID != "" {
-			updates["completed_by_admin_user_id"] = actorUserID
+
+		if core.OrderStatus(current.Status) == status {
+			return nil
 		}
+		return r.recordStatusChange(tx, id, core.OrderStatus(current.Status), status, actor, note)
+	})
+}
+
+// IsPickupCodeActive reports whether an open (PENDING, PARTIALLY_PAID, AWAITING_CASH, SCHEDULED, PAID, READY or OUT_FOR_DELIVERY) order already uses the code
+func (r *orderRepository) IsPickupCodeActive(ctx context.Context, code string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("orders").
+		Where("pickup_code = ? AND status IN ?", code, []string{
+			string(core.OrderStatusPending),
+			string(core.OrderStatusPartiallyPaid),
+			string(core.OrderStatusAwaitingCash),
+			string(core.OrderStatusScheduled),
+			string(core.OrderStatusPaid),
+			string(core.OrderStatusReady),
+			string(core.OrderStatusOutForDelivery),
+		}).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check pickup code: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MarkAccepted records the bar staff member who accepted a paid order.
+// Only the first acceptance is kept; later taps return false.
+func (r *orderRepository) MarkAccepted(ctx context.Context, id string, staffID string) (bool, error) {
+	result := r.db.WithContext(ctx).Table("orders").
+		Where("id = ? AND accepted_by_staff_id IS NULL", id).
+		Updates(map[string]interface{}{
+			"accepted_by_staff_id": staffID,
+			"accepted_at":          gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at":           gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark order accepted: %w", result.Error)
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	result = result.Updates(updates)
+// GetUncollected retrieves READY orders that have waited at least readyFor and haven't been escalated.
+// ready_at is set from the database clock, so the age is compared there too.
+func (r *orderRepository) GetUncollected(ctx context.Context, readyFor time.Duration) ([]*core.Order, error) {
+	var orderModels []OrderModel
+	if err := r.db.WithContext(ctx).Table("orders").
+		Where("status = ? AND pickup_escalated_at IS NULL AND ready_at <= CURRENT_TIMESTAMP - ? * INTERVAL '1 second'", string(core.OrderStatusReady), int64(readyFor/time.Second)).
+		Order("ready_at ASC").
+		Find(&orderModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get uncollected orders: %w", err)
+	}
+
+	orders := make([]*core.Order, len(orderModels))
+	for i := range orderModels {
+		orders[i] = orderModels[i].ToDomain()
+	}
+	return orders, nil
+}
+
+// ClaimReadyReminder records pickup reminder n for an order that has been READY for readyFor.
+// The conditional update makes each reminder go out once even with several replicas polling.
+func (r *orderRepository) ClaimReadyReminder(ctx context.Context, id string, reminder int, readyFor time.Duration) (bool, error) {
+	result := r.db.WithContext(ctx).Table("orders").
+		Where("id = ? AND status = ? AND ready_reminders_sent < ? AND ready_at <= CURRENT_TIMESTAMP - ? * INTERVAL '1 second'",
+			id, string(core.OrderStatusReady), reminder, int64(readyFor/time.Second)).
+		Updates(map[string]interface{}{
+			"ready_reminders_sent": reminder,
+			"updated_at":           gorm.Expr("CURRENT_TIMESTAMP"),
+		})
 
 	if result.Error != nil {
-		return fmt.Errorf("failed to update order status: %w", result.Error)
+		return false, fmt.Errorf("failed to record pickup reminder: %w", result.Error)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("order not found")
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimPickupEscalation flags an order still READY after readyFor for bar staff attention
+func (r *orderRepository) ClaimPickupEscalation(ctx context.Context, id string, readyFor time.Duration) (bool, error) {
+	result := r.db.WithContext(ctx).Table("orders").
+		Where("id = ? AND status = ? AND pickup_escalated_at IS NULL AND ready_at <= CURRENT_TIMESTAMP - ? * INTERVAL '1 second'",
+			id, string(core.OrderStatusReady), int64(readyFor/time.Second)).
+		Updates(map[string]interface{}{
+			"pickup_escalated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at":          gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to escalate uncollected order: %w", result.Error)
 	}
-	return nil
+	return result.RowsAffected > 0, nil
 }
 
-// GetAllWithFilters retrieves orders with optional status filter and limit
-func (r *orderRepository) GetAllWithFilters(ctx context.Context, status string, limit int) ([]*core.Order, error) {
-	query := r.db.WithContext(ctx).Table("orders").Order("created_at DESC")
+// Search retrieves orders newest first, narrowed by the given filter
+func (r *orderRepository) Search(ctx context.Context, filter core.OrderFilter) ([]*core.Order, error) {
+	query := r.db.WithContext(ctx).Table("orders")
 
-	// Apply status filter if provided
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.PickupCode != "" {
+		query = query.Where("pickup_code ILIKE ?", "%"+filter.PickupCode+"%")
+	}
+	if patterns := buildPhoneSearchPatterns(filter.Phone); len(patterns) > 0 {
+		clauses := make([]string, len(patterns))
+		args := make([]interface{}, len(patterns))
+		for i, pattern := range patterns {
+			clauses[i] = "customer_phone LIKE ?"
+			args[i] = "%" + pattern + "%"
+		}
+		query = query.Where("("+strings.Join(clauses, " OR ")+")", args...)
+	}
+	if filter.PaymentMethod != "" {
+		query = query.Where("payment_method = ?", filter.PaymentMethod)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("total_amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("total_amount <= ?", *filter.MaxAmount)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
 	}
 
-	// Apply limit if provided
-	if limit > 0 {
-		query = query.Limit(limit)
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
 	}
 
 	var orderModels []OrderModel
-	if err := query.Find(&orderModels).Error; err != nil {
+	if err := query.Order("created_at DESC").Limit(limit).Find(&orderModels).Error; err != nil {
 		return nil, fmt.Errorf("failed to get orders: %w", err)
 	}
 
-	orders := make([]*core.Order, len(orderModels))
+	orderIDs := make([]string, len(orderModels))
 	for i, om := range orderModels {
-		order := om.ToDomain()
+		orderIDs[i] = om.ID
+	}
 
-		// Get order items with product names using helper method
-		items, err := r.fetchOrderItemsWithProductNames(ctx, om.ID)
-		if err != nil {
-			return nil, err
-		}
-		order.Items = items
+	itemsByOrder, err := r.fetchOrderItemsForOrders(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
 
+	orders := make([]*core.Order, len(orderModels))
+	for i, om := range orderModels {
+		order := om.ToDomain()
+		order.Items = itemsByOrder[om.ID]
 		orders[i] = order
 	}
 
@@ -629,7 +1061,7 @@ func (r *orderRepository) FindPendingByAmount(ctx context.Context, amount float6
 	var orderModel OrderModel
 
 	// Find most recent pending order with matching amount, created within last 30 minutes
-	cutoffTime := time.Now().Add(-30 * time.Minute)
+	cutoffTime := r.clock.Now().Add(-30 * time.Minute)
 
 	err := r.db.WithContext(ctx).Table("orders").
 		Where("status = ? AND total_amount = ? AND created_at > ?",
@@ -665,7 +1097,7 @@ func (r *orderRepository) FindPendingByHashedPhoneAndAmount(ctx context.Context,
 	}
 
 	// Find pending orders with matching amount within time window
-	cutoffTime := time.Now().Add(-30 * time.Minute)
+	cutoffTime := r.clock.Now().Add(-30 * time.Minute)
 	var orderModels []OrderModel
 
 	err := r.db.WithContext(ctx).Table("orders").
@@ -742,14 +1174,19 @@ func computeSHA256(input string) string {
 
 // ProductModel represents the product table structure
 type ProductModel struct {
-	ID            string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Name          string         `gorm:"column:name;type:varchar(255);not null"`
-	Description   sql.NullString `gorm:"column:description;type:text"`
-	Price         float64        `gorm:"column:price;type:decimal(10,2);not null"`
-	Category      string         `gorm:"column:category;type:varchar(100);not null"`
-	StockQuantity int            `gorm:"column:stock_quantity;type:integer;not null;default:0"`
-	ImageURL      sql.NullString `gorm:"column:image_url;type:varchar(500)"`
-	IsActive      bool           `gorm:"column:is_active;type:boolean;not null;default:true"`
+	ID            string          `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name          string          `gorm:"column:name;type:varchar(255);not null"`
+	Description   sql.NullString  `gorm:"column:description;type:text"`
+	Price         float64         `gorm:"column:price;type:decimal(10,2);not null"`
+	Category      string          `gorm:"column:category;type:varchar(100);not null"`
+	StockQuantity int             `gorm:"column:stock_quantity;type:integer;not null;default:0"`
+	ImageURL      sql.NullString  `gorm:"column:image_url;type:varchar(500)"`
+	IsActive      bool            `gorm:"column:is_active;type:boolean;not null;default:true"`
+	ArchivedAt    sql.NullTime    `gorm:"column:archived_at;type:timestamp"`
+	BottleML      sql.NullInt64   `gorm:"column:bottle_ml;type:integer"`
+	PouredML      float64         `gorm:"column:poured_ml;type:decimal(10,2);not null;default:0"`
+	CostPrice     sql.NullFloat64 `gorm:"column:cost_price;type:decimal(10,2)"`
+	HideSoldOut   sql.NullBool    `gorm:"column:hide_when_sold_out;type:boolean"`
 }
 
 func (ProductModel) TableName() string {
@@ -765,6 +1202,7 @@ func (p *ProductModel) ToDomain() *core.Product {
 		Category:      p.Category,
 		StockQuantity: p.StockQuantity,
 		IsActive:      p.IsActive,
+		PouredML:      p.PouredML,
 	}
 
 	if p.Description.Valid {
@@ -773,27 +1211,59 @@ func (p *ProductModel) ToDomain() *core.Product {
 	if p.ImageURL.Valid {
 		product.ImageURL = p.ImageURL.String
 	}
+	if p.ArchivedAt.Valid {
+		archivedAt := p.ArchivedAt.Time
+		product.ArchivedAt = &archivedAt
+	}
+	if p.BottleML.Valid {
+		product.BottleML = int(p.BottleML.Int64)
+	}
+	if p.CostPrice.Valid {
+		product.CostPrice = p.CostPrice.Float64
+	}
+	if p.HideSoldOut.Valid {
+		hide := p.HideSoldOut.Bool
+		product.HideWhenSoldOut = &hide
+	}
 
 	return product
 }
 
 // OrderModel represents the order table structure
 type OrderModel struct {
-	ID                     string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID                 string         `gorm:"column:user_id;type:uuid;not null"`
-	CustomerPhone          string         `gorm:"column:customer_phone;type:varchar(20);not null;index"`
-	TableNumber            string         `gorm:"column:table_number;type:varchar(20)"`
-	TotalAmount            float64        `gorm:"column:total_amount;type:decimal(10,2);not null"`
-	Status                 string         `gorm:"column:status;type:varchar(20);not null;default:'PENDING';index"`
-	PaymentMethod          string         `gorm:"column:payment_method;type:varchar(20)"`
-	PaymentRef             string         `gorm:"column:payment_reference;type:varchar(255)"`
-	PickupCode             string         `gorm:"column:pickup_code;type:varchar(4);index"` // 4-digit pickup code for bar staff
-	ReadyAt                sql.NullTime   `gorm:"column:ready_at;type:timestamp"`
-	ReadyByAdminUserID     sql.NullString `gorm:"column:ready_by_admin_user_id;type:uuid"`
-	CompletedAt            sql.NullTime   `gorm:"column:completed_at;type:timestamp"`
-	CompletedByAdminUserID sql.NullString `gorm:"column:completed_by_admin_user_id;type:uuid"`
-	CreatedAt              time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt              time.Time      `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ID                     string          `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID                 string          `gorm:"column:user_id;type:uuid;not null"`
+	CustomerPhone          string          `gorm:"column:customer_phone;type:varchar(20);not null;index"`
+	TableNumber            string          `gorm:"column:table_number;type:varchar(20)"`
+	TotalAmount            float64         `gorm:"column:total_amount;type:decimal(10,2);not null"`
+	TaxAmount              float64         `gorm:"column:tax_amount;type:decimal(10,2);not null;default:0"`
+	TaxRate                float64         `gorm:"column:tax_rate;type:decimal(5,2);not null;default:0"`
+	TipAmount              float64         `gorm:"column:tip_amount;type:decimal(10,2);not null;default:0"`
+	Notes                  string          `gorm:"column:notes;type:text;not null;default:''"`
+	ScheduledFor           sql.NullTime    `gorm:"column:scheduled_for;type:timestamp"`
+	DeliveryAddress        string          `gorm:"column:delivery_address;type:text;not null;default:''"`
+	DeliveryLatitude       sql.NullFloat64 `gorm:"column:delivery_latitude;type:double precision"`
+	DeliveryLongitude      sql.NullFloat64 `gorm:"column:delivery_longitude;type:double precision"`
+	DeliveryFee            float64         `gorm:"column:delivery_fee;type:decimal(10,2);not null;default:0"`
+	Status                 string          `gorm:"column:status;type:varchar(20);not null;default:'PENDING';index"`
+	PaymentMethod          string          `gorm:"column:payment_method;type:varchar(20)"`
+	PaymentRef             string          `gorm:"column:payment_reference;type:varchar(255)"`
+	PickupCode             string          `gorm:"column:pickup_code;type:varchar(8);index"` // Pickup code for bar staff (4 digits by default)
+	ReadyAt                sql.NullTime    `gorm:"column:ready_at;type:timestamp"`
+	ReadyByAdminUserID     sql.NullString  `gorm:"column:ready_by_admin_user_id;type:uuid"`
+	CompletedAt            sql.NullTime    `gorm:"column:completed_at;type:timestamp"`
+	CompletedByAdminUserID sql.NullString  `gorm:"column:completed_by_admin_user_id;type:uuid"`
+	AcceptedByStaffID      sql.NullString  `gorm:"column:accepted_by_staff_id;type:uuid"`
+	AcceptedAt             sql.NullTime    `gorm:"column:accepted_at;type:timestamp"`
+	RiderID                sql.NullString  `gorm:"column:rider_id;type:uuid"`
+	RiderAssignedAt        sql.NullTime    `gorm:"column:rider_assigned_at;type:timestamp"`
+	ReadyRemindersSent     int             `gorm:"column:ready_reminders_sent;type:smallint;not null;default:0"`
+	PickupEscalatedAt      sql.NullTime    `gorm:"column:pickup_escalated_at;type:timestamp"`
+	PrepSLAAlertedAt       sql.NullTime    `gorm:"column:prep_sla_alerted_at;type:timestamp"`
+	AmountPaid             float64         `gorm:"column:amount_paid;type:decimal(10,2);not null;default:0"`
+	AgeConfirmedAt         sql.NullTime    `gorm:"column:age_confirmed_at;type:timestamp"`
+	CreatedAt              time.Time       `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt              time.Time       `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 }
 
 func (OrderModel) TableName() string {
@@ -802,6 +1272,20 @@ func (OrderModel) TableName() string {
 
 // OrderModelFromDomain creates OrderModel from core.Order
 func OrderModelFromDomain(order *core.Order) *OrderModel {
+	scheduledFor := sql.NullTime{}
+	if order.ScheduledFor != nil {
+		scheduledFor = sql.NullTime{
+			Time:  *order.ScheduledFor,
+			Valid: true,
+		}
+	}
+
+	deliveryLatitude, deliveryLongitude := sql.NullFloat64{}, sql.NullFloat64{}
+	if order.DeliveryLocation != nil {
+		deliveryLatitude = sql.NullFloat64{Float64: order.DeliveryLocation.Latitude, Valid: true}
+		deliveryLongitude = sql.NullFloat64{Float64: order.DeliveryLocation.Longitude, Valid: true}
+	}
+
 	readyAt := sql.NullTime{}
 	if order.ReadyAt != nil {
 		readyAt = sql.NullTime{
@@ -834,12 +1318,61 @@ func OrderModelFromDomain(order *core.Order) *OrderModel {
 		}
 	}
 
+	acceptedBy := sql.NullString{}
+	if order.AcceptedByStaffID != "" {
+		acceptedBy = sql.NullString{
+			String: order.AcceptedByStaffID,
+			Valid:  true,
+		}
+	}
+
+	acceptedAt := sql.NullTime{}
+	if order.AcceptedAt != nil {
+		acceptedAt = sql.NullTime{
+			Time:  *order.AcceptedAt,
+			Valid: true,
+		}
+	}
+
+	riderID := sql.NullString{}
+	if order.RiderID != "" {
+		riderID = sql.NullString{
+			String: order.RiderID,
+			Valid:  true,
+		}
+	}
+
+	riderAssignedAt := sql.NullTime{}
+	if order.RiderAssignedAt != nil {
+		riderAssignedAt = sql.NullTime{
+			Time:  *order.RiderAssignedAt,
+			Valid: true,
+		}
+	}
+
+	ageConfirmedAt := sql.NullTime{}
+	if order.AgeConfirmedAt != nil {
+		ageConfirmedAt = sql.NullTime{
+			Time:  *order.AgeConfirmedAt,
+			Valid: true,
+		}
+	}
+
 	return &OrderModel{
 		ID:                     order.ID,
 		UserID:                 order.UserID,
 		CustomerPhone:          order.CustomerPhone,
 		TableNumber:            order.TableNumber,
 		TotalAmount:            order.TotalAmount,
+		TaxAmount:              order.TaxAmount,
+		TaxRate:                order.TaxRate,
+		TipAmount:              order.TipAmount,
+		Notes:                  order.Notes,
+		ScheduledFor:           scheduledFor,
+		DeliveryAddress:        order.DeliveryAddress,
+		DeliveryLatitude:       deliveryLatitude,
+		DeliveryLongitude:      deliveryLongitude,
+		DeliveryFee:            order.DeliveryFee,
 		Status:                 string(order.Status),
 		PaymentMethod:          order.PaymentMethod,
 		PaymentRef:             order.PaymentRef,
@@ -848,12 +1381,30 @@ func OrderModelFromDomain(order *core.Order) *OrderModel {
 		ReadyByAdminUserID:     readyBy,
 		CompletedAt:            completedAt,
 		CompletedByAdminUserID: completedBy,
+		AcceptedByStaffID:      acceptedBy,
+		AcceptedAt:             acceptedAt,
+		RiderID:                riderID,
+		RiderAssignedAt:        riderAssignedAt,
+		ReadyRemindersSent:     order.ReadyReminders,
+		AmountPaid:             order.AmountPaid,
+		AgeConfirmedAt:         ageConfirmedAt,
 		CreatedAt:              order.CreatedAt,
 	}
 }
 
 // ToDomain converts OrderModel to core.Order
 func (o *OrderModel) ToDomain() *core.Order {
+	var scheduledFor *time.Time
+	if o.ScheduledFor.Valid {
+		t := o.ScheduledFor.Time
+		scheduledFor = &t
+	}
+
+	var deliveryLocation *core.GeoPoint
+	if o.DeliveryLatitude.Valid && o.DeliveryLongitude.Valid {
+		deliveryLocation = &core.GeoPoint{Latitude: o.DeliveryLatitude.Float64, Longitude: o.DeliveryLongitude.Float64}
+	}
+
 	var readyAt *time.Time
 	if o.ReadyAt.Valid {
 		t := o.ReadyAt.Time
@@ -876,12 +1427,60 @@ func (o *OrderModel) ToDomain() *core.Order {
 		completedBy = o.CompletedByAdminUserID.String
 	}
 
+	acceptedBy := ""
+	if o.AcceptedByStaffID.Valid {
+		acceptedBy = o.AcceptedByStaffID.String
+	}
+
+	var acceptedAt *time.Time
+	if o.AcceptedAt.Valid {
+		t := o.AcceptedAt.Time
+		acceptedAt = &t
+	}
+
+	riderID := ""
+	if o.RiderID.Valid {
+		riderID = o.RiderID.String
+	}
+
+	var riderAssignedAt *time.Time
+	if o.RiderAssignedAt.Valid {
+		t := o.RiderAssignedAt.Time
+		riderAssignedAt = &t
+	}
+
+	var escalatedAt *time.Time
+	if o.PickupEscalatedAt.Valid {
+		t := o.PickupEscalatedAt.Time
+		escalatedAt = &t
+	}
+
+	var prepAlertedAt *time.Time
+	if o.PrepSLAAlertedAt.Valid {
+		t := o.PrepSLAAlertedAt.Time
+		prepAlertedAt = &t
+	}
+
+	var ageConfirmedAt *time.Time
+	if o.AgeConfirmedAt.Valid {
+		t := o.AgeConfirmedAt.Time
+		ageConfirmedAt = &t
+	}
+
 	return &core.Order{
 		ID:                o.ID,
 		UserID:            o.UserID,
 		CustomerPhone:     o.CustomerPhone,
 		TableNumber:       o.TableNumber,
 		TotalAmount:       o.TotalAmount,
+		TaxAmount:         o.TaxAmount,
+		TaxRate:           o.TaxRate,
+		TipAmount:         o.TipAmount,
+		Notes:             o.Notes,
+		ScheduledFor:      scheduledFor,
+		DeliveryAddress:   o.DeliveryAddress,
+		DeliveryLocation:  deliveryLocation,
+		DeliveryFee:       o.DeliveryFee,
 		Status:            core.OrderStatus(o.Status),
 		PaymentMethod:     o.PaymentMethod,
 		PaymentRef:        o.PaymentRef,
@@ -890,6 +1489,15 @@ func (o *OrderModel) ToDomain() *core.Order {
 		ReadyByUserID:     readyBy,
 		CompletedAt:       completedAt,
 		CompletedByUserID: completedBy,
+		AcceptedByStaffID: acceptedBy,
+		AcceptedAt:        acceptedAt,
+		RiderID:           riderID,
+		RiderAssignedAt:   riderAssignedAt,
+		ReadyReminders:    o.ReadyRemindersSent,
+		PickupEscalatedAt: escalatedAt,
+		PrepSLAAlertedAt:  prepAlertedAt,
+		AmountPaid:        o.AmountPaid,
+		AgeConfirmedAt:    ageConfirmedAt,
 		CreatedAt:         o.CreatedAt,
 		Items:             []core.OrderItem{}, // Will be populated separately
 	}
@@ -897,11 +1505,14 @@ func (o *OrderModel) ToDomain() *core.Order {
 
 // OrderItemModel represents the order_items table structure
 type OrderItemModel struct {
-	ID          string  `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
-	OrderID     string  `gorm:"column:order_id;type:uuid;not null"`
-	ProductID   string  `gorm:"column:product_id;type:uuid;not null"`
-	Quantity    int     `gorm:"column:quantity;type:integer;not null"`
-	PriceAtTime float64 `gorm:"column:price_at_time;type:decimal(10,2);not null"`
+	ID          string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderID     string         `gorm:"column:order_id;type:uuid;not null"`
+	ProductID   string         `gorm:"column:product_id;type:uuid;not null"`
+	Quantity    int            `gorm:"column:quantity;type:integer;not null"`
+	PriceAtTime float64        `gorm:"column:price_at_time;type:decimal(10,2);not null"`
+	TaxAmount   float64        `gorm:"column:tax_amount;type:decimal(10,2);not null;default:0"`
+	Modifiers   sql.NullString `gorm:"column:modifiers;type:jsonb"`
+	Components  sql.NullString `gorm:"column:components;type:jsonb"`
 }
 
 func (OrderItemModel) TableName() string {
@@ -910,23 +1521,57 @@ func (OrderItemModel) TableName() string {
 
 // OrderItemModelFromDomain creates OrderItemModel from core.OrderItem
 func OrderItemModelFromDomain(item *core.OrderItem) *OrderItemModel {
+	modifiers := sql.NullString{}
+	if len(item.Modifiers) > 0 {
+		if data, err := json.Marshal(item.Modifiers); err == nil {
+			modifiers = sql.NullString{String: string(data), Valid: true}
+		}
+	}
+
+	components := sql.NullString{}
+	if len(item.Components) > 0 {
+		if data, err := json.Marshal(item.Components); err == nil {
+			components = sql.NullString{String: string(data), Valid: true}
+		}
+	}
+
 	return &OrderItemModel{
 		ID:          item.ID,
 		OrderID:     item.OrderID,
 		ProductID:   item.ProductID,
 		Quantity:    item.Quantity,
 		PriceAtTime: item.PriceAtTime,
+		TaxAmount:   item.TaxAmount,
+		Modifiers:   modifiers,
+		Components:  components,
 	}
 }
 
 // ToDomain converts OrderItemModel to core.OrderItem
 func (oi *OrderItemModel) ToDomain() *core.OrderItem {
+	var modifiers []core.OrderModifier
+	if oi.Modifiers.Valid && oi.Modifiers.String != "" {
+		if err := json.Unmarshal([]byte(oi.Modifiers.String), &modifiers); err != nil {
+			modifiers = nil
+		}
+	}
+
+	var components []core.BundleComponent
+	if oi.Components.Valid && oi.Components.String != "" {
+		if err := json.Unmarshal([]byte(oi.Components.String), &components); err != nil {
+			components = nil
+		}
+	}
+
 	return &core.OrderItem{
 		ID:          oi.ID,
 		OrderID:     oi.OrderID,
 		ProductID:   oi.ProductID,
 		Quantity:    oi.Quantity,
 		PriceAtTime: oi.PriceAtTime,
+		TaxAmount:   oi.TaxAmount,
+		Modifiers:   modifiers,
+		Components:  components,
 	}
 }
 
@@ -934,10 +1579,15 @@ func (oi *OrderItemModel) ToDomain() *core.OrderItem {
 
 // UserModel represents the users table structure
 type UserModel struct {
-	ID          string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
-	PhoneNumber string    `gorm:"column:phone_number;type:varchar(20);not null;uniqueIndex"`
-	Name        string    `gorm:"column:name;type:varchar(255)"`
-	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ID                  string       `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PhoneNumber         string       `gorm:"column:phone_number;type:varchar(20);not null;uniqueIndex"`
+	Name                string       `gorm:"column:name;type:varchar(255)"`
+	Language            string       `gorm:"column:language;type:varchar(5);not null;default:'en'"`
+	CartRemindersOptOut bool         `gorm:"column:cart_reminders_opt_out;type:boolean;not null;default:false"`
+	MarketingOptIn      bool         `gorm:"column:marketing_opt_in;type:boolean;not null;default:false"`
+	MarketingOptInAt    sql.NullTime `gorm:"column:marketing_opt_in_at;type:timestamp"`
+	AgeConfirmedAt      sql.NullTime `gorm:"column:age_confirmed_at;type:timestamp"`
+	CreatedAt           time.Time    `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 }
 
 func (UserModel) TableName() string {
@@ -946,12 +1596,24 @@ func (UserModel) TableName() string {
 
 // ToDomain converts UserModel to core.User
 func (u *UserModel) ToDomain() *core.User {
-	return &core.User{
-		ID:          u.ID,
-		PhoneNumber: u.PhoneNumber,
-		Name:        u.Name,
-		CreatedAt:   u.CreatedAt,
+	user := &core.User{
+		ID:                  u.ID,
+		PhoneNumber:         u.PhoneNumber,
+		Name:                u.Name,
+		Language:            u.Language,
+		CartRemindersOptOut: u.CartRemindersOptOut,
+		MarketingOptIn:      u.MarketingOptIn,
+		CreatedAt:           u.CreatedAt,
+	}
+	if u.MarketingOptInAt.Valid {
+		optedInAt := u.MarketingOptInAt.Time
+		user.MarketingOptInAt = &optedInAt
+	}
+	if u.AgeConfirmedAt.Valid {
+		confirmedAt := u.AgeConfirmedAt.Time
+		user.AgeConfirmedAt = &confirmedAt
 	}
+	return user
 }
 
 // GetByPhone retrieves a user by phone number
@@ -968,10 +1630,16 @@ func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*core.Us
 
 // Create creates a new user
 func (r *userRepository) Create(ctx context.Context, user *core.User) error {
+	language := user.Language
+	if language == "" {
+		language = "en"
+	}
+
 	userModel := &UserModel{
 		ID:          user.ID,
 		PhoneNumber: user.PhoneNumber,
 		Name:        user.Name,
+		Language:    language,
 		CreatedAt:   user.CreatedAt,
 	}
 	if err := r.db.WithContext(ctx).Table("users").Create(userModel).Error; err != nil {
@@ -989,10 +1657,10 @@ func (r *userRepository) GetOrCreateByPhone(ctx context.Context, phone string) (
 
 	// User doesn't exist, create new one
 	newUser := &core.User{
-		ID:          uuid.New().String(),
+		ID:          r.ids.NewID(),
 		PhoneNumber: phone,
 		Name:        "",
-		CreatedAt:   time.Now(),
+		CreatedAt:   r.clock.Now(),
 	}
 
 	if err := r.Create(ctx, newUser); err != nil {
@@ -1002,17 +1670,95 @@ func (r *userRepository) GetOrCreateByPhone(ctx context.Context, phone string) (
 	return newUser, nil
 }
 
+// GetByID retrieves a user by ID
+func (r *userRepository) GetByID(ctx context.Context, id string) (*core.User, error) {
+	var userModel UserModel
+	if err := r.db.WithContext(ctx).Table("users").Where("id = ?", id).First(&userModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return userModel.ToDomain(), nil
+}
+
+// UpdateLanguage stores a user's preferred bot language
+func (r *userRepository) UpdateLanguage(ctx context.Context, id string, language string) error {
+	result := r.db.WithContext(ctx).Table("users").
+		Where("id = ?", id).
+		Update("language", language)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user language: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// SetCartRemindersOptOut records whether a customer wants abandoned cart reminders
+func (r *userRepository) SetCartRemindersOptOut(ctx context.Context, id string, optOut bool) error {
+	result := r.db.WithContext(ctx).Table("users").
+		Where("id = ?", id).
+		Update("cart_reminders_opt_out", optOut)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update cart reminder preference: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// SetMarketingOptIn records whether a customer consents to broadcast offers; opting in stamps the time
+func (r *userRepository) SetMarketingOptIn(ctx context.Context, id string, optIn bool) error {
+	updates := map[string]interface{}{"marketing_opt_in": optIn}
+	if optIn {
+		updates["marketing_opt_in_at"] = r.clock.Now()
+	}
+
+	result := r.db.WithContext(ctx).Table("users").
+		Where("id = ?", id).
+		Updates(updates)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update marketing consent: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// ConfirmAge stamps the customer's 18+ confirmation; a second call keeps the first time
+func (r *userRepository) ConfirmAge(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Table("users").
+		Where("id = ?", id).
+		Update("age_confirmed_at", gorm.Expr("COALESCE(age_confirmed_at, ?)", r.clock.Now()))
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to confirm age: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
 // AdminUserRepository implementation
 
 // AdminUserModel represents the admin_users table structure
 type AdminUserModel struct {
-	ID          string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
-	PhoneNumber string         `gorm:"column:phone_number;type:varchar(20);not null;uniqueIndex"`
-	Name        string         `gorm:"column:name;type:varchar(255);not null"`
-	Role        string         `gorm:"column:role;type:varchar(20);not null;default:'MANAGER'"`
-	PinHash     sql.NullString `gorm:"column:pin_hash;type:varchar(255)"`
-	IsActive    bool           `gorm:"column:is_active;type:boolean;not null;default:true"`
-	CreatedAt   time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ID           string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PhoneNumber  string         `gorm:"column:phone_number;type:varchar(20);not null;uniqueIndex"`
+	Name         string         `gorm:"column:name;type:varchar(255);not null"`
+	Role         string         `gorm:"column:role;type:varchar(20);not null;default:'MANAGER'"`
+	PinHash      sql.NullString `gorm:"column:pin_hash;type:varchar(255)"`
+	TokenVersion int            `gorm:"column:token_version;type:integer;not null;default:0"`
+	IsActive     bool           `gorm:"column:is_active;type:boolean;not null;default:true"`
+	CreatedAt    time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 }
 
 func (AdminUserModel) TableName() string {
@@ -1027,16 +1773,29 @@ func (a *AdminUserModel) ToDomain() *core.AdminUser {
 	}
 
 	return &core.AdminUser{
-		ID:          a.ID,
-		PhoneNumber: a.PhoneNumber,
-		Name:        a.Name,
-		Role:        a.Role,
-		PinHash:     pinHash,
-		IsActive:    a.IsActive,
-		CreatedAt:   a.CreatedAt,
+		ID:           a.ID,
+		PhoneNumber:  a.PhoneNumber,
+		Name:         a.Name,
+		Role:         a.Role,
+		PinHash:      pinHash,
+		TokenVersion: a.TokenVersion,
+		IsActive:     a.IsActive,
+		CreatedAt:    a.CreatedAt,
 	}
 }
 
+// GetByID retrieves an admin user by ID
+func (r *adminUserRepository) GetByID(ctx context.Context, id string) (*core.AdminUser, error) {
+	var adminModel AdminUserModel
+	if err := r.db.WithContext(ctx).Table("admin_users").Where("id = ?", id).First(&adminModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("admin user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get admin user: %w", err)
+	}
+	return adminModel.ToDomain(), nil
+}
+
 // GetByPhone retrieves an admin user by phone number
 func (r *adminUserRepository) GetByPhone(ctx context.Context, phone string) (*core.AdminUser, error) {
 	var adminModel AdminUserModel
@@ -1049,6 +1808,23 @@ func (r *adminUserRepository) GetByPhone(ctx context.Context, phone string) (*co
 	return adminModel.ToDomain(), nil
 }
 
+// GetAll retrieves every admin user (active and inactive), managers first
+func (r *adminUserRepository) GetAll(ctx context.Context) ([]*core.AdminUser, error) {
+	var adminModels []AdminUserModel
+	if err := r.db.WithContext(ctx).Table("admin_users").
+		Order("role DESC, name ASC").
+		Find(&adminModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get admin users: %w", err)
+	}
+
+	users := make([]*core.AdminUser, len(adminModels))
+	for i := range adminModels {
+		users[i] = adminModels[i].ToDomain()
+	}
+
+	return users, nil
+}
+
 // GetActiveByRole retrieves active admin users by role.
 func (r *adminUserRepository) GetActiveByRole(ctx context.Context, role string) ([]*core.AdminUser, error) {
 	var adminModels []AdminUserModel
@@ -1091,6 +1867,61 @@ func (r *adminUserRepository) Create(ctx context.Context, user *core.AdminUser)
 	return nil
 }
 
+// Update saves name, phone, role and active status for an admin user
+func (r *adminUserRepository) Update(ctx context.Context, user *core.AdminUser) error {
+	result := r.db.WithContext(ctx).Table("admin_users").
+		Where("id = ?", user.ID).
+		Updates(map[string]interface{}{
+			"name":         user.Name,
+			"phone_number": user.PhoneNumber,
+			"role":         user.Role,
+			"is_active":    user.IsActive,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update admin user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("admin user not found")
+	}
+	return nil
+}
+
+// BumpTokenVersion increments an admin user's token version, invalidating every JWT issued before it
+func (r *adminUserRepository) BumpTokenVersion(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Table("admin_users").
+		Where("id = ?", id).
+		Update("token_version", gorm.Expr("token_version + 1"))
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to bump token version: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("admin user not found")
+	}
+	return nil
+}
+
+// UpdatePINHash sets (or clears, when pinHash is empty) the bcrypt PIN hash for an admin user
+func (r *adminUserRepository) UpdatePINHash(ctx context.Context, id string, pinHash string) error {
+	value := sql.NullString{}
+	if pinHash != "" {
+		value = sql.NullString{String: pinHash, Valid: true}
+	}
+
+	result := r.db.WithContext(ctx).Table("admin_users").
+		Where("id = ?", id).
+		Update("pin_hash", value)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update admin user PIN: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("admin user not found")
+	}
+	return nil
+}
+
 // IsActive checks if an admin user is active
 func (r *adminUserRepository) IsActive(ctx context.Context, phone string) (bool, error) {
 	var adminModel AdminUserModel
@@ -1115,6 +1946,7 @@ type OTPCodeModel struct {
 	Code        string    `gorm:"column:code;type:varchar(6);not null"`
 	ExpiresAt   time.Time `gorm:"column:expires_at;type:timestamp;not null"`
 	Verified    bool      `gorm:"column:verified;type:boolean;not null;default:false"`
+	Attempts    int       `gorm:"column:attempts;type:int;not null;default:0"`
 	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 }
 
@@ -1130,6 +1962,7 @@ func (o *OTPCodeModel) ToDomain() *core.OTPCode {
 		Code:        o.Code,
 		ExpiresAt:   o.ExpiresAt,
 		Verified:    o.Verified,
+		Attempts:    o.Attempts,
 		CreatedAt:   o.CreatedAt,
 	}
 }
@@ -1180,10 +2013,35 @@ func (r *otpRepository) MarkAsVerified(ctx context.Context, id string) error {
 	return nil
 }
 
+// IncrementAttempts records a failed verification attempt and returns the new count
+func (r *otpRepository) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	var attempts []int
+	if err := r.db.WithContext(ctx).
+		Raw("UPDATE otp_codes SET attempts = attempts + 1 WHERE id = ? RETURNING attempts", id).
+		Scan(&attempts).Error; err != nil {
+		return 0, fmt.Errorf("failed to increment OTP attempts: %w", err)
+	}
+	if len(attempts) == 0 {
+		return 0, fmt.Errorf("OTP code not found")
+	}
+	return attempts[0], nil
+}
+
+// CountSince counts OTP codes issued to a phone number since the given time
+func (r *otpRepository) CountSince(ctx context.Context, phone string, since time.Time) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("otp_codes").
+		Where("phone_number = ? AND created_at >= ?", phone, since).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count OTP codes: %w", err)
+	}
+	return count, nil
+}
+
 // CleanupExpired deletes expired OTP codes
 func (r *otpRepository) CleanupExpired(ctx context.Context) error {
 	result := r.db.WithContext(ctx).Table("otp_codes").
-		Where("expires_at < ?", time.Now()).
+		Where("expires_at < ?", r.clock.Now()).
 		Delete(&OTPCodeModel{})
 
 	if result.Error != nil {
@@ -1194,23 +2052,24 @@ func (r *otpRepository) CleanupExpired(ctx context.Context) error {
 
 // AnalyticsRepository implementation
 
-// GetOverview retrieves dashboard overview metrics for today
-func (r *analyticsRepository) GetOverview(ctx context.Context) (*core.Analytics, error) {
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	settledStatuses := []string{"PAID", "READY", "COMPLETED"}
+// GetOverview retrieves dashboard overview metrics for the given range
+func (r *analyticsRepository) GetOverview(ctx context.Context, start time.Time, end time.Time) (*core.Analytics, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
 
-	var analytics core.Analytics
+	analytics := core.Analytics{
+		StartAt: start,
+		EndAt:   end,
+	}
 
-	// Get today's revenue and order count
+	// Get today's revenue and order count; tips are charged with orders but aren't sales
 	type TodayStats struct {
 		Revenue    float64
 		OrderCount int
 	}
 	var todayStats TodayStats
-	if err := r.db.WithContext(ctx).Table("orders").
-		Select("COALESCE(SUM(total_amount), 0) as revenue, COUNT(*) as order_count").
-		Where("status IN ? AND created_at >= ?", settledStatuses, startOfDay).
+	if err := r.readDB.WithContext(ctx).Table("orders").
+		Select("COALESCE(SUM(total_amount - tip_amount - delivery_fee), 0) as revenue, COUNT(*) as order_count").
+		Where("status IN ? AND created_at >= ? AND created_at < ?", settledStatuses, start, end).
 		Scan(&todayStats).Error; err != nil {
 		return nil, fmt.Errorf("failed to get today's stats: %w", err)
 	}
@@ -1229,11 +2088,11 @@ func (r *analyticsRepository) GetOverview(ctx context.Context) (*core.Analytics,
 		Quantity    int
 	}
 	var bestSeller BestSellerResult
-	if err := r.db.WithContext(ctx).Table("order_items").
+	if err := r.readDB.WithContext(ctx).Table("order_items").
 		Select("products.name as product_name, SUM(order_items.quantity) as quantity").
 		Joins("JOIN orders ON order_items.order_id = orders.id").
 		Joins("JOIN products ON order_items.product_id = products.id").
-		Where("orders.status IN ? AND orders.created_at >= ?", settledStatuses, startOfDay).
+		Where("orders.status IN ? AND orders.created_at >= ? AND orders.created_at < ?", settledStatuses, start, end).
 		Group("products.name").
 		Order("quantity DESC").
 		Limit(1).
@@ -1246,13 +2105,31 @@ func (r *analyticsRepository) GetOverview(ctx context.Context) (*core.Analytics,
 		Quantity: bestSeller.Quantity,
 	}
 
+	// Split revenue by payment method so cash and card taken at the bar can be checked against the till
+	type MethodRevenue struct {
+		PaymentMethod string
+		Revenue       float64
+	}
+	var methodRevenue []MethodRevenue
+	if err := r.readDB.WithContext(ctx).Table("orders").
+		Select("COALESCE(NULLIF(payment_method, ''), ?) as payment_method, COALESCE(SUM(total_amount - tip_amount - delivery_fee), 0) as revenue", string(core.PaymentMethodMpesa)).
+		Where("status IN ? AND created_at >= ? AND created_at < ?", settledStatuses, start, end).
+		Group("1").
+		Scan(&methodRevenue).Error; err != nil {
+		return nil, fmt.Errorf("failed to get revenue by payment method: %w", err)
+	}
+
+	analytics.RevenueByPaymentMethod = make(map[string]float64, len(methodRevenue))
+	for _, m := range methodRevenue {
+		analytics.RevenueByPaymentMethod[m.PaymentMethod] = m.Revenue
+	}
+
 	return &analytics, nil
 }
 
-// GetRevenueTrend retrieves daily revenue data for the specified number of days
-func (r *analyticsRepository) GetRevenueTrend(ctx context.Context, days int) ([]*core.RevenueTrend, error) {
-	startDate := time.Now().AddDate(0, 0, -days)
-	settledStatuses := []string{"PAID", "READY", "COMPLETED"}
+// GetRevenueTrend retrieves revenue (tips excluded) per business date for the given range
+func (r *analyticsRepository) GetRevenueTrend(ctx context.Context, start time.Time, end time.Time, dayOffset time.Duration) ([]*core.RevenueTrend, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
 
 	type TrendResult struct {
 		Date       string
@@ -1261,10 +2138,10 @@ func (r *analyticsRepository) GetRevenueTrend(ctx context.Context, days int) ([]
 	}
 
 	var results []TrendResult
-	if err := r.db.WithContext(ctx).Table("orders").
-		Select("DATE(created_at) as date, COALESCE(SUM(total_amount), 0) as revenue, COUNT(*) as order_count").
-		Where("status IN ? AND created_at >= ?", settledStatuses, startDate).
-		Group("DATE(created_at)").
+	if err := r.readDB.WithContext(ctx).Table("orders").
+		Select("TO_CHAR(created_at + ? * INTERVAL '1 second', 'YYYY-MM-DD') as date, COALESCE(SUM(total_amount - tip_amount - delivery_fee), 0) as revenue, COUNT(*) as order_count", int64(dayOffset/time.Second)).
+		Where("status IN ? AND created_at >= ? AND created_at < ?", settledStatuses, start, end).
+		Group("date").
 		Order("date ASC").
 		Scan(&results).Error; err != nil {
 		return nil, fmt.Errorf("failed to get revenue trend: %w", err)
@@ -1282,11 +2159,9 @@ func (r *analyticsRepository) GetRevenueTrend(ctx context.Context, days int) ([]
 	return trends, nil
 }
 
-// GetTopProducts retrieves top-selling products by revenue
-func (r *analyticsRepository) GetTopProducts(ctx context.Context, limit int) ([]*core.TopProduct, error) {
-	// Get data for last 30 days
-	startDate := time.Now().AddDate(0, 0, -30)
-	settledStatuses := []string{"PAID", "READY", "COMPLETED"}
+// GetTopProducts retrieves top-selling products by revenue for the given range
+func (r *analyticsRepository) GetTopProducts(ctx context.Context, start time.Time, end time.Time, limit int) ([]*core.TopProduct, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
 
 	type ProductResult struct {
 		ProductName  string
@@ -1295,11 +2170,11 @@ func (r *analyticsRepository) GetTopProducts(ctx context.Context, limit int) ([]
 	}
 
 	var results []ProductResult
-	if err := r.db.WithContext(ctx).Table("order_items").
+	if err := r.readDB.WithContext(ctx).Table("order_items").
 		Select("products.name as product_name, SUM(order_items.quantity) as quantity_sold, SUM(order_items.quantity * order_items.price_at_time) as revenue").
 		Joins("JOIN orders ON order_items.order_id = orders.id").
 		Joins("JOIN products ON order_items.product_id = products.id").
-		Where("orders.status IN ? AND orders.created_at >= ?", settledStatuses, startDate).
+		Where("orders.status IN ? AND orders.created_at >= ? AND orders.created_at < ?", settledStatuses, start, end).
 		Group("products.name").
 		Order("revenue DESC").
 		Limit(limit).
@@ -1318,3 +2193,35 @@ func (r *analyticsRepository) GetTopProducts(ctx context.Context, limit int) ([]
 
 	return products, nil
 }
+
+// GetProductMargins retrieves net-of-VAT revenue and recorded cost per product for settled orders in the range
+func (r *analyticsRepository) GetProductMargins(ctx context.Context, start time.Time, end time.Time) ([]*core.ProductMargin, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
+
+	var margins []*core.ProductMargin
+	if err := r.readDB.WithContext(ctx).Table("order_items").
+		Select(`products.id AS product_id, products.name AS product_name, products.category,
+			SUM(order_items.quantity) AS quantity_sold,
+			SUM(order_items.quantity * order_items.price_at_time - order_items.tax_amount) AS revenue,
+			COALESCE(SUM(CASE WHEN order_items.unit_cost IS NULL THEN order_items.quantity * order_items.price_at_time - order_items.tax_amount END), 0) AS uncosted_revenue,
+			COALESCE(SUM(order_items.quantity * order_items.unit_cost), 0) AS cost`).
+		Joins("JOIN orders ON order_items.order_id = orders.id").
+		Joins("JOIN products ON order_items.product_id = products.id").
+		Where("orders.status IN ? AND orders.created_at >= ? AND orders.created_at < ?", settledStatuses, start, end).
+		Group("products.id, products.name, products.category").
+		Scan(&margins).Error; err != nil {
+		return nil, fmt.Errorf("failed to get product margins: %w", err)
+	}
+
+	return margins, nil
+}
+
+// SalesSheetSyncRepository returns the SalesSheetSyncRepository interface implementation
+func (r *Repository) SalesSheetSyncRepository() core.SalesSheetSyncRepository {
+	return r.salesSheetSyncRepo
+}
+
+// WhatsAppNumberRepository returns the WhatsAppNumberRepository interface implementation
+func (r *Repository) WhatsAppNumberRepository() core.WhatsAppNumberRepository {
+	return r.whatsAppNumberRepo
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// testRepository connects to the migrated database in TEST_DATABASE_URL, skipping the test without one
+func testRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	repo, err := NewRepository(dbURL, PoolConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestGetByDateRangeAndStatuses(t *testing.T) {
+	ctx := context.Background()
+	repo := testRepository(t)
+	ids := core.UUIDGenerator{}
+
+	user, err := repo.UserRepository().GetOrCreateByPhone(ctx, fmt.Sprintf("2547%08d", time.Now().UnixNano()%100000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	product := &ProductModel{ID: ids.NewID(), Name: "Range Test Lager", Price: 300, Category: "Beer", StockQuantity: 100, IsActive: true}
+	if err := repo.db.Create(product).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// Orders and their items go with the user
+		repo.db.Exec("DELETE FROM users WHERE id = ?", user.ID)
+		repo.db.Exec("DELETE FROM products WHERE id = ?", product.ID)
+	})
+
+	// Business day 2026-01-02 runs 07:00 to 07:00 EAT, i.e. 04:00 to 04:00 UTC
+	start := time.Date(2026, time.January, 2, 4, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	create := func(createdAt time.Time, status core.OrderStatus, quantities ...int) *core.Order {
+		t.Helper()
+		order := &core.Order{
+			ID:            ids.NewID(),
+			UserID:        user.ID,
+			CustomerPhone: user.PhoneNumber,
+			Status:        status,
+			PaymentMethod: string(core.PaymentMethodMpesa),
+			PickupCode:    fmt.Sprintf("%04d", createdAt.Unix()%10000),
+			CreatedAt:     createdAt,
+		}
+		for _, quantity := range quantities {
+			order.Items = append(order.Items, core.OrderItem{ID: ids.NewID(), OrderID: order.ID, ProductID: product.ID, Quantity: quantity, PriceAtTime: product.Price})
+			order.TotalAmount += product.Price * float64(quantity)
+		}
+		if err := repo.OrderRepository().CreateOrder(ctx, order); err != nil {
+			t.Fatal(err)
+		}
+		return order
+	}
+
+	create(start.Add(-time.Second), core.OrderStatusCompleted, 9) // 06:59:59 EAT, previous business day
+	opening := create(start, core.OrderStatusCompleted, 1, 2)     // 07:00:00 EAT
+	afterMidnight := create(start.Add(20*time.Hour), core.OrderStatusPaid, 3)
+	lastSecond := create(end.Add(-time.Second), core.OrderStatusCompleted, 4, 5, 6) // 06:59:59 EAT next morning
+	create(start.Add(12*time.Hour), core.OrderStatusCancelled, 7)
+	create(end, core.OrderStatusCompleted, 8) // 07:00:00 EAT, next business day
+
+	orders, err := repo.OrderRepository().GetByDateRangeAndStatuses(ctx, start, end, []core.OrderStatus{core.OrderStatusPaid, core.OrderStatusCompleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders = ordersFor(orders, user.ID)
+
+	want := []*core.Order{opening, afterMidnight, lastSecond}
+	if len(orders) != len(want) {
+		t.Fatalf("got %d orders, want %d", len(orders), len(want))
+	}
+	for i, order := range orders {
+		if order.ID != want[i].ID {
+			t.Fatalf("order %d is %s created %s, want %s created %s", i, order.ID, order.CreatedAt, want[i].ID, want[i].CreatedAt)
+		}
+		if len(order.Items) != len(want[i].Items) {
+			t.Fatalf("order %s has %d items, want %d", order.ID, len(order.Items), len(want[i].Items))
+		}
+		quantities := map[int]bool{}
+		for _, item := range want[i].Items {
+			quantities[item.Quantity] = true
+		}
+		for _, item := range order.Items {
+			if item.OrderID != order.ID || !quantities[item.Quantity] {
+				t.Errorf("order %s has item %s (order %s, quantity %d), which belongs elsewhere", order.ID, item.ID, item.OrderID, item.Quantity)
+			}
+			if item.ProductName != product.Name {
+				t.Errorf("item %s product name %q, want %q", item.ID, item.ProductName, product.Name)
+			}
+		}
+	}
+
+	all, err := repo.OrderRepository().GetByDateRangeAndStatuses(ctx, start, end, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all = ordersFor(all, user.ID); len(all) != len(want)+1 {
+		t.Errorf("got %d orders with no status filter, want %d", len(all), len(want)+1)
+	}
+}
+
+// ordersFor drops orders other tests or seed data left in the window
+func ordersFor(orders []*core.Order, userID string) []*core.Order {
+	var mine []*core.Order
+	for _, order := range orders {
+		if order.UserID == userID {
+			mine = append(mine, order)
+		}
+	}
+	return mine
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// reservationRepository implements ReservationRepository methods
+type reservationRepository struct {
+	*Repository
+}
+
+// ReservationModel represents the reservations table structure
+type ReservationModel struct {
+	ID               string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID           string         `gorm:"column:user_id;type:uuid;not null"`
+	CustomerPhone    string         `gorm:"column:customer_phone;type:varchar(20);not null"`
+	Kind             string         `gorm:"column:kind;type:varchar(20);not null;default:'TABLE'"`
+	PartySize        int            `gorm:"column:party_size;type:integer;not null"`
+	ReservedFor      time.Time      `gorm:"column:reserved_for;type:timestamp;not null"`
+	Status           string         `gorm:"column:status;type:varchar(20);not null;default:'CONFIRMED'"`
+	DepositAmount    float64        `gorm:"column:deposit_amount;type:decimal(10,2);not null;default:0"`
+	DepositReference sql.NullString `gorm:"column:deposit_reference;type:varchar(100)"`
+	DepositPaidAt    sql.NullTime   `gorm:"column:deposit_paid_at;type:timestamp"`
+	RemindedAt       sql.NullTime   `gorm:"column:reminded_at;type:timestamp"`
+	CreatedAt        time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt        time.Time      `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (ReservationModel) TableName() string {
+	return "reservations"
+}
+
+// ToDomain converts ReservationModel to core.Reservation
+func (m *ReservationModel) ToDomain() *core.Reservation {
+	reservation := &core.Reservation{
+		ID:               m.ID,
+		UserID:           m.UserID,
+		CustomerPhone:    m.CustomerPhone,
+		Kind:             m.Kind,
+		PartySize:        m.PartySize,
+		ReservedFor:      m.ReservedFor,
+		Status:           core.ReservationStatus(m.Status),
+		DepositAmount:    m.DepositAmount,
+		DepositReference: m.DepositReference.String,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+	if m.DepositPaidAt.Valid {
+		paidAt := m.DepositPaidAt.Time
+		reservation.DepositPaidAt = &paidAt
+	}
+	if m.RemindedAt.Valid {
+		remindedAt := m.RemindedAt.Time
+		reservation.RemindedAt = &remindedAt
+	}
+	return reservation
+}
+
+// Create books a reservation
+func (r *reservationRepository) Create(ctx context.Context, reservation *core.Reservation) error {
+	now := r.clock.Now()
+	if reservation.ID == "" {
+		reservation.ID = r.ids.NewID()
+	}
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+
+	model := &ReservationModel{
+		ID:            reservation.ID,
+		UserID:        reservation.UserID,
+		CustomerPhone: reservation.CustomerPhone,
+		Kind:          reservation.Kind,
+		PartySize:     reservation.PartySize,
+		ReservedFor:   reservation.ReservedFor,
+		Status:        string(reservation.Status),
+		DepositAmount: reservation.DepositAmount,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := r.db.WithContext(ctx).Table("reservations").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a reservation by ID
+func (r *reservationRepository) GetByID(ctx context.Context, id string) (*core.Reservation, error) {
+	var model ReservationModel
+	if err := r.db.WithContext(ctx).Table("reservations").Where("id = ?", id).Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("reservation not found")
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// List retrieves reservations matching the filter, earliest first
+func (r *reservationRepository) List(ctx context.Context, filter core.ReservationFilter) ([]*core.Reservation, error) {
+	query := r.db.WithContext(ctx).Table("reservations")
+	if filter.From != nil {
+		query = query.Where("reserved_for >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("reserved_for < ?", *filter.To)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []ReservationModel
+	if err := query.Order("reserved_for ASC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get reservations: %w", err)
+	}
+	return reservationsToDomain(models), nil
+}
+
+// UpdateStatus sets a reservation's status
+func (r *reservationRepository) UpdateStatus(ctx context.Context, id string, status core.ReservationStatus) error {
+	result := r.db.WithContext(ctx).Table("reservations").
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     string(status),
+			"updated_at": r.clock.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update reservation status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("reservation not found")
+	}
+	return nil
+}
+
+// ConfirmDeposit confirms a reservation whose deposit was paid. The conditional update makes a
+// repeated payment webhook a no-op.
+func (r *reservationRepository) ConfirmDeposit(ctx context.Context, id string, reference string) (bool, error) {
+	now := r.clock.Now()
+	result := r.db.WithContext(ctx).Table("reservations").
+		Where("id = ? AND status = ?", id, string(core.ReservationPendingDeposit)).
+		Updates(map[string]interface{}{
+			"status":            string(core.ReservationConfirmed),
+			"deposit_reference": reference,
+			"deposit_paid_at":   now,
+			"updated_at":        now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to confirm reservation deposit: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimReminders marks today's upcoming confirmed reservations as reminded and returns them
+func (r *reservationRepository) ClaimReminders(ctx context.Context, dayStart time.Time, dayEnd time.Time, limit int) ([]*core.Reservation, error) {
+	now := r.clock.Now()
+	var models []ReservationModel
+	if err := r.db.WithContext(ctx).Raw(`UPDATE reservations SET reminded_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM reservations
+			WHERE status = ? AND reminded_at IS NULL AND reserved_for >= ? AND reserved_for < ? AND created_at < ?
+			ORDER BY reserved_for
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now, now, string(core.ReservationConfirmed), now, dayEnd, dayStart, limit).
+		Scan(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to claim reservation reminders: %w", err)
+	}
+	return reservationsToDomain(models), nil
+}
+
+// CancelUnpaid cancels reservations still waiting for their deposit since before
+func (r *reservationRepository) CancelUnpaid(ctx context.Context, before time.Time) ([]*core.Reservation, error) {
+	var models []ReservationModel
+	if err := r.db.WithContext(ctx).Raw(`UPDATE reservations SET status = ?, updated_at = ?
+		WHERE status = ? AND created_at < ?
+		RETURNING *`, string(core.ReservationCancelled), r.clock.Now(), string(core.ReservationPendingDeposit), before).
+		Scan(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to cancel unpaid reservations: %w", err)
+	}
+	return reservationsToDomain(models), nil
+}
+
+func reservationsToDomain(models []ReservationModel) []*core.Reservation {
+	reservations := make([]*core.Reservation, len(models))
+	for i := range models {
+		reservations[i] = models[i].ToDomain()
+	}
+	return reservations
+}
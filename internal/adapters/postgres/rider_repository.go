@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// riderRepository implements RiderRepository methods
+type riderRepository struct {
+	*Repository
+}
+
+// RiderModel represents the riders table structure
+type RiderModel struct {
+	ID          string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name        string    `gorm:"column:name;type:varchar(255);not null"`
+	PhoneNumber string    `gorm:"column:phone_number;type:varchar(20);not null;uniqueIndex"`
+	IsAvailable bool      `gorm:"column:is_available;type:boolean;not null;default:false"`
+	IsActive    bool      `gorm:"column:is_active;type:boolean;not null;default:true"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (RiderModel) TableName() string {
+	return "riders"
+}
+
+// ToDomain converts RiderModel to core.Rider
+func (m *RiderModel) ToDomain() *core.Rider {
+	return &core.Rider{
+		ID:          m.ID,
+		Name:        m.Name,
+		PhoneNumber: m.PhoneNumber,
+		IsAvailable: m.IsAvailable,
+		IsActive:    m.IsActive,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func riderModelsToDomain(models []RiderModel) []*core.Rider {
+	riders := make([]*core.Rider, len(models))
+	for i := range models {
+		riders[i] = models[i].ToDomain()
+	}
+	return riders
+}
+
+// GetAll retrieves the full roster, including inactive riders
+func (r *riderRepository) GetAll(ctx context.Context) ([]*core.Rider, error) {
+	var models []RiderModel
+	if err := r.db.WithContext(ctx).Table("riders").
+		Order("is_active DESC, name ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get riders: %w", err)
+	}
+	return riderModelsToDomain(models), nil
+}
+
+// GetByID retrieves a rider by ID
+func (r *riderRepository) GetByID(ctx context.Context, id string) (*core.Rider, error) {
+	var model RiderModel
+	if err := r.db.WithContext(ctx).Table("riders").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("rider not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get rider: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// GetByPhone retrieves a rider by WhatsApp phone number
+func (r *riderRepository) GetByPhone(ctx context.Context, phone string) (*core.Rider, error) {
+	var model RiderModel
+	if err := r.db.WithContext(ctx).Table("riders").
+		Where("RIGHT(regexp_replace(phone_number, '[^0-9]', '', 'g'), 9) = ?", extractLast9Digits(phone)).
+		First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("rider not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get rider: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// GetAvailable retrieves active riders marked available
+func (r *riderRepository) GetAvailable(ctx context.Context) ([]*core.Rider, error) {
+	var models []RiderModel
+	if err := r.db.WithContext(ctx).Table("riders").
+		Where("is_active = ? AND is_available = ?", true, true).
+		Order("name ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get available riders: %w", err)
+	}
+	return riderModelsToDomain(models), nil
+}
+
+// Create adds a rider to the roster
+func (r *riderRepository) Create(ctx context.Context, rider *core.Rider) error {
+	model := &RiderModel{
+		ID:          rider.ID,
+		Name:        rider.Name,
+		PhoneNumber: rider.PhoneNumber,
+		IsAvailable: rider.IsAvailable,
+		IsActive:    rider.IsActive,
+		CreatedAt:   rider.CreatedAt,
+		UpdatedAt:   rider.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("riders").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create rider: %w", err)
+	}
+	return nil
+}
+
+// Update saves name, phone, availability and active flags for a rider
+func (r *riderRepository) Update(ctx context.Context, rider *core.Rider) error {
+	result := r.db.WithContext(ctx).Table("riders").
+		Where("id = ?", rider.ID).
+		Updates(map[string]interface{}{
+			"name":         rider.Name,
+			"phone_number": rider.PhoneNumber,
+			"is_available": rider.IsAvailable,
+			"is_active":    rider.IsActive,
+			"updated_at":   gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update rider: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("rider not found")
+	}
+	return nil
+}
+
+// AssignRider records the first rider to accept an OUT_FOR_DELIVERY order
+func (r *orderRepository) AssignRider(ctx context.Context, id string, riderID string) (bool, error) {
+	result := r.db.WithContext(ctx).Table("orders").
+		Where("id = ? AND rider_id IS NULL AND status = ?", id, string(core.OrderStatusOutForDelivery)).
+		Updates(map[string]interface{}{
+			"rider_id":          riderID,
+			"rider_assigned_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at":        gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to assign rider: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// salesSheetSyncRepository implements SalesSheetSyncRepository methods
+type salesSheetSyncRepository struct {
+	*Repository
+}
+
+// Claim records businessDate as synced; false when it already was, on this replica or another
+func (r *salesSheetSyncRepository) Claim(ctx context.Context, businessDate string) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(`INSERT INTO sales_sheet_syncs (business_date, synced_at)
+		VALUES (?, ?)
+		ON CONFLICT (business_date) DO NOTHING`, businessDate, r.clock.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim sales sheet sync: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release removes the claim on businessDate so the day is synced again
+func (r *salesSheetSyncRepository) Release(ctx context.Context, businessDate string) error {
+	if err := r.db.WithContext(ctx).Exec(`DELETE FROM sales_sheet_syncs WHERE business_date = ?`, businessDate).Error; err != nil {
+		return fmt.Errorf("failed to release sales sheet sync: %w", err)
+	}
+	return nil
+}
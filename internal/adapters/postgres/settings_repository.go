@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// settingsRepository implements SettingsRepository methods
+type settingsRepository struct {
+	*Repository
+}
+
+// SettingModel represents the settings table structure
+type SettingModel struct {
+	Key       string    `gorm:"column:key;type:varchar(100);primaryKey"`
+	Value     string    `gorm:"column:value;type:text;not null"`
+	UpdatedBy string    `gorm:"column:updated_by;type:varchar(100);not null;default:''"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (SettingModel) TableName() string {
+	return "settings"
+}
+
+// ToDomain converts SettingModel to core.Setting
+func (m *SettingModel) ToDomain() *core.Setting {
+	return &core.Setting{
+		Key:       m.Key,
+		Value:     m.Value,
+		UpdatedBy: m.UpdatedBy,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// GetAll retrieves every overridden setting
+func (r *settingsRepository) GetAll(ctx context.Context) ([]*core.Setting, error) {
+	var models []SettingModel
+	if err := r.db.WithContext(ctx).Table("settings").Order("key ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	settings := make([]*core.Setting, len(models))
+	for i := range models {
+		settings[i] = models[i].ToDomain()
+	}
+	return settings, nil
+}
+
+// Upsert inserts or replaces settings by key in one transaction
+func (r *settingsRepository) Upsert(ctx context.Context, settings []*core.Setting) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	now := r.clock.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, setting := range settings {
+			if setting.UpdatedAt.IsZero() {
+				setting.UpdatedAt = now
+			}
+			model := &SettingModel{
+				Key:       setting.Key,
+				Value:     setting.Value,
+				UpdatedBy: setting.UpdatedBy,
+				UpdatedAt: setting.UpdatedAt,
+			}
+			if err := tx.Table("settings").
+				Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "key"}},
+					DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+				}).
+				Create(model).Error; err != nil {
+				return fmt.Errorf("failed to save setting %s: %w", setting.Key, err)
+			}
+		}
+		return nil
+	})
+}
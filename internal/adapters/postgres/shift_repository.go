@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// shiftRepository implements ShiftRepository methods
+type shiftRepository struct {
+	*Repository
+}
+
+// ShiftModel represents the shifts table structure
+type ShiftModel struct {
+	ID         string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	BarStaffID string         `gorm:"column:bar_staff_id;type:uuid;not null"`
+	StartedAt  time.Time      `gorm:"column:started_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	EndedAt    sql.NullTime   `gorm:"column:ended_at;type:timestamp"`
+	OpenedVia  string         `gorm:"column:opened_via;type:varchar(20);not null;default:'dashboard'"`
+	ClosedVia  sql.NullString `gorm:"column:closed_via;type:varchar(20)"`
+	StaffName  string         `gorm:"column:staff_name;->"` // Read-only, joined from bar_staff
+}
+
+func (ShiftModel) TableName() string {
+	return "shifts"
+}
+
+// ToDomain converts ShiftModel to core.Shift
+func (m *ShiftModel) ToDomain() *core.Shift {
+	shift := &core.Shift{
+		ID:        m.ID,
+		StaffID:   m.BarStaffID,
+		StaffName: m.StaffName,
+		StartedAt: m.StartedAt,
+		OpenedVia: m.OpenedVia,
+		ClosedVia: m.ClosedVia.String,
+	}
+	if m.EndedAt.Valid {
+		endedAt := m.EndedAt.Time
+		shift.EndedAt = &endedAt
+	}
+	return shift
+}
+
+// shiftSelect is the column list for shifts joined with the bartender's name
+const shiftSelect = "shifts.*, bar_staff.name AS staff_name"
+
+// Open clocks a bartender in; the partial unique index allows one open shift per bartender
+func (r *shiftRepository) Open(ctx context.Context, shift *core.Shift) error {
+	model := &ShiftModel{
+		ID:         shift.ID,
+		BarStaffID: shift.StaffID,
+		StartedAt:  shift.StartedAt,
+		OpenedVia:  shift.OpenedVia,
+	}
+	if err := r.db.WithContext(ctx).Table("shifts").Omit("staff_name").Create(model).Error; err != nil {
+		if strings.Contains(err.Error(), "idx_shifts_one_open") {
+			return fmt.Errorf("already clocked in")
+		}
+		return fmt.Errorf("failed to open shift: %w", err)
+	}
+	return nil
+}
+
+// Close clocks a bartender out of their open shift and returns it
+func (r *shiftRepository) Close(ctx context.Context, staffID string, endedAt time.Time, via string) (*core.Shift, error) {
+	var id string
+	result := r.db.WithContext(ctx).Raw(`UPDATE shifts SET ended_at = GREATEST(?, started_at), closed_via = ?
+		WHERE bar_staff_id = ? AND ended_at IS NULL
+		RETURNING id`, endedAt, via, staffID).Scan(&id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to close shift: %w", result.Error)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("not clocked in")
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByID retrieves a shift by ID
+func (r *shiftRepository) GetByID(ctx context.Context, id string) (*core.Shift, error) {
+	var model ShiftModel
+	if err := r.db.WithContext(ctx).Table("shifts").
+		Select(shiftSelect).
+		Joins("JOIN bar_staff ON bar_staff.id = shifts.bar_staff_id").
+		Where("shifts.id = ?", id).
+		Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("shift not found")
+		}
+		return nil, fmt.Errorf("failed to get shift: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// List retrieves shifts matching the filter, newest first
+func (r *shiftRepository) List(ctx context.Context, filter core.ShiftFilter) ([]*core.Shift, error) {
+	query := r.db.WithContext(ctx).Table("shifts").
+		Select(shiftSelect).
+		Joins("JOIN bar_staff ON bar_staff.id = shifts.bar_staff_id")
+
+	if filter.StaffID != "" {
+		query = query.Where("shifts.bar_staff_id = ?", filter.StaffID)
+	}
+	if filter.From != nil {
+		query = query.Where("shifts.started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("shifts.started_at < ?", *filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []ShiftModel
+	if err := query.Order("shifts.started_at DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get shifts: %w", err)
+	}
+
+	shifts := make([]*core.Shift, len(models))
+	for i := range models {
+		shifts[i] = models[i].ToDomain()
+	}
+	return shifts, nil
+}
+
+// GetSales totals settled orders first paid in [start, end) by payment method
+func (r *shiftRepository) GetSales(ctx context.Context, start time.Time, end time.Time, staffID string) (*core.ShiftSales, error) {
+	settledStatuses := []string{"PAID", "SCHEDULED", "READY", "COMPLETED", "OUT_FOR_DELIVERY", "DELIVERED"}
+
+	var sales core.ShiftSales
+	if err := r.db.WithContext(ctx).Table("orders").
+		Select(`COUNT(*) AS orders,
+			COALESCE(SUM(total_amount - tip_amount - delivery_fee), 0) AS revenue,
+			COALESCE(SUM(tip_amount), 0) AS tips,
+			COALESCE(SUM(total_amount) FILTER (WHERE payment_method = ?), 0) AS cash_collected,
+			COALESCE(SUM(total_amount) FILTER (WHERE payment_method = ?), 0) AS card_collected,
+			COALESCE(SUM(total_amount) FILTER (WHERE payment_method NOT IN ?), 0) AS mpesa_collected,
+			COUNT(*) FILTER (WHERE accepted_by_staff_id::text = ?) AS orders_accepted`,
+			string(core.PaymentMethodCash), string(core.PaymentMethodCard),
+			[]string{string(core.PaymentMethodCash), string(core.PaymentMethodCard)}, staffID).
+		Where("status IN ? AND "+paidAtSQL+" >= ? AND "+paidAtSQL+" < ?", settledStatuses, start, end).
+		Scan(&sales).Error; err != nil {
+		return nil, fmt.Errorf("failed to get shift sales: %w", err)
+	}
+	return &sales, nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// stkAttemptRepository implements STKAttemptRepository methods
+type stkAttemptRepository struct {
+	*Repository
+}
+
+// STKAttemptModel represents the stk_attempts table structure
+type STKAttemptModel struct {
+	ID               string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderID          string    `gorm:"column:order_id;type:uuid;not null;index"`
+	Phone            string    `gorm:"column:phone;type:varchar(20);not null;default:''"`
+	Amount           float64   `gorm:"column:amount;type:decimal(10,2);not null"`
+	Status           string    `gorm:"column:status;type:varchar(20);not null"`
+	PaymentRequestID string    `gorm:"column:payment_request_id;type:varchar(100);not null;default:''"`
+	Location         string    `gorm:"column:location;type:text;not null;default:''"`
+	HTTPStatus       int       `gorm:"column:http_status;type:integer;not null;default:0"`
+	Error            string    `gorm:"column:error;type:text;not null;default:''"`
+	CreatedAt        time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (STKAttemptModel) TableName() string {
+	return "stk_attempts"
+}
+
+// ToDomain converts STKAttemptModel to core.STKAttempt
+func (m *STKAttemptModel) ToDomain() *core.STKAttempt {
+	return &core.STKAttempt{
+		ID:               m.ID,
+		OrderID:          m.OrderID,
+		Phone:            m.Phone,
+		Amount:           m.Amount,
+		Status:           m.Status,
+		PaymentRequestID: m.PaymentRequestID,
+		Location:         m.Location,
+		HTTPStatus:       m.HTTPStatus,
+		Error:            m.Error,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+// Create records an STK push attempt
+func (r *stkAttemptRepository) Create(ctx context.Context, attempt *core.STKAttempt) error {
+	now := r.clock.Now()
+	if attempt.ID == "" {
+		attempt.ID = r.ids.NewID()
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = now
+	}
+	attempt.UpdatedAt = now
+
+	model := &STKAttemptModel{
+		ID:               attempt.ID,
+		OrderID:          attempt.OrderID,
+		Phone:            attempt.Phone,
+		Amount:           attempt.Amount,
+		Status:           attempt.Status,
+		PaymentRequestID: attempt.PaymentRequestID,
+		Location:         attempt.Location,
+		HTTPStatus:       attempt.HTTPStatus,
+		Error:            attempt.Error,
+		CreatedAt:        attempt.CreatedAt,
+		UpdatedAt:        attempt.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("stk_attempts").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to record STK attempt: %w", err)
+	}
+	return nil
+}
+
+// ListByOrder retrieves an order's STK push attempts, oldest first
+func (r *stkAttemptRepository) ListByOrder(ctx context.Context, orderID string) ([]*core.STKAttempt, error) {
+	var models []STKAttemptModel
+	if err := r.db.WithContext(ctx).Table("stk_attempts").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get STK attempts: %w", err)
+	}
+
+	attempts := make([]*core.STKAttempt, len(models))
+	for i := range models {
+		attempts[i] = models[i].ToDomain()
+	}
+	return attempts, nil
+}
+
+// FindByPaymentRequestID retrieves the attempt Kopo Kopo assigned the given incoming payment ID
+func (r *stkAttemptRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*core.STKAttempt, error) {
+	if paymentRequestID == "" {
+		return nil, fmt.Errorf("STK attempt not found")
+	}
+
+	var model STKAttemptModel
+	if err := r.db.WithContext(ctx).Table("stk_attempts").
+		Where("payment_request_id = ?", paymentRequestID).
+		Order("created_at DESC").
+		First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("STK attempt not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get STK attempt: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// UpdateResult records the callback outcome for an attempt
+func (r *stkAttemptRepository) UpdateResult(ctx context.Context, paymentRequestID string, status string, errMsg string) error {
+	result := r.db.WithContext(ctx).Table("stk_attempts").
+		Where("payment_request_id = ?", paymentRequestID).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"error":      errMsg,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update STK attempt: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("STK attempt not found")
+	}
+	return nil
+}
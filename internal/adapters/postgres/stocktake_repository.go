@@ -0,0 +1,339 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stocktakeRepository implements StocktakeRepository methods
+type stocktakeRepository struct {
+	*Repository
+}
+
+// StocktakeModel represents the stocktakes table structure
+type StocktakeModel struct {
+	ID        string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Status    string         `gorm:"column:status;type:varchar(20);not null;default:'OPEN'"`
+	Note      sql.NullString `gorm:"column:note;type:text"`
+	StartedBy string         `gorm:"column:started_by;type:varchar(64);not null"`
+	AppliedBy sql.NullString `gorm:"column:applied_by;type:varchar(64)"`
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	AppliedAt sql.NullTime   `gorm:"column:applied_at;type:timestamp"`
+}
+
+func (StocktakeModel) TableName() string {
+	return "stocktakes"
+}
+
+// ToDomain converts StocktakeModel to core.Stocktake
+func (m *StocktakeModel) ToDomain() *core.Stocktake {
+	stocktake := &core.Stocktake{
+		ID:        m.ID,
+		Status:    core.StocktakeStatus(m.Status),
+		Note:      m.Note.String,
+		StartedBy: m.StartedBy,
+		AppliedBy: m.AppliedBy.String,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.AppliedAt.Valid {
+		appliedAt := m.AppliedAt.Time
+		stocktake.AppliedAt = &appliedAt
+	}
+	return stocktake
+}
+
+// StocktakeLineModel represents the stocktake_lines table structure
+type StocktakeLineModel struct {
+	ID              string    `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	StocktakeID     string    `gorm:"column:stocktake_id;type:uuid;not null;index"`
+	ProductID       string    `gorm:"column:product_id;type:uuid;not null"`
+	SystemQuantity  int       `gorm:"column:system_quantity;type:integer;not null"`
+	CountedQuantity int       `gorm:"column:counted_quantity;type:integer;not null"`
+	Variance        int       `gorm:"column:variance;type:integer;not null"`
+	CountedBy       string    `gorm:"column:counted_by;type:varchar(64);not null"`
+	CountedAt       time.Time `gorm:"column:counted_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (StocktakeLineModel) TableName() string {
+	return "stocktake_lines"
+}
+
+// StockAdjustmentModel represents the stock_adjustments table structure
+type StockAdjustmentModel struct {
+	ID              string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductID       string         `gorm:"column:product_id;type:uuid;not null"`
+	StocktakeID     sql.NullString `gorm:"column:stocktake_id;type:uuid"`
+	PurchaseOrderID sql.NullString `gorm:"column:purchase_order_id;type:uuid"`
+	OrderID         sql.NullString `gorm:"column:order_id;type:uuid"`
+	OldQuantity     int            `gorm:"column:old_quantity;type:integer;not null"`
+	NewQuantity     int            `gorm:"column:new_quantity;type:integer;not null"`
+	Reason          string         `gorm:"column:reason;type:varchar(50);not null"`
+	Actor           string         `gorm:"column:actor;type:varchar(64);not null"`
+	CreatedAt       time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (StockAdjustmentModel) TableName() string {
+	return "stock_adjustments"
+}
+
+// stocktakeLineRow is a stocktake line joined with its product's name, category and price
+type stocktakeLineRow struct {
+	StocktakeLineModel
+	ProductName string  `gorm:"column:product_name"`
+	Category    string  `gorm:"column:category"`
+	UnitPrice   float64 `gorm:"column:unit_price"`
+}
+
+// ToDomain converts stocktakeLineRow to core.StocktakeLine
+func (r *stocktakeLineRow) ToDomain() core.StocktakeLine {
+	return core.StocktakeLine{
+		ProductID:       r.ProductID,
+		ProductName:     r.ProductName,
+		Category:        r.Category,
+		UnitPrice:       r.UnitPrice,
+		SystemQuantity:  r.SystemQuantity,
+		CountedQuantity: r.CountedQuantity,
+		Variance:        r.Variance,
+		CountedBy:       r.CountedBy,
+		CountedAt:       r.CountedAt,
+	}
+}
+
+// Create starts a stocktake; the partial unique index allows only one OPEN at a time
+func (r *stocktakeRepository) Create(ctx context.Context, stocktake *core.Stocktake) error {
+	model := &StocktakeModel{
+		ID:        stocktake.ID,
+		Status:    string(stocktake.Status),
+		Note:      sql.NullString{String: stocktake.Note, Valid: stocktake.Note != ""},
+		StartedBy: stocktake.StartedBy,
+		CreatedAt: stocktake.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("stocktakes").Create(model).Error; err != nil {
+		if strings.Contains(err.Error(), "idx_stocktakes_one_open") {
+			return fmt.Errorf("a stocktake is already open")
+		}
+		return fmt.Errorf("failed to create stocktake: %w", err)
+	}
+	return nil
+}
+
+// GetAll retrieves the most recent stocktakes without their lines
+func (r *stocktakeRepository) GetAll(ctx context.Context, limit int) ([]*core.Stocktake, error) {
+	var models []StocktakeModel
+	if err := r.db.WithContext(ctx).Table("stocktakes").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get stocktakes: %w", err)
+	}
+
+	stocktakes := make([]*core.Stocktake, len(models))
+	for i := range models {
+		stocktakes[i] = models[i].ToDomain()
+	}
+	return stocktakes, nil
+}
+
+// GetByID retrieves a stocktake with its lines, ordered by category and product name
+func (r *stocktakeRepository) GetByID(ctx context.Context, id string) (*core.Stocktake, error) {
+	var model StocktakeModel
+	if err := r.db.WithContext(ctx).Table("stocktakes").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("stocktake not found")
+		}
+		return nil, fmt.Errorf("failed to get stocktake: %w", err)
+	}
+
+	var rows []stocktakeLineRow
+	if err := r.db.WithContext(ctx).Table("stocktake_lines").
+		Select("stocktake_lines.*, products.name AS product_name, products.category, products.price AS unit_price").
+		Joins("JOIN products ON products.id = stocktake_lines.product_id").
+		Where("stocktake_lines.stocktake_id = ?", id).
+		Order("products.category ASC, products.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get stocktake lines: %w", err)
+	}
+
+	stocktake := model.ToDomain()
+	for i := range rows {
+		stocktake.Lines = append(stocktake.Lines, rows[i].ToDomain())
+	}
+	return stocktake, nil
+}
+
+// lockOpenStocktake locks a stocktake row and checks it's still being counted
+func lockOpenStocktake(tx *gorm.DB, id string) error {
+	var current StocktakeModel
+	if err := tx.Table("stocktakes").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		Where("id = ?", id).
+		First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("stocktake not found")
+		}
+		return fmt.Errorf("failed to get stocktake: %w", err)
+	}
+	if core.StocktakeStatus(current.Status) != core.StocktakeOpen {
+		return fmt.Errorf("stocktake is %s, only OPEN stocktakes can be changed", current.Status)
+	}
+	return nil
+}
+
+// RecordCount saves (or recounts) a product's quantity against its stock right now
+func (r *stocktakeRepository) RecordCount(ctx context.Context, stocktakeID string, productID string, counted int, actor string) (*core.StocktakeLine, error) {
+	var line *core.StocktakeLine
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenStocktake(tx, stocktakeID); err != nil {
+			return err
+		}
+
+		var product ProductModel
+		if err := tx.Table("products").
+			Select("id", "name", "category", "price", "stock_quantity").
+			Where("id = ?", productID).
+			First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("product not found")
+			}
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		now := r.clock.Now()
+		model := &StocktakeLineModel{
+			ID:              r.ids.NewID(),
+			StocktakeID:     stocktakeID,
+			ProductID:       productID,
+			SystemQuantity:  product.StockQuantity,
+			CountedQuantity: counted,
+			Variance:        counted - product.StockQuantity,
+			CountedBy:       actor,
+			CountedAt:       now,
+		}
+		if err := tx.Table("stocktake_lines").
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "stocktake_id"}, {Name: "product_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"system_quantity", "counted_quantity", "variance", "counted_by", "counted_at"}),
+			}).
+			Create(model).Error; err != nil {
+			return fmt.Errorf("failed to record count: %w", err)
+		}
+
+		line = &core.StocktakeLine{
+			ProductID:       product.ID,
+			ProductName:     product.Name,
+			Category:        product.Category,
+			UnitPrice:       product.Price,
+			SystemQuantity:  model.SystemQuantity,
+			CountedQuantity: model.CountedQuantity,
+			Variance:        model.Variance,
+			CountedBy:       actor,
+			CountedAt:       now,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// Apply adds each line's variance to current stock rather than overwriting it with the count,
+// so drinks sold between counting and applying aren't put back on the shelf
+func (r *stocktakeRepository) Apply(ctx context.Context, id string, actor string) ([]core.StockAdjustment, error) {
+	var adjustments []core.StockAdjustment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenStocktake(tx, id); err != nil {
+			return err
+		}
+
+		var lines []StocktakeLineModel
+		if err := tx.Table("stocktake_lines").
+			Where("stocktake_id = ? AND variance <> 0", id).
+			Order("product_id").
+			Find(&lines).Error; err != nil {
+			return fmt.Errorf("failed to get stocktake lines: %w", err)
+		}
+
+		now := r.clock.Now()
+		for _, line := range lines {
+			var product ProductModel
+			if err := tx.Table("products").
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "stock_quantity").
+				Where("id = ?", line.ProductID).
+				First(&product).Error; err != nil {
+				return fmt.Errorf("failed to get product stock: %w", err)
+			}
+
+			newQuantity := product.StockQuantity + line.Variance
+			if newQuantity < 0 {
+				newQuantity = 0
+			}
+			if newQuantity == product.StockQuantity {
+				continue
+			}
+
+			if err := tx.Table("products").Where("id = ?", product.ID).Updates(map[string]interface{}{
+				"stock_quantity": newQuantity,
+				"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to adjust stock: %w", err)
+			}
+
+			audit := &StockAdjustmentModel{
+				ID:          r.ids.NewID(),
+				ProductID:   product.ID,
+				StocktakeID: sql.NullString{String: id, Valid: true},
+				OldQuantity: product.StockQuantity,
+				NewQuantity: newQuantity,
+				Reason:      core.StockAdjustmentStocktake,
+				Actor:       actor,
+				CreatedAt:   now,
+			}
+			if err := tx.Table("stock_adjustments").Create(audit).Error; err != nil {
+				return fmt.Errorf("failed to record stock adjustment: %w", err)
+			}
+
+			adjustments = append(adjustments, core.StockAdjustment{
+				ProductID:   product.ID,
+				OldQuantity: product.StockQuantity,
+				NewQuantity: newQuantity,
+			})
+		}
+
+		if err := tx.Table("stocktakes").Where("id = ?", id).Updates(map[string]interface{}{
+			"status":     string(core.StocktakeApplied),
+			"applied_by": actor,
+			"applied_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to apply stocktake: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// Cancel abandons an OPEN stocktake without touching stock
+func (r *stocktakeRepository) Cancel(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenStocktake(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Table("stocktakes").Where("id = ?", id).Update("status", string(core.StocktakeCancelled)).Error; err != nil {
+			return fmt.Errorf("failed to cancel stocktake: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// supplierRepository implements SupplierRepository methods
+type supplierRepository struct {
+	*Repository
+}
+
+// SupplierModel represents the suppliers table structure
+type SupplierModel struct {
+	ID          string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name        string         `gorm:"column:name;type:varchar(255);not null"`
+	PhoneNumber sql.NullString `gorm:"column:phone_number;type:varchar(20)"`
+	Email       sql.NullString `gorm:"column:email;type:varchar(255)"`
+	Notes       sql.NullString `gorm:"column:notes;type:text"`
+	IsActive    bool           `gorm:"column:is_active;type:boolean;not null;default:true"`
+	CreatedAt   time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (SupplierModel) TableName() string {
+	return "suppliers"
+}
+
+// ToDomain converts SupplierModel to core.Supplier
+func (m *SupplierModel) ToDomain() *core.Supplier {
+	return &core.Supplier{
+		ID:          m.ID,
+		Name:        m.Name,
+		PhoneNumber: m.PhoneNumber.String,
+		Email:       m.Email.String,
+		Notes:       m.Notes.String,
+		IsActive:    m.IsActive,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// GetAll retrieves every supplier, active ones first
+func (r *supplierRepository) GetAll(ctx context.Context) ([]*core.Supplier, error) {
+	var models []SupplierModel
+	if err := r.db.WithContext(ctx).Table("suppliers").
+		Order("is_active DESC, name ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get suppliers: %w", err)
+	}
+
+	suppliers := make([]*core.Supplier, len(models))
+	for i := range models {
+		suppliers[i] = models[i].ToDomain()
+	}
+	return suppliers, nil
+}
+
+// GetByID retrieves a supplier by ID
+func (r *supplierRepository) GetByID(ctx context.Context, id string) (*core.Supplier, error) {
+	var model SupplierModel
+	if err := r.db.WithContext(ctx).Table("suppliers").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("supplier not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// Create adds a supplier
+func (r *supplierRepository) Create(ctx context.Context, supplier *core.Supplier) error {
+	model := &SupplierModel{
+		ID:          supplier.ID,
+		Name:        supplier.Name,
+		PhoneNumber: sql.NullString{String: supplier.PhoneNumber, Valid: supplier.PhoneNumber != ""},
+		Email:       sql.NullString{String: supplier.Email, Valid: supplier.Email != ""},
+		Notes:       sql.NullString{String: supplier.Notes, Valid: supplier.Notes != ""},
+		IsActive:    supplier.IsActive,
+		CreatedAt:   supplier.CreatedAt,
+		UpdatedAt:   supplier.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("suppliers").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create supplier: %w", err)
+	}
+	return nil
+}
+
+// Update saves a supplier's contact details and active flag
+func (r *supplierRepository) Update(ctx context.Context, supplier *core.Supplier) error {
+	result := r.db.WithContext(ctx).Table("suppliers").
+		Where("id = ?", supplier.ID).
+		Updates(map[string]interface{}{
+			"name":         supplier.Name,
+			"phone_number": sql.NullString{String: supplier.PhoneNumber, Valid: supplier.PhoneNumber != ""},
+			"email":        sql.NullString{String: supplier.Email, Valid: supplier.Email != ""},
+			"notes":        sql.NullString{String: supplier.Notes, Valid: supplier.Notes != ""},
+			"is_active":    supplier.IsActive,
+			"updated_at":   gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update supplier: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("supplier not found")
+	}
+	return nil
+}
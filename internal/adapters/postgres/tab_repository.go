@@ -0,0 +1,339 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// tabRepository implements TabRepository methods
+type tabRepository struct {
+	*Repository
+}
+
+// unpaidTabItemCondition matches tab items on no order, or on an order that is missing, FAILED or CANCELLED
+const unpaidTabItemCondition = `(tab_items.order_id IS NULL OR NOT EXISTS (
+	SELECT 1 FROM orders WHERE orders.id = tab_items.order_id AND orders.status NOT IN ('FAILED', 'CANCELLED')))`
+
+// TabModel represents the tabs table structure
+type TabModel struct {
+	ID          string       `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TableNumber string       `gorm:"column:table_number;type:varchar(20);not null"`
+	JoinCode    string       `gorm:"column:join_code;type:varchar(8);not null"`
+	Status      string       `gorm:"column:status;type:varchar(20);not null;default:'OPEN'"`
+	OpenedBy    string       `gorm:"column:opened_by;type:varchar(20);not null"`
+	CreatedAt   time.Time    `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ClosedAt    sql.NullTime `gorm:"column:closed_at;type:timestamp"`
+}
+
+func (TabModel) TableName() string {
+	return "tabs"
+}
+
+// ToDomain converts TabModel to core.Tab; members and items are loaded separately
+func (m *TabModel) ToDomain() *core.Tab {
+	var closedAt *time.Time
+	if m.ClosedAt.Valid {
+		t := m.ClosedAt.Time
+		closedAt = &t
+	}
+
+	return &core.Tab{
+		ID:          m.ID,
+		TableNumber: m.TableNumber,
+		JoinCode:    m.JoinCode,
+		Status:      core.TabStatus(m.Status),
+		OpenedBy:    m.OpenedBy,
+		Members:     []core.TabMember{},
+		Items:       []core.TabItem{},
+		CreatedAt:   m.CreatedAt,
+		ClosedAt:    closedAt,
+	}
+}
+
+// TabMemberModel represents the tab_members table structure
+type TabMemberModel struct {
+	TabID    string    `gorm:"column:tab_id;type:uuid;primaryKey"`
+	Phone    string    `gorm:"column:phone;type:varchar(20);primaryKey"`
+	JoinedAt time.Time `gorm:"column:joined_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TabMemberModel) TableName() string {
+	return "tab_members"
+}
+
+// TabItemModel represents the tab_items table structure
+type TabItemModel struct {
+	ID        string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TabID     string         `gorm:"column:tab_id;type:uuid;not null;index"`
+	Phone     string         `gorm:"column:phone;type:varchar(20);not null"`
+	ProductID string         `gorm:"column:product_id;type:uuid;not null"`
+	Name      string         `gorm:"column:name;type:varchar(255);not null"`
+	Quantity  int            `gorm:"column:quantity;type:integer;not null"`
+	Price     float64        `gorm:"column:price;type:decimal(10,2);not null"`
+	Modifiers sql.NullString `gorm:"column:modifiers;type:jsonb"`
+	OrderID   sql.NullString `gorm:"column:order_id;type:uuid;index"`
+	CreatedAt time.Time      `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TabItemModel) TableName() string {
+	return "tab_items"
+}
+
+// ToDomain converts TabItemModel to core.TabItem; the order status comes from a JOIN
+func (m *TabItemModel) ToDomain() core.TabItem {
+	var modifiers []core.OrderModifier
+	if m.Modifiers.Valid && m.Modifiers.String != "" {
+		if err := json.Unmarshal([]byte(m.Modifiers.String), &modifiers); err != nil {
+			modifiers = nil
+		}
+	}
+
+	return core.TabItem{
+		ID:        m.ID,
+		TabID:     m.TabID,
+		Phone:     m.Phone,
+		ProductID: m.ProductID,
+		Name:      m.Name,
+		Quantity:  m.Quantity,
+		Price:     m.Price,
+		Modifiers: modifiers,
+		OrderID:   m.OrderID.String,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// Create opens a tab with its opener as the first member
+func (r *tabRepository) Create(ctx context.Context, tab *core.Tab) error {
+	now := r.clock.Now()
+	if tab.ID == "" {
+		tab.ID = r.ids.NewID()
+	}
+	if tab.Status == "" {
+		tab.Status = core.TabStatusOpen
+	}
+	tab.CreatedAt = now
+	tab.Members = []core.TabMember{{Phone: tab.OpenedBy, JoinedAt: now}}
+	tab.Items = []core.TabItem{}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model := &TabModel{
+			ID:          tab.ID,
+			TableNumber: tab.TableNumber,
+			JoinCode:    tab.JoinCode,
+			Status:      string(tab.Status),
+			OpenedBy:    tab.OpenedBy,
+			CreatedAt:   now,
+		}
+		if err := tx.Table("tabs").Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create tab: %w", err)
+		}
+		member := &TabMemberModel{TabID: tab.ID, Phone: tab.OpenedBy, JoinedAt: now}
+		if err := tx.Table("tab_members").Create(member).Error; err != nil {
+			return fmt.Errorf("failed to add tab member: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a tab with its members and items
+func (r *tabRepository) GetByID(ctx context.Context, id string) (*core.Tab, error) {
+	var model TabModel
+	if err := r.db.WithContext(ctx).Table("tabs").Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("tab not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get tab: %w", err)
+	}
+	return r.withDetails(ctx, &model)
+}
+
+// GetOpenByJoinCode retrieves the open tab using a join code, or nil when there is none
+func (r *tabRepository) GetOpenByJoinCode(ctx context.Context, code string) (*core.Tab, error) {
+	var model TabModel
+	if err := r.db.WithContext(ctx).Table("tabs").
+		Where("join_code = ? AND status = ?", code, string(core.TabStatusOpen)).
+		First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tab by join code: %w", err)
+	}
+	return r.withDetails(ctx, &model)
+}
+
+// GetOpenByMember retrieves the open tab a phone is on, or nil when there is none
+func (r *tabRepository) GetOpenByMember(ctx context.Context, phone string) (*core.Tab, error) {
+	var model TabModel
+	if err := r.db.WithContext(ctx).Table("tabs").
+		Joins("JOIN tab_members ON tab_members.tab_id = tabs.id").
+		Where("tab_members.phone = ? AND tabs.status = ?", phone, string(core.TabStatusOpen)).
+		Order("tabs.created_at DESC").
+		Select("tabs.*").
+		First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tab by member: %w", err)
+	}
+	return r.withDetails(ctx, &model)
+}
+
+// ListOpen retrieves every open tab with members and items, by table number
+func (r *tabRepository) ListOpen(ctx context.Context) ([]*core.Tab, error) {
+	var models []TabModel
+	if err := r.db.WithContext(ctx).Table("tabs").
+		Where("status = ?", string(core.TabStatusOpen)).
+		Order("table_number ASC, created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list open tabs: %w", err)
+	}
+
+	tabs := make([]*core.Tab, len(models))
+	for i := range models {
+		tab, err := r.withDetails(ctx, &models[i])
+		if err != nil {
+			return nil, err
+		}
+		tabs[i] = tab
+	}
+	return tabs, nil
+}
+
+// AddMember puts a phone on a tab; joining twice is a no-op
+func (r *tabRepository) AddMember(ctx context.Context, tabID string, phone string) error {
+	member := &TabMemberModel{TabID: tabID, Phone: phone, JoinedAt: r.clock.Now()}
+	if err := r.db.WithContext(ctx).Table("tab_members").
+		Where(TabMemberModel{TabID: tabID, Phone: phone}).
+		FirstOrCreate(member).Error; err != nil {
+		return fmt.Errorf("failed to add tab member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember takes a phone off a tab and returns how many members are left
+func (r *tabRepository) RemoveMember(ctx context.Context, tabID string, phone string) (int, error) {
+	if err := r.db.WithContext(ctx).Table("tab_members").
+		Where("tab_id = ? AND phone = ?", tabID, phone).
+		Delete(&TabMemberModel{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to remove tab member: %w", err)
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Table("tab_members").Where("tab_id = ?", tabID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count tab members: %w", err)
+	}
+	return int(count), nil
+}
+
+// AddItems puts cart items on a tab under the member who added them
+func (r *tabRepository) AddItems(ctx context.Context, tabID string, phone string, items []core.CartItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	now := r.clock.Now()
+	models := make([]TabItemModel, len(items))
+	for i, item := range items {
+		modifiers := sql.NullString{}
+		if len(item.Modifiers) > 0 {
+			if data, err := json.Marshal(item.Modifiers); err == nil {
+				modifiers = sql.NullString{String: string(data), Valid: true}
+			}
+		}
+		models[i] = TabItemModel{
+			ID:        r.ids.NewID(),
+			TabID:     tabID,
+			Phone:     phone,
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			Modifiers: modifiers,
+			CreatedAt: now,
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Table("tab_items").Create(&models).Error; err != nil {
+		return fmt.Errorf("failed to add tab items: %w", err)
+	}
+	return nil
+}
+
+// ClaimItems puts the unpaid items among itemIDs on orderID. Items another member already
+// checked out are skipped, so the caller compares the count with len(itemIDs).
+func (r *tabRepository) ClaimItems(ctx context.Context, tabID string, itemIDs []string, orderID string) (int, error) {
+	if len(itemIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Table("tab_items").
+		Where("tab_id = ? AND id IN ?", tabID, itemIDs).
+		Where(unpaidTabItemCondition).
+		Update("order_id", orderID)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to claim tab items: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// Close stops a tab taking drinks or members
+func (r *tabRepository) Close(ctx context.Context, tabID string) error {
+	result := r.db.WithContext(ctx).Table("tabs").
+		Where("id = ? AND status = ?", tabID, string(core.TabStatusOpen)).
+		Updates(map[string]interface{}{
+			"status":    string(core.TabStatusClosed),
+			"closed_at": r.clock.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to close tab: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("open tab not found")
+	}
+	return nil
+}
+
+// withDetails loads a tab's members and items, with each item's order status
+func (r *tabRepository) withDetails(ctx context.Context, model *TabModel) (*core.Tab, error) {
+	tab := model.ToDomain()
+
+	var members []TabMemberModel
+	if err := r.db.WithContext(ctx).Table("tab_members").
+		Where("tab_id = ?", tab.ID).
+		Order("joined_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tab members: %w", err)
+	}
+	for _, member := range members {
+		tab.Members = append(tab.Members, core.TabMember{Phone: member.Phone, JoinedAt: member.JoinedAt})
+	}
+
+	type TabItemWithOrderStatus struct {
+		TabItemModel
+		OrderStatus string `gorm:"column:order_status"`
+	}
+
+	var items []TabItemWithOrderStatus
+	if err := r.db.WithContext(ctx).Table("tab_items").
+		Select("tab_items.*, COALESCE(orders.status, '') as order_status").
+		Joins("LEFT JOIN orders ON orders.id = tab_items.order_id").
+		Where("tab_items.tab_id = ?", tab.ID).
+		Order("tab_items.created_at ASC").
+		Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tab items: %w", err)
+	}
+	for _, row := range items {
+		item := row.TabItemModel.ToDomain()
+		item.OrderStatus = core.OrderStatus(row.OrderStatus)
+		tab.Items = append(tab.Items, item)
+	}
+
+	return tab, nil
+}
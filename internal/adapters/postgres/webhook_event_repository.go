@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+)
+
+// webhookEventRepository implements WebhookEventRepository methods
+type webhookEventRepository struct {
+	*Repository
+}
+
+// WebhookEventModel represents the webhook_events table structure
+type WebhookEventModel struct {
+	ID             string         `gorm:"column:id;type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Source         string         `gorm:"column:source;type:varchar(20);not null"`
+	Headers        string         `gorm:"column:headers;type:jsonb;not null"`
+	Body           string         `gorm:"column:body;type:text;not null"`
+	SignatureValid sql.NullBool   `gorm:"column:signature_valid;type:boolean"`
+	StatusCode     int            `gorm:"column:status_code;type:integer;not null"`
+	Response       sql.NullString `gorm:"column:response;type:text"`
+	IP             sql.NullString `gorm:"column:ip;type:varchar(64)"`
+	RequestID      sql.NullString `gorm:"column:request_id;type:varchar(128)"`
+	ReplayCount    int            `gorm:"column:replay_count;type:integer;not null;default:0"`
+	LastReplayedAt sql.NullTime   `gorm:"column:last_replayed_at;type:timestamp"`
+	ReceivedAt     time.Time      `gorm:"column:received_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (WebhookEventModel) TableName() string {
+	return "webhook_events"
+}
+
+// ToDomain converts WebhookEventModel to core.WebhookEvent
+func (m *WebhookEventModel) ToDomain() *core.WebhookEvent {
+	event := &core.WebhookEvent{
+		ID:          m.ID,
+		Source:      m.Source,
+		Headers:     map[string]string{},
+		Body:        m.Body,
+		StatusCode:  m.StatusCode,
+		Response:    m.Response.String,
+		IP:          m.IP.String,
+		RequestID:   m.RequestID.String,
+		ReplayCount: m.ReplayCount,
+		ReceivedAt:  m.ReceivedAt,
+	}
+	if m.Headers != "" {
+		_ = json.Unmarshal([]byte(m.Headers), &event.Headers)
+	}
+	if m.SignatureValid.Valid {
+		valid := m.SignatureValid.Bool
+		event.SignatureValid = &valid
+	}
+	if m.LastReplayedAt.Valid {
+		replayedAt := m.LastReplayedAt.Time
+		event.LastReplayedAt = &replayedAt
+	}
+	return event
+}
+
+// Record stores one inbound webhook request
+func (r *webhookEventRepository) Record(ctx context.Context, event *core.WebhookEvent) error {
+	if event.ID == "" {
+		event.ID = r.ids.NewID()
+	}
+	if event.ReceivedAt.IsZero() {
+		event.ReceivedAt = r.clock.Now()
+	}
+
+	headers, err := json.Marshal(event.Headers)
+	if err != nil || event.Headers == nil {
+		headers = []byte("{}")
+	}
+
+	model := &WebhookEventModel{
+		ID:         event.ID,
+		Source:     event.Source,
+		Headers:    string(headers),
+		Body:       event.Body,
+		StatusCode: event.StatusCode,
+		Response:   sql.NullString{String: event.Response, Valid: event.Response != ""},
+		IP:         sql.NullString{String: event.IP, Valid: event.IP != ""},
+		RequestID:  sql.NullString{String: event.RequestID, Valid: event.RequestID != ""},
+		ReceivedAt: event.ReceivedAt,
+	}
+	if event.SignatureValid != nil {
+		model.SignatureValid = sql.NullBool{Bool: *event.SignatureValid, Valid: true}
+	}
+	if err := r.db.WithContext(ctx).Table("webhook_events").Create(model).Error; err != nil {
+		return fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a webhook event by ID
+func (r *webhookEventRepository) GetByID(ctx context.Context, id string) (*core.WebhookEvent, error) {
+	var model WebhookEventModel
+	if err := r.db.WithContext(ctx).Table("webhook_events").Where("id = ?", id).Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("webhook event not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// List retrieves webhook events matching the filter, newest first
+func (r *webhookEventRepository) List(ctx context.Context, filter core.WebhookEventFilter) ([]*core.WebhookEvent, error) {
+	query := r.db.WithContext(ctx).Table("webhook_events")
+
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.SignatureValid != nil {
+		query = query.Where("signature_valid = ?", *filter.SignatureValid)
+	}
+	if filter.StatusCode > 0 {
+		query = query.Where("status_code = ?", filter.StatusCode)
+	}
+	if filter.Search != "" {
+		query = query.Where("body ILIKE ?", "%"+filter.Search+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("received_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("received_at < ?", *filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var models []WebhookEventModel
+	if err := query.Order("received_at DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook events: %w", err)
+	}
+
+	events := make([]*core.WebhookEvent, len(models))
+	for i := range models {
+		events[i] = models[i].ToDomain()
+	}
+	return events, nil
+}
+
+// MarkReplayed counts a replay of a webhook event
+func (r *webhookEventRepository) MarkReplayed(ctx context.Context, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Table("webhook_events").Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"replay_count":     gorm.Expr("replay_count + 1"),
+			"last_replayed_at": at,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark webhook event replayed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("webhook event not found")
+	}
+	return nil
+}
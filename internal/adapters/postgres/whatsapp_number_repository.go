@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// whatsAppNumberRepository implements WhatsAppNumberRepository methods
+type whatsAppNumberRepository struct {
+	*Repository
+}
+
+// WhatsAppNumberModel represents the whatsapp_numbers table structure
+type WhatsAppNumberModel struct {
+	PhoneNumberID      string    `gorm:"column:phone_number_id;type:varchar(50);primaryKey"`
+	DisplayPhoneNumber string    `gorm:"column:display_phone_number;type:varchar(30);not null;default:''"`
+	VenueName          string    `gorm:"column:venue_name;type:varchar(100);not null"`
+	AccessToken        string    `gorm:"column:access_token;type:text;not null"`
+	Categories         string    `gorm:"column:categories;type:jsonb;not null"`
+	Active             bool      `gorm:"column:active;not null;default:true"`
+	UpdatedBy          string    `gorm:"column:updated_by;type:varchar(100);not null;default:''"`
+	CreatedAt          time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (WhatsAppNumberModel) TableName() string {
+	return "whatsapp_numbers"
+}
+
+// ToDomain converts WhatsAppNumberModel to core.WhatsAppNumber
+func (m *WhatsAppNumberModel) ToDomain() *core.WhatsAppNumber {
+	categories := []string{}
+	if err := json.Unmarshal([]byte(m.Categories), &categories); err != nil {
+		categories = []string{}
+	}
+
+	return &core.WhatsAppNumber{
+		PhoneNumberID:      m.PhoneNumberID,
+		DisplayPhoneNumber: m.DisplayPhoneNumber,
+		VenueName:          m.VenueName,
+		AccessToken:        m.AccessToken,
+		Categories:         categories,
+		Active:             m.Active,
+		UpdatedBy:          m.UpdatedBy,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+// List retrieves every registered number, ordered by venue
+func (r *whatsAppNumberRepository) List(ctx context.Context) ([]*core.WhatsAppNumber, error) {
+	var models []WhatsAppNumberModel
+	if err := r.db.WithContext(ctx).Table("whatsapp_numbers").Order("venue_name ASC, phone_number_id ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list whatsapp numbers: %w", err)
+	}
+
+	numbers := make([]*core.WhatsAppNumber, len(models))
+	for i := range models {
+		numbers[i] = models[i].ToDomain()
+	}
+	return numbers, nil
+}
+
+// Upsert inserts a number or replaces everything but its created_at
+func (r *whatsAppNumberRepository) Upsert(ctx context.Context, number *core.WhatsAppNumber) error {
+	if number.Categories == nil {
+		number.Categories = []string{}
+	}
+	categories, err := json.Marshal(number.Categories)
+	if err != nil {
+		return fmt.Errorf("failed to marshal whatsapp number categories: %w", err)
+	}
+	now := r.clock.Now()
+	if number.CreatedAt.IsZero() {
+		number.CreatedAt = now
+	}
+	number.UpdatedAt = now
+
+	model := &WhatsAppNumberModel{
+		PhoneNumberID:      number.PhoneNumberID,
+		DisplayPhoneNumber: number.DisplayPhoneNumber,
+		VenueName:          number.VenueName,
+		AccessToken:        number.AccessToken,
+		Categories:         string(categories),
+		Active:             number.Active,
+		UpdatedBy:          number.UpdatedBy,
+		CreatedAt:          number.CreatedAt,
+		UpdatedAt:          number.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Table("whatsapp_numbers").
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "phone_number_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"display_phone_number", "venue_name", "access_token", "categories", "active", "updated_by", "updated_at",
+			}),
+		}).
+		Create(model).Error; err != nil {
+		return fmt.Errorf("failed to save whatsapp number: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a number and the customers routed to it, who go back to the default number
+func (r *whatsAppNumberRepository) Delete(ctx context.Context, phoneNumberID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`DELETE FROM whatsapp_numbers WHERE phone_number_id = ?`, phoneNumberID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete whatsapp number: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("whatsapp number not found")
+		}
+		if err := tx.Exec(`DELETE FROM whatsapp_contacts WHERE phone_number_id = ?`, phoneNumberID).Error; err != nil {
+			return fmt.Errorf("failed to delete whatsapp contacts: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetContactNumber records the number phone last messaged; unchanged rows aren't rewritten
+func (r *whatsAppNumberRepository) SetContactNumber(ctx context.Context, phone string, phoneNumberID string) error {
+	if err := r.db.WithContext(ctx).Exec(`INSERT INTO whatsapp_contacts (phone, phone_number_id, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (phone) DO UPDATE SET phone_number_id = EXCLUDED.phone_number_id, updated_at = EXCLUDED.updated_at
+		WHERE whatsapp_contacts.phone_number_id <> EXCLUDED.phone_number_id`, phone, phoneNumberID, r.clock.Now()).Error; err != nil {
+		return fmt.Errorf("failed to save whatsapp contact: %w", err)
+	}
+	return nil
+}
+
+// GetContactNumber returns the number phone last messaged, or "" when there is none on record
+func (r *whatsAppNumberRepository) GetContactNumber(ctx context.Context, phone string) (string, error) {
+	var phoneNumberIDs []string
+	if err := r.db.WithContext(ctx).Table("whatsapp_contacts").Where("phone = ?", phone).Limit(1).
+		Pluck("phone_number_id", &phoneNumberIDs).Error; err != nil {
+		return "", fmt.Errorf("failed to get whatsapp contact: %w", err)
+	}
+	if len(phoneNumberIDs) == 0 {
+		return "", nil
+	}
+	return phoneNumberIDs[0], nil
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// cartActivityKey is a sorted set of phones with an open cart, scored by last cart change (unix ms)
+	cartActivityKey = "carts:activity"
+	// cartReminderKeyPrefix marks a customer as recently reminded; the key's TTL is the frequency cap
+	cartReminderKeyPrefix = "cart_reminder:"
+)
+
+// trackCart keeps the idle cart index in step with a saved session: open carts are
+// (re)stamped with the current time, empty or checked-out carts are dropped
+func (r *Repository) trackCart(ctx context.Context, phone string, session *core.Session) error {
+	if len(session.Cart) == 0 || session.PendingOrderID != "" {
+		return r.client.ZRem(ctx, cartActivityKey, phone).Err()
+	}
+	return r.client.ZAdd(ctx, cartActivityKey, redis.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: phone,
+	}).Err()
+}
+
+// ClaimIdleCarts removes and returns up to limit phones whose cart hasn't changed since idleSince.
+// ZREM decides ownership, so two replicas never remind the same customer for the same cart.
+func (r *Repository) ClaimIdleCarts(ctx context.Context, idleSince time.Time, limit int) ([]string, error) {
+	phones, err := r.client.ZRangeByScore(ctx, cartActivityKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(idleSince.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idle carts: %w", err)
+	}
+
+	claimed := make([]string, 0, len(phones))
+	for _, phone := range phones {
+		removed, err := r.client.ZRem(ctx, cartActivityKey, phone).Result()
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim idle cart: %w", err)
+		}
+		if removed == 0 {
+			continue // another replica got it first
+		}
+		claimed = append(claimed, phone)
+	}
+
+	return claimed, nil
+}
+
+// ReserveReminder records a reminder for phone unless one was already sent within window
+func (r *Repository) ReserveReminder(ctx context.Context, phone string, window time.Duration) (bool, error) {
+	reserved, err := r.client.SetNX(ctx, cartReminderKeyPrefix+phone, time.Now().Unix(), window).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve cart reminder: %w", err)
+	}
+	return reserved, nil
+}
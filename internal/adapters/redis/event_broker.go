@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultEventChannel is the pub/sub channel used to fan dashboard events out across instances
+const DefaultEventChannel = "dashboard:events"
+
+// EventBroker implements events.Broker using Redis pub/sub
+type EventBroker struct {
+	client  *redis.Client
+	channel string
+}
+
+// nextEventIDScript advances the shared event counter like events.EventID: to the current time in
+// microseconds (ARGV[1]), or by one when events come faster than that. It survives a Redis flush or a
+// move to a new instance without going backwards.
+var nextEventIDScript = redis.NewScript(`
+local id = redis.call('INCR', KEYS[1])
+local now = tonumber(ARGV[1])
+if id < now then
+	redis.call('SET', KEYS[1], ARGV[1])
+	id = now
+end
+return id
+`)
+
+// wireEvent is the JSON envelope published on the Redis channel.
+// Data stays raw so SSE formatting re-emits it unchanged.
+type wireEvent struct {
+	ID            uint64           `json:"id"`
+	Type          events.EventType `json:"type"`
+	Data          json.RawMessage  `json:"data"`
+	RequestID     string           `json:"request_id,omitempty"`
+	SchemaVersion int              `json:"schema_version,omitempty"`
+}
+
+// NewEventBroker creates a new Redis-backed event broker
+func NewEventBroker(client *redis.Client, channel string) *EventBroker {
+	if channel == "" {
+		channel = DefaultEventChannel
+	}
+	return &EventBroker{client: client, channel: channel}
+}
+
+// Publish serializes an event and publishes it to every subscribed instance. The event ID comes from
+// a counter next to the channel, so every instance numbers events the same way.
+func (b *EventBroker) Publish(ctx context.Context, event events.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	id, err := nextEventIDScript.Run(ctx, b.client, []string{b.channel + ":seq"}, time.Now().UnixMicro()).Uint64()
+	if err != nil {
+		return fmt.Errorf("failed to assign event id: %w", err)
+	}
+
+	payload, err := json.Marshal(wireEvent{
+		ID:            id,
+		Type:          event.Type,
+		Data:          data,
+		RequestID:     event.RequestID,
+		SchemaVersion: event.SchemaVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe listens on the channel and delivers events until ctx is cancelled.
+// go-redis reconnects the underlying subscription automatically.
+func (b *EventBroker) Subscribe(ctx context.Context, deliver func(events.Event)) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+
+	// Wait for subscription confirmation so early publishes aren't missed.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", b.channel, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("event channel %s closed", b.channel)
+			}
+
+			var wire wireEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &wire); err != nil {
+				log.Printf("Dropping malformed event from %s: %v", b.channel, err)
+				continue
+			}
+
+			deliver(events.Event{
+				ID:            wire.ID,
+				Type:          wire.Type,
+				Data:          wire.Data,
+				RequestID:     wire.RequestID,
+				SchemaVersion: wire.SchemaVersion,
+			})
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/redis/go-redis/v9"
+)
+
+// idempotencyKeyPrefix holds one admin request's reservation or stored response; the key's TTL is its lifetime
+const idempotencyKeyPrefix = "admin:idempotency:"
+
+// IdempotencyStore implements core.IdempotencyStore using Redis
+type IdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewIdempotencyStore creates a new Redis-backed idempotency store
+func NewIdempotencyStore(client *redis.Client) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+// Reserve claims the key for a request in flight. SETNX decides ownership, so two concurrent
+// requests with the same key can't both run.
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, requestHash string, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(&core.IdempotentResponse{
+		RequestHash: requestHash,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	reserved, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return reserved, nil
+}
+
+// Get returns the reservation or stored response for a key
+func (s *IdempotencyStore) Get(ctx context.Context, key string) (*core.IdempotentResponse, error) {
+	data, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("idempotency key not found")
+		}
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	var response core.IdempotentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	return &response, nil
+}
+
+// Complete stores the response to replay for the key until ttl passes
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response *core.IdempotentResponse, ttl time.Duration) error {
+	response.Completed = true
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	if err := s.client.Set(ctx, idempotencyKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release deletes the key so a request that failed can be retried with it
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, idempotencyKeyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// outboundRetryKey is a sorted set of pending messages scored by next attempt (unix ms)
+	outboundRetryKey = "whatsapp:outbound:retry"
+	// outboundDeadLetterKey is a list of messages that exhausted their retries, newest first
+	outboundDeadLetterKey = "whatsapp:outbound:dead"
+	// outboundDeadLetterCap bounds the dead-letter list so it can't grow forever
+	outboundDeadLetterCap = 500
+)
+
+// OutboundMessageStore implements core.OutboundMessageStore using Redis so queued
+// WhatsApp retries survive restarts and are shared across replicas
+type OutboundMessageStore struct {
+	client *redis.Client
+}
+
+// NewOutboundMessageStore creates a new Redis-backed outbound message store
+func NewOutboundMessageStore(client *redis.Client) *OutboundMessageStore {
+	return &OutboundMessageStore{client: client}
+}
+
+// ScheduleRetry queues a message for delivery at msg.NextAttemptAt
+func (s *OutboundMessageStore) ScheduleRetry(ctx context.Context, msg *core.OutboundMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbound message: %w", err)
+	}
+
+	if err := s.client.ZAdd(ctx, outboundRetryKey, redis.Z{
+		Score:  float64(msg.NextAttemptAt.UnixMilli()),
+		Member: data,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to schedule outbound message: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue removes and returns up to limit messages whose next attempt is due.
+// ZREM decides ownership, so two workers never claim the same message.
+func (s *OutboundMessageStore) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*core.OutboundMessage, error) {
+	members, err := s.client.ZRangeByScore(ctx, outboundRetryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read due outbound messages: %w", err)
+	}
+
+	claimed := make([]*core.OutboundMessage, 0, len(members))
+	for _, member := range members {
+		removed, err := s.client.ZRem(ctx, outboundRetryKey, member).Result()
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim outbound message: %w", err)
+		}
+		if removed == 0 {
+			continue // another worker got it first
+		}
+
+		var msg core.OutboundMessage
+		if err := json.Unmarshal([]byte(member), &msg); err != nil {
+			continue
+		}
+		claimed = append(claimed, &msg)
+	}
+
+	return claimed, nil
+}
+
+// DeadLetter parks a message that exhausted its retries
+func (s *OutboundMessageStore) DeadLetter(ctx context.Context, msg *core.OutboundMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbound message: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.LPush(ctx, outboundDeadLetterKey, data)
+	pipe.LTrim(ctx, outboundDeadLetterKey, 0, outboundDeadLetterCap-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to dead-letter outbound message: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns the most recent dead-lettered messages
+func (s *OutboundMessageStore) ListDeadLetters(ctx context.Context, limit int) ([]*core.OutboundMessage, error) {
+	if limit <= 0 || limit > outboundDeadLetterCap {
+		limit = outboundDeadLetterCap
+	}
+
+	members, err := s.client.LRange(ctx, outboundDeadLetterKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered messages: %w", err)
+	}
+
+	messages := make([]*core.OutboundMessage, 0, len(members))
+	for _, member := range members {
+		var msg core.OutboundMessage
+		if err := json.Unmarshal([]byte(member), &msg); err != nil {
+			continue
+		}
+		messages = append(messages, &msg)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,32 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// processedMessageKeyPrefix marks an inbound WhatsApp message ID as handled; the key's TTL is the dedup window
+const processedMessageKeyPrefix = "whatsapp:processed:"
+
+// ProcessedMessageStore implements core.ProcessedMessageStore using Redis
+type ProcessedMessageStore struct {
+	client *redis.Client
+}
+
+// NewProcessedMessageStore creates a new Redis-backed processed message store
+func NewProcessedMessageStore(client *redis.Client) *ProcessedMessageStore {
+	return &ProcessedMessageStore{client: client}
+}
+
+// MarkProcessed claims a message ID. SETNX decides, so when Meta redelivers a message to two
+// replicas at once only one of them handles it.
+func (s *ProcessedMessageStore) MarkProcessed(ctx context.Context, messageID string, ttl time.Duration) (bool, error) {
+	first, err := s.client.SetNX(ctx, processedMessageKeyPrefix+messageID, time.Now().Unix(), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark message processed: %w", err)
+	}
+	return first, nil
+}
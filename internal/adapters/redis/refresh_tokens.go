@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// refreshTokenKeyPrefix holds one refresh token record, keyed by the token's SHA-256; the key's TTL is its lifetime
+	refreshTokenKeyPrefix = "auth:refresh:"
+	// refreshTokenUserKeyPrefix is a set of a user's outstanding token hashes, used to revoke them all at once
+	refreshTokenUserKeyPrefix = "auth:refresh:user:"
+)
+
+// RefreshTokenStore implements core.RefreshTokenStore using Redis
+type RefreshTokenStore struct {
+	client *redis.Client
+}
+
+// NewRefreshTokenStore creates a new Redis-backed refresh token store
+func NewRefreshTokenStore(client *redis.Client) *RefreshTokenStore {
+	return &RefreshTokenStore{client: client}
+}
+
+// Save stores a refresh token until its expiry
+func (s *RefreshTokenStore) Save(ctx context.Context, token *core.RefreshToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal refresh token: %w", err)
+	}
+
+	ttl := time.Until(token.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("refresh token already expired")
+	}
+
+	userKey := refreshTokenUserKeyPrefix + token.UserID
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, refreshTokenKeyPrefix+token.TokenHash, data, ttl)
+		pipe.SAdd(ctx, userKey, token.TokenHash)
+		// The index only needs to outlive the user's newest token
+		pipe.Expire(ctx, userKey, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+// Consume atomically fetches and deletes a refresh token. GETDEL decides ownership,
+// so two concurrent refreshes with the same token can't both succeed.
+func (s *RefreshTokenStore) Consume(ctx context.Context, tokenHash string) (*core.RefreshToken, error) {
+	data, err := s.client.GetDel(ctx, refreshTokenKeyPrefix+tokenHash).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return nil, fmt.Errorf("failed to read refresh token: %w", err)
+	}
+
+	var token core.RefreshToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refresh token: %w", err)
+	}
+
+	if err := s.client.SRem(ctx, refreshTokenUserKeyPrefix+token.UserID, tokenHash).Err(); err != nil {
+		return nil, fmt.Errorf("failed to update refresh token index: %w", err)
+	}
+	return &token, nil
+}
+
+// RevokeUser deletes every refresh token issued to the user
+func (s *RefreshTokenStore) RevokeUser(ctx context.Context, userID string) error {
+	userKey := refreshTokenUserKeyPrefix + userID
+	hashes, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read refresh tokens: %w", err)
+	}
+
+	keys := make([]string, 0, len(hashes)+1)
+	for _, hash := range hashes {
+		keys = append(keys, refreshTokenKeyPrefix+hash)
+	}
+	keys = append(keys, userKey)
+
+	if err := s.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
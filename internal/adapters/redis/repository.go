@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/core"
@@ -19,18 +20,39 @@ const (
 
 // Repository implements SessionRepository using Redis
 type Repository struct {
-	client *redis.Client
+	client     *redis.Client
+	defaultTTL time.Duration
+	sliding    bool
 }
 
 // NewRepository creates a new Redis repository
 func NewRepository(client *redis.Client) *Repository {
-	return &Repository{client: client}
+	return &Repository{
+		client:     client,
+		defaultTTL: DefaultSessionTTL,
+	}
+}
+
+// ConfigureTTL sets the TTL used when callers don't pass one and, when sliding is on,
+// restarts it on every Get so active customers aren't cut off mid-order
+func (r *Repository) ConfigureTTL(ttl time.Duration, sliding bool) {
+	if ttl > 0 {
+		r.defaultTTL = ttl
+	}
+	r.sliding = sliding
 }
 
 // Get retrieves a session from Redis
 func (r *Repository) Get(ctx context.Context, phone string) (*core.Session, error) {
 	key := SessionKeyPrefix + phone
-	val, err := r.client.Get(ctx, key).Result()
+
+	var val string
+	var err error
+	if r.sliding {
+		val, err = r.getAndRefresh(ctx, key)
+	} else {
+		val, err = r.client.Get(ctx, key).Result()
+	}
 	if err == redis.Nil {
 		return nil, fmt.Errorf("session not found")
 	}
@@ -56,22 +78,74 @@ func (r *Repository) Set(ctx context.Context, phone string, session *core.Sessio
 
 	ttlDuration := time.Duration(ttl) * time.Second
 	if ttl <= 0 {
-		ttlDuration = DefaultSessionTTL
+		ttlDuration = r.defaultTTL
 	}
 
 	if err := r.client.Set(ctx, key, data, ttlDuration).Err(); err != nil {
 		return fmt.Errorf("failed to set session: %w", err)
 	}
 
+	// The idle cart index only drives reminders, so a failure here mustn't fail the conversation
+	if err := r.trackCart(ctx, phone, session); err != nil {
+		log.Printf("Failed to track cart activity for %s: %v", phone, err)
+	}
+
 	return nil
 }
 
+// Inspect reads a session and its remaining TTL without restarting it, for the admin session view
+func (r *Repository) Inspect(ctx context.Context, phone string) (*core.Session, time.Duration, error) {
+	key := SessionKeyPrefix + phone
+
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		ttl = pipe.TTL(ctx, key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, 0, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	val, err := get.Result()
+	if err == redis.Nil {
+		return nil, 0, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	var session core.Session
+	if err := json.Unmarshal([]byte(val), &session); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, ttl.Val(), nil
+}
+
+// getAndRefresh reads the session and restarts its TTL in one round trip
+func (r *Repository) getAndRefresh(ctx context.Context, key string) (string, error) {
+	var get *redis.StringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Expire(ctx, key, r.defaultTTL)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return "", err
+	}
+	return get.Result()
+}
+
 // Delete removes a session from Redis
 func (r *Repository) Delete(ctx context.Context, phone string) error {
 	key := SessionKeyPrefix + phone
 	if err := r.client.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
+	if err := r.client.ZRem(ctx, cartActivityKey, phone).Err(); err != nil {
+		return fmt.Errorf("failed to clear cart activity: %w", err)
+	}
 	return nil
 }
 
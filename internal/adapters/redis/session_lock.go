@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// sessionLockKeyPrefix holds the token of whoever is processing a phone's message; the key's TTL frees
+// the lock if its holder crashes
+const sessionLockKeyPrefix = "session_lock:"
+
+// sessionUnlockScript deletes the lock only while it still holds the caller's token, so a holder whose
+// lock expired can't release the next holder's
+var sessionUnlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// SessionLockStore implements core.SessionLockStore using Redis
+type SessionLockStore struct {
+	client *redis.Client
+}
+
+// NewSessionLockStore creates a new Redis-backed session lock store
+func NewSessionLockStore(client *redis.Client) *SessionLockStore {
+	return &SessionLockStore{client: client}
+}
+
+// TryLock takes a phone's lock for ttl with a random token, without waiting
+func (s *SessionLockStore) TryLock(ctx context.Context, phone string, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	ok, err := s.client.SetNX(ctx, sessionLockKeyPrefix+phone, token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to take session lock: %w", err)
+	}
+	if !ok {
+		return "", false, nil
+	}
+	return token, true, nil
+}
+
+// Unlock releases a phone's lock if token still holds it
+func (s *SessionLockStore) Unlock(ctx context.Context, phone string, token string) error {
+	if err := sessionUnlockScript.Run(ctx, s.client, []string{sessionLockKeyPrefix + phone}, token).Err(); err != nil {
+		return fmt.Errorf("failed to release session lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// stkQueueKey is a sorted set of waiting job IDs scored by when they are due (unix ms)
+	stkQueueKey = "payment:stk:queue"
+	// stkProcessingKey is a sorted set of claimed job IDs scored by their visibility deadline (unix ms)
+	stkProcessingKey = "payment:stk:processing"
+	// stkJobsKey is a hash of job ID to job JSON for queued and claimed jobs
+	stkJobsKey = "payment:stk:jobs"
+	// stkAttemptsKey is a hash of job ID to delivery count, bumped on every claim
+	stkAttemptsKey = "payment:stk:attempts"
+	// stkDeadLetterKey is a list of jobs that exhausted their retries, newest first
+	stkDeadLetterKey = "payment:stk:dead"
+	// stkDeadLetterCap bounds the dead-letter list so it can't grow forever
+	stkDeadLetterCap = 500
+)
+
+// stkClaimScript moves the oldest due job from the queue to processing in one step, so a crash
+// can't leave a job in neither set. Returns {id, job JSON, attempts} or nil when nothing is due.
+var stkClaimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 1)
+if #ids == 0 then
+	return false
+end
+local id = ids[1]
+redis.call('ZREM', KEYS[1], id)
+local data = redis.call('HGET', KEYS[3], id)
+if not data then
+	redis.call('HDEL', KEYS[4], id)
+	return false
+end
+redis.call('ZADD', KEYS[2], ARGV[2], id)
+local attempts = redis.call('HINCRBY', KEYS[4], id, 1)
+return {id, data, attempts}
+`)
+
+// stkRequeueScript returns claimed jobs whose visibility deadline has passed to the queue, due now
+var stkRequeueScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[1], id)
+end
+return #ids
+`)
+
+// STKPushQueue implements core.STKPushQueue using Redis
+type STKPushQueue struct {
+	client *redis.Client
+}
+
+// NewSTKPushQueue creates a new Redis-backed STK push queue
+func NewSTKPushQueue(client *redis.Client) *STKPushQueue {
+	return &STKPushQueue{client: client}
+}
+
+// Enqueue adds a job, due immediately
+func (q *STKPushQueue) Enqueue(ctx context.Context, job *core.STKPushJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal STK push job: %w", err)
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, stkJobsKey, job.ID, data)
+		pipe.HSet(ctx, stkAttemptsKey, job.ID, job.Attempts)
+		pipe.ZAdd(ctx, stkQueueKey, redis.Z{Score: float64(time.Now().UnixMilli()), Member: job.ID})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue STK push job: %w", err)
+	}
+	return nil
+}
+
+// Claim takes the oldest due job and hides it from other workers for visibility
+func (q *STKPushQueue) Claim(ctx context.Context, now time.Time, visibility time.Duration) (*core.STKPushJob, error) {
+	result, err := stkClaimScript.Run(ctx, q.client,
+		[]string{stkQueueKey, stkProcessingKey, stkJobsKey, stkAttemptsKey},
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.FormatInt(now.Add(visibility).UnixMilli(), 10),
+	).Slice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim STK push job: %w", err)
+	}
+	if len(result) != 3 {
+		return nil, fmt.Errorf("failed to claim STK push job: unexpected reply %v", result)
+	}
+
+	data, _ := result[1].(string)
+	var job core.STKPushJob
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal STK push job: %w", err)
+	}
+	if attempts, ok := result[2].(int64); ok {
+		job.Attempts = int(attempts)
+	}
+	return &job, nil
+}
+
+// Ack drops a delivered job
+func (q *STKPushQueue) Ack(ctx context.Context, job *core.STKPushJob) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, stkProcessingKey, job.ID)
+		pipe.HDel(ctx, stkJobsKey, job.ID)
+		pipe.HDel(ctx, stkAttemptsKey, job.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ack STK push job: %w", err)
+	}
+	return nil
+}
+
+// Retry puts a claimed job back on the queue, due at at
+func (q *STKPushQueue) Retry(ctx context.Context, job *core.STKPushJob, at time.Time) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal STK push job: %w", err)
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, stkJobsKey, job.ID, data)
+		pipe.ZRem(ctx, stkProcessingKey, job.ID)
+		pipe.ZAdd(ctx, stkQueueKey, redis.Z{Score: float64(at.UnixMilli()), Member: job.ID})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retry STK push job: %w", err)
+	}
+	return nil
+}
+
+// DeadLetter parks a job that exhausted its retries
+func (q *STKPushQueue) DeadLetter(ctx context.Context, job *core.STKPushJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal STK push job: %w", err)
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, stkProcessingKey, job.ID)
+		pipe.HDel(ctx, stkJobsKey, job.ID)
+		pipe.HDel(ctx, stkAttemptsKey, job.ID)
+		pipe.LPush(ctx, stkDeadLetterKey, data)
+		pipe.LTrim(ctx, stkDeadLetterKey, 0, stkDeadLetterCap-1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter STK push job: %w", err)
+	}
+	return nil
+}
+
+// RequeueExpired returns jobs whose worker died (or hung) mid-delivery to the queue
+func (q *STKPushQueue) RequeueExpired(ctx context.Context, now time.Time) (int, error) {
+	count, err := stkRequeueScript.Run(ctx, q.client,
+		[]string{stkProcessingKey, stkQueueKey},
+		strconv.FormatInt(now.UnixMilli(), 10),
+	).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue expired STK push jobs: %w", err)
+	}
+	return count, nil
+}
+
+// ListDeadLetters returns the most recent dead-lettered jobs
+func (q *STKPushQueue) ListDeadLetters(ctx context.Context, limit int) ([]*core.STKPushJob, error) {
+	if limit <= 0 || limit > stkDeadLetterCap {
+		limit = stkDeadLetterCap
+	}
+
+	members, err := q.client.LRange(ctx, stkDeadLetterKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered STK push jobs: %w", err)
+	}
+
+	jobs := make([]*core.STKPushJob, 0, len(members))
+	for _, member := range members {
+		var job core.STKPushJob
+		if err := json.Unmarshal([]byte(member), &job); err != nil {
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,256 @@
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxInFlight bounds reports being sent at once; further reports are dropped (they are still logged)
+	maxInFlight = 20
+	sendTimeout = 10 * time.Second
+	modulePath  = "github.com/dumu-tech/destination-cocktails"
+)
+
+// Reporter sends errors and panics to Sentry (or a Sentry-compatible service such as GlitchTip)
+// through the store API, without blocking the caller
+type Reporter struct {
+	storeURL    string
+	authHeader  string
+	release     string
+	environment string
+	serverName  string
+	httpClient  *http.Client
+
+	slots   chan struct{}
+	pending sync.WaitGroup
+}
+
+// NewReporter creates a reporter from a DSN (https://<public_key>@<host>/<project_id>).
+// Every event is tagged with release and environment.
+func NewReporter(dsn string, release string, environment string) (*Reporter, error) {
+	parsed, err := url.Parse(strings.TrimSpace(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Sentry DSN: %w", err)
+	}
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing public key")
+	}
+
+	path := strings.Trim(parsed.Path, "/")
+	projectID := path
+	prefix := ""
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		prefix, projectID = "/"+path[:i], path[i+1:]
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing project ID")
+	}
+
+	serverName, _ := os.Hostname()
+	return &Reporter{
+		storeURL: fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, prefix, projectID),
+		authHeader: fmt.Sprintf("Sentry sentry_version=7, sentry_client=destination-cocktails/1.0, sentry_key=%s",
+			parsed.User.Username()),
+		release:     release,
+		environment: environment,
+		serverName:  serverName,
+		httpClient:  &http.Client{Timeout: sendTimeout},
+		slots:       make(chan struct{}, maxInFlight),
+	}, nil
+}
+
+// event is the subset of the Sentry event payload we send
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Exception   struct {
+		Values []exception `json:"values"`
+	} `json:"exception"`
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+type exception struct {
+	Type       string     `json:"type"`
+	Value      string     `json:"value"`
+	Stacktrace stacktrace `json:"stacktrace"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// CaptureException reports err at error level
+func (r *Reporter) CaptureException(ctx context.Context, err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	evt := r.newEvent("error", tags)
+	evt.Exception.Values = []exception{{
+		Type:       reflect.TypeOf(err).String(),
+		Value:      err.Error(),
+		Stacktrace: stacktrace{Frames: callerFrames()},
+	}}
+	r.send(evt)
+}
+
+// CapturePanic reports a recovered panic at fatal level; called from the recovering goroutine, so the
+// captured frames include where the panic happened
+func (r *Reporter) CapturePanic(ctx context.Context, recovered interface{}, stack []byte, tags map[string]string) {
+	evt := r.newEvent("fatal", tags)
+	evt.Exception.Values = []exception{{
+		Type:       "panic",
+		Value:      fmt.Sprint(recovered),
+		Stacktrace: stacktrace{Frames: callerFrames()},
+	}}
+	evt.Extra = map[string]interface{}{"stack": string(stack)}
+	r.send(evt)
+}
+
+// Flush waits for reports being sent, up to timeout
+func (r *Reporter) Flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		r.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (r *Reporter) newEvent(level string, tags map[string]string) *event {
+	return &event{
+		EventID:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       level,
+		Platform:    "go",
+		Release:     r.release,
+		Environment: r.environment,
+		ServerName:  r.serverName,
+		Tags:        tags,
+	}
+}
+
+// send posts the event in the background; when too many are in flight it is dropped
+func (r *Reporter) send(evt *event) {
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		log.Printf("Sentry reporter busy, dropping event %s", evt.EventID)
+		return
+	}
+
+	r.pending.Add(1)
+	go func() {
+		defer func() {
+			<-r.slots
+			r.pending.Done()
+		}()
+		if err := r.post(evt); err != nil {
+			log.Printf("Failed to send event %s to Sentry: %v", evt.EventID, err)
+		}
+	}()
+}
+
+func (r *Reporter) post(evt *event) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.authHeader)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("sentry returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// callerFrames returns the current goroutine's stack, oldest call first as Sentry expects,
+// without the reporting machinery itself
+func callerFrames() []frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	callers := runtime.CallersFrames(pcs[:n])
+
+	var frames []frame
+	for {
+		caller, more := callers.Next()
+		if !isReportingFrame(caller.Function) {
+			frames = append(frames, frame{
+				Function: caller.Function,
+				Module:   functionPackage(caller.Function),
+				AbsPath:  caller.File,
+				Lineno:   caller.Line,
+				InApp:    strings.HasPrefix(caller.Function, modulePath),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+func isReportingFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.Callers") ||
+		strings.HasPrefix(function, "runtime/debug.") ||
+		strings.HasPrefix(function, modulePath+"/internal/reporting.") ||
+		strings.HasPrefix(function, modulePath+"/internal/adapters/sentry.")
+}
+
+// functionPackage returns the package path of a fully qualified function name
+func functionPackage(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[lastSlash+1:], "."); dot >= 0 {
+		return function[:lastSlash+1+dot]
+	}
+	return function
+}
@@ -0,0 +1,240 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	googleSheetsBaseURL   = "https://sheets.googleapis.com/v4/spreadsheets"
+	googleTokenURL        = "https://oauth2.googleapis.com/token"
+	googleSheetsScope     = "https://www.googleapis.com/auth/spreadsheets"
+	googleTokenLifetime   = time.Hour
+	googleTokenEarlyRenew = time.Minute // Renew this long before the token expires
+)
+
+// GoogleSheetsClient appends rows to one sheet of a Google spreadsheet as a service account. The
+// spreadsheet has to be shared (as editor) with the service account's client_email.
+type GoogleSheetsClient struct {
+	spreadsheetID string
+	sheetRange    string // The sheet's name in A1 notation; Sheets appends after its last row
+	clientEmail   string
+	privateKey    *rsa.PrivateKey
+	tokenURL      string
+	httpClient    *http.Client
+
+	accessToken string
+	tokenExpiry time.Time
+	tokenMu     sync.Mutex
+}
+
+// serviceAccountKey is the part of a service account's JSON key file the client reads
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// tokenResponse is the OAuth JWT bearer grant response
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// NewGoogleSheetsClient creates a client for sheetName in spreadsheetID from a service account key
+// (the JSON key file's contents)
+func NewGoogleSheetsClient(credentialsJSON string, spreadsheetID string, sheetName string) (*GoogleSheetsClient, error) {
+	if strings.TrimSpace(credentialsJSON) == "" {
+		return nil, fmt.Errorf("GOOGLE_SHEETS_CREDENTIALS is required but not set")
+	}
+	if strings.TrimSpace(spreadsheetID) == "" {
+		return nil, fmt.Errorf("GOOGLE_SHEETS_SPREADSHEET_ID is required but not set")
+	}
+
+	var key serviceAccountKey
+	if err := json.Unmarshal([]byte(credentialsJSON), &key); err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("service account key has no client_email or private_key")
+	}
+	privateKey, err := parsePrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+	if strings.TrimSpace(sheetName) == "" {
+		sheetName = "Sheet1"
+	}
+
+	return &GoogleSheetsClient{
+		spreadsheetID: spreadsheetID,
+		sheetRange:    "'" + strings.ReplaceAll(sheetName, "'", "''") + "'",
+		clientEmail:   key.ClientEmail,
+		privateKey:    privateKey,
+		tokenURL:      tokenURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// AppendRow adds values as a new row below the sheet's last row. Values are entered as if typed, so
+// Sheets reads "2026-03-28" as a date and numbers stay numbers.
+func (c *GoogleSheetsClient) AppendRow(ctx context.Context, values []interface{}) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"values": [][]interface{}{values},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode sheet row: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/values/%s:append?valueInputOption=USER_ENTERED&insertDataOption=INSERT_ROWS",
+		googleSheetsBaseURL, url.PathEscape(c.spreadsheetID), url.PathEscape(c.sheetRange))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sheet append request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to append sheet row: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusUnauthorized {
+			c.clearToken()
+		}
+		return fmt.Errorf("google sheets API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// token returns a cached access token, exchanging a freshly signed JWT for a new one when it's about to expire
+func (c *GoogleSheetsClient) token(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	now := time.Now()
+	if c.accessToken != "" && now.Before(c.tokenExpiry.Add(-googleTokenEarlyRenew)) {
+		return c.accessToken, nil
+	}
+
+	assertion, err := c.signAssertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request Google access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("google token error (status %d): %s", resp.StatusCode, string(body))
+	}
+	var result tokenResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("google token response has no access_token")
+	}
+
+	c.accessToken = result.AccessToken
+	c.tokenExpiry = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return c.accessToken, nil
+}
+
+func (c *GoogleSheetsClient) clearToken() {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.accessToken = ""
+	c.tokenExpiry = time.Time{}
+}
+
+// signAssertion builds the RS256-signed JWT the token endpoint exchanges for an access token
+func (c *GoogleSheetsClient) signAssertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   c.clientEmail,
+		"scope": googleSheetsScope,
+		"aud":   c.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(googleTokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey reads the PEM private key from a service account key (PKCS#8, as Google issues them)
+func parsePrivateKey(value string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, fmt.Errorf("service account private_key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("service account private_key is not an RSA key")
+		}
+		return rsaKey, nil
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account private_key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,120 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+)
+
+const (
+	africasTalkingLiveURL    = "https://api.africastalking.com/version1/messaging"
+	africasTalkingSandboxURL = "https://api.sandbox.africastalking.com/version1/messaging"
+)
+
+// AfricasTalkingClient sends SMS through the Africa's Talking messaging API
+type AfricasTalkingClient struct {
+	baseURL    string
+	username   string
+	apiKey     string
+	senderID   string // Optional registered sender ID / short code; empty uses the account default
+	httpClient *http.Client
+}
+
+// africasTalkingResponse is the body returned by POST /version1/messaging
+type africasTalkingResponse struct {
+	SMSMessageData struct {
+		Message    string `json:"Message"`
+		Recipients []struct {
+			StatusCode int    `json:"statusCode"`
+			Number     string `json:"number"`
+			Status     string `json:"status"`
+			MessageID  string `json:"messageId"`
+		} `json:"Recipients"`
+	} `json:"SMSMessageData"`
+}
+
+// NewAfricasTalkingClient creates an Africa's Talking SMS client. The "sandbox" username uses the
+// sandbox API, as Africa's Talking does.
+func NewAfricasTalkingClient(username string, apiKey string, senderID string) (*AfricasTalkingClient, error) {
+	if username == "" {
+		return nil, fmt.Errorf("AFRICASTALKING_USERNAME is required but not set")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("AFRICASTALKING_API_KEY is required but not set")
+	}
+
+	baseURL := africasTalkingLiveURL
+	if username == "sandbox" {
+		baseURL = africasTalkingSandboxURL
+	}
+
+	return &AfricasTalkingClient{
+		baseURL:  baseURL,
+		username: username,
+		apiKey:   apiKey,
+		senderID: senderID,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// SendSMS sends a single SMS. phone may be in any format msisdn.Normalize accepts.
+func (c *AfricasTalkingClient) SendSMS(ctx context.Context, phone string, message string) error {
+	to, err := msisdn.Normalize(phone)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS to %s: %w", phone, err)
+	}
+
+	form := url.Values{}
+	form.Set("username", c.username)
+	form.Set("to", to)
+	form.Set("message", message)
+	if c.senderID != "" {
+		form.Set("from", c.senderID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create SMS request: %w", err)
+	}
+	req.Header.Set("apiKey", c.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("africa's talking API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result africasTalkingResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse SMS response: %w", err)
+	}
+	if len(result.SMSMessageData.Recipients) == 0 {
+		return fmt.Errorf("SMS not sent: %s", result.SMSMessageData.Message)
+	}
+
+	// 100 Processed, 101 Sent and 102 Queued are accepted; anything else (invalid number,
+	// insufficient balance, blacklisted...) means the SMS won't arrive
+	recipient := result.SMSMessageData.Recipients[0]
+	switch recipient.StatusCode {
+	case 100, 101, 102:
+		return nil
+	default:
+		return fmt.Errorf("SMS to %s rejected: %s (status %d)", recipient.Number, recipient.Status, recipient.StatusCode)
+	}
+}
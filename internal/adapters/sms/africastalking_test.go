@@ -0,0 +1,104 @@
+package sms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newTestClient points an Africa's Talking client at a server answering with status and body
+func newTestClient(t *testing.T, senderID string, status int, body string, requests chan<- *http.Request) *AfricasTalkingClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(data)))
+		if requests != nil {
+			requests <- r
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewAfricasTalkingClient("bar", "key", senderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.baseURL = server.URL
+	return client
+}
+
+func TestSendSMS(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	client := newTestClient(t, "DESTINATION", http.StatusCreated, `{"SMSMessageData":{"Message":"Sent to 1/1","Recipients":[{"statusCode":101,"number":"+254712345678","status":"Success"}]}}`, requests)
+
+	if err := client.SendSMS(context.Background(), "0712 345 678", "Your code is 123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-requests
+	if req.Header.Get("apiKey") != "key" {
+		t.Errorf("apiKey header %q, want key", req.Header.Get("apiKey"))
+	}
+	body, _ := io.ReadAll(req.Body)
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{"username": {"bar"}, "to": {"+254712345678"}, "message": {"Your code is 123456"}, "from": {"DESTINATION"}}
+	for field := range want {
+		if form.Get(field) != want.Get(field) {
+			t.Errorf("%s = %q, want %q", field, form.Get(field), want.Get(field))
+		}
+	}
+}
+
+func TestSendSMSFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		phone  string
+		status int
+		body   string
+	}{
+		{name: "invalid phone", phone: "not a number", status: http.StatusCreated},
+		{name: "API error", phone: "0712345678", status: http.StatusUnauthorized, body: "The supplied authentication is invalid"},
+		{name: "no recipients", phone: "0712345678", status: http.StatusCreated, body: `{"SMSMessageData":{"Message":"InvalidSenderId","Recipients":[]}}`},
+		{name: "recipient rejected", phone: "0712345678", status: http.StatusCreated, body: `{"SMSMessageData":{"Message":"Sent to 0/1","Recipients":[{"statusCode":405,"number":"+254712345678","status":"InsufficientBalance"}]}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, "", tc.status, tc.body, nil)
+			if err := client.SendSMS(context.Background(), tc.phone, "hello"); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
+
+func TestNewAfricasTalkingClient(t *testing.T) {
+	if _, err := NewAfricasTalkingClient("", "key", ""); err == nil {
+		t.Error("missing username: got no error")
+	}
+	if _, err := NewAfricasTalkingClient("bar", "", ""); err == nil {
+		t.Error("missing API key: got no error")
+	}
+
+	sandbox, err := NewAfricasTalkingClient("sandbox", "key", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sandbox.baseURL != africasTalkingSandboxURL {
+		t.Errorf("sandbox username uses %s, want %s", sandbox.baseURL, africasTalkingSandboxURL)
+	}
+	live, err := NewAfricasTalkingClient("bar", "key", "DESTINATION")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.baseURL != africasTalkingLiveURL {
+		t.Errorf("live username uses %s, want %s", live.baseURL, africasTalkingLiveURL)
+	}
+}
@@ -18,6 +18,12 @@ type Client struct {
 	phoneNumberID string
 	token        string
 	httpClient   *http.Client
+	limiter      *rateLimiter
+	// Optional retry queue for transient failures (see queue.go)
+	outbound         core.OutboundMessageStore
+	maxRetryAttempts int
+	// Optional: other numbers pointed at this server, each with its own token (see SetSenderResolver)
+	senders core.WhatsAppSenderResolver
 }
 
 // NewClient creates a new WhatsApp client
@@ -36,29 +42,65 @@ func NewClient(phoneNumberID, token string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		limiter: newRateLimiter(DefaultMessagesPerSecond),
 	}
 }
 
-// SendMessage sends a generic message payload to WhatsApp
-func (c *Client) SendMessage(ctx context.Context, to string, payload interface{}) error {
-	url := fmt.Sprintf("%s/%s/messages", c.baseURL, c.phoneNumberID)
+// SetSenderResolver sends each message from the number the resolver picks for it (the number the customer
+// messaged), with that number's token. Messages it has no number for go out from the client's own number.
+func (c *Client) SetSenderResolver(senders core.WhatsAppSenderResolver) {
+	c.senders = senders
+}
 
+// sender returns the phone number ID and token a message to to is sent with
+func (c *Client) sender(ctx context.Context, to string) (string, string) {
+	if c.senders != nil {
+		if phoneNumberID, token, ok := c.senders.ResolveSender(ctx, to); ok {
+			return phoneNumberID, token
+		}
+	}
+	return c.phoneNumberID, c.token
+}
+
+// SendMessage sends a generic message payload to WhatsApp.
+// When retries are enabled, transient failures (429/5xx/network) are queued and nil is returned.
+func (c *Client) SendMessage(ctx context.Context, to string, payload interface{}) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
+	err = c.deliver(ctx, to, jsonData)
+	if err != nil && c.outbound != nil && isRetryable(err) {
+		return c.queueRetry(ctx, to, jsonData, err)
+	}
+	return err
+}
+
+// deliver posts an already-marshaled message to the Cloud API, pacing requests through the rate limiter
+func (c *Client) deliver(ctx context.Context, to string, jsonData []byte) error {
+	phoneNumberID, token := c.sender(ctx, to)
+	url := fmt.Sprintf("%s/%s/messages", c.baseURL, phoneNumberID)
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	requestID := core.RequestIDFrom(ctx)
+	if requestID != "" {
+		req.Header.Set(core.RequestIDHeader, requestID)
+	}
 
 	// Log request details (masked for security)
-	fmt.Printf("WhatsApp API Request: POST %s (to: %s, phone_id: %s)\n", 
-		url, to, c.phoneNumberID)
+	fmt.Printf("WhatsApp API Request: POST %s (to: %s, phone_id: %s, request_id: %s)\n", 
+		url, to, phoneNumberID, requestID)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -69,8 +111,13 @@ func (c *Client) SendMessage(ctx context.Context, to string, payload interface{}
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("whatsapp API error: status %d, url: %s, phone_number_id: %s, body: %s", 
-			resp.StatusCode, url, c.phoneNumberID, string(body))
+		return &APIError{
+			StatusCode:    resp.StatusCode,
+			URL:           url,
+			PhoneNumberID: phoneNumberID,
+			Body:          string(body),
+			RetryAfter:    parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	return nil
@@ -217,6 +264,11 @@ func (c *Client) SendMenu(ctx context.Context, phone string, products []*core.Pr
 
 // SendCategoryList sends a list of categories (implements WhatsAppGateway interface)
 func (c *Client) SendCategoryList(ctx context.Context, phone string, categories []string) error {
+	return c.SendCategoryListWithText(ctx, phone, "Select a category to browse:", "View Menu", categories)
+}
+
+// SendCategoryListWithText sends the category list with custom body text and button label (used for translated copy)
+func (c *Client) SendCategoryListWithText(ctx context.Context, phone string, text string, buttonLabel string, categories []string) error {
 	items := make([]struct {
 		ID          string
 		Title       string
@@ -229,7 +281,25 @@ func (c *Client) SendCategoryList(ctx context.Context, phone string, categories
 		items[i].Title = truncateTitle(cat, 24)
 	}
 
-	return c.sendInteractiveList(ctx, phone, "Select a category to browse:", "View Menu", items)
+	return c.sendInteractiveList(ctx, phone, text, buttonLabel, items)
+}
+
+// SendListRows sends an interactive list where each row has its own reply ID and title
+// (used for paged category lists with a "More" row). WhatsApp shows at most 10 rows.
+func (c *Client) SendListRows(ctx context.Context, phone string, text string, buttonLabel string, rows []core.ListRow) error {
+	items := make([]struct {
+		ID          string
+		Title       string
+		Description string
+	}, len(rows))
+
+	for i, row := range rows {
+		items[i].ID = row.ID
+		items[i].Title = truncateTitle(row.Title, 24)
+		items[i].Description = row.Description
+	}
+
+	return c.sendInteractiveList(ctx, phone, text, buttonLabel, items)
 }
 
 // SendProductList sends a list of products (implements WhatsAppGateway interface)
@@ -0,0 +1,61 @@
+package whatsapp
+
+import (
+	"context"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// flowMessageVersion is the Cloud API version of the interactive flow message format
+const flowMessageVersion = "3"
+
+// FlowMessage represents an interactive message whose button opens a WhatsApp Flow
+type FlowMessage struct {
+	MessagingProduct string `json:"messaging_product"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Interactive      struct {
+		Type string `json:"type"`
+		Body struct {
+			Text string `json:"text"`
+		} `json:"body"`
+		Action struct {
+			Name       string `json:"name"`
+			Parameters struct {
+				FlowMessageVersion string `json:"flow_message_version"`
+				FlowToken          string `json:"flow_token"`
+				FlowID             string `json:"flow_id"`
+				FlowCTA            string `json:"flow_cta"`
+				FlowAction         string `json:"flow_action"`
+				FlowActionPayload  struct {
+					Screen string                 `json:"screen"`
+					Data   map[string]interface{} `json:"data,omitempty"`
+				} `json:"flow_action_payload"`
+			} `json:"parameters"`
+		} `json:"action"`
+	} `json:"interactive"`
+}
+
+// SendFlow sends a message with a button that opens a published WhatsApp Flow on flow.Screen.
+// The customer's submission arrives on the webhook as an interactive nfm_reply.
+func (c *Client) SendFlow(ctx context.Context, phone string, flow core.Flow) error {
+	payload := FlowMessage{
+		MessagingProduct: "whatsapp",
+		To:               phone,
+		Type:             "interactive",
+	}
+	payload.Interactive.Type = "flow"
+	payload.Interactive.Body.Text = flow.Body
+	payload.Interactive.Action.Name = "flow"
+
+	params := &payload.Interactive.Action.Parameters
+	params.FlowMessageVersion = flowMessageVersion
+	params.FlowToken = flow.Token
+	params.FlowID = flow.ID
+	params.FlowCTA = truncateTitle(flow.CTA, 20)
+	params.FlowAction = "navigate"
+	params.FlowActionPayload.Screen = flow.Screen
+	params.FlowActionPayload.Data = flow.Data
+
+	return c.SendMessage(ctx, phone, payload)
+}
@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Ping checks that the Graph API is reachable and the token can read the configured phone number.
+// It skips the outbound rate limiter so health checks never queue behind customer messages.
+func (c *Client) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/%s?fields=id", c.baseURL, c.phoneNumberID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach WhatsApp API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &APIError{
+			StatusCode:    resp.StatusCode,
+			URL:           url,
+			PhoneNumberID: c.phoneNumberID,
+			Body:          string(body),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// DocumentMessage represents a document message referencing uploaded media
+type DocumentMessage struct {
+	MessagingProduct string `json:"messaging_product"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Document         struct {
+		ID       string `json:"id"`
+		Filename string `json:"filename,omitempty"`
+		Caption  string `json:"caption,omitempty"`
+	} `json:"document"`
+}
+
+// SendDocument uploads a PDF and sends it as a WhatsApp document with an optional caption
+func (c *Client) SendDocument(ctx context.Context, phone string, filename string, data []byte, caption string) error {
+	mediaID, err := c.uploadMedia(ctx, phone, filename, "application/pdf", data)
+	if err != nil {
+		return err
+	}
+
+	payload := DocumentMessage{
+		MessagingProduct: "whatsapp",
+		To:               phone,
+		Type:             "document",
+	}
+	payload.Document.ID = mediaID
+	payload.Document.Filename = filename
+	payload.Document.Caption = caption
+
+	return c.SendMessage(ctx, phone, payload)
+}
+
+// uploadMedia stores a file with the Cloud API, under the number that will send it to to, and returns its
+// media ID (valid for 30 days, so queued retries of the document message can still reference it)
+func (c *Client) uploadMedia(ctx context.Context, to string, filename string, contentType string, data []byte) (string, error) {
+	phoneNumberID, token := c.sender(ctx, to)
+	url := fmt.Sprintf("%s/%s/media", c.baseURL, phoneNumberID)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("messaging_product", "whatsapp"); err != nil {
+		return "", fmt.Errorf("failed to build media upload: %w", err)
+	}
+	if err := writer.WriteField("type", contentType); err != nil {
+		return "", fmt.Errorf("failed to build media upload: %w", err)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to build media upload: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to build media upload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to build media upload: %w", err)
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("failed to upload media: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", &APIError{
+			StatusCode:    resp.StatusCode,
+			URL:           url,
+			PhoneNumberID: phoneNumberID,
+			Body:          string(respBody),
+			RetryAfter:    parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil || result.ID == "" {
+		return "", fmt.Errorf("failed to parse media upload response: %s", string(respBody))
+	}
+	return result.ID, nil
+}
@@ -0,0 +1,226 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultMessagesPerSecond keeps us well under Cloud API throughput limits (80 msg/s on the default tier)
+	DefaultMessagesPerSecond = 20
+	// DefaultMaxRetryAttempts is how many retries a message gets before it is dead-lettered
+	DefaultMaxRetryAttempts = 6
+
+	retryBaseDelay     = 5 * time.Second
+	retryMaxDelay      = 10 * time.Minute
+	retryPollInterval  = 2 * time.Second
+	retryBatchSize     = 20
+	retryDeliveryLimit = 30 * time.Second
+)
+
+// APIError is a non-200 response from the WhatsApp Cloud API
+type APIError struct {
+	StatusCode    int
+	URL           string
+	PhoneNumberID string
+	Body          string
+	RetryAfter    time.Duration // From the Retry-After header, when Meta sends one
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("whatsapp API error: status %d, url: %s, phone_number_id: %s, body: %s",
+		e.StatusCode, e.URL, e.PhoneNumberID, e.Body)
+}
+
+// isRetryable reports whether a send failure is transient: rate limiting, a Meta-side 5xx or a network error.
+// Other 4xx responses (bad payload, invalid recipient) will fail the same way again.
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// EnableRetries turns on the outbound retry queue. Transient failures are persisted in store
+// and redelivered by RunRetryWorker with exponential backoff; messages that still fail after
+// maxAttempts are moved to the dead-letter list.
+func (c *Client) EnableRetries(store core.OutboundMessageStore, maxAttempts int) {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxRetryAttempts
+	}
+	c.outbound = store
+	c.maxRetryAttempts = maxAttempts
+}
+
+// SetRateLimit overrides how many Cloud API requests per second the client sends
+func (c *Client) SetRateLimit(perSecond int) {
+	if perSecond <= 0 {
+		perSecond = DefaultMessagesPerSecond
+	}
+	c.limiter = newRateLimiter(perSecond)
+}
+
+// queueRetry persists a failed message for redelivery. If it can't be queued the original error is returned.
+func (c *Client) queueRetry(ctx context.Context, to string, payload []byte, sendErr error) error {
+	now := time.Now()
+	msg := &core.OutboundMessage{
+		ID:            uuid.New().String(),
+		To:            to,
+		Payload:       payload,
+		Attempts:      1,
+		LastError:     sendErr.Error(),
+		RequestID:     core.RequestIDFrom(ctx),
+		PhoneNumberID: core.WhatsAppNumberFrom(ctx),
+		CreatedAt:     now,
+	}
+	msg.NextAttemptAt = now.Add(retryDelay(msg.Attempts, sendErr))
+
+	if err := c.outbound.ScheduleRetry(ctx, msg); err != nil {
+		log.Printf("Failed to queue WhatsApp message to %s for retry: %v", to, err)
+		return sendErr
+	}
+
+	log.Printf("WhatsApp send to %s failed (%v), queued for retry at %s", to, sendErr, msg.NextAttemptAt.Format(time.RFC3339))
+	return nil
+}
+
+// RunRetryWorker redelivers queued messages until ctx is cancelled. Safe to run on every replica.
+func (c *Client) RunRetryWorker(ctx context.Context) {
+	if c.outbound == nil {
+		return
+	}
+
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.processDueRetries(ctx)
+		}
+	}
+}
+
+func (c *Client) processDueRetries(ctx context.Context) {
+	due, err := c.outbound.ClaimDue(ctx, time.Now(), retryBatchSize)
+	if err != nil {
+		log.Printf("Error claiming WhatsApp retries: %v", err)
+	}
+
+	for _, msg := range due {
+		// Sent again from the number it was first sent from, with the request ID that sent it
+		sendCtx := core.WithWhatsAppNumber(core.WithRequestID(ctx, msg.RequestID), msg.PhoneNumberID)
+		sendCtx, cancel := context.WithTimeout(sendCtx, retryDeliveryLimit)
+		err := c.deliver(sendCtx, msg.To, msg.Payload)
+		cancel()
+
+		if err == nil {
+			log.Printf("WhatsApp message %s to %s delivered after %d failed attempts", msg.ID, msg.To, msg.Attempts)
+			continue
+		}
+
+		msg.Attempts++
+		msg.LastError = err.Error()
+
+		if !isRetryable(err) || msg.Attempts > c.maxRetryAttempts {
+			failedAt := time.Now()
+			msg.FailedAt = &failedAt
+			if dlqErr := c.outbound.DeadLetter(ctx, msg); dlqErr != nil {
+				log.Printf("Error dead-lettering WhatsApp message %s: %v", msg.ID, dlqErr)
+			}
+			log.Printf("WhatsApp message %s to %s dead-lettered after %d attempts: %v", msg.ID, msg.To, msg.Attempts, err)
+			continue
+		}
+
+		msg.NextAttemptAt = time.Now().Add(retryDelay(msg.Attempts, err))
+		if err := c.outbound.ScheduleRetry(ctx, msg); err != nil {
+			log.Printf("Error rescheduling WhatsApp message %s: %v", msg.ID, err)
+		}
+	}
+}
+
+// retryDelay is exponential backoff (5s, 10s, 20s, ... capped at 10m), or Meta's Retry-After when longer
+func retryDelay(attempt int, err error) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// rateLimiter paces requests to a fixed rate, allowing a one-second burst
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perSecond int) *rateLimiter {
+	return &rateLimiter{
+		interval: time.Second / time.Duration(perSecond),
+		burst:    time.Second,
+	}
+}
+
+// Wait blocks until the next request slot is available or ctx is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if earliest := now.Add(-l.burst); l.next.Before(earliest) {
+		l.next = earliest
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package whatsapp
+
+import "context"
+
+// TemplateMessage represents a message built from an approved WhatsApp template. Templates are the only
+// way to message a customer outside the 24-hour customer service window.
+type TemplateMessage struct {
+	MessagingProduct string `json:"messaging_product"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Template         struct {
+		Name     string `json:"name"`
+		Language struct {
+			Code string `json:"code"`
+		} `json:"language"`
+		Components []TemplateComponent `json:"components,omitempty"`
+	} `json:"template"`
+}
+
+// TemplateComponent fills the variables of one part of a template
+type TemplateComponent struct {
+	Type       string              `json:"type"`
+	Parameters []TemplateParameter `json:"parameters"`
+}
+
+// TemplateParameter is one {{n}} variable
+type TemplateParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SendTemplate sends an approved template; params fill the body's {{1}}, {{2}}, ... in order
+func (c *Client) SendTemplate(ctx context.Context, phone string, name string, language string, params []string) error {
+	payload := TemplateMessage{
+		MessagingProduct: "whatsapp",
+		To:               phone,
+		Type:             "template",
+	}
+	payload.Template.Name = name
+	payload.Template.Language.Code = language
+
+	if len(params) > 0 {
+		body := TemplateComponent{Type: "body"}
+		for _, param := range params {
+			body.Parameters = append(body.Parameters, TemplateParameter{Type: "text", Text: param})
+		}
+		payload.Template.Components = []TemplateComponent{body}
+	}
+
+	return c.SendMessage(ctx, phone, payload)
+}
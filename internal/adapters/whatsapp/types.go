@@ -93,7 +93,18 @@ type WebhookPayload struct {
 							Title       string `json:"title"`
 							Description string `json:"description"`
 						} `json:"list_reply,omitempty"`
+						NfmReply struct {
+							Name         string `json:"name"`
+							Body         string `json:"body"`
+							ResponseJSON string `json:"response_json"` // Submitted Flow fields plus flow_token
+						} `json:"nfm_reply,omitempty"`
 					} `json:"interactive,omitempty"`
+					Location struct {
+						Latitude  float64 `json:"latitude"`
+						Longitude float64 `json:"longitude"`
+						Name      string  `json:"name"`
+						Address   string  `json:"address"`
+					} `json:"location,omitempty"` // Shared location pin
 				} `json:"messages"`
 			} `json:"value"`
 			Field string `json:"field"`
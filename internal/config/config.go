@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -15,6 +18,15 @@ type Config struct {
 	AppPort string `envconfig:"APP_PORT" default:"8080"`
 	AppEnv  string `envconfig:"APP_ENV" default:"development"`
 
+	// Readiness probe (/health/ready): per-dependency timeout, and whether to also call the WhatsApp Graph API
+	HealthCheckTimeout  time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+	HealthCheckWhatsApp bool          `envconfig:"HEALTH_CHECK_WHATSAPP" default:"false"`
+
+	// Error reporting: errors from background goroutines and recovered panics go to Sentry when a DSN is set.
+	// Events are tagged with APP_ENV and the release (defaults to Railway's deployed commit).
+	SentryDSN  string `envconfig:"SENTRY_DSN"`
+	AppRelease string `envconfig:"APP_RELEASE"`
+
 	// Database
 	DBHost     string `envconfig:"DB_HOST" default:"localhost"`
 	DBPort     string `envconfig:"DB_PORT" default:"5432"`
@@ -23,21 +35,184 @@ type Config struct {
 	DBName     string `envconfig:"DB_NAME" default:"destination_cocktails"`
 	DBURL      string `envconfig:"DB_URL"`
 
+	// Connection pool per database (0 keeps the database/sql default). With DB_READ_REPLICA_URL set,
+	// analytics and report queries go to the replica instead of the primary.
+	DBMaxOpenConns    int           `envconfig:"DB_MAX_OPEN_CONNS" default:"25"`
+	DBMaxIdleConns    int           `envconfig:"DB_MAX_IDLE_CONNS" default:"10"`
+	DBConnMaxLifetime time.Duration `envconfig:"DB_CONN_MAX_LIFETIME" default:"30m"`
+	DBConnMaxIdleTime time.Duration `envconfig:"DB_CONN_MAX_IDLE_TIME" default:"5m"`
+	DBReadReplicaURL  string        `envconfig:"DB_READ_REPLICA_URL"`
+
 	// Redis
 	RedisURL      string `envconfig:"REDIS_URL" default:"redis://localhost:6379"`
 	RedisPassword string `envconfig:"REDIS_PASSWORD" default:""`
 
+	// Bot sessions: lifetime after the last save, and whether every read restarts it
+	SessionTTL        time.Duration `envconfig:"SESSION_TTL" default:"2h"`
+	SessionSlidingTTL bool          `envconfig:"SESSION_SLIDING_TTL" default:"true"`
+
+	// Per-phone Redis lock around bot message processing: held at most SessionLockTTL (0 disables),
+	// and a message waits up to SessionLockWait for its phone's previous message
+	SessionLockTTL  time.Duration `envconfig:"SESSION_LOCK_TTL" default:"30s"`
+	SessionLockWait time.Duration `envconfig:"SESSION_LOCK_WAIT" default:"10s"`
+
+	// Abandoned cart reminders: one WhatsApp nudge after CartReminderIdle, at most once per CartReminderCap
+	CartReminderEnabled bool          `envconfig:"CART_REMINDER_ENABLED" default:"true"`
+	CartReminderIdle    time.Duration `envconfig:"CART_REMINDER_IDLE" default:"30m"`
+	CartReminderCap     time.Duration `envconfig:"CART_REMINDER_CAP" default:"24h"`
+
+	// Unpaid orders: a PENDING order with nothing paid PENDING_ORDER_EXPIRY after checkout is cancelled,
+	// the customer is told and the items go back in their cart (0 disables). Keep it well beyond the
+	// M-Pesa prompt's lifetime so a slow payment isn't cancelled under the customer.
+	PendingOrderExpiry time.Duration `envconfig:"PENDING_ORDER_EXPIRY" default:"30m"`
+
+	// Event bus backend for dashboard SSE: memory (single instance) or redis (multi-replica)
+	EventBusBackend string `envconfig:"EVENT_BUS_BACKEND" default:"memory"`
+	EventBusChannel string `envconfig:"EVENT_BUS_CHANNEL" default:"dashboard:events"`
+	// Recent events kept per type for SSE clients reconnecting with Last-Event-ID
+	EventBusReplaySize int `envconfig:"EVENT_BUS_REPLAY_SIZE" default:"100"`
+	// Events buffered per SSE/WebSocket client, and how long a client's buffer may stay full before it's disconnected
+	EventBusSubscriberBuffer int           `envconfig:"EVENT_BUS_SUBSCRIBER_BUFFER" default:"64"`
+	EventBusStallTimeout     time.Duration `envconfig:"EVENT_BUS_STALL_TIMEOUT" default:"1m"`
+
+	// Menu and product search results are cached in memory for this long (0 disables the cache);
+	// product, stock and price change events drop the cache on every replica sooner
+	ProductCacheTTL time.Duration `envconfig:"PRODUCT_CACHE_TTL" default:"30s"`
+
 	// WhatsApp
 	WhatsAppToken         string `envconfig:"WHATSAPP_TOKEN"`
 	WhatsAppPhoneNumberID string `envconfig:"WHATSAPP_PHONE_NUMBER_ID"`
 	WhatsAppVerifyToken   string `envconfig:"WHATSAPP_VERIFY_TOKEN"`
+	WhatsAppAppSecret     string `envconfig:"WHATSAPP_APP_SECRET"` // Meta app secret; enables X-Hub-Signature-256 verification
+
+	// Inbound message IDs are remembered in Redis this long so messages Meta redelivers are handled once (0 disables)
+	WhatsAppDedupTTL time.Duration `envconfig:"WHATSAPP_DEDUP_TTL" default:"24h"`
+
+	// WhatsApp outbound pacing and retry queue (transient 429/5xx failures are retried from Redis)
+	WhatsAppRateLimit        int  `envconfig:"WHATSAPP_RATE_LIMIT" default:"20"` // Cloud API requests per second
+	WhatsAppRetryEnabled     bool `envconfig:"WHATSAPP_RETRY_ENABLED" default:"true"`
+	WhatsAppRetryMaxAttempts int  `envconfig:"WHATSAPP_RETRY_MAX_ATTEMPTS" default:"6"` // Then the message is dead-lettered
+	WhatsAppSendReceipts     bool `envconfig:"WHATSAPP_SEND_RECEIPTS" default:"true"`   // PDF receipt after payment confirmation
+
+	// Published WhatsApp Flow (internal/adapters/whatsapp/flows/checkout.json) asking quantity, table and M-Pesa number in one form.
+	// Empty keeps the one-question-per-message checkout.
+	WhatsAppCheckoutFlowID string `envconfig:"WHATSAPP_CHECKOUT_FLOW_ID"`
+
+	// SMS fallback (Africa's Talking; the "sandbox" username uses the sandbox API). Route for payment
+	// confirmations: whatsapp (never SMS), fallback (SMS when WhatsApp rejects the message) or sms (SMS only).
+	// Without SMS_PROVIDER everything goes over WhatsApp.
+	SMSProvider            string `envconfig:"SMS_PROVIDER"` // africastalking, or empty to disable SMS
+	AfricasTalkingUsername string `envconfig:"AFRICASTALKING_USERNAME"`
+	AfricasTalkingAPIKey   string `envconfig:"AFRICASTALKING_API_KEY"`
+	AfricasTalkingSenderID string `envconfig:"AFRICASTALKING_SENDER_ID"` // Optional registered sender ID
+	SMSPaymentRoute        string `envconfig:"SMS_PAYMENT_ROUTE" default:"fallback"`
+
+	// Optional LLM fallback for free-text customer messages the built-in parser can't place: an
+	// OpenAI-compatible chat completions API maps them to browse/add/cart/checkout actions
+	NLUProvider string        `envconfig:"NLU_PROVIDER"` // openai, or empty to disable
+	NLUAPIKey   string        `envconfig:"NLU_API_KEY"`
+	NLUBaseURL  string        `envconfig:"NLU_BASE_URL" default:"https://api.openai.com/v1"`
+	NLUModel    string        `envconfig:"NLU_MODEL" default:"gpt-4o-mini"`
+	NLUTimeout  time.Duration `envconfig:"NLU_TIMEOUT" default:"4s"` // The bot falls back to the state machine after this
+
+	// Google Sheets sync: once a business day closes, its summary row (date, revenue, orders, average order
+	// value, top product) is appended to the sheet as a service account the spreadsheet is shared with
+	GoogleSheetsSpreadsheetID string `envconfig:"GOOGLE_SHEETS_SPREADSHEET_ID"` // Empty disables the sync
+	GoogleSheetsSheetName     string `envconfig:"GOOGLE_SHEETS_SHEET_NAME" default:"Sheet1"`
+	GoogleSheetsCredentials   string `envconfig:"GOOGLE_SHEETS_CREDENTIALS"` // The service account's JSON key, as one line
+
+	// Dashboard login codes: whatsapp, sms, fallback (WhatsApp, then SMS) or console (logged only; needs
+	// APP_ENV=development). A new code can be resent after OTP_RESEND_COOLDOWN.
+	OTPChannel        string        `envconfig:"OTP_CHANNEL" default:"fallback"`
+	OTPResendCooldown time.Duration `envconfig:"OTP_RESEND_COOLDOWN" default:"60s"`
 
 	// Bar Staff
-	BarStaffPhone string `envconfig:"BAR_STAFF_PHONE" default:"254735537873"` // Phone number for bar staff notifications
+	BarStaffPhone      string `envconfig:"BAR_STAFF_PHONE" default:"254735537873"`    // Fallback phone when no bartender is on shift
+	BarStaffNotifyMode string `envconfig:"BAR_STAFF_NOTIFY_MODE" default:"broadcast"` // broadcast (all on-shift) or round_robin
+
+	// Pickup reminders: re-ping customers whose order is still READY, then alert bar staff
+	PickupReminderEnabled bool            `envconfig:"PICKUP_REMINDER_ENABLED" default:"true"`
+	PickupReminderAfter   []time.Duration `envconfig:"PICKUP_REMINDER_AFTER" default:"10m,20m"`
+	PickupEscalationAfter time.Duration   `envconfig:"PICKUP_ESCALATION_AFTER" default:"30m"`
+
+	// Preparation SLA: warn the dashboard when an order sits PAID longer than this; 0 disables the warning
+	PrepSLA time.Duration `envconfig:"PREP_SLA" default:"15m"`
+
+	// Pickup codes: numeric or alphanumeric, 4-8 characters
+	PickupCodeFormat string `envconfig:"PICKUP_CODE_FORMAT" default:"numeric"`
+	PickupCodeLength int    `envconfig:"PICKUP_CODE_LENGTH" default:"4"`
+
+	// VAT: rate in percent (0 disables) and whether menu prices already include it
+	VATRate            float64 `envconfig:"VAT_RATE" default:"16"`
+	VATPricesInclusive bool    `envconfig:"VAT_PRICES_INCLUSIVE" default:"true"`
+
+	// Tips: ask for an optional tip (none, 5%, 10% or a custom amount) before the STK push
+	TipsEnabled bool `envconfig:"TIPS_ENABLED" default:"true"`
+
+	// Order notes: ask for optional special instructions ("no ice") before the tip and payment prompts
+	OrderNotesEnabled bool `envconfig:"ORDER_NOTES_ENABLED" default:"true"`
+
+	// Group tabs: customers at one table share a tab (join code), then pay it whole or share by share
+	TabsEnabled bool `envconfig:"TABS_ENABLED" default:"true"`
+
+	// Age gate: new customers confirm they're 18 or older before the bot takes orders; the confirmation is
+	// stored on the user and copied onto each order for compliance exports
+	AgeGateEnabled bool `envconfig:"AGE_GATE_ENABLED" default:"true"`
+
+	// Favorites: offer "Save as favorite" after payment; "favorites" lists saved baskets to reorder in one tap
+	FavoritesEnabled bool `envconfig:"FAVORITES_ENABLED" default:"true"`
+
+	// Reservations: "book" reserves a table or VIP booth; a non-zero deposit is charged by STK push and
+	// bookings unpaid after 30 minutes are cancelled. Reminders go out at RESERVATION_REMINDER_HOUR on the day.
+	ReservationsEnabled     bool    `envconfig:"RESERVATIONS_ENABLED" default:"true"`
+	ReservationTableDeposit float64 `envconfig:"RESERVATION_TABLE_DEPOSIT" default:"0"`
+	ReservationVIPDeposit   float64 `envconfig:"RESERVATION_VIP_DEPOSIT" default:"5000"`
+	ReservationMaxParty     int     `envconfig:"RESERVATION_MAX_PARTY" default:"20"`
+	ReservationMaxDaysAhead int     `envconfig:"RESERVATION_MAX_DAYS_AHEAD" default:"30"`
+	ReservationReminderHour int     `envconfig:"RESERVATION_REMINDER_HOUR" default:"12"`
+
+	// Pre-orders: checkout asks "now or later?"; a paid pre-order waits as SCHEDULED and goes to the bar
+	// SCHEDULED_ORDER_LEAD_TIME before the chosen time, which may be up to SCHEDULED_ORDER_MAX_AHEAD away
+	ScheduledOrdersEnabled bool          `envconfig:"SCHEDULED_ORDERS_ENABLED" default:"true"`
+	ScheduledOrderLeadTime time.Duration `envconfig:"SCHEDULED_ORDER_LEAD_TIME" default:"15m"`
+	ScheduledOrderMaxAhead time.Duration `envconfig:"SCHEDULED_ORDER_MAX_AHEAD" default:"12h"`
+
+	// Delivery: checkout asks "pickup or delivery?" (orders not placed from a table), takes an address or
+	// location pin and adds DELIVERY_FEE; dispatched orders are offered to available riders on the roster
+	DeliveryEnabled bool    `envconfig:"DELIVERY_ENABLED" default:"false"`
+	DeliveryFee     float64 `envconfig:"DELIVERY_FEE" default:"200"`
+
+	// Pay at the bar: offer cash or card at the counter alongside M-Pesa; staff confirm the payment
+	PayAtBarEnabled bool `envconfig:"PAY_AT_BAR_ENABLED" default:"true"`
+
+	// Customer blocklist: blocked phones get a polite decline (or silence), and phones with
+	// BLOCKLIST_FLAG_FAILED_PAYMENTS failed payments within BLOCKLIST_FLAG_WINDOW are flagged for review (0 disables)
+	BlockedCustomerReply        string        `envconfig:"BLOCKED_CUSTOMER_REPLY" default:"decline"` // decline or silent
+	BlocklistFlagFailedPayments int           `envconfig:"BLOCKLIST_FLAG_FAILED_PAYMENTS" default:"3"`
+	BlocklistFlagWindow         time.Duration `envconfig:"BLOCKLIST_FLAG_WINDOW" default:"24h"`
+
+	// Marketing broadcasts: campaign messages to opted-in customers go out at most this many per minute per replica
+	BroadcastEnabled       bool `envconfig:"BROADCAST_ENABLED" default:"true"`
+	BroadcastRatePerMinute int  `envconfig:"BROADCAST_RATE_PER_MINUTE" default:"60"`
+
+	// Feedback: customers are asked for a 1-5 rating FEEDBACK_REQUEST_DELAY after their order is completed;
+	// ratings at or below FEEDBACK_ALERT_MAX_RATING are sent to managers on WhatsApp
+	FeedbackEnabled        bool          `envconfig:"FEEDBACK_ENABLED" default:"true"`
+	FeedbackRequestDelay   time.Duration `envconfig:"FEEDBACK_REQUEST_DELAY" default:"10m"`
+	FeedbackAlertMaxRating int           `envconfig:"FEEDBACK_ALERT_MAX_RATING" default:"2"`
 
 	// Dashboard
 	JWTSecret     string `envconfig:"JWT_SECRET" default:"change-this-secret-in-production"`
-	AllowedOrigin string `envconfig:"ALLOWED_ORIGIN" default:"https://destination-dashboard-production.up.railway.app"`
+	AllowedOrigin string `envconfig:"ALLOWED_ORIGIN" default:"https://destination-dashboard-production.up.railway.app"` // Used when CORS_ALLOWED_ORIGINS is unset
+	// Comma-separated dashboard origins, e.g. "https://bar.example.com,https://*.example.com" ("*" allows any, without cookies)
+	CORSAllowedOrigins []string `envconfig:"CORS_ALLOWED_ORIGINS"`
+
+	// Dashboard sessions: short-lived access JWTs renewed with single-use refresh tokens stored in Redis
+	JWTAccessTTL  time.Duration `envconfig:"JWT_ACCESS_TTL" default:"15m"`
+	JWTRefreshTTL time.Duration `envconfig:"JWT_REFRESH_TTL" default:"168h"`
+
+	// How long a POST/PATCH response is kept for replay when the dashboard retries with the same Idempotency-Key
+	IdempotencyKeyTTL time.Duration `envconfig:"IDEMPOTENCY_KEY_TTL" default:"24h"`
 
 	// Kopo Kopo (use Client ID + Secret for OAuth; or set Access Token for sandbox manual token)
 	KopoKopoClientID      string `envconfig:"KOPOKOPO_CLIENT_ID"`
@@ -48,10 +223,22 @@ type Config struct {
 	KopoKopoAccessToken   string `envconfig:"KOPOKOPO_ACCESS_TOKEN"` // Optional: manual token (e.g. sandbox); else we use Client ID/Secret OAuth
 	KopoKopoCallbackURL   string `envconfig:"KOPOKOPO_CALLBACK_URL"` // Full callback URL (e.g., https://your-app.railway.app/api/webhooks/payment)
 
+	// STK push queue: persisted in Redis so queued pushes survive restarts; failures retry, then dead-letter
+	STKQueuePersistent  bool          `envconfig:"STK_QUEUE_PERSISTENT" default:"true"`
+	STKQueueMaxAttempts int           `envconfig:"STK_QUEUE_MAX_ATTEMPTS" default:"3"`
+	STKQueueVisibility  time.Duration `envconfig:"STK_QUEUE_VISIBILITY_TIMEOUT" default:"60s"` // A claimed push not finished by then is retried
+
+	// Outbox: paid-order confirmations, bar staff messages and SSE events are stored with the payment and
+	// delivered by a poller; failed deliveries retry with backoff, then the message is marked FAILED
+	OutboxPollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL" default:"2s"`
+	OutboxMaxAttempts  int           `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"8"`
+
 	// Pesapal
 	PesapalClientID     string `envconfig:"PESAPAL_CLIENT_ID"`
 	PesapalClientSecret string `envconfig:"PESAPAL_CLIENT_SECRET"`
 	PesapalEnvironment  string `envconfig:"PESAPAL_ENVIRONMENT" default:"sandbox"`
+
+	appEnvSet bool // APP_ENV was set explicitly rather than left at its default
 }
 
 var instance *Config
@@ -73,6 +260,7 @@ func Load() (*Config, error) {
 	if err := envconfig.Process("", cfg); err != nil {
 		return nil, fmt.Errorf("error processing environment variables: %w", err)
 	}
+	_, cfg.appEnvSet = os.LookupEnv("APP_ENV")
 
 	// Check for Railway's DATABASE_URL if DB_URL is not set
 	if cfg.DBURL == "" {
@@ -81,16 +269,33 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// Tag error reports with the deployed commit on Railway unless a release is set
+	if cfg.AppRelease == "" {
+		cfg.AppRelease = os.Getenv("RAILWAY_GIT_COMMIT_SHA")
+	}
+
 	// Build DBURL if still not provided
 	if cfg.DBURL == "" {
 		cfg.DBURL = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 			cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
 	}
 
+	origins, err := parseCORSOrigins(cfg.CORSAllowedOrigins, cfg.AllowedOrigin)
+	if err != nil {
+		return nil, err
+	}
+	cfg.CORSAllowedOrigins = origins
+
 	instance = cfg
 	return instance, nil
 }
 
+// IsDevelopment reports whether APP_ENV is explicitly set to development. The default value doesn't
+// count, so development-only shortcuts (the test admin's fixed OTP, console OTPs) stay off unless asked for.
+func (c *Config) IsDevelopment() bool {
+	return c.appEnvSet && strings.EqualFold(c.AppEnv, "development")
+}
+
 // Get returns the singleton Config instance (must call Load first)
 func Get() *Config {
 	if instance == nil {
@@ -98,3 +303,35 @@ func Get() *Config {
 	}
 	return instance
 }
+
+// parseCORSOrigins normalizes CORS_ALLOWED_ORIGINS (falling back to ALLOWED_ORIGIN) to lowercase
+// scheme://host[:port] entries. A "*." host prefix allows any subdomain; "*" alone allows every origin.
+func parseCORSOrigins(origins []string, fallback string) ([]string, error) {
+	if len(origins) == 0 && strings.TrimSpace(fallback) != "" {
+		origins = strings.Split(fallback, ",")
+	}
+
+	normalized := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			return []string{"*"}, nil
+		}
+
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.Path != "" || parsed.RawQuery != "" || parsed.User != nil ||
+			strings.Contains(strings.TrimPrefix(parsed.Host, "*."), "*") {
+			return nil, fmt.Errorf("invalid CORS origin %q: expected scheme://host[:port], optionally with a *. subdomain wildcard", origin)
+		}
+		normalized = append(normalized, parsed.Scheme+"://"+parsed.Host)
+	}
+
+	if len(normalized) == 0 {
+		return []string{"*"}, nil
+	}
+	return normalized, nil
+}
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SystemClock is the production Clock backed by time.Now
+type SystemClock struct{}
+
+// Now returns the current wall-clock time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// UUIDGenerator is the production IDGenerator producing random UUIDv4 strings
+type UUIDGenerator struct{}
+
+// NewID returns a new random UUID
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
@@ -1,60 +1,483 @@
 package core
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
 
 // Product represents a menu item (drink/food) in the system
 type Product struct {
-	ID            string  `json:"id"`
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	Price         float64 `json:"price"`
-	Category      string  `json:"category"`
-	StockQuantity int     `json:"stock_quantity"`
-	ImageURL      string  `json:"image_url"`
-	IsActive      bool    `json:"is_active"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Price         float64    `json:"price"`
+	Category      string     `json:"category"`
+	StockQuantity int        `json:"stock_quantity"`
+	ImageURL      string     `json:"image_url"`
+	IsActive      bool       `json:"is_active"`
+	ArchivedAt    *time.Time `json:"archived_at,omitempty"` // Archived products leave the menu but stay joinable for order history
+	BottleML      int        `json:"bottle_ml,omitempty"`   // Size of one stock unit, for ingredients measured in ml
+	PouredML      float64    `json:"poured_ml,omitempty"`   // Poured so far from the open bottle
+	CostPrice     float64    `json:"cost_price,omitempty"`  // Cost per stock unit, set by a manager or the last purchase order received; zero when unknown
+	// HideWhenSoldOut keeps the product off the bot's menu and search while it has no stock; nil follows
+	// the menu.hide_out_of_stock setting
+	HideWhenSoldOut *bool `json:"hide_when_sold_out,omitempty"`
+}
+
+// ProductOption is one serving choice for a product, e.g. group "Size" with label "Double"
+type ProductOption struct {
+	ID         string    `json:"id"`
+	ProductID  string    `json:"product_id"`
+	GroupName  string    `json:"group_name"` // Options in the same group are mutually exclusive (Size, Ice, Mixer)
+	Label      string    `json:"label"`      // Shown as a WhatsApp button, max 20 characters
+	PriceDelta float64   `json:"price_delta"`
+	SortOrder  int       `json:"sort_order"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderModifier is a serving option chosen for a cart or order item
+type OrderModifier struct {
+	Group      string  `json:"group"`
+	Label      string  `json:"label"`
+	PriceDelta float64 `json:"price_delta,omitempty"`
+}
+
+// FormatModifiers joins modifier labels for display, e.g. "Double, No ice"
+func FormatModifiers(modifiers []OrderModifier) string {
+	labels := make([]string, len(modifiers))
+	for i, modifier := range modifiers {
+		labels[i] = modifier.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// BundleCategory is the menu category combo products are listed under
+const BundleCategory = "Combos"
+
+// BundleComponent is one product inside a combo and how many of it each combo uses
+type BundleComponent struct {
+	ProductID     string `json:"product_id"`
+	ProductName   string `json:"product_name"`
+	Quantity      int    `json:"quantity"`
+	StockQuantity int    `json:"stock_quantity,omitempty"` // Live component stock; not kept on order item snapshots
+}
+
+// Bundle is a combo product (e.g. "Gin + 2 Tonics") sold at its own price
+type Bundle struct {
+	Product    Product           `json:"product"`
+	Components []BundleComponent `json:"components"`
+	Available  int               `json:"available"` // Combos that can be made from current component stock
+}
+
+// BundleAvailability returns how many combos current component stock can make
+func BundleAvailability(components []BundleComponent) int {
+	if len(components) == 0 {
+		return 0
+	}
+
+	available := -1
+	for _, component := range components {
+		if component.Quantity <= 0 {
+			continue
+		}
+		count := component.StockQuantity / component.Quantity
+		if available < 0 || count < available {
+			available = count
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// RecipeUnit is how a recipe measures an ingredient
+type RecipeUnit string
+
+const (
+	RecipeUnitML    RecipeUnit = "ml"    // Poured from bottles of the ingredient's BottleML
+	RecipeUnitCount RecipeUnit = "count" // Whole stock units, e.g. a can of tonic or a lime
+)
+
+// RecipeIngredient is one product poured into a cocktail and how much of it each cocktail uses
+type RecipeIngredient struct {
+	ProductID     string     `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	Measure       float64    `json:"measure"`
+	Unit          RecipeUnit `json:"unit"`
+	StockQuantity int        `json:"stock_quantity"`
+	BottleML      int        `json:"bottle_ml,omitempty"`
+	PouredML      float64    `json:"poured_ml,omitempty"`
+}
+
+// Servings returns how many measures of the ingredient are left in stock
+func (i RecipeIngredient) Servings() int {
+	if i.Measure <= 0 {
+		return 0
+	}
+	switch i.Unit {
+	case RecipeUnitML:
+		if i.BottleML <= 0 {
+			return 0
+		}
+		left := float64(i.StockQuantity*i.BottleML) - i.PouredML
+		if left <= 0 {
+			return 0
+		}
+		return int(math.Floor(left/i.Measure + 1e-9))
+	default:
+		if i.StockQuantity <= 0 {
+			return 0
+		}
+		return int(math.Floor(float64(i.StockQuantity)/i.Measure + 1e-9))
+	}
+}
+
+// Recipe is a cocktail product and the bottle stock it's made from
+type Recipe struct {
+	Product     Product            `json:"product"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	Available   int                `json:"available"` // Cocktails that can be made from current ingredient stock
+}
+
+// RecipeAvailability returns how many cocktails current ingredient stock can make
+func RecipeAvailability(ingredients []RecipeIngredient) int {
+	if len(ingredients) == 0 {
+		return 0
+	}
+
+	available := -1
+	for _, ingredient := range ingredients {
+		if servings := ingredient.Servings(); available < 0 || servings < available {
+			available = servings
+		}
+	}
+	return available
+}
+
+// PourStock takes amount of an ingredient out of stock. Measures in ml come out of the open
+// bottle, and each bottle emptied takes one off the stock count; stock never goes below zero.
+func PourStock(stock int, bottleML int, poured float64, unit RecipeUnit, amount float64) (int, float64) {
+	switch unit {
+	case RecipeUnitML:
+		if bottleML <= 0 {
+			return stock, poured
+		}
+		poured += amount
+		emptied := int(math.Floor(poured / float64(bottleML)))
+		stock -= emptied
+		poured = math.Round((poured-float64(emptied*bottleML))*100) / 100
+	default:
+		stock -= int(math.Ceil(amount - 1e-9))
+	}
+
+	if stock <= 0 {
+		return 0, 0
+	}
+	return stock, poured
+}
+
+// StocktakeStatus represents where a stocktake is in the count → apply workflow
+type StocktakeStatus string
+
+const (
+	StocktakeOpen      StocktakeStatus = "OPEN"    // Lines are being counted
+	StocktakeApplied   StocktakeStatus = "APPLIED" // Variances have been applied to stock
+	StocktakeCancelled StocktakeStatus = "CANCELLED"
+)
+
+// stock_adjustments reasons
+const (
+	StockAdjustmentStocktake      = "stocktake"       // Counted variance applied by a stocktake
+	StockAdjustmentPurchaseOrder  = "purchase_order"  // Supplier delivery received
+	StockAdjustmentCorrection     = "correction"      // Stock set to an absolute number from the dashboard
+	StockAdjustmentSaleCorrection = "sale_correction" // A sale recorded wrongly, e.g. rung up twice or never
+	StockAdjustmentBreakage       = "breakage"
+	StockAdjustmentRestock        = "restock" // Stock received without a purchase order
+	StockAdjustmentTheft          = "theft"
+	StockAdjustmentRecipe         = "recipe" // Ingredients poured for a paid order's cocktails
+)
+
+// Stocktake is a physical count of products compared against system stock
+type Stocktake struct {
+	ID        string          `json:"id"`
+	Status    StocktakeStatus `json:"status"`
+	Note      string          `json:"note,omitempty"`
+	StartedBy string          `json:"started_by"` // Admin user ID
+	AppliedBy string          `json:"applied_by,omitempty"`
+	Lines     []StocktakeLine `json:"lines,omitempty"` // Only when a single stocktake is fetched
+	CreatedAt time.Time       `json:"created_at"`
+	AppliedAt *time.Time      `json:"applied_at,omitempty"`
+}
+
+// StocktakeLine is one product's counted quantity against the system stock when it was counted
+type StocktakeLine struct {
+	ProductID       string    `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	Category        string    `json:"category"`
+	UnitPrice       float64   `json:"unit_price"` // Current menu price, for valuing the variance
+	SystemQuantity  int       `json:"system_quantity"`
+	CountedQuantity int       `json:"counted_quantity"`
+	Variance        int       `json:"variance"` // Counted minus system; negative is shrinkage
+	CountedBy       string    `json:"counted_by"`
+	CountedAt       time.Time `json:"counted_at"`
+}
+
+// StockAdjustment is one audited stock change
+type StockAdjustment struct {
+	ProductID   string `json:"product_id"`
+	OldQuantity int    `json:"old_quantity"`
+	NewQuantity int    `json:"new_quantity"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Sources of a price change
+const (
+	PriceChangeManual = "manual"
+	PriceChangeImport = "import"
+)
+
+// PriceChange is one entry in a product's price audit trail
+type PriceChange struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	OldPrice  float64   `json:"old_price"`
+	NewPrice  float64   `json:"new_price"`
+	Actor     string    `json:"actor"`                // Admin user ID
+	ActorName string    `json:"actor_name,omitempty"` // Resolved from admin_users
+	Source    string    `json:"source"`               // manual or import
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StocktakeVariance summarises a stocktake's variances for shrinkage analysis, valued at menu price
+type StocktakeVariance struct {
+	Stocktake    *Stocktake      `json:"stocktake"`
+	Lines        []StocktakeLine `json:"lines"` // Counted lines with a variance, largest loss first
+	LinesCounted int             `json:"lines_counted"`
+	UnitsShort   int             `json:"units_short"`
+	UnitsOver    int             `json:"units_over"`
+	ValueShort   float64         `json:"value_short"`
+	ValueOver    float64         `json:"value_over"`
+	NetValue     float64         `json:"net_value"` // ValueOver minus ValueShort
+}
+
+// TaxPolicy describes how VAT applies to menu prices
+type TaxPolicy struct {
+	Rate      float64 `json:"rate"`      // Percent, e.g. 16 for Kenya's standard rate; zero disables VAT
+	Inclusive bool    `json:"inclusive"` // Menu prices already include VAT
+}
+
+// LineTax returns the VAT portion of an amount at menu prices, rounded to cents
+func (p TaxPolicy) LineTax(amount float64) float64 {
+	if p.Rate <= 0 {
+		return 0
+	}
+	if p.Inclusive {
+		return roundCents(amount * p.Rate / (100 + p.Rate))
+	}
+	return roundCents(amount * p.Rate / 100)
+}
+
+// OrderTotals returns the VAT on a menu-price subtotal and the amount the customer pays.
+// When VAT is added on top, the total is rounded to whole shillings (M-Pesa only
+// charges whole amounts) and the VAT absorbs the rounding.
+func (p TaxPolicy) OrderTotals(subtotal float64) (tax float64, total float64) {
+	if p.Rate <= 0 {
+		return 0, subtotal
+	}
+	if p.Inclusive {
+		return p.LineTax(subtotal), subtotal
+	}
+	total = math.Round(subtotal * (100 + p.Rate) / 100)
+	return roundCents(total - subtotal), total
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
 }
 
 // Order represents a customer order
 type Order struct {
-	ID                string      `json:"id"`
-	UserID            string      `json:"user_id"`        // FK to users.id
-	CustomerPhone     string      `json:"customer_phone"` // Denormalized for performance
-	TableNumber       string      `json:"table_number"`
-	TotalAmount       float64     `json:"total_amount"`
-	Status            OrderStatus `json:"status"`
-	PaymentMethod     string      `json:"payment_method"`
-	PaymentRef        string      `json:"payment_reference"`
-	PickupCode        string      `json:"pickup_code"` // 4-digit code for bar staff
-	ReadyAt           *time.Time  `json:"ready_at,omitempty"`
-	ReadyByUserID     string      `json:"ready_by_user_id,omitempty"`
-	CompletedAt       *time.Time  `json:"completed_at,omitempty"`
-	CompletedByUserID string      `json:"completed_by_user_id,omitempty"`
-	Items             []OrderItem `json:"items"`
-	CreatedAt         time.Time   `json:"created_at"`
+	ID                string          `json:"id"`
+	UserID            string          `json:"user_id"`        // FK to users.id
+	CustomerPhone     string          `json:"customer_phone"` // Denormalized for performance
+	TableNumber       string          `json:"table_number"`
+	TotalAmount       float64         `json:"total_amount"`
+	TaxAmount         float64         `json:"tax_amount"`                  // VAT included in TotalAmount
+	TaxRate           float64         `json:"tax_rate"`                    // VAT percent in force when the order was placed
+	TipAmount         float64         `json:"tip_amount"`                  // Included in TotalAmount; carries no VAT and isn't product revenue
+	Notes             string          `json:"notes,omitempty"`             // Customer's special instructions for the bar
+	ScheduledFor      *time.Time      `json:"scheduled_for,omitempty"`     // Pre-order: when the customer wants it ready; nil for straight away
+	DeliveryAddress   string          `json:"delivery_address,omitempty"`  // Set for delivery orders; empty for pickup at the bar
+	DeliveryLocation  *GeoPoint       `json:"delivery_location,omitempty"` // Location pin the customer shared, if any
+	DeliveryFee       float64         `json:"delivery_fee"`                // Included in TotalAmount; like TipAmount, no VAT and not product revenue
+	Status            OrderStatus     `json:"status"`
+	PaymentMethod     string          `json:"payment_method"`
+	PaymentRef        string          `json:"payment_reference"`
+	PickupCode        string          `json:"pickup_code"` // 4-digit code for bar staff
+	ReadyAt           *time.Time      `json:"ready_at,omitempty"`
+	ReadyByUserID     string          `json:"ready_by_user_id,omitempty"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty"`
+	CompletedByUserID string          `json:"completed_by_user_id,omitempty"`
+	AcceptedByStaffID string          `json:"accepted_by_staff_id,omitempty"`
+	AcceptedAt        *time.Time      `json:"accepted_at,omitempty"`
+	RiderID           string          `json:"rider_id,omitempty"` // Rider who accepted the delivery
+	RiderAssignedAt   *time.Time      `json:"rider_assigned_at,omitempty"`
+	ReadyReminders    int             `json:"ready_reminders_sent,omitempty"` // Pickup reminders sent while READY
+	PickupEscalatedAt *time.Time      `json:"pickup_escalated_at,omitempty"`  // Flagged to bar staff as uncollected
+	PrepSLAAlertedAt  *time.Time      `json:"prep_sla_alerted_at,omitempty"`  // Flagged for waiting PAID past the preparation SLA
+	AmountPaid        float64         `json:"amount_paid"`                    // Sum of confirmed payments; PAID once it covers TotalAmount
+	AgeConfirmedAt    *time.Time      `json:"age_confirmed_at,omitempty"`     // Customer's 18+ confirmation in force when the order was placed
+	Items             []OrderItem     `json:"items"`
+	PaymentShares     []*PaymentShare `json:"payment_shares,omitempty"` // Split bill shares created with the order; loaded for order detail
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+// IsDelivery reports whether the order goes out to the customer instead of being collected at the bar
+func (o *Order) IsDelivery() bool {
+	return o.DeliveryAddress != ""
+}
+
+// GeoPoint is a latitude/longitude pair, e.g. from a WhatsApp location message
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// AmountDue is what is still owed on the order
+func (o *Order) AmountDue() float64 {
+	if due := o.TotalAmount - o.AmountPaid; due > 0 {
+		return due
+	}
+	return 0
 }
 
 // OrderItem represents a single item in an order
 type OrderItem struct {
-	ID          string  `json:"id"`
-	OrderID     string  `json:"order_id"`
-	ProductID   string  `json:"product_id"`
-	Quantity    int     `json:"quantity"`
-	PriceAtTime float64 `json:"price_at_time"`
-	ProductName string  `json:"product_name" gorm:"-"` // Not stored in DB, populated via JOIN
+	ID          string            `json:"id"`
+	OrderID     string            `json:"order_id"`
+	ProductID   string            `json:"product_id"`
+	Quantity    int               `json:"quantity"`
+	PriceAtTime float64           `json:"price_at_time"` // Unit price including modifier price deltas
+	TaxAmount   float64           `json:"tax_amount"`    // VAT on the whole line (price x quantity)
+	Modifiers   []OrderModifier   `json:"modifiers,omitempty"`
+	Components  []BundleComponent `json:"components,omitempty"`  // Per-unit contents when the product is a combo
+	ProductName string            `json:"product_name" gorm:"-"` // Not stored in DB, populated via JOIN
 }
 
 // OrderStatus represents the state of an order
 type OrderStatus string
 
 const (
-	OrderStatusPending   OrderStatus = "PENDING"
-	OrderStatusPaid      OrderStatus = "PAID"
-	OrderStatusFailed    OrderStatus = "FAILED"
-	OrderStatusReady     OrderStatus = "READY"
-	OrderStatusCompleted OrderStatus = "COMPLETED"
-	OrderStatusCancelled OrderStatus = "CANCELLED"
+	OrderStatusPending        OrderStatus = "PENDING"
+	OrderStatusPartiallyPaid  OrderStatus = "PARTIALLY_PAID" // Some payments confirmed, not yet the full total
+	OrderStatusAwaitingCash   OrderStatus = "AWAITING_CASH"  // Pay at the bar: waiting for staff to confirm cash or card
+	OrderStatusPaid           OrderStatus = "PAID"
+	OrderStatusScheduled      OrderStatus = "SCHEDULED" // Paid pre-order held until shortly before ScheduledFor, then released to the bar as PAID
+	OrderStatusFailed         OrderStatus = "FAILED"
+	OrderStatusReady          OrderStatus = "READY"
+	OrderStatusCompleted      OrderStatus = "COMPLETED"
+	OrderStatusOutForDelivery OrderStatus = "OUT_FOR_DELIVERY" // Delivery order handed to the rider
+	OrderStatusDelivered      OrderStatus = "DELIVERED"        // Delivery order handed to the customer
+	OrderStatusCancelled      OrderStatus = "CANCELLED"
+)
+
+// ErrOrderNotAwaitingCash is returned when a bar payment is confirmed for an order that isn't AWAITING_CASH,
+// usually because another member of staff confirmed it first
+var ErrOrderNotAwaitingCash = errors.New("only AWAITING_CASH orders can be confirmed as paid at the bar")
+
+// Actors recorded in the order status history when no dashboard user made the change
+const (
+	OrderActorSystem  = "system"
+	OrderActorWebhook = "webhook"
+)
+
+// OrderStatusChange is one entry in an order's status audit trail
+type OrderStatusChange struct {
+	ID         string      `json:"id"`
+	OrderID    string      `json:"order_id"`
+	FromStatus OrderStatus `json:"from_status"` // Empty for the entry written when the order is created
+	ToStatus   OrderStatus `json:"to_status"`
+	Actor      string      `json:"actor"`                // Admin user ID, "system" or "webhook"
+	ActorName  string      `json:"actor_name,omitempty"` // Admin user name when Actor is a dashboard user
+	Note       string      `json:"note,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// BoardOrder is an order on the live orders board, with when it entered its current status
+type BoardOrder struct {
+	ID                string      `json:"id"`
+	PickupCode        string      `json:"pickup_code"`
+	TableNumber       string      `json:"table_number,omitempty"`
+	Status            OrderStatus `json:"status"`
+	Notes             string      `json:"notes,omitempty"`
+	IsDelivery        bool        `json:"is_delivery"`
+	AcceptedByStaffID string      `json:"accepted_by_staff_id,omitempty"`
+	Items             []BoardItem `json:"items"`
+	StatusSince       time.Time   `json:"status_since"`
+	AgeSeconds        int         `json:"age_seconds"` // Time spent in the current status when the board was built
+	CreatedAt         time.Time   `json:"created_at"`
+}
+
+// BoardItem is one line of a board order, as the bar needs to make it
+type BoardItem struct {
+	ProductName string          `json:"product_name"`
+	Quantity    int             `json:"quantity"`
+	Modifiers   []OrderModifier `json:"modifiers,omitempty"`
+}
+
+// OrderBoard is the kitchen/bar display: the PAID queue and READY orders oldest first, and recently
+// COMPLETED orders newest first
+type OrderBoard struct {
+	Paid        []*BoardOrder `json:"paid"`
+	Ready       []*BoardOrder `json:"ready"`
+	Completed   []*BoardOrder `json:"completed"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// Payment share statuses
+const (
+	PaymentSharePending = "PENDING"
+	PaymentSharePaid    = "PAID"
+	PaymentShareFailed  = "FAILED"
 )
 
+// PaymentShare is one payment towards an order. Split bills create a PENDING share per payer up front;
+// a confirmed payment that matches no share is recorded as a PAID share when it arrives.
+type PaymentShare struct {
+	ID        string     `json:"id"`
+	OrderID   string     `json:"order_id"`
+	Phone     string     `json:"phone"`
+	Amount    float64    `json:"amount"`
+	Status    string     `json:"status"`
+	Reference string     `json:"reference,omitempty"`
+	PaidAt    *time.Time `json:"paid_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PaymentApplication is the outcome of applying a confirmed payment to an order
+type PaymentApplication struct {
+	Share      *PaymentShare `json:"share"`
+	AmountPaid float64       `json:"amount_paid"`
+	Status     OrderStatus   `json:"status"`    // Order status after the payment
+	Duplicate  bool          `json:"duplicate"` // The reference was already applied; nothing changed
+	// The order was cancelled by staff rather than expiring unpaid, so the payment wasn't applied and
+	// has to be refunded or attached to another order
+	RefundRequired bool `json:"refund_required"`
+}
+
+// OrderDetail is a single order with everything the dashboard's order page shows
+type OrderDetail struct {
+	*Order
+	ReadyByName     string               `json:"ready_by_name,omitempty"`     // Resolved from admin_users
+	CompletedByName string               `json:"completed_by_name,omitempty"` // Resolved from admin_users
+	History         []*OrderStatusChange `json:"history"`
+}
+
 // PaymentMethod represents the payment method used
 type PaymentMethod string
 
@@ -64,45 +487,310 @@ const (
 	PaymentMethodCash  PaymentMethod = "CASH"
 )
 
-// User represents a customer in the system
-type User struct {
+// Payment represents a webhook-confirmed transaction in the payments ledger
+type Payment struct {
 	ID          string    `json:"id"`
-	PhoneNumber string    `json:"phone_number"`
-	Name        string    `json:"name"`
+	Provider    string    `json:"provider"` // KOPOKOPO
+	Reference   string    `json:"reference"`
+	Phone       string    `json:"phone"`
+	HashedPhone string    `json:"hashed_phone,omitempty"`
+	PayerName   string    `json:"payer_name"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"` // Raw provider status (e.g., Success, Received)
+	OrderID     string    `json:"order_id,omitempty"`
+	IsOrphan    bool      `json:"is_orphan"` // No order matched when the webhook arrived
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+const (
+	PaymentProviderKopoKopo = "KOPOKOPO"
+)
+
+// WebhookSubscription is a payment provider webhook registration
+type WebhookSubscription struct {
+	ID             string `json:"id"`
+	EventType      string `json:"event_type"` // e.g. buygoods_transaction_received
+	URL            string `json:"url"`
+	Scope          string `json:"scope,omitempty"` // till or company
+	ScopeReference string `json:"scope_reference,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
+
+// PaymentFilter narrows payment ledger queries; zero values are ignored
+type PaymentFilter struct {
+	Provider  string
+	Phone     string
+	Reference string
+	OrderID   string
+	Orphan    *bool
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+}
+
+// AuditLog is one mutating admin API request
+type AuditLog struct {
+	ID         string          `json:"id"`
+	Actor      string          `json:"actor"` // Admin user ID from the JWT
+	ActorName  string          `json:"actor_name,omitempty"`
+	Role       string          `json:"role,omitempty"`
+	Method     string          `json:"method"`
+	Route      string          `json:"route"` // Matched pattern, e.g. /api/admin/products/:id/price
+	Path       string          `json:"path"`
+	EntityType string          `json:"entity_type,omitempty"` // First route segment, e.g. products
+	EntityID   string          `json:"entity_id,omitempty"`   // The route's :id, or the ID of the object a POST created
+	StatusCode int             `json:"status_code"`
+	Changes    json.RawMessage `json:"changes,omitempty"` // Request body with secrets redacted
+	IP         string          `json:"ip,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter narrows audit log searches; zero values are ignored
+type AuditLogFilter struct {
+	Actor      string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time // Exclusive
+	Limit      int
+}
+
+// Webhook event sources
+const (
+	WebhookSourceWhatsApp = "whatsapp"
+	WebhookSourcePayment  = "payment"
+)
+
+// WebhookEvent is one raw inbound webhook request, kept so it can be inspected and replayed
+type WebhookEvent struct {
+	ID             string            `json:"id"`
+	Source         string            `json:"source"` // whatsapp or payment
+	Headers        map[string]string `json:"headers"`
+	Body           string            `json:"body"`
+	SignatureValid *bool             `json:"signature_valid"` // nil when the source isn't verified (no WhatsApp app secret)
+	StatusCode     int               `json:"status_code"`     // What the webhook endpoint responded
+	Response       string            `json:"response,omitempty"`
+	IP             string            `json:"ip,omitempty"`
+	RequestID      string            `json:"request_id,omitempty"`
+	ReplayCount    int               `json:"replay_count"`
+	LastReplayedAt *time.Time        `json:"last_replayed_at,omitempty"`
+	ReceivedAt     time.Time         `json:"received_at"`
+}
+
+// WebhookEventFilter narrows webhook event searches; zero values are ignored
+type WebhookEventFilter struct {
+	Source         string
+	SignatureValid *bool
+	StatusCode     int
+	Search         string // Substring of the raw body, e.g. an M-Pesa reference or phone number
+	From           *time.Time
+	To             *time.Time // Exclusive
+	Limit          int
+}
+
+// Outbox topics: the side effects of an order being paid, each delivered and retried on its own
+const (
+	OutboxTopicPaidCustomer       = "order_paid.customer"       // Pickup code (or delivery details) and receipt
+	OutboxTopicPaidBarStaff       = "order_paid.bar_staff"      // Order to the bar staff on shift
+	OutboxTopicPaidDashboard      = "order_paid.dashboard"      // new_order SSE event
+	OutboxTopicScheduledCustomer  = "order_scheduled.customer"  // Pre-order confirmation with its time, and receipt
+	OutboxTopicScheduledDashboard = "order_scheduled.dashboard" // order_scheduled SSE event
+	OutboxTopicPaidStock          = "order_paid.stock"          // stock_updated (and low_stock) for the recipe ingredients poured
+)
+
+// Outbox message statuses
+const (
+	OutboxStatusPending = "PENDING"
+	OutboxStatusSent    = "SENT"
+	OutboxStatusFailed  = "FAILED" // Out of attempts
+)
+
+// OutboxMessage is a side effect of an order status change, stored in the same transaction as the
+// change and delivered afterwards by the outbox dispatcher
+type OutboxMessage struct {
+	ID            string     `json:"id"`
+	Topic         string     `json:"topic"`
+	OrderID       string     `json:"order_id"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// OrderFilter narrows admin order searches; zero values are ignored
+type OrderFilter struct {
+	Status        string
+	PickupCode    string
+	Phone         string // Any Kenyan format; matched against equivalent forms of the number
+	PaymentMethod string
+	MinAmount     *float64
+	MaxAmount     *float64
+	From          *time.Time
+	To            *time.Time // Exclusive
+	Limit         int
+}
+
+// User represents a customer in the system
+type User struct {
+	ID                  string     `json:"id"`
+	PhoneNumber         string     `json:"phone_number"`
+	Name                string     `json:"name"`
+	Language            string     `json:"language"` // Preferred bot language: en, sw
+	CartRemindersOptOut bool       `json:"cart_reminders_opt_out"`
+	MarketingOptIn      bool       `json:"marketing_opt_in"`              // Consented to broadcast offers ("subscribe")
+	MarketingOptInAt    *time.Time `json:"marketing_opt_in_at,omitempty"` // When consent was last given
+	AgeConfirmedAt      *time.Time `json:"age_confirmed_at,omitempty"`    // When the customer confirmed they're 18 or older
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
 // Session represents a user's current state in Redis
 type Session struct {
-	State            string     `json:"state"`              // START, MENU, BROWSING, SELECTING_PRODUCT, QUANTITY, CONFIRMATION
-	CurrentCategory  string     `json:"current_category"`   // Current category being browsed
-	CurrentProductID string     `json:"current_product_id"` // Product being selected
-	Cart             []CartItem `json:"cart"`               // Array of cart items
-	PendingOrderID   string     `json:"pending_order_id"`   // Order ID with pending payment (prevents duplicate checkout)
+	State            string          `json:"state"`                       // START, MENU, BROWSING, SELECTING_PRODUCT, SELECTING_OPTION, QUANTITY, CONFIRMATION
+	CurrentCategory  string          `json:"current_category"`            // Current category being browsed
+	CurrentProductID string          `json:"current_product_id"`          // Product being selected
+	Cart             []CartItem      `json:"cart"`                        // Array of cart items
+	PendingOrderID   string          `json:"pending_order_id"`            // Order ID with pending payment (prevents duplicate checkout)
+	Language         string          `json:"language,omitempty"`          // Bot language for this conversation (en, sw)
+	Page             int             `json:"page,omitempty"`              // Zero-based page of the category or product list being shown
+	PendingModifiers []OrderModifier `json:"pending_modifiers,omitempty"` // Options chosen so far for CurrentProductID
+	PendingQuantity  int             `json:"pending_quantity,omitempty"`  // Quantity typed with the product ("2 mojitos"), added once options are answered
+	SplitCount       int             `json:"split_count,omitempty"`       // People sharing the bill when splitting at checkout
+	SplitPhones      []string        `json:"split_phones,omitempty"`      // M-Pesa numbers collected so far for a split bill
+	TipAmount        float64         `json:"tip_amount,omitempty"`        // Tip chosen at checkout, added to the amount charged
+	TableNumber      string          `json:"table_number,omitempty"`      // Table given in the checkout form, copied to the order
+	PaymentPhone     string          `json:"payment_phone,omitempty"`     // M-Pesa number given in the checkout form (+254...)
+	OrderNotes       string          `json:"order_notes,omitempty"`       // Special instructions given before checkout, copied to the order
+	TabID            string          `json:"tab_id,omitempty"`            // Tab being checked out; the cart holds its items
+	TabItemIDs       []string        `json:"tab_item_ids,omitempty"`      // Tab items the next order pays for
+	ScheduledFor     *time.Time      `json:"scheduled_for,omitempty"`     // Pickup time chosen at checkout for a pre-order
+	DeliveryAddress  string          `json:"delivery_address,omitempty"`  // Address given at checkout for delivery; empty for pickup
+	DeliveryLocation *GeoPoint       `json:"delivery_location,omitempty"` // Location pin shared at checkout for delivery
+	DeliveryFee      float64         `json:"delivery_fee,omitempty"`      // Delivery fee added to the amount charged
+	FeedbackID       string          `json:"feedback_id,omitempty"`       // Rated order feedback waiting for an optional comment
+	FavoriteOrderID  string          `json:"favorite_order_id,omitempty"` // Paid order being saved as a favorite, until it's named
+	Booking          *BookingDraft   `json:"booking,omitempty"`           // Reservation being made, until it's booked
+	AgeConfirmed     bool            `json:"age_confirmed,omitempty"`     // Customer has confirmed they're 18 or older (cached from the user)
+}
+
+// BotAction is what a customer's free-text message asks the bot to do
+type BotAction string
+
+// Bot actions a MessageInterpreter can return
+const (
+	BotActionNone           BotAction = "none" // Not understood; the state machine handles the message
+	BotActionBrowseCategory BotAction = "browse_category"
+	BotActionAddItem        BotAction = "add_item"
+	BotActionViewCart       BotAction = "view_cart"
+	BotActionCheckout       BotAction = "checkout"
+)
+
+// InterpretedMessage is a customer message mapped to a bot action. The bot validates it against the
+// live menu before acting on it.
+type InterpretedMessage struct {
+	Action    BotAction `json:"action"`
+	Category  string    `json:"category,omitempty"`   // Menu category, for browse_category
+	ProductID string    `json:"product_id,omitempty"` // Menu product ID, for add_item
+	Quantity  int       `json:"quantity,omitempty"`   // For add_item; 0 when the customer didn't say
 }
 
 // CartItem represents an item in the user's shopping cart
 type CartItem struct {
-	ProductID string  `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Name      string  `json:"name"`  // Denormalized for quick display
-	Price     float64 `json:"price"` // Denormalized for quick calculation
+	ProductID string          `json:"product_id"`
+	Quantity  int             `json:"quantity"`
+	Name      string          `json:"name"`  // Denormalized for quick display
+	Price     float64         `json:"price"` // Denormalized for quick calculation (includes modifier price deltas)
+	Modifiers []OrderModifier `json:"modifiers,omitempty"`
 }
 
 // AdminUser represents a manager/owner who can access the dashboard
 type AdminUser struct {
+	ID           string    `json:"id"`
+	PhoneNumber  string    `json:"phone_number"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"` // MANAGER, BARTENDER
+	PinHash      string    `json:"-"`
+	TokenVersion int       `json:"-"` // Bumped on deactivation or role change to invalidate issued JWTs
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+const (
+	AdminRoleManager   = "MANAGER"
+	AdminRoleBartender = "BARTENDER"
+)
+
+// BarStaff represents a bartender who receives paid-order notifications on WhatsApp
+type BarStaff struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	PhoneNumber    string     `json:"phone_number"`
+	IsOnShift      bool       `json:"is_on_shift"`
+	IsActive       bool       `json:"is_active"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Shift is a bartender's clock in to clock out; EndedAt is nil while they're still on shift
+type Shift struct {
+	ID        string     `json:"id"`
+	StaffID   string     `json:"bar_staff_id"`
+	StaffName string     `json:"staff_name"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	OpenedVia string     `json:"opened_via"`           // dashboard or whatsapp
+	ClosedVia string     `json:"closed_via,omitempty"` // dashboard or whatsapp
+}
+
+// Where a clock in or out came from
+const (
+	ShiftViaDashboard = "dashboard"
+	ShiftViaWhatsApp  = "whatsapp"
+)
+
+// ShiftFilter narrows the shift list
+type ShiftFilter struct {
+	StaffID string
+	From    *time.Time // Shifts started at or after
+	To      *time.Time // Shifts started before
+	Limit   int
+}
+
+// ShiftSales are the orders paid while a shift was running, bar-wide
+type ShiftSales struct {
+	Orders         int     `json:"orders"`
+	Revenue        float64 `json:"revenue"` // Tips and delivery fees excluded
+	Tips           float64 `json:"tips"`
+	CashCollected  float64 `json:"cash_collected"`
+	CardCollected  float64 `json:"card_collected"`
+	MpesaCollected float64 `json:"mpesa_collected"`
+	OrdersAccepted int     `json:"orders_accepted"` // Paid orders this bartender accepted
+}
+
+// ShiftReport is a shift with its sales; running shifts are reported up to now
+type ShiftReport struct {
+	Shift    *Shift      `json:"shift"`
+	Sales    *ShiftSales `json:"sales"`
+	Duration int         `json:"duration_seconds"`
+}
+
+// Rider delivers dispatched delivery orders; available riders are offered each one on WhatsApp
+type Rider struct {
 	ID          string    `json:"id"`
-	PhoneNumber string    `json:"phone_number"`
 	Name        string    `json:"name"`
-	Role        string    `json:"role"` // MANAGER, BARTENDER
-	PinHash     string    `json:"-"`
+	PhoneNumber string    `json:"phone_number"`
+	IsAvailable bool      `json:"is_available"`
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// Bar staff notification modes
 const (
-	AdminRoleManager   = "MANAGER"
-	AdminRoleBartender = "BARTENDER"
+	BarStaffNotifyBroadcast  = "broadcast"
+	BarStaffNotifyRoundRobin = "round_robin"
 )
 
 // OTPCode represents a one-time password for authentication
@@ -112,15 +800,19 @@ type OTPCode struct {
 	Code        string    `json:"code"`
 	ExpiresAt   time.Time `json:"expires_at"`
 	Verified    bool      `json:"verified"`
+	Attempts    int       `json:"attempts"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
 // Analytics represents dashboard overview metrics
 type Analytics struct {
-	TodayRevenue      float64    `json:"today_revenue"`
-	TodayOrders       int        `json:"today_orders"`
-	BestSeller        BestSeller `json:"best_seller"`
-	AverageOrderValue float64    `json:"average_order_value"`
+	TodayRevenue           float64            `json:"today_revenue"` // Revenue for the requested range (the current business day by default)
+	TodayOrders            int                `json:"today_orders"`
+	BestSeller             BestSeller         `json:"best_seller"`
+	AverageOrderValue      float64            `json:"average_order_value"`
+	RevenueByPaymentMethod map[string]float64 `json:"revenue_by_payment_method"` // MPESA, CASH, CARD
+	StartAt                time.Time          `json:"start_at"`
+	EndAt                  time.Time          `json:"end_at"`
 }
 
 // BestSeller represents the top-selling product
@@ -143,18 +835,652 @@ type TopProduct struct {
 	Revenue      float64 `json:"revenue"`
 }
 
+// CoPurchase is a product often bought in the same order as another one
+type CoPurchase struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Category    string `json:"category"`
+	Orders      int    `json:"orders"` // Settled orders with both products
+}
+
+// ProductMargin is one product's sales against the cost recorded on its order items
+type ProductMargin struct {
+	ProductID       string  `json:"product_id"`
+	ProductName     string  `json:"product_name"`
+	Category        string  `json:"category"`
+	QuantitySold    int     `json:"quantity_sold"`
+	Revenue         float64 `json:"revenue"`          // Net of VAT
+	UncostedRevenue float64 `json:"uncosted_revenue"` // Revenue from items sold without a known cost
+	Cost            float64 `json:"cost"`
+	GrossProfit     float64 `json:"gross_profit"`   // Costed revenue minus cost
+	MarginPercent   float64 `json:"margin_percent"` // Gross profit over costed revenue
+	NegativeMargin  bool    `json:"negative_margin"`
+}
+
+// CategoryMargin totals the costed products of one category
+type CategoryMargin struct {
+	Category        string  `json:"category"`
+	QuantitySold    int     `json:"quantity_sold"`
+	Revenue         float64 `json:"revenue"`
+	UncostedRevenue float64 `json:"uncosted_revenue"`
+	Cost            float64 `json:"cost"`
+	GrossProfit     float64 `json:"gross_profit"`
+	MarginPercent   float64 `json:"margin_percent"`
+	NegativeMargin  bool    `json:"negative_margin"`
+}
+
+// StockValuation is one product's stock on hand valued at its cost price
+type StockValuation struct {
+	ProductID     string     `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	Category      string     `json:"category"`
+	StockQuantity int        `json:"stock_quantity"`
+	CostPrice     float64    `json:"cost_price"`   // Zero when unknown
+	Price         float64    `json:"price"`        // Menu price
+	Value         float64    `json:"value"`        // Stock at cost price
+	RetailValue   float64    `json:"retail_value"` // Stock at menu price
+	LastSoldAt    *time.Time `json:"last_sold_at,omitempty"`
+	DeadStock     bool       `json:"dead_stock"` // No sales in the report's dead-stock window
+}
+
+// CategoryValuation totals the stock of one category
+type CategoryValuation struct {
+	Category      string  `json:"category"`
+	Products      int     `json:"products"`
+	StockQuantity int     `json:"stock_quantity"`
+	Value         float64 `json:"value"`
+	RetailValue   float64 `json:"retail_value"`
+}
+
+// InventoryValuationReport values current stock at cost for the owner's monthly review. Products
+// without a cost price are counted in UncostedProducts and add nothing to Value.
+type InventoryValuationReport struct {
+	Products         []*StockValuation    `json:"products"`   // By category, then name
+	Categories       []*CategoryValuation `json:"categories"` // Highest value first
+	DeadStock        []*StockValuation    `json:"dead_stock"` // Highest value first
+	DeadStockDays    int                  `json:"dead_stock_days"`
+	DeadStockValue   float64              `json:"dead_stock_value"`
+	Value            float64              `json:"value"`
+	RetailValue      float64              `json:"retail_value"`
+	UncostedProducts int                  `json:"uncosted_products"`
+	GeneratedAt      time.Time            `json:"generated_at"`
+}
+
+// MarginReport is gross margin for a date range. Products sold without any cost data are left out
+// and only counted in UncostedProducts/UncostedRevenue, so they don't show up as pure profit.
+type MarginReport struct {
+	Products         []*ProductMargin  `json:"products"`   // Highest gross profit first
+	Categories       []*CategoryMargin `json:"categories"` // Highest gross profit first
+	Revenue          float64           `json:"revenue"`
+	UncostedRevenue  float64           `json:"uncosted_revenue"`
+	UncostedProducts int               `json:"uncosted_products"`
+	Cost             float64           `json:"cost"`
+	GrossProfit      float64           `json:"gross_profit"`
+	MarginPercent    float64           `json:"margin_percent"`
+	StartAt          time.Time         `json:"start_at"`
+	EndAt            time.Time         `json:"end_at"`
+}
+
+// PrepTimeStats summarises service speed for a group of orders: PAID to READY (preparation) and
+// READY to COMPLETED (collection), in seconds
+type PrepTimeStats struct {
+	Date                string  `json:"date,omitempty"`           // Business date, for per-day rows
+	BartenderID         string  `json:"bartender_id,omitempty"`   // Admin user who marked the orders ready, for per-bartender rows
+	BartenderName       string  `json:"bartender_name,omitempty"` // Empty when orders were marked ready by the system
+	Orders              int     `json:"orders"`                   // Orders paid in the range and marked ready
+	AvgPaidToReady      float64 `json:"avg_paid_to_ready_seconds"`
+	P50PaidToReady      float64 `json:"p50_paid_to_ready_seconds"`
+	P90PaidToReady      float64 `json:"p90_paid_to_ready_seconds"`
+	OverSLA             int     `json:"over_sla"` // Orders whose preparation took longer than the SLA
+	Collected           int     `json:"collected"`
+	AvgReadyToCompleted float64 `json:"avg_ready_to_completed_seconds"`
+	P50ReadyToCompleted float64 `json:"p50_ready_to_completed_seconds"`
+	P90ReadyToCompleted float64 `json:"p90_ready_to_completed_seconds"`
+}
+
+// PrepTimeReport is service speed over a date range, overall, per business day and per bartender
+type PrepTimeReport struct {
+	Overall    *PrepTimeStats   `json:"overall"`
+	Days       []*PrepTimeStats `json:"days"`       // Oldest first
+	Bartenders []*PrepTimeStats `json:"bartenders"` // Fastest average preparation first
+	SLASeconds int              `json:"sla_seconds"`
+	StartAt    time.Time        `json:"start_at"`
+	EndAt      time.Time        `json:"end_at"`
+}
+
+// StaffPerformance is one dashboard user's share of the order handling in a date range
+type StaffPerformance struct {
+	AdminUserID        string  `json:"admin_user_id"`
+	Name               string  `json:"name"`
+	Role               string  `json:"role"`
+	IsActive           bool    `json:"is_active"`
+	OrdersReady        int     `json:"orders_ready"`         // Orders this user marked ready
+	OrdersCompleted    int     `json:"orders_completed"`     // Orders this user marked completed or delivered
+	AvgHandlingSeconds float64 `json:"avg_handling_seconds"` // Paid→ready on the orders this user marked ready
+	Tips               float64 `json:"tips"`                 // Tips on orders this user marked ready (or completed, when nobody marked them ready)
+}
+
+// StaffReport is per-user order handling over a date range, for shift reviews
+type StaffReport struct {
+	Staff   []*StaffPerformance `json:"staff"` // Most orders handled first
+	StartAt time.Time           `json:"start_at"`
+	EndAt   time.Time           `json:"end_at"`
+}
+
+// Supplier delivers stock to the bar
+type Supplier struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	PhoneNumber string    `json:"phone_number,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PurchaseOrderStatus represents where a purchase order is in the order → receive workflow
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderOrdered   PurchaseOrderStatus = "ORDERED"
+	PurchaseOrderReceived  PurchaseOrderStatus = "RECEIVED" // Delivered quantities added to stock
+	PurchaseOrderCancelled PurchaseOrderStatus = "CANCELLED"
+)
+
+// PurchaseOrder is stock ordered from a supplier
+type PurchaseOrder struct {
+	ID           string              `json:"id"`
+	SupplierID   string              `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Status       PurchaseOrderStatus `json:"status"`
+	Reference    string              `json:"reference,omitempty"` // Supplier's invoice or delivery note number
+	Notes        string              `json:"notes,omitempty"`
+	Items        []PurchaseOrderItem `json:"items"`
+	TotalCost    float64             `json:"total_cost"` // Ordered cost until received, then the received cost
+	CreatedBy    string              `json:"created_by"`
+	ReceivedBy   string              `json:"received_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+}
+
+// PurchaseOrderItem is one product on a purchase order
+type PurchaseOrderItem struct {
+	ProductID        string  `json:"product_id"`
+	ProductName      string  `json:"product_name"`
+	Quantity         int     `json:"quantity"`
+	UnitCost         float64 `json:"unit_cost"`
+	ReceivedQuantity *int    `json:"received_quantity,omitempty"` // Set once the delivery is received
+}
+
 // SalesReport represents an exportable sales report for a time range.
 type SalesReport struct {
-	Title               string    `json:"title"`
-	DateLabel           string    `json:"date_label"`
-	Timezone            string    `json:"timezone"`
-	BusinessDayStart    string    `json:"business_day_start"`
-	StartAt             time.Time `json:"start_at"`
-	EndAt               time.Time `json:"end_at"`
-	GeneratedAt         time.Time `json:"generated_at"`
-	TotalRevenue        float64   `json:"total_revenue"`
-	OrderCount          int       `json:"order_count"`
-	AverageOrderValue   float64   `json:"average_order_value"`
-	SettledStatusFilter []string  `json:"settled_status_filter"`
-	Orders              []Order   `json:"orders"`
+	Title               string              `json:"title"`
+	DateLabel           string              `json:"date_label"`
+	Timezone            string              `json:"timezone"`
+	BusinessDayStart    string              `json:"business_day_start"`
+	StartAt             time.Time           `json:"start_at"`
+	EndAt               time.Time           `json:"end_at"`
+	GeneratedAt         time.Time           `json:"generated_at"`
+	TotalRevenue        float64             `json:"total_revenue"` // Sales only; tips are reported in TotalTips
+	OrderCount          int                 `json:"order_count"`
+	AverageOrderValue   float64             `json:"average_order_value"`
+	TotalTax            float64             `json:"total_tax"`
+	NetSales            float64             `json:"net_sales"` // TotalRevenue less VAT
+	TaxSummary          []TaxLine           `json:"tax_summary"`
+	PaymentMethods      []PaymentMethodLine `json:"payment_methods"`
+	TotalTips           float64             `json:"total_tips"`
+	StaffTips           []StaffTips         `json:"staff_tips"`
+	SettledStatusFilter []string            `json:"settled_status_filter"`
+	Orders              []Order             `json:"orders"`
+	Comparison          *SalesComparison    `json:"comparison,omitempty"` // Weekly and monthly reports only
+}
+
+// SalesComparison compares a weekly or monthly report with the period before it
+type SalesComparison struct {
+	PreviousLabel             string         `json:"previous_label"`
+	PreviousRevenue           float64        `json:"previous_revenue"`
+	PreviousOrderCount        int            `json:"previous_order_count"`
+	PreviousAverageOrderValue float64        `json:"previous_average_order_value"`
+	RevenueChange             *float64       `json:"revenue_change"` // Percent; nil when the previous period had none
+	OrderCountChange          *float64       `json:"order_count_change"`
+	AverageOrderValueChange   *float64       `json:"average_order_value_change"`
+	MoversUp                  []ProductMover `json:"movers_up"`   // Largest revenue gain first
+	MoversDown                []ProductMover `json:"movers_down"` // Largest revenue drop first
+}
+
+// ProductMover is a product whose sales changed between two report periods
+type ProductMover struct {
+	ProductID        string  `json:"product_id"`
+	ProductName      string  `json:"product_name"`
+	Quantity         int     `json:"quantity"`
+	PreviousQuantity int     `json:"previous_quantity"`
+	Revenue          float64 `json:"revenue"`
+	PreviousRevenue  float64 `json:"previous_revenue"`
+	RevenueChange    float64 `json:"revenue_change"` // Revenue less PreviousRevenue
+}
+
+// AccountingEntry is one journal line of the accounting export: a settled order, or the refund of one
+type AccountingEntry struct {
+	Date          time.Time `json:"date"` // When the order was placed, or when it was refunded
+	OrderID       string    `json:"order_id"`
+	PickupCode    string    `json:"pickup_code"`
+	PaymentRef    string    `json:"payment_reference"`
+	PaymentMethod string    `json:"payment_method"`
+	Gross         float64   `json:"gross"` // Negative for refunds
+	Tax           float64   `json:"tax"`   // Negative for refunds
+	Refund        bool      `json:"refund"`
+	Note          string    `json:"note,omitempty"` // Refund reason
+}
+
+// DailySalesSummary is one business day's row in the owner's sales spreadsheet
+type DailySalesSummary struct {
+	BusinessDate       string  `json:"business_date"` // YYYY-MM-DD
+	Revenue            float64 `json:"revenue"`
+	OrderCount         int     `json:"order_count"`
+	AverageOrderValue  float64 `json:"average_order_value"`
+	TopProduct         string  `json:"top_product"` // Most units sold; empty on a day without sales
+	TopProductQuantity int     `json:"top_product_quantity"`
+}
+
+// TaxLine totals the orders in a report charged at one VAT rate
+type TaxLine struct {
+	Rate          float64 `json:"rate"`
+	OrderCount    int     `json:"order_count"`
+	GrossSales    float64 `json:"gross_sales"`
+	TaxableAmount float64 `json:"taxable_amount"` // Gross sales less VAT
+	TaxAmount     float64 `json:"tax_amount"`
+}
+
+// PaymentMethodLine totals the report's sales settled with one payment method
+type PaymentMethodLine struct {
+	Method     string  `json:"method"`
+	OrderCount int     `json:"order_count"`
+	Amount     float64 `json:"amount"` // Sales only, like SalesReport.TotalRevenue
+}
+
+// StaffTips totals the tips on orders accepted by one bar staff member
+type StaffTips struct {
+	StaffID    string  `json:"staff_id,omitempty"` // Empty for orders no bartender accepted
+	StaffName  string  `json:"staff_name"`
+	OrderCount int     `json:"order_count"`
+	Amount     float64 `json:"amount"`
+}
+
+// Setting is a runtime setting a manager has overridden; Value is typed by the settings service
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedBy string    `json:"updated_by,omitempty"` // Admin user who last changed it
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrderingStatus is the manager's "bar paused" switch: while Paused the bot accepts no new checkouts
+type OrderingStatus struct {
+	Paused    bool      `json:"paused"`
+	Message   string    `json:"message,omitempty"`    // Shown to customers who try to check out while paused
+	UpdatedBy string    `json:"updated_by,omitempty"` // Admin user who last changed it
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TabStatus represents whether a tab still takes drinks
+type TabStatus string
+
+const (
+	TabStatusOpen   TabStatus = "OPEN"
+	TabStatusClosed TabStatus = "CLOSED"
+)
+
+// Tab is a shared bill for customers at one table; members join with JoinCode
+type Tab struct {
+	ID          string      `json:"id"`
+	TableNumber string      `json:"table_number"`
+	JoinCode    string      `json:"join_code"`
+	Status      TabStatus   `json:"status"`
+	OpenedBy    string      `json:"opened_by"` // WhatsApp phone of the customer who started it
+	Members     []TabMember `json:"members"`
+	Items       []TabItem   `json:"items"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ClosedAt    *time.Time  `json:"closed_at,omitempty"`
+}
+
+// TabMember is a customer on a tab
+type TabMember struct {
+	Phone    string    `json:"phone"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// TabItem is a drink a member put on the tab
+type TabItem struct {
+	ID          string          `json:"id"`
+	TabID       string          `json:"tab_id"`
+	Phone       string          `json:"phone"` // Member who added it
+	ProductID   string          `json:"product_id"`
+	Name        string          `json:"name"`
+	Quantity    int             `json:"quantity"`
+	Price       float64         `json:"price"` // Unit price including serving option adjustments
+	Modifiers   []OrderModifier `json:"modifiers,omitempty"`
+	OrderID     string          `json:"order_id,omitempty"`     // Order it was checked out on
+	OrderStatus OrderStatus     `json:"order_status,omitempty"` // Status of that order
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Unpaid reports whether the item still needs checking out: it's on no order, or its order failed
+func (i *TabItem) Unpaid() bool {
+	switch i.OrderStatus {
+	case "", OrderStatusFailed, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// UnpaidItems returns the items still to be checked out, only phone's when phone is set
+func (t *Tab) UnpaidItems(phone string) []TabItem {
+	var items []TabItem
+	for _, item := range t.Items {
+		if item.Unpaid() && (phone == "" || item.Phone == phone) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// HasMember reports whether phone is on the tab
+func (t *Tab) HasMember(phone string) bool {
+	for _, member := range t.Members {
+		if member.Phone == phone {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedCustomerStatus says whether a listed phone is blocked or only flagged for review
+type BlockedCustomerStatus string
+
+const (
+	BlockedCustomerBlocked BlockedCustomerStatus = "BLOCKED" // The bot ignores or declines the customer's messages
+	BlockedCustomerFlagged BlockedCustomerStatus = "FLAGGED" // Repeated failed payments; ordering continues until a manager blocks
+)
+
+// BlockedCustomer is a phone on the blocklist
+type BlockedCustomer struct {
+	ID        string                `json:"id"`
+	Phone     string                `json:"phone"` // +2547xxxxxxxx
+	Status    BlockedCustomerStatus `json:"status"`
+	Reason    string                `json:"reason,omitempty"`
+	CreatedBy string                `json:"created_by,omitempty"` // Admin user, or "system" for automatic flags
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// BroadcastSegment selects which opted-in customers a campaign goes to
+type BroadcastSegment string
+
+const (
+	BroadcastSegmentAll    BroadcastSegment = "all"    // Every opted-in customer
+	BroadcastSegmentRecent BroadcastSegment = "recent" // A settled order in the last SegmentDays
+	BroadcastSegmentLapsed BroadcastSegment = "lapsed" // Ordered before, but not in the last SegmentDays
+)
+
+// BroadcastStatus represents where a campaign is in the queue → send workflow
+type BroadcastStatus string
+
+const (
+	BroadcastQueued    BroadcastStatus = "QUEUED"
+	BroadcastSending   BroadcastStatus = "SENDING"
+	BroadcastCompleted BroadcastStatus = "COMPLETED" // Every recipient sent, failed or skipped
+	BroadcastCancelled BroadcastStatus = "CANCELLED"
+)
+
+// BroadcastRecipientStatus is the delivery state of one campaign message
+type BroadcastRecipientStatus string
+
+const (
+	BroadcastRecipientPending   BroadcastRecipientStatus = "PENDING"
+	BroadcastRecipientSending   BroadcastRecipientStatus = "SENDING"
+	BroadcastRecipientSent      BroadcastRecipientStatus = "SENT" // Accepted by WhatsApp or queued for retry
+	BroadcastRecipientFailed    BroadcastRecipientStatus = "FAILED"
+	BroadcastRecipientSkipped   BroadcastRecipientStatus = "SKIPPED" // Opted out before their turn
+	BroadcastRecipientCancelled BroadcastRecipientStatus = "CANCELLED"
+)
+
+// BroadcastCampaign is a marketing message sent to a segment of opted-in customers
+type BroadcastCampaign struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	Message          string           `json:"message"`
+	TemplateName     string           `json:"template_name,omitempty"` // Approved WhatsApp template; Message fills its body parameter
+	TemplateLanguage string           `json:"template_language,omitempty"`
+	Segment          BroadcastSegment `json:"segment"`
+	SegmentDays      int              `json:"segment_days,omitempty"`
+	Status           BroadcastStatus  `json:"status"`
+	Stats            BroadcastStats   `json:"stats"`
+	CreatedBy        string           `json:"created_by"` // Admin user ID
+	CreatedAt        time.Time        `json:"created_at"`
+	StartedAt        *time.Time       `json:"started_at,omitempty"`
+	CompletedAt      *time.Time       `json:"completed_at,omitempty"`
+}
+
+// BroadcastStats counts a campaign's recipients by delivery status
+type BroadcastStats struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending"` // Includes recipients being sent right now
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled"`
+}
+
+// BroadcastRecipient is one customer a campaign is sent to
+type BroadcastRecipient struct {
+	ID         string                   `json:"id"`
+	CampaignID string                   `json:"campaign_id"`
+	UserID     string                   `json:"user_id"`
+	Phone      string                   `json:"phone"`
+	Status     BroadcastRecipientStatus `json:"status"`
+	Error      string                   `json:"error,omitempty"`
+	SentAt     *time.Time               `json:"sent_at,omitempty"`
+}
+
+// Feedback is a customer's answer to the rating request sent after their order was completed
+type Feedback struct {
+	ID            string     `json:"id"`
+	OrderID       string     `json:"order_id"`
+	PickupCode    string     `json:"pickup_code,omitempty"`
+	CustomerPhone string     `json:"customer_phone"`
+	Rating        int        `json:"rating,omitempty"` // 1-5; 0 until the customer answers
+	Comment       string     `json:"comment,omitempty"`
+	RequestedAt   time.Time  `json:"requested_at"`
+	RatedAt       *time.Time `json:"rated_at,omitempty"`
+}
+
+// FeedbackFilter narrows the feedback list; zero values mean no filter
+type FeedbackFilter struct {
+	From      *time.Time // Requested at or after
+	To        *time.Time // Requested before
+	MaxRating int        // Only ratings at or below this
+	Limit     int
+}
+
+// FeedbackTrend is the average rating for one business day
+type FeedbackTrend struct {
+	Date          string  `json:"date"`
+	Responses     int     `json:"responses"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+// FeedbackReport summarises ratings for a date range
+type FeedbackReport struct {
+	Requests           int              `json:"requests"`      // Rating requests sent
+	Responses          int              `json:"responses"`     // Requests answered with a rating
+	ResponseRate       float64          `json:"response_rate"` // Percent of requests answered
+	AverageRating      float64          `json:"average_rating"`
+	Ratings            map[int]int      `json:"ratings"` // Responses per rating, 1-5
+	Trend              []*FeedbackTrend `json:"trend"`
+	LowRatingThreshold int              `json:"low_rating_threshold"` // Ratings at or below this alert managers
+	LowRatings         []*Feedback      `json:"low_ratings"`          // Newest first
+	StartAt            time.Time        `json:"start_at"`
+	EndAt              time.Time        `json:"end_at"`
+}
+
+// Favorite is a basket a customer saved under a name after paying, to order again in one tap
+type Favorite struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	Items     []CartItem `json:"items"` // Prices are as paid; a reorder uses the current menu prices
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ReservationStatus is where a table or booth booking stands
+type ReservationStatus string
+
+// Reservation statuses
+const (
+	ReservationPendingDeposit ReservationStatus = "PENDING_DEPOSIT" // Waiting for the M-Pesa deposit
+	ReservationConfirmed      ReservationStatus = "CONFIRMED"
+	ReservationSeated         ReservationStatus = "SEATED"
+	ReservationNoShow         ReservationStatus = "NO_SHOW"
+	ReservationCancelled      ReservationStatus = "CANCELLED"
+)
+
+// What a reservation books
+const (
+	ReservationKindTable = "TABLE"
+	ReservationKindVIP   = "VIP" // VIP booth
+)
+
+// Reservation is a table or VIP booth booked through the bot
+type Reservation struct {
+	ID               string            `json:"id"`
+	UserID           string            `json:"user_id"`
+	CustomerPhone    string            `json:"customer_phone"`
+	Kind             string            `json:"kind"` // TABLE or VIP
+	PartySize        int               `json:"party_size"`
+	ReservedFor      time.Time         `json:"reserved_for"`
+	Status           ReservationStatus `json:"status"`
+	DepositAmount    float64           `json:"deposit_amount"` // Zero when no deposit is taken
+	DepositReference string            `json:"deposit_reference,omitempty"`
+	DepositPaidAt    *time.Time        `json:"deposit_paid_at,omitempty"`
+	RemindedAt       *time.Time        `json:"reminded_at,omitempty"` // Set when the on-the-day reminder went out
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// ReservationFilter narrows the reservation list; zero values mean no filter
+type ReservationFilter struct {
+	From   *time.Time // Reserved for at or after
+	To     *time.Time // Reserved for before
+	Status ReservationStatus
+	Limit  int
+}
+
+// BookingDraft holds the answers of a reservation being made in the bot
+type BookingDraft struct {
+	Kind      string     `json:"kind,omitempty"`
+	Date      string     `json:"date,omitempty"` // YYYY-MM-DD, bar-local
+	At        *time.Time `json:"at,omitempty"`
+	PartySize int        `json:"party_size,omitempty"`
+}
+
+// What a purchase limit applies to
+const (
+	PurchaseLimitCategory = "CATEGORY" // Every product in a menu category together, e.g. Spirits
+	PurchaseLimitProduct  = "PRODUCT"
+)
+
+// PurchaseLimit caps how many units of a category or a product one order can hold
+type PurchaseLimit struct {
+	ID          string    `json:"id"`
+	Scope       string    `json:"scope"`  // CATEGORY or PRODUCT
+	Target      string    `json:"target"` // Category name, or product ID
+	Label       string    `json:"label"`  // Category or product name shown to customers
+	MaxQuantity int       `json:"max_quantity"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RefreshToken is a server-side record of a dashboard refresh token; only the SHA-256 of the token is stored
+type RefreshToken struct {
+	TokenHash string    `json:"token_hash"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"` // Role issued at login (PIN logins are always BARTENDER)
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IdempotentResponse is the stored outcome of an admin mutation sent with an Idempotency-Key.
+// Completed is false while the first request is still being handled.
+type IdempotentResponse struct {
+	RequestHash string    `json:"request_hash"` // SHA-256 of method, path and body; a reused key must match it
+	Completed   bool      `json:"completed"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WhatsAppNumber is a WhatsApp Business number pointed at this server, with its own access token. Customers
+// who message it see only its venue's menu categories, and are answered from it.
+type WhatsAppNumber struct {
+	PhoneNumberID      string    `json:"phone_number_id"` // Meta's ID, sent as metadata.phone_number_id on webhooks
+	DisplayPhoneNumber string    `json:"display_phone_number,omitempty"`
+	VenueName          string    `json:"venue_name"`
+	AccessToken        string    `json:"-"`
+	Categories         []string  `json:"categories"` // Menu categories offered through this number; empty for the whole menu
+	Active             bool      `json:"active"`     // Messages to an inactive number are ignored
+	UpdatedBy          string    `json:"updated_by,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// OutboundMessage is a WhatsApp message waiting for a retry or parked in the dead-letter list
+type OutboundMessage struct {
+	ID            string          `json:"id"`
+	To            string          `json:"to"`
+	Payload       json.RawMessage `json:"payload"` // Cloud API request body, replayed as-is
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	RequestID     string          `json:"request_id,omitempty"`      // Request that sent the message, restored on retries
+	PhoneNumberID string          `json:"phone_number_id,omitempty"` // WhatsApp number it was sent from, restored on retries
+	CreatedAt     time.Time       `json:"created_at"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"` // Set when moved to the dead-letter list
+}
+
+// STKPushJob is a queued M-Pesa STK push request, or one parked in the dead-letter list
+type STKPushJob struct {
+	ID        string     `json:"id"`
+	OrderID   string     `json:"order_id"`
+	Phone     string     `json:"phone"`
+	Amount    float64    `json:"amount"`
+	RequestID string     `json:"request_id,omitempty"` // Request that queued the push, for log correlation
+	Attempts  int        `json:"attempts"`             // Deliveries so far, including ones cut short by a crash
+	LastError string     `json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	FailedAt  *time.Time `json:"failed_at,omitempty"` // Set when moved to the dead-letter list
+}
+
+// STK attempt statuses
+const (
+	STKAttemptSent     = "SENT"     // Accepted by Kopo Kopo; the customer should see a prompt
+	STKAttemptRejected = "REJECTED" // Never reached the customer (invalid phone, API or network error)
+	STKAttemptSuccess  = "SUCCESS"  // Callback confirmed payment
+	STKAttemptFailed   = "FAILED"   // Callback reported a failed or cancelled payment
+)
+
+// STKAttempt records one STK push sent for an order and what became of it
+type STKAttempt struct {
+	ID               string    `json:"id"`
+	OrderID          string    `json:"order_id"`
+	Phone            string    `json:"phone"`
+	Amount           float64   `json:"amount"`
+	Status           string    `json:"status"`
+	PaymentRequestID string    `json:"payment_request_id,omitempty"` // Kopo Kopo incoming_payment ID, echoed as data.id in the callback
+	Location         string    `json:"location,omitempty"`
+	HTTPStatus       int       `json:"http_status,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
@@ -5,6 +5,16 @@ import (
 	"time"
 )
 
+// Clock abstracts the current time so time-dependent logic can be tested deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// IDGenerator abstracts ID creation (UUIDs in production)
+type IDGenerator interface {
+	NewID() string
+}
+
 // ProductRepository defines the interface for product data access
 type ProductRepository interface {
 	GetByID(ctx context.Context, id string) (*Product, error)
@@ -12,8 +22,79 @@ type ProductRepository interface {
 	GetAll(ctx context.Context) ([]*Product, error)
 	GetMenu(ctx context.Context) (map[string][]*Product, error)
 	UpdateStock(ctx context.Context, id string, quantity int) error
-	UpdatePrice(ctx context.Context, id string, price float64) error
+	// AdjustStock adds delta (negative to remove) to a product's stock and records it in stock_adjustments
+	// with reason; a delta that would take stock below zero is rejected
+	AdjustStock(ctx context.Context, id string, delta int, reason string, actor string) (*StockAdjustment, error)
+	CorrectStock(ctx context.Context, id string, quantity int, actor string) (*StockAdjustment, error) // Sets stock, recorded as a correction
+	UpdatePrice(ctx context.Context, id string, price float64, actor string) error
+	SetBottleSize(ctx context.Context, id string, bottleML int) error // Zero clears it
+	SetCostPrice(ctx context.Context, id string, cost float64) error  // Zero clears it
 	SearchProducts(ctx context.Context, query string) ([]*Product, error)
+	GetArchived(ctx context.Context) ([]*Product, error)
+	SetArchived(ctx context.Context, id string, archived bool) error
+	GetByNames(ctx context.Context, names []string) (map[string]*Product, error) // Keyed by exact name, archived included
+	UpsertByName(ctx context.Context, products []*Product, actor string) (inserted int, updated int, err error)
+	GetPriceHistory(ctx context.Context, id string, limit int) ([]*PriceChange, error) // Newest first
+	SetHideWhenSoldOut(ctx context.Context, id string, hide *bool) error               // Nil follows the menu.hide_out_of_stock setting
+	// ClaimRestocked marks products that just sold out, and returns (and unmarks) up to limit that were
+	// sold out and have stock again. Each restock is returned once across replicas.
+	ClaimRestocked(ctx context.Context, limit int) ([]*Product, error)
+}
+
+// ProductOptionRepository defines the interface for product serving options
+type ProductOptionRepository interface {
+	GetByProductID(ctx context.Context, productID string) ([]*ProductOption, error) // Includes inactive options, ordered by sort_order
+	GetByID(ctx context.Context, id string) (*ProductOption, error)
+	Create(ctx context.Context, option *ProductOption) error
+	Update(ctx context.Context, option *ProductOption) error
+	Delete(ctx context.Context, id string) error
+}
+
+// BundleRepository defines the interface for combo products and their components
+type BundleRepository interface {
+	GetAll(ctx context.Context) ([]*Bundle, error)
+	GetComponents(ctx context.Context, bundleProductID string) ([]BundleComponent, error) // Empty when the product isn't a combo
+	Create(ctx context.Context, bundle *Bundle) error                                     // Inserts the combo product and its components together
+	SetComponents(ctx context.Context, bundleProductID string, components []BundleComponent) error
+}
+
+// RecipeRepository defines the interface for cocktail recipes measured from bottle stock
+type RecipeRepository interface {
+	GetAll(ctx context.Context) ([]*Recipe, error)                                                      // Every product that has a recipe
+	GetIngredients(ctx context.Context, cocktailProductID string) ([]RecipeIngredient, error)           // Empty when the product has no recipe
+	SetIngredients(ctx context.Context, cocktailProductID string, ingredients []RecipeIngredient) error // An empty list removes the recipe
+	GetOrderDrawdown(ctx context.Context, orderID string) ([]StockAdjustment, error)                    // Ingredient stock an order's cocktails took when it was paid
+}
+
+// StocktakeRepository defines the interface for stocktakes and the stock adjustments they apply
+type StocktakeRepository interface {
+	Create(ctx context.Context, stocktake *Stocktake) error // Fails while another stocktake is OPEN
+	GetAll(ctx context.Context, limit int) ([]*Stocktake, error)
+	GetByID(ctx context.Context, id string) (*Stocktake, error) // Includes its lines
+	// RecordCount saves a product's counted quantity, snapshotting current stock as the system quantity
+	RecordCount(ctx context.Context, stocktakeID string, productID string, counted int, actor string) (*StocktakeLine, error)
+	// Apply adds each line's variance to current stock, audits every change and marks the stocktake APPLIED
+	Apply(ctx context.Context, id string, actor string) ([]StockAdjustment, error)
+	Cancel(ctx context.Context, id string) error
+}
+
+// SupplierRepository defines the interface for the suppliers list
+type SupplierRepository interface {
+	GetAll(ctx context.Context) ([]*Supplier, error)
+	GetByID(ctx context.Context, id string) (*Supplier, error)
+	Create(ctx context.Context, supplier *Supplier) error
+	Update(ctx context.Context, supplier *Supplier) error
+}
+
+// PurchaseOrderRepository defines the interface for supplier purchase orders
+type PurchaseOrderRepository interface {
+	GetAll(ctx context.Context, status PurchaseOrderStatus, limit int) ([]*PurchaseOrder, error) // Any status when status is empty
+	GetByID(ctx context.Context, id string) (*PurchaseOrder, error)
+	Create(ctx context.Context, order *PurchaseOrder) error
+	// Receive adds the received quantities to stock, sets each product's cost price, audits every change
+	// and marks the order RECEIVED
+	Receive(ctx context.Context, id string, items []PurchaseOrderItem, actor string) ([]StockAdjustment, error)
+	Cancel(ctx context.Context, id string) error
 }
 
 // OrderRepository defines the interface for order data access
@@ -25,11 +106,39 @@ type OrderRepository interface {
 	GetByDateRangeAndStatuses(ctx context.Context, start time.Time, end time.Time, statuses []OrderStatus) ([]*Order, error)
 	UpdateStatus(ctx context.Context, id string, status OrderStatus) error
 	UpdateStatusWithActor(ctx context.Context, id string, status OrderStatus, actorUserID string) error
-	GetAllWithFilters(ctx context.Context, status string, limit int) ([]*Order, error)
+	UpdateStatusWithNote(ctx context.Context, id string, status OrderStatus, actor string, note string) error // actor: admin user ID, OrderActorSystem or OrderActorWebhook
+	GetStatusHistory(ctx context.Context, orderID string) ([]*OrderStatusChange, error)
+	MarkAccepted(ctx context.Context, id string, staffID string) (bool, error) // false when another staff member accepted first
+	AssignRider(ctx context.Context, id string, riderID string) (bool, error)  // false when another rider accepted first or the order isn't OUT_FOR_DELIVERY
+	Search(ctx context.Context, filter OrderFilter) ([]*Order, error)          // Newest first
 	GetCompletedHistory(ctx context.Context, pickupCode string, phone string, limit int) ([]*Order, error)
 	FindPendingByPhoneAndAmount(ctx context.Context, phone string, amount float64) (*Order, error)
 	FindPendingByHashedPhoneAndAmount(ctx context.Context, hashedPhone string, amount float64) (*Order, error) // Match by hashed phone from buygoods webhooks
 	FindPendingByAmount(ctx context.Context, amount float64) (*Order, error)                                   // Fallback when phone unavailable
+	IsPickupCodeActive(ctx context.Context, code string) (bool, error)                                         // True when a PENDING/PARTIALLY_PAID/AWAITING_CASH/SCHEDULED/PAID/READY order holds the code
+	GetUncollected(ctx context.Context, readyFor time.Duration) ([]*Order, error)                              // READY for at least readyFor and not yet escalated
+	ClaimReadyReminder(ctx context.Context, id string, reminder int, readyFor time.Duration) (bool, error)     // Records reminder n once the order has been READY for readyFor; false if already sent
+	ClaimPickupEscalation(ctx context.Context, id string, readyFor time.Duration) (bool, error)                // False when already escalated, collected or not yet due
+	GetDueScheduled(ctx context.Context, dueBy time.Time) ([]*Order, error)                                    // SCHEDULED orders wanted at or before dueBy, soonest first
+	ReleaseScheduled(ctx context.Context, id string, note string) (bool, error)                                // SCHEDULED → PAID; false if another replica already released it
+	GetExpiredPending(ctx context.Context, createdBefore time.Time, limit int) ([]*Order, error)               // PENDING with nothing paid, created before createdBefore; oldest first
+	CancelExpiredPending(ctx context.Context, id string, note string) (bool, bool, error)                      // PENDING → CANCELLED unless a payment got there first; the second bool is true when it paid for tab items
+	FailPaymentShare(ctx context.Context, orderID string, phone string, amount float64) (*PaymentShare, error) // Marks the matching PENDING split share FAILED
+	ResetPaymentShare(ctx context.Context, id string) error                                                    // FAILED share back to PENDING before its prompt is resent
+	GetPaymentShares(ctx context.Context, orderID string) ([]*PaymentShare, error)
+	GetBoard(ctx context.Context, completedSince time.Time) ([]*BoardOrder, error)         // PAID and READY orders plus those COMPLETED since completedSince, with items
+	GetPrepOverdue(ctx context.Context, paidFor time.Duration) ([]*Order, error)           // PAID for at least paidFor and not yet flagged
+	ClaimPrepSLAAlert(ctx context.Context, id string, paidFor time.Duration) (bool, error) // False when already flagged, no longer PAID or not yet due
+
+	// ApplyPayment adds a confirmed payment to the order's amount paid and moves it to PARTIALLY_PAID,
+	// or PAID once the total is covered (SCHEDULED for a pre-order whose time is still ahead).
+	// A reference already applied to the order is reported as Duplicate. Moving to PAID or SCHEDULED
+	// queues the order's confirmation side effects in the outbox in the same transaction.
+	ApplyPayment(ctx context.Context, orderID string, phone string, amount float64, reference string, actor string, note string) (*PaymentApplication, error)
+
+	// ConfirmBarPayment moves an AWAITING_CASH order to PAID once staff have taken cash or card at the bar,
+	// queueing the paid order's side effects in the outbox
+	ConfirmBarPayment(ctx context.Context, orderID string, method PaymentMethod, actor string, note string) error
 }
 
 // UserRepository defines the interface for user data access
@@ -37,6 +146,11 @@ type UserRepository interface {
 	GetByPhone(ctx context.Context, phone string) (*User, error)
 	Create(ctx context.Context, user *User) error
 	GetOrCreateByPhone(ctx context.Context, phone string) (*User, error)
+	GetByID(ctx context.Context, id string) (*User, error)
+	UpdateLanguage(ctx context.Context, id string, language string) error
+	SetCartRemindersOptOut(ctx context.Context, id string, optOut bool) error
+	SetMarketingOptIn(ctx context.Context, id string, optIn bool) error
+	ConfirmAge(ctx context.Context, id string) error // Stamps the customer's 18+ confirmation; a second call keeps the first time
 }
 
 // SessionRepository defines the interface for session state management in Redis
@@ -54,6 +168,24 @@ type Button struct {
 	Title string
 }
 
+// ListRow represents one row of an interactive list message
+type ListRow struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+// Flow is a WhatsApp Flows form sent as an interactive message; the submission comes back
+// through the webhook as an nfm_reply carrying Token
+type Flow struct {
+	ID     string                 // Published Flow ID from WhatsApp Manager
+	Token  string                 // Echoed back in the reply so it can be matched to the conversation
+	Screen string                 // First screen to open
+	Body   string                 // Message text shown above the button
+	CTA    string                 // Label of the button that opens the form
+	Data   map[string]interface{} // Initial data for the first screen
+}
+
 // WhatsAppGateway defines the interface for WhatsApp messaging
 type WhatsAppGateway interface {
 	SendText(ctx context.Context, phone string, message string) error
@@ -61,6 +193,28 @@ type WhatsAppGateway interface {
 	SendCategoryList(ctx context.Context, phone string, categories []string) error
 	SendProductList(ctx context.Context, phone string, category string, products []*Product) error
 	SendMenuButtons(ctx context.Context, phone string, text string, buttons []Button) error
+	SendDocument(ctx context.Context, phone string, filename string, data []byte, caption string) error // PDF documents such as receipts
+}
+
+// SMSGateway defines the interface for SMS delivery, used when WhatsApp can't carry a critical message
+type SMSGateway interface {
+	SendSMS(ctx context.Context, phone string, message string) error
+}
+
+// SpreadsheetGateway appends rows to an external spreadsheet, such as the owner's Google Sheet
+type SpreadsheetGateway interface {
+	AppendRow(ctx context.Context, values []interface{}) error
+}
+
+// MessageInterpreter maps an unstructured customer message to a bot action, e.g. with an LLM. It gets
+// the live menu so the action can name one of its products or categories.
+type MessageInterpreter interface {
+	Interpret(ctx context.Context, message string, menu map[string][]*Product) (*InterpretedMessage, error)
+}
+
+// OTPSender delivers dashboard login codes (WhatsApp, SMS, or the console in development)
+type OTPSender interface {
+	SendOTP(ctx context.Context, phone string, code string) error
 }
 
 // PaymentGateway defines the interface for payment processing
@@ -70,36 +224,337 @@ type PaymentGateway interface {
 	ProcessWebhook(ctx context.Context, payload []byte) (*PaymentWebhook, error)
 }
 
+// PaymentWebhookSubscriptions manages the provider's webhook subscriptions (which URLs get payment events)
+type PaymentWebhookSubscriptions interface {
+	CallbackURL() string // Where this server expects payment webhooks
+	ListWebhookSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
+	CreateWebhookSubscription(ctx context.Context, eventType string, url string) (*WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+}
+
+// STKAttemptRepository persists STK push attempts so callbacks can be matched by payment request ID
+type STKAttemptRepository interface {
+	Create(ctx context.Context, attempt *STKAttempt) error
+	ListByOrder(ctx context.Context, orderID string) ([]*STKAttempt, error) // Oldest first
+	FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*STKAttempt, error)
+	UpdateResult(ctx context.Context, paymentRequestID string, status string, errMsg string) error
+}
+
 // PaymentWebhook represents the structure of a payment webhook result
 type PaymentWebhook struct {
-	OrderID     string
-	Status      string
-	Reference   string
-	Amount      float64
-	Phone       string // Sender phone number from webhook (may be empty for buygoods)
-	HashedPhone string // SHA256 hashed phone from buygoods webhooks
-	Success     bool
+	OrderID          string
+	PaymentRequestID string // Kopo Kopo incoming_payment ID (STK push callbacks only)
+	RequestID        string // Request that sent the STK push, echoed back in its metadata
+	Status           string
+	Reference        string
+	Amount           float64
+	Phone            string // Sender phone number from webhook (may be empty for buygoods)
+	HashedPhone      string // SHA256 hashed phone from buygoods webhooks
+	PayerName        string // Sender first/last name when the provider includes it
+	Currency         string
+	Success          bool
+}
+
+// PaymentRepository defines the interface for the payments ledger
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *Payment) error // No-op when provider+reference is already recorded
+	List(ctx context.Context, filter PaymentFilter) ([]*Payment, error)
+	GetByID(ctx context.Context, id string) (*Payment, error)
+	AttachOrder(ctx context.Context, id string, orderID string) (bool, error)            // false when the payment is no longer orphaned
+	ListBetween(ctx context.Context, start time.Time, end time.Time) ([]*Payment, error) // Every payment received in [start, end), oldest first
 }
 
 // AdminUserRepository defines the interface for admin user data access
 type AdminUserRepository interface {
+	GetByID(ctx context.Context, id string) (*AdminUser, error)
 	GetByPhone(ctx context.Context, phone string) (*AdminUser, error)
+	GetAll(ctx context.Context) ([]*AdminUser, error)
 	GetActiveByRole(ctx context.Context, role string) ([]*AdminUser, error)
 	Create(ctx context.Context, user *AdminUser) error
+	Update(ctx context.Context, user *AdminUser) error
+	UpdatePINHash(ctx context.Context, id string, pinHash string) error
+	BumpTokenVersion(ctx context.Context, id string) error
 	IsActive(ctx context.Context, phone string) (bool, error)
 }
 
+// ShiftRepository stores bartender shifts
+type ShiftRepository interface {
+	Open(ctx context.Context, shift *Shift) error                                             // Fails with "already clocked in" when the staff member has an open shift
+	Close(ctx context.Context, staffID string, endedAt time.Time, via string) (*Shift, error) // Fails with "not clocked in" when there's no open shift
+	GetByID(ctx context.Context, id string) (*Shift, error)
+	List(ctx context.Context, filter ShiftFilter) ([]*Shift, error) // Newest first
+	// GetSales totals orders first paid in [start, end); OrdersAccepted counts those staffID accepted
+	GetSales(ctx context.Context, start time.Time, end time.Time, staffID string) (*ShiftSales, error)
+}
+
+// BarStaffRepository defines the interface for the bar staff roster
+type BarStaffRepository interface {
+	GetAll(ctx context.Context) ([]*BarStaff, error)
+	GetByID(ctx context.Context, id string) (*BarStaff, error)
+	GetByPhone(ctx context.Context, phone string) (*BarStaff, error)
+	GetOnShift(ctx context.Context) ([]*BarStaff, error)
+	Create(ctx context.Context, staff *BarStaff) error
+	Update(ctx context.Context, staff *BarStaff) error
+	MarkNotified(ctx context.Context, id string) error
+}
+
+// RiderRepository defines the interface for the delivery riders roster
+type RiderRepository interface {
+	GetAll(ctx context.Context) ([]*Rider, error)
+	GetByID(ctx context.Context, id string) (*Rider, error)
+	GetByPhone(ctx context.Context, phone string) (*Rider, error)
+	GetAvailable(ctx context.Context) ([]*Rider, error)
+	Create(ctx context.Context, rider *Rider) error
+	Update(ctx context.Context, rider *Rider) error
+}
+
 // OTPRepository defines the interface for OTP code management
 type OTPRepository interface {
 	Create(ctx context.Context, otp *OTPCode) error
 	GetLatestByPhone(ctx context.Context, phone string) (*OTPCode, error)
 	MarkAsVerified(ctx context.Context, id string) error
+	IncrementAttempts(ctx context.Context, id string) (int, error)
+	CountSince(ctx context.Context, phone string, since time.Time) (int64, error)
 	CleanupExpired(ctx context.Context) error
 }
 
-// AnalyticsRepository defines the interface for analytics data access
+// AnalyticsRepository defines the interface for analytics data access.
+// Ranges are half-open [start, end) over settled orders.
 type AnalyticsRepository interface {
-	GetOverview(ctx context.Context) (*Analytics, error)
-	GetRevenueTrend(ctx context.Context, days int) ([]*RevenueTrend, error)
-	GetTopProducts(ctx context.Context, limit int) ([]*TopProduct, error)
+	GetOverview(ctx context.Context, start time.Time, end time.Time) (*Analytics, error)
+	GetRevenueTrend(ctx context.Context, start time.Time, end time.Time, dayOffset time.Duration) ([]*RevenueTrend, error) // dayOffset shifts UTC timestamps onto business dates
+	GetTopProducts(ctx context.Context, start time.Time, end time.Time, limit int) ([]*TopProduct, error)
+	GetProductMargins(ctx context.Context, start time.Time, end time.Time) ([]*ProductMargin, error)
+	// GetPrepTimes fills a report's Overall, Days and Bartenders stats for orders paid in the range
+	GetPrepTimes(ctx context.Context, start time.Time, end time.Time, dayOffset time.Duration, sla time.Duration) (*PrepTimeReport, error)
+	GetStaffPerformance(ctx context.Context, start time.Time, end time.Time) ([]*StaffPerformance, error) // Admin users who marked orders created in the range ready or completed
+	// GetCoPurchases returns active products (in category, when set) bought in the same settled orders as
+	// productID since since, most shared orders first
+	GetCoPurchases(ctx context.Context, productID string, category string, since time.Time, limit int) ([]*CoPurchase, error)
+	// GetStockValuation returns every menu product with stock on hand, by category then name, with when
+	// it (or a cocktail or combo made from it) last sold
+	GetStockValuation(ctx context.Context) ([]*StockValuation, error)
+	// GetAccountingEntries returns orders placed in [start, end) that were settled, including ones refunded
+	// since, and refunds made in [start, end), oldest first
+	GetAccountingEntries(ctx context.Context, start time.Time, end time.Time) ([]*AccountingEntry, error)
+}
+
+// ProcessedMessageStore remembers inbound WhatsApp message IDs so a redelivered message is handled once
+type ProcessedMessageStore interface {
+	MarkProcessed(ctx context.Context, messageID string, ttl time.Duration) (bool, error) // False when the ID was already marked within ttl
+}
+
+// SessionLockStore hands out short-lived per-phone locks shared by every replica, so one customer's
+// messages are processed one at a time
+type SessionLockStore interface {
+	TryLock(ctx context.Context, phone string, ttl time.Duration) (token string, ok bool, err error) // ok is false while someone else holds the lock
+	Unlock(ctx context.Context, phone string, token string) error                                    // Releases the lock only while token still holds it
+}
+
+// SessionInspector reads a bot session for support staff without restarting its TTL
+type SessionInspector interface {
+	Inspect(ctx context.Context, phone string) (*Session, time.Duration, error) // Fails with "session not found"; the duration is the TTL left
+}
+
+// CartActivityStore tracks when customers last changed a cart that hasn't been checked out,
+// so idle carts can be nudged
+type CartActivityStore interface {
+	ClaimIdleCarts(ctx context.Context, idleSince time.Time, limit int) ([]string, error)  // Phones idle since before idleSince; claimed phones leave the index
+	ReserveReminder(ctx context.Context, phone string, window time.Duration) (bool, error) // False when the customer was already reminded within window
+}
+
+// RefreshTokenStore keeps dashboard refresh tokens server-side so they can be rotated and revoked
+type RefreshTokenStore interface {
+	Save(ctx context.Context, token *RefreshToken) error
+	Consume(ctx context.Context, tokenHash string) (*RefreshToken, error) // Returns and deletes the token, so each one is usable once
+	RevokeUser(ctx context.Context, userID string) error                  // Deletes every refresh token issued to the user
+}
+
+// SettingsRepository stores overridden runtime settings
+type SettingsRepository interface {
+	GetAll(ctx context.Context) ([]*Setting, error)
+	Upsert(ctx context.Context, settings []*Setting) error // Saves every setting or none
+}
+
+// TabRepository stores shared table tabs, their members and items
+type TabRepository interface {
+	Create(ctx context.Context, tab *Tab) error // Adds OpenedBy as the first member
+	GetByID(ctx context.Context, id string) (*Tab, error)
+	GetOpenByJoinCode(ctx context.Context, code string) (*Tab, error) // Nil without error when no open tab uses the code
+	GetOpenByMember(ctx context.Context, phone string) (*Tab, error)  // Nil without error when phone is on no open tab
+	ListOpen(ctx context.Context) ([]*Tab, error)
+	AddMember(ctx context.Context, tabID string, phone string) error
+	RemoveMember(ctx context.Context, tabID string, phone string) (int, error) // Returns the members left
+	AddItems(ctx context.Context, tabID string, phone string, items []CartItem) error
+	// ClaimItems puts the unpaid items among itemIDs on orderID and returns how many were claimed
+	ClaimItems(ctx context.Context, tabID string, itemIDs []string, orderID string) (int, error)
+	Close(ctx context.Context, tabID string) error
+}
+
+// BlockedCustomerRepository stores the customer blocklist
+type BlockedCustomerRepository interface {
+	GetByPhone(ctx context.Context, phone string) (*BlockedCustomer, error) // Nil without error when the phone isn't listed
+	List(ctx context.Context) ([]*BlockedCustomer, error)
+	Upsert(ctx context.Context, customer *BlockedCustomer) error // Replaces the status and reason of an existing row
+	Delete(ctx context.Context, phone string) error
+	CountFailedOrdersSince(ctx context.Context, phone string, since time.Time) (int, error)
+}
+
+// OrderingStatusStore persists the ordering pause switch so it survives restarts and is shared by every replica
+type OrderingStatusStore interface {
+	GetOrderingStatus(ctx context.Context) (*OrderingStatus, error) // Not paused when it was never set
+	SetOrderingStatus(ctx context.Context, status *OrderingStatus) error
+}
+
+// IdempotencyStore keeps admin mutation responses by Idempotency-Key so a retried request is replayed
+// instead of being applied twice
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key string, requestHash string, ttl time.Duration) (bool, error) // False when the key is already in use
+	Get(ctx context.Context, key string) (*IdempotentResponse, error)                             // Returns "idempotency key not found" once it expires
+	Complete(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error
+	Release(ctx context.Context, key string) error // Forgets a reservation so the request can be retried
+}
+
+// AuditLogRepository stores the admin audit log
+type AuditLogRepository interface {
+	Record(ctx context.Context, entry *AuditLog) error
+	List(ctx context.Context, filter AuditLogFilter) ([]*AuditLog, error) // Newest first
+}
+
+// WebhookEventRepository archives raw inbound webhooks
+type WebhookEventRepository interface {
+	Record(ctx context.Context, event *WebhookEvent) error
+	GetByID(ctx context.Context, id string) (*WebhookEvent, error)
+	List(ctx context.Context, filter WebhookEventFilter) ([]*WebhookEvent, error) // Newest first, bodies included
+	MarkReplayed(ctx context.Context, id string, at time.Time) error
+}
+
+// OutboxRepository delivers the side effects queued with order status changes. Claimed messages are
+// hidden from other replicas until lease runs out, so a dispatcher that crashes mid-delivery is retried.
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxMessage, error) // PENDING messages due by now, oldest first; each claim counts an attempt
+	MarkSent(ctx context.Context, id string, at time.Time) error
+	MarkFailed(ctx context.Context, id string, errMsg string, retryAt *time.Time) error // nil retryAt gives up: the message becomes FAILED
+}
+
+// BroadcastRepository stores marketing campaigns and the delivery status of each recipient
+type BroadcastRepository interface {
+	// Create stores the campaign and queues every opted-in, unblocked customer in its segment
+	Create(ctx context.Context, campaign *BroadcastCampaign, since time.Time) error
+	CountAudience(ctx context.Context, segment BroadcastSegment, since time.Time) (int, error)
+	GetAll(ctx context.Context, limit int) ([]*BroadcastCampaign, error) // Newest first, with stats
+	GetByID(ctx context.Context, id string) (*BroadcastCampaign, error)
+	// ClaimPending marks up to limit PENDING recipients of active campaigns SENDING, oldest campaign first.
+	// Rows locked by another replica are skipped.
+	ClaimPending(ctx context.Context, limit int) ([]*BroadcastRecipient, error)
+	MarkRecipient(ctx context.Context, id string, status BroadcastRecipientStatus, errMsg string) error
+	// FinishCampaigns fails recipients left SENDING since before staleBefore and completes campaigns with
+	// nothing left to send
+	FinishCampaigns(ctx context.Context, staleBefore time.Time) error
+	Cancel(ctx context.Context, id string) error // Recipients not yet sent are CANCELLED
+}
+
+// FeedbackRepository stores the ratings customers give completed orders
+type FeedbackRepository interface {
+	// ClaimDue records a rating request for up to limit orders completed (or delivered) between
+	// completedAfter and completedBefore that haven't been asked yet, and returns them. Each order
+	// is claimed once across replicas.
+	ClaimDue(ctx context.Context, completedAfter time.Time, completedBefore time.Time, limit int) ([]*Feedback, error)
+	// GetLatestUnrated returns the phone's most recent request since since that has no rating yet
+	GetLatestUnrated(ctx context.Context, phone string, since time.Time) (*Feedback, error)
+	GetByID(ctx context.Context, id string) (*Feedback, error)
+	Rate(ctx context.Context, id string, rating int) error
+	SetComment(ctx context.Context, id string, comment string) error
+	List(ctx context.Context, filter FeedbackFilter) ([]*Feedback, error) // Rated feedback, newest first
+	// CountRatings returns requests sent between start and end, keyed by rating (0 = unanswered)
+	CountRatings(ctx context.Context, start time.Time, end time.Time) (map[int]int, error)
+	// GetTrend averages ratings per business day of the request; dayOffset shifts timestamps onto business dates
+	GetTrend(ctx context.Context, start time.Time, end time.Time, dayOffset time.Duration) ([]*FeedbackTrend, error)
+}
+
+// ReservationRepository stores table and VIP booth bookings
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *Reservation) error
+	GetByID(ctx context.Context, id string) (*Reservation, error)
+	List(ctx context.Context, filter ReservationFilter) ([]*Reservation, error) // By reserved time
+	UpdateStatus(ctx context.Context, id string, status ReservationStatus) error
+	// ConfirmDeposit moves a PENDING_DEPOSIT reservation to CONFIRMED; false when it wasn't waiting for one
+	ConfirmDeposit(ctx context.Context, id string, reference string) (bool, error)
+	// ClaimReminders marks up to limit CONFIRMED reservations for later today (before dayEnd) that were
+	// booked before dayStart and not reminded yet, and returns them. Each is claimed once across replicas.
+	ClaimReminders(ctx context.Context, dayStart time.Time, dayEnd time.Time, limit int) ([]*Reservation, error)
+	// CancelUnpaid cancels PENDING_DEPOSIT reservations created before before and returns them
+	CancelUnpaid(ctx context.Context, before time.Time) ([]*Reservation, error)
+}
+
+// PurchaseLimitRepository stores the per-order quantity caps on categories and products
+type PurchaseLimitRepository interface {
+	List(ctx context.Context) ([]*PurchaseLimit, error) // Categories first, then products, by label
+	// Upsert creates the limit or replaces the max quantity of the one with the same scope and target
+	Upsert(ctx context.Context, limit *PurchaseLimit) error
+	Delete(ctx context.Context, id string) error
+}
+
+// RestockAlertRepository stores customers waiting to hear a sold-out product is back
+type RestockAlertRepository interface {
+	Subscribe(ctx context.Context, productID string, phone string) error // Subscribing twice is a no-op
+	Claim(ctx context.Context, productID string) ([]string, error)       // Removes and returns the subscribed phones
+}
+
+// SalesSheetSyncRepository records the business days (YYYY-MM-DD) appended to the sales spreadsheet
+type SalesSheetSyncRepository interface {
+	Claim(ctx context.Context, businessDate string) (bool, error) // False when the day was already synced
+	Release(ctx context.Context, businessDate string) error       // Forgets a claim whose append failed, so it's retried
+}
+
+// FavoriteRepository stores the baskets customers saved as favorites
+type FavoriteRepository interface {
+	Create(ctx context.Context, favorite *Favorite) error // Fails with "favorite name already used" when the user has one with the same name
+	GetByID(ctx context.Context, id string) (*Favorite, error)
+	ListByUser(ctx context.Context, userID string, limit int) ([]*Favorite, error) // Newest first
+	Delete(ctx context.Context, id string) error
+}
+
+// STKPushQueue persists STK push requests so they survive restarts and are shared across replicas.
+// Delivery is at-least-once: a claimed job that is neither acked nor retried before its visibility
+// timeout goes back on the queue.
+type STKPushQueue interface {
+	Enqueue(ctx context.Context, job *STKPushJob) error
+	Claim(ctx context.Context, now time.Time, visibility time.Duration) (*STKPushJob, error) // Nil when nothing is due; Attempts already counts this delivery
+	Ack(ctx context.Context, job *STKPushJob) error                                          // Delivered; drops the job
+	Retry(ctx context.Context, job *STKPushJob, at time.Time) error                          // Back on the queue, due at at
+	DeadLetter(ctx context.Context, job *STKPushJob) error
+	RequeueExpired(ctx context.Context, now time.Time) (int, error) // Returns jobs whose visibility timeout passed to the queue
+	ListDeadLetters(ctx context.Context, limit int) ([]*STKPushJob, error)
+}
+
+// WhatsAppNumberRepository stores the WhatsApp numbers served by this server, and which number each
+// customer last messaged so messages sent outside a conversation come from the same number
+type WhatsAppNumberRepository interface {
+	List(ctx context.Context) ([]*WhatsAppNumber, error)
+	Upsert(ctx context.Context, number *WhatsAppNumber) error
+	Delete(ctx context.Context, phoneNumberID string) error
+	SetContactNumber(ctx context.Context, phone string, phoneNumberID string) error
+	GetContactNumber(ctx context.Context, phone string) (string, error) // "" when the customer hasn't messaged a registered number
+}
+
+// WhatsAppSenderResolver picks the number and access token a message to a customer is sent with; false
+// means the default number from the environment
+type WhatsAppSenderResolver interface {
+	ResolveSender(ctx context.Context, to string) (phoneNumberID string, token string, ok bool)
+}
+
+// OutboundMessageStore persists WhatsApp messages that need a retry, and the ones that ran out of retries
+type OutboundMessageStore interface {
+	ScheduleRetry(ctx context.Context, msg *OutboundMessage) error
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*OutboundMessage, error) // Claimed messages are removed from the retry queue
+	DeadLetter(ctx context.Context, msg *OutboundMessage) error
+	ListDeadLetters(ctx context.Context, limit int) ([]*OutboundMessage, error)
+}
+
+// ErrorReporter sends errors to an error tracker such as Sentry. Implementations must not block the caller.
+type ErrorReporter interface {
+	CaptureException(ctx context.Context, err error, tags map[string]string)
+	CapturePanic(ctx context.Context, recovered interface{}, stack []byte, tags map[string]string)
+	Flush(timeout time.Duration) bool // Waits for queued reports; false if some were still being sent
 }
@@ -0,0 +1,35 @@
+package core
+
+import "context"
+
+// RequestIDHeader carries the request ID on inbound API requests, responses and outbound provider calls
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestIDKey is the context key for the request ID. The HTTP middleware also stores it as a
+// fasthttp user value under this key, so Fiber's c.Context() carries it as well.
+var RequestIDKey = requestIDKey{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, RequestIDKey, requestID)
+}
+
+// RequestIDFrom returns the request ID carried by ctx, or "" when there is none
+func RequestIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
+
+// DetachRequestID returns a background context carrying only ctx's request ID, for work that outlives
+// the request (Fiber recycles its request context once the handler returns)
+func DetachRequestID(ctx context.Context) context.Context {
+	return WithRequestID(context.Background(), RequestIDFrom(ctx))
+}
@@ -0,0 +1,23 @@
+package core
+
+import "context"
+
+type whatsAppNumberKey struct{}
+
+// WithWhatsAppNumber returns a copy of ctx carrying the phone_number_id of the WhatsApp number a customer
+// message arrived on, so replies are sent from it
+func WithWhatsAppNumber(ctx context.Context, phoneNumberID string) context.Context {
+	if phoneNumberID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, whatsAppNumberKey{}, phoneNumberID)
+}
+
+// WhatsAppNumberFrom returns the WhatsApp number carried by ctx, or "" when there is none
+func WhatsAppNumberFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	phoneNumberID, _ := ctx.Value(whatsAppNumberKey{}).(string)
+	return phoneNumberID
+}
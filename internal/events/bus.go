@@ -3,47 +3,204 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
 )
 
 // EventType represents the type of event
 type EventType string
 
 const (
-	EventNewOrder       EventType = "new_order"
-	EventOrderReady     EventType = "order_ready"
-	EventOrderCompleted EventType = "order_completed"
-	EventStockUpdated   EventType = "stock_updated"
-	EventPriceUpdated   EventType = "price_updated"
+	EventNewOrder               EventType = "new_order"
+	EventOrderPartiallyPaid     EventType = "order_partially_paid"
+	EventOrderScheduled         EventType = "order_scheduled"
+	EventOrderReady             EventType = "order_ready"
+	EventOrderCompleted         EventType = "order_completed"
+	EventOrderDispatched        EventType = "order_out_for_delivery"
+	EventRiderAssigned          EventType = "order_rider_assigned"
+	EventOrderDelivered         EventType = "order_delivered"
+	EventOrderRefunded          EventType = "order_refunded"
+	EventPaymentRefundRequired  EventType = "payment_refund_required"
+	EventStockUpdated           EventType = "stock_updated"
+	EventLowStock               EventType = "low_stock"
+	EventPriceUpdated           EventType = "price_updated"
+	EventPickupOverdue          EventType = "pickup_overdue"
+	EventPrepOverdue            EventType = "prep_overdue"
+	EventProductArchived        EventType = "product_archived"
+	EventProductRestocked       EventType = "product_restocked"
+	EventSettingsUpdated        EventType = "settings_updated"
+	EventWhatsAppNumbersUpdated EventType = "whatsapp_numbers_updated"
+	// EventResyncRequired is sent to a single subscriber that fell behind and missed events; it isn't
+	// numbered or replayed, and clients should reload what they show
+	EventResyncRequired EventType = "resync_required"
+)
+
+const (
+	// DefaultReplaySize is how many recent events of each type the bus keeps for clients that reconnect
+	DefaultReplaySize = 100
+	// DefaultSubscriberBuffer is how many events can wait for a subscriber before it starts missing them
+	DefaultSubscriberBuffer = 64
+	// DefaultStallTimeout is how long a subscriber's buffer can stay full before it's disconnected
+	DefaultStallTimeout = time.Minute
 )
 
 // Event represents a server-sent event
 type Event struct {
-	Type EventType   `json:"type"`
-	Data interface{} `json:"data"`
+	ID            uint64      `json:"id,omitempty"` // Increases with every event, across restarts (see EventID); the SSE id a reconnecting client sends back as Last-Event-ID
+	Type          EventType   `json:"type"`
+	Data          interface{} `json:"data"`                 // One of the payloads in payloads.go, or the *core.Order for order events
+	RequestID     string      `json:"request_id,omitempty"` // Request that triggered the event, when there was one
+	SchemaVersion int         `json:"schema_version"`       // Version of Data's shape, also added to the SSE data
+}
+
+// EventID returns the ID for the event after the one numbered last: the current time in microseconds,
+// or last+1 when events come faster than that. IDs keep increasing across restarts, so a dashboard
+// that reconnects after a deploy isn't ahead of the new process.
+func EventID(last uint64, now time.Time) uint64 {
+	if id := uint64(now.UnixMicro()); id > last {
+		return id
+	}
+	return last + 1
+}
+
+// Broker fans events out across API instances (e.g. Redis pub/sub).
+// Every instance, including the publisher, receives published events through Subscribe. Publish assigns
+// the event's ID from a counter shared by every instance (following EventID), so a client can resume on
+// any of them.
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+	Subscribe(ctx context.Context, deliver func(Event)) error
 }
 
 // EventBus manages SSE subscriptions and broadcasts events
 type EventBus struct {
-	subscribers map[string]chan Event
-	mu          sync.RWMutex
+	subscribers  map[string]*subscriber
+	mu           sync.RWMutex
+	broker       Broker
+	lastID       uint64
+	startID      uint64 // EventID when the bus was created; this process never saw events before it
+	replaySize   int
+	history      map[EventType][]Event // The last replaySize events of each type, oldest first
+	trimmedID    map[EventType]uint64  // ID of the newest event of each type dropped from history
+	bufferSize   int
+	stallTimeout time.Duration
+
+	// Counters since startup, reported by SubscriberStats
+	delivered uint64
+	dropped   uint64
+	resyncs   uint64
+	evicted   uint64
+}
+
+// subscriber is one Subscribe call. Events that don't fit in its buffer are counted in missed; once
+// there's room again it gets a resync_required event before anything newer.
+type subscriber struct {
+	ch        chan Event
+	missed    uint64
+	fullSince time.Time // When an event first didn't fit; zero while events are getting through
+	internal  bool      // Never disconnected for stalling (see SubscribeInternal)
 }
 
 // NewEventBus creates a new event bus
 func NewEventBus() *EventBus {
 	return &EventBus{
-		subscribers: make(map[string]chan Event),
+		subscribers:  make(map[string]*subscriber),
+		replaySize:   DefaultReplaySize,
+		history:      make(map[EventType][]Event),
+		trimmedID:    make(map[EventType]uint64),
+		startID:      EventID(0, time.Now()),
+		bufferSize:   DefaultSubscriberBuffer,
+		stallTimeout: DefaultStallTimeout,
+	}
+}
+
+// SetSubscriberLimits sets the buffer of subscribers created from now on and how long a subscriber can
+// go without taking an event from a full buffer before it's disconnected (0 never disconnects)
+func (eb *EventBus) SetSubscriberLimits(bufferSize int, stallTimeout time.Duration) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if bufferSize > 0 {
+		eb.bufferSize = bufferSize
+	}
+	eb.stallTimeout = stallTimeout
+}
+
+// SubscriberStats reports the current number of subscribers and, since startup, the events delivered to
+// subscribers, the events they missed because their buffer was full, the resync_required events sent
+// and the subscribers disconnected for not reading
+func (eb *EventBus) SubscriberStats() (subscribers int, delivered uint64, dropped uint64, resyncs uint64, evicted uint64) {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	return len(eb.subscribers), eb.delivered, eb.dropped, eb.resyncs, eb.evicted
+}
+
+// SetReplaySize sets how many recent events of each type are kept for Since (0 keeps none)
+func (eb *EventBus) SetReplaySize(size int) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.replaySize = size
+	for eventType := range eb.history {
+		eb.trimHistory(eventType)
+	}
+}
+
+// Since returns the kept events with an ID after lastID, oldest first, limited to types when any are
+// given. complete is false when some of the events after lastID can't be replayed: lastID is from
+// before this process started, older than the replay buffer, or ahead of every event seen here. The
+// client should then reload instead of relying on the replay.
+func (eb *EventBus) Since(lastID uint64, types map[EventType]bool) (missed []Event, complete bool) {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	if lastID < eb.startID || lastID > eb.lastID {
+		return nil, false
+	}
+	for eventType, trimmed := range eb.trimmedID {
+		if (len(types) == 0 || types[eventType]) && trimmed > lastID {
+			return nil, false
+		}
+	}
+
+	for eventType, events := range eb.history {
+		if len(types) > 0 && !types[eventType] {
+			continue
+		}
+		for i := len(events) - 1; i >= 0 && events[i].ID > lastID; i-- {
+			missed = append(missed, events[i])
+		}
 	}
+	sort.Slice(missed, func(i, j int) bool { return missed[i].ID < missed[j].ID })
+	return missed, true
 }
 
-// Subscribe adds a new subscriber and returns a channel for receiving events
+// trimHistory drops the oldest events of eventType beyond replaySize, remembering the newest one
+// dropped. Called with eb.mu held.
+func (eb *EventBus) trimHistory(eventType EventType) {
+	events := eb.history[eventType]
+	if len(events) <= eb.replaySize {
+		return
+	}
+	drop := len(events) - eb.replaySize
+	eb.trimmedID[eventType] = events[drop-1].ID
+	eb.history[eventType] = events[drop:]
+}
+
+// Subscribe adds a new subscriber and returns a channel for receiving events. The channel is closed
+// when ctx is done, or when the subscriber stops reading for longer than the stall timeout.
 func (eb *EventBus) Subscribe(ctx context.Context, id string) <-chan Event {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
 	// Create buffered channel to prevent blocking
-	ch := make(chan Event, 10)
-	eb.subscribers[id] = ch
+	ch := make(chan Event, eb.bufferSize)
+	eb.subscribers[id] = &subscriber{ch: ch}
 
 	// Clean up when context is done
 	go func() {
@@ -54,74 +211,308 @@ func (eb *EventBus) Subscribe(ctx context.Context, id string) <-chan Event {
 	return ch
 }
 
+// SubscribeInternal is Subscribe for the server's own listeners, such as caches that drop their contents
+// on change events. They are never disconnected for stalling, so the channel stays open until ctx is
+// done; events that don't fit in the buffer are still missed and followed by resync_required, on which
+// a listener should discard everything it derived from earlier events.
+func (eb *EventBus) SubscribeInternal(ctx context.Context, id string) <-chan Event {
+	ch := eb.Subscribe(ctx, id)
+
+	eb.mu.Lock()
+	if sub, exists := eb.subscribers[id]; exists {
+		sub.internal = true
+	}
+	eb.mu.Unlock()
+
+	return ch
+}
+
 // Unsubscribe removes a subscriber
 func (eb *EventBus) Unsubscribe(id string) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
-	if ch, exists := eb.subscribers[id]; exists {
-		close(ch)
+	if sub, exists := eb.subscribers[id]; exists {
+		close(sub.ch)
 		delete(eb.subscribers, id)
 	}
 }
 
-// Publish sends an event to all subscribers
-func (eb *EventBus) Publish(eventType EventType, data interface{}) {
-	eb.mu.RLock()
-	defer eb.mu.RUnlock()
+// UseBroker routes published events through a distributed broker so SSE clients on every
+// instance receive them. The broker subscription runs until ctx is cancelled.
+func (eb *EventBus) UseBroker(ctx context.Context, broker Broker) {
+	eb.mu.Lock()
+	eb.broker = broker
+	eb.mu.Unlock()
+
+	go func() {
+		if err := broker.Subscribe(ctx, eb.deliver); err != nil && ctx.Err() == nil {
+			log.Printf("Event broker subscription stopped: %v", err)
+		}
+	}()
+}
 
+// Publish sends an event to all subscribers, tagged with ctx's request ID
+func (eb *EventBus) Publish(ctx context.Context, eventType EventType, data interface{}) {
 	event := Event{
-		Type: eventType,
-		Data: data,
+		Type:          eventType,
+		Data:          data,
+		RequestID:     core.RequestIDFrom(ctx),
+		SchemaVersion: SchemaVersion,
+	}
+
+	eb.mu.RLock()
+	broker := eb.broker
+	eb.mu.RUnlock()
+
+	if broker != nil {
+		err := broker.Publish(core.DetachRequestID(ctx), event)
+		if err == nil {
+			// Delivered back to this instance via the broker subscription.
+			return
+		}
+		log.Printf("Event broker publish failed, delivering locally only: %v", err)
 	}
 
+	eb.deliver(event)
+}
+
+// deliver records an event for replay and sends it to this instance's local subscribers. Events
+// published without a broker are numbered here.
+func (eb *EventBus) deliver(event Event) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if event.ID == 0 {
+		event.ID = EventID(eb.lastID, time.Now())
+	}
+	if event.ID > eb.lastID {
+		eb.lastID = event.ID
+	}
+	eb.history[event.Type] = append(eb.history[event.Type], event)
+	eb.trimHistory(event.Type)
+
 	// Send to all subscribers (non-blocking)
-	for _, ch := range eb.subscribers {
+	now := time.Now()
+	for id, sub := range eb.subscribers {
+		if eb.send(sub, event) {
+			continue
+		}
+
+		// Buffer full: the subscriber misses this event and is told to resync once it catches up
+		sub.missed++
+		eb.dropped++
+		if sub.fullSince.IsZero() {
+			sub.fullSince = now
+		} else if !sub.internal && eb.stallTimeout > 0 && now.Sub(sub.fullSince) > eb.stallTimeout {
+			log.Printf("Disconnecting event subscriber %s: no events read for %s, %d missed", id, now.Sub(sub.fullSince).Round(time.Second), sub.missed)
+			close(sub.ch)
+			delete(eb.subscribers, id)
+			eb.evicted++
+		}
+	}
+}
+
+// send puts event in sub's buffer without blocking, preceded by a resync_required event when sub
+// missed events before it. Called with eb.mu held.
+func (eb *EventBus) send(sub *subscriber, event Event) bool {
+	if sub.missed > 0 {
+		resync := Event{
+			Type:          EventResyncRequired,
+			Data:          ResyncRequiredPayload{Reason: ResyncSlowClient, Missed: sub.missed},
+			SchemaVersion: SchemaVersion,
+		}
 		select {
-		case ch <- event:
+		case sub.ch <- resync:
+			sub.missed = 0
+			eb.resyncs++
 		default:
-			// Skip if channel is full (prevents blocking)
+			return false
 		}
 	}
+
+	select {
+	case sub.ch <- event:
+		sub.fullSince = time.Time{}
+		eb.delivered++
+		return true
+	default:
+		return false
+	}
 }
 
 // PublishNewOrder publishes a new order event
-func (eb *EventBus) PublishNewOrder(order interface{}) {
-	eb.Publish(EventNewOrder, order)
+func (eb *EventBus) PublishNewOrder(ctx context.Context, order *core.Order) {
+	eb.Publish(ctx, EventNewOrder, order)
+}
+
+// PublishOrderPartiallyPaid publishes progress on a split bill that isn't fully paid yet
+func (eb *EventBus) PublishOrderPartiallyPaid(ctx context.Context, orderID string, amountPaid float64, totalAmount float64) {
+	eb.Publish(ctx, EventOrderPartiallyPaid, OrderPartiallyPaidPayload{
+		OrderID:     orderID,
+		AmountPaid:  amountPaid,
+		TotalAmount: totalAmount,
+	})
+}
+
+// PublishOrderScheduled publishes a paid pre-order that waits for its time; new_order follows when it's released to the bar
+func (eb *EventBus) PublishOrderScheduled(ctx context.Context, order *core.Order) {
+	eb.Publish(ctx, EventOrderScheduled, order)
 }
 
 // PublishOrderReady publishes an order ready event.
-func (eb *EventBus) PublishOrderReady(order interface{}) {
-	eb.Publish(EventOrderReady, order)
+func (eb *EventBus) PublishOrderReady(ctx context.Context, order *core.Order) {
+	eb.Publish(ctx, EventOrderReady, order)
 }
 
 // PublishOrderCompleted publishes an order completed event
-func (eb *EventBus) PublishOrderCompleted(orderID string) {
-	eb.Publish(EventOrderCompleted, map[string]string{"order_id": orderID})
+func (eb *EventBus) PublishOrderCompleted(ctx context.Context, orderID string) {
+	eb.Publish(ctx, EventOrderCompleted, OrderIDPayload{OrderID: orderID})
+}
+
+// PublishOrderDispatched publishes a delivery order handed to the rider
+func (eb *EventBus) PublishOrderDispatched(ctx context.Context, order *core.Order) {
+	eb.Publish(ctx, EventOrderDispatched, order)
+}
+
+// PublishRiderAssigned publishes a delivery order once a rider has accepted it
+func (eb *EventBus) PublishRiderAssigned(ctx context.Context, order *core.Order) {
+	eb.Publish(ctx, EventRiderAssigned, order)
+}
+
+// PublishOrderDelivered publishes a delivery order handed to the customer
+func (eb *EventBus) PublishOrderDelivered(ctx context.Context, orderID string) {
+	eb.Publish(ctx, EventOrderDelivered, OrderIDPayload{OrderID: orderID})
+}
+
+// PublishOrderRefunded publishes a settled order a manager refunded (and so cancelled)
+func (eb *EventBus) PublishOrderRefunded(ctx context.Context, orderID string, amount float64) {
+	eb.Publish(ctx, EventOrderRefunded, OrderRefundedPayload{
+		OrderID: orderID,
+		Amount:  amount,
+	})
+}
+
+// PublishPaymentRefundRequired flags an M-Pesa payment for an order staff had already cancelled
+func (eb *EventBus) PublishPaymentRefundRequired(ctx context.Context, orderID string, pickupCode string, phone string, amount float64, reference string) {
+	eb.Publish(ctx, EventPaymentRefundRequired, PaymentRefundRequiredPayload{
+		OrderID:       orderID,
+		PickupCode:    pickupCode,
+		CustomerPhone: phone,
+		Amount:        amount,
+		Reference:     reference,
+	})
+}
+
+// PublishPickupOverdue flags a READY order the customer hasn't collected
+func (eb *EventBus) PublishPickupOverdue(ctx context.Context, order *core.Order) {
+	eb.Publish(ctx, EventPickupOverdue, order)
+}
+
+// PublishPrepOverdue warns that a PAID order has waited longer than the preparation SLA
+func (eb *EventBus) PublishPrepOverdue(ctx context.Context, order *core.Order, slaSeconds int) {
+	eb.Publish(ctx, EventPrepOverdue, PrepOverduePayload{
+		Order:      order,
+		SLASeconds: slaSeconds,
+	})
 }
 
 // PublishStockUpdated publishes a stock updated event
-func (eb *EventBus) PublishStockUpdated(productID string, stock int) {
-	eb.Publish(EventStockUpdated, map[string]interface{}{
-		"product_id": productID,
-		"stock":      stock,
+func (eb *EventBus) PublishStockUpdated(ctx context.Context, productID string, stock int) {
+	eb.Publish(ctx, EventStockUpdated, StockUpdatedPayload{
+		ProductID: productID,
+		Stock:     stock,
+	})
+}
+
+// PublishLowStock warns that a product's stock has fallen to the low stock threshold or below
+func (eb *EventBus) PublishLowStock(ctx context.Context, productID string, stock int, threshold int) {
+	eb.Publish(ctx, EventLowStock, LowStockPayload{
+		ProductID: productID,
+		Stock:     stock,
+		Threshold: threshold,
+	})
+}
+
+// PublishPriceUpdated publishes a price updated event with the admin user who changed it
+func (eb *EventBus) PublishPriceUpdated(ctx context.Context, productID string, price float64, actor string, actorName string) {
+	eb.Publish(ctx, EventPriceUpdated, PriceUpdatedPayload{
+		ProductID: productID,
+		Price:     price,
+		Actor:     actor,
+		ActorName: actorName,
 	})
 }
 
-// PublishPriceUpdated publishes a price updated event
-func (eb *EventBus) PublishPriceUpdated(productID string, price float64) {
-	eb.Publish(EventPriceUpdated, map[string]interface{}{
-		"product_id": productID,
-		"price":      price,
+// PublishProductArchived publishes a product archived or unarchived event
+func (eb *EventBus) PublishProductArchived(ctx context.Context, productID string, archived bool) {
+	eb.Publish(ctx, EventProductArchived, ProductArchivedPayload{
+		ProductID: productID,
+		Archived:  archived,
 	})
 }
 
+// PublishProductRestocked announces a sold-out product has stock again
+func (eb *EventBus) PublishProductRestocked(ctx context.Context, productID string, name string, stock int) {
+	eb.Publish(ctx, EventProductRestocked, ProductRestockedPayload{
+		ProductID: productID,
+		Name:      name,
+		Stock:     stock,
+	})
+}
+
+// PublishSettingsUpdated publishes a change to runtime settings such as the ordering pause
+func (eb *EventBus) PublishSettingsUpdated(ctx context.Context, payload SettingsUpdatedPayload) {
+	eb.Publish(ctx, EventSettingsUpdated, payload)
+}
+
+// PublishWhatsAppNumbersUpdated publishes that a WhatsApp number was added, changed or removed. The
+// access token is never included.
+func (eb *EventBus) PublishWhatsAppNumbersUpdated(ctx context.Context, phoneNumberID string) {
+	eb.Publish(ctx, EventWhatsAppNumbersUpdated, WhatsAppNumbersUpdatedPayload{PhoneNumberID: phoneNumberID})
+}
+
 // FormatSSE formats an event as Server-Sent Event string
 func FormatSSE(event Event) (string, error) {
 	data, err := json.Marshal(event.Data)
 	if err != nil {
 		return "", err
 	}
+	data = withEnvelope(data, event.RequestID, event.SchemaVersion)
+
+	id := ""
+	if event.ID > 0 {
+		id = "id: " + strconv.FormatUint(event.ID, 10) + "\n"
+	}
+	return id + "event: " + string(event.Type) + "\ndata: " + string(data) + "\n\n", nil
+}
+
+// withEnvelope adds schema_version and request_id to a JSON object payload, so dashboard clients can
+// check the payload's shape and correlate the event with the API call that caused it. Fields the
+// payload already has, and payloads that aren't objects, are left unchanged.
+func withEnvelope(data []byte, requestID string, schemaVersion int) []byte {
+	if requestID == "" && schemaVersion == 0 {
+		return data
+	}
 
-	return "event: " + string(event.Type) + "\ndata: " + string(data) + "\n\n", nil
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return data
+	}
+	if _, ok := fields["request_id"]; !ok && requestID != "" {
+		encodedID, err := json.Marshal(requestID)
+		if err != nil {
+			return data
+		}
+		fields["request_id"] = encodedID
+	}
+	if _, ok := fields["schema_version"]; !ok && schemaVersion != 0 {
+		fields["schema_version"] = json.RawMessage(strconv.Itoa(schemaVersion))
+	}
+
+	tagged, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return tagged
 }
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSlowSubscriberIsToldToResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewEventBus()
+	bus.SetSubscriberLimits(1, 0)
+	client := bus.Subscribe(ctx, "dashboard")
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(ctx, EventStockUpdated, StockUpdatedPayload{ProductID: "p1", Stock: i})
+	}
+	if event := <-client; event.Type != EventStockUpdated {
+		t.Fatalf("got %s, want the buffered stock_updated", event.Type)
+	}
+
+	// The resync notice takes the free slot ahead of the next event
+	bus.Publish(ctx, EventPriceUpdated, PriceUpdatedPayload{ProductID: "p1", Price: 500})
+	event := <-client
+	resync, ok := event.Data.(ResyncRequiredPayload)
+	if event.Type != EventResyncRequired || !ok || resync.Missed != 2 {
+		t.Fatalf("got %s %+v, want resync_required for 2 missed events", event.Type, event.Data)
+	}
+
+	if subscribers, delivered, dropped, resyncs, evicted := bus.SubscriberStats(); subscribers != 1 || delivered != 1 || dropped != 3 || resyncs != 1 || evicted != 0 {
+		t.Errorf("stats: %d subscribers, %d delivered, %d dropped, %d resyncs, %d evicted; want 1, 1, 3, 1, 0", subscribers, delivered, dropped, resyncs, evicted)
+	}
+}
+
+func TestStalledSubscriberIsEvicted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewEventBus()
+	bus.SetSubscriberLimits(1, time.Nanosecond)
+	client := bus.Subscribe(ctx, "dashboard")
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(ctx, EventStockUpdated, StockUpdatedPayload{ProductID: "p1", Stock: i})
+		time.Sleep(time.Millisecond)
+	}
+
+	// The dashboard stalled past the timeout and was disconnected after its buffered event
+	if event := <-client; event.Type != EventStockUpdated {
+		t.Fatalf("got %s, want the buffered stock_updated", event.Type)
+	}
+	if _, open := <-client; open {
+		t.Fatal("stalled subscriber wasn't disconnected")
+	}
+
+	if subscribers, _, dropped, _, evicted := bus.SubscriberStats(); subscribers != 0 || dropped != 2 || evicted != 1 {
+		t.Errorf("stats: %d subscribers, %d dropped, %d evicted; want 0, 2, 1", subscribers, dropped, evicted)
+	}
+}
+
+func TestInternalSubscribersAreNeverEvicted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewEventBus()
+	bus.SetSubscriberLimits(1, time.Nanosecond)
+	cache := bus.SubscribeInternal(ctx, "cache")
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(ctx, EventStockUpdated, StockUpdatedPayload{ProductID: "p1", Stock: i})
+		time.Sleep(time.Millisecond)
+	}
+
+	// The cache stays subscribed and is told it missed events once it reads again
+	if event := <-cache; event.Type != EventStockUpdated {
+		t.Fatalf("cache got %s, want the buffered stock_updated", event.Type)
+	}
+	bus.Publish(ctx, EventPriceUpdated, PriceUpdatedPayload{ProductID: "p1", Price: 500})
+	event, open := <-cache
+	if !open {
+		t.Fatal("internal subscriber was disconnected")
+	}
+	resync, ok := event.Data.(ResyncRequiredPayload)
+	if event.Type != EventResyncRequired || !ok || resync.Reason != ResyncSlowClient || resync.Missed != 2 {
+		t.Fatalf("cache got %s %+v, want resync_required for 2 missed events", event.Type, event.Data)
+	}
+
+	if subscribers, _, _, _, evicted := bus.SubscriberStats(); subscribers != 1 || evicted != 0 {
+		t.Errorf("stats: %d subscribers, %d evicted; want 1, 0", subscribers, evicted)
+	}
+}
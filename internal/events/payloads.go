@@ -0,0 +1,101 @@
+package events
+
+import "github.com/dumu-tech/destination-cocktails/internal/core"
+
+// SchemaVersion is sent with every event as schema_version. It goes up when a payload below changes in a
+// way an existing dashboard can't read (a field removed, renamed or retyped); added fields keep it.
+const SchemaVersion = 1
+
+// Order events (new_order, order_scheduled, order_ready, order_out_for_delivery, order_rider_assigned,
+// pickup_overdue) carry the *core.Order itself.
+
+// OrderIDPayload is the data of order_completed and order_delivered
+type OrderIDPayload struct {
+	OrderID string `json:"order_id"`
+}
+
+// OrderPartiallyPaidPayload is the data of order_partially_paid
+type OrderPartiallyPaidPayload struct {
+	OrderID     string  `json:"order_id"`
+	AmountPaid  float64 `json:"amount_paid"`
+	TotalAmount float64 `json:"total_amount"`
+}
+
+// OrderRefundedPayload is the data of order_refunded
+type OrderRefundedPayload struct {
+	OrderID string  `json:"order_id"`
+	Amount  float64 `json:"amount"`
+}
+
+// PaymentRefundRequiredPayload is the data of payment_refund_required
+type PaymentRefundRequiredPayload struct {
+	OrderID       string  `json:"order_id"`
+	PickupCode    string  `json:"pickup_code"`
+	CustomerPhone string  `json:"customer_phone"`
+	Amount        float64 `json:"amount"`
+	Reference     string  `json:"reference"`
+}
+
+// PrepOverduePayload is the data of prep_overdue
+type PrepOverduePayload struct {
+	Order      *core.Order `json:"order"`
+	SLASeconds int         `json:"sla_seconds"`
+}
+
+// StockUpdatedPayload is the data of stock_updated
+type StockUpdatedPayload struct {
+	ProductID string `json:"product_id"`
+	Stock     int    `json:"stock"`
+}
+
+// LowStockPayload is the data of low_stock
+type LowStockPayload struct {
+	ProductID string `json:"product_id"`
+	Stock     int    `json:"stock"`
+	Threshold int    `json:"threshold"`
+}
+
+// PriceUpdatedPayload is the data of price_updated
+type PriceUpdatedPayload struct {
+	ProductID string  `json:"product_id"`
+	Price     float64 `json:"price"`
+	Actor     string  `json:"actor"`
+	ActorName string  `json:"actor_name"`
+}
+
+// ProductArchivedPayload is the data of product_archived
+type ProductArchivedPayload struct {
+	ProductID string `json:"product_id"`
+	Archived  bool   `json:"archived"`
+}
+
+// ProductRestockedPayload is the data of product_restocked
+type ProductRestockedPayload struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Stock     int    `json:"stock"`
+}
+
+// SettingsUpdatedPayload is the data of settings_updated: the changed settings from PATCH /settings, or
+// the ordering pause switch
+type SettingsUpdatedPayload struct {
+	Settings interface{}          `json:"settings,omitempty"` // []service.SettingView
+	Ordering *core.OrderingStatus `json:"ordering,omitempty"`
+}
+
+// Reasons a subscriber is sent resync_required
+const (
+	ResyncSlowClient        = "slow_client"        // Events didn't fit in the subscriber's buffer
+	ResyncReplayUnavailable = "replay_unavailable" // Last-Event-ID couldn't be resumed from (see EventBus.Since)
+)
+
+// ResyncRequiredPayload is the data of resync_required
+type ResyncRequiredPayload struct {
+	Reason string `json:"reason"`
+	Missed uint64 `json:"missed"` // Events that didn't fit in the subscriber's buffer
+}
+
+// WhatsAppNumbersUpdatedPayload is the data of whatsapp_numbers_updated
+type WhatsAppNumbersUpdatedPayload struct {
+	PhoneNumberID string `json:"phone_number_id"`
+}
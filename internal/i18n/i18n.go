@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Supported languages
+const (
+	English         = "en"
+	Swahili         = "sw"
+	DefaultLanguage = English
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Bundle holds customer-facing bot strings keyed by language then message key
+type Bundle struct {
+	messages map[string]map[string]string
+}
+
+var (
+	defaultBundle *Bundle
+	defaultOnce   sync.Once
+)
+
+// Load parses every embedded locales/<lang>.json file into a bundle
+func Load() (*Bundle, error) {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locales: %w", err)
+	}
+
+	bundle := &Bundle{messages: make(map[string]map[string]string, len(entries))}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale %s: %w", entry.Name(), err)
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse locale %s: %w", entry.Name(), err)
+		}
+
+		bundle.messages[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+
+	if _, ok := bundle.messages[DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("default locale %s.json is missing", DefaultLanguage)
+	}
+
+	return bundle, nil
+}
+
+// Default returns the bundle built from the embedded locale files (loaded once)
+func Default() *Bundle {
+	defaultOnce.Do(func() {
+		bundle, err := Load()
+		if err != nil {
+			log.Printf("Failed to load i18n bundle, falling back to message keys: %v", err)
+			bundle = &Bundle{messages: map[string]map[string]string{}}
+		}
+		defaultBundle = bundle
+	})
+	return defaultBundle
+}
+
+// T translates key into lang, falling back to English and then to the key itself.
+// args are applied with fmt.Sprintf when present.
+func (b *Bundle) T(lang string, key string, args ...interface{}) string {
+	message, ok := b.messages[lang][key]
+	if !ok {
+		message, ok = b.messages[DefaultLanguage][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Supports reports whether the bundle has a locale for lang
+func (b *Bundle) Supports(lang string) bool {
+	_, ok := b.messages[lang]
+	return ok
+}
+
+// Normalize maps user input such as "Kiswahili" or "english" to a language code.
+// Returns "" when the input isn't a recognised language.
+func Normalize(input string) string {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "en", "eng", "english", "kiingereza":
+		return English
+	case "sw", "swa", "swahili", "kiswahili":
+		return Swahili
+	default:
+		return ""
+	}
+}
+
+// Resolve returns lang when it is supported, otherwise the default language
+func Resolve(lang string) string {
+	if normalized := Normalize(lang); normalized != "" {
+		return normalized
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// RequestIDHandler adds the request_id carried by the context to every record logged with one
+// (slog.InfoContext and friends), so a customer's messages, payment and webhook can be traced together
+type RequestIDHandler struct {
+	slog.Handler
+}
+
+// NewRequestIDHandler wraps next so records include the context's request ID
+func NewRequestIDHandler(next slog.Handler) *RequestIDHandler {
+	return &RequestIDHandler{Handler: next}
+}
+
+// Handle adds request_id when the context has one
+func (h *RequestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := core.RequestIDFrom(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs keeps request IDs on loggers derived with With
+func (h *RequestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &RequestIDHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps request IDs on loggers derived with WithGroup
+func (h *RequestIDHandler) WithGroup(name string) slog.Handler {
+	return &RequestIDHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxAuditBodyBytes keeps CSV imports and other large bodies out of the audit log
+const maxAuditBodyBytes = 16 * 1024
+
+// auditRedactedFields are body fields never written to the audit log
+var auditRedactedFields = map[string]struct{}{
+	"pin":           {},
+	"otp":           {},
+	"code":          {},
+	"password":      {},
+	"token":         {},
+	"refresh_token": {},
+	"secret":        {},
+}
+
+// AuditLog records every POST, PUT, PATCH and DELETE that reaches an admin route: who made it
+// (from the JWT claims), the matched route and its :id, the response status, the request body
+// with secrets redacted, and the client IP. It must run after AuthMiddleware; mounted after
+// Idempotency, replayed responses aren't logged a second time. A failure to record is logged
+// and never fails the request.
+func AuditLog(repo core.AuditLogRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		handlerErr := c.Next()
+
+		// Unmatched paths fall back to the group's own route; there's nothing to audit
+		route := c.Route().Path
+		entityType := auditEntityType(route)
+		if entityType == "" {
+			return handlerErr
+		}
+
+		status := c.Response().StatusCode()
+		if handlerErr != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := handlerErr.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		actor, _ := c.Locals("user_id").(string)
+		name, _ := c.Locals("name").(string)
+		role, _ := c.Locals("role").(string)
+		entry := &core.AuditLog{
+			Actor:      actor,
+			ActorName:  name,
+			Role:       role,
+			Method:     c.Method(),
+			Route:      route,
+			Path:       c.Path(),
+			EntityType: entityType,
+			EntityID:   auditEntityID(c, status),
+			StatusCode: status,
+			Changes:    auditChanges(c),
+			IP:         auditClientIP(c),
+			RequestID:  RequestIDOf(c),
+		}
+		if err := repo.Record(c.UserContext(), entry); err != nil {
+			log.Printf("Failed to record audit log for %s %s: %v", entry.Method, entry.Path, err)
+		}
+		return handlerErr
+	}
+}
+
+// auditEntityType is the first segment after /api/admin, e.g. "products" for /api/admin/products/:id/price
+func auditEntityType(route string) string {
+	rest := strings.TrimPrefix(strings.TrimPrefix(route, "/api/admin"), "/")
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// auditEntityID is the route's :id, or for a successful create the "id" of the object returned
+func auditEntityID(c *fiber.Ctx, status int) string {
+	if id := c.Params("id"); id != "" {
+		return id
+	}
+	if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices ||
+		!strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return ""
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(c.Response().Body(), &created); err != nil || len(created.ID) > 64 {
+		return ""
+	}
+	return created.ID
+}
+
+// auditChanges returns the JSON request body with secret fields redacted. Non-JSON bodies (CSV
+// uploads) and oversized ones are summarised rather than stored.
+func auditChanges(c *fiber.Ctx) json.RawMessage {
+	body := bytes.TrimSpace(c.Body())
+	if len(body) == 0 {
+		return nil
+	}
+
+	if len(body) > maxAuditBodyBytes || !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		summary, _ := json.Marshal(map[string]interface{}{
+			"content_type": c.Get(fiber.HeaderContentType),
+			"bytes":        len(body),
+		})
+		return summary
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(redactAuditValue(decoded))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redactAuditValue replaces secret fields at any depth with "[redacted]"
+func redactAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if _, secret := auditRedactedFields[strings.ToLower(key)]; secret {
+				v[key] = "[redacted]"
+				continue
+			}
+			v[key] = redactAuditValue(field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactAuditValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// auditClientIP prefers the first X-Forwarded-For hop, since the API runs behind a proxy
+func auditClientIP(c *fiber.Ctx) string {
+	if ips := c.IPs(); len(ips) > 0 {
+		return ips[0]
+	}
+	return c.IP()
+}
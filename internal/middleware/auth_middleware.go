@@ -38,11 +38,15 @@ func AuthMiddleware(dashboardService *service.DashboardService) fiber.Handler {
 			})
 		}
 
-		// Validate token
-		claims, err := dashboardService.ValidateJWT(token)
+		// Validate token and make sure it hasn't been revoked
+		claims, err := dashboardService.AuthorizeToken(c.Context(), token)
 		if err != nil {
+			message := "unauthorized: invalid token"
+			if strings.HasPrefix(err.Error(), "unauthorized:") {
+				message = err.Error()
+			}
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "unauthorized: invalid token",
+				"error": message,
 			})
 		}
 
@@ -50,7 +54,9 @@ func AuthMiddleware(dashboardService *service.DashboardService) fiber.Handler {
 		c.Locals("user_id", fmt.Sprintf("%v", claims["user_id"]))
 		c.Locals("phone", fmt.Sprintf("%v", claims["phone"]))
 		c.Locals("name", fmt.Sprintf("%v", claims["name"]))
-		c.Locals("role", strings.ToUpper(strings.TrimSpace(fmt.Sprintf("%v", claims["role"]))))
+		// A missing role claim must stay empty rather than become "<nil>", so RequireRoles rejects it as such
+		role, _ := claims["role"].(string)
+		c.Locals("role", strings.ToUpper(strings.TrimSpace(role)))
 
 		return c.Next()
 	}
@@ -67,7 +73,8 @@ func RequireRoles(allowedRoles ...string) fiber.Handler {
 	}
 
 	return func(c *fiber.Ctx) error {
-		role := strings.ToUpper(strings.TrimSpace(fmt.Sprintf("%v", c.Locals("role"))))
+		role, _ := c.Locals("role").(string)
+		role = strings.ToUpper(strings.TrimSpace(role))
 		if role == "" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "forbidden: role not found in token",
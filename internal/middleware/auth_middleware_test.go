@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireRoles(t *testing.T) {
+	app := fiber.New()
+	// Stands in for AuthMiddleware: the role claim comes from the X-Role header
+	app.Use(func(c *fiber.Ctx) error {
+		if role, ok := c.GetReqHeaders()["X-Role"]; ok {
+			c.Locals("role", role[0])
+		}
+		return c.Next()
+	})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/manager", RequireRoles("MANAGER"), ok)
+	app.Get("/shared", RequireRoles(" manager ", "Bartender", ""), ok)
+
+	for _, tc := range []struct {
+		path string
+		role *string
+		want int
+	}{
+		{path: "/manager", role: strPtr("MANAGER"), want: fiber.StatusOK},
+		{path: "/manager", role: strPtr("manager"), want: fiber.StatusOK},
+		{path: "/manager", role: strPtr("BARTENDER"), want: fiber.StatusForbidden},
+		{path: "/manager", want: fiber.StatusForbidden},
+		{path: "/manager", role: strPtr("  "), want: fiber.StatusForbidden},
+		{path: "/shared", role: strPtr("MANAGER"), want: fiber.StatusOK},
+		{path: "/shared", role: strPtr("BARTENDER"), want: fiber.StatusOK},
+		{path: "/shared", role: strPtr("RIDER"), want: fiber.StatusForbidden},
+		{path: "/shared", want: fiber.StatusForbidden},
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, tc.path, nil)
+		role := "(none)"
+		if tc.role != nil {
+			role = *tc.role
+			req.Header.Set("X-Role", *tc.role)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("role %q on %s got %d, want %d", role, tc.path, resp.StatusCode, tc.want)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestWebSocketOrigin(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		origins []string
+		origin  string
+		cookie  bool
+		want    int
+	}{
+		{name: "no origin", origins: []string{"https://dash.example.com"}, cookie: true, want: fiber.StatusOK},
+		{name: "same origin", origins: []string{"https://dash.example.com"}, origin: "https://api.example.com", cookie: true, want: fiber.StatusOK},
+		{name: "listed origin", origins: []string{"https://dash.example.com"}, origin: "https://dash.example.com", cookie: true, want: fiber.StatusOK},
+		{name: "origin case", origins: []string{"https://dash.example.com"}, origin: "HTTPS://Dash.Example.com", cookie: true, want: fiber.StatusOK},
+		{name: "subdomain wildcard", origins: []string{"https://*.example.com"}, origin: "https://bar.example.com", cookie: true, want: fiber.StatusOK},
+		{name: "wildcard scheme", origins: []string{"https://*.example.com"}, origin: "http://bar.example.com", cookie: true, want: fiber.StatusForbidden},
+		{name: "wildcard suffix", origins: []string{"https://*.example.com"}, origin: "https://evilexample.com", cookie: true, want: fiber.StatusForbidden},
+		{name: "other origin", origins: []string{"https://dash.example.com"}, origin: "https://evil.test", want: fiber.StatusForbidden},
+		{name: "allow all without cookie", origins: []string{"*"}, origin: "https://evil.test", want: fiber.StatusOK},
+		{name: "allow all with cookie", origins: []string{"*"}, origin: "https://evil.test", cookie: true, want: fiber.StatusForbidden},
+		{name: "no origins with cookie", origin: "https://evil.test", cookie: true, want: fiber.StatusForbidden},
+	} {
+		app := fiber.New()
+		app.Get("/ws", WebSocketOrigin(tc.origins), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "https://api.example.com/ws", nil)
+		if tc.origin != "" {
+			req.Header.Set(fiber.HeaderOrigin, tc.origin)
+		}
+		if tc.cookie {
+			req.Header.Set(fiber.HeaderCookie, "auth_token=jwt")
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, resp.StatusCode, tc.want)
+		}
+	}
+}
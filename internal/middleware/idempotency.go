@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// IdempotencyKeyHeader lets dashboard clients retry a mutation without applying it twice
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader marks a response replayed from the store rather than handled again
+	idempotentReplayHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long an in-flight request blocks its retries if the server dies mid-request
+	idempotencyLockTTL = time.Minute
+)
+
+// Idempotency replays the stored response when a POST or PATCH repeats an Idempotency-Key.
+// Keys are scoped to the authenticated user, so it must run after AuthMiddleware. Requests without
+// the header are handled as usual, and 5xx responses aren't stored so the client can retry them.
+func Idempotency(store core.IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodPost && c.Method() != fiber.MethodPatch {
+			return c.Next()
+		}
+
+		key := strings.TrimSpace(c.Get(IdempotencyKeyHeader))
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		userID, _ := c.Locals("user_id").(string)
+		scopedKey := userID + ":" + key
+		hash := idempotencyRequestHash(c)
+
+		reserved, err := store.Reserve(c.Context(), scopedKey, hash, idempotencyLockTTL)
+		if err != nil {
+			// Don't block the bar when Redis is unavailable; the request just isn't protected
+			log.Printf("Idempotency store unavailable, handling %s %s without replay protection: %v", c.Method(), c.Path(), err)
+			return c.Next()
+		}
+		if !reserved {
+			return replayIdempotentResponse(c, store, scopedKey, hash)
+		}
+
+		if err := c.Next(); err != nil {
+			if releaseErr := store.Release(c.Context(), scopedKey); releaseErr != nil {
+				log.Printf("Failed to release idempotency key after error: %v", releaseErr)
+			}
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			if err := store.Release(c.Context(), scopedKey); err != nil {
+				log.Printf("Failed to release idempotency key after %d response: %v", status, err)
+			}
+			return nil
+		}
+
+		response := &core.IdempotentResponse{
+			RequestHash: hash,
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+			CreatedAt:   time.Now(),
+		}
+		if err := store.Complete(c.Context(), scopedKey, response, ttl); err != nil {
+			log.Printf("Failed to store idempotent response for %s %s: %v", c.Method(), c.Path(), err)
+		}
+		return nil
+	}
+}
+
+// replayIdempotentResponse answers a request whose key is already in use: the stored response when
+// the first request has finished, otherwise a conflict
+func replayIdempotentResponse(c *fiber.Ctx, store core.IdempotencyStore, key string, hash string) error {
+	existing, err := store.Get(c.Context(), key)
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "a request with this Idempotency-Key is still being processed",
+		})
+	}
+
+	if existing.RequestHash != hash {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "Idempotency-Key was already used for a different request",
+		})
+	}
+
+	if !existing.Completed {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "a request with this Idempotency-Key is still being processed",
+		})
+	}
+
+	c.Set(idempotentReplayHeader, "true")
+	if existing.ContentType != "" {
+		c.Set(fiber.HeaderContentType, existing.ContentType)
+	}
+	return c.Status(existing.StatusCode).Send(existing.Body)
+}
+
+// idempotencyRequestHash fingerprints the method, URL and body so a key can't be reused for a different request
+func idempotencyRequestHash(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.OriginalURL() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds client-supplied X-Request-ID values before they reach logs and providers
+const maxRequestIDLength = 128
+
+// RequestID assigns every request an ID, honouring a well-formed X-Request-ID from the client.
+// The ID is stored in the request context (see core.RequestIDFrom), echoed in the X-Request-ID
+// response header and added as "request_id" to JSON error responses.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := strings.TrimSpace(c.Get(core.RequestIDHeader))
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Locals(core.RequestIDKey, requestID)
+		c.SetUserContext(core.WithRequestID(c.UserContext(), requestID))
+		c.Set(core.RequestIDHeader, requestID)
+
+		if err := c.Next(); err != nil {
+			// The app's ErrorHandler writes this response and adds the request ID itself
+			return err
+		}
+
+		if c.Response().StatusCode() >= fiber.StatusBadRequest {
+			addRequestIDToError(c, requestID)
+		}
+		return nil
+	}
+}
+
+// RequestIDOf returns the request ID assigned by RequestID
+func RequestIDOf(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(core.RequestIDKey).(string)
+	return requestID
+}
+
+// addRequestIDToError adds request_id to a {"error": ...} JSON body so it can be quoted in support requests
+func addRequestIDToError(c *fiber.Ctx, requestID string) {
+	if !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(c.Response().Body(), &body); err != nil {
+		return
+	}
+	if _, ok := body["error"]; !ok {
+		return
+	}
+	if _, ok := body["request_id"]; ok {
+		return
+	}
+
+	encodedID, err := json.Marshal(requestID)
+	if err != nil {
+		return
+	}
+	body["request_id"] = encodedID
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+	c.Response().SetBodyRaw(data)
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII without spaces, so they are safe to log and forward
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxWebhookResponseBytes bounds the endpoint response kept with each archived webhook
+const maxWebhookResponseBytes = 4 * 1024
+
+// webhookSkippedHeaders are request headers never archived
+var webhookSkippedHeaders = map[string]struct{}{
+	"authorization": {},
+	"cookie":        {},
+}
+
+// WebhookArchive stores every request to a webhook endpoint in webhook_events: the raw body, the
+// request headers, the signature verification result the handler left in the
+// "webhook_signature_valid" local (absent when the source isn't verified) and the status and body
+// the endpoint responded with. Rejected requests are archived too. A failure to record is logged and
+// never fails the webhook.
+func WebhookArchive(repo core.WebhookEventRepository, source string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Copied up front: fasthttp reuses the request buffer once the handler returns
+		body := string(c.Body())
+
+		handlerErr := c.Next()
+
+		status := c.Response().StatusCode()
+		if handlerErr != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := handlerErr.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		headers := make(map[string]string)
+		for name, values := range c.GetReqHeaders() {
+			if _, skip := webhookSkippedHeaders[strings.ToLower(name)]; skip {
+				continue
+			}
+			headers[name] = strings.Join(values, ", ")
+		}
+
+		response := string(c.Response().Body())
+		if len(response) > maxWebhookResponseBytes {
+			response = response[:maxWebhookResponseBytes]
+		}
+
+		event := &core.WebhookEvent{
+			Source:     source,
+			Headers:    headers,
+			Body:       body,
+			StatusCode: status,
+			Response:   response,
+			IP:         auditClientIP(c),
+			RequestID:  RequestIDOf(c),
+		}
+		if valid, ok := c.Locals("webhook_signature_valid").(bool); ok {
+			event.SignatureValid = &valid
+		}
+		if err := repo.Record(c.UserContext(), event); err != nil {
+			log.Printf("Failed to archive %s webhook: %v", source, err)
+		}
+		return handlerErr
+	}
+}
@@ -0,0 +1,63 @@
+// Package msisdn normalizes Kenyan phone numbers so the bot, dashboard and message gateways
+// (WhatsApp, SMS, M-Pesa) agree on one format.
+package msisdn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize converts a Kenyan phone number to +254xxxxxxxxx (E.164).
+// Supports: 07..., 01..., 254..., +254..., 7..., 1..., with spaces, dashes or brackets.
+func Normalize(phone string) (string, error) {
+	// Remove spaces, dashes and brackets
+	phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(phone)
+	phone = strings.TrimSpace(phone)
+
+	// Remove leading +
+	phone = strings.TrimPrefix(phone, "+")
+
+	for _, c := range phone {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("invalid phone number format")
+		}
+	}
+
+	// Handle different formats
+	if strings.HasPrefix(phone, "254") {
+		// Already in 254xxxxxxxxx format
+		if len(phone) == 12 {
+			return "+" + phone, nil
+		}
+		return "", fmt.Errorf("invalid phone number format")
+	} else if strings.HasPrefix(phone, "07") || strings.HasPrefix(phone, "01") {
+		// 07xxxxxxxx or 01xxxxxxxx -> +2547xxxxxxxx or +2541xxxxxxxx
+		if len(phone) == 10 {
+			return "+254" + phone[1:], nil
+		}
+		return "", fmt.Errorf("invalid phone number format")
+	} else if strings.HasPrefix(phone, "7") || strings.HasPrefix(phone, "1") {
+		// 7xxxxxxxx or 1xxxxxxxx -> +2547xxxxxxxx or +2541xxxxxxxx
+		if len(phone) == 9 {
+			return "+254" + phone, nil
+		}
+		return "", fmt.Errorf("invalid phone number format")
+	}
+
+	return "", fmt.Errorf("unsupported phone number format")
+}
+
+// IsKenyanMobile validates that a normalized phone starts with +2547 or +2541
+func IsKenyanMobile(normalizedPhone string) bool {
+	return strings.HasPrefix(normalizedPhone, "+2547") || strings.HasPrefix(normalizedPhone, "+2541")
+}
+
+// WhatsAppID converts a phone number to the WhatsApp wa_id format (254xxxxxxxxx), which is also
+// the MSISDN format M-Pesa expects
+func WhatsAppID(phone string) (string, error) {
+	normalized, err := Normalize(phone)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(normalized, "+"), nil
+}
@@ -0,0 +1,42 @@
+package msisdn
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		phone   string
+		want    string
+		wantErr bool
+	}{
+		{phone: "0712345678", want: "+254712345678"},
+		{phone: "0112345678", want: "+254112345678"},
+		{phone: "254712345678", want: "+254712345678"},
+		{phone: "+254 712-345-678", want: "+254712345678"},
+		{phone: "(0712) 345 678", want: "+254712345678"},
+		{phone: "712345678", want: "+254712345678"},
+		{phone: "07123456", wantErr: true},
+		{phone: "25471234567", wantErr: true},
+		{phone: "0712abc678", wantErr: true},
+		{phone: "0812345678", wantErr: true},
+	} {
+		got, err := Normalize(tc.phone)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("Normalize(%q) = %q, want an error", tc.phone, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tc.phone, got, err, tc.want)
+		}
+	}
+}
+
+func TestWhatsAppID(t *testing.T) {
+	if got, err := WhatsAppID("0712345678"); err != nil || got != "254712345678" {
+		t.Errorf("WhatsAppID = %q, %v; want 254712345678", got, err)
+	}
+	if !IsKenyanMobile("+254712345678") || IsKenyanMobile("+255712345678") {
+		t.Error("IsKenyanMobile should accept +2547 and reject other country codes")
+	}
+}
@@ -0,0 +1,88 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// reporterBox lets atomic.Value hold an interface that may change implementation
+type reporterBox struct {
+	reporter core.ErrorReporter
+}
+
+var current atomic.Value // reporterBox
+
+// SetReporter sends captured errors and panics to reporter as well as the logs.
+// Passing nil goes back to logging only.
+func SetReporter(reporter core.ErrorReporter) {
+	current.Store(reporterBox{reporter: reporter})
+}
+
+// Flush waits up to timeout for reports still being sent, e.g. before the process exits
+func Flush(timeout time.Duration) bool {
+	if reporter := activeReporter(); reporter != nil {
+		return reporter.Flush(timeout)
+	}
+	return true
+}
+
+// CaptureError logs err and reports it, tagged with the operation that failed (e.g. "bar_staff.notify").
+// Nil errors are ignored so it can wrap a call directly.
+func CaptureError(ctx context.Context, operation string, err error) {
+	if err == nil {
+		return
+	}
+
+	slog.ErrorContext(ctx, "Background operation failed", "operation", operation, "error", err.Error())
+	if reporter := activeReporter(); reporter != nil {
+		reporter.CaptureException(ctx, err, tagsFor(ctx, operation))
+	}
+}
+
+// CapturePanic logs and reports a recovered panic with the stack of the goroutine that panicked.
+// Call it from the deferred function that recovered.
+func CapturePanic(ctx context.Context, operation string, recovered interface{}) {
+	stack := debug.Stack()
+	slog.ErrorContext(ctx, "Recovered from panic",
+		"operation", operation,
+		"panic", fmt.Sprint(recovered),
+		"stack", string(stack))
+	if reporter := activeReporter(); reporter != nil {
+		reporter.CapturePanic(ctx, recovered, stack, tagsFor(ctx, operation))
+	}
+}
+
+// Recover reports a panic instead of letting it crash the server. Use it as `defer reporting.Recover(ctx, "op")`.
+func Recover(ctx context.Context, operation string) {
+	if recovered := recover(); recovered != nil {
+		CapturePanic(ctx, operation, recovered)
+	}
+}
+
+// Go runs fn in a fire-and-forget goroutine. A returned error or a panic is logged and reported
+// under operation, instead of only being printed (or taking the whole server down).
+func Go(ctx context.Context, operation string, fn func(ctx context.Context) error) {
+	go func() {
+		defer Recover(ctx, operation)
+		CaptureError(ctx, operation, fn(ctx))
+	}()
+}
+
+func activeReporter() core.ErrorReporter {
+	box, _ := current.Load().(reporterBox)
+	return box.reporter
+}
+
+func tagsFor(ctx context.Context, operation string) map[string]string {
+	tags := map[string]string{"operation": operation}
+	if requestID := core.RequestIDFrom(ctx); requestID != "" {
+		tags["request_id"] = requestID
+	}
+	return tags
+}
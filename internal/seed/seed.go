@@ -0,0 +1,227 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Seed files bundled into the binary from seeds/, so the seeders work without the repo checked out
+const (
+	MenuFile    = "menu.json"
+	ChasersFile = "chasers.json"
+)
+
+//go:embed seeds/*.json
+var seedFiles embed.FS
+
+// Item is one product in a seed file
+type Item struct {
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Category    string  `json:"category"`
+	Stock       int     `json:"stock"`
+	Description string  `json:"description,omitempty"`
+}
+
+// Options controls how Upsert treats products that already exist
+type Options struct {
+	DryRun bool // Log what would change without writing
+	// Restore also resets the category and re-activates (un-archives) existing products,
+	// for seed files that bring back previously removed items
+	Restore bool
+}
+
+// Result counts what Upsert did (or would do on a dry run)
+type Result struct {
+	Inserted int
+	Updated  int
+}
+
+// LoadEmbedded parses a seed file bundled with the binary, e.g. MenuFile
+func LoadEmbedded(name string) ([]Item, error) {
+	data, err := fs.ReadFile(seedFiles, path.Join("seeds", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file %s: %w", name, err)
+	}
+	return parse(name, data)
+}
+
+// LoadFile parses a seed file from disk. JSON files hold an array of items;
+// CSV files use the product export header (name, price, category, stock, description).
+func LoadFile(filename string) ([]Item, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file %s: %w", filename, err)
+	}
+	return parse(filename, data)
+}
+
+func parse(name string, data []byte) ([]Item, error) {
+	var items []Item
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse seed file %s: %w", name, err)
+		}
+	case ".csv":
+		parsed, err := parseCSV(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse seed file %s: %w", name, err)
+		}
+		items = parsed
+	default:
+		return nil, fmt.Errorf("unsupported seed file %s: expected .json or .csv", name)
+	}
+
+	for i, item := range items {
+		if strings.TrimSpace(item.Name) == "" || strings.TrimSpace(item.Category) == "" || item.Price <= 0 || item.Stock < 0 {
+			return nil, fmt.Errorf("invalid item %d in seed file %s: name, category, a positive price and non-negative stock are required", i+1, name)
+		}
+	}
+	return items, nil
+}
+
+func parseCSV(data io.Reader) ([]Item, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	for _, required := range []string{"name", "price", "category", "stock"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %q column", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var items []Item
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		price, err := strconv.ParseFloat(field(record, "price"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, field(record, "price"))
+		}
+		stock, err := strconv.Atoi(field(record, "stock"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid stock %q", line, field(record, "stock"))
+		}
+
+		items = append(items, Item{
+			Name:        field(record, "name"),
+			Price:       price,
+			Category:    field(record, "category"),
+			Stock:       stock,
+			Description: field(record, "description"),
+		})
+	}
+	return items, nil
+}
+
+// Upsert updates products matched by name (price and stock) and inserts the rest.
+// All writes happen in one transaction.
+func Upsert(ctx context.Context, db *gorm.DB, items []Item, opts Options) (Result, error) {
+	var result Result
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			var existingID string
+			if err := tx.Table("products").
+				Select("id").
+				Where("name = ?", item.Name).
+				Limit(1).
+				Scan(&existingID).Error; err != nil {
+				return fmt.Errorf("failed to check existing product %s: %w", item.Name, err)
+			}
+
+			if existingID != "" {
+				result.Updated++
+				if opts.DryRun {
+					log.Printf("  Would update: %s (price %.2f, stock %d)", item.Name, item.Price, item.Stock)
+					continue
+				}
+
+				updates := map[string]interface{}{
+					"price":          item.Price,
+					"stock_quantity": item.Stock,
+					"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+				}
+				if item.Description != "" {
+					updates["description"] = item.Description
+				}
+				if opts.Restore {
+					updates["category"] = item.Category
+					updates["is_active"] = true
+					updates["archived_at"] = nil
+				}
+				if err := tx.Table("products").Where("id = ?", existingID).Updates(updates).Error; err != nil {
+					return fmt.Errorf("failed to update product %s: %w", item.Name, err)
+				}
+				log.Printf("  Updated: %s", item.Name)
+				continue
+			}
+
+			result.Inserted++
+			if opts.DryRun {
+				log.Printf("  Would insert: %s (%s, price %.2f, stock %d)", item.Name, item.Category, item.Price, item.Stock)
+				continue
+			}
+
+			product := map[string]interface{}{
+				"id":             uuid.New().String(),
+				"name":           item.Name,
+				"description":    item.Description,
+				"price":          item.Price,
+				"category":       item.Category,
+				"stock_quantity": item.Stock,
+				"image_url":      "",
+				"is_active":      true,
+			}
+			if err := tx.Table("products").Create(product).Error; err != nil {
+				return fmt.Errorf("failed to insert product %s: %w", item.Name, err)
+			}
+			log.Printf("  Inserted: %s", item.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
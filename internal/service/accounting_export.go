@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+var accountingExportCSVHeader = []string{
+	"Date",
+	"Reference",
+	"Description",
+	"Payment Method",
+	"Gross",
+	"Tax",
+	"Fees",
+	"Amount",
+}
+
+// GenerateAccountingExport renders settled orders and refunds placed in business dates from..to
+// (YYYY-MM-DD, inclusive; default the last 30 business days) as a journal-style CSV for QuickBooks or
+// Xero bank imports: one line per order, refunds as negative lines on the day they were made.
+// Fees are the accounting.mpesa_fee_basis_points charge on M-Pesa sales; Amount is Gross less Fees.
+func (s *DashboardService) GenerateAccountingExport(ctx context.Context, from string, to string) ([]byte, string, error) {
+	loc := reportLocation()
+	start, end, err := businessDateRange(from, to, 30, s.clock.Now().In(loc), loc, s.businessDayStartHour(ctx))
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, err := s.analyticsRepo.GetAccountingEntries(ctx, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, "", err
+	}
+
+	feeBasisPoints := 0
+	if s.settings != nil {
+		feeBasisPoints = s.settings.Int(ctx, SettingMpesaFeeBasisPoints)
+	}
+	data, err := renderAccountingExportCSV(entries, feeBasisPoints, loc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("accounting-%s-to-%s.csv", start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	return data, filename, nil
+}
+
+// renderAccountingExportCSV writes dates as DD/MM/YYYY and plain two-decimal amounts, which both
+// QuickBooks and Xero read for Kenyan accounts
+func renderAccountingExportCSV(entries []*core.AccountingEntry, feeBasisPoints int, loc *time.Location) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write(accountingExportCSVHeader); err != nil {
+		return nil, fmt.Errorf("failed to render CSV: %w", err)
+	}
+	for _, entry := range entries {
+		method := entry.PaymentMethod
+		if method == "" {
+			method = string(core.PaymentMethodMpesa)
+		}
+		reference := entry.PaymentRef
+		if reference == "" {
+			reference = entry.PickupCode
+		}
+
+		description := fmt.Sprintf("Order %s", entry.PickupCode)
+		fees := 0.0
+		if entry.Refund {
+			description = fmt.Sprintf("Refund of order %s", entry.PickupCode)
+			if entry.Note != "" {
+				description += ": " + entry.Note
+			}
+		} else if method == string(core.PaymentMethodMpesa) {
+			fees = roundCents(entry.Gross * float64(feeBasisPoints) / 10000)
+		}
+
+		if err := writer.Write([]string{
+			entry.Date.In(loc).Format("02/01/2006"),
+			reference,
+			description,
+			method,
+			formatCSVAmount(entry.Gross),
+			formatCSVAmount(entry.Tax),
+			formatCSVAmount(fees),
+			formatCSVAmount(roundCents(entry.Gross - fees)),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to render CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to render CSV: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// RefundOrder records that a settled order's money was returned to the customer (e.g. an M-Pesa reversal
+// made from the till portal) by cancelling the order with reason in its status history. The accounting
+// export then lists the refund as a negative line. Stock isn't put back.
+func (s *DashboardService) RefundOrder(ctx context.Context, orderID string, actorUserID string, reason string) (*core.Order, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	settled := false
+	for _, status := range settledSalesStatuses {
+		if order.Status == status {
+			settled = true
+			break
+		}
+	}
+	if !settled {
+		return nil, fmt.Errorf("only paid orders can be refunded")
+	}
+
+	if err := s.orderRepo.UpdateStatusWithNote(ctx, orderID, core.OrderStatusCancelled, actorUserID, reason); err != nil {
+		return nil, fmt.Errorf("failed to refund order: %w", err)
+	}
+	order.Status = core.OrderStatusCancelled
+
+	s.eventBus.PublishOrderRefunded(ctx, order.ID, order.TotalAmount)
+	return order, nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AdminUserInput holds the fields for creating a dashboard user
+type AdminUserInput struct {
+	Name        string
+	PhoneNumber string
+	Role        string
+	PIN         string // optional 4-digit PIN for bartender login
+}
+
+// AdminUserUpdate holds optional fields for updating a dashboard user
+type AdminUserUpdate struct {
+	Name        *string
+	PhoneNumber *string
+	Role        *string
+	IsActive    *bool
+}
+
+// ListAdminUsers retrieves all dashboard users (managers and bartenders)
+func (s *DashboardService) ListAdminUsers(ctx context.Context) ([]*core.AdminUser, error) {
+	return s.adminUserRepo.GetAll(ctx)
+}
+
+// CreateAdminUser adds a manager or bartender account
+func (s *DashboardService) CreateAdminUser(ctx context.Context, input AdminUserInput) (*core.AdminUser, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	normalizedPhone, err := normalizeStaffPhone(input.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// New accounts default to the least-privileged role.
+	role := core.AdminRoleBartender
+	if strings.TrimSpace(input.Role) != "" {
+		role, err = normalizeAdminRole(input.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	user := &core.AdminUser{
+		ID:          s.ids.NewID(),
+		PhoneNumber: normalizedPhone,
+		Name:        name,
+		Role:        role,
+		IsActive:    true,
+		CreatedAt:   s.clock.Now(),
+	}
+
+	if input.PIN != "" {
+		pinHash, err := s.hashAdminPIN(ctx, input.PIN, "")
+		if err != nil {
+			return nil, err
+		}
+		user.PinHash = pinHash
+	}
+
+	if err := s.adminUserRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateAdminUser applies a partial update to a dashboard user (name, phone, role, active).
+// Managers can't demote or deactivate themselves, so the dashboard can't lose its last manager by accident.
+func (s *DashboardService) UpdateAdminUser(ctx context.Context, id string, actorUserID string, update AdminUserUpdate) (*core.AdminUser, error) {
+	user, err := s.adminUserRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		user.Name = name
+	}
+
+	if update.PhoneNumber != nil {
+		normalizedPhone, err := normalizeStaffPhone(*update.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		user.PhoneNumber = normalizedPhone
+	}
+
+	// Deactivation and role changes end every session the user has open
+	revokeSessions := false
+	if update.Role != nil {
+		role, err := normalizeAdminRole(*update.Role)
+		if err != nil {
+			return nil, err
+		}
+		if id == actorUserID && role != user.Role {
+			return nil, fmt.Errorf("forbidden: you cannot change your own role")
+		}
+		revokeSessions = revokeSessions || role != user.Role
+		user.Role = role
+	}
+
+	if update.IsActive != nil {
+		if id == actorUserID && !*update.IsActive {
+			return nil, fmt.Errorf("forbidden: you cannot deactivate your own account")
+		}
+		revokeSessions = revokeSessions || (user.IsActive && !*update.IsActive)
+		user.IsActive = *update.IsActive
+	}
+
+	if err := s.adminUserRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if revokeSessions {
+		if err := s.adminUserRepo.BumpTokenVersion(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.TokenVersion++
+		s.revokeUserSessions(ctx, user.ID)
+	}
+
+	return user, nil
+}
+
+// DeactivateAdminUser disables a dashboard user (order audit history keeps referencing the account)
+func (s *DashboardService) DeactivateAdminUser(ctx context.Context, id string, actorUserID string) error {
+	inactive := false
+	_, err := s.UpdateAdminUser(ctx, id, actorUserID, AdminUserUpdate{IsActive: &inactive})
+	return err
+}
+
+// SetAdminUserPIN sets or resets the 4-digit PIN used for bartender login.
+// An empty PIN removes PIN login for the account.
+func (s *DashboardService) SetAdminUserPIN(ctx context.Context, id string, pin string) error {
+	if _, err := s.adminUserRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	pinHash := ""
+	if pin != "" {
+		hash, err := s.hashAdminPIN(ctx, pin, id)
+		if err != nil {
+			return err
+		}
+		pinHash = hash
+	}
+
+	return s.adminUserRepo.UpdatePINHash(ctx, id, pinHash)
+}
+
+// hashAdminPIN validates and bcrypt-hashes a PIN. PIN login identifies the account by PIN alone,
+// so a PIN already used by another active account is rejected.
+func (s *DashboardService) hashAdminPIN(ctx context.Context, pin string, ownerID string) (string, error) {
+	if !isValidFourDigitPIN(pin) {
+		return "", fmt.Errorf("PIN must be exactly 4 digits")
+	}
+
+	for _, role := range []string{core.AdminRoleBartender, core.AdminRoleManager} {
+		users, err := s.adminUserRepo.GetActiveByRole(ctx, role)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch PIN-enabled accounts: %w", err)
+		}
+
+		for _, user := range users {
+			if user.ID == ownerID || user.PinHash == "" {
+				continue
+			}
+			if bcrypt.CompareHashAndPassword([]byte(user.PinHash), []byte(pin)) == nil {
+				return "", fmt.Errorf("PIN is already in use by another account")
+			}
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	return string(hash), nil
+}
+
+// normalizeAdminRole upper-cases and validates a dashboard role
+func normalizeAdminRole(role string) (string, error) {
+	role = strings.ToUpper(strings.TrimSpace(role))
+	switch role {
+	case core.AdminRoleManager, core.AdminRoleBartender:
+		return role, nil
+	default:
+		return "", fmt.Errorf("invalid role: must be MANAGER or BARTENDER")
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// AuditLogQuery holds the raw audit log filters accepted by the admin API
+type AuditLogQuery struct {
+	Actor      string
+	EntityType string
+	EntityID   string
+	From       string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	To         string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	Limit      int
+}
+
+// SetAuditLogRepository wires the audit log read by the audit-logs endpoint
+func (s *DashboardService) SetAuditLogRepository(auditLogRepo core.AuditLogRepository) {
+	s.auditLogRepo = auditLogRepo
+}
+
+// ListAuditLogs retrieves audited admin requests matching the query, newest first
+func (s *DashboardService) ListAuditLogs(ctx context.Context, query AuditLogQuery) ([]*core.AuditLog, error) {
+	if s.auditLogRepo == nil {
+		return nil, fmt.Errorf("audit log not configured")
+	}
+
+	filter := core.AuditLogFilter{
+		Actor:      strings.TrimSpace(query.Actor),
+		EntityType: strings.ToLower(strings.TrimSpace(query.EntityType)),
+		EntityID:   strings.TrimSpace(query.EntityID),
+		Limit:      query.Limit,
+	}
+
+	loc := reportLocation()
+	if from := strings.TrimSpace(query.From); from != "" {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+		}
+		filter.From = &start
+	}
+	if to := strings.TrimSpace(query.To); to != "" {
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	return s.auditLogRepo.List(ctx, filter)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// Default token lifetimes; access tokens are short so a revoked refresh token locks the user out quickly
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// AuthTokens is what a successful login or refresh hands back to the dashboard
+type AuthTokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	Role             string
+}
+
+// SetRefreshTokenStore wires the server-side refresh token store used by login, refresh and logout
+func (s *DashboardService) SetRefreshTokenStore(store core.RefreshTokenStore) {
+	s.refreshTokens = store
+}
+
+// SetTokenLifetimes overrides how long access and refresh tokens stay valid
+func (s *DashboardService) SetTokenLifetimes(accessTTL time.Duration, refreshTTL time.Duration) {
+	if accessTTL > 0 {
+		s.accessTokenTTL = accessTTL
+	}
+	if refreshTTL > 0 {
+		s.refreshTokenTTL = refreshTTL
+	}
+}
+
+// RefreshSession rotates a refresh token: the presented one is consumed and a new access/refresh pair issued.
+// The account must still be active, and manager sessions end if the user is no longer a manager.
+func (s *DashboardService) RefreshSession(ctx context.Context, refreshToken string) (*AuthTokens, error) {
+	if s.refreshTokens == nil {
+		return nil, fmt.Errorf("refresh tokens not configured")
+	}
+
+	refreshToken = strings.TrimSpace(refreshToken)
+	if refreshToken == "" {
+		return nil, fmt.Errorf("unauthorized: refresh token required")
+	}
+
+	stored, err := s.refreshTokens.Consume(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("unauthorized: invalid or expired refresh token")
+		}
+		return nil, err
+	}
+	if s.clock.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("unauthorized: invalid or expired refresh token")
+	}
+
+	user, err := s.adminUserRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: admin user not found")
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("unauthorized: admin user inactive")
+	}
+	if stored.Role == core.AdminRoleManager && user.Role != core.AdminRoleManager {
+		return nil, fmt.Errorf("unauthorized: role changed, log in again")
+	}
+
+	user.Role = stored.Role
+	return s.issueTokens(ctx, user)
+}
+
+// RevokeRefreshToken invalidates a single refresh token (logout from one device).
+// Unknown or already-used tokens are ignored so logout always succeeds.
+func (s *DashboardService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	refreshToken = strings.TrimSpace(refreshToken)
+	if s.refreshTokens == nil || refreshToken == "" {
+		return nil
+	}
+
+	if _, err := s.refreshTokens.Consume(ctx, hashRefreshToken(refreshToken)); err != nil && !strings.Contains(err.Error(), "not found") {
+		return err
+	}
+	return nil
+}
+
+// revokeUserSessions drops every refresh token for a user (access tokens are cut off by the token version bump)
+func (s *DashboardService) revokeUserSessions(ctx context.Context, userID string) {
+	if s.refreshTokens == nil {
+		return
+	}
+	if err := s.refreshTokens.RevokeUser(ctx, userID); err != nil {
+		log.Printf("Failed to revoke refresh tokens for admin user %s: %v", userID, err)
+	}
+}
+
+// issueTokens signs an access token for user (with the role already decided by the login method)
+// and stores a new refresh token for it
+func (s *DashboardService) issueTokens(ctx context.Context, user *core.AdminUser) (*AuthTokens, error) {
+	if s.refreshTokens == nil {
+		return nil, fmt.Errorf("refresh tokens not configured")
+	}
+
+	now := s.clock.Now()
+	accessToken, err := s.generateJWT(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &core.RefreshToken{
+		TokenHash: hashRefreshToken(refreshToken),
+		UserID:    user.ID,
+		Role:      user.Role,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTokenTTL),
+	}
+	if err := s.refreshTokens.Save(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  now.Add(s.accessTokenTTL),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+		Role:             user.Role,
+	}, nil
+}
+
+// newRefreshToken returns 32 random bytes, URL-safe encoded
+func newRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRefreshToken is how refresh tokens are keyed server-side, so a store leak doesn't leak usable tokens
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
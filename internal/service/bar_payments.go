@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// ConfirmBarPayment marks a pay-at-the-bar order PAID from the dashboard once staff have taken
+// the money. method is CASH or CARD (CASH when empty).
+func (s *DashboardService) ConfirmBarPayment(ctx context.Context, orderID string, method string, actorUserID string) (*core.Order, error) {
+	paymentMethod := core.PaymentMethod(strings.ToUpper(strings.TrimSpace(method)))
+	if paymentMethod == "" {
+		paymentMethod = core.PaymentMethodCash
+	}
+	if paymentMethod != core.PaymentMethodCash && paymentMethod != core.PaymentMethodCard {
+		return nil, fmt.Errorf("invalid payment method: must be CASH or CARD")
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != core.OrderStatusAwaitingCash {
+		return nil, fmt.Errorf("%w (order is %s)", core.ErrOrderNotAwaitingCash, order.Status)
+	}
+
+	note := fmt.Sprintf("%s payment taken at the bar, confirmed from the dashboard", strings.ToLower(string(paymentMethod)))
+	if err := s.orderRepo.ConfirmBarPayment(ctx, orderID, paymentMethod, actorUserID, note); err != nil {
+		return nil, err
+	}
+
+	// The customer's confirmation, bar staff message and SSE event were queued in the outbox with the status change
+	order.Status = core.OrderStatusPaid
+	order.PaymentMethod = string(paymentMethod)
+	order.AmountPaid = order.TotalAmount
+	s.wakeOutbox()
+
+	return order, nil
+}
@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/dumu-tech/destination-cocktails/internal/testkit"
+)
+
+// staleOrders reads every order as AWAITING_CASH, as a bartender does who loaded it just before
+// someone else confirmed it
+type staleOrders struct {
+	*testkit.OrderRepository
+}
+
+func (r staleOrders) GetByID(ctx context.Context, id string) (*core.Order, error) {
+	order, err := r.OrderRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	order.Status = core.OrderStatusAwaitingCash
+	return order, nil
+}
+
+// barStaffRoster is a bar staff repository that only looks staff up by phone
+type barStaffRoster struct {
+	core.BarStaffRepository
+	staff *core.BarStaff
+}
+
+func (r barStaffRoster) GetByPhone(ctx context.Context, phone string) (*core.BarStaff, error) {
+	if phone != r.staff.PhoneNumber {
+		return nil, errors.New("bar staff not found")
+	}
+	return r.staff, nil
+}
+
+func paidBarOrder(t *testing.T, orders *testkit.OrderRepository) *core.Order {
+	t.Helper()
+
+	order := &core.Order{CustomerPhone: "254711000000", PickupCode: "4821", Status: core.OrderStatusPaid, PaymentMethod: string(core.PaymentMethodCash), TotalAmount: 600, AmountPaid: 600}
+	if err := orders.CreateOrder(context.Background(), order); err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestConfirmBarPaymentRejectsOrdersNotAwaitingCash(t *testing.T) {
+	clock := testkit.NewFakeClock(testkit.Epoch)
+	ids := &testkit.SequenceIDGenerator{}
+	orders := testkit.NewOrderRepository(clock, ids)
+	order := paidBarOrder(t, orders)
+	dashboard := service.NewDashboardService(nil, nil, testkit.NewProductRepository(clock, ids), orders, nil, testkit.NewWhatsAppGateway(), events.NewEventBus(), "secret")
+
+	if _, err := dashboard.ConfirmBarPayment(context.Background(), order.ID, string(core.PaymentMethodCash), "manager"); !errors.Is(err, core.ErrOrderNotAwaitingCash) {
+		t.Errorf("confirming a PAID order got %v, want ErrOrderNotAwaitingCash", err)
+	}
+	if err := orders.ConfirmBarPayment(context.Background(), order.ID, core.PaymentMethodCard, "manager", ""); !errors.Is(err, core.ErrOrderNotAwaitingCash) {
+		t.Errorf("repository confirming a PAID order got %v, want ErrOrderNotAwaitingCash", err)
+	}
+}
+
+func TestBarStaffConfirmingAnAlreadyPaidOrder(t *testing.T) {
+	clock := testkit.NewFakeClock(testkit.Epoch)
+	orders := testkit.NewOrderRepository(clock, &testkit.SequenceIDGenerator{})
+	order := paidBarOrder(t, orders)
+	staff := &core.BarStaff{ID: "staff-1", Name: "Wanjiru", PhoneNumber: "254722000000", IsActive: true}
+	whatsapp := testkit.NewWhatsAppGateway()
+	notifier := service.NewBarStaffNotifier(barStaffRoster{staff: staff}, staleOrders{orders}, whatsapp, core.BarStaffNotifyBroadcast, "")
+
+	confirmed, err := notifier.ConfirmBarPayment(context.Background(), staff.PhoneNumber, order.ID, core.PaymentMethodCash)
+	if err != nil || confirmed != nil {
+		t.Fatalf("got %v, %v; want the second confirmation answered without an error", confirmed, err)
+	}
+	if !whatsapp.Contains(staff.PhoneNumber, "has already been confirmed") {
+		t.Errorf("bartender was sent %+v, want the already confirmed reply", whatsapp.Sent(staff.PhoneNumber))
+	}
+}
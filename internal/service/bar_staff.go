@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+)
+
+// BarStaffUpdate holds optional fields for updating a bar staff member
+type BarStaffUpdate struct {
+	Name        *string
+	PhoneNumber *string
+	IsOnShift   *bool
+	IsActive    *bool
+}
+
+// SetBarStaffRepository wires the bar staff roster used by the staff management endpoints
+func (s *DashboardService) SetBarStaffRepository(barStaffRepo core.BarStaffRepository) {
+	s.barStaffRepo = barStaffRepo
+}
+
+// SetBarStaffNotifier lets dashboard actions that mark orders PAID alert the bar staff roster
+func (s *DashboardService) SetBarStaffNotifier(notifier *BarStaffNotifier) {
+	s.staffNotifier = notifier
+}
+
+// ListBarStaff retrieves the full bar staff roster
+func (s *DashboardService) ListBarStaff(ctx context.Context) ([]*core.BarStaff, error) {
+	if s.barStaffRepo == nil {
+		return nil, fmt.Errorf("bar staff roster not configured")
+	}
+	return s.barStaffRepo.GetAll(ctx)
+}
+
+// CreateBarStaff adds a bartender to the notification roster
+func (s *DashboardService) CreateBarStaff(ctx context.Context, name string, phone string, onShift bool) (*core.BarStaff, error) {
+	if s.barStaffRepo == nil {
+		return nil, fmt.Errorf("bar staff roster not configured")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	normalizedPhone, err := normalizeStaffPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	staff := &core.BarStaff{
+		ID:          s.ids.NewID(),
+		Name:        name,
+		PhoneNumber: normalizedPhone,
+		IsOnShift:   onShift,
+		IsActive:    true,
+		CreatedAt:   s.clock.Now(),
+	}
+
+	if err := s.barStaffRepo.Create(ctx, staff); err != nil {
+		return nil, err
+	}
+
+	return staff, nil
+}
+
+// UpdateBarStaff applies a partial update to a bar staff member (name, phone, shift, active)
+func (s *DashboardService) UpdateBarStaff(ctx context.Context, id string, update BarStaffUpdate) (*core.BarStaff, error) {
+	if s.barStaffRepo == nil {
+		return nil, fmt.Errorf("bar staff roster not configured")
+	}
+
+	staff, err := s.barStaffRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		staff.Name = name
+	}
+
+	if update.PhoneNumber != nil {
+		normalizedPhone, err := normalizeStaffPhone(*update.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		staff.PhoneNumber = normalizedPhone
+	}
+
+	if update.IsOnShift != nil {
+		staff.IsOnShift = *update.IsOnShift
+	}
+
+	if update.IsActive != nil {
+		staff.IsActive = *update.IsActive
+		// Deactivated staff can't stay on shift.
+		if !staff.IsActive {
+			staff.IsOnShift = false
+		}
+	}
+
+	if err := s.barStaffRepo.Update(ctx, staff); err != nil {
+		return nil, err
+	}
+
+	return staff, nil
+}
+
+// DeactivateBarStaff removes a bartender from notifications while keeping acceptance history intact
+func (s *DashboardService) DeactivateBarStaff(ctx context.Context, id string) error {
+	inactive := false
+	_, err := s.UpdateBarStaff(ctx, id, BarStaffUpdate{IsActive: &inactive})
+	return err
+}
+
+// normalizeStaffPhone converts a Kenyan mobile number to the WhatsApp wa_id format (254xxxxxxxxx)
+func normalizeStaffPhone(phone string) (string, error) {
+	normalizedPhone, err := msisdn.Normalize(phone)
+	if err != nil || !msisdn.IsKenyanMobile(normalizedPhone) {
+		return "", fmt.Errorf("invalid phone number: expected a Kenyan mobile number (e.g., 0712345678)")
+	}
+	return strings.TrimPrefix(normalizedPhone, "+"), nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// BarStaffNotifier dispatches paid-order notifications to the on-shift bar staff roster
+type BarStaffNotifier struct {
+	staffRepo     core.BarStaffRepository
+	orderRepo     core.OrderRepository
+	whatsapp      core.WhatsAppGateway
+	mode          string
+	fallbackPhone string
+}
+
+// NewBarStaffNotifier creates a new notifier.
+// mode is core.BarStaffNotifyBroadcast or core.BarStaffNotifyRoundRobin; fallbackPhone is used when nobody is on shift.
+func NewBarStaffNotifier(staffRepo core.BarStaffRepository, orderRepo core.OrderRepository, whatsapp core.WhatsAppGateway, mode string, fallbackPhone string) *BarStaffNotifier {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode != core.BarStaffNotifyRoundRobin {
+		mode = core.BarStaffNotifyBroadcast
+	}
+
+	return &BarStaffNotifier{
+		staffRepo:     staffRepo,
+		orderRepo:     orderRepo,
+		whatsapp:      whatsapp,
+		mode:          mode,
+		fallbackPhone: strings.TrimSpace(fallbackPhone),
+	}
+}
+
+// NotifyPaidOrder sends the order to on-shift bartenders.
+// Broadcast mode notifies everyone on shift; round-robin notifies the least recently notified bartender.
+func (n *BarStaffNotifier) NotifyPaidOrder(ctx context.Context, order *core.Order) error {
+	if order.Status != core.OrderStatusPaid {
+		return fmt.Errorf("only PAID orders are sent to bar staff (order %s is %s)", order.ID, order.Status)
+	}
+
+	message := formatBarStaffOrderMessage(order)
+	buttons := []core.Button{
+		{
+			ID:    fmt.Sprintf("accept_%s", order.ID),
+			Title: "Accept",
+		},
+		{
+			ID:    fmt.Sprintf("complete_%s", order.ID),
+			Title: "Mark Done",
+		},
+	}
+
+	onShift, err := n.staffRepo.GetOnShift(ctx)
+	if err != nil {
+		log.Printf("Failed to load on-shift bar staff, using fallback phone: %v", err)
+		onShift = nil
+	}
+
+	if len(onShift) == 0 {
+		if n.fallbackPhone == "" {
+			return fmt.Errorf("no bar staff on shift and BAR_STAFF_PHONE not configured")
+		}
+		log.Printf("No bar staff on shift, notifying fallback phone for order %s", order.PickupCode)
+		return n.sendWithFallback(ctx, n.fallbackPhone, message, buttons)
+	}
+
+	recipients := onShift
+	if n.mode == core.BarStaffNotifyRoundRobin {
+		// GetOnShift is ordered least recently notified first.
+		recipients = onShift[:1]
+	}
+
+	var lastErr error
+	delivered := 0
+	for _, staff := range recipients {
+		if err := n.sendWithFallback(ctx, staff.PhoneNumber, message, buttons); err != nil {
+			log.Printf("Failed to notify bar staff %s for order %s: %v", staff.Name, order.PickupCode, err)
+			lastErr = err
+			continue
+		}
+		delivered++
+
+		if err := n.staffRepo.MarkNotified(ctx, staff.ID); err != nil {
+			log.Printf("Failed to record notification for bar staff %s: %v", staff.Name, err)
+		}
+	}
+
+	if delivered == 0 && lastErr != nil {
+		return fmt.Errorf("failed to notify any on-shift bar staff: %w", lastErr)
+	}
+	return nil
+}
+
+// AcceptOrder records the first bartender to tap "Accept" and tells the others who took it.
+func (n *BarStaffNotifier) AcceptOrder(ctx context.Context, staffPhone string, orderID string) error {
+	staff, err := n.staffRepo.GetByPhone(ctx, staffPhone)
+	if err != nil || !staff.IsActive {
+		return n.whatsapp.SendText(ctx, staffPhone, "❌ Your number is not on the bar staff roster.")
+	}
+
+	order, err := n.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return n.whatsapp.SendText(ctx, staffPhone, "❌ Order not found")
+	}
+
+	accepted, err := n.orderRepo.MarkAccepted(ctx, orderID, staff.ID)
+	if err != nil {
+		return fmt.Errorf("failed to accept order: %w", err)
+	}
+
+	if !accepted {
+		acceptedBy := "another bartender"
+		if order.AcceptedByStaffID != "" {
+			if owner, err := n.staffRepo.GetByID(ctx, order.AcceptedByStaffID); err == nil {
+				acceptedBy = owner.Name
+			}
+		}
+		return n.whatsapp.SendText(ctx, staffPhone, fmt.Sprintf("ℹ️ Order #%s was already accepted by %s.", order.PickupCode, acceptedBy))
+	}
+
+	if err := n.whatsapp.SendText(ctx, staffPhone, fmt.Sprintf("👍 You accepted order #%s.", order.PickupCode)); err != nil {
+		log.Printf("Failed to confirm acceptance to %s: %v", staff.Name, err)
+	}
+
+	if n.mode == core.BarStaffNotifyBroadcast {
+		onShift, err := n.staffRepo.GetOnShift(ctx)
+		if err != nil {
+			return nil
+		}
+		for _, other := range onShift {
+			if other.ID == staff.ID {
+				continue
+			}
+			if err := n.whatsapp.SendText(ctx, other.PhoneNumber, fmt.Sprintf("✋ Order #%s accepted by %s.", order.PickupCode, staff.Name)); err != nil {
+				log.Printf("Failed to send acceptance notice to %s: %v", other.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NotifyAwaitingPayment asks bar staff to collect payment for a pay-at-the-bar order.
+// Everyone on shift is told in both modes, since the customer pays whoever is at the counter.
+func (n *BarStaffNotifier) NotifyAwaitingPayment(ctx context.Context, order *core.Order) error {
+	if order.Status != core.OrderStatusAwaitingCash {
+		return fmt.Errorf("only AWAITING_CASH orders are sent for payment at the bar (order %s is %s)", order.ID, order.Status)
+	}
+
+	message := fmt.Sprintf("💵 *Pay at the Bar*\n\n*Order #%s*\n*Collect:* KES %.0f\n*Customer:* %s\n\nConfirm once the customer has paid; the order is sent for preparation after that.",
+		order.PickupCode, order.TotalAmount, order.CustomerPhone)
+	buttons := []core.Button{
+		{
+			ID:    fmt.Sprintf("barpaid_cash_%s", order.ID),
+			Title: "Cash Received",
+		},
+		{
+			ID:    fmt.Sprintf("barpaid_card_%s", order.ID),
+			Title: "Card Paid",
+		},
+	}
+
+	onShift, err := n.staffRepo.GetOnShift(ctx)
+	if err != nil {
+		log.Printf("Failed to load on-shift bar staff, using fallback phone: %v", err)
+		onShift = nil
+	}
+	if len(onShift) == 0 {
+		if n.fallbackPhone == "" {
+			return fmt.Errorf("no bar staff on shift and BAR_STAFF_PHONE not configured")
+		}
+		return n.sendWithFallback(ctx, n.fallbackPhone, message, buttons)
+	}
+
+	var lastErr error
+	delivered := 0
+	for _, staff := range onShift {
+		if err := n.sendWithFallback(ctx, staff.PhoneNumber, message, buttons); err != nil {
+			log.Printf("Failed to send bar payment request to %s for order %s: %v", staff.Name, order.PickupCode, err)
+			lastErr = err
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 && lastErr != nil {
+		return fmt.Errorf("failed to notify any on-shift bar staff: %w", lastErr)
+	}
+	return nil
+}
+
+// ConfirmBarPayment records cash or card taken at the bar by a rostered bartender and returns the PAID order.
+// The order is nil when nothing was confirmed; the bartender has already been told why.
+func (n *BarStaffNotifier) ConfirmBarPayment(ctx context.Context, staffPhone string, orderID string, method core.PaymentMethod) (*core.Order, error) {
+	if method != core.PaymentMethodCash && method != core.PaymentMethodCard {
+		return nil, fmt.Errorf("unsupported bar payment method %q", method)
+	}
+
+	staff, err := n.staffRepo.GetByPhone(ctx, staffPhone)
+	if err != nil || !staff.IsActive {
+		return nil, n.whatsapp.SendText(ctx, staffPhone, "❌ Your number is not on the bar staff roster.")
+	}
+
+	order, err := n.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, n.whatsapp.SendText(ctx, staffPhone, "❌ Order not found")
+	}
+	if order.Status != core.OrderStatusAwaitingCash {
+		return nil, n.whatsapp.SendText(ctx, staffPhone, fmt.Sprintf("ℹ️ Order #%s is not waiting for payment at the bar (status %s).", order.PickupCode, order.Status))
+	}
+
+	note := fmt.Sprintf("%s payment taken at the bar, confirmed via WhatsApp by %s (%s)", strings.ToLower(string(method)), staff.Name, staffPhone)
+	if err := n.orderRepo.ConfirmBarPayment(ctx, orderID, method, core.OrderActorWebhook, note); err != nil {
+		// Another bartender confirmed it first
+		if errors.Is(err, core.ErrOrderNotAwaitingCash) {
+			return nil, n.whatsapp.SendText(ctx, staffPhone, fmt.Sprintf("ℹ️ Order #%s has already been confirmed.", order.PickupCode))
+		}
+		n.whatsapp.SendText(ctx, staffPhone, "❌ Failed to update order status")
+		return nil, fmt.Errorf("failed to confirm bar payment: %w", err)
+	}
+
+	if err := n.whatsapp.SendText(ctx, staffPhone, fmt.Sprintf("💵 Order #%s marked as paid (%s).", order.PickupCode, strings.ToLower(string(method)))); err != nil {
+		log.Printf("Failed to confirm bar payment to %s: %v", staff.Name, err)
+	}
+
+	order.Status = core.OrderStatusPaid
+	order.PaymentMethod = string(method)
+	order.AmountPaid = order.TotalAmount
+	return order, nil
+}
+
+// NotifyUncollectedOrder asks bar staff to chase a READY order the customer hasn't picked up.
+// The bartender who accepted the order is told first; otherwise everyone on shift, then the fallback phone.
+func (n *BarStaffNotifier) NotifyUncollectedOrder(ctx context.Context, order *core.Order, waiting time.Duration) error {
+	message := fmt.Sprintf("⏰ *Order #%s not collected*\n\nReady for %d min and still waiting at the bar.\n*Customer:* %s",
+		order.PickupCode, int(waiting.Minutes()), order.CustomerPhone)
+	buttons := []core.Button{
+		{
+			ID:    fmt.Sprintf("complete_%s", order.ID),
+			Title: "Mark Done",
+		},
+	}
+
+	if order.AcceptedByStaffID != "" {
+		if staff, err := n.staffRepo.GetByID(ctx, order.AcceptedByStaffID); err == nil && staff.IsActive {
+			return n.sendWithFallback(ctx, staff.PhoneNumber, message, buttons)
+		}
+	}
+
+	onShift, err := n.staffRepo.GetOnShift(ctx)
+	if err != nil {
+		log.Printf("Failed to load on-shift bar staff, using fallback phone: %v", err)
+		onShift = nil
+	}
+	if len(onShift) == 0 {
+		if n.fallbackPhone == "" {
+			return fmt.Errorf("no bar staff on shift and BAR_STAFF_PHONE not configured")
+		}
+		return n.sendWithFallback(ctx, n.fallbackPhone, message, buttons)
+	}
+
+	var lastErr error
+	for _, staff := range onShift {
+		if err := n.sendWithFallback(ctx, staff.PhoneNumber, message, buttons); err != nil {
+			log.Printf("Failed to alert bar staff %s about order %s: %v", staff.Name, order.PickupCode, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// sendWithFallback sends interactive buttons and falls back to plain text if buttons fail
+func (n *BarStaffNotifier) sendWithFallback(ctx context.Context, phone string, message string, buttons []core.Button) error {
+	if err := n.whatsapp.SendMenuButtons(ctx, phone, message, buttons); err != nil {
+		log.Printf("Error sending bar staff notification with buttons: %v", err)
+		return n.whatsapp.SendText(ctx, phone, message)
+	}
+	return nil
+}
+
+// formatBarStaffOrderMessage builds the WhatsApp message bar staff receive for a paid order
+func formatBarStaffOrderMessage(order *core.Order) string {
+	message := "🚨 *New Order Paid!*\n\n"
+	message += fmt.Sprintf("*Order #%s*\n", order.PickupCode)
+	if order.ScheduledFor != nil {
+		message += fmt.Sprintf("🕘 *Pre-order for %s*\n", FormatScheduledTime(*order.ScheduledFor))
+	}
+	if order.IsDelivery() {
+		message += fmt.Sprintf("🛵 *Delivery to:* %s\n", order.DeliveryAddress)
+	}
+	message += "\n*Items:*\n"
+
+	for _, item := range order.Items {
+		productName := item.ProductName
+		if productName == "" {
+			productName = "Unknown Item"
+		}
+		if len(item.Modifiers) > 0 {
+			productName += " (" + core.FormatModifiers(item.Modifiers) + ")"
+		}
+		message += fmt.Sprintf("• %d x %s\n", item.Quantity, productName)
+		for _, component := range item.Components {
+			message += fmt.Sprintf("   ↳ %d x %s\n", component.Quantity*item.Quantity, component.ProductName)
+		}
+	}
+
+	if order.Notes != "" {
+		message += fmt.Sprintf("\n📝 *Notes:* %s\n", order.Notes)
+	}
+
+	message += fmt.Sprintf("\n*Total:* KES %.0f\n", order.TotalAmount)
+	message += fmt.Sprintf("*Customer:* %s\n", order.CustomerPhone)
+
+	return message
+}
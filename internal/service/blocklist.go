@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+)
+
+const (
+	// BlockedReplyDecline answers blocked customers with a short polite refusal
+	BlockedReplyDecline = "decline"
+	// BlockedReplySilent drops messages from blocked customers without answering
+	BlockedReplySilent = "silent"
+	// maxBlockReasonLength keeps reasons to a short note for other managers
+	maxBlockReasonLength = 500
+	// blocklistActorSystem is recorded as the creator of automatic flags
+	blocklistActorSystem = "system"
+)
+
+// Blocklist decides which customers the bot serves and flags phones with repeated failed payments
+type Blocklist struct {
+	repo          core.BlockedCustomerRepository
+	clock         core.Clock
+	flagThreshold int           // FAILED orders within flagWindow that flag a phone; 0 disables flagging
+	flagWindow    time.Duration // How far back failed payments are counted
+}
+
+// NewBlocklist creates a blocklist that flags a phone after flagThreshold failed payments within flagWindow
+func NewBlocklist(repo core.BlockedCustomerRepository, flagThreshold int, flagWindow time.Duration) *Blocklist {
+	return &Blocklist{
+		repo:          repo,
+		clock:         core.SystemClock{},
+		flagThreshold: flagThreshold,
+		flagWindow:    flagWindow,
+	}
+}
+
+// IsBlocked reports whether the bot should refuse the phone. Flagged phones are not blocked.
+func (l *Blocklist) IsBlocked(ctx context.Context, phone string) (bool, error) {
+	normalized, err := msisdn.Normalize(phone)
+	if err != nil {
+		return false, nil
+	}
+
+	entry, err := l.repo.GetByPhone(ctx, normalized)
+	if err != nil {
+		return false, err
+	}
+	return entry != nil && entry.Status == core.BlockedCustomerBlocked, nil
+}
+
+// List returns every blocked and flagged phone
+func (l *Blocklist) List(ctx context.Context) ([]*core.BlockedCustomer, error) {
+	return l.repo.List(ctx)
+}
+
+// Block stops the bot serving a phone; blocking a flagged phone keeps its entry and replaces the reason
+func (l *Blocklist) Block(ctx context.Context, phone string, reason string, actorUserID string) (*core.BlockedCustomer, error) {
+	normalized, err := msisdn.Normalize(phone)
+	if err != nil || !msisdn.IsKenyanMobile(normalized) {
+		return nil, fmt.Errorf("phone must be a Kenyan mobile number")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	if len(reason) > maxBlockReasonLength {
+		return nil, fmt.Errorf("reason must be at most %d characters", maxBlockReasonLength)
+	}
+
+	entry := &core.BlockedCustomer{
+		Phone:     normalized,
+		Status:    core.BlockedCustomerBlocked,
+		Reason:    reason,
+		CreatedBy: actorUserID,
+	}
+	if err := l.repo.Upsert(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Unblock removes a phone's blocked or flagged entry
+func (l *Blocklist) Unblock(ctx context.Context, phone string) error {
+	normalized, err := msisdn.Normalize(phone)
+	if err != nil {
+		return fmt.Errorf("phone must be a Kenyan mobile number")
+	}
+	return l.repo.Delete(ctx, normalized)
+}
+
+// RecordFailedPayment flags the phone for a manager to review once it reaches the failed payment threshold.
+// Phones that are already listed are left alone, so a flag never downgrades a manager's block.
+func (l *Blocklist) RecordFailedPayment(ctx context.Context, phone string) error {
+	if l.flagThreshold <= 0 {
+		return nil
+	}
+	normalized, err := msisdn.Normalize(phone)
+	if err != nil {
+		return nil
+	}
+
+	entry, err := l.repo.GetByPhone(ctx, normalized)
+	if err != nil {
+		return err
+	}
+	if entry != nil {
+		return nil
+	}
+
+	failed, err := l.repo.CountFailedOrdersSince(ctx, normalized, l.clock.Now().Add(-l.flagWindow))
+	if err != nil {
+		return err
+	}
+	if failed < l.flagThreshold {
+		return nil
+	}
+
+	flag := &core.BlockedCustomer{
+		Phone:     normalized,
+		Status:    core.BlockedCustomerFlagged,
+		Reason:    fmt.Sprintf("%d failed payments in %s", failed, l.flagWindow),
+		CreatedBy: blocklistActorSystem,
+	}
+	if err := l.repo.Upsert(ctx, flag); err != nil {
+		return err
+	}
+	log.Printf("Flagged %s for review after %d failed payments", normalized, failed)
+	return nil
+}
+
+// SetBlocklist wires the customer blocklist managed from the dashboard
+func (s *DashboardService) SetBlocklist(blocklist *Blocklist) {
+	s.blocklist = blocklist
+}
+
+// ListBlockedCustomers returns blocked phones and phones flagged for review
+func (s *DashboardService) ListBlockedCustomers(ctx context.Context) ([]*core.BlockedCustomer, error) {
+	if s.blocklist == nil {
+		return nil, fmt.Errorf("blocklist not configured")
+	}
+	return s.blocklist.List(ctx)
+}
+
+// BlockCustomer stops the bot serving a phone
+func (s *DashboardService) BlockCustomer(ctx context.Context, phone string, reason string, actorUserID string) (*core.BlockedCustomer, error) {
+	if s.blocklist == nil {
+		return nil, fmt.Errorf("blocklist not configured")
+	}
+	return s.blocklist.Block(ctx, phone, reason, actorUserID)
+}
+
+// UnblockCustomer lets a blocked or flagged phone order again
+func (s *DashboardService) UnblockCustomer(ctx context.Context, phone string) error {
+	if s.blocklist == nil {
+		return fmt.Errorf("blocklist not configured")
+	}
+	return s.blocklist.Unblock(ctx, phone)
+}
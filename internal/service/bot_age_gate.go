@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// ageConfirmID and ageDeclineID are the buttons of the 18+ question asked on first contact
+	ageConfirmID = "age_yes"
+	ageDeclineID = "age_no"
+)
+
+// requireAge holds back customers who haven't confirmed they're 18 or older: the first message gets
+// the 18+ question and nothing else works until it's answered. It returns false once the customer has
+// confirmed, so the message goes on to the bot. The answer is cached on the session after the first
+// lookup.
+func (b *BotService) requireAge(ctx context.Context, phone string, session *core.Session, normalizedMessage string) (bool, error) {
+	if !b.AgeGate || session.AgeConfirmed {
+		return false, nil
+	}
+
+	user, err := b.UserRepo.GetOrCreateByPhone(ctx, phone)
+	if err != nil {
+		return true, fmt.Errorf("failed to get or create user: %w", err)
+	}
+	if user.AgeConfirmedAt != nil {
+		session.AgeConfirmed = true
+		return false, b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+
+	switch normalizedMessage {
+	case ageConfirmID, "yes", "ndio", "ndiyo", "18+":
+		if err := b.UserRepo.ConfirmAge(ctx, user.ID); err != nil {
+			return true, fmt.Errorf("failed to confirm age: %w", err)
+		}
+		session.AgeConfirmed = true
+		if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "age.confirmed")); err != nil {
+			return true, fmt.Errorf("failed to send age confirmation: %w", err)
+		}
+		return true, b.handleStart(ctx, phone, session, "")
+	case ageDeclineID, "no", "hapana":
+		return true, b.WhatsApp.SendText(ctx, phone, b.t(session, "age.declined"))
+	}
+
+	buttons := []core.Button{
+		{ID: ageConfirmID, Title: b.t(session, "button.age_confirm")},
+		{ID: ageDeclineID, Title: b.t(session, "button.age_decline")},
+	}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "age.prompt"), buttons); err != nil {
+		return true, fmt.Errorf("failed to send age prompt: %w", err)
+	}
+	return true, nil
+}
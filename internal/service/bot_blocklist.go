@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"log"
+)
+
+// rejectIfBlocked answers a blocked customer (unless replies are silent) and reports true, so the
+// message isn't processed. If the blocklist can't be read, the customer is served.
+func (b *BotService) rejectIfBlocked(ctx context.Context, phone string) (bool, error) {
+	if b.Blocklist == nil {
+		return false, nil
+	}
+
+	blocked, err := b.Blocklist.IsBlocked(ctx, phone)
+	if err != nil {
+		log.Printf("Failed to check blocklist, serving %s: %v", phone, err)
+		return false, nil
+	}
+	if !blocked {
+		return false, nil
+	}
+
+	if b.BlockedReply == BlockedReplySilent {
+		return true, nil
+	}
+	lang := b.preferredLanguage(ctx, phone)
+	return true, b.WhatsApp.SendText(ctx, phone, b.I18n.T(lang, "blocked.declined"))
+}
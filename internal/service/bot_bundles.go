@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// availableStock returns how many of a product can be ordered; combos are limited by their components' stock
+// and cocktails with a recipe by the bottles they're poured from
+func (b *BotService) availableStock(ctx context.Context, product *core.Product) (int, error) {
+	if b.Bundles != nil && product.Category == core.BundleCategory {
+		components, err := b.Bundles.GetComponents(ctx, product.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get bundle components: %w", err)
+		}
+		return core.BundleAvailability(components), nil
+	}
+
+	if b.Recipes != nil {
+		ingredients, err := b.Recipes.GetIngredients(ctx, product.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get recipe ingredients: %w", err)
+		}
+		if len(ingredients) > 0 {
+			return core.RecipeAvailability(ingredients), nil
+		}
+	}
+
+	return product.StockQuantity, nil
+}
+
+// orderItemComponents snapshots what goes into one unit of a combo for the order item; nil for regular products
+func (b *BotService) orderItemComponents(ctx context.Context, productID string) ([]core.BundleComponent, error) {
+	if b.Bundles == nil {
+		return nil, nil
+	}
+
+	components, err := b.Bundles.GetComponents(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle components: %w", err)
+	}
+
+	for i := range components {
+		components[i].StockQuantity = 0 // Live stock isn't part of the order record
+	}
+	return components, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+)
+
+const (
+	// FlowReplyMessageType is the message type the webhook handler passes for a submitted WhatsApp Flow
+	FlowReplyMessageType = "flow"
+	// checkoutFlowScreen is the first screen of internal/adapters/whatsapp/flows/checkout.json
+	checkoutFlowScreen = "CHECKOUT"
+	// checkoutFlowTokenPrefix starts the flow token of a checkout form; the product ID follows
+	checkoutFlowTokenPrefix = "checkout:"
+	// payFormID is the payment button that charges the M-Pesa number given in the checkout form
+	payFormID = "pay_form"
+	// maxTableNumberLength matches orders.table_number
+	maxTableNumberLength = 20
+)
+
+// flowSender is implemented by WhatsApp gateways that can send WhatsApp Flows forms
+type flowSender interface {
+	SendFlow(ctx context.Context, phone string, flow core.Flow) error
+}
+
+// checkoutFormReply is the response_json of a submitted checkout form.
+// Quantity arrives as a string or a number depending on the client.
+type checkoutFormReply struct {
+	FlowToken    string      `json:"flow_token"`
+	Quantity     interface{} `json:"quantity"`
+	TableNumber  string      `json:"table_number"`
+	PaymentPhone string      `json:"payment_phone"`
+}
+
+// sendCheckoutForm opens the checkout form for the selected product. It reports false when no Flow
+// is configured, the gateway can't send Flows or sending failed, so the caller falls back to text prompts.
+func (b *BotService) sendCheckoutForm(ctx context.Context, phone string, session *core.Session, product *core.Product) bool {
+	sender, ok := b.WhatsApp.(flowSender)
+	if b.CheckoutFlowID == "" || !ok {
+		return false
+	}
+
+	name := itemDisplayName(product.Name, session.PendingModifiers)
+	unitPrice := product.Price + modifiersPriceDelta(session.PendingModifiers)
+
+	flow := core.Flow{
+		ID:     b.CheckoutFlowID,
+		Token:  checkoutFlowTokenPrefix + product.ID,
+		Screen: checkoutFlowScreen,
+		Body:   b.t(session, "flow.checkout_prompt", name, unitPrice),
+		CTA:    b.t(session, "button.order_form"),
+		Data: map[string]interface{}{
+			"product_name":  name,
+			"unit_price":    b.t(session, "flow.unit_price", unitPrice),
+			"payment_phone": localPhoneNumber(phone),
+		},
+	}
+
+	if err := sender.SendFlow(ctx, phone, flow); err != nil {
+		log.Printf("Failed to send checkout form to %s, asking for quantity by text: %v", phone, err)
+		return false
+	}
+	return true
+}
+
+// handleCheckoutForm adds the item from a submitted checkout form to the cart and keeps the table
+// and M-Pesa number for the order and payment step
+func (b *BotService) handleCheckoutForm(ctx context.Context, phone string, session *core.Session, responseJSON string) error {
+	var reply checkoutFormReply
+	if err := json.Unmarshal([]byte(responseJSON), &reply); err != nil {
+		return fmt.Errorf("failed to parse checkout form: %w", err)
+	}
+
+	// A form opened for another product, or before the order moved on, no longer applies
+	productID, isCheckout := strings.CutPrefix(reply.FlowToken, checkoutFlowTokenPrefix)
+	if !isCheckout || session.State != StateQuantity || productID != session.CurrentProductID {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "flow.expired"))
+	}
+
+	quantity, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(reply.Quantity)))
+	if err != nil || quantity <= 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "quantity.invalid"))
+	}
+
+	paymentPhone, err := msisdn.Normalize(reply.PaymentPhone)
+	if err != nil || !msisdn.IsKenyanMobile(paymentPhone) {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "flow.invalid_phone"))
+	}
+
+	tableNumber := strings.TrimSpace(reply.TableNumber)
+	if len(tableNumber) > maxTableNumberLength {
+		tableNumber = tableNumber[:maxTableNumberLength]
+	}
+
+	session.TableNumber = tableNumber
+	session.PaymentPhone = paymentPhone
+	return b.addToCart(ctx, phone, session, quantity)
+}
+
+// localPhoneNumber shows a Kenyan number the way customers type it, e.g. 0712345678
+func localPhoneNumber(phone string) string {
+	normalized, err := msisdn.Normalize(phone)
+	if err != nil {
+		return phone
+	}
+	return "0" + strings.TrimPrefix(normalized, "+254")
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+// maxBotCopyLength is the longest bot copy managers can set; WhatsApp list bodies stop at 1024 characters
+const maxBotCopyLength = 1024
+
+// defaultResetKeywords restart the conversation from any state
+const defaultResetKeywords = "hi,hello,start,restart,reset,menu,0"
+
+// botCopyMessages are the bot messages managers can reword from the settings, by message key
+var botCopyMessages = map[string]string{
+	"menu.greeting":           "Greeting shown above the categories when a customer starts or resets",
+	"product.out_of_stock":    "Reply when the chosen product is out of stock (%s is the product name)",
+	"payment.already_pending": "Reply when the customer checks out again while an M-Pesa prompt is pending",
+	"payment.waiting":         "Sent with a Retry button when an M-Pesa payment is still pending after the safety-net delay",
+	"session.expired":         "Sent when a customer taps an old button after their session timed out",
+}
+
+// formatVerbPattern matches fmt verbs such as %s and %.0f
+var formatVerbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+// botCopySettingKey is the setting holding a message's copy in one language, e.g. copy.sw.session.expired
+func botCopySettingKey(lang string, key string) string {
+	return "copy." + lang + "." + key
+}
+
+// withBotCopySettings adds a string setting per language for each rewordable bot message. Its default
+// is the built-in translation, so the settings list shows managers the text they are changing.
+func withBotCopySettings(definitions map[string]settingDefinition) map[string]settingDefinition {
+	bundle := i18n.Default()
+	for key, description := range botCopyMessages {
+		for _, lang := range []string{i18n.English, i18n.Swahili} {
+			builtIn := bundle.T(lang, key)
+			definitions[botCopySettingKey(lang, key)] = settingDefinition{
+				Type:        settingTypeString,
+				Default:     builtIn,
+				MaxLength:   maxBotCopyLength,
+				Validate:    sameFormatVerbs(builtIn),
+				Description: fmt.Sprintf("Bot copy (%s): %s; empty uses the built-in text", lang, description),
+			}
+		}
+	}
+	return definitions
+}
+
+// sameFormatVerbs requires new copy to keep the placeholders of the built-in text, in order
+func sameFormatVerbs(builtIn string) func(string) error {
+	want := formatVerbs(builtIn)
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		got := formatVerbs(value)
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			if len(want) == 0 {
+				return fmt.Errorf("must not contain %% placeholders (write %%%% for a percent sign)")
+			}
+			return fmt.Errorf("must keep the placeholders %s in that order", strings.Join(want, " "))
+		}
+		return nil
+	}
+}
+
+// formatVerbs lists the fmt verbs in a message, leaving out %%
+func formatVerbs(message string) []string {
+	verbs := make([]string, 0)
+	for _, verb := range formatVerbPattern.FindAllString(message, -1) {
+		if verb != "%%" {
+			verbs = append(verbs, verb)
+		}
+	}
+	return verbs
+}
+
+// validateResetKeywords requires at least one keyword in a comma-separated list
+func validateResetKeywords(value string) error {
+	if len(parseResetKeywords(value)) == 0 {
+		return fmt.Errorf("must list at least one keyword")
+	}
+	return nil
+}
+
+// parseResetKeywords splits a comma-separated keyword list, lowercased with blanks dropped
+func parseResetKeywords(value string) []string {
+	keywords := make([]string, 0)
+	for _, keyword := range strings.Split(value, ",") {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+// text translates a bot message into lang, preferring copy a manager set in the settings. Lookups read
+// the settings cache, which every replica drops on a settings_updated event.
+func (b *BotService) text(lang string, key string, args ...interface{}) string {
+	if b.Settings != nil {
+		if _, ok := botCopyMessages[key]; ok {
+			if message := b.Settings.String(context.Background(), botCopySettingKey(lang, key)); message != "" {
+				if len(args) > 0 {
+					return fmt.Sprintf(message, args...)
+				}
+				return message
+			}
+		}
+	}
+	return b.I18n.T(lang, key, args...)
+}
+
+// resetKeywords are the messages that restart the conversation: the bot.reset_keywords setting when
+// runtime settings are wired, otherwise the built-in list
+func (b *BotService) resetKeywords(ctx context.Context) []string {
+	if b.Settings == nil {
+		return parseResetKeywords(defaultResetKeywords)
+	}
+	return parseResetKeywords(b.Settings.String(ctx, SettingResetKeywords))
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// LocationMessageType is the message type the webhook handler passes for a shared location pin
+	LocationMessageType = "location"
+	// fulfilPickupID collects the order at the bar; fulfilDeliveryID asks for a delivery address
+	fulfilPickupID   = "fulfil_pickup"
+	fulfilDeliveryID = "fulfil_delivery"
+	// minDeliveryAddressLength turns away replies too short to find anyone by
+	minDeliveryAddressLength = 5
+	// maxDeliveryAddressLength keeps the address short enough for the rider and bar staff messages
+	maxDeliveryAddressLength = 200
+)
+
+// SharedLocation is the JSON the webhook handler passes for a WhatsApp location message
+type SharedLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+}
+
+// offersDelivery reports whether checkout asks pickup or delivery; orders for a table or a tab are served in the bar
+func (b *BotService) offersDelivery(session *core.Session) bool {
+	return b.Delivery && session.TableNumber == "" && session.TabID == ""
+}
+
+// sendFulfilmentPrompt asks whether the order is collected at the bar or delivered, showing the delivery fee
+func (b *BotService) sendFulfilmentPrompt(ctx context.Context, phone string, session *core.Session) error {
+	buttons := []core.Button{
+		{
+			ID:    fulfilPickupID,
+			Title: b.t(session, "button.fulfil_pickup"),
+		},
+		{
+			ID:    fulfilDeliveryID,
+			Title: b.t(session, "button.fulfil_delivery"),
+		},
+	}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "delivery.prompt", b.DeliveryFee), buttons); err != nil {
+		return fmt.Errorf("failed to send delivery prompt: %w", err)
+	}
+
+	session.State = StateFulfilment
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleFulfilment handles the FULFILMENT state - the pickup/delivery buttons or their words
+func (b *BotService) handleFulfilment(ctx context.Context, phone string, session *core.Session, message string) error {
+	switch strings.ToLower(strings.TrimSpace(message)) {
+	case fulfilPickupID, "pickup", "pick up", "collect", "chukua":
+		clearDelivery(session)
+		return b.continueToTip(ctx, phone, session)
+	case fulfilDeliveryID, "delivery", "deliver", "leta":
+		if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "delivery.address_prompt")); err != nil {
+			return fmt.Errorf("failed to send delivery address prompt: %w", err)
+		}
+		session.State = StateDeliveryAddress
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+	return b.sendFulfilmentPrompt(ctx, phone, session)
+}
+
+// handleDeliveryAddress handles the DELIVERY_ADDRESS state - a typed address, or "pickup" to collect instead
+func (b *BotService) handleDeliveryAddress(ctx context.Context, phone string, session *core.Session, message string) error {
+	address := strings.Join(strings.Fields(message), " ")
+
+	switch strings.ToLower(address) {
+	case fulfilPickupID, "pickup", "pick up", "chukua":
+		clearDelivery(session)
+		return b.continueToTip(ctx, phone, session)
+	}
+	if len([]rune(address)) < minDeliveryAddressLength {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "delivery.address_invalid"))
+	}
+	if len([]rune(address)) > maxDeliveryAddressLength {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "delivery.address_too_long", maxDeliveryAddressLength))
+	}
+
+	session.DeliveryAddress = address
+	session.DeliveryLocation = nil
+	session.DeliveryFee = b.DeliveryFee
+	return b.continueToTip(ctx, phone, session)
+}
+
+// handleSharedLocation takes a location pin as the delivery address; anywhere else in the flow it's not expected
+func (b *BotService) handleSharedLocation(ctx context.Context, phone string, session *core.Session, message string) error {
+	if session.State != StateDeliveryAddress {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "delivery.location_unexpected"))
+	}
+
+	var location SharedLocation
+	if err := json.Unmarshal([]byte(message), &location); err != nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "delivery.address_invalid"))
+	}
+
+	session.DeliveryAddress = location.describe()
+	session.DeliveryLocation = &core.GeoPoint{Latitude: location.Latitude, Longitude: location.Longitude}
+	session.DeliveryFee = b.DeliveryFee
+	return b.continueToTip(ctx, phone, session)
+}
+
+// describe is the pin's place name and address, or its coordinates for a dropped pin
+func (l SharedLocation) describe() string {
+	parts := make([]string, 0, 2)
+	for _, part := range []string{l.Name, l.Address} {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("Pin at %.5f, %.5f", l.Latitude, l.Longitude)
+	}
+
+	address := strings.Join(parts, ", ")
+	if runes := []rune(address); len(runes) > maxDeliveryAddressLength {
+		address = string(runes[:maxDeliveryAddressLength])
+	}
+	return address
+}
+
+// clearDelivery makes the checkout a pickup again
+func clearDelivery(session *core.Session) {
+	session.DeliveryAddress = ""
+	session.DeliveryLocation = nil
+	session.DeliveryFee = 0
+}
+
+// DeliveryMapsLink opens a delivery location in Google Maps
+func DeliveryMapsLink(location core.GeoPoint) string {
+	return fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", location.Latitude, location.Longitude)
+}
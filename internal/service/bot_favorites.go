@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// favoriteSavePrefix starts the reply ID of the "Save as favorite" button sent after payment
+	favoriteSavePrefix = "fav_save_"
+	// favoriteOrderPrefix starts the reply ID of a favorite's list row; tapping it reorders the basket
+	favoriteOrderPrefix = "fav_order_"
+	// maxFavoriteNameLength keeps names within WhatsApp's 20-character button titles
+	maxFavoriteNameLength = 20
+	// maxFavorites is how many favorites a customer keeps; saving another drops the oldest
+	maxFavorites = maxListRows
+	// maxFavoriteDescription is WhatsApp's limit on a list row description
+	maxFavoriteDescription = 72
+)
+
+// isFavoritesCommand recognises the "favorites" keyword in English and Swahili
+func isFavoritesCommand(normalizedMessage string) bool {
+	switch normalizedMessage {
+	case "favorites", "favourites", "favorite", "favourite", "my favorites", "my favourites", "vipendwa":
+		return true
+	}
+	return false
+}
+
+// OfferFavorite follows a paid order's confirmation with a "Save as favorite" button
+func (b *BotService) OfferFavorite(ctx context.Context, order *core.Order, lang string) error {
+	if b.Favorites == nil || len(order.Items) == 0 {
+		return nil
+	}
+
+	buttons := []core.Button{{ID: favoriteSavePrefix + order.ID, Title: b.text(lang, "button.favorite_save")}}
+	if err := b.WhatsApp.SendMenuButtons(ctx, order.CustomerPhone, b.text(lang, "favorites.offer"), buttons); err != nil {
+		return fmt.Errorf("failed to send favorite offer: %w", err)
+	}
+	return nil
+}
+
+// handleFavoriteSave asks for a name for the paid order the customer wants to keep
+func (b *BotService) handleFavoriteSave(ctx context.Context, phone string, session *core.Session, orderID string) error {
+	if _, err := b.customerOrder(ctx, phone, orderID); err != nil {
+		log.Printf("Favorite save for order %s from %s rejected: %v", orderID, phone, err)
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.not_found"))
+	}
+
+	session.State = StateFavoriteName
+	session.FavoriteOrderID = orderID
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.name_prompt", maxFavoriteNameLength))
+}
+
+// handleFavoriteName handles the FAVORITE_NAME state - saves the order's items under the name given.
+// A name the customer already used is replaced, and the oldest favorite makes room when they have
+// maxFavorites.
+func (b *BotService) handleFavoriteName(ctx context.Context, phone string, session *core.Session, message string) error {
+	name := strings.Join(strings.Fields(message), " ")
+	if normalized := strings.ToLower(name); normalized == "cancel" || normalized == "skip" || normalized == "ghairi" {
+		return b.finishFavoriteName(ctx, phone, session, b.t(session, "favorites.cancelled"))
+	}
+	if name == "" || utf8.RuneCountInString(name) > maxFavoriteNameLength {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.invalid_name", maxFavoriteNameLength))
+	}
+
+	order, err := b.customerOrder(ctx, phone, session.FavoriteOrderID)
+	if err != nil {
+		log.Printf("Favorite order %s from %s not saved: %v", session.FavoriteOrderID, phone, err)
+		return b.finishFavoriteName(ctx, phone, session, b.t(session, "favorites.not_found"))
+	}
+
+	existing, err := b.Favorites.ListByUser(ctx, order.UserID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to load favorites: %w", err)
+	}
+	kept := 0
+	for _, favorite := range existing {
+		if strings.EqualFold(favorite.Name, name) || kept >= maxFavorites-1 {
+			if err := b.Favorites.Delete(ctx, favorite.ID); err != nil {
+				return fmt.Errorf("failed to replace favorite: %w", err)
+			}
+			continue
+		}
+		kept++
+	}
+
+	favorite := &core.Favorite{
+		ID:     b.IDs.NewID(),
+		UserID: order.UserID,
+		Name:   name,
+		Items:  expiredOrderCart(order),
+	}
+	if err := b.Favorites.Create(ctx, favorite); err != nil {
+		return fmt.Errorf("failed to save favorite: %w", err)
+	}
+	return b.finishFavoriteName(ctx, phone, session, b.t(session, "favorites.saved", name))
+}
+
+// finishFavoriteName leaves the FAVORITE_NAME state and replies with message
+func (b *BotService) finishFavoriteName(ctx context.Context, phone string, session *core.Session, message string) error {
+	session.State = StateStart
+	session.FavoriteOrderID = ""
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, message)
+}
+
+// handleFavoritesList lists the customer's favorites, newest first, as rows that reorder in one tap.
+// Gateways without lists get the newest three as buttons.
+func (b *BotService) handleFavoritesList(ctx context.Context, phone string, session *core.Session) error {
+	user, err := b.UserRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.none"))
+	}
+	favorites, err := b.Favorites.ListByUser(ctx, user.ID, maxFavorites)
+	if err != nil {
+		return fmt.Errorf("failed to load favorites: %w", err)
+	}
+	if len(favorites) == 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.none"))
+	}
+
+	sender, ok := b.WhatsApp.(listRowSender)
+	if !ok {
+		buttons := make([]core.Button, 0, 3)
+		for _, favorite := range favorites {
+			if len(buttons) == cap(buttons) {
+				break
+			}
+			buttons = append(buttons, core.Button{ID: favoriteOrderPrefix + favorite.ID, Title: favorite.Name})
+		}
+		return b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "favorites.list"), buttons)
+	}
+
+	rows := make([]core.ListRow, len(favorites))
+	for i, favorite := range favorites {
+		rows[i] = core.ListRow{
+			ID:          favoriteOrderPrefix + favorite.ID,
+			Title:       favorite.Name,
+			Description: favoriteDescription(favorite.Items),
+		}
+	}
+	return sender.SendListRows(ctx, phone, b.t(session, "favorites.list"), b.t(session, "favorites.list_button"), rows)
+}
+
+// handleFavoriteReorder replaces the cart with a favorite's items at today's prices and goes straight to
+// checkout. Products that are off the menu or short of stock are left out and named in the summary.
+func (b *BotService) handleFavoriteReorder(ctx context.Context, phone string, session *core.Session, favoriteID string) error {
+	favorite, err := b.Favorites.GetByID(ctx, favoriteID)
+	if err == nil {
+		if user, userErr := b.UserRepo.GetByPhone(ctx, phone); userErr != nil || user.ID != favorite.UserID {
+			err = fmt.Errorf("favorite belongs to another customer")
+		}
+	}
+	if err != nil {
+		log.Printf("Favorite reorder %s from %s rejected: %v", favoriteID, phone, err)
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.not_found"))
+	}
+
+	cart := make([]core.CartItem, 0, len(favorite.Items))
+	var skipped []string
+	for _, item := range favorite.Items {
+		product, err := b.Repo.GetByID(ctx, item.ProductID)
+		if err != nil || !product.IsActive || product.ArchivedAt != nil {
+			skipped = append(skipped, item.Name)
+			continue
+		}
+		available, err := b.availableStock(ctx, product)
+		if err != nil {
+			return err
+		}
+		if available < item.Quantity {
+			skipped = append(skipped, product.Name)
+			continue
+		}
+		cart = append(cart, core.CartItem{
+			ProductID: product.ID,
+			Quantity:  item.Quantity,
+			Name:      product.Name,
+			Price:     product.Price + modifiersPriceDelta(item.Modifiers),
+			Modifiers: item.Modifiers,
+		})
+	}
+	if len(cart) == 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "favorites.unavailable", favorite.Name))
+	}
+
+	summary := b.t(session, "favorites.reorder_header", favorite.Name)
+	for _, item := range cart {
+		summary += fmt.Sprintf("%s x%d = KES %.0f\n", itemDisplayName(item.Name, item.Modifiers), item.Quantity, item.Price*float64(item.Quantity))
+	}
+	if len(skipped) > 0 {
+		summary += b.t(session, "favorites.skipped", strings.Join(skipped, ", "))
+	}
+	if err := b.WhatsApp.SendText(ctx, phone, summary); err != nil {
+		return fmt.Errorf("failed to send favorite summary: %w", err)
+	}
+
+	session.Cart = cart
+	session.CurrentProductID = ""
+	session.PendingModifiers = nil
+	session.PendingQuantity = 0
+	session.TabID = ""
+	session.TabItemIDs = nil
+	session.State = StateConfirmOrder
+	// Checkout can stop early (bar paused, a payment still pending); the cart is kept either way
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.handleCheckout(ctx, phone, session)
+}
+
+// customerOrder loads an order placed by the customer messaging from phone
+func (b *BotService) customerOrder(ctx context.Context, phone string, orderID string) (*core.Order, error) {
+	order, err := b.OrderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := b.UserRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != user.ID {
+		return nil, fmt.Errorf("order belongs to another customer")
+	}
+	return order, nil
+}
+
+// favoriteDescription lists a favorite's items for its list row, e.g. "2x Mojito, 1x Tusker"
+func favoriteDescription(items []core.CartItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprintf("%dx %s", item.Quantity, item.Name)
+	}
+	description := strings.Join(parts, ", ")
+	if utf8.RuneCountInString(description) > maxFavoriteDescription {
+		description = string([]rune(description)[:maxFavoriteDescription-1]) + "…"
+	}
+	return description
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// feedbackSkipID is the button that skips the optional comment after a rating
+const feedbackSkipID = "feedback_skip"
+
+// parseRatingCommand recognises a rating row ("rate_4") or the typed "rate 4"
+func parseRatingCommand(normalizedMessage string) (int, bool) {
+	var value string
+	switch {
+	case strings.HasPrefix(normalizedMessage, "rate_"):
+		value = strings.TrimPrefix(normalizedMessage, "rate_")
+	case strings.HasPrefix(normalizedMessage, "rate "):
+		value = strings.TrimSpace(strings.TrimPrefix(normalizedMessage, "rate "))
+	default:
+		return 0, false
+	}
+
+	rating, err := strconv.Atoi(value)
+	if err != nil || rating < 1 || rating > 5 {
+		return 0, false
+	}
+	return rating, true
+}
+
+// handleFeedbackRating stores a rating for the customer's latest completed order and offers to take a comment
+func (b *BotService) handleFeedbackRating(ctx context.Context, phone string, session *core.Session, rating int) error {
+	feedback, err := b.Feedback.Rate(ctx, phone, rating)
+	if err != nil {
+		log.Printf("Failed to store rating from %s: %v", phone, err)
+	}
+	if feedback == nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "feedback.none"))
+	}
+
+	buttons := []core.Button{{ID: feedbackSkipID, Title: b.t(session, "feedback.skip")}}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "feedback.comment_prompt"), buttons); err != nil {
+		return fmt.Errorf("failed to send feedback comment prompt: %w", err)
+	}
+
+	session.State = StateFeedbackComment
+	session.FeedbackID = feedback.ID
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleFeedbackComment handles the FEEDBACK_COMMENT state - an optional comment on the rating just given
+func (b *BotService) handleFeedbackComment(ctx context.Context, phone string, session *core.Session, message string) error {
+	if normalized := strings.ToLower(strings.TrimSpace(message)); b.Feedback != nil && normalized != feedbackSkipID && normalized != "skip" {
+		if err := b.Feedback.Comment(ctx, session.FeedbackID, message); err != nil {
+			log.Printf("Failed to store feedback comment from %s: %v", phone, err)
+		}
+	}
+
+	session.State = StateStart
+	session.FeedbackID = ""
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "feedback.thanks"))
+}
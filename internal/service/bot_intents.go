@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// maxIntentQuantity is the largest quantity taken from a free-text order; bigger numbers are left to the state handlers
+const maxIntentQuantity = 50
+
+// intentKind is what a free-text message asks the bot to do
+type intentKind int
+
+const (
+	intentNone       intentKind = iota
+	intentAddProduct            // "2 mojitos please", "nataka tusker mbili"
+	intentCategory              // "gin", "show me cocktails"
+	intentViewCart              // "cart", "kikapu"
+	intentClearCart             // "clear cart", "futa kikapu"
+	intentCheckout              // From the message interpreter: "that's all, I'll pay"
+)
+
+// botIntent is a free-text message understood with enough confidence to skip the numbered lists
+type botIntent struct {
+	Kind     intentKind
+	Product  *core.Product
+	Quantity int    // 0 when the message didn't say how many
+	Category string // Menu category name, as listed
+	Phrase   string // What's left of the message once quantities and filler words are removed
+	Ordering bool   // The message said how many or used an ordering word ("nataka", "i want")
+}
+
+var (
+	viewCartCommands  = map[string]bool{"cart": true, "my cart": true, "view cart": true, "show cart": true, "kikapu": true, "kikapu changu": true}
+	clearCartCommands = map[string]bool{"clear cart": true, "empty cart": true, "clear my cart": true, "futa kikapu": true, "ondoa kikapu": true}
+
+	// intentNumberWords are spelled-out quantities in English and Swahili
+	intentNumberWords = map[string]int{
+		"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+		"moja": 1, "mbili": 2, "tatu": 3, "nne": 4, "tano": 5, "sita": 6, "saba": 7, "nane": 8, "tisa": 9, "kumi": 10,
+	}
+
+	// intentOrderingWords say the customer wants something; they're dropped from the product phrase
+	intentOrderingWords = map[string]bool{
+		"want": true, "like": true, "give": true, "get": true, "add": true, "order": true, "bring": true, "another": true,
+		"nataka": true, "ningependa": true, "naomba": true, "nipe": true, "niletee": true, "nipatie": true, "leta": true, "ongeza": true,
+	}
+
+	// intentFillerWords carry no product meaning
+	intentFillerWords = map[string]bool{
+		"please": true, "pls": true, "plz": true, "i": true, "id": true, "would": true, "can": true, "could": true,
+		"me": true, "us": true, "a": true, "an": true, "some": true, "of": true, "the": true, "x": true, "and": true,
+		"for": true, "to": true, "my": true, "have": true, "show": true, "see": true, "list": true,
+		"d": true, "tafadhali": true, "tafadali": true, "na": true,
+	}
+
+	// intentReservedPhrases are replies the state handlers understand; they're never taken as products
+	intentReservedPhrases = map[string]bool{"more": true, "continue": true, "checkout": true, "menu": true, "bar": true, "drink": true}
+)
+
+// parseIntent recognises a product with an optional quantity, a category name or a cart command in a
+// free-text message. A product needs an exact name or a name only one menu product contains; anything
+// less certain returns intentNone so the current state handles the message as before.
+func parseIntent(message string, menu map[string][]*core.Product) botIntent {
+	normalized := strings.Join(intentTokens(message), " ")
+	if viewCartCommands[normalized] {
+		return botIntent{Kind: intentViewCart}
+	}
+	if clearCartCommands[normalized] {
+		return botIntent{Kind: intentClearCart}
+	}
+
+	intent := botIntent{}
+	words := make([]string, 0)
+	for _, token := range intentTokens(message) {
+		if quantity, ok := intentQuantity(token); ok {
+			if intent.Quantity > 0 || quantity > maxIntentQuantity {
+				return botIntent{} // "2 mojitos and 3 gins" or an M-Pesa number: leave it to the state handler
+			}
+			intent.Quantity = quantity
+			intent.Ordering = true
+			continue
+		}
+		if intentOrderingWords[token] {
+			intent.Ordering = true
+			continue
+		}
+		if intentFillerWords[token] {
+			continue
+		}
+		words = append(words, singularWord(token))
+	}
+	intent.Phrase = strings.Join(words, " ")
+	if intent.Phrase == "" || intentReservedPhrases[intent.Phrase] {
+		return botIntent{}
+	}
+
+	// Exact product name, then category name, then a name only one product contains
+	var contains []*core.Product
+	for _, products := range menu {
+		for _, product := range products {
+			name := productIntentKey(product.Name)
+			if name == intent.Phrase {
+				intent.Kind = intentAddProduct
+				intent.Product = product
+				return intent
+			}
+			if len(intent.Phrase) >= 3 && strings.Contains(" "+name+" ", " "+intent.Phrase+" ") {
+				contains = append(contains, product)
+			}
+		}
+	}
+
+	if intent.Quantity == 0 {
+		for category := range menu {
+			if productIntentKey(category) == intent.Phrase {
+				intent.Kind = intentCategory
+				intent.Category = category
+				return intent
+			}
+		}
+	}
+
+	if len(contains) == 1 {
+		intent.Kind = intentAddProduct
+		intent.Product = contains[0]
+	}
+	return intent
+}
+
+// intentTokens lowercases a message and splits it into words, dropping punctuation
+func intentTokens(message string) []string {
+	return strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// intentQuantity reads "2", "2x", "x2" or a number word
+func intentQuantity(token string) (int, bool) {
+	if quantity, ok := intentNumberWords[token]; ok {
+		return quantity, true
+	}
+	digits := strings.TrimSuffix(strings.TrimPrefix(token, "x"), "x")
+	quantity, err := strconv.Atoi(digits)
+	if err != nil || quantity <= 0 {
+		return 0, false
+	}
+	return quantity, true
+}
+
+// productIntentKey normalizes a product or category name the same way as the customer's phrase
+func productIntentKey(name string) string {
+	words := make([]string, 0)
+	for _, token := range intentTokens(name) {
+		if intentFillerWords[token] {
+			continue
+		}
+		words = append(words, singularWord(token))
+	}
+	return strings.Join(words, " ")
+}
+
+// singularWord drops a plural "s" ("mojitos" → "mojito", "cocktails" → "cocktail")
+func singularWord(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// freeTextStates are the states where typed text can be an intent; the others expect a specific answer
+// (a quantity, an option, a phone number, notes)
+var freeTextStates = map[string]bool{
+	"":                    true,
+	StateStart:            true,
+	"MENU":                true,
+	StateBrowsing:         true,
+	StateSelectingProduct: true,
+	StateConfirmOrder:     true,
+}
+
+// handleIntent acts on a free-text message understood with high confidence. It returns false when the
+// message should go through the state machine instead.
+func (b *BotService) handleIntent(ctx context.Context, phone string, session *core.Session, message string) (bool, error) {
+	if !freeTextStates[session.State] {
+		return false, nil
+	}
+
+	menu, err := b.Repo.GetMenu(ctx)
+	if err != nil {
+		return false, nil // The state handler reports it
+	}
+
+	intent := parseIntent(message, menu)
+	if intent.Kind == intentNone && intent.Ordering && intent.Phrase != "" {
+		// "2 mohitos": an ordering message whose product only matches fuzzily, when search finds exactly one
+		if products, err := b.Repo.SearchProducts(ctx, intent.Phrase); err == nil && len(products) == 1 {
+			intent.Kind = intentAddProduct
+			intent.Product = products[0]
+		}
+	}
+	if intent.Kind == intentNone && b.Interpreter != nil && len(strings.Fields(message)) >= 2 {
+		intent = b.interpretIntent(ctx, message, menu)
+	}
+
+	switch intent.Kind {
+	case intentAddProduct:
+		return true, b.selectIntentProduct(ctx, phone, session, intent.Product, intent.Quantity)
+	case intentCategory:
+		return true, b.handleBrowsing(ctx, phone, session, intent.Category)
+	case intentViewCart:
+		if len(session.Cart) == 0 {
+			return true, b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.empty"))
+		}
+		return true, b.sendCartSummary(ctx, phone, session, b.t(session, "cart.header"))
+	case intentClearCart:
+		return true, b.clearCart(ctx, phone, session)
+	case intentCheckout:
+		if len(session.Cart) == 0 {
+			return true, b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.empty"))
+		}
+		session.State = StateConfirmOrder
+		return true, b.handleCheckout(ctx, phone, session)
+	default:
+		return false, nil
+	}
+}
+
+// selectIntentProduct selects a product named in a free-text message, asks its serving options and adds
+// the quantity given (or asks for one)
+func (b *BotService) selectIntentProduct(ctx context.Context, phone string, session *core.Session, product *core.Product, quantity int) error {
+	available, err := b.availableStock(ctx, product)
+	if err != nil {
+		return err
+	}
+	if available <= 0 {
+		return b.sendOutOfStock(ctx, phone, session, product)
+	}
+
+	session.CurrentProductID = product.ID
+	session.PendingModifiers = nil
+	session.PendingQuantity = quantity
+	return b.promptNextOption(ctx, phone, session, product)
+}
+
+// clearCart empties the cart and shows the categories again
+func (b *BotService) clearCart(ctx context.Context, phone string, session *core.Session) error {
+	session.Cart = []core.CartItem{}
+	session.CurrentProductID = ""
+	session.PendingModifiers = nil
+	session.PendingQuantity = 0
+	session.TabID = ""
+	session.TabItemIDs = nil
+
+	if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.cleared")); err != nil {
+		return fmt.Errorf("failed to send cart cleared message: %w", err)
+	}
+	return b.handleStart(ctx, phone, session, "")
+}
+
+// interpretIntent asks the message interpreter what a message the parser couldn't place means, and
+// accepts the answer only if it names a live menu product or category. Errors and anything invalid
+// return intentNone, leaving the message to the state machine.
+func (b *BotService) interpretIntent(ctx context.Context, message string, menu map[string][]*core.Product) botIntent {
+	interpreted, err := b.Interpreter.Interpret(ctx, message, menu)
+	if err != nil {
+		log.Printf("Message interpreter failed, using the state machine: %v", err)
+		return botIntent{}
+	}
+
+	switch interpreted.Action {
+	case core.BotActionAddItem:
+		if interpreted.Quantity < 0 || interpreted.Quantity > maxIntentQuantity {
+			return botIntent{}
+		}
+		for _, products := range menu {
+			for _, product := range products {
+				if product.ID == interpreted.ProductID {
+					return botIntent{Kind: intentAddProduct, Product: product, Quantity: interpreted.Quantity}
+				}
+			}
+		}
+	case core.BotActionBrowseCategory:
+		for category := range menu {
+			if strings.EqualFold(category, strings.TrimSpace(interpreted.Category)) {
+				return botIntent{Kind: intentCategory, Category: category}
+			}
+		}
+	case core.BotActionViewCart:
+		return botIntent{Kind: intentViewCart}
+	case core.BotActionCheckout:
+		return botIntent{Kind: intentCheckout}
+	}
+	return botIntent{}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+// t translates a bot message into the session's language
+func (b *BotService) t(session *core.Session, key string, args ...interface{}) string {
+	return b.text(sessionLanguage(session), key, args...)
+}
+
+// sessionLanguage returns the session language, defaulting to English
+func sessionLanguage(session *core.Session) string {
+	if session == nil {
+		return i18n.DefaultLanguage
+	}
+	return i18n.Resolve(session.Language)
+}
+
+// preferredLanguage looks up the language to use for a new or reset session:
+// the current session first, then the stored user preference.
+func (b *BotService) preferredLanguage(ctx context.Context, phone string) string {
+	if session, err := b.Session.Get(ctx, phone); err == nil && session.Language != "" {
+		return i18n.Resolve(session.Language)
+	}
+	if user, err := b.UserRepo.GetByPhone(ctx, phone); err == nil && user.Language != "" {
+		return i18n.Resolve(user.Language)
+	}
+	return i18n.DefaultLanguage
+}
+
+// CustomerLanguage returns the stored bot language for a user (used for payment notifications)
+func (b *BotService) CustomerLanguage(ctx context.Context, userID string) string {
+	if userID == "" {
+		return i18n.DefaultLanguage
+	}
+	user, err := b.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return i18n.DefaultLanguage
+	}
+	return i18n.Resolve(user.Language)
+}
+
+// parseLanguageCommand recognises "lugha", "language", "lugha sw", "language english"
+// and the lang_en / lang_sw button IDs. requested is "" when the user should be asked to pick.
+func parseLanguageCommand(normalizedMessage string) (requested string, ok bool) {
+	if strings.HasPrefix(normalizedMessage, "lang_") {
+		if lang := i18n.Normalize(strings.TrimPrefix(normalizedMessage, "lang_")); lang != "" {
+			return lang, true
+		}
+		return "", false
+	}
+
+	fields := strings.Fields(normalizedMessage)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", false
+	}
+
+	switch fields[0] {
+	case "lugha", "language":
+	default:
+		return "", false
+	}
+
+	if len(fields) == 1 {
+		return "", true
+	}
+	if lang := i18n.Normalize(fields[1]); lang != "" {
+		return lang, true
+	}
+	return "", true
+}
+
+// handleLanguageCommand asks for a language or stores the chosen one on the session and user
+func (b *BotService) handleLanguageCommand(ctx context.Context, phone string, session *core.Session, requested string) error {
+	if requested == "" || !b.I18n.Supports(requested) {
+		buttons := []core.Button{
+			{
+				ID:    "lang_" + i18n.English,
+				Title: b.I18n.T(i18n.English, "button.english"),
+			},
+			{
+				ID:    "lang_" + i18n.Swahili,
+				Title: b.I18n.T(i18n.Swahili, "button.swahili"),
+			},
+		}
+		return b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "language.prompt"), buttons)
+	}
+
+	session.Language = requested
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save language: %w", err)
+	}
+
+	// Persist on the user so the preference survives session expiry.
+	if user, err := b.UserRepo.GetOrCreateByPhone(ctx, phone); err != nil {
+		log.Printf("Failed to load user %s for language preference: %v", phone, err)
+	} else if err := b.UserRepo.UpdateLanguage(ctx, user.ID, requested); err != nil {
+		log.Printf("Failed to store language preference for %s: %v", phone, err)
+	}
+
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "language.changed"))
+}
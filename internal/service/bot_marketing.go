@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// Marketing consent buttons; the typed commands "subscribe" and "unsubscribe" do the same
+const (
+	marketingOptInID  = "offers_subscribe"
+	marketingOptOutID = "offers_unsubscribe"
+)
+
+// parseMarketingCommand recognises the broadcast consent commands and buttons
+func parseMarketingCommand(normalizedMessage string) (optIn bool, ok bool) {
+	switch normalizedMessage {
+	case marketingOptInID, "subscribe", "jiunge":
+		return true, true
+	case marketingOptOutID, "unsubscribe", "jiondoe":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// handleMarketingConsent records whether the customer wants broadcast offers
+func (b *BotService) handleMarketingConsent(ctx context.Context, phone string, session *core.Session, optIn bool) error {
+	user, err := b.UserRepo.GetOrCreateByPhone(ctx, phone)
+	if err != nil {
+		log.Printf("Failed to load user %s for marketing consent: %v", phone, err)
+	} else if err := b.UserRepo.SetMarketingOptIn(ctx, user.ID, optIn); err != nil {
+		log.Printf("Failed to store marketing consent for %s: %v", phone, err)
+	}
+
+	if optIn {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "broadcast.subscribed"))
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "broadcast.unsubscribed"))
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// maxReplyButtons is WhatsApp's limit on reply buttons in one message
+const maxReplyButtons = 3
+
+// optionGroup is one question asked after product selection, e.g. "Size" with Single/Double
+type optionGroup struct {
+	Name    string
+	Options []*core.ProductOption
+}
+
+// activeOptionGroups groups active options by name, in the order each group first appears
+func activeOptionGroups(options []*core.ProductOption) []optionGroup {
+	groups := make([]optionGroup, 0)
+	index := make(map[string]int)
+
+	for _, option := range options {
+		if !option.IsActive {
+			continue
+		}
+		key := strings.ToLower(option.GroupName)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, optionGroup{Name: option.GroupName})
+		}
+		groups[i].Options = append(groups[i].Options, option)
+	}
+
+	return groups
+}
+
+// productOptionGroups loads the option questions for a product; none when options aren't configured
+func (b *BotService) productOptionGroups(ctx context.Context, productID string) ([]optionGroup, error) {
+	if b.Options == nil {
+		return nil, nil
+	}
+
+	options, err := b.Options.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product options: %w", err)
+	}
+	return activeOptionGroups(options), nil
+}
+
+// modifiersPriceDelta sums the price adjustments of chosen modifiers
+func modifiersPriceDelta(modifiers []core.OrderModifier) float64 {
+	total := 0.0
+	for _, modifier := range modifiers {
+		total += modifier.PriceDelta
+	}
+	return total
+}
+
+// itemDisplayName appends chosen modifiers to a product name, e.g. "Gin & Tonic (Double, No ice)"
+func itemDisplayName(name string, modifiers []core.OrderModifier) string {
+	if len(modifiers) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, core.FormatModifiers(modifiers))
+}
+
+// formatPriceDelta renders an option's price adjustment, e.g. " (+KES 200)"; empty when free
+func formatPriceDelta(delta float64) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf(" (+KES %.0f)", delta)
+	case delta < 0:
+		return fmt.Sprintf(" (-KES %.0f)", math.Abs(delta))
+	default:
+		return ""
+	}
+}
+
+// promptNextOption asks the next unanswered option question, or for the quantity once all are answered
+func (b *BotService) promptNextOption(ctx context.Context, phone string, session *core.Session, product *core.Product) error {
+	groups, err := b.productOptionGroups(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(session.PendingModifiers) >= len(groups) {
+		return b.promptQuantity(ctx, phone, session, product)
+	}
+
+	if err := b.sendOptionGroup(ctx, phone, session, product, groups[len(session.PendingModifiers)]); err != nil {
+		return fmt.Errorf("failed to send product options: %w", err)
+	}
+
+	session.State = StateSelectingOption
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// promptQuantity asks how many of the selected product (with its chosen options) to add,
+// through the checkout form when one is configured
+func (b *BotService) promptQuantity(ctx context.Context, phone string, session *core.Session, product *core.Product) error {
+	// The customer already said how many ("2 mojitos")
+	if quantity := session.PendingQuantity; quantity > 0 {
+		session.PendingQuantity = 0
+		session.State = StateQuantity // Asked again if there isn't enough stock
+		if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		return b.addToCart(ctx, phone, session, quantity)
+	}
+
+	// The checkout form asks quantity, table and M-Pesa number at once; typed quantities still work
+	if b.sendCheckoutForm(ctx, phone, session, product) {
+		session.State = StateQuantity
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+
+	quantityMsg := b.t(session, "quantity.prompt",
+		itemDisplayName(product.Name, session.PendingModifiers),
+		product.Price+modifiersPriceDelta(session.PendingModifiers))
+
+	if err := b.WhatsApp.SendText(ctx, phone, quantityMsg); err != nil {
+		return fmt.Errorf("failed to send quantity prompt: %w", err)
+	}
+
+	// Set state to QUANTITY
+	session.State = StateQuantity
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// sendOptionGroup shows one option question: reply buttons for up to 3 choices, otherwise a list
+func (b *BotService) sendOptionGroup(ctx context.Context, phone string, session *core.Session, product *core.Product, group optionGroup) error {
+	text := b.t(session, "option.prompt", group.Name, product.Name)
+	for i, option := range group.Options {
+		text += fmt.Sprintf("%d. %s%s\n", i+1, option.Label, formatPriceDelta(option.PriceDelta))
+	}
+
+	if len(group.Options) <= maxReplyButtons {
+		buttons := make([]core.Button, len(group.Options))
+		for i, option := range group.Options {
+			buttons[i] = core.Button{ID: option.ID, Title: option.Label}
+		}
+		return b.WhatsApp.SendMenuButtons(ctx, phone, text, buttons)
+	}
+
+	if sender, ok := b.WhatsApp.(listRowSender); ok && len(group.Options) <= maxListRows {
+		rows := make([]core.ListRow, len(group.Options))
+		for i, option := range group.Options {
+			rows[i] = core.ListRow{
+				ID:          option.ID,
+				Title:       option.Label,
+				Description: strings.TrimSpace(formatPriceDelta(option.PriceDelta)),
+			}
+		}
+		return sender.SendListRows(ctx, phone, text, b.t(session, "option.button"), rows)
+	}
+
+	return b.WhatsApp.SendText(ctx, phone, text+b.t(session, "option.reply_hint"))
+}
+
+// handleSelectingOption handles the SELECTING_OPTION state - user picks a serving option
+func (b *BotService) handleSelectingOption(ctx context.Context, phone string, session *core.Session, message string) error {
+	product, err := b.Repo.GetByID(ctx, session.CurrentProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	groups, err := b.productOptionGroups(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	// Options were removed since the question was asked - move straight on
+	if len(session.PendingModifiers) >= len(groups) {
+		return b.promptQuantity(ctx, phone, session, product)
+	}
+
+	group := groups[len(session.PendingModifiers)]
+	selected := matchProductOption(group.Options, message)
+	if selected == nil {
+		if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "option.invalid")); err != nil {
+			return fmt.Errorf("failed to send error message: %w", err)
+		}
+		if err := b.sendOptionGroup(ctx, phone, session, product, group); err != nil {
+			return fmt.Errorf("failed to send product options: %w", err)
+		}
+		// Keep state as SELECTING_OPTION
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+
+	session.PendingModifiers = append(session.PendingModifiers, core.OrderModifier{
+		Group:      group.Name,
+		Label:      selected.Label,
+		PriceDelta: selected.PriceDelta,
+	})
+
+	return b.promptNextOption(ctx, phone, session, product)
+}
+
+// matchProductOption matches a reply by option ID (button/list reply), number, or label
+func matchProductOption(options []*core.ProductOption, message string) *core.ProductOption {
+	trimmed := strings.TrimSpace(message)
+
+	for _, option := range options {
+		if option.ID == trimmed {
+			return option
+		}
+	}
+
+	if num, err := strconv.Atoi(trimmed); err == nil {
+		if num > 0 && num <= len(options) {
+			return options[num-1]
+		}
+		return nil
+	}
+
+	for _, option := range options {
+		if strings.EqualFold(option.Label, trimmed) {
+			return option
+		}
+	}
+	return nil
+}
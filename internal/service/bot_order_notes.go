@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// notesSkipID is the button that checks out without special instructions
+	notesSkipID = "notes_skip"
+	// maxOrderNotesLength keeps instructions short enough for the bar staff message and the receipt
+	maxOrderNotesLength = 200
+)
+
+// sendNotesPrompt asks for optional special instructions, with a button to skip
+func (b *BotService) sendNotesPrompt(ctx context.Context, phone string, session *core.Session) error {
+	buttons := []core.Button{
+		{
+			ID:    notesSkipID,
+			Title: b.t(session, "button.notes_skip"),
+		},
+	}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "notes.prompt", maxOrderNotesLength), buttons); err != nil {
+		return fmt.Errorf("failed to send notes prompt: %w", err)
+	}
+
+	session.State = StateOrderNotes
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleOrderNotes handles the ORDER_NOTES state - free text kept for the order, or the skip button
+func (b *BotService) handleOrderNotes(ctx context.Context, phone string, session *core.Session, message string) error {
+	notes := strings.Join(strings.Fields(message), " ")
+
+	switch strings.ToLower(notes) {
+	case notesSkipID, "skip", "no", "none", "ruka", "hapana":
+		session.OrderNotes = ""
+		return b.continueCheckout(ctx, phone, session)
+	}
+
+	if notes == "" {
+		return b.sendNotesPrompt(ctx, phone, session)
+	}
+	if len([]rune(notes)) > maxOrderNotesLength {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "notes.too_long", maxOrderNotesLength))
+	}
+
+	session.OrderNotes = notes
+	return b.continueCheckout(ctx, phone, session)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// rejectIfOrderingPaused tells the customer ordering is paused and reports true, so the caller
+// stops before creating an order. If the switch can't be read, ordering stays open.
+func (b *BotService) rejectIfOrderingPaused(ctx context.Context, phone string, session *core.Session) (bool, error) {
+	if b.Ordering == nil {
+		return false, nil
+	}
+
+	status, err := b.Ordering.GetOrderingStatus(ctx)
+	if err != nil {
+		log.Printf("Failed to read ordering pause, accepting checkout from %s: %v", phone, err)
+		return false, nil
+	}
+	if !status.Paused {
+		return false, nil
+	}
+
+	message := b.t(session, "ordering.paused")
+	if status.Message != "" {
+		message = b.t(session, "ordering.paused_custom", status.Message)
+	}
+	return true, b.WhatsApp.SendText(ctx, phone, message)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// maxListRows is WhatsApp's limit on rows in an interactive list
+	maxListRows = 10
+	// categoriesPerPage leaves room for the "More categories" row when the menu needs paging
+	categoriesPerPage = maxListRows - 1
+	// productsPerPage keeps numbered product lists well under WhatsApp's 4096-character text limit
+	productsPerPage = 20
+	// moreCategoriesID is the reply ID of the "More categories" list row
+	moreCategoriesID = "menu_more"
+)
+
+// listRowSender is implemented by WhatsApp gateways that accept list rows with separate IDs and titles
+type listRowSender interface {
+	SendListRows(ctx context.Context, phone string, text string, buttonLabel string, rows []core.ListRow) error
+}
+
+// isMoreCommand reports whether the customer asked for the next page of a list
+func isMoreCommand(normalizedMessage string) bool {
+	switch normalizedMessage {
+	case moreCategoriesID, "more", "next", "zaidi":
+		return true
+	}
+	return false
+}
+
+// pageBounds returns the slice bounds for a zero-based page, the page count, and the page actually used
+// (paging past the last page wraps back to the first so "more" keeps cycling).
+func pageBounds(total int, perPage int, page int) (start int, end int, pageCount int, current int) {
+	pageCount = (total + perPage - 1) / perPage
+	if pageCount == 0 {
+		return 0, 0, 1, 0
+	}
+
+	current = page
+	if current < 0 || current >= pageCount {
+		current = 0
+	}
+
+	start = current * perPage
+	end = start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end, pageCount, current
+}
+
+// sendCategoryList sends the page of categories stored in session.Page, with a "More categories"
+// row when the menu doesn't fit in one WhatsApp list. Translated copy is used when the gateway supports it.
+func (b *BotService) sendCategoryList(ctx context.Context, phone string, session *core.Session, categories []string) error {
+	return b.sendCategoryListWithHeader(ctx, phone, session, categories, "menu.category_list")
+}
+
+// sendCategoryListWithHeader sends the category list with the message headerKey as its body, e.g. the
+// greeting after a reset
+func (b *BotService) sendCategoryListWithHeader(ctx context.Context, phone string, session *core.Session, categories []string, headerKey string) error {
+	perPage := categoriesPerPage
+	if len(categories) <= maxListRows {
+		perPage = maxListRows
+	}
+
+	start, end, pageCount, page := pageBounds(len(categories), perPage, session.Page)
+	session.Page = page
+	pageCategories := categories[start:end]
+
+	sender, ok := b.WhatsApp.(listRowSender)
+	if !ok {
+		if err := b.WhatsApp.SendCategoryList(ctx, phone, pageCategories); err != nil {
+			return err
+		}
+		if pageCount > 1 {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "menu.more_hint"))
+		}
+		return nil
+	}
+
+	rows := make([]core.ListRow, 0, len(pageCategories)+1)
+	for _, category := range pageCategories {
+		rows = append(rows, core.ListRow{ID: category, Title: category})
+	}
+	if pageCount > 1 {
+		nextPage := (page+1)%pageCount + 1
+		rows = append(rows, core.ListRow{
+			ID:          moreCategoriesID,
+			Title:       b.t(session, "menu.more_categories"),
+			Description: b.t(session, "menu.more_categories_description", nextPage, pageCount),
+		})
+	}
+
+	return sender.SendListRows(ctx, phone,
+		b.t(session, headerKey),
+		b.t(session, "menu.category_button"),
+		rows)
+}
+
+// productPageText renders the page of a numbered product list stored in session.Page.
+// Numbering continues across pages so a reply like "23" maps straight onto the full sorted list.
+func (b *BotService) productPageText(session *core.Session, header string, replyHint string, products []*core.Product) string {
+	start, end, pageCount, page := pageBounds(len(products), productsPerPage, session.Page)
+	session.Page = page
+
+	text := header
+	for i := start; i < end; i++ {
+		text += fmt.Sprintf("%d. %s - KES %.0f\n", i+1, products[i].Name, products[i].Price)
+	}
+	text += replyHint
+	if pageCount > 1 {
+		text += b.t(session, "list.page_hint", page+1, pageCount)
+	}
+	return text
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// payAtBarID is the checkout choice for paying cash or card at the counter
+const payAtBarID = "pay_bar"
+
+// sendPaymentOptions sends the M-Pesa payment buttons, adding "Pay at the bar" when it's enabled.
+// Four choices don't fit in reply buttons, so they go out as a list; gateways without lists get
+// the buttons and a hint to reply "bar".
+func (b *BotService) sendPaymentOptions(ctx context.Context, phone string, session *core.Session, text string, buttons []core.Button) error {
+	// A pre-order is paid up front, so it's only held and released to the bar on time when paid by M-Pesa;
+	// a delivery customer isn't at the bar to pay there
+	if b.BarStaff == nil || session.ScheduledFor != nil || session.DeliveryAddress != "" {
+		return b.WhatsApp.SendMenuButtons(ctx, phone, text, buttons)
+	}
+
+	sender, ok := b.WhatsApp.(listRowSender)
+	if !ok {
+		return b.WhatsApp.SendMenuButtons(ctx, phone, text+"\n\n"+b.t(session, "payment.bar_hint"), buttons)
+	}
+
+	rows := make([]core.ListRow, 0, len(buttons)+1)
+	for _, button := range buttons {
+		rows = append(rows, core.ListRow{ID: button.ID, Title: button.Title})
+	}
+	rows = append(rows, core.ListRow{
+		ID:          payAtBarID,
+		Title:       b.t(session, "button.pay_bar"),
+		Description: b.t(session, "payment.bar_description"),
+	})
+	return sender.SendListRows(ctx, phone, text, b.t(session, "payment.options_button"), rows)
+}
+
+// handlePayAtBar places the order as AWAITING_CASH and asks bar staff to collect the payment.
+// The customer gets the pickup code straight away so they can show it when paying.
+func (b *BotService) handlePayAtBar(ctx context.Context, phone string, session *core.Session) error {
+	if len(session.Cart) == 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.empty"))
+	}
+	if paused, err := b.rejectIfOrderingPaused(ctx, phone, session); paused || err != nil {
+		return err
+	}
+	if session.ScheduledFor != nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "schedule.no_bar"))
+	}
+	if session.DeliveryAddress != "" {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "delivery.no_bar"))
+	}
+
+	order, err := b.newPendingOrder(ctx, phone, session, phone)
+	if errors.Is(err, errTabChanged) {
+		return b.handleTabChanged(ctx, phone, session)
+	}
+	if err != nil {
+		return err
+	}
+	order.Status = core.OrderStatusAwaitingCash
+	// Staff record CARD instead when they confirm a card payment
+	order.PaymentMethod = string(core.PaymentMethodCash)
+
+	if err := b.OrderRepo.CreateOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	if err := b.BarStaff.NotifyAwaitingPayment(ctx, order); err != nil {
+		log.Printf("Failed to send pay-at-bar order %s to bar staff: %v", order.ID, err)
+	}
+
+	// Nothing is pending on M-Pesa, so the customer can start another order straight away
+	session.Cart = []core.CartItem{}
+	session.TipAmount = 0
+	session.TableNumber = ""
+	session.PaymentPhone = ""
+	session.OrderNotes = ""
+	session.ScheduledFor = nil
+	clearDelivery(session)
+	clearTabCheckout(session)
+	session.State = "START"
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "payment.pay_at_bar", order.PickupCode, order.TotalAmount))
+}
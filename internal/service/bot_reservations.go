@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// reservationTableID and reservationVIPID are the kind buttons that start a booking
+	reservationTableID = "res_table"
+	reservationVIPID   = "res_vip"
+	// reservationConfirmID books (and requests the deposit); reservationCancelID drops the booking
+	reservationConfirmID = "res_confirm"
+	reservationCancelID  = "res_cancel"
+)
+
+// reservationWeekdays maps English and Swahili day names to weekdays
+var reservationWeekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "jumatatu": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "jumanne": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "jumatano": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "alhamisi": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "ijumaa": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "jumamosi": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "jumapili": time.Sunday,
+}
+
+// isReservationCommand recognises the booking keywords in English and Swahili
+func isReservationCommand(normalizedMessage string) bool {
+	switch normalizedMessage {
+	case "book", "book a table", "reserve", "reservation", "reservations", "booking", "hifadhi", "hifadhi meza":
+		return true
+	}
+	return false
+}
+
+// handleReservationStart starts a booking by asking for a table or a VIP booth
+func (b *BotService) handleReservationStart(ctx context.Context, phone string, session *core.Session) error {
+	buttons := []core.Button{
+		{ID: reservationTableID, Title: b.t(session, "button.reservation_table")},
+		{ID: reservationVIPID, Title: b.t(session, "button.reservation_vip")},
+	}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "reservation.kind_prompt"), buttons); err != nil {
+		return fmt.Errorf("failed to send reservation kind prompt: %w", err)
+	}
+
+	session.State = StateReservationKind
+	session.Booking = &core.BookingDraft{}
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleReservation handles the RESERVATION_* states, one answer per step: kind, date, time, party
+// size and confirmation. "cancel" leaves the booking at any step.
+func (b *BotService) handleReservation(ctx context.Context, phone string, session *core.Session, message string) error {
+	normalized := strings.ToLower(strings.Join(strings.Fields(message), " "))
+	if normalized == reservationCancelID || normalized == "cancel" || normalized == "ghairi" || session.Booking == nil || b.Reservations == nil {
+		return b.finishReservation(ctx, phone, session, b.t(session, "reservation.cancelled_by_customer"))
+	}
+
+	policy := b.Reservations.Policy()
+	loc := reportLocation()
+	now := b.Clock.Now()
+
+	switch session.State {
+	case StateReservationKind:
+		switch normalized {
+		case reservationTableID, "table", "meza":
+			session.Booking.Kind = core.ReservationKindTable
+		case reservationVIPID, "vip", "booth", "vip booth":
+			session.Booking.Kind = core.ReservationKindVIP
+		default:
+			return b.handleReservationStart(ctx, phone, session)
+		}
+		session.State = StateReservationDate
+		return b.saveReservationStep(ctx, phone, session, b.t(session, "reservation.date_prompt"))
+
+	case StateReservationDate:
+		date, ok := parseReservationDate(normalized, now, loc)
+		if !ok {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "reservation.invalid_date"))
+		}
+		today := now.In(loc)
+		today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+		if date.Before(today) || date.After(today.AddDate(0, 0, policy.MaxDaysAhead)) {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "reservation.date_out_of_range", policy.MaxDaysAhead))
+		}
+		session.Booking.Date = date.Format("2006-01-02")
+		session.State = StateReservationTime
+		return b.saveReservationStep(ctx, phone, session, b.t(session, "reservation.time_prompt"))
+
+	case StateReservationTime:
+		date, err := time.ParseInLocation("2006-01-02", session.Booking.Date, loc)
+		hour, minute, ok := parseClockTime(normalized)
+		if err != nil || !ok {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "reservation.invalid_time"))
+		}
+		at := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc).UTC()
+		if at.Sub(now) < reservationLeadTime {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "reservation.too_soon"))
+		}
+		session.Booking.At = &at
+		session.State = StateReservationParty
+		return b.saveReservationStep(ctx, phone, session, b.t(session, "reservation.party_prompt", policy.MaxPartySize))
+
+	case StateReservationParty:
+		party, err := strconv.Atoi(normalized)
+		if err != nil || party < 1 || party > policy.MaxPartySize {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "reservation.invalid_party", policy.MaxPartySize))
+		}
+		session.Booking.PartySize = party
+		session.State = StateReservationConfirm
+		if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		return b.sendReservationConfirm(ctx, phone, session)
+
+	case StateReservationConfirm:
+		switch normalized {
+		case reservationConfirmID, "yes", "ndio", "ndiyo":
+			return b.bookReservation(ctx, phone, session)
+		}
+		return b.sendReservationConfirm(ctx, phone, session)
+	}
+	return nil
+}
+
+// sendReservationConfirm summarises the booking, with its deposit, and asks to confirm
+func (b *BotService) sendReservationConfirm(ctx context.Context, phone string, session *core.Session) error {
+	draft := session.Booking
+	if draft.At == nil {
+		return b.finishReservation(ctx, phone, session, b.t(session, "reservation.cancelled_by_customer"))
+	}
+	summary := b.Reservations.describe(sessionLanguage(session), &core.Reservation{Kind: draft.Kind, PartySize: draft.PartySize, ReservedFor: *draft.At})
+
+	prompt := b.t(session, "reservation.confirm_prompt", summary)
+	confirmTitle := b.t(session, "button.reservation_confirm")
+	if deposit := b.Reservations.Deposit(draft.Kind); deposit > 0 {
+		prompt += b.t(session, "reservation.confirm_deposit", deposit)
+		confirmTitle = b.t(session, "button.reservation_pay")
+	}
+	buttons := []core.Button{
+		{ID: reservationConfirmID, Title: confirmTitle},
+		{ID: reservationCancelID, Title: b.t(session, "button.reservation_cancel")},
+	}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, prompt, buttons); err != nil {
+		return fmt.Errorf("failed to send reservation confirmation: %w", err)
+	}
+	return nil
+}
+
+// bookReservation books the confirmed draft; bookings with a deposit wait for the STK push to be paid
+func (b *BotService) bookReservation(ctx context.Context, phone string, session *core.Session) error {
+	draft := session.Booking
+	if draft.At == nil {
+		return b.finishReservation(ctx, phone, session, b.t(session, "reservation.cancelled_by_customer"))
+	}
+
+	reservation, err := b.Reservations.Book(ctx, phone, draft.Kind, *draft.At, draft.PartySize)
+	if err != nil {
+		log.Printf("Reservation for %s failed: %v", phone, err)
+		return b.finishReservation(ctx, phone, session, b.t(session, "reservation.failed"))
+	}
+
+	summary := b.Reservations.describe(sessionLanguage(session), reservation)
+	if reservation.Status == core.ReservationPendingDeposit {
+		return b.finishReservation(ctx, phone, session, b.t(session, "reservation.deposit_requested", reservation.DepositAmount, summary))
+	}
+	return b.finishReservation(ctx, phone, session, b.t(session, "reservation.confirmed", summary))
+}
+
+// saveReservationStep saves the booking so far and asks the next question
+func (b *BotService) saveReservationStep(ctx context.Context, phone string, session *core.Session, prompt string) error {
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, prompt)
+}
+
+// finishReservation leaves the booking flow and replies with message
+func (b *BotService) finishReservation(ctx context.Context, phone string, session *core.Session, message string) error {
+	session.State = StateStart
+	session.Booking = nil
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, message)
+}
+
+// parseReservationDate reads "today", "tomorrow", a day name (the next one, today included) or a date
+// as 25/12, 25/12/2026 or 2026-12-25, in English or Swahili. It returns midnight of that day in loc.
+func parseReservationDate(message string, now time.Time, loc *time.Location) (time.Time, bool) {
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	switch message {
+	case "today", "tonight", "leo", "leo usiku":
+		return today, true
+	case "tomorrow", "kesho":
+		return today.AddDate(0, 0, 1), true
+	}
+	if weekday, ok := reservationWeekdays[strings.TrimPrefix(message, "this ")]; ok {
+		return today.AddDate(0, 0, (int(weekday)-int(today.Weekday())+7)%7), true
+	}
+
+	for _, layout := range []string{"2006-01-02", "2/1/2006", "2-1-2006"} {
+		if date, err := time.ParseInLocation(layout, message, loc); err == nil {
+			return date, true
+		}
+	}
+	for _, layout := range []string{"2/1", "2-1"} {
+		if date, err := time.ParseInLocation(layout, message, loc); err == nil {
+			// No year: the next time that day comes round
+			date = time.Date(today.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+			if date.Before(today) {
+				date = date.AddDate(1, 0, 0)
+			}
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// restockNotifyPrefix starts the reply ID of "Notify me" on a sold-out product, followed by its ID
+	restockNotifyPrefix = "restock_notify_"
+	// restockOrderPrefix starts the reply ID of "Order now" on a back-in-stock message, followed by the product ID
+	restockOrderPrefix = "restock_order_"
+)
+
+// sendOutOfStock tells the customer a product is sold out. Products sold out on their own stock get a
+// "Notify me" button; cocktails and combos don't, since their stock comes back with other products.
+func (b *BotService) sendOutOfStock(ctx context.Context, phone string, session *core.Session, product *core.Product) error {
+	message := b.t(session, "product.out_of_stock", product.Name)
+	if !b.restockAlertsEnabled(ctx) || product.StockQuantity > 0 || !b.hasOwnStock(ctx, product) {
+		return b.WhatsApp.SendText(ctx, phone, message)
+	}
+
+	buttons := []core.Button{{ID: restockNotifyPrefix + product.ID, Title: b.t(session, "button.restock_notify")}}
+	return b.WhatsApp.SendMenuButtons(ctx, phone, message, buttons)
+}
+
+// hasOwnStock reports whether a product's availability is its own stock quantity rather than its
+// combo components' or recipe ingredients'
+func (b *BotService) hasOwnStock(ctx context.Context, product *core.Product) bool {
+	if product.Category == core.BundleCategory {
+		return false
+	}
+	if b.Recipes != nil {
+		ingredients, err := b.Recipes.GetIngredients(ctx, product.ID)
+		if err != nil || len(ingredients) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// handleRestockNotify subscribes the customer to a sold-out product's back-in-stock message; a product
+// restocked in the meantime is selected straight away
+func (b *BotService) handleRestockNotify(ctx context.Context, phone string, session *core.Session, productID string) error {
+	product, err := b.Repo.GetByID(ctx, productID)
+	if err != nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "product.invalid_option"))
+	}
+	if product.StockQuantity > 0 {
+		return b.selectIntentProduct(ctx, phone, session, product, 0)
+	}
+
+	if err := b.RestockAlerts.Subscribe(ctx, product.ID, phone); err != nil {
+		return fmt.Errorf("failed to subscribe to restock alert: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "restock.subscribed", product.Name))
+}
+
+// handleRestockOrder selects the product named in a back-in-stock message, asking its options and quantity
+func (b *BotService) handleRestockOrder(ctx context.Context, phone string, session *core.Session, productID string) error {
+	product, err := b.Repo.GetByID(ctx, productID)
+	if err != nil || !product.IsActive || product.ArchivedAt != nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "product.invalid_option"))
+	}
+	return b.selectIntentProduct(ctx, phone, session, product, 0)
+}
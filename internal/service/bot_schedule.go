@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// scheduleNowID orders for straight away; scheduleLaterID asks for a pickup time
+	scheduleNowID   = "schedule_now"
+	scheduleLaterID = "schedule_later"
+)
+
+// scheduleTimePattern matches "21:30", "21.30", "9pm", "9:30 pm" and "at 9pm tonight"
+var scheduleTimePattern = regexp.MustCompile(`^(?:at\s+)?(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)?(?:\s+(?:tonight|today|leo|usiku))?$`)
+
+// sendSchedulePrompt asks whether the order is for now or a later pickup time
+func (b *BotService) sendSchedulePrompt(ctx context.Context, phone string, session *core.Session) error {
+	buttons := []core.Button{
+		{
+			ID:    scheduleNowID,
+			Title: b.t(session, "button.schedule_now"),
+		},
+		{
+			ID:    scheduleLaterID,
+			Title: b.t(session, "button.schedule_later"),
+		},
+	}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "schedule.prompt"), buttons); err != nil {
+		return fmt.Errorf("failed to send schedule prompt: %w", err)
+	}
+
+	session.State = StateOrderTime
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleOrderTime handles the ORDER_TIME state - the now/later buttons or a typed pickup time
+func (b *BotService) handleOrderTime(ctx context.Context, phone string, session *core.Session, message string) error {
+	normalized := strings.ToLower(strings.Join(strings.Fields(message), " "))
+
+	switch normalized {
+	case scheduleNowID, "now", "sasa":
+		session.ScheduledFor = nil
+		return b.continueToPayment(ctx, phone, session)
+	case scheduleLaterID, "later", "baadaye":
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "schedule.time_prompt"))
+	}
+
+	now := b.Clock.Now()
+	scheduledFor, ok := parseScheduleTime(normalized, now, reportLocation())
+	if !ok {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "schedule.invalid_time"))
+	}
+	if scheduledFor.Sub(now) < b.scheduleLeadTime() {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "schedule.too_soon", int(b.scheduleLeadTime()/time.Minute)))
+	}
+	if scheduledFor.Sub(now) > b.scheduleMaxAhead() {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "schedule.too_far", int(b.scheduleMaxAhead()/time.Hour)))
+	}
+
+	session.ScheduledFor = &scheduledFor
+	return b.continueToPayment(ctx, phone, session)
+}
+
+// scheduleLeadTime is how far ahead a pre-order must be; the bar gets it this long before the time
+func (b *BotService) scheduleLeadTime() time.Duration {
+	if b.PreOrderLead > 0 {
+		return b.PreOrderLead
+	}
+	return 15 * time.Minute
+}
+
+// scheduleMaxAhead is the furthest ahead a pre-order can be placed
+func (b *BotService) scheduleMaxAhead() time.Duration {
+	if b.PreOrderWindow > 0 {
+		return b.PreOrderWindow
+	}
+	return 12 * time.Hour
+}
+
+// parseScheduleTime reads a clock time in loc and returns its next occurrence after now,
+// so "1am" typed at 23:00 means tomorrow
+func parseScheduleTime(message string, now time.Time, loc *time.Location) (time.Time, bool) {
+	hour, minute, ok := parseClockTime(message)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !scheduled.After(local) {
+		scheduled = scheduled.AddDate(0, 0, 1)
+	}
+	return scheduled.UTC(), true
+}
+
+// parseClockTime reads the hour and minute of a typed clock time such as "21:30" or "9pm"
+func parseClockTime(message string) (int, int, bool) {
+	match := scheduleTimePattern.FindStringSubmatch(message)
+	if match == nil {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	switch match[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+	case "":
+		// A bare hour is evening at a bar: "9" means 21:00
+		if match[2] == "" && hour >= 1 && hour < 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
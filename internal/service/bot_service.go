@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -9,17 +10,52 @@ import (
 	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
 	"github.com/google/uuid"
 )
 
 // BotService handles the bot state machine and message processing
 type BotService struct {
-	Repo      core.ProductRepository
-	Session   core.SessionRepository
-	WhatsApp  core.WhatsAppGateway
-	Payment   core.PaymentGateway
-	OrderRepo core.OrderRepository
-	UserRepo  core.UserRepository
+	Repo           core.ProductRepository
+	Session        core.SessionRepository
+	WhatsApp       core.WhatsAppGateway
+	Payment        core.PaymentGateway
+	OrderRepo      core.OrderRepository
+	UserRepo       core.UserRepository
+	Clock          core.Clock
+	IDs            core.IDGenerator
+	PickupCodes    *PickupCodeGenerator
+	I18n           *i18n.Bundle
+	Options        core.ProductOptionRepository // Optional: serving options asked after product selection
+	Bundles        core.BundleRepository        // Optional: combo stock is checked against component products
+	Recipes        core.RecipeRepository        // Optional: cocktails with a recipe are limited by their ingredients' stock
+	Tax            core.TaxPolicy               // VAT applied at checkout; zero rate means no VAT
+	TipsEnabled    bool                         // Ask for an optional tip before the STK push
+	NotesEnabled   bool                         // Ask for optional special instructions at checkout
+	BarStaff       *BarStaffNotifier            // Optional: offers "Pay at the bar" and sends those orders to staff
+	CheckoutFlowID string                       // Optional: WhatsApp Flow asking quantity, table and M-Pesa number in one form
+	Ordering       core.OrderingStatusStore     // Optional: manager's "bar paused" switch, checked before every checkout
+	Settings       *SettingsService             // Optional: runtime overrides of the session TTL and payment safety-net delay
+	Blocklist      *Blocklist                   // Optional: blocked customers are declined before any processing
+	BlockedReply   string                       // BlockedReplyDecline or BlockedReplySilent
+	Tabs           core.TabRepository           // Optional: shared tabs for customers at one table
+	Feedback       *FeedbackCollector           // Optional: 1-5 ratings asked after orders are completed
+	PreOrders      bool                         // Ask "now or later?" at checkout; later orders are held as SCHEDULED once paid
+	PreOrderLead   time.Duration                // Minimum time ahead for a pre-order (the bar gets it this early)
+	PreOrderWindow time.Duration                // Furthest ahead a pre-order can be placed
+	Delivery       bool                         // Ask "pickup or delivery?" at checkout for orders not placed from a table
+	DeliveryFee    float64                      // Added to the amount charged for delivery orders
+	SessionTTL     int                          // Seconds a session lives after it's saved
+	Interpreter    core.MessageInterpreter      // Optional: LLM fallback for free-text messages the intent parser can't place
+	Locks          *SessionLocker               // Optional: one message per phone at a time across replicas
+	Favorites      core.FavoriteRepository      // Optional: paid baskets saved by name and reordered in one tap
+	Suggestions    *Suggester                   // Optional: chasers often bought with a cocktail, offered once it's added
+	Reservations   *ReservationService          // Optional: table and VIP booth bookings, with deposits taken by STK push
+	AgeGate        bool                         // New customers confirm they're 18 or older before they can order
+	Limits         *PurchaseLimiter             // Optional: per-order caps on categories and products, checked when adding and at checkout
+	RestockAlerts  core.RestockAlertRepository  // Optional: "Notify me" on sold-out products, messaged when they're back in stock
 }
 
 var fixedCategoryOrder = []string{
@@ -39,20 +75,43 @@ const (
 	StateStart                  = "START"
 	StateBrowsing               = "BROWSING"
 	StateSelectingProduct       = "SELECTING_PRODUCT"
+	StateSelectingOption        = "SELECTING_OPTION"
 	StateQuantity               = "QUANTITY"
 	StateConfirmOrder           = "CONFIRM_ORDER"
 	StateWaitingForPaymentPhone = "WAITING_FOR_PAYMENT_PHONE"
+	StateSelectingTip           = "SELECTING_TIP"
+	StateTipCustom              = "TIP_CUSTOM"
+	StateOrderNotes             = "ORDER_NOTES"
+	StateOrderTime              = "ORDER_TIME"
+	StateFulfilment             = "FULFILMENT"
+	StateDeliveryAddress        = "DELIVERY_ADDRESS"
+	StateTabTable               = "TAB_TABLE"
+	StateTabJoinCode            = "TAB_JOIN_CODE"
+	StateSplitCount             = "SPLIT_COUNT"
+	StateSplitPhones            = "SPLIT_PHONES"
+	StateFeedbackComment        = "FEEDBACK_COMMENT"
+	StateFavoriteName           = "FAVORITE_NAME"
+	StateReservationKind        = "RESERVATION_KIND"
+	StateReservationDate        = "RESERVATION_DATE"
+	StateReservationTime        = "RESERVATION_TIME"
+	StateReservationParty       = "RESERVATION_PARTY"
+	StateReservationConfirm     = "RESERVATION_CONFIRM"
 )
 
 // NewBotService creates a new bot service
 func NewBotService(repo core.ProductRepository, session core.SessionRepository, whatsapp core.WhatsAppGateway, payment core.PaymentGateway, orderRepo core.OrderRepository, userRepo core.UserRepository) *BotService {
 	return &BotService{
-		Repo:      repo,
-		Session:   session,
-		WhatsApp:  whatsapp,
-		Payment:   payment,
-		OrderRepo: orderRepo,
-		UserRepo:  userRepo,
+		Repo:        repo,
+		Session:     session,
+		WhatsApp:    whatsapp,
+		Payment:     payment,
+		OrderRepo:   orderRepo,
+		UserRepo:    userRepo,
+		Clock:       core.SystemClock{},
+		IDs:         core.UUIDGenerator{},
+		PickupCodes: NewPickupCodeGenerator(orderRepo, PickupCodeNumeric, 4),
+		I18n:        i18n.Default(),
+		SessionTTL:  7200,
 	}
 }
 
@@ -71,6 +130,12 @@ func buildOrderedCategories(menu map[string][]*core.Product) []string {
 	categories := make([]string, 0, len(fixedCategoryOrder)+len(menu))
 	seen := make(map[string]struct{}, len(fixedCategoryOrder)+len(menu))
 
+	// Combos lead the list, but only when there are active ones to show
+	if len(menu[core.BundleCategory]) > 0 {
+		categories = append(categories, core.BundleCategory)
+		seen[core.BundleCategory] = struct{}{}
+	}
+
 	for _, category := range fixedCategoryOrder {
 		categories = append(categories, category)
 		seen[category] = struct{}{}
@@ -90,11 +155,7 @@ func buildOrderedCategories(menu map[string][]*core.Product) []string {
 
 	categories = append(categories, extraCategories...)
 
-	// Limit to 10 categories (WhatsApp list limit)
-	if len(categories) > 10 {
-		categories = categories[:10]
-	}
-
+	// No truncation here: sendCategoryList pages the list to fit WhatsApp's 10-row limit
 	return categories
 }
 
@@ -107,15 +168,26 @@ func isCategoryInList(categories []string, target string) bool {
 	return false
 }
 
-// HandleIncomingMessage processes incoming WhatsApp messages
-func (b *BotService) HandleIncomingMessage(phone string, message string, messageType string) error {
-	ctx := context.Background()
+// HandleIncomingMessage processes incoming WhatsApp messages, holding the phone's session lock when
+// one is configured. ctx carries the webhook's request ID through to outbound messages and STK pushes.
+func (b *BotService) HandleIncomingMessage(ctx context.Context, phone string, message string, messageType string) error {
+	if b.Locks == nil {
+		return b.handleIncomingMessage(ctx, phone, message, messageType)
+	}
+	return b.Locks.Run(ctx, phone, func(ctx context.Context) error {
+		return b.handleIncomingMessage(ctx, phone, message, messageType)
+	})
+}
+
+func (b *BotService) handleIncomingMessage(ctx context.Context, phone string, message string, messageType string) error {
+	if blocked, err := b.rejectIfBlocked(ctx, phone); blocked {
+		return err
+	}
 
 	// Global Reset Check: Check for reset keywords before processing state
 	normalizedMessage := strings.ToLower(strings.TrimSpace(message))
-	resetKeywords := []string{"hi", "hello", "start", "restart", "reset", "menu", "0"}
 
-	for _, keyword := range resetKeywords {
+	for _, keyword := range b.resetKeywords(ctx) {
 		if normalizedMessage == keyword {
 			// Create a completely fresh session
 			newSession := &core.Session{
@@ -123,12 +195,16 @@ func (b *BotService) HandleIncomingMessage(phone string, message string, message
 				Cart:             []core.CartItem{}, // Explicit empty slice
 				CurrentCategory:  "",
 				CurrentProductID: "",
+				Language:         b.preferredLanguage(ctx, phone), // Language survives resets
 			}
 
 			// Save the fresh session to Redis
-			if err := b.Session.Set(ctx, phone, newSession, 7200); err != nil {
+			if err := b.Session.Set(ctx, phone, newSession, b.sessionTTL(ctx)); err != nil {
 				return fmt.Errorf("failed to reset session: %w", err)
 			}
+			if gated, err := b.requireAge(ctx, phone, newSession, normalizedMessage); gated {
+				return err
+			}
 
 			// Call handleStart with empty string to show welcome (not search)
 			return b.handleStart(ctx, phone, newSession, "")
@@ -140,12 +216,98 @@ func (b *BotService) HandleIncomingMessage(phone string, message string, message
 	if err != nil {
 		// Session doesn't exist, create new one
 		session = &core.Session{
-			State: "START",
-			Cart:  []core.CartItem{},
+			State:    "START",
+			Cart:     []core.CartItem{},
+			Language: b.preferredLanguage(ctx, phone),
 		}
-		if err := b.Session.Set(ctx, phone, session, 7200); err != nil { // 2 hours TTL
+		if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
 			return fmt.Errorf("failed to create session: %w", err)
 		}
+
+		// A button or list reply means the customer was mid-order when the session expired
+		if (messageType == "interactive" || messageType == FlowReplyMessageType) && !strings.HasPrefix(normalizedMessage, "retry_pay_") {
+			if gated, err := b.requireAge(ctx, phone, session, normalizedMessage); gated {
+				return err
+			}
+			if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "session.expired")); err != nil {
+				return fmt.Errorf("failed to send session expired message: %w", err)
+			}
+			return b.handleStart(ctx, phone, session, "")
+		}
+	}
+
+	// Nothing but the language command works until the customer confirms they're 18 or older
+	if _, ok := parseLanguageCommand(normalizedMessage); !ok {
+		if gated, err := b.requireAge(ctx, phone, session, normalizedMessage); gated {
+			return err
+		}
+	}
+
+	// Submitted checkout forms carry their own product check
+	if messageType == FlowReplyMessageType {
+		return b.handleCheckoutForm(ctx, phone, session, message)
+	}
+	if messageType == LocationMessageType {
+		return b.handleSharedLocation(ctx, phone, session, message)
+	}
+
+	// Language command ("lugha" / "language") works from any state
+	if requested, ok := parseLanguageCommand(normalizedMessage); ok {
+		return b.handleLanguageCommand(ctx, phone, session, requested)
+	}
+
+	// Cart reminder buttons work from any state
+	if normalizedMessage == cartReminderStopID || normalizedMessage == "stop reminders" {
+		return b.handleStopCartReminders(ctx, phone, session)
+	}
+	// Broadcast consent ("subscribe" / "unsubscribe") works from any state
+	if optIn, ok := parseMarketingCommand(normalizedMessage); ok {
+		return b.handleMarketingConsent(ctx, phone, session, optIn)
+	}
+	// Order ratings ("rate_4" rows or "rate 4") work from any state
+	if rating, ok := parseRatingCommand(normalizedMessage); ok && b.Feedback != nil {
+		return b.handleFeedbackRating(ctx, phone, session, rating)
+	}
+	// Saved favorites ("favorites", the save button after payment, a favorite's row) work from any state
+	if b.Favorites != nil {
+		switch {
+		case isFavoritesCommand(normalizedMessage):
+			return b.handleFavoritesList(ctx, phone, session)
+		case strings.HasPrefix(normalizedMessage, favoriteSavePrefix):
+			return b.handleFavoriteSave(ctx, phone, session, strings.TrimPrefix(normalizedMessage, favoriteSavePrefix))
+		case strings.HasPrefix(normalizedMessage, favoriteOrderPrefix):
+			return b.handleFavoriteReorder(ctx, phone, session, strings.TrimPrefix(normalizedMessage, favoriteOrderPrefix))
+		}
+	}
+	// "book" starts a table or VIP booth reservation from any state
+	if b.Reservations != nil && isReservationCommand(normalizedMessage) {
+		return b.handleReservationStart(ctx, phone, session)
+	}
+	// Group tab commands and buttons work from any state
+	if b.Tabs != nil {
+		if handled, err := b.handleTabCommand(ctx, phone, session, message); handled {
+			return err
+		}
+	}
+
+	if normalizedMessage == "checkout" && len(session.Cart) > 0 && session.State != "CONFIRM_ORDER" {
+		session.State = "CONFIRM_ORDER"
+		return b.handleCheckout(ctx, phone, session)
+	}
+
+	// "People also add" button after a cocktail is added
+	if strings.HasPrefix(normalizedMessage, suggestionAddPrefix) && b.Suggestions != nil {
+		return b.handleSuggestionAdd(ctx, phone, session, strings.TrimPrefix(normalizedMessage, suggestionAddPrefix))
+	}
+
+	// "Notify me" on a sold-out product, and "Order now" on the message saying it's back
+	if b.RestockAlerts != nil {
+		switch {
+		case strings.HasPrefix(normalizedMessage, restockNotifyPrefix):
+			return b.handleRestockNotify(ctx, phone, session, strings.TrimPrefix(normalizedMessage, restockNotifyPrefix))
+		case strings.HasPrefix(normalizedMessage, restockOrderPrefix):
+			return b.handleRestockOrder(ctx, phone, session, strings.TrimPrefix(normalizedMessage, restockOrderPrefix))
+		}
 	}
 
 	// Handle Retry Payment button (from 15s timeout fallback)
@@ -154,6 +316,13 @@ func (b *BotService) HandleIncomingMessage(phone string, message string, message
 		return b.handleRetryPayment(ctx, phone, session, orderID)
 	}
 
+	// Typed orders ("2 mojitos please"), category names and cart commands skip the numbered lists
+	if messageType == "text" {
+		if handled, err := b.handleIntent(ctx, phone, session, message); handled {
+			return err
+		}
+	}
+
 	// Route based on state
 	switch session.State {
 	case "START", "":
@@ -164,16 +333,44 @@ func (b *BotService) HandleIncomingMessage(phone string, message string, message
 		return b.handleBrowsing(ctx, phone, session, message)
 	case "SELECTING_PRODUCT":
 		return b.handleSelectingProduct(ctx, phone, session, message)
+	case StateSelectingOption:
+		return b.handleSelectingOption(ctx, phone, session, message)
 	case "QUANTITY":
 		return b.handleQuantity(ctx, phone, session, message)
 	case "CONFIRM_ORDER":
 		return b.handleConfirmOrder(ctx, phone, session, message)
 	case StateWaitingForPaymentPhone:
 		return b.handlePaymentPhoneInput(ctx, phone, session, message)
+	case StateTabTable:
+		return b.handleTabTable(ctx, phone, session, message)
+	case StateTabJoinCode:
+		return b.handleTabJoinCode(ctx, phone, session, message)
+	case StateOrderNotes:
+		return b.handleOrderNotes(ctx, phone, session, message)
+	case StateOrderTime:
+		return b.handleOrderTime(ctx, phone, session, message)
+	case StateFulfilment:
+		return b.handleFulfilment(ctx, phone, session, message)
+	case StateDeliveryAddress:
+		return b.handleDeliveryAddress(ctx, phone, session, message)
+	case StateSelectingTip:
+		return b.handleSelectingTip(ctx, phone, session, message)
+	case StateTipCustom:
+		return b.handleTipCustom(ctx, phone, session, message)
+	case StateSplitCount:
+		return b.handleSplitCount(ctx, phone, session, message)
+	case StateSplitPhones:
+		return b.handleSplitPhoneInput(ctx, phone, session, message)
+	case StateFeedbackComment:
+		return b.handleFeedbackComment(ctx, phone, session, message)
+	case StateFavoriteName:
+		return b.handleFavoriteName(ctx, phone, session, message)
+	case StateReservationKind, StateReservationDate, StateReservationTime, StateReservationParty, StateReservationConfirm:
+		return b.handleReservation(ctx, phone, session, message)
 	default:
 		// Unknown state, reset to START
 		session.State = "START"
-		b.Session.Set(ctx, phone, session, 7200)
+		b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 		return b.handleStart(ctx, phone, session, message)
 	}
 }
@@ -191,15 +388,16 @@ func (b *BotService) handleStart(ctx context.Context, phone string, session *cor
 		}
 
 		categories := buildOrderedCategories(menu)
+		session.Page = 0
 
-		// Send category list directly
-		if err := b.WhatsApp.SendCategoryList(ctx, phone, categories); err != nil {
+		// Send the greeting with the category list
+		if err := b.sendCategoryListWithHeader(ctx, phone, session, categories, "menu.greeting"); err != nil {
 			return fmt.Errorf("failed to send categories: %w", err)
 		}
 
 		// Set state to BROWSING
 		session.State = "BROWSING"
-		return b.Session.Set(ctx, phone, session, 7200)
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 	}
 
 	// If message is "order_drinks" button or contains "order", DIRECTLY show menu
@@ -211,15 +409,16 @@ func (b *BotService) handleStart(ctx context.Context, phone string, session *cor
 		}
 
 		categories := buildOrderedCategories(menu)
+		session.Page = 0
 
 		// Send category list directly (no welcome message needed)
-		if err := b.WhatsApp.SendCategoryList(ctx, phone, categories); err != nil {
+		if err := b.sendCategoryList(ctx, phone, session, categories); err != nil {
 			return fmt.Errorf("failed to send categories: %w", err)
 		}
 
 		// Set state to BROWSING (skip MENU state)
 		session.State = "BROWSING"
-		return b.Session.Set(ctx, phone, session, 7200)
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 	}
 
 	// Otherwise, treat the message as a search query
@@ -233,11 +432,11 @@ func (b *BotService) handleStart(ctx context.Context, phone string, session *cor
 
 	// If no results found, send error message and "Order Drinks" button
 	if len(products) == 0 {
-		noResultsMsg := fmt.Sprintf("❌ No products found for '%s'.\n\n💡 Try:\n• Typing just one word (e.g., 'Gin', 'Water')\n• Browsing the full menu below", searchQuery)
+		noResultsMsg := b.t(session, "search.no_results", searchQuery)
 		buttons := []core.Button{
 			{
 				ID:    "order_drinks",
-				Title: "View Full Menu",
+				Title: b.t(session, "button.view_full_menu"),
 			},
 		}
 
@@ -246,18 +445,18 @@ func (b *BotService) handleStart(ctx context.Context, phone string, session *cor
 		}
 
 		// Stay in START state
-		return b.Session.Set(ctx, phone, session, 7200)
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 	}
 
-	// Sort products alphabetically
-	sortedProducts := sortProductsAlphabetically(products)
+	// Search results stay in relevance order (best match first)
+	sortedProducts := products
 
-	// Build formatted text message with numbered list
-	productList := fmt.Sprintf("🔍 Search results for '*%s*':\n\n", searchQuery)
-	for i, product := range sortedProducts {
-		productList += fmt.Sprintf("%d. %s - KES %.0f\n", i+1, product.Name, product.Price)
-	}
-	productList += "\nReply with the number or name to add to cart."
+	// Build formatted text message with numbered list (first page)
+	session.Page = 0
+	productList := b.productPageText(session,
+		b.t(session, "search.results_header", searchQuery),
+		b.t(session, "search.reply_hint"),
+		sortedProducts)
 
 	// Send product list as text message
 	if err := b.WhatsApp.SendText(ctx, phone, productList); err != nil {
@@ -268,7 +467,7 @@ func (b *BotService) handleStart(ctx context.Context, phone string, session *cor
 	// We'll use a special category name that includes all search results
 	session.CurrentCategory = "_SEARCH_" + searchQuery
 	session.State = "SELECTING_PRODUCT"
-	return b.Session.Set(ctx, phone, session, 7200)
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 }
 
 // handleMenu handles the MENU state - shows categories
@@ -284,20 +483,21 @@ func (b *BotService) handleMenu(ctx context.Context, phone string, session *core
 		}
 
 		categories := buildOrderedCategories(menu)
+		session.Page = 0
 
-		errorMsg := "That menu is expired. Here is the latest one."
+		errorMsg := b.t(session, "menu.expired")
 		// Send error message first, then the list
 		if err := b.WhatsApp.SendText(ctx, phone, errorMsg); err != nil {
 			return fmt.Errorf("failed to send error message: %w", err)
 		}
 
-		if err := b.WhatsApp.SendCategoryList(ctx, phone, categories); err != nil {
+		if err := b.sendCategoryList(ctx, phone, session, categories); err != nil {
 			return fmt.Errorf("failed to send categories: %w", err)
 		}
 
 		// Set state to BROWSING
 		session.State = "BROWSING"
-		return b.Session.Set(ctx, phone, session, 7200)
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 	}
 
 	// Get menu (grouped by category)
@@ -307,15 +507,16 @@ func (b *BotService) handleMenu(ctx context.Context, phone string, session *core
 	}
 
 	categories := buildOrderedCategories(menu)
+	session.Page = 0
 
 	// Send category list using interactive list
-	if err := b.WhatsApp.SendCategoryList(ctx, phone, categories); err != nil {
+	if err := b.sendCategoryList(ctx, phone, session, categories); err != nil {
 		return fmt.Errorf("failed to send categories: %w", err)
 	}
 
 	// Set state to BROWSING
 	session.State = "BROWSING"
-	return b.Session.Set(ctx, phone, session, 7200)
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 }
 
 // handleBrowsing handles the BROWSING state - shows products in a category
@@ -330,22 +531,32 @@ func (b *BotService) handleBrowsing(ctx context.Context, phone string, session *
 	selectedCategory := strings.TrimSpace(message)
 
 	orderedCategories := buildOrderedCategories(menu)
+
+	// "More categories" row or "more" keyword - show the next page of categories
+	if isMoreCommand(strings.ToLower(selectedCategory)) {
+		session.Page++
+		if err := b.sendCategoryList(ctx, phone, session, orderedCategories); err != nil {
+			return fmt.Errorf("failed to send categories: %w", err)
+		}
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+
 	if !isCategoryInList(orderedCategories, selectedCategory) {
 		// Invalid category - resend the category list
 		categories := orderedCategories
 
-		errorMsg := "That menu is expired. Here is the latest one."
+		errorMsg := b.t(session, "menu.expired")
 		// Send error message first, then the list
 		if err := b.WhatsApp.SendText(ctx, phone, errorMsg); err != nil {
 			return fmt.Errorf("failed to send error message: %w", err)
 		}
 
-		if err := b.WhatsApp.SendCategoryList(ctx, phone, categories); err != nil {
+		if err := b.sendCategoryList(ctx, phone, session, categories); err != nil {
 			return fmt.Errorf("failed to send categories: %w", err)
 		}
 
 		// Keep state as BROWSING
-		return b.Session.Set(ctx, phone, session, 7200)
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 	}
 
 	// Category is valid in UI order; it may still have no active products in DB.
@@ -353,18 +564,18 @@ func (b *BotService) handleBrowsing(ctx context.Context, phone string, session *
 
 	// Get products for this category
 	if len(products) == 0 {
-		return b.WhatsApp.SendText(ctx, phone, "No products available in this category.")
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "category.empty"))
 	}
 
 	// Sort products alphabetically by name (A-Z)
 	sortedProducts := sortProductsAlphabetically(products)
 
-	// Build formatted text message with numbered list
-	productList := fmt.Sprintf("Products in *%s*:\n\n", selectedCategory)
-	for i, product := range sortedProducts {
-		productList += fmt.Sprintf("%d. %s - KES %.0f\n", i+1, product.Name, product.Price)
-	}
-	productList += "\nReply with the product name or number to add to cart."
+	// Build formatted text message with numbered list (first page)
+	session.Page = 0
+	productList := b.productPageText(session,
+		b.t(session, "category.header", selectedCategory),
+		b.t(session, "category.reply_hint"),
+		sortedProducts)
 
 	// Send product list as text message
 	if err := b.WhatsApp.SendText(ctx, phone, productList); err != nil {
@@ -374,7 +585,7 @@ func (b *BotService) handleBrowsing(ctx context.Context, phone string, session *
 	// Update session with current category
 	session.CurrentCategory = selectedCategory
 	session.State = "SELECTING_PRODUCT"
-	return b.Session.Set(ctx, phone, session, 7200)
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 }
 
 // handleSelectingProduct handles the SELECTING_PRODUCT state - user selects a product
@@ -392,9 +603,9 @@ func (b *BotService) handleSelectingProduct(ctx context.Context, phone string, s
 			return fmt.Errorf("failed to search products: %w", err)
 		}
 		if len(products) == 0 {
-			return b.WhatsApp.SendText(ctx, phone, "No products available. Please search again.")
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "product.empty_search"))
 		}
-		sortedProducts = sortProductsAlphabetically(products)
+		sortedProducts = products // Relevance order, as listed
 	} else {
 		// Get products from current category (normal menu flow)
 		menu, err := b.Repo.GetMenu(ctx)
@@ -404,7 +615,7 @@ func (b *BotService) handleSelectingProduct(ctx context.Context, phone string, s
 
 		products := menu[session.CurrentCategory]
 		if len(products) == 0 {
-			return b.WhatsApp.SendText(ctx, phone, "No products available. Please select another category.")
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "product.empty_category"))
 		}
 
 		// Sort products alphabetically (same order as displayed in handleBrowsing)
@@ -416,6 +627,22 @@ func (b *BotService) handleSelectingProduct(ctx context.Context, phone string, s
 	messageTrimmed := strings.TrimSpace(message)
 	messageLower := strings.ToLower(messageTrimmed)
 
+	// "more" keyword - show the next page of the product list
+	if isMoreCommand(messageLower) {
+		header := b.t(session, "category.header", session.CurrentCategory)
+		replyHint := b.t(session, "category.reply_hint")
+		if isSearchMode {
+			header = b.t(session, "search.results_header", strings.TrimPrefix(session.CurrentCategory, "_SEARCH_"))
+			replyHint = b.t(session, "search.reply_hint")
+		}
+
+		session.Page++
+		if err := b.WhatsApp.SendText(ctx, phone, b.productPageText(session, header, replyHint, sortedProducts)); err != nil {
+			return fmt.Errorf("failed to send products: %w", err)
+		}
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+
 	// Try UUID first (from interactive list reply - backward compatibility)
 	if productID, err := uuid.Parse(messageTrimmed); err == nil {
 		// Valid UUID - fetch product by ID
@@ -476,34 +703,31 @@ func (b *BotService) handleSelectingProduct(ctx context.Context, phone string, s
 
 	if selectedProduct == nil {
 		// Invalid selection - send short error message (don't resend list)
-		errorMsg := "Invalid option. Please reply with the number (e.g., '1') or the name of the drink."
+		errorMsg := b.t(session, "product.invalid_option")
 		if err := b.WhatsApp.SendText(ctx, phone, errorMsg); err != nil {
 			return fmt.Errorf("failed to send error message: %w", err)
 		}
 
 		// Keep state as SELECTING_PRODUCT
-		return b.Session.Set(ctx, phone, session, 7200)
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 	}
 
-	// Check stock
-	if selectedProduct.StockQuantity <= 0 {
-		return b.WhatsApp.SendText(ctx, phone, fmt.Sprintf("Sorry, %s is out of stock. Please select another product.", selectedProduct.Name))
+	// Check stock (combos are limited by their components, cocktails by their recipe)
+	available, err := b.availableStock(ctx, selectedProduct)
+	if err != nil {
+		return err
+	}
+	if available <= 0 {
+		return b.sendOutOfStock(ctx, phone, session, selectedProduct)
 	}
 
 	// Store selected product
 	session.CurrentProductID = selectedProduct.ID
+	session.PendingModifiers = nil
+	session.PendingQuantity = 0
 
-	// Ask for quantity
-	quantityMsg := fmt.Sprintf("You selected: *%s*\nPrice: KES %.0f\n\nHow many would you like? (Enter a number)",
-		selectedProduct.Name, selectedProduct.Price)
-
-	if err := b.WhatsApp.SendText(ctx, phone, quantityMsg); err != nil {
-		return fmt.Errorf("failed to send quantity prompt: %w", err)
-	}
-
-	// Set state to QUANTITY
-	session.State = "QUANTITY"
-	return b.Session.Set(ctx, phone, session, 7200)
+	// Ask for serving options (if any are configured), then quantity
+	return b.promptNextOption(ctx, phone, session, selectedProduct)
 }
 
 // handleQuantity handles the QUANTITY state - user enters quantity
@@ -512,44 +736,71 @@ func (b *BotService) handleQuantity(ctx context.Context, phone string, session *
 	quantity, err := strconv.Atoi(strings.TrimSpace(message))
 	if err != nil || quantity <= 0 {
 		// Invalid input - forgiving state: keep in QUANTITY
-		return b.WhatsApp.SendText(ctx, phone, "Please enter a valid number (e.g., 2)")
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "quantity.invalid"))
 	}
 
+	return b.addToCart(ctx, phone, session, quantity)
+}
+
+// addToCart adds quantity of the current product (with its chosen options) to the cart when in stock,
+// then shows the cart with Add More / Checkout buttons
+func (b *BotService) addToCart(ctx context.Context, phone string, session *core.Session, quantity int) error {
 	// Get product details
 	product, err := b.Repo.GetByID(ctx, session.CurrentProductID)
 	if err != nil {
 		return fmt.Errorf("failed to get product: %w", err)
 	}
 
-	// Check stock
-	if product.StockQuantity < quantity {
-		return b.WhatsApp.SendText(ctx, phone,
-			fmt.Sprintf("Sorry, only %d available in stock. Please enter a smaller quantity.", product.StockQuantity))
+	// Check stock (combos are limited by their components, cocktails by their recipe)
+	available, err := b.availableStock(ctx, product)
+	if err != nil {
+		return err
+	}
+	if available < quantity {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "quantity.insufficient_stock", available))
 	}
 
-	// Add to cart
+	// Add to cart (unit price includes any serving option adjustments)
 	cartItem := core.CartItem{
 		ProductID: product.ID,
 		Quantity:  quantity,
 		Name:      product.Name,
-		Price:     product.Price,
+		Price:     product.Price + modifiersPriceDelta(session.PendingModifiers),
+		Modifiers: session.PendingModifiers,
 	}
 
-	session.Cart = append(session.Cart, cartItem)
+	// Purchase limits count what's already in the cart; the customer can type a smaller quantity
+	cart := append(append([]core.CartItem{}, session.Cart...), cartItem)
+	if refused, err := b.checkPurchaseLimits(ctx, phone, session, cart, quantity); refused || err != nil {
+		return err
+	}
 
-	// Calculate total
-	total := 0.0
-	for _, item := range session.Cart {
-		total += item.Price * float64(item.Quantity)
+	session.Cart = cart
+	session.PendingModifiers = nil
+
+	if err := b.sendCartSummary(ctx, phone, session, b.t(session, "cart.added_header")); err != nil {
+		return err
 	}
+	b.sendSuggestion(ctx, phone, session, product)
+	return nil
+}
+
+// sendCartSummary shows every cart item with its price and the total under header, with Add More /
+// Checkout buttons
+func (b *BotService) sendCartSummary(ctx context.Context, phone string, session *core.Session, header string) error {
+	// Calculate total (VAT on top of menu prices is shown separately)
+	tax, total := b.Tax.OrderTotals(cartSubtotal(session.Cart))
 
 	// Build cart summary showing all items with prices before total
-	cartSummary := "✅ Added to cart!\n\n📦 Your cart:\n"
+	cartSummary := header
 	for _, item := range session.Cart {
 		itemTotal := item.Price * float64(item.Quantity)
-		cartSummary += fmt.Sprintf("%s x%d = KES %.0f\n", item.Name, item.Quantity, itemTotal)
+		cartSummary += fmt.Sprintf("%s x%d = KES %.0f\n", itemDisplayName(item.Name, item.Modifiers), item.Quantity, itemTotal)
+	}
+	cartSummary += b.t(session, "cart.total", total)
+	if tax > 0 && !b.Tax.Inclusive {
+		cartSummary += b.t(session, "cart.vat_added", tax, b.Tax.Rate)
 	}
-	cartSummary += fmt.Sprintf("\n💰 Cart total: KES %.0f", total)
 
 	// Confirm addition with interactive buttons
 	confirmMsg := cartSummary
@@ -557,21 +808,29 @@ func (b *BotService) handleQuantity(ctx context.Context, phone string, session *
 	buttons := []core.Button{
 		{
 			ID:    "add_more",
-			Title: "Add More",
+			Title: b.t(session, "button.add_more"),
 		},
 		{
 			ID:    "checkout",
-			Title: "Checkout",
+			Title: b.t(session, "button.checkout"),
 		},
 	}
 
+	// Tab members can put the drinks on their table's tab instead of paying now
+	if len(session.TabItemIDs) == 0 && b.onOpenTab(ctx, phone) {
+		buttons = append(buttons, core.Button{
+			ID:    tabAddID,
+			Title: b.t(session, "button.tab_add"),
+		})
+	}
+
 	if err := b.WhatsApp.SendMenuButtons(ctx, phone, confirmMsg, buttons); err != nil {
 		return fmt.Errorf("failed to send confirmation: %w", err)
 	}
 
 	// Set state to CONFIRM_ORDER
 	session.State = "CONFIRM_ORDER"
-	return b.Session.Set(ctx, phone, session, 7200)
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 }
 
 // handleConfirmOrder handles the CONFIRM_ORDER state - user can add more or checkout
@@ -588,7 +847,11 @@ func (b *BotService) handleConfirmOrder(ctx context.Context, phone string, sessi
 		return b.handleCheckout(ctx, phone, session)
 	}
 
-	// Handle payment confirmation buttons (pay_self, pay_other)
+	// Handle payment confirmation buttons (pay_form, pay_self, pay_other, pay_split, pay_bar)
+	if messageLower == payFormID && session.PaymentPhone != "" {
+		return b.processPayment(ctx, phone, session, session.PaymentPhone)
+	}
+
 	if messageLower == "pay_self" {
 		return b.handlePaySelf(ctx, phone, session)
 	}
@@ -597,78 +860,138 @@ func (b *BotService) handleConfirmOrder(ctx context.Context, phone string, sessi
 		return b.handlePayOther(ctx, phone, session)
 	}
 
+	if messageLower == "pay_split" {
+		return b.handleSplitBill(ctx, phone, session)
+	}
+
+	if b.BarStaff != nil && (messageLower == payAtBarID || messageLower == "bar") {
+		return b.handlePayAtBar(ctx, phone, session)
+	}
+
 	// Invalid input - resend buttons
-	confirmMsg := "Please select an option:"
+	confirmMsg := b.t(session, "cart.select_option")
 	buttons := []core.Button{
 		{
 			ID:    "add_more",
-			Title: "Add More",
+			Title: b.t(session, "button.add_more"),
 		},
 		{
 			ID:    "checkout",
-			Title: "Checkout",
+			Title: b.t(session, "button.checkout"),
 		},
 	}
 	return b.WhatsApp.SendMenuButtons(ctx, phone, confirmMsg, buttons)
 }
 
-// generatePickupCode generates a random 4-digit pickup code
-func generatePickupCode() string {
-	return fmt.Sprintf("%04d", time.Now().UnixNano()%10000)
-}
-
 // handleCheckout initiates the checkout process by asking for payment number confirmation
 func (b *BotService) handleCheckout(ctx context.Context, phone string, session *core.Session) error {
 	// Validate cart
 	if len(session.Cart) == 0 {
-		return b.WhatsApp.SendText(ctx, phone, "Your cart is empty. Please add items first.")
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.empty"))
+	}
+
+	// The bar can pause ordering (out of ice, M-Pesa down); the cart is kept for later
+	if paused, err := b.rejectIfOrderingPaused(ctx, phone, session); paused || err != nil {
+		return err
+	}
+
+	// Limits may have changed since the drinks were added, or a favorite brought more; tabs already served are exempt
+	if session.TabID == "" {
+		if refused, err := b.checkPurchaseLimits(ctx, phone, session, session.Cart, 0); refused || err != nil {
+			return err
+		}
 	}
 
 	// DUPLICATE CHECKOUT PREVENTION: Check if user has a pending order
 	if session.PendingOrderID != "" {
 		// Check if the order is still pending
 		order, err := b.OrderRepo.GetByID(ctx, session.PendingOrderID)
-		if err == nil && order != nil && order.Status == core.OrderStatusPending {
+		if err == nil && order != nil && (order.Status == core.OrderStatusPending || order.Status == core.OrderStatusPartiallyPaid) {
 			// Order still pending - show helpful message with retry option
-			msg := "⏳ *Payment Already Pending*\n\n" +
-				"An M-Pesa prompt was already sent for your order.\n\n" +
-				"*What to do:*\n" +
-				"1. Check your phone for the M-Pesa prompt\n" +
-				"2. Enter your PIN to complete payment\n" +
-				"3. If you missed it, wait 30 seconds then try again\n\n" +
-				"_If the prompt expired, type 'hi' to start fresh._"
-			return b.WhatsApp.SendText(ctx, phone, msg)
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "payment.already_pending"))
 		}
 		// Order is no longer pending (paid, failed, or cancelled) - clear and continue
 		session.PendingOrderID = ""
 	}
 
-	// Calculate total
-	total := 0.0
-	for _, item := range session.Cart {
-		total += item.Price * float64(item.Quantity)
+	// Ask for special instructions first; the tip and payment prompts follow
+	session.OrderNotes = ""
+	if b.NotesEnabled {
+		return b.sendNotesPrompt(ctx, phone, session)
 	}
+	return b.continueCheckout(ctx, phone, session)
+}
+
+// continueCheckout asks whether the order is for now or later when pre-orders are on, then continues to payment
+func (b *BotService) continueCheckout(ctx context.Context, phone string, session *core.Session) error {
+	session.ScheduledFor = nil
+	if b.PreOrders {
+		return b.sendSchedulePrompt(ctx, phone, session)
+	}
+	return b.continueToPayment(ctx, phone, session)
+}
+
+// continueToPayment asks pickup or delivery when delivery is offered, then continues to the tip
+func (b *BotService) continueToPayment(ctx context.Context, phone string, session *core.Session) error {
+	clearDelivery(session)
+	if b.offersDelivery(session) {
+		return b.sendFulfilmentPrompt(ctx, phone, session)
+	}
+	return b.continueToTip(ctx, phone, session)
+}
+
+// continueToTip offers a tip, or goes straight to the payment prompt when tips are off
+func (b *BotService) continueToTip(ctx context.Context, phone string, session *core.Session) error {
+	session.TipAmount = 0
+	if b.TipsEnabled {
+		return b.sendTipPrompt(ctx, phone, session)
+	}
+	return b.sendPaymentPrompt(ctx, phone, session)
+}
+
+// sendPaymentPrompt shows the amount to charge and asks which M-Pesa number pays it (or, when enabled, to pay at the bar)
+func (b *BotService) sendPaymentPrompt(ctx context.Context, phone string, session *core.Session) error {
+	_, total := b.checkoutTotals(session)
 
 	// Send button prompt asking which number to charge
-	promptMsg := fmt.Sprintf("Your total is *KES %.0f*.\n\nWhich M-Pesa number should we charge?", total)
+	promptMsg := b.t(session, "payment.total_prompt", total)
+	if session.ScheduledFor != nil {
+		promptMsg += b.t(session, "schedule.summary", FormatScheduledTime(*session.ScheduledFor))
+	}
+	if session.DeliveryAddress != "" {
+		promptMsg += b.t(session, "delivery.summary", session.DeliveryAddress, session.DeliveryFee)
+	}
 
 	buttons := []core.Button{
 		{
 			ID:    "pay_self",
-			Title: "Use My Number",
+			Title: b.t(session, "button.pay_self"),
 		},
 		{
 			ID:    "pay_other",
-			Title: "Different Number",
+			Title: b.t(session, "button.pay_other"),
+		},
+		{
+			ID:    "pay_split",
+			Title: b.t(session, "button.pay_split"),
 		},
 	}
 
-	if err := b.WhatsApp.SendMenuButtons(ctx, phone, promptMsg, buttons); err != nil {
+	// The number given in the checkout form replaces "Use My Number"
+	if session.PaymentPhone != "" {
+		buttons[0] = core.Button{
+			ID:    payFormID,
+			Title: b.t(session, "button.pay_number", localPhoneNumber(session.PaymentPhone)),
+		}
+	}
+
+	if err := b.sendPaymentOptions(ctx, phone, session, promptMsg, buttons); err != nil {
 		return fmt.Errorf("failed to send payment prompt: %w", err)
 	}
 
-	// Keep state as CONFIRM_ORDER (user will respond with button click)
-	return b.Session.Set(ctx, phone, session, 7200)
+	// Back to CONFIRM_ORDER (user will respond with button click)
+	session.State = StateConfirmOrder
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 }
 
 // handlePaySelf handles when user chooses to use their own WhatsApp number
@@ -680,7 +1003,7 @@ func (b *BotService) handlePaySelf(ctx context.Context, phone string, session *c
 // handlePayOther handles when user chooses to use a different number
 func (b *BotService) handlePayOther(ctx context.Context, phone string, session *core.Session) error {
 	// Prompt for phone number
-	promptMsg := "Please type the Safaricom M-Pesa number you want to use (e.g., 0712345678)."
+	promptMsg := b.t(session, "payment.enter_phone")
 
 	if err := b.WhatsApp.SendText(ctx, phone, promptMsg); err != nil {
 		return fmt.Errorf("failed to send phone prompt: %w", err)
@@ -688,16 +1011,16 @@ func (b *BotService) handlePayOther(ctx context.Context, phone string, session *
 
 	// Set state to wait for phone input
 	session.State = StateWaitingForPaymentPhone
-	return b.Session.Set(ctx, phone, session, 7200)
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
 }
 
 // handlePaymentPhoneInput handles user input when waiting for alternative payment phone
 func (b *BotService) handlePaymentPhoneInput(ctx context.Context, phone string, session *core.Session, message string) error {
 	// Normalize and validate the phone number
-	normalizedPhone, err := normalizePhone(message)
-	if err != nil || !isValidKenyanMobile(normalizedPhone) {
+	normalizedPhone, err := msisdn.Normalize(message)
+	if err != nil || !msisdn.IsKenyanMobile(normalizedPhone) {
 		// Invalid phone number - ask to try again (keep state)
-		errorMsg := "That doesn't look like a valid phone number. Please try again (e.g., 0712345678)."
+		errorMsg := b.t(session, "payment.invalid_phone")
 		return b.WhatsApp.SendText(ctx, phone, errorMsg)
 	}
 
@@ -711,13 +1034,25 @@ func (b *BotService) handleRetryPayment(ctx context.Context, whatsappPhone strin
 	// Fetch the existing order
 	order, err := b.OrderRepo.GetByID(ctx, orderID)
 	if err != nil {
-		b.WhatsApp.SendText(ctx, whatsappPhone, "Order not found. Please start a new order.")
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "order.not_found"))
 		return nil
 	}
 
+	// Split bills resend the prompts of the payers who haven't paid yet
+	if order.Status == core.OrderStatusPending || order.Status == core.OrderStatusPartiallyPaid {
+		shares, err := b.OrderRepo.GetPaymentShares(ctx, orderID)
+		if err != nil {
+			b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "payment.system_busy"))
+			return nil
+		}
+		if len(shares) > 0 {
+			return b.retrySplitShares(ctx, whatsappPhone, session, order, shares)
+		}
+	}
+
 	// Check if order is still PENDING (payment not yet completed)
 	if order.Status != core.OrderStatusPending {
-		b.WhatsApp.SendText(ctx, whatsappPhone, "This order has already been processed.")
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "order.already_processed"))
 		return nil
 	}
 
@@ -725,90 +1060,69 @@ func (b *BotService) handleRetryPayment(ctx context.Context, whatsappPhone strin
 	err = b.Payment.InitiateSTKPush(ctx, orderID, order.CustomerPhone, order.TotalAmount)
 	if err != nil {
 		// Send error message - safe because no STK push was sent
-		b.WhatsApp.SendText(ctx, whatsappPhone, "⚠️ Payment system busy. Please try again in a moment.")
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "payment.system_busy"))
 		return nil
 	}
 
-	// SAFETY NET: Launch goroutine to check order status after 45 seconds
+	// SAFETY NET: Launch goroutine to check order status after the safety-net delay (45 seconds by default)
 	// Note: M-Pesa STK prompts can take 20-40 seconds to arrive, so we wait longer
+	lang := sessionLanguage(session)
+	checkCtx := core.DetachRequestID(ctx)
+	delay := b.safetyNetDelay(ctx)
 	go func(oID string, waPhone string) {
-		time.Sleep(45 * time.Second)
+		defer reporting.Recover(checkCtx, "payment.retry_safety_net")
+		time.Sleep(delay)
 
-		checkCtx := context.Background()
 		order, err := b.OrderRepo.GetByID(checkCtx, oID)
 		if err != nil {
+			reporting.CaptureError(checkCtx, "payment.retry_safety_net", err)
 			return
 		}
 
 		if order.Status == core.OrderStatusPending {
 			// Order still pending - send retry button again
-			timeoutMsg := "⏳ *Waiting for M-Pesa*\n\n" +
-				"The payment prompt can take up to 60 seconds to appear.\n\n" +
-				"*If it hasn't appeared yet:*\n" +
-				"• Check your phone for the M-Pesa prompt\n" +
-				"• Make sure you have network signal\n" +
-				"• Tap 'Retry' below if needed\n\n" +
-				"_If you already completed payment, please wait for confirmation._"
+			timeoutMsg := b.text(lang, "payment.waiting")
 			buttons := []core.Button{
 				{
 					ID:    "retry_pay_" + oID,
-					Title: "Retry Payment",
+					Title: b.I18n.T(lang, "button.retry_payment"),
 				},
 			}
-			b.WhatsApp.SendMenuButtons(checkCtx, waPhone, timeoutMsg, buttons)
+			if err := b.WhatsApp.SendMenuButtons(checkCtx, waPhone, timeoutMsg, buttons); err != nil {
+				reporting.CaptureError(checkCtx, "payment.retry_safety_net", fmt.Errorf("failed to send retry prompt: %w", err))
+			}
 		}
 	}(orderID, whatsappPhone)
 
 	return nil
 }
 
+// cartSubtotal sums the cart at menu prices (modifier price deltas included)
+func cartSubtotal(cart []core.CartItem) float64 {
+	subtotal := 0.0
+	for _, item := range cart {
+		subtotal += item.Price * float64(item.Quantity)
+	}
+	return subtotal
+}
+
 // processPayment creates the order and initiates STK push
 // SILENT CHECKOUT: No WhatsApp messages are sent during STK push to prevent iPhone UI freeze
 func (b *BotService) processPayment(ctx context.Context, whatsappPhone string, session *core.Session, paymentPhone string) error {
-	// Calculate total
-	total := 0.0
-	for _, item := range session.Cart {
-		total += item.Price * float64(item.Quantity)
-	}
-
-	// Upsert user (Get or Create) using WhatsApp phone
-	user, err := b.UserRepo.GetOrCreateByPhone(ctx, whatsappPhone)
-	if err != nil {
-		return fmt.Errorf("failed to get or create user: %w", err)
-	}
-
-	// Generate order ID
-	orderID := uuid.New().String()
-
-	// Generate 4-digit pickup code
-	pickupCode := generatePickupCode()
-
-	// Create order items from cart
-	orderItems := make([]core.OrderItem, len(session.Cart))
-	for i, cartItem := range session.Cart {
-		orderItems[i] = core.OrderItem{
-			ID:          uuid.New().String(),
-			OrderID:     orderID,
-			ProductID:   cartItem.ProductID,
-			Quantity:    cartItem.Quantity,
-			PriceAtTime: cartItem.Price,
-		}
+	// Ordering may have been paused since the payment prompt was shown
+	if paused, err := b.rejectIfOrderingPaused(ctx, whatsappPhone, session); paused || err != nil {
+		return err
 	}
 
-	// Create order with PENDING status
 	// CRITICAL: Use paymentPhone for CustomerPhone (for webhook matching)
-	order := &core.Order{
-		ID:            orderID,
-		UserID:        user.ID,
-		CustomerPhone: paymentPhone, // Use payment phone for webhook matching
-		TableNumber:   "",           // TODO: Ask for table number or get from session
-		TotalAmount:   total,
-		Status:        core.OrderStatusPending,
-		PaymentMethod: string(core.PaymentMethodMpesa),
-		PickupCode:    pickupCode,
-		Items:         orderItems,
-		CreatedAt:     time.Now(),
+	order, err := b.newPendingOrder(ctx, whatsappPhone, session, paymentPhone)
+	if errors.Is(err, errTabChanged) {
+		return b.handleTabChanged(ctx, whatsappPhone, session)
+	}
+	if err != nil {
+		return err
 	}
+	orderID, total := order.ID, order.TotalAmount
 
 	if err := b.OrderRepo.CreateOrder(ctx, order); err != nil {
 		return fmt.Errorf("failed to create order: %w", err)
@@ -822,90 +1136,129 @@ func (b *BotService) processPayment(ctx context.Context, whatsappPhone string, s
 	err = b.Payment.InitiateSTKPush(ctx, orderID, paymentPhone, total)
 	if err != nil {
 		// If queueing fails (system busy), update order status to FAILED and clear pending ID
-		b.OrderRepo.UpdateStatus(ctx, orderID, core.OrderStatusFailed)
+		b.OrderRepo.UpdateStatusWithNote(ctx, orderID, core.OrderStatusFailed, core.OrderActorSystem, "STK push could not be queued")
 		session.PendingOrderID = ""
-		b.Session.Set(ctx, whatsappPhone, session, 7200)
+		b.Session.Set(ctx, whatsappPhone, session, b.sessionTTL(ctx))
 		// Send error message - safe because no STK push was sent to freeze the phone
-		b.WhatsApp.SendText(ctx, whatsappPhone, "⚠️ Payment system busy. Please try again in a moment.")
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "payment.system_busy"))
 		return fmt.Errorf("failed to initiate STK push: %w", err)
 	}
 
 	// Clear cart and reset state, but KEEP PendingOrderID until payment is processed
 	session.Cart = []core.CartItem{}
+	session.TipAmount = 0
+	session.TableNumber = ""
+	session.PaymentPhone = ""
+	session.OrderNotes = ""
+	session.ScheduledFor = nil
+	clearDelivery(session)
+	clearTabCheckout(session)
 	session.State = "START"
-	b.Session.Set(ctx, whatsappPhone, session, 7200)
+	b.Session.Set(ctx, whatsappPhone, session, b.sessionTTL(ctx))
 
-	// SAFETY NET: Launch goroutine to check order status after 45 seconds
+	// SAFETY NET: Launch goroutine to check order status after the safety-net delay (45 seconds by default)
 	// If order is still PENDING, send a Retry button to the user
 	// Note: M-Pesa STK prompts can take 20-40 seconds to arrive, so we wait longer
+	lang := sessionLanguage(session)
+	checkCtx := core.DetachRequestID(ctx)
+	delay := b.safetyNetDelay(ctx)
 	go func(oID string, waPhone string, payPhone string) {
-		time.Sleep(45 * time.Second)
+		defer reporting.Recover(checkCtx, "payment.safety_net")
+		time.Sleep(delay)
 
 		// Check if order is still PENDING
-		checkCtx := context.Background()
 		order, err := b.OrderRepo.GetByID(checkCtx, oID)
 		if err != nil {
-			return // Order not found or error, skip
+			reporting.CaptureError(checkCtx, "payment.safety_net", err)
+			return
 		}
 
 		if order.Status == core.OrderStatusPending {
-			// Order still pending after 45 seconds - send retry button
-			timeoutMsg := "⏳ *Waiting for M-Pesa*\n\n" +
-				"The payment prompt can take up to 60 seconds to appear.\n\n" +
-				"*If it hasn't appeared yet:*\n" +
-				"• Check your phone for the M-Pesa prompt\n" +
-				"• Make sure you have network signal\n" +
-				"• Tap 'Retry' below if needed\n\n" +
-				"_If you already completed payment, please wait for confirmation._"
+			// Order still pending after the delay - send retry button
+			timeoutMsg := b.text(lang, "payment.waiting")
 			buttons := []core.Button{
 				{
 					ID:    "retry_pay_" + oID,
-					Title: "Retry Payment",
+					Title: b.I18n.T(lang, "button.retry_payment"),
 				},
 			}
-			b.WhatsApp.SendMenuButtons(checkCtx, waPhone, timeoutMsg, buttons)
+			if err := b.WhatsApp.SendMenuButtons(checkCtx, waPhone, timeoutMsg, buttons); err != nil {
+				reporting.CaptureError(checkCtx, "payment.safety_net", fmt.Errorf("failed to send retry prompt: %w", err))
+			}
 		}
 	}(orderID, whatsappPhone, paymentPhone)
 
 	return nil
 }
 
-// normalizePhone normalizes a Kenyan phone number to +254xxxxxxxxx format
-// Supports: 07..., 01..., 254..., +254..., 7..., 1...
-func normalizePhone(phone string) (string, error) {
-	// Remove spaces and dashes
-	phone = strings.ReplaceAll(phone, " ", "")
-	phone = strings.ReplaceAll(phone, "-", "")
-	phone = strings.TrimSpace(phone)
+// newPendingOrder builds a PENDING order from the session cart for the customer on whatsappPhone.
+// The order is not saved; customerPhone is where payment confirmation and the pickup code go.
+func (b *BotService) newPendingOrder(ctx context.Context, whatsappPhone string, session *core.Session, customerPhone string) (*core.Order, error) {
+	// Calculate total, including VAT when it's added on top of menu prices and the tip
+	tax, total := b.checkoutTotals(session)
 
-	// Remove leading +
-	phone = strings.TrimPrefix(phone, "+")
+	// Upsert user (Get or Create) using WhatsApp phone
+	user, err := b.UserRepo.GetOrCreateByPhone(ctx, whatsappPhone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get or create user: %w", err)
+	}
 
-	// Handle different formats
-	if strings.HasPrefix(phone, "254") {
-		// Already in 254xxxxxxxxx format
-		if len(phone) == 12 {
-			return "+" + phone, nil
-		}
-		return "", fmt.Errorf("invalid phone number format")
-	} else if strings.HasPrefix(phone, "07") || strings.HasPrefix(phone, "01") {
-		// 07xxxxxxxx or 01xxxxxxxx -> +2547xxxxxxxx or +2541xxxxxxxx
-		if len(phone) == 10 {
-			return "+254" + phone[1:], nil
+	// Generate order ID
+	orderID := b.IDs.NewID()
+
+	// Tab items are claimed before the order is saved; if saving fails they count as unpaid again
+	if err := b.claimTabItems(ctx, session, orderID); err != nil {
+		return nil, err
+	}
+
+	// Generate a pickup code unique among open orders
+	pickupCode, err := b.PickupCodes.Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create order items from cart; combos keep their own name and record their components
+	orderItems := make([]core.OrderItem, len(session.Cart))
+	for i, cartItem := range session.Cart {
+		components, err := b.orderItemComponents(ctx, cartItem.ProductID)
+		if err != nil {
+			return nil, err
 		}
-		return "", fmt.Errorf("invalid phone number format")
-	} else if strings.HasPrefix(phone, "7") || strings.HasPrefix(phone, "1") {
-		// 7xxxxxxxx or 1xxxxxxxx -> +2547xxxxxxxx or +2541xxxxxxxx
-		if len(phone) == 9 {
-			return "+254" + phone, nil
+
+		orderItems[i] = core.OrderItem{
+			ID:          b.IDs.NewID(),
+			OrderID:     orderID,
+			ProductID:   cartItem.ProductID,
+			Quantity:    cartItem.Quantity,
+			PriceAtTime: cartItem.Price,
+			TaxAmount:   b.Tax.LineTax(cartItem.Price * float64(cartItem.Quantity)),
+			Modifiers:   cartItem.Modifiers,
+			Components:  components,
 		}
-		return "", fmt.Errorf("invalid phone number format")
 	}
 
-	return "", fmt.Errorf("unsupported phone number format")
-}
+	// Create order with PENDING status
+	order := &core.Order{
+		ID:               orderID,
+		UserID:           user.ID,
+		CustomerPhone:    customerPhone,
+		TableNumber:      session.TableNumber,
+		Notes:            session.OrderNotes,
+		ScheduledFor:     session.ScheduledFor,
+		DeliveryAddress:  session.DeliveryAddress,
+		DeliveryLocation: session.DeliveryLocation,
+		DeliveryFee:      session.DeliveryFee,
+		TotalAmount:      total,
+		TaxAmount:        tax,
+		TaxRate:          b.Tax.Rate,
+		TipAmount:        session.TipAmount,
+		AgeConfirmedAt:   user.AgeConfirmedAt,
+		Status:           core.OrderStatusPending,
+		PaymentMethod:    string(core.PaymentMethodMpesa),
+		PickupCode:       pickupCode,
+		Items:            orderItems,
+		CreatedAt:        b.Clock.Now(),
+	}
 
-// isValidKenyanMobile validates that a normalized phone starts with +2547 or +2541
-func isValidKenyanMobile(normalizedPhone string) bool {
-	return strings.HasPrefix(normalizedPhone, "+2547") || strings.HasPrefix(normalizedPhone, "+2541")
+	return order, nil
 }
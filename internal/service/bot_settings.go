@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// defaultSafetyNetDelay is how long after an STK push a customer with a still-pending order gets
+// a Retry button; M-Pesa prompts can take 20-40 seconds to arrive
+const defaultSafetyNetDelay = 45 * time.Second
+
+// sessionTTL is how many seconds a saved session lives: the session.ttl_seconds setting when
+// runtime settings are wired, otherwise SessionTTL
+func (b *BotService) sessionTTL(ctx context.Context) int {
+	if b.Settings == nil {
+		return b.SessionTTL
+	}
+	return b.Settings.Int(ctx, SettingSessionTTL)
+}
+
+// safetyNetDelay is how long the payment safety nets wait before checking an order
+func (b *BotService) safetyNetDelay(ctx context.Context) time.Duration {
+	if b.Settings == nil {
+		return defaultSafetyNetDelay
+	}
+	return b.Settings.Duration(ctx, SettingSafetyNetDelay)
+}
+
+// restockAlertsEnabled reports whether "Notify me" on sold-out products is wired and switched on
+func (b *BotService) restockAlertsEnabled(ctx context.Context) bool {
+	if b.RestockAlerts == nil {
+		return false
+	}
+	return b.Settings == nil || b.Settings.Bool(ctx, SettingRestockAlerts)
+}
+
+// suggestionsEnabled reports whether "people also add" suggestions are wired and switched on
+func (b *BotService) suggestionsEnabled(ctx context.Context) bool {
+	if b.Suggestions == nil {
+		return false
+	}
+	return b.Settings == nil || b.Settings.Bool(ctx, SettingSuggestionsEnabled)
+}
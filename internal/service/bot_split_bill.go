@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
+)
+
+// Split bill limits: WhatsApp groups at a table rarely exceed ten, and each payer gets their own prompt
+const (
+	splitMinPeople = 2
+	splitMaxPeople = 10
+)
+
+// splitSelfReplies use the customer's own WhatsApp number as one of the split payers
+var splitSelfReplies = map[string]bool{"me": true, "mimi": true}
+
+// handleSplitBill starts a split bill checkout by asking how many people are paying
+func (b *BotService) handleSplitBill(ctx context.Context, phone string, session *core.Session) error {
+	if len(session.Cart) == 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.empty"))
+	}
+
+	_, total := b.checkoutTotals(session)
+	if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "split.ask_count", total, splitMaxPeople)); err != nil {
+		return fmt.Errorf("failed to send split count prompt: %w", err)
+	}
+
+	session.State = StateSplitCount
+	session.SplitCount = 0
+	session.SplitPhones = nil
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleSplitCount handles the SPLIT_COUNT state - the number of people sharing the bill
+func (b *BotService) handleSplitCount(ctx context.Context, phone string, session *core.Session, message string) error {
+	count, err := strconv.Atoi(strings.TrimSpace(message))
+	_, total := b.checkoutTotals(session)
+	// Every share must be at least KES 1, M-Pesa's smallest charge
+	if err != nil || count < splitMinPeople || count > splitMaxPeople || total < float64(count) {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "split.invalid_count", splitMinPeople, splitMaxPeople))
+	}
+
+	session.SplitCount = count
+	session.SplitPhones = []string{}
+	session.State = StateSplitPhones
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "split.ask_phone", 1, count))
+}
+
+// handleSplitPhoneInput handles the SPLIT_PHONES state - one payer's M-Pesa number per message
+func (b *BotService) handleSplitPhoneInput(ctx context.Context, phone string, session *core.Session, message string) error {
+	input := message
+	if splitSelfReplies[strings.ToLower(strings.TrimSpace(message))] {
+		input = phone
+	}
+
+	normalizedPhone, err := msisdn.Normalize(input)
+	if err != nil || !msisdn.IsKenyanMobile(normalizedPhone) {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "payment.invalid_phone"))
+	}
+
+	for _, existing := range session.SplitPhones {
+		if existing == normalizedPhone {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "split.duplicate_phone"))
+		}
+	}
+	session.SplitPhones = append(session.SplitPhones, normalizedPhone)
+
+	if len(session.SplitPhones) < session.SplitCount {
+		if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "split.ask_phone", len(session.SplitPhones)+1, session.SplitCount))
+	}
+
+	return b.processSplitPayment(ctx, phone, session)
+}
+
+// processSplitPayment creates the order with one share per payer and sends each payer an STK push.
+// The order stays PENDING, then PARTIALLY_PAID, until the shares cover the total.
+// SILENT CHECKOUT: like processPayment, nothing is sent to WhatsApp while prompts are going out.
+func (b *BotService) processSplitPayment(ctx context.Context, whatsappPhone string, session *core.Session) error {
+	if paused, err := b.rejectIfOrderingPaused(ctx, whatsappPhone, session); paused || err != nil {
+		return err
+	}
+
+	// The pickup code and payment progress go to the customer who ordered, not to each payer
+	order, err := b.newPendingOrder(ctx, whatsappPhone, session, whatsappPhone)
+	if errors.Is(err, errTabChanged) {
+		return b.handleTabChanged(ctx, whatsappPhone, session)
+	}
+	if err != nil {
+		return err
+	}
+
+	amounts := splitAmounts(order.TotalAmount, len(session.SplitPhones))
+	order.PaymentShares = make([]*core.PaymentShare, len(amounts))
+	for i, amount := range amounts {
+		order.PaymentShares[i] = &core.PaymentShare{
+			ID:        b.IDs.NewID(),
+			OrderID:   order.ID,
+			Phone:     session.SplitPhones[i],
+			Amount:    amount,
+			Status:    core.PaymentSharePending,
+			CreatedAt: order.CreatedAt,
+		}
+	}
+
+	if err := b.OrderRepo.CreateOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	// CRITICAL: Store pending order ID in session for duplicate checkout prevention
+	session.PendingOrderID = order.ID
+
+	failed := b.sendSplitSharePushes(ctx, order.ID, order.PaymentShares)
+	if failed == len(order.PaymentShares) {
+		b.OrderRepo.UpdateStatusWithNote(ctx, order.ID, core.OrderStatusFailed, core.OrderActorSystem, "STK push could not be queued")
+		session.PendingOrderID = ""
+		b.Session.Set(ctx, whatsappPhone, session, b.sessionTTL(ctx))
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "payment.system_busy"))
+		return fmt.Errorf("failed to initiate STK push for any split share")
+	}
+
+	// Clear cart and split state, but KEEP PendingOrderID until the bill is paid
+	session.Cart = []core.CartItem{}
+	session.TipAmount = 0
+	session.TableNumber = ""
+	session.PaymentPhone = ""
+	session.OrderNotes = ""
+	session.ScheduledFor = nil
+	clearDelivery(session)
+	clearTabCheckout(session)
+	session.SplitCount = 0
+	session.SplitPhones = nil
+	session.State = "START"
+	b.Session.Set(ctx, whatsappPhone, session, b.sessionTTL(ctx))
+
+	b.watchSplitPayment(ctx, order.ID, whatsappPhone, sessionLanguage(session))
+	return nil
+}
+
+// retrySplitShares resends the STK push for every share that isn't paid yet
+func (b *BotService) retrySplitShares(ctx context.Context, whatsappPhone string, session *core.Session, order *core.Order, shares []*core.PaymentShare) error {
+	unpaid := make([]*core.PaymentShare, 0, len(shares))
+	for _, share := range shares {
+		if share.Status == core.PaymentSharePaid {
+			continue
+		}
+		if share.Status == core.PaymentShareFailed {
+			if err := b.OrderRepo.ResetPaymentShare(ctx, share.ID); err != nil {
+				continue
+			}
+		}
+		unpaid = append(unpaid, share)
+	}
+
+	if len(unpaid) == 0 {
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "order.already_processed"))
+		return nil
+	}
+
+	if failed := b.sendSplitSharePushes(ctx, order.ID, unpaid); failed == len(unpaid) {
+		b.WhatsApp.SendText(ctx, whatsappPhone, b.t(session, "payment.system_busy"))
+		return nil
+	}
+
+	b.watchSplitPayment(ctx, order.ID, whatsappPhone, sessionLanguage(session))
+	return nil
+}
+
+// sendSplitSharePushes queues an STK push per share and returns how many could not be queued.
+// Those shares are marked FAILED so a retry picks them up.
+func (b *BotService) sendSplitSharePushes(ctx context.Context, orderID string, shares []*core.PaymentShare) int {
+	failed := 0
+	for _, share := range shares {
+		if err := b.Payment.InitiateSTKPush(ctx, orderID, share.Phone, share.Amount); err != nil {
+			failed++
+			b.OrderRepo.FailPaymentShare(ctx, orderID, share.Phone, share.Amount)
+		}
+	}
+	return failed
+}
+
+// watchSplitPayment is the split bill safety net: after the safety-net delay, if the bill still isn't
+// fully paid, the customer gets a summary of who has paid and a Retry button for everyone else
+func (b *BotService) watchSplitPayment(ctx context.Context, orderID string, whatsappPhone string, lang string) {
+	checkCtx := core.DetachRequestID(ctx)
+	delay := b.safetyNetDelay(ctx)
+	go func() {
+		defer reporting.Recover(checkCtx, "payment.split_safety_net")
+		time.Sleep(delay)
+
+		order, err := b.OrderRepo.GetByID(checkCtx, orderID)
+		if err != nil {
+			reporting.CaptureError(checkCtx, "payment.split_safety_net", err)
+			return
+		}
+		if order.Status != core.OrderStatusPending && order.Status != core.OrderStatusPartiallyPaid {
+			return
+		}
+
+		shares, err := b.OrderRepo.GetPaymentShares(checkCtx, orderID)
+		if err != nil {
+			reporting.CaptureError(checkCtx, "payment.split_safety_net", err)
+			return
+		}
+
+		message := b.I18n.T(lang, "split.waiting", order.AmountPaid, order.TotalAmount, splitSharesSummary(shares))
+		buttons := []core.Button{
+			{
+				ID:    "retry_pay_" + orderID,
+				Title: b.I18n.T(lang, "button.retry_payment"),
+			},
+		}
+		if err := b.WhatsApp.SendMenuButtons(checkCtx, whatsappPhone, message, buttons); err != nil {
+			reporting.CaptureError(checkCtx, "payment.split_safety_net", fmt.Errorf("failed to send split payment summary: %w", err))
+		}
+	}()
+}
+
+// splitAmounts divides total into n whole-shilling shares, since M-Pesa only charges whole shillings.
+// The first share takes the remainder, rounded up, so the shares always cover the total.
+func splitAmounts(total float64, n int) []float64 {
+	base := math.Floor(total / float64(n))
+	amounts := make([]float64, n)
+	for i := range amounts {
+		amounts[i] = base
+	}
+	amounts[0] = math.Ceil(total - base*float64(n-1) - 0.005)
+	return amounts
+}
+
+// splitSharesSummary lists each payer's share with a paid, waiting or failed marker
+func splitSharesSummary(shares []*core.PaymentShare) string {
+	lines := make([]string, len(shares))
+	for i, share := range shares {
+		marker := "⏳"
+		switch share.Status {
+		case core.PaymentSharePaid:
+			marker = "✅"
+		case core.PaymentShareFailed:
+			marker = "❌"
+		}
+		lines[i] = fmt.Sprintf("%s %s — KES %.0f", marker, share.Phone, share.Amount)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// suggestionTriggerCategory is the category whose products get a "people also add" suggestion
+	suggestionTriggerCategory = "Cocktails"
+	// suggestionCategory is where the suggested products come from
+	suggestionCategory = "Chasers"
+	// maxSuggestions is how many products one suggestion names
+	maxSuggestions = 2
+	// suggestionAddPrefix starts the reply ID of the suggestion's add button, followed by comma-separated product IDs
+	suggestionAddPrefix = "suggest_add_"
+)
+
+// sendSuggestion follows a cocktail added to the cart with the chasers most often bought with it
+// ("People also add Ice Cubes + Coca-Cola") and a button adding one of each. Suggestions are extras:
+// failures are logged and never fail the add.
+func (b *BotService) sendSuggestion(ctx context.Context, phone string, session *core.Session, product *core.Product) {
+	if product.Category != suggestionTriggerCategory || !b.suggestionsEnabled(ctx) {
+		return
+	}
+
+	candidates, err := b.Suggestions.Suggest(ctx, product.ID, suggestionCategory, maxListRows)
+	if err != nil {
+		log.Printf("Failed to load suggestions for %s: %v", product.Name, err)
+		return
+	}
+
+	names := make([]string, 0, maxSuggestions)
+	ids := make([]string, 0, maxSuggestions)
+	for _, candidate := range candidates {
+		if len(ids) == maxSuggestions {
+			break
+		}
+		if cartHasProduct(session.Cart, candidate.ProductID) {
+			continue
+		}
+		if suggested, ok := b.suggestableProduct(ctx, candidate.ProductID); ok {
+			names = append(names, suggested.Name)
+			ids = append(ids, suggested.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	buttons := []core.Button{{ID: suggestionAddPrefix + strings.Join(ids, ","), Title: b.t(session, "button.suggest_add")}}
+	if err := b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "suggest.prompt", strings.Join(names, " + ")), buttons); err != nil {
+		log.Printf("Failed to send suggestion to %s: %v", phone, err)
+	}
+}
+
+// handleSuggestionAdd adds one of each suggested product still available and within purchase limits,
+// then shows the cart
+func (b *BotService) handleSuggestionAdd(ctx context.Context, phone string, session *core.Session, productIDs string) error {
+	added := 0
+	for _, id := range strings.Split(productIDs, ",") {
+		product, ok := b.suggestableProduct(ctx, strings.TrimSpace(id))
+		if !ok {
+			continue
+		}
+		cart := append(append([]core.CartItem{}, session.Cart...), core.CartItem{
+			ProductID: product.ID,
+			Quantity:  1,
+			Name:      product.Name,
+			Price:     product.Price,
+		})
+		// Suggestions that would go over a purchase limit are left out
+		if b.Limits != nil {
+			if breach, err := b.Limits.Check(ctx, cart); err != nil || breach != nil {
+				continue
+			}
+		}
+		session.Cart = cart
+		added++
+	}
+	if added == 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "suggest.unavailable"))
+	}
+	return b.sendCartSummary(ctx, phone, session, b.t(session, "cart.added_header"))
+}
+
+// suggestableProduct loads a product that can go straight in the cart: on the menu, in stock and
+// without serving options to ask
+func (b *BotService) suggestableProduct(ctx context.Context, productID string) (*core.Product, bool) {
+	product, err := b.Repo.GetByID(ctx, productID)
+	if err != nil || !product.IsActive || product.ArchivedAt != nil {
+		return nil, false
+	}
+	if available, err := b.availableStock(ctx, product); err != nil || available <= 0 {
+		return nil, false
+	}
+	if groups, err := b.productOptionGroups(ctx, product.ID); err != nil || len(groups) > 0 {
+		return nil, false
+	}
+	return product, true
+}
+
+// cartHasProduct reports whether the cart already holds productID
+func cartHasProduct(cart []core.CartItem, productID string) bool {
+	for _, item := range cart {
+		if item.ProductID == productID {
+			return true
+		}
+	}
+	return false
+}
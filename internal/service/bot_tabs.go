@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// Group tab buttons
+const (
+	tabStartID   = "tab_start"
+	tabJoinID    = "tab_join"
+	tabAddID     = "tab_add"
+	tabPayAllID  = "tab_pay_all"
+	tabPayMineID = "tab_pay_mine"
+	tabLeaveID   = "tab_leave"
+)
+
+const (
+	tabJoinCodeLength      = 6
+	tabJoinCodeMaxAttempts = 10
+)
+
+// errTabChanged means another member checked out some of the tab items while this customer was paying
+var errTabChanged = errors.New("tab items were checked out by another member")
+
+// handleTabCommand handles the "tab" and "join <code>" commands and the tab buttons from any state.
+// It reports false when the message isn't one of them.
+func (b *BotService) handleTabCommand(ctx context.Context, phone string, session *core.Session, message string) (bool, error) {
+	normalized := strings.ToLower(strings.TrimSpace(message))
+
+	switch normalized {
+	case "tab", "my tab":
+		return true, b.showTab(ctx, phone, session)
+	case tabStartID, "start tab":
+		return true, b.handleStartTab(ctx, phone, session)
+	case tabJoinID, "join tab":
+		if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.join_prompt")); err != nil {
+			return true, fmt.Errorf("failed to send tab join prompt: %w", err)
+		}
+		session.State = StateTabJoinCode
+		return true, b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	case tabAddID:
+		return true, b.handleAddToTab(ctx, phone, session)
+	case tabPayAllID:
+		return true, b.handleTabCheckout(ctx, phone, session, false)
+	case tabPayMineID:
+		return true, b.handleTabCheckout(ctx, phone, session, true)
+	case tabLeaveID, "leave tab":
+		return true, b.handleLeaveTab(ctx, phone, session)
+	}
+
+	if code, ok := strings.CutPrefix(normalized, "join "); ok {
+		return true, b.joinTab(ctx, phone, session, code)
+	}
+	return false, nil
+}
+
+// showTab sends the customer's open tab, or offers to start or join one
+func (b *BotService) showTab(ctx context.Context, phone string, session *core.Session) error {
+	tab, err := b.Tabs.GetOpenByMember(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("failed to get tab: %w", err)
+	}
+	if tab == nil {
+		return b.sendNoTab(ctx, phone, session)
+	}
+	return b.sendTabSummary(ctx, phone, session, tab)
+}
+
+// sendNoTab offers to start a tab or join a friend's
+func (b *BotService) sendNoTab(ctx context.Context, phone string, session *core.Session) error {
+	buttons := []core.Button{
+		{
+			ID:    tabStartID,
+			Title: b.t(session, "button.tab_start"),
+		},
+		{
+			ID:    tabJoinID,
+			Title: b.t(session, "button.tab_join"),
+		},
+	}
+	return b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "tab.none"), buttons)
+}
+
+// sendTabSummary lists who ordered what on the tab, what's still to pay and the payment buttons
+func (b *BotService) sendTabSummary(ctx context.Context, phone string, session *core.Session, tab *core.Tab) error {
+	text := b.t(session, "tab.header", tab.TableNumber, tab.JoinCode, len(tab.Members))
+	for _, item := range tab.Items {
+		mark := ""
+		switch {
+		case item.Unpaid():
+		case item.OrderStatus == core.OrderStatusPending || item.OrderStatus == core.OrderStatusPartiallyPaid || item.OrderStatus == core.OrderStatusAwaitingCash:
+			mark = " ⏳"
+		default:
+			mark = " ✅"
+		}
+		text += fmt.Sprintf("%s x%d = KES %.0f (%s)%s\n",
+			itemDisplayName(item.Name, item.Modifiers), item.Quantity, item.Price*float64(item.Quantity), b.tabMemberLabel(session, phone, item.Phone), mark)
+	}
+	if len(tab.Items) == 0 {
+		text += b.t(session, "tab.empty")
+	}
+
+	_, unpaid := b.Tax.OrderTotals(tabItemsSubtotal(tab.UnpaidItems("")))
+	_, mine := b.Tax.OrderTotals(tabItemsSubtotal(tab.UnpaidItems(phone)))
+	text += b.t(session, "tab.balance", unpaid, mine)
+
+	var buttons []core.Button
+	if unpaid > 0 {
+		buttons = append(buttons, core.Button{ID: tabPayAllID, Title: b.t(session, "button.tab_pay_all")})
+	}
+	if mine > 0 {
+		buttons = append(buttons, core.Button{ID: tabPayMineID, Title: b.t(session, "button.tab_pay_mine")})
+	}
+	buttons = append(buttons, core.Button{ID: tabLeaveID, Title: b.t(session, "button.tab_leave")})
+
+	return b.WhatsApp.SendMenuButtons(ctx, phone, text, buttons)
+}
+
+// handleStartTab asks for the table number of a new tab
+func (b *BotService) handleStartTab(ctx context.Context, phone string, session *core.Session) error {
+	tab, err := b.Tabs.GetOpenByMember(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("failed to get tab: %w", err)
+	}
+	if tab != nil {
+		return b.sendTabSummary(ctx, phone, session, tab)
+	}
+
+	if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.table_prompt")); err != nil {
+		return fmt.Errorf("failed to send tab table prompt: %w", err)
+	}
+	session.State = StateTabTable
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleTabTable handles the TAB_TABLE state - opens the tab and shares its join code
+func (b *BotService) handleTabTable(ctx context.Context, phone string, session *core.Session, message string) error {
+	tableNumber := strings.TrimSpace(message)
+	if tableNumber == "" || len(tableNumber) > maxTableNumberLength {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.invalid_table", maxTableNumberLength))
+	}
+
+	code, err := b.newTabJoinCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	tab := &core.Tab{
+		TableNumber: tableNumber,
+		JoinCode:    code,
+		OpenedBy:    phone,
+	}
+	if err := b.Tabs.Create(ctx, tab); err != nil {
+		return fmt.Errorf("failed to open tab: %w", err)
+	}
+
+	session.TableNumber = tableNumber
+	if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.started", tableNumber, code, code)); err != nil {
+		return fmt.Errorf("failed to send tab code: %w", err)
+	}
+	return b.handleMenu(ctx, phone, session, "Order Drinks")
+}
+
+// handleTabJoinCode handles the TAB_JOIN_CODE state - the code a friend shared
+func (b *BotService) handleTabJoinCode(ctx context.Context, phone string, session *core.Session, message string) error {
+	return b.joinTab(ctx, phone, session, message)
+}
+
+// joinTab adds the customer to the open tab using code
+func (b *BotService) joinTab(ctx context.Context, phone string, session *core.Session, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	current, err := b.Tabs.GetOpenByMember(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("failed to get tab: %w", err)
+	}
+	if current != nil {
+		if current.JoinCode == code {
+			return b.sendTabSummary(ctx, phone, session, current)
+		}
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.already_on_tab", current.TableNumber))
+	}
+
+	tab, err := b.Tabs.GetOpenByJoinCode(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to find tab: %w", err)
+	}
+	if tab == nil {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.code_not_found", code))
+	}
+
+	if err := b.Tabs.AddMember(ctx, tab.ID, phone); err != nil {
+		return fmt.Errorf("failed to join tab: %w", err)
+	}
+
+	session.TableNumber = tab.TableNumber
+	if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.joined", tab.TableNumber, len(tab.Members)+1)); err != nil {
+		return fmt.Errorf("failed to send tab joined message: %w", err)
+	}
+	return b.handleMenu(ctx, phone, session, "Order Drinks")
+}
+
+// handleAddToTab moves the customer's cart onto their tab
+func (b *BotService) handleAddToTab(ctx context.Context, phone string, session *core.Session) error {
+	tab, err := b.Tabs.GetOpenByMember(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("failed to get tab: %w", err)
+	}
+	if tab == nil {
+		return b.sendNoTab(ctx, phone, session)
+	}
+
+	items := personalCartItems(session)
+	if len(items) == 0 {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.empty"))
+	}
+	if err := b.Tabs.AddItems(ctx, tab.ID, phone, items); err != nil {
+		return fmt.Errorf("failed to add to tab: %w", err)
+	}
+
+	// A tab checkout that was under way is dropped; the customer pays from the updated tab
+	clearTabCheckout(session)
+	session.Cart = []core.CartItem{}
+	session.State = StateStart
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.showTab(ctx, phone, session)
+}
+
+// handleTabCheckout fills the cart with the tab's unpaid items (only the customer's own when mine)
+// and continues with the normal checkout, so notes, tips, split bill and pay at the bar all apply
+func (b *BotService) handleTabCheckout(ctx context.Context, phone string, session *core.Session, mine bool) error {
+	tab, err := b.Tabs.GetOpenByMember(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("failed to get tab: %w", err)
+	}
+	if tab == nil {
+		return b.sendNoTab(ctx, phone, session)
+	}
+
+	// Drinks still in the customer's own cart go on the tab first
+	if items := personalCartItems(session); len(items) > 0 {
+		if err := b.Tabs.AddItems(ctx, tab.ID, phone, items); err != nil {
+			return fmt.Errorf("failed to add to tab: %w", err)
+		}
+		if tab, err = b.Tabs.GetByID(ctx, tab.ID); err != nil {
+			return fmt.Errorf("failed to get tab: %w", err)
+		}
+	}
+
+	owner := ""
+	if mine {
+		owner = phone
+	}
+	unpaid := tab.UnpaidItems(owner)
+	if len(unpaid) == 0 {
+		key := "tab.nothing_to_pay"
+		if mine {
+			key = "tab.nothing_to_pay_mine"
+		}
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, key))
+	}
+
+	session.Cart = make([]core.CartItem, len(unpaid))
+	session.TabItemIDs = make([]string, len(unpaid))
+	for i, item := range unpaid {
+		session.Cart[i] = core.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Name:      item.Name,
+			Price:     item.Price,
+			Modifiers: item.Modifiers,
+		}
+		session.TabItemIDs[i] = item.ID
+	}
+	session.TabID = tab.ID
+	session.TableNumber = tab.TableNumber
+	session.State = StateConfirmOrder
+	return b.handleCheckout(ctx, phone, session)
+}
+
+// handleLeaveTab takes the customer off their tab once their own drinks are paid for.
+// The last member out closes the tab.
+func (b *BotService) handleLeaveTab(ctx context.Context, phone string, session *core.Session) error {
+	tab, err := b.Tabs.GetOpenByMember(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("failed to get tab: %w", err)
+	}
+	if tab == nil {
+		return b.sendNoTab(ctx, phone, session)
+	}
+
+	if unpaid := tab.UnpaidItems(phone); len(unpaid) > 0 {
+		_, owed := b.Tax.OrderTotals(tabItemsSubtotal(unpaid))
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.leave_unpaid", owed))
+	}
+
+	remaining, err := b.Tabs.RemoveMember(ctx, tab.ID, phone)
+	if err != nil {
+		return fmt.Errorf("failed to leave tab: %w", err)
+	}
+	if remaining == 0 {
+		if err := b.Tabs.Close(ctx, tab.ID); err != nil {
+			return fmt.Errorf("failed to close tab: %w", err)
+		}
+	}
+
+	session.TableNumber = ""
+	clearTabCheckout(session)
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.left", tab.TableNumber))
+}
+
+// claimTabItems puts the tab items being checked out on orderID before the order is saved.
+// If saving the order fails, the items count as unpaid again.
+func (b *BotService) claimTabItems(ctx context.Context, session *core.Session, orderID string) error {
+	if b.Tabs == nil || session.TabID == "" || len(session.TabItemIDs) == 0 {
+		return nil
+	}
+
+	claimed, err := b.Tabs.ClaimItems(ctx, session.TabID, session.TabItemIDs, orderID)
+	if err != nil {
+		return err
+	}
+	if claimed < len(session.TabItemIDs) {
+		return errTabChanged
+	}
+	return nil
+}
+
+// handleTabChanged drops a tab checkout whose items were partly paid by someone else meanwhile
+func (b *BotService) handleTabChanged(ctx context.Context, phone string, session *core.Session) error {
+	clearTabCheckout(session)
+	session.Cart = []core.CartItem{}
+	session.State = StateStart
+	if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "tab.changed"))
+}
+
+// onOpenTab reports whether the customer is on an open tab; lookup errors count as no tab
+func (b *BotService) onOpenTab(ctx context.Context, phone string) bool {
+	if b.Tabs == nil {
+		return false
+	}
+	tab, err := b.Tabs.GetOpenByMember(ctx, phone)
+	return err == nil && tab != nil
+}
+
+// tabMemberLabel names who added a tab item without showing tablemates' full numbers
+func (b *BotService) tabMemberLabel(session *core.Session, phone string, memberPhone string) string {
+	if memberPhone == phone {
+		return b.t(session, "tab.you")
+	}
+	if len(memberPhone) > 3 {
+		return "…" + memberPhone[len(memberPhone)-3:]
+	}
+	return memberPhone
+}
+
+// newTabJoinCode returns a code no open tab uses
+func (b *BotService) newTabJoinCode(ctx context.Context) (string, error) {
+	max := big.NewInt(int64(len(pickupCodeAlphanumeric)))
+	for attempt := 0; attempt < tabJoinCodeMaxAttempts; attempt++ {
+		code := make([]byte, tabJoinCodeLength)
+		for i := range code {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate tab code: %w", err)
+			}
+			code[i] = pickupCodeAlphanumeric[n.Int64()]
+		}
+
+		existing, err := b.Tabs.GetOpenByJoinCode(ctx, string(code))
+		if err != nil {
+			return "", fmt.Errorf("failed to check tab code: %w", err)
+		}
+		if existing == nil {
+			return string(code), nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a unique tab code after %d attempts", tabJoinCodeMaxAttempts)
+}
+
+// personalCartItems is the part of the cart that isn't already on a tab. A tab checkout puts
+// the tab items first, so anything added after them is the customer's own.
+func personalCartItems(session *core.Session) []core.CartItem {
+	if len(session.TabItemIDs) > len(session.Cart) {
+		return nil
+	}
+	return session.Cart[len(session.TabItemIDs):]
+}
+
+// clearTabCheckout forgets which tab items the next order pays for
+func clearTabCheckout(session *core.Session) {
+	session.TabID = ""
+	session.TabItemIDs = nil
+}
+
+// tabItemsSubtotal is the menu-price total of tab items
+func tabItemsSubtotal(items []core.TabItem) float64 {
+	subtotal := 0.0
+	for _, item := range items {
+		subtotal += item.Price * float64(item.Quantity)
+	}
+	return subtotal
+}
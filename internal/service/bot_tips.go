@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// Tip choices offered at checkout
+const (
+	tipNoneID   = "tip_0"
+	tipCustomID = "tip_custom"
+)
+
+// tipPercents are the percentage tips offered, each as a "tip_<percent>" row
+var tipPercents = []int{5, 10}
+
+// checkoutTotals is the cart's VAT and the amount to charge, including the chosen tip and any delivery fee.
+// Tips and delivery fees carry no VAT.
+func (b *BotService) checkoutTotals(session *core.Session) (float64, float64) {
+	tax, total := b.Tax.OrderTotals(cartSubtotal(session.Cart))
+	return tax, total + session.TipAmount + session.DeliveryFee
+}
+
+// percentTip is pct of the order total, rounded to whole shillings for M-Pesa
+func percentTip(orderTotal float64, pct int) float64 {
+	return math.Round(orderTotal * float64(pct) / 100)
+}
+
+// sendTipPrompt asks whether to add a tip: none, one of tipPercents or a custom amount
+func (b *BotService) sendTipPrompt(ctx context.Context, phone string, session *core.Session) error {
+	_, orderTotal := b.Tax.OrderTotals(cartSubtotal(session.Cart))
+	text := b.t(session, "tip.prompt", orderTotal)
+
+	rows := []core.ListRow{{ID: tipNoneID, Title: b.t(session, "tip.none")}}
+	for _, pct := range tipPercents {
+		rows = append(rows, core.ListRow{
+			ID:          fmt.Sprintf("tip_%d", pct),
+			Title:       fmt.Sprintf("%d%%", pct),
+			Description: fmt.Sprintf("KES %.0f", percentTip(orderTotal, pct)),
+		})
+	}
+	rows = append(rows, core.ListRow{ID: tipCustomID, Title: b.t(session, "tip.custom")})
+
+	var err error
+	if sender, ok := b.WhatsApp.(listRowSender); ok {
+		err = sender.SendListRows(ctx, phone, text, b.t(session, "tip.button"), rows)
+	} else {
+		for i, row := range rows {
+			text += fmt.Sprintf("%d. %s %s\n", i+1, row.Title, row.Description)
+		}
+		err = b.WhatsApp.SendText(ctx, phone, text+b.t(session, "option.reply_hint"))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send tip prompt: %w", err)
+	}
+
+	session.State = StateSelectingTip
+	return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+}
+
+// handleSelectingTip handles the SELECTING_TIP state - a row ID, or its number when typed
+func (b *BotService) handleSelectingTip(ctx context.Context, phone string, session *core.Session, message string) error {
+	choice := strings.ToLower(strings.TrimSpace(message))
+	if n, err := strconv.Atoi(choice); err == nil {
+		switch {
+		case n == 1:
+			choice = tipNoneID
+		case n >= 2 && n-2 < len(tipPercents):
+			choice = fmt.Sprintf("tip_%d", tipPercents[n-2])
+		case n == len(tipPercents)+2:
+			choice = tipCustomID
+		}
+	}
+
+	_, orderTotal := b.Tax.OrderTotals(cartSubtotal(session.Cart))
+	switch choice {
+	case tipNoneID:
+		session.TipAmount = 0
+		return b.sendPaymentPrompt(ctx, phone, session)
+	case tipCustomID:
+		if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "tip.custom_prompt")); err != nil {
+			return fmt.Errorf("failed to send custom tip prompt: %w", err)
+		}
+		session.State = StateTipCustom
+		return b.Session.Set(ctx, phone, session, b.sessionTTL(ctx))
+	}
+
+	for _, pct := range tipPercents {
+		if choice == fmt.Sprintf("tip_%d", pct) {
+			session.TipAmount = percentTip(orderTotal, pct)
+			return b.sendPaymentPrompt(ctx, phone, session)
+		}
+	}
+
+	if err := b.WhatsApp.SendText(ctx, phone, b.t(session, "option.invalid")); err != nil {
+		return fmt.Errorf("failed to send error message: %w", err)
+	}
+	return b.sendTipPrompt(ctx, phone, session)
+}
+
+// handleTipCustom handles the TIP_CUSTOM state - a whole-shilling tip up to the order total
+func (b *BotService) handleTipCustom(ctx context.Context, phone string, session *core.Session, message string) error {
+	_, orderTotal := b.Tax.OrderTotals(cartSubtotal(session.Cart))
+
+	input := strings.ToLower(strings.TrimSpace(message))
+	input = strings.TrimSpace(strings.TrimPrefix(input, "kes"))
+	input = strings.ReplaceAll(input, ",", "")
+
+	// Capped at the order total so a typo can't charge a customer several times over
+	amount, err := strconv.ParseFloat(input, 64)
+	if err != nil || amount < 0 || amount > orderTotal {
+		return b.WhatsApp.SendText(ctx, phone, b.t(session, "tip.invalid_amount", orderTotal))
+	}
+
+	session.TipAmount = math.Round(amount)
+	return b.sendPaymentPrompt(ctx, phone, session)
+}
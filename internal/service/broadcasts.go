@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+const (
+	broadcastPollInterval = 10 * time.Second
+	// broadcastStaleAfter is how long a claimed recipient may stay SENDING before it's counted as failed
+	broadcastStaleAfter = 10 * time.Minute
+	// maxBroadcastMessageLength is WhatsApp's limit for a template body parameter
+	maxBroadcastMessageLength = 1024
+	defaultBroadcastDays      = 30
+)
+
+// BroadcastInput holds a new campaign from the admin API
+type BroadcastInput struct {
+	Name             string
+	Message          string
+	TemplateName     string // Optional approved WhatsApp template; Message fills its {{1}}
+	TemplateLanguage string // Template language code (default "en")
+	Segment          string // all, recent or lapsed (default all)
+	Days             int    // Window for recent and lapsed (default 30)
+}
+
+// BroadcastAudience is how many customers a segment reaches right now
+type BroadcastAudience struct {
+	Segment core.BroadcastSegment `json:"segment"`
+	Days    int                   `json:"days,omitempty"`
+	Count   int                   `json:"count"`
+}
+
+// SetBroadcastRepository wires the campaigns managed by the broadcast endpoints
+func (s *DashboardService) SetBroadcastRepository(broadcastRepo core.BroadcastRepository) {
+	s.broadcastRepo = broadcastRepo
+}
+
+// parseBroadcastSegment validates a segment and its day window
+func parseBroadcastSegment(segment string, days int) (core.BroadcastSegment, int, error) {
+	parsed := core.BroadcastSegment(strings.ToLower(strings.TrimSpace(segment)))
+	switch parsed {
+	case "":
+		return core.BroadcastSegmentAll, 0, nil
+	case core.BroadcastSegmentAll:
+		return parsed, 0, nil
+	case core.BroadcastSegmentRecent, core.BroadcastSegmentLapsed:
+		if days == 0 {
+			days = defaultBroadcastDays
+		}
+		if days < 1 || days > 365 {
+			return "", 0, fmt.Errorf("invalid days: must be between 1 and 365")
+		}
+		return parsed, days, nil
+	default:
+		return "", 0, fmt.Errorf("invalid segment: use all, recent or lapsed")
+	}
+}
+
+// PreviewBroadcastAudience counts the opted-in customers a campaign to segment would reach
+func (s *DashboardService) PreviewBroadcastAudience(ctx context.Context, segment string, days int) (*BroadcastAudience, error) {
+	if s.broadcastRepo == nil {
+		return nil, fmt.Errorf("broadcasts not configured")
+	}
+
+	parsed, days, err := parseBroadcastSegment(segment, days)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.broadcastRepo.CountAudience(ctx, parsed, s.clock.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+	return &BroadcastAudience{Segment: parsed, Days: days, Count: count}, nil
+}
+
+// CreateBroadcast queues a campaign to every opted-in customer in its segment; BroadcastSender sends it
+func (s *DashboardService) CreateBroadcast(ctx context.Context, input BroadcastInput, actor string) (*core.BroadcastCampaign, error) {
+	if s.broadcastRepo == nil {
+		return nil, fmt.Errorf("broadcasts not configured")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	message := strings.TrimSpace(input.Message)
+	if message == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+	if len([]rune(message)) > maxBroadcastMessageLength {
+		return nil, fmt.Errorf("invalid message: at most %d characters", maxBroadcastMessageLength)
+	}
+
+	segment, days, err := parseBroadcastSegment(input.Segment, input.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &core.BroadcastCampaign{
+		ID:           s.ids.NewID(),
+		Name:         name,
+		Message:      message,
+		TemplateName: strings.TrimSpace(input.TemplateName),
+		Segment:      segment,
+		SegmentDays:  days,
+		CreatedBy:    actor,
+		CreatedAt:    s.clock.Now(),
+	}
+	if campaign.TemplateName != "" {
+		campaign.TemplateLanguage = strings.TrimSpace(input.TemplateLanguage)
+		if campaign.TemplateLanguage == "" {
+			campaign.TemplateLanguage = i18n.English
+		}
+	}
+
+	if err := s.broadcastRepo.Create(ctx, campaign, campaign.CreatedAt.AddDate(0, 0, -days)); err != nil {
+		return nil, err
+	}
+	log.Printf("Broadcast %s (%q) queued for %d %s customer(s) by %s", campaign.ID, campaign.Name, campaign.Stats.Total, segment, actor)
+	return campaign, nil
+}
+
+// ListBroadcasts retrieves recent campaigns with their delivery stats
+func (s *DashboardService) ListBroadcasts(ctx context.Context, limit int) ([]*core.BroadcastCampaign, error) {
+	if s.broadcastRepo == nil {
+		return nil, fmt.Errorf("broadcasts not configured")
+	}
+	return s.broadcastRepo.GetAll(ctx, limit)
+}
+
+// GetBroadcast retrieves one campaign with its delivery stats
+func (s *DashboardService) GetBroadcast(ctx context.Context, id string) (*core.BroadcastCampaign, error) {
+	if s.broadcastRepo == nil {
+		return nil, fmt.Errorf("broadcasts not configured")
+	}
+	return s.broadcastRepo.GetByID(ctx, id)
+}
+
+// CancelBroadcast stops a campaign that is still queued or sending
+func (s *DashboardService) CancelBroadcast(ctx context.Context, id string) (*core.BroadcastCampaign, error) {
+	if s.broadcastRepo == nil {
+		return nil, fmt.Errorf("broadcasts not configured")
+	}
+	if err := s.broadcastRepo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.broadcastRepo.GetByID(ctx, id)
+}
+
+// templateSender is implemented by WhatsApp gateways that can send approved message templates
+type templateSender interface {
+	SendTemplate(ctx context.Context, phone string, name string, language string, params []string) error
+}
+
+// BroadcastSender sends queued campaign messages at a fixed rate, re-checking each customer's consent
+// just before their message goes out
+type BroadcastSender struct {
+	broadcasts core.BroadcastRepository
+	users      core.UserRepository
+	whatsapp   core.WhatsAppGateway
+	i18n       *i18n.Bundle
+	clock      core.Clock
+	batchSize  int
+}
+
+// NewBroadcastSender creates the sender job; perMinute caps campaign messages sent per minute across
+// this replica (60 when not set)
+func NewBroadcastSender(broadcasts core.BroadcastRepository, users core.UserRepository, whatsapp core.WhatsAppGateway, perMinute int) *BroadcastSender {
+	if perMinute <= 0 {
+		perMinute = 60
+	}
+	batchSize := perMinute * int(broadcastPollInterval/time.Second) / 60
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &BroadcastSender{
+		broadcasts: broadcasts,
+		users:      users,
+		whatsapp:   whatsapp,
+		i18n:       i18n.Default(),
+		clock:      core.SystemClock{},
+		batchSize:  batchSize,
+	}
+}
+
+// Run sends a batch every 10 seconds until ctx is cancelled. Safe to run on every replica; each
+// recipient is claimed by one of them.
+func (s *BroadcastSender) Run(ctx context.Context) {
+	ticker := time.NewTicker(broadcastPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sendBatch(ctx)
+		}
+	}
+}
+
+func (s *BroadcastSender) sendBatch(ctx context.Context) {
+	recipients, err := s.broadcasts.ClaimPending(ctx, s.batchSize)
+	if err != nil {
+		log.Printf("Error claiming broadcast recipients: %v", err)
+	}
+
+	campaigns := make(map[string]*core.BroadcastCampaign)
+	for _, recipient := range recipients {
+		campaign, ok := campaigns[recipient.CampaignID]
+		if !ok {
+			campaign, err = s.broadcasts.GetByID(ctx, recipient.CampaignID)
+			if err != nil {
+				log.Printf("Error loading broadcast %s: %v", recipient.CampaignID, err)
+				s.mark(ctx, recipient, core.BroadcastRecipientFailed, err.Error())
+				continue
+			}
+			campaigns[recipient.CampaignID] = campaign
+		}
+
+		status, errMsg := s.send(ctx, campaign, recipient)
+		s.mark(ctx, recipient, status, errMsg)
+	}
+
+	if err := s.broadcasts.FinishCampaigns(ctx, s.clock.Now().Add(-broadcastStaleAfter)); err != nil {
+		log.Printf("Error finishing broadcasts: %v", err)
+	}
+}
+
+// send delivers one campaign message and returns the recipient's new status
+func (s *BroadcastSender) send(ctx context.Context, campaign *core.BroadcastCampaign, recipient *core.BroadcastRecipient) (core.BroadcastRecipientStatus, string) {
+	if campaign.Status == core.BroadcastCancelled {
+		return core.BroadcastRecipientCancelled, ""
+	}
+
+	user, err := s.users.GetByID(ctx, recipient.UserID)
+	if err != nil {
+		return core.BroadcastRecipientFailed, err.Error()
+	}
+	if !user.MarketingOptIn {
+		return core.BroadcastRecipientSkipped, ""
+	}
+
+	if campaign.TemplateName != "" {
+		sender, ok := s.whatsapp.(templateSender)
+		if !ok {
+			return core.BroadcastRecipientFailed, "whatsapp gateway can't send templates"
+		}
+		err = sender.SendTemplate(ctx, recipient.Phone, campaign.TemplateName, campaign.TemplateLanguage, []string{campaign.Message})
+	} else {
+		lang := i18n.Resolve(user.Language)
+		err = s.whatsapp.SendText(ctx, recipient.Phone, campaign.Message+s.i18n.T(lang, "broadcast.footer"))
+	}
+	if err != nil {
+		return core.BroadcastRecipientFailed, err.Error()
+	}
+	return core.BroadcastRecipientSent, ""
+}
+
+func (s *BroadcastSender) mark(ctx context.Context, recipient *core.BroadcastRecipient, status core.BroadcastRecipientStatus, errMsg string) {
+	if err := s.broadcasts.MarkRecipient(ctx, recipient.ID, status, errMsg); err != nil {
+		log.Printf("Error recording broadcast %s delivery to %s: %v", recipient.CampaignID, recipient.Phone, err)
+	}
+}
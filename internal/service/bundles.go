@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// BundleComponentInput is one component product and how many of it go into a combo
+type BundleComponentInput struct {
+	ProductID string
+	Quantity  int
+}
+
+// BundleInput holds the fields for a new combo product
+type BundleInput struct {
+	Name        string
+	Description string
+	Price       float64
+	ImageURL    string
+	Components  []BundleComponentInput
+}
+
+// SetBundleRepository wires the combo products used by the bundle endpoints
+func (s *DashboardService) SetBundleRepository(bundleRepo core.BundleRepository) {
+	s.bundleRepo = bundleRepo
+}
+
+// ListBundles retrieves every combo with its components and how many can be made from current stock
+func (s *DashboardService) ListBundles(ctx context.Context) ([]*core.Bundle, error) {
+	if s.bundleRepo == nil {
+		return nil, fmt.Errorf("bundles not configured")
+	}
+	return s.bundleRepo.GetAll(ctx)
+}
+
+// CreateBundle adds a combo product to the "Combos" menu category
+func (s *DashboardService) CreateBundle(ctx context.Context, input BundleInput) (*core.Bundle, error) {
+	if s.bundleRepo == nil {
+		return nil, fmt.Errorf("bundles not configured")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if input.Price <= 0 {
+		return nil, fmt.Errorf("price must be greater than zero")
+	}
+
+	components, err := s.resolveBundleComponents(ctx, "", input.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &core.Bundle{
+		Product: core.Product{
+			ID:          s.ids.NewID(),
+			Name:        name,
+			Description: strings.TrimSpace(input.Description),
+			Price:       input.Price,
+			Category:    core.BundleCategory,
+			ImageURL:    strings.TrimSpace(input.ImageURL),
+			IsActive:    true,
+		},
+		Components: components,
+		Available:  core.BundleAvailability(components),
+	}
+
+	if err := s.bundleRepo.Create(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// SetBundleComponents replaces what goes into a combo
+func (s *DashboardService) SetBundleComponents(ctx context.Context, bundleID string, inputs []BundleComponentInput) (*core.Bundle, error) {
+	if s.bundleRepo == nil {
+		return nil, fmt.Errorf("bundles not configured")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Category != core.BundleCategory {
+		return nil, fmt.Errorf("bundle not found")
+	}
+
+	components, err := s.resolveBundleComponents(ctx, bundleID, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bundleRepo.SetComponents(ctx, bundleID, components); err != nil {
+		return nil, err
+	}
+
+	return &core.Bundle{
+		Product:    *product,
+		Components: components,
+		Available:  core.BundleAvailability(components),
+	}, nil
+}
+
+// resolveBundleComponents validates component inputs and looks up each product's name and stock.
+// Combos can't contain other combos, so availability never has to recurse.
+func (s *DashboardService) resolveBundleComponents(ctx context.Context, bundleID string, inputs []BundleComponentInput) ([]core.BundleComponent, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("at least one component is required")
+	}
+
+	components := make([]core.BundleComponent, 0, len(inputs))
+	seen := make(map[string]struct{}, len(inputs))
+	for _, input := range inputs {
+		productID := strings.TrimSpace(input.ProductID)
+		if productID == "" {
+			return nil, fmt.Errorf("component product_id is required")
+		}
+		if input.Quantity <= 0 {
+			return nil, fmt.Errorf("component quantity must be greater than zero")
+		}
+		if productID == bundleID {
+			return nil, fmt.Errorf("invalid component: a bundle can't contain itself")
+		}
+		if _, dup := seen[productID]; dup {
+			return nil, fmt.Errorf("invalid component: product %s is listed twice", productID)
+		}
+		seen[productID] = struct{}{}
+
+		product, err := s.productRepo.GetByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if product.Category == core.BundleCategory {
+			return nil, fmt.Errorf("invalid component: %s is itself a bundle", product.Name)
+		}
+
+		components = append(components, core.BundleComponent{
+			ProductID:     product.ID,
+			ProductName:   product.Name,
+			Quantity:      input.Quantity,
+			StockQuantity: product.StockQuantity,
+		})
+	}
+
+	return components, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// The bar trades past midnight, so sales reports and dashboard analytics count
+// business days from 07:00 to 06:59:59 EAT rather than calendar days. Managers can
+// move the start hour with the reports.business_day_start_hour setting.
+const (
+	reportTimezoneName             = "Africa/Nairobi"
+	defaultBusinessDayStartHourEAT = 7
+)
+
+func reportLocation() *time.Location {
+	loc, err := time.LoadLocation(reportTimezoneName)
+	if err == nil {
+		return loc
+	}
+
+	// Fallback for minimal container images missing IANA zone files.
+	return time.FixedZone("EAT", 3*60*60)
+}
+
+func resolveBusinessDate(dateString string, nowLocal time.Time, loc *time.Location, startHour int) (time.Time, error) {
+	if strings.TrimSpace(dateString) == "" {
+		return currentBusinessDateInLocation(nowLocal, loc, startHour), nil
+	}
+
+	parsed, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(dateString), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
+	}
+
+	return parsed, nil
+}
+
+func currentBusinessDateInLocation(nowLocal time.Time, loc *time.Location, startHour int) time.Time {
+	reference := nowLocal
+	if reference.Hour() < startHour {
+		reference = reference.AddDate(0, 0, -1)
+	}
+
+	return time.Date(reference.Year(), reference.Month(), reference.Day(), 0, 0, 0, 0, loc)
+}
+
+func businessDayWindow(businessDate time.Time, loc *time.Location, startHour int) (time.Time, time.Time) {
+	start := time.Date(
+		businessDate.Year(),
+		businessDate.Month(),
+		businessDate.Day(),
+		startHour,
+		0,
+		0,
+		0,
+		loc,
+	)
+	return start, start.Add(24 * time.Hour)
+}
+
+// businessDateRange resolves optional from/to business dates (YYYY-MM-DD, inclusive) into a
+// [start, end) window. Without dates it covers the last defaultDays business days up to and
+// including the current one; a single date on either side defaults the other to the same day.
+func businessDateRange(from string, to string, defaultDays int, nowLocal time.Time, loc *time.Location, startHour int) (time.Time, time.Time, error) {
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+
+	if from == "" && to == "" {
+		if defaultDays < 1 {
+			defaultDays = 1
+		}
+		current := currentBusinessDateInLocation(nowLocal, loc, startHour)
+		start, _ := businessDayWindow(current.AddDate(0, 0, -(defaultDays-1)), loc, startHour)
+		_, end := businessDayWindow(current, loc, startHour)
+		return start, end, nil
+	}
+
+	if from == "" {
+		from = to
+	}
+	if to == "" {
+		to = from
+	}
+
+	fromDate, err := resolveBusinessDate(from, nowLocal, loc, startHour)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	toDate, err := resolveBusinessDate(to, nowLocal, loc, startHour)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if toDate.Before(fromDate) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range: to is before from")
+	}
+
+	start, _ := businessDayWindow(fromDate, loc, startHour)
+	_, end := businessDayWindow(toDate, loc, startHour)
+	return start, end, nil
+}
+
+// businessDayOffset is added to a UTC timestamp so that its calendar date is the business date
+// (e.g. -4h in EAT: UTC+3, with days starting at 07:00)
+func businessDayOffset(at time.Time, loc *time.Location, startHour int) time.Duration {
+	_, zoneOffset := at.In(loc).Zone()
+	return time.Duration(zoneOffset)*time.Second - time.Duration(startHour)*time.Hour
+}
@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+)
+
+func date(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+func TestCurrentBusinessDate(t *testing.T) {
+	loc := service.ReportLocation()
+	justBeforeOpening := time.Date(2026, time.January, 3, 6, 59, 59, 0, loc)
+
+	for _, tc := range []struct {
+		name      string
+		advance   time.Duration
+		startHour int
+		want      time.Time
+	}{
+		{name: "just before opening counts as the previous day", startHour: service.DefaultBusinessDayStartHourEAT, want: date(2026, time.January, 2, loc)},
+		{name: "opening hour starts the new day", advance: time.Second, startHour: service.DefaultBusinessDayStartHourEAT, want: date(2026, time.January, 3, loc)},
+		{name: "late evening", advance: 15 * time.Hour, startHour: service.DefaultBusinessDayStartHourEAT, want: date(2026, time.January, 3, loc)},
+		{name: "after midnight", advance: 17*time.Hour + time.Second, startHour: service.DefaultBusinessDayStartHourEAT, want: date(2026, time.January, 3, loc)},
+		{name: "custom start hour", advance: 17*time.Hour + 5*time.Hour + time.Second, startHour: 5, want: date(2026, time.January, 4, loc)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := justBeforeOpening.Add(tc.advance)
+			got := service.CurrentBusinessDateInLocation(now, loc, tc.startHour)
+			if !got.Equal(tc.want) {
+				t.Errorf("business date at %s = %s, want %s", now, got.Format("2006-01-02"), tc.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestBusinessDayWindow(t *testing.T) {
+	loc := service.ReportLocation()
+
+	start, end := service.BusinessDayWindow(date(2026, time.January, 2, loc), loc, service.DefaultBusinessDayStartHourEAT)
+
+	wantStart := time.Date(2026, time.January, 2, 4, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.January, 3, 4, 0, 0, 0, time.UTC)
+	if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+		t.Fatalf("window [%s, %s), want [%s, %s)", start.UTC(), end.UTC(), wantStart, wantEnd)
+	}
+	if end.Sub(start) != 24*time.Hour {
+		t.Errorf("window is %s long, want 24h", end.Sub(start))
+	}
+}
+
+func TestBusinessDayWindowBoundaries(t *testing.T) {
+	loc := service.ReportLocation()
+
+	start, end := service.BusinessDayWindow(date(2026, time.January, 2, loc), loc, service.DefaultBusinessDayStartHourEAT)
+	assertBusinessDayBoundaries(t, start, end, loc)
+}
+
+func TestBusinessDateRangeBoundaries(t *testing.T) {
+	loc := service.ReportLocation()
+
+	start, end, err := service.BusinessDateRange("2026-01-02", "", 7, time.Date(2026, time.January, 3, 12, 0, 0, 0, loc), loc, service.DefaultBusinessDayStartHourEAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBusinessDayBoundaries(t, start, end, loc)
+}
+
+// assertBusinessDayBoundaries checks that [start, end) is the business day of 2026-01-02
+func assertBusinessDayBoundaries(t *testing.T, start time.Time, end time.Time, loc *time.Location) {
+	t.Helper()
+
+	for _, tc := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{at: time.Date(2026, time.January, 2, 6, 59, 59, 0, loc), want: false},
+		{at: time.Date(2026, time.January, 2, 7, 0, 0, 0, loc), want: true},
+		{at: time.Date(2026, time.January, 3, 1, 30, 0, 0, loc), want: true},
+		{at: time.Date(2026, time.January, 3, 6, 59, 59, 0, loc), want: true},
+		{at: time.Date(2026, time.January, 3, 7, 0, 0, 0, loc), want: false}, // The end bound is exclusive
+	} {
+		// Repositories compare UTC timestamps against [start, end)
+		at := tc.at.UTC()
+		got := !at.Before(start.UTC()) && at.Before(end.UTC())
+		if got != tc.want {
+			t.Errorf("%s in [%s, %s) = %v, want %v", tc.at, start.In(loc), end.In(loc), got, tc.want)
+		}
+	}
+}
+
+func TestBusinessDayOffset(t *testing.T) {
+	loc := service.ReportLocation()
+	justBeforeOpening := time.Date(2026, time.January, 3, 3, 59, 59, 0, time.UTC) // 06:59:59 EAT
+
+	offset := service.BusinessDayOffset(justBeforeOpening, loc, service.DefaultBusinessDayStartHourEAT)
+	if offset != -4*time.Hour {
+		t.Fatalf("offset %s, want -4h", offset)
+	}
+	if got := justBeforeOpening.Add(offset).Format("2006-01-02"); got != "2026-01-02" {
+		t.Errorf("06:59:59 EAT falls on %s, want 2026-01-02", got)
+	}
+	if got := justBeforeOpening.Add(time.Second).Add(offset).Format("2006-01-02"); got != "2026-01-03" {
+		t.Errorf("07:00:00 EAT falls on %s, want 2026-01-03", got)
+	}
+}
+
+func TestBusinessDateRange(t *testing.T) {
+	loc := service.ReportLocation()
+	justBeforeOpening := time.Date(2026, time.January, 10, 6, 59, 59, 0, loc)
+	at := func(month time.Month, day int, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, loc)
+	}
+
+	for _, tc := range []struct {
+		name               string
+		from, to           string
+		defaultDays        int
+		advance            time.Duration
+		wantStart, wantEnd time.Time
+		wantErr            bool
+	}{
+		{name: "default before opening ends with the previous business day", defaultDays: 7, wantStart: at(time.January, 3, 7), wantEnd: at(time.January, 10, 7)},
+		{name: "default at opening includes the new business day", defaultDays: 7, advance: time.Second, wantStart: at(time.January, 4, 7), wantEnd: at(time.January, 11, 7)},
+		{name: "default days at least one", defaultDays: 0, wantStart: at(time.January, 9, 7), wantEnd: at(time.January, 10, 7)},
+		{name: "from and to inclusive", from: "2026-01-01", to: "2026-01-03", wantStart: at(time.January, 1, 7), wantEnd: at(time.January, 4, 7)},
+		{name: "only to", to: "2026-01-05", wantStart: at(time.January, 5, 7), wantEnd: at(time.January, 6, 7)},
+		{name: "to before from", from: "2026-01-03", to: "2026-01-01", wantErr: true},
+		{name: "bad date", from: "03/01/2026", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := service.BusinessDateRange(tc.from, tc.to, tc.defaultDays, justBeforeOpening.Add(tc.advance), loc, service.DefaultBusinessDayStartHourEAT)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("range [%s, %s), want an error", start, end)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !start.Equal(tc.wantStart) || !end.Equal(tc.wantEnd) {
+				t.Errorf("range [%s, %s), want [%s, %s)", start, end, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+const (
+	// cartReminderStopID is the reminder button that opts the customer out of further reminders
+	cartReminderStopID = "stop_reminders"
+
+	cartReminderPollInterval = time.Minute
+	cartReminderBatchSize    = 50
+)
+
+// CartReminder sends one WhatsApp nudge to customers who left items in their cart without checking out
+type CartReminder struct {
+	carts        core.CartActivityStore
+	sessions     core.SessionRepository
+	users        core.UserRepository
+	whatsapp     core.WhatsAppGateway
+	i18n         *i18n.Bundle
+	clock        core.Clock
+	idleAfter    time.Duration
+	frequencyCap time.Duration
+}
+
+// NewCartReminder creates a reminder job. Carts untouched for idleAfter get a nudge,
+// and a customer gets at most one nudge per frequencyCap.
+func NewCartReminder(carts core.CartActivityStore, sessions core.SessionRepository, users core.UserRepository, whatsapp core.WhatsAppGateway, idleAfter time.Duration, frequencyCap time.Duration) *CartReminder {
+	if idleAfter <= 0 {
+		idleAfter = 30 * time.Minute
+	}
+	if frequencyCap <= 0 {
+		frequencyCap = 24 * time.Hour
+	}
+
+	return &CartReminder{
+		carts:        carts,
+		sessions:     sessions,
+		users:        users,
+		whatsapp:     whatsapp,
+		i18n:         i18n.Default(),
+		clock:        core.SystemClock{},
+		idleAfter:    idleAfter,
+		frequencyCap: frequencyCap,
+	}
+}
+
+// Run checks for idle carts every minute until ctx is cancelled. Safe to run on every replica.
+func (r *CartReminder) Run(ctx context.Context) {
+	ticker := time.NewTicker(cartReminderPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.remindIdleCarts(ctx)
+		}
+	}
+}
+
+func (r *CartReminder) remindIdleCarts(ctx context.Context) {
+	phones, err := r.carts.ClaimIdleCarts(ctx, r.clock.Now().Add(-r.idleAfter), cartReminderBatchSize)
+	if err != nil {
+		log.Printf("Error claiming idle carts: %v", err)
+	}
+
+	for _, phone := range phones {
+		if err := r.remind(ctx, phone); err != nil {
+			log.Printf("Error sending cart reminder to %s: %v", phone, err)
+		}
+	}
+}
+
+// remind nudges one customer, skipping carts that were emptied or checked out, customers
+// who opted out and anyone already reminded within the frequency cap
+func (r *CartReminder) remind(ctx context.Context, phone string) error {
+	session, err := r.sessions.Get(ctx, phone)
+	if err != nil {
+		return nil // Session expired along with the cart
+	}
+	if len(session.Cart) == 0 || session.PendingOrderID != "" {
+		return nil
+	}
+
+	if user, err := r.users.GetByPhone(ctx, phone); err == nil && user.CartRemindersOptOut {
+		return nil
+	}
+
+	reserved, err := r.carts.ReserveReminder(ctx, phone, r.frequencyCap)
+	if err != nil || !reserved {
+		return err
+	}
+
+	items := 0
+	for _, item := range session.Cart {
+		items += item.Quantity
+	}
+
+	lang := sessionLanguage(session)
+	buttons := []core.Button{
+		{
+			ID:    "checkout",
+			Title: r.i18n.T(lang, "button.checkout"),
+		},
+		{
+			ID:    cartReminderStopID,
+			Title: r.i18n.T(lang, "button.stop_reminders"),
+		},
+	}
+	return r.whatsapp.SendMenuButtons(ctx, phone, r.i18n.T(lang, "cart.reminder", items), buttons)
+}
+
+// handleStopCartReminders opts the customer out of abandoned cart reminders
+func (b *BotService) handleStopCartReminders(ctx context.Context, phone string, session *core.Session) error {
+	user, err := b.UserRepo.GetOrCreateByPhone(ctx, phone)
+	if err != nil {
+		log.Printf("Failed to load user %s for cart reminder opt-out: %v", phone, err)
+	} else if err := b.UserRepo.SetCartRemindersOptOut(ctx, user.ID, true); err != nil {
+		log.Printf("Failed to store cart reminder opt-out for %s: %v", phone, err)
+	}
+
+	return b.WhatsApp.SendText(ctx, phone, b.t(session, "cart.reminders_stopped"))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// MessageRoute decides which channel carries a kind of critical message
+type MessageRoute string
+
+const (
+	RouteWhatsApp MessageRoute = "whatsapp" // WhatsApp only
+	RouteFallback MessageRoute = "fallback" // WhatsApp, then SMS if WhatsApp rejects the message
+	RouteSMS      MessageRoute = "sms"      // SMS only
+)
+
+// ParseMessageRoute validates a route name from configuration
+func ParseMessageRoute(route string) (MessageRoute, error) {
+	switch parsed := MessageRoute(strings.ToLower(strings.TrimSpace(route))); parsed {
+	case RouteWhatsApp, RouteFallback, RouteSMS:
+		return parsed, nil
+	case "":
+		return RouteFallback, nil
+	default:
+		return "", fmt.Errorf("invalid message route %q: use whatsapp, fallback or sms", route)
+	}
+}
+
+// smsMarkup strips WhatsApp's *bold* and _italic_ markers, which SMS shows literally
+var smsMarkup = strings.NewReplacer("*", "", "_", "")
+
+// CriticalMessenger sends messages a customer can't do without (payment confirmations) over WhatsApp,
+// SMS or WhatsApp with an SMS fallback. WhatsApp failures the client
+// retries on its own (rate limits, outages) are queued and don't trigger the fallback; errors like an
+// expired token or a customer who blocked the bot do.
+type CriticalMessenger struct {
+	whatsapp     core.WhatsAppGateway
+	sms          core.SMSGateway
+	paymentRoute MessageRoute
+}
+
+// NewCriticalMessenger creates the messenger; without an SMS gateway every route is WhatsApp only
+func NewCriticalMessenger(whatsapp core.WhatsAppGateway, sms core.SMSGateway, paymentRoute MessageRoute) *CriticalMessenger {
+	if sms == nil {
+		paymentRoute = RouteWhatsApp
+	}
+	return &CriticalMessenger{
+		whatsapp:     whatsapp,
+		sms:          sms,
+		paymentRoute: paymentRoute,
+	}
+}
+
+// SendPaymentConfirmation sends a customer's payment confirmation along the payment route
+func (m *CriticalMessenger) SendPaymentConfirmation(ctx context.Context, phone string, message string) error {
+	return m.send(ctx, m.paymentRoute, phone, message)
+}
+
+func (m *CriticalMessenger) send(ctx context.Context, route MessageRoute, phone string, message string) error {
+	switch route {
+	case RouteSMS:
+		return m.sms.SendSMS(ctx, phone, smsMarkup.Replace(message))
+	case RouteFallback:
+		err := m.whatsapp.SendText(ctx, phone, message)
+		if err == nil {
+			return nil
+		}
+		log.Printf("WhatsApp send to %s failed, falling back to SMS: %v", phone, err)
+		if smsErr := m.sms.SendSMS(ctx, phone, smsMarkup.Replace(message)); smsErr != nil {
+			return fmt.Errorf("whatsapp: %v; sms: %w", err, smsErr)
+		}
+		return nil
+	default:
+		return m.whatsapp.SendText(ctx, phone, message)
+	}
+}
@@ -3,17 +3,35 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dumu-tech/destination-cocktails/internal/core"
 	"github.com/dumu-tech/destination-cocktails/internal/events"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// otpMaxAttempts is how many guesses a single OTP code allows before it is invalidated
+	otpMaxAttempts = 5
+	// otpMaxRequests caps how many codes a phone number can request per otpRequestWindow
+	otpMaxRequests   = 3
+	otpRequestWindow = 15 * time.Minute
+	// defaultOTPResendCooldown is how long a manager waits before asking for the code again
+	defaultOTPResendCooldown = time.Minute
+)
+
+// The test admin gets the same OTP code when APP_ENV=development, so local and demo logins don't need WhatsApp
+const (
+	TestAdminPhone = "254700000000"
+	TestAdminOTP   = "123456"
+)
+
 // DashboardService handles dashboard business logic
 type DashboardService struct {
 	adminUserRepo   core.AdminUserRepository
@@ -22,8 +40,49 @@ type DashboardService struct {
 	orderRepo       core.OrderRepository
 	analyticsRepo   core.AnalyticsRepository
 	whatsappGateway core.WhatsAppGateway
+	otpSender       core.OTPSender
+	otpCooldown     time.Duration
+	testAdminOTP    bool
 	eventBus        *events.EventBus
 	jwtSecret       string
+	barStaffRepo    core.BarStaffRepository
+	paymentRepo     core.PaymentRepository
+	paymentWebhooks core.PaymentWebhookSubscriptions
+	stkAttemptRepo  core.STKAttemptRepository
+	staffNotifier   *BarStaffNotifier
+	outbox          *OutboxDispatcher
+	outboundStore   core.OutboundMessageStore
+	stkQueue        core.STKPushQueue
+	optionRepo      core.ProductOptionRepository
+	bundleRepo      core.BundleRepository
+	recipeRepo      core.RecipeRepository
+	stocktakeRepo   core.StocktakeRepository
+	supplierRepo    core.SupplierRepository
+	purchaseRepo    core.PurchaseOrderRepository
+	auditLogRepo    core.AuditLogRepository
+	webhookRepo     core.WebhookEventRepository
+	broadcastRepo   core.BroadcastRepository
+	feedbackRepo    core.FeedbackRepository
+	lowRating       int
+	prepSLA         time.Duration
+	shifts          *ShiftService
+	refreshTokens   core.RefreshTokenStore
+	orderingStatus  core.OrderingStatusStore
+	settings        *SettingsService
+	blocklist       *Blocklist
+	sessions        core.SessionRepository
+	sessionReader   core.SessionInspector
+	tabRepo         core.TabRepository
+	riderRepo       core.RiderRepository
+	riders          DeliveryNotifier
+	reservations    *ReservationService
+	limiter         *PurchaseLimiter
+	salesSheet      *SalesSheetSync
+	whatsAppNumbers *WhatsAppNumbers
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	clock           core.Clock
+	ids             core.IDGenerator
 }
 
 // NewDashboardService creates a new dashboard service
@@ -44,12 +103,46 @@ func NewDashboardService(
 		orderRepo:       orderRepo,
 		analyticsRepo:   analyticsRepo,
 		whatsappGateway: whatsappGateway,
+		otpSender:       &routedOTPSender{messenger: &CriticalMessenger{whatsapp: whatsappGateway}, route: RouteWhatsApp},
+		otpCooldown:     defaultOTPResendCooldown,
 		eventBus:        eventBus,
 		jwtSecret:       jwtSecret,
+		accessTokenTTL:  defaultAccessTokenTTL,
+		refreshTokenTTL: defaultRefreshTokenTTL,
+		clock:           core.SystemClock{},
+		ids:             core.UUIDGenerator{},
+	}
+}
+
+// SetClock overrides the time source (used for deterministic report windows and OTP expiry in tests)
+func (s *DashboardService) SetClock(clock core.Clock) {
+	s.clock = clock
+}
+
+// SetIDGenerator overrides how new record IDs are generated
+func (s *DashboardService) SetIDGenerator(ids core.IDGenerator) {
+	s.ids = ids
+}
+
+// SetOTPSender replaces WhatsApp as the login code channel (see NewOTPSender)
+func (s *DashboardService) SetOTPSender(sender core.OTPSender) {
+	s.otpSender = sender
+}
+
+// SetOTPResendCooldown sets how long ResendOTP waits after the last code
+func (s *DashboardService) SetOTPResendCooldown(cooldown time.Duration) {
+	if cooldown > 0 {
+		s.otpCooldown = cooldown
 	}
 }
 
-// RequestOTP generates and sends an OTP code via WhatsApp
+// SetTestAdminOTP enables the fixed TestAdminOTP for TestAdminPhone. Only enable it when APP_ENV is
+// explicitly development.
+func (s *DashboardService) SetTestAdminOTP(enabled bool) {
+	s.testAdminOTP = enabled
+}
+
+// RequestOTP generates and sends an OTP code via the configured OTP sender
 func (s *DashboardService) RequestOTP(ctx context.Context, phone string) error {
 	// OTP flow is manager-only.
 	adminUser, err := s.adminUserRepo.GetByPhone(ctx, phone)
@@ -61,10 +154,20 @@ func (s *DashboardService) RequestOTP(ctx context.Context, phone string) error {
 		return fmt.Errorf("unauthorized: OTP login is manager-only")
 	}
 
-	// Generate OTP code (hardcoded for test admin, random for others)
+	// Throttle code requests per phone so WhatsApp can't be spammed and new codes
+	// can't be used to reset the per-code attempt limit indefinitely.
+	recent, err := s.otpRepo.CountSince(ctx, phone, s.clock.Now().Add(-otpRequestWindow))
+	if err != nil {
+		return fmt.Errorf("failed to check OTP request limit: %w", err)
+	}
+	if recent >= otpMaxRequests {
+		return fmt.Errorf("too many OTP requests: please wait %d minutes before requesting a new code", int(otpRequestWindow.Minutes()))
+	}
+
+	// Generate OTP code (fixed for the test admin in development, random for everyone else)
 	var code string
-	if phone == "254700000000" {
-		code = "123456" // Hardcoded for test admin
+	if s.testAdminOTP && phone == TestAdminPhone {
+		code = TestAdminOTP
 	} else {
 		code, err = generateOTP()
 		if err != nil {
@@ -74,80 +177,96 @@ func (s *DashboardService) RequestOTP(ctx context.Context, phone string) error {
 
 	// Create OTP record
 	otp := &core.OTPCode{
-		ID:          uuid.New().String(),
+		ID:          s.ids.NewID(),
 		PhoneNumber: phone,
 		Code:        code,
-		ExpiresAt:   time.Now().Add(5 * time.Minute),
+		ExpiresAt:   s.clock.Now().Add(5 * time.Minute),
 		Verified:    false,
-		CreatedAt:   time.Now(),
+		CreatedAt:   s.clock.Now(),
 	}
 
 	if err := s.otpRepo.Create(ctx, otp); err != nil {
 		return fmt.Errorf("failed to save OTP: %w", err)
 	}
 
-	// Send OTP via WhatsApp
-	message := fmt.Sprintf("Your Destination Cocktails Dashboard login code is: *%s*\n\nThis code expires in 5 minutes.", code)
-	if err := s.whatsappGateway.SendText(ctx, phone, message); err != nil {
-		return fmt.Errorf("failed to send OTP via WhatsApp: %w", err)
+	if err := s.otpSender.SendOTP(ctx, phone, code); err != nil {
+		return fmt.Errorf("failed to send OTP: %w", err)
 	}
 
 	return nil
 }
 
-// VerifyOTP verifies an OTP code and returns a JWT token
-func (s *DashboardService) VerifyOTP(ctx context.Context, phone string, code string) (string, error) {
+// ResendOTP sends a new code once the cooldown since the last one has passed. It returns how long to
+// wait when it's too soon; the per-phone request limit of RequestOTP still applies.
+func (s *DashboardService) ResendOTP(ctx context.Context, phone string) (time.Duration, error) {
+	if latest, err := s.otpRepo.GetLatestByPhone(ctx, phone); err == nil {
+		if wait := latest.CreatedAt.Add(s.otpCooldown).Sub(s.clock.Now()); wait > 0 {
+			seconds := int((wait + time.Second - 1) / time.Second)
+			return wait, fmt.Errorf("too many OTP requests: please wait %d seconds before requesting a new code", seconds)
+		}
+	}
+	return 0, s.RequestOTP(ctx, phone)
+}
+
+// VerifyOTP verifies an OTP code and returns an access/refresh token pair
+func (s *DashboardService) VerifyOTP(ctx context.Context, phone string, code string) (*AuthTokens, error) {
 	// Get latest OTP for phone
 	otp, err := s.otpRepo.GetLatestByPhone(ctx, phone)
 	if err != nil {
-		return "", fmt.Errorf("invalid or expired OTP")
+		return nil, fmt.Errorf("invalid or expired OTP")
 	}
 
 	// Check if OTP is expired
-	if time.Now().After(otp.ExpiresAt) {
-		return "", fmt.Errorf("OTP has expired")
+	if s.clock.Now().After(otp.ExpiresAt) {
+		return nil, fmt.Errorf("OTP has expired")
+	}
+
+	// Count the attempt before comparing so concurrent guesses can't exceed the limit
+	attempts, err := s.otpRepo.IncrementAttempts(ctx, otp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify OTP: %w", err)
+	}
+	if attempts > otpMaxAttempts {
+		return nil, fmt.Errorf("too many failed attempts: request a new OTP code")
 	}
 
 	// Check if OTP code matches
-	if otp.Code != code {
-		return "", fmt.Errorf("invalid OTP code")
+	if subtle.ConstantTimeCompare([]byte(otp.Code), []byte(code)) != 1 {
+		if attempts == otpMaxAttempts {
+			return nil, fmt.Errorf("too many failed attempts: request a new OTP code")
+		}
+		return nil, fmt.Errorf("invalid OTP code: %d attempts remaining", otpMaxAttempts-attempts)
 	}
 
 	// Mark OTP as verified
 	if err := s.otpRepo.MarkAsVerified(ctx, otp.ID); err != nil {
-		return "", fmt.Errorf("failed to verify OTP: %w", err)
+		return nil, fmt.Errorf("failed to verify OTP: %w", err)
 	}
 
 	// Get admin user details
 	adminUser, err := s.adminUserRepo.GetByPhone(ctx, phone)
 	if err != nil {
-		return "", fmt.Errorf("admin user not found: %w", err)
+		return nil, fmt.Errorf("admin user not found: %w", err)
 	}
 
 	if !adminUser.IsActive {
-		return "", fmt.Errorf("unauthorized: admin user inactive")
+		return nil, fmt.Errorf("unauthorized: admin user inactive")
 	}
 
 	if adminUser.Role != core.AdminRoleManager {
-		return "", fmt.Errorf("unauthorized: OTP login is manager-only")
+		return nil, fmt.Errorf("unauthorized: OTP login is manager-only")
 	}
 
 	// OTP login always issues MANAGER role per RBAC contract.
 	adminUser.Role = core.AdminRoleManager
 
-	// Generate JWT token
-	token, err := s.generateJWT(adminUser)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	return token, nil
+	return s.issueTokens(ctx, adminUser)
 }
 
-// VerifyBartenderPIN verifies a bartender PIN and returns a JWT token.
-func (s *DashboardService) VerifyBartenderPIN(ctx context.Context, pin string) (string, error) {
+// VerifyBartenderPIN verifies a bartender PIN and returns an access/refresh token pair.
+func (s *DashboardService) VerifyBartenderPIN(ctx context.Context, pin string) (*AuthTokens, error) {
 	if !isValidFourDigitPIN(pin) {
-		return "", fmt.Errorf("PIN must be exactly 4 digits")
+		return nil, fmt.Errorf("PIN must be exactly 4 digits")
 	}
 
 	// Allow PIN login for dedicated bartenders and manager accounts that have a PIN configured.
@@ -155,7 +274,7 @@ func (s *DashboardService) VerifyBartenderPIN(ctx context.Context, pin string) (
 	for _, role := range []string{core.AdminRoleBartender, core.AdminRoleManager} {
 		users, err := s.adminUserRepo.GetActiveByRole(ctx, role)
 		if err != nil {
-			return "", fmt.Errorf("failed to fetch PIN-enabled accounts: %w", err)
+			return nil, fmt.Errorf("failed to fetch PIN-enabled accounts: %w", err)
 		}
 
 		for _, user := range users {
@@ -171,15 +290,11 @@ func (s *DashboardService) VerifyBartenderPIN(ctx context.Context, pin string) (
 		if err := bcrypt.CompareHashAndPassword([]byte(user.PinHash), []byte(pin)); err == nil {
 			// PIN login always issues BARTENDER role.
 			user.Role = core.AdminRoleBartender
-			token, tokenErr := s.generateJWT(user)
-			if tokenErr != nil {
-				return "", fmt.Errorf("failed to generate token: %w", tokenErr)
-			}
-			return token, nil
+			return s.issueTokens(ctx, user)
 		}
 	}
 
-	return "", fmt.Errorf("invalid PIN")
+	return nil, fmt.Errorf("invalid PIN")
 }
 
 // MarkOrderReady transitions an order from PAID to READY and notifies the customer.
@@ -193,6 +308,10 @@ func (s *DashboardService) MarkOrderReady(ctx context.Context, orderID string, a
 		return nil
 	}
 
+	if order.IsDelivery() {
+		return fmt.Errorf("delivery orders are dispatched, not marked READY")
+	}
+
 	if order.Status != core.OrderStatusPaid {
 		return fmt.Errorf("only PAID orders can be marked READY")
 	}
@@ -208,7 +327,7 @@ func (s *DashboardService) MarkOrderReady(ctx context.Context, orderID string, a
 		return fmt.Errorf("order marked ready but failed to notify customer: %w", err)
 	}
 
-	s.eventBus.PublishOrderReady(order)
+	s.eventBus.PublishOrderReady(ctx, order)
 
 	return nil
 }
@@ -232,7 +351,7 @@ func (s *DashboardService) MarkOrderCompleted(ctx context.Context, orderID strin
 		return fmt.Errorf("failed to mark order completed: %w", err)
 	}
 
-	s.eventBus.PublishOrderCompleted(orderID)
+	s.eventBus.PublishOrderCompleted(ctx, orderID)
 
 	return nil
 }
@@ -242,33 +361,142 @@ func (s *DashboardService) GetProducts(ctx context.Context) ([]*core.Product, er
 	return s.productRepo.GetAll(ctx)
 }
 
-// UpdateStock updates product stock and emits event
-func (s *DashboardService) UpdateStock(ctx context.Context, productID string, stock int) error {
-	if err := s.productRepo.UpdateStock(ctx, productID, stock); err != nil {
+// UpdateStock sets product stock, recording it in stock_adjustments as a correction, and emits event
+func (s *DashboardService) UpdateStock(ctx context.Context, productID string, stock int, actorUserID string) error {
+	adjustment, err := s.productRepo.CorrectStock(ctx, productID, stock, actorUserID)
+	if err != nil {
 		return err
 	}
 
-	// Emit stock updated event
-	s.eventBus.PublishStockUpdated(productID, stock)
+	// Emit stock updated event (and low_stock when the correction took it below the threshold)
+	s.publishStockChange(ctx, *adjustment)
 
 	return nil
 }
 
-// UpdatePrice updates product price and emits event
-func (s *DashboardService) UpdatePrice(ctx context.Context, productID string, price float64) error {
-	if err := s.productRepo.UpdatePrice(ctx, productID, price); err != nil {
+// UpdatePrice updates product price, recording who changed it, and emits event
+func (s *DashboardService) UpdatePrice(ctx context.Context, productID string, price float64, actorUserID string) error {
+	if err := s.productRepo.UpdatePrice(ctx, productID, price, actorUserID); err != nil {
 		return err
 	}
 
 	// Emit price updated event
-	s.eventBus.PublishPriceUpdated(productID, price)
+	s.eventBus.PublishPriceUpdated(ctx, productID, price, actorUserID, s.adminUserName(ctx, actorUserID))
 
 	return nil
 }
 
-// GetOrders retrieves orders with optional filters
-func (s *DashboardService) GetOrders(ctx context.Context, status string, limit int) ([]*core.Order, error) {
-	return s.orderRepo.GetAllWithFilters(ctx, status, limit)
+// GetPriceHistory retrieves a product's price changes, newest first
+func (s *DashboardService) GetPriceHistory(ctx context.Context, productID string, limit int) ([]*core.PriceChange, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.productRepo.GetPriceHistory(ctx, productID, limit)
+}
+
+// adminUserName resolves a dashboard user's name for events; a deleted or unknown user leaves it blank
+func (s *DashboardService) adminUserName(ctx context.Context, userID string) string {
+	if userID == "" {
+		return ""
+	}
+	user, err := s.adminUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	return user.Name
+}
+
+// GetArchivedProducts retrieves products that have been archived off the menu
+func (s *DashboardService) GetArchivedProducts(ctx context.Context) ([]*core.Product, error) {
+	return s.productRepo.GetArchived(ctx)
+}
+
+// ArchiveProduct removes a product from the menu while keeping it for order history and emits event
+func (s *DashboardService) ArchiveProduct(ctx context.Context, productID string) (*core.Product, error) {
+	return s.setProductArchived(ctx, productID, true)
+}
+
+// UnarchiveProduct returns an archived product to the menu and emits event
+func (s *DashboardService) UnarchiveProduct(ctx context.Context, productID string) (*core.Product, error) {
+	return s.setProductArchived(ctx, productID, false)
+}
+
+func (s *DashboardService) setProductArchived(ctx context.Context, productID string, archived bool) (*core.Product, error) {
+	if err := s.productRepo.SetArchived(ctx, productID, archived); err != nil {
+		return nil, err
+	}
+
+	s.eventBus.PublishProductArchived(ctx, productID, archived)
+
+	return s.productRepo.GetByID(ctx, productID)
+}
+
+// OrderQuery holds the raw order search filters accepted by the admin API
+type OrderQuery struct {
+	Status        string
+	PickupCode    string
+	Phone         string
+	PaymentMethod string
+	MinAmount     string
+	MaxAmount     string
+	From          string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	To            string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	Limit         int
+}
+
+// GetOrders retrieves orders matching the query, newest first
+func (s *DashboardService) GetOrders(ctx context.Context, query OrderQuery) ([]*core.Order, error) {
+	filter := core.OrderFilter{
+		Status:        strings.ToUpper(strings.TrimSpace(query.Status)),
+		PickupCode:    strings.TrimSpace(query.PickupCode),
+		Phone:         strings.TrimSpace(query.Phone),
+		PaymentMethod: strings.ToUpper(strings.TrimSpace(query.PaymentMethod)),
+		Limit:         query.Limit,
+	}
+
+	parseAmount := func(name string, raw string) (*float64, error) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return nil, nil
+		}
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil || amount < 0 {
+			return nil, fmt.Errorf("invalid %s: must be a non-negative number", name)
+		}
+		return &amount, nil
+	}
+	var err error
+	if filter.MinAmount, err = parseAmount("min_amount", query.MinAmount); err != nil {
+		return nil, err
+	}
+	if filter.MaxAmount, err = parseAmount("max_amount", query.MaxAmount); err != nil {
+		return nil, err
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, fmt.Errorf("invalid amount range: min_amount is greater than max_amount")
+	}
+
+	loc := reportLocation()
+	if from := strings.TrimSpace(query.From); from != "" {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+		}
+		filter.From = &start
+	}
+	if to := strings.TrimSpace(query.To); to != "" {
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	return s.orderRepo.Search(ctx, filter)
 }
 
 // GetOrderHistory retrieves completed orders for dispute lookup.
@@ -276,19 +504,87 @@ func (s *DashboardService) GetOrderHistory(ctx context.Context, pickupCode strin
 	return s.orderRepo.GetCompletedHistory(ctx, pickupCode, phone, limit)
 }
 
-// GetAnalyticsOverview retrieves dashboard overview metrics
-func (s *DashboardService) GetAnalyticsOverview(ctx context.Context) (*core.Analytics, error) {
-	return s.analyticsRepo.GetOverview(ctx)
+// GetOrderStatusHistory retrieves the status audit trail for a single order
+func (s *DashboardService) GetOrderStatusHistory(ctx context.Context, orderID string) ([]*core.OrderStatusChange, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.GetStatusHistory(ctx, orderID)
+}
+
+// GetOrderDetail retrieves one order with its items, payment shares, status timeline and the names of the
+// admin users who marked it ready and completed
+func (s *DashboardService) GetOrderDetail(ctx context.Context, orderID string) (*core.OrderDetail, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.orderRepo.GetStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	order.PaymentShares, err = s.orderRepo.GetPaymentShares(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, 2)
+	adminName := func(userID string) string {
+		if userID == "" {
+			return ""
+		}
+		if name, ok := names[userID]; ok {
+			return name
+		}
+		// A deleted admin user just leaves the name blank; the ID is still on the order
+		name := ""
+		if user, err := s.adminUserRepo.GetByID(ctx, userID); err == nil {
+			name = user.Name
+		}
+		names[userID] = name
+		return name
+	}
+
+	return &core.OrderDetail{
+		Order:           order,
+		ReadyByName:     adminName(order.ReadyByUserID),
+		CompletedByName: adminName(order.CompletedByUserID),
+		History:         history,
+	}, nil
+}
+
+// GetAnalyticsOverview retrieves dashboard overview metrics for business dates from..to
+// (YYYY-MM-DD, inclusive), defaulting to the current business day like the daily report
+func (s *DashboardService) GetAnalyticsOverview(ctx context.Context, from string, to string) (*core.Analytics, error) {
+	loc := reportLocation()
+	start, end, err := businessDateRange(from, to, 1, s.clock.Now().In(loc), loc, s.businessDayStartHour(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.GetOverview(ctx, start.UTC(), end.UTC())
 }
 
-// GetRevenueTrend retrieves revenue trend data
-func (s *DashboardService) GetRevenueTrend(ctx context.Context, days int) ([]*core.RevenueTrend, error) {
-	return s.analyticsRepo.GetRevenueTrend(ctx, days)
+// GetRevenueTrend retrieves revenue per business date for from..to, or the last `days` business days
+func (s *DashboardService) GetRevenueTrend(ctx context.Context, days int, from string, to string) ([]*core.RevenueTrend, error) {
+	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
+	start, end, err := businessDateRange(from, to, days, s.clock.Now().In(loc), loc, startHour)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.GetRevenueTrend(ctx, start.UTC(), end.UTC(), businessDayOffset(start, loc, startHour))
 }
 
-// GetTopProducts retrieves top-selling products
-func (s *DashboardService) GetTopProducts(ctx context.Context, limit int) ([]*core.TopProduct, error) {
-	return s.analyticsRepo.GetTopProducts(ctx, limit)
+// GetTopProducts retrieves top-selling products for from..to, or the last 30 business days
+func (s *DashboardService) GetTopProducts(ctx context.Context, limit int, from string, to string) ([]*core.TopProduct, error) {
+	loc := reportLocation()
+	start, end, err := businessDateRange(from, to, 30, s.clock.Now().In(loc), loc, s.businessDayStartHour(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.GetTopProducts(ctx, start.UTC(), end.UTC(), limit)
 }
 
 // GetEventBus returns the event bus for SSE subscriptions
@@ -332,8 +628,9 @@ func (s *DashboardService) generateJWT(user *core.AdminUser) (string, error) {
 		"phone":   user.PhoneNumber,
 		"name":    user.Name,
 		"role":    user.Role,
-		"exp":     time.Now().Add(7 * 24 * time.Hour).Unix(), // 7 days
-		"iat":     time.Now().Unix(),
+		"ver":     user.TokenVersion,
+		"exp":     s.clock.Now().Add(s.accessTokenTTL).Unix(),
+		"iat":     s.clock.Now().Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -359,3 +656,29 @@ func (s *DashboardService) ValidateJWT(tokenString string) (jwt.MapClaims, error
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// AuthorizeToken validates a JWT and checks it hasn't been revoked: the account must still be active
+// and the token's version must match the user's current token version
+func (s *DashboardService) AuthorizeToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	claims, err := s.ValidateJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := claims["user_id"].(string)
+	user, err := s.adminUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: admin user not found")
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("unauthorized: admin user inactive")
+	}
+
+	// Tokens issued before versioning carry no "ver" and count as version 0
+	version, _ := claims["ver"].(float64)
+	if int(version) != user.TokenVersion {
+		return nil, fmt.Errorf("unauthorized: session revoked")
+	}
+
+	return claims, nil
+}
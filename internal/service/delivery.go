@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+// DeliveryNotifier is told when a delivery order leaves the bar, e.g. to offer it to riders
+type DeliveryNotifier interface {
+	NotifyDispatched(ctx context.Context, order *core.Order) error
+}
+
+// SetDeliveryNotifier sets who is told when a delivery order is dispatched
+func (s *DashboardService) SetDeliveryNotifier(riders DeliveryNotifier) {
+	s.riders = riders
+}
+
+// DispatchOrder sends a PAID delivery order out (OUT_FOR_DELIVERY), offers it to riders and tells the customer it's on its way
+func (s *DashboardService) DispatchOrder(ctx context.Context, orderID string, actorUserID string) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if !order.IsDelivery() {
+		return fmt.Errorf("only delivery orders can be dispatched")
+	}
+	if order.Status == core.OrderStatusOutForDelivery {
+		return nil
+	}
+	if order.Status != core.OrderStatusPaid {
+		return fmt.Errorf("only PAID orders can be dispatched")
+	}
+
+	if err := s.orderRepo.UpdateStatusWithActor(ctx, orderID, core.OrderStatusOutForDelivery, actorUserID); err != nil {
+		return fmt.Errorf("failed to dispatch order: %w", err)
+	}
+
+	// Keep in-memory order aligned for SSE payload.
+	order.Status = core.OrderStatusOutForDelivery
+
+	if s.riders != nil {
+		if err := s.riders.NotifyDispatched(ctx, order); err != nil {
+			log.Printf("Error notifying riders about order %s: %v", order.ID, err)
+		}
+	}
+
+	s.eventBus.PublishOrderDispatched(ctx, order)
+
+	message := i18n.Default().T(i18n.DefaultLanguage, "delivery.dispatched", order.PickupCode)
+	if err := s.whatsappGateway.SendText(ctx, order.CustomerPhone, message); err != nil {
+		return fmt.Errorf("order dispatched but failed to notify customer: %w", err)
+	}
+
+	return nil
+}
+
+// MarkOrderDelivered transitions a delivery order from OUT_FOR_DELIVERY to DELIVERED.
+func (s *DashboardService) MarkOrderDelivered(ctx context.Context, orderID string, actorUserID string) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.Status == core.OrderStatusDelivered {
+		return nil
+	}
+	if order.Status != core.OrderStatusOutForDelivery {
+		return fmt.Errorf("only OUT_FOR_DELIVERY orders can be marked DELIVERED")
+	}
+
+	if err := s.orderRepo.UpdateStatusWithActor(ctx, orderID, core.OrderStatusDelivered, actorUserID); err != nil {
+		return fmt.Errorf("failed to mark order delivered: %w", err)
+	}
+
+	return announceDelivered(ctx, s.whatsappGateway, s.eventBus, order)
+}
+
+// announceDelivered tells the dashboard and the customer that a delivery order has arrived
+func announceDelivered(ctx context.Context, whatsapp core.WhatsAppGateway, eventBus *events.EventBus, order *core.Order) error {
+	if eventBus != nil {
+		eventBus.PublishOrderDelivered(ctx, order.ID)
+	}
+
+	message := i18n.Default().T(i18n.DefaultLanguage, "delivery.delivered", order.PickupCode)
+	if err := whatsapp.SendText(ctx, order.CustomerPhone, message); err != nil {
+		return fmt.Errorf("order marked delivered but failed to notify customer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package service
+
+// Unexported helpers exercised from the service_test package
+var (
+	ReportLocation                = reportLocation
+	CurrentBusinessDateInLocation = currentBusinessDateInLocation
+	BusinessDayWindow             = businessDayWindow
+	BusinessDateRange             = businessDateRange
+	BusinessDayOffset             = businessDayOffset
+)
+
+const DefaultBusinessDayStartHourEAT = defaultBusinessDayStartHourEAT
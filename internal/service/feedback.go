@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+const (
+	feedbackPollInterval = time.Minute
+	feedbackBatchSize    = 50
+	// feedbackRequestWindow is how far back a completed order can still be asked about, so turning
+	// the feature on doesn't message every past customer
+	feedbackRequestWindow = 24 * time.Hour
+	// feedbackReplyWindow is how long a rating request can still be answered
+	feedbackReplyWindow = 48 * time.Hour
+	// maxFeedbackCommentLength keeps comments readable on the dashboard
+	maxFeedbackCommentLength = 1000
+	defaultLowRatingAlert    = 2
+)
+
+// feedbackRatingID is the reply ID of the rating list row for n stars
+func feedbackRatingID(n int) string {
+	return fmt.Sprintf("rate_%d", n)
+}
+
+// FeedbackCollector asks customers to rate their order once it's completed, stores the answers and
+// alerts managers about low ratings
+type FeedbackCollector struct {
+	feedback       core.FeedbackRepository
+	users          core.UserRepository
+	admins         core.AdminUserRepository
+	whatsapp       core.WhatsAppGateway
+	i18n           *i18n.Bundle
+	clock          core.Clock
+	delay          time.Duration
+	alertAtOrBelow int
+}
+
+// NewFeedbackCollector creates the feedback job. Customers are asked delay after their order is
+// completed; ratings at or below alertAtOrBelow are sent to every active manager (2 when not set).
+func NewFeedbackCollector(feedback core.FeedbackRepository, users core.UserRepository, admins core.AdminUserRepository, whatsapp core.WhatsAppGateway, delay time.Duration, alertAtOrBelow int) *FeedbackCollector {
+	if delay < 0 {
+		delay = 0
+	}
+	if alertAtOrBelow <= 0 || alertAtOrBelow > 5 {
+		alertAtOrBelow = defaultLowRatingAlert
+	}
+
+	return &FeedbackCollector{
+		feedback:       feedback,
+		users:          users,
+		admins:         admins,
+		whatsapp:       whatsapp,
+		i18n:           i18n.Default(),
+		clock:          core.SystemClock{},
+		delay:          delay,
+		alertAtOrBelow: alertAtOrBelow,
+	}
+}
+
+// Run sends due rating requests every minute until ctx is cancelled. Safe to run on every replica.
+func (f *FeedbackCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(feedbackPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.requestDue(ctx)
+		}
+	}
+}
+
+func (f *FeedbackCollector) requestDue(ctx context.Context) {
+	completedBefore := f.clock.Now().Add(-f.delay)
+	requests, err := f.feedback.ClaimDue(ctx, completedBefore.Add(-feedbackRequestWindow), completedBefore, feedbackBatchSize)
+	if err != nil {
+		log.Printf("Error claiming feedback requests: %v", err)
+		return
+	}
+
+	for _, request := range requests {
+		if err := f.sendRequest(ctx, request); err != nil {
+			log.Printf("Error asking %s for feedback on order %s: %v", request.CustomerPhone, request.PickupCode, err)
+		}
+	}
+}
+
+// sendRequest asks for a 1-5 rating as list rows, or as typed "rate N" replies when the gateway has no lists
+func (f *FeedbackCollector) sendRequest(ctx context.Context, request *core.Feedback) error {
+	lang := i18n.DefaultLanguage
+	if user, err := f.users.GetByPhone(ctx, request.CustomerPhone); err == nil {
+		lang = i18n.Resolve(user.Language)
+	}
+
+	text := f.i18n.T(lang, "feedback.request", request.PickupCode)
+	if sender, ok := f.whatsapp.(listRowSender); ok {
+		rows := make([]core.ListRow, 0, 5)
+		for n := 5; n >= 1; n-- {
+			rows = append(rows, core.ListRow{
+				ID:          feedbackRatingID(n),
+				Title:       strings.Repeat("⭐", n),
+				Description: f.i18n.T(lang, fmt.Sprintf("feedback.rating_%d", n)),
+			})
+		}
+		return sender.SendListRows(ctx, request.CustomerPhone, text, f.i18n.T(lang, "feedback.button"), rows)
+	}
+	return f.whatsapp.SendText(ctx, request.CustomerPhone, text+f.i18n.T(lang, "feedback.reply_hint"))
+}
+
+// Rate records the rating for the phone's latest unanswered request. It returns nil when there is
+// no request to answer.
+func (f *FeedbackCollector) Rate(ctx context.Context, phone string, rating int) (*core.Feedback, error) {
+	if rating < 1 || rating > 5 {
+		return nil, fmt.Errorf("invalid rating: must be between 1 and 5")
+	}
+
+	request, err := f.feedback.GetLatestUnrated(ctx, phone, f.clock.Now().Add(-feedbackReplyWindow))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := f.feedback.Rate(ctx, request.ID, rating); err != nil {
+		return nil, err
+	}
+	now := f.clock.Now()
+	request.Rating = rating
+	request.RatedAt = &now
+
+	if rating <= f.alertAtOrBelow {
+		f.alertManagers(ctx, request)
+	}
+	return request, nil
+}
+
+// Comment stores the optional comment that follows a rating. Comments on low ratings are passed on
+// to managers too.
+func (f *FeedbackCollector) Comment(ctx context.Context, feedbackID string, comment string) error {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil
+	}
+	if runes := []rune(comment); len(runes) > maxFeedbackCommentLength {
+		comment = string(runes[:maxFeedbackCommentLength])
+	}
+
+	if err := f.feedback.SetComment(ctx, feedbackID, comment); err != nil {
+		return err
+	}
+
+	feedback, err := f.feedback.GetByID(ctx, feedbackID)
+	if err != nil {
+		return err
+	}
+	if feedback.Rating > 0 && feedback.Rating <= f.alertAtOrBelow {
+		f.alertManagers(ctx, feedback)
+	}
+	return nil
+}
+
+// alertManagers sends a low rating (and its comment, once given) to every active manager
+func (f *FeedbackCollector) alertManagers(ctx context.Context, feedback *core.Feedback) {
+	managers, err := f.admins.GetActiveByRole(ctx, core.AdminRoleManager)
+	if err != nil {
+		log.Printf("Failed to load managers for low rating alert on order %s: %v", feedback.PickupCode, err)
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ *Low rating: %d/5*\n\n*Order #%s*\n*Customer:* %s",
+		feedback.Rating, feedback.PickupCode, feedback.CustomerPhone)
+	if feedback.Comment != "" {
+		message += fmt.Sprintf("\n*Comment:* %s", feedback.Comment)
+	}
+
+	for _, manager := range managers {
+		if err := f.whatsapp.SendText(ctx, manager.PhoneNumber, message); err != nil {
+			log.Printf("Failed to alert manager %s about low rating on order %s: %v", manager.Name, feedback.PickupCode, err)
+		}
+	}
+}
+
+// SetFeedbackRepository wires the feedback report; ratings at or below lowRating are listed as low ratings
+func (s *DashboardService) SetFeedbackRepository(feedbackRepo core.FeedbackRepository, lowRating int) {
+	if lowRating <= 0 || lowRating > 5 {
+		lowRating = defaultLowRatingAlert
+	}
+	s.feedbackRepo = feedbackRepo
+	s.lowRating = lowRating
+}
+
+// GetFeedbackReport summarises ratings requested in from..to, or the last 30 business days: average,
+// response rate, per-rating counts, a daily trend and the latest low ratings
+func (s *DashboardService) GetFeedbackReport(ctx context.Context, from string, to string, limit int) (*core.FeedbackReport, error) {
+	if s.feedbackRepo == nil {
+		return nil, fmt.Errorf("feedback not configured")
+	}
+
+	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
+	start, end, err := businessDateRange(from, to, 30, s.clock.Now().In(loc), loc, startHour)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.feedbackRepo.CountRatings(ctx, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	trend, err := s.feedbackRepo.GetTrend(ctx, start.UTC(), end.UTC(), businessDayOffset(start, loc, startHour))
+	if err != nil {
+		return nil, err
+	}
+	startUTC, endUTC := start.UTC(), end.UTC()
+	lowRatings, err := s.feedbackRepo.List(ctx, core.FeedbackFilter{
+		From:      &startUTC,
+		To:        &endUTC,
+		MaxRating: s.lowRating,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &core.FeedbackReport{
+		Ratings:            make(map[int]int, 5),
+		Trend:              trend,
+		LowRatingThreshold: s.lowRating,
+		LowRatings:         lowRatings,
+		StartAt:            start,
+		EndAt:              end,
+	}
+	total := 0
+	for rating := 1; rating <= 5; rating++ {
+		report.Ratings[rating] = counts[rating]
+		report.Responses += counts[rating]
+		total += rating * counts[rating]
+	}
+	report.Requests = report.Responses + counts[0]
+	if report.Requests > 0 {
+		report.ResponseRate = math.Round(float64(report.Responses)/float64(report.Requests)*1000) / 10
+	}
+	if report.Responses > 0 {
+		report.AverageRating = math.Round(float64(total)/float64(report.Responses)*100) / 100
+	}
+	for _, day := range report.Trend {
+		day.AverageRating = math.Round(day.AverageRating*100) / 100
+	}
+	return report, nil
+}
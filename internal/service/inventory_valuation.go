@@ -0,0 +1,311 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	// defaultDeadStockDays is how long a product can go unsold before it's flagged as dead stock
+	defaultDeadStockDays = 30
+	maxDeadStockDays     = 365
+)
+
+var inventoryValuationCSVHeader = []string{
+	"product_id",
+	"product",
+	"category",
+	"stock_quantity",
+	"unit_cost",
+	"stock_value",
+	"unit_price",
+	"retail_value",
+	"last_sold_at",
+	"dead_stock",
+}
+
+// GetInventoryValuation values the stock on hand at cost per product and category and flags products
+// with no sales in the last deadStockDays (30 when zero)
+func (s *DashboardService) GetInventoryValuation(ctx context.Context, deadStockDays int) (*core.InventoryValuationReport, error) {
+	if deadStockDays == 0 {
+		deadStockDays = defaultDeadStockDays
+	}
+	if deadStockDays < 1 || deadStockDays > maxDeadStockDays {
+		return nil, fmt.Errorf("invalid dead_stock_days: must be between 1 and %d", maxDeadStockDays)
+	}
+
+	products, err := s.analyticsRepo.GetStockValuation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	deadBefore := now.AddDate(0, 0, -deadStockDays)
+	report := &core.InventoryValuationReport{
+		Products:      products,
+		Categories:    []*core.CategoryValuation{},
+		DeadStock:     []*core.StockValuation{},
+		DeadStockDays: deadStockDays,
+		GeneratedAt:   now,
+	}
+	categories := make(map[string]*core.CategoryValuation)
+	for _, product := range products {
+		product.Value = roundCents(float64(product.StockQuantity) * product.CostPrice)
+		product.RetailValue = roundCents(float64(product.StockQuantity) * product.Price)
+		product.DeadStock = product.LastSoldAt == nil || product.LastSoldAt.Before(deadBefore)
+		if product.CostPrice <= 0 {
+			report.UncostedProducts++
+		}
+		report.Value += product.Value
+		report.RetailValue += product.RetailValue
+		if product.DeadStock {
+			report.DeadStock = append(report.DeadStock, product)
+			report.DeadStockValue += product.Value
+		}
+
+		category, ok := categories[product.Category]
+		if !ok {
+			category = &core.CategoryValuation{Category: product.Category}
+			categories[product.Category] = category
+			report.Categories = append(report.Categories, category)
+		}
+		category.Products++
+		category.StockQuantity += product.StockQuantity
+		category.Value += product.Value
+		category.RetailValue += product.RetailValue
+	}
+
+	for _, category := range report.Categories {
+		category.Value = roundCents(category.Value)
+		category.RetailValue = roundCents(category.RetailValue)
+	}
+	report.Value = roundCents(report.Value)
+	report.RetailValue = roundCents(report.RetailValue)
+	report.DeadStockValue = roundCents(report.DeadStockValue)
+
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Value > report.Categories[j].Value
+	})
+	sort.SliceStable(report.DeadStock, func(i, j int) bool {
+		return report.DeadStock[i].Value > report.DeadStock[j].Value
+	})
+	return report, nil
+}
+
+// GenerateInventoryValuationReport renders the inventory valuation as PDF (default), CSV or XLSX
+func (s *DashboardService) GenerateInventoryValuationReport(ctx context.Context, deadStockDays int, format string) ([]byte, string, error) {
+	format, err := normalizeReportFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	report, err := s.GetInventoryValuation(ctx, deadStockDays)
+	if err != nil {
+		return nil, "", err
+	}
+
+	loc := reportLocation()
+	var data []byte
+	switch format {
+	case ReportFormatCSV:
+		data, err = renderInventoryValuationCSV(report, loc)
+	case ReportFormatXLSX:
+		data, err = renderInventoryValuationXLSX(report, loc)
+	default:
+		data, err = renderInventoryValuationPDF(report, loc)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("inventory-valuation-%s.%s", report.GeneratedAt.In(loc).Format("2006-01-02"), format)
+	return data, filename, nil
+}
+
+// renderInventoryValuationCSV renders one row per product; category totals are left to the spreadsheet
+func renderInventoryValuationCSV(report *core.InventoryValuationReport, loc *time.Location) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write(inventoryValuationCSVHeader); err != nil {
+		return nil, fmt.Errorf("failed to render CSV: %w", err)
+	}
+	for _, product := range report.Products {
+		lastSoldAt := ""
+		if product.LastSoldAt != nil {
+			lastSoldAt = product.LastSoldAt.In(loc).Format("2006-01-02 15:04:05")
+		}
+		if err := writer.Write([]string{
+			product.ProductID,
+			product.ProductName,
+			product.Category,
+			strconv.Itoa(product.StockQuantity),
+			formatCSVAmount(product.CostPrice),
+			formatCSVAmount(product.Value),
+			formatCSVAmount(product.Price),
+			formatCSVAmount(product.RetailValue),
+			lastSoldAt,
+			strconv.FormatBool(product.DeadStock),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to render CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to render CSV: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderInventoryValuationXLSX renders a Products sheet (the CSV columns, typed) and a Categories sheet
+func renderInventoryValuationXLSX(report *core.InventoryValuationReport, loc *time.Location) ([]byte, error) {
+	products := [][]interface{}{toInterfaces(inventoryValuationCSVHeader)}
+	for _, product := range report.Products {
+		var lastSoldAt interface{}
+		if product.LastSoldAt != nil {
+			lastSoldAt = *product.LastSoldAt
+		}
+		products = append(products, []interface{}{
+			product.ProductID,
+			product.ProductName,
+			product.Category,
+			product.StockQuantity,
+			product.CostPrice,
+			product.Value,
+			product.Price,
+			product.RetailValue,
+			lastSoldAt,
+			strconv.FormatBool(product.DeadStock),
+		})
+	}
+
+	categories := [][]interface{}{{"category", "products", "stock_quantity", "stock_value", "retail_value"}}
+	for _, category := range report.Categories {
+		categories = append(categories, []interface{}{
+			category.Category,
+			category.Products,
+			category.StockQuantity,
+			category.Value,
+			category.RetailValue,
+		})
+	}
+
+	return renderXLSX([]xlsxSheet{
+		{Name: "Products", Rows: products},
+		{Name: "Categories", Rows: categories},
+	}, loc)
+}
+
+func toInterfaces(values []string) []interface{} {
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return row
+}
+
+func renderInventoryValuationPDF(report *core.InventoryValuationReport, loc *time.Location) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 12)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 8, "Destination Cocktails", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 13)
+	pdf.CellFormat(0, 7, "Inventory Valuation", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Generated At: %s", formatReportDateTime(report.GeneratedAt, loc)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Dead Stock: no sales in the last %d days", report.DeadStockDays), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, "Summary", "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(95, 7, fmt.Sprintf("Stock at Cost: %s", formatKsh(report.Value)), "1", 0, "L", false, 0, "")
+	pdf.CellFormat(95, 7, fmt.Sprintf("Stock at Menu Price: %s", formatKsh(report.RetailValue)), "1", 1, "L", false, 0, "")
+	pdf.CellFormat(95, 7, fmt.Sprintf("Products in Stock: %d", len(report.Products)), "1", 0, "L", false, 0, "")
+	pdf.CellFormat(95, 7, fmt.Sprintf("Without Cost Price: %d", report.UncostedProducts), "1", 1, "L", false, 0, "")
+	pdf.CellFormat(190, 7, fmt.Sprintf("Dead Stock: %d products, %s at cost", len(report.DeadStock), formatKsh(report.DeadStockValue)), "1", 1, "L", false, 0, "")
+	pdf.Ln(3)
+
+	ensurePageSpace(pdf, 30)
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, "By Category", "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(70, 7, "Category", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(30, 7, "Products", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(30, 7, "Units", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(60, 7, "Value at Cost", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	if len(report.Categories) == 0 {
+		pdf.CellFormat(190, 7, "No stock on hand.", "1", 1, "L", false, 0, "")
+	}
+	for _, category := range report.Categories {
+		pdf.CellFormat(70, 7, safeReportValue(category.Category), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 7, strconv.Itoa(category.Products), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 7, strconv.Itoa(category.StockQuantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(60, 7, formatKsh(category.Value), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	renderStockLinesPDF(pdf, "Products", report.Products, loc, "No stock on hand.")
+	renderStockLinesPDF(pdf, "Dead Stock", report.DeadStock, loc, "Every product in stock has sold recently.")
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderStockLinesPDF prints one row per product with its stock, unit cost, value and last sale
+func renderStockLinesPDF(pdf *gofpdf.Fpdf, title string, products []*core.StockValuation, loc *time.Location, empty string) {
+	ensurePageSpace(pdf, 30)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, title, "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(60, 7, "Product", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 7, "Units", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 7, "Unit Cost", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 7, "Value", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 7, "Last Sold", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	if len(products) == 0 {
+		pdf.CellFormat(190, 7, empty, "1", 1, "L", false, 0, "")
+	}
+	for _, product := range products {
+		ensurePageSpace(pdf, 8)
+		lastSold := "Never"
+		if product.LastSoldAt != nil {
+			lastSold = product.LastSoldAt.In(loc).Format("02 Jan 2006")
+		}
+		unitCost := "-"
+		if product.CostPrice > 0 {
+			unitCost = formatKsh(product.CostPrice)
+		}
+		pdf.CellFormat(60, 7, safeReportValue(product.ProductName), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 7, strconv.Itoa(product.StockQuantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 7, unitCost, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 7, formatKsh(product.Value), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 7, lastSold, "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(3)
+}
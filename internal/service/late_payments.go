@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+// LatePaymentAlerts tells managers about M-Pesa payments that arrived for an order staff had already
+// cancelled. The payment isn't applied to the order, so someone has to refund the customer or attach it
+// to another order from the orphaned payments list.
+type LatePaymentAlerts struct {
+	admins   core.AdminUserRepository
+	whatsapp core.WhatsAppGateway
+	eventBus *events.EventBus
+}
+
+// NewLatePaymentAlerts creates the alerter
+func NewLatePaymentAlerts(admins core.AdminUserRepository, whatsapp core.WhatsAppGateway, eventBus *events.EventBus) *LatePaymentAlerts {
+	return &LatePaymentAlerts{
+		admins:   admins,
+		whatsapp: whatsapp,
+		eventBus: eventBus,
+	}
+}
+
+// AlertRefundRequired publishes payment_refund_required and messages every active manager
+func (a *LatePaymentAlerts) AlertRefundRequired(ctx context.Context, order *core.Order, amount float64, reference string) {
+	if a.eventBus != nil {
+		a.eventBus.PublishPaymentRefundRequired(ctx, order.ID, order.PickupCode, order.CustomerPhone, amount, reference)
+	}
+
+	managers, err := a.admins.GetActiveByRole(ctx, core.AdminRoleManager)
+	if err != nil {
+		log.Printf("Failed to load managers for refund alert on order %s: %v", order.PickupCode, err)
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ *Payment for a cancelled order*\n\n*Order #%s*\n*Customer:* %s\n*Amount:* KES %.0f\n*M-Pesa ref:* %s\n\nThe order stays cancelled. Refund the customer or attach the payment to another order.",
+		order.PickupCode, order.CustomerPhone, amount, reference)
+	for _, manager := range managers {
+		if err := a.whatsapp.SendText(ctx, manager.PhoneNumber, message); err != nil {
+			log.Printf("Failed to alert manager %s about payment for cancelled order %s: %v", manager.Name, order.PickupCode, err)
+		}
+	}
+}
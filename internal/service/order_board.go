@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	defaultBoardCompletedWindow = 30 * time.Minute
+	maxBoardCompletedWindow     = 4 * time.Hour
+	// maxBoardCompleted keeps the done column to what fits on a bar display
+	maxBoardCompleted = 20
+)
+
+// GetOrderBoard builds the live orders board: the PAID queue and READY orders oldest first, and orders
+// COMPLETED within completedWindow (30 minutes when not set) newest first. Displays load it once and
+// refetch on new_order, order_ready and order_completed SSE events.
+func (s *DashboardService) GetOrderBoard(ctx context.Context, completedWindow time.Duration) (*core.OrderBoard, error) {
+	if completedWindow <= 0 {
+		completedWindow = defaultBoardCompletedWindow
+	}
+	if completedWindow > maxBoardCompletedWindow {
+		completedWindow = maxBoardCompletedWindow
+	}
+
+	now := s.clock.Now()
+	orders, err := s.orderRepo.GetBoard(ctx, now.Add(-completedWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	board := &core.OrderBoard{
+		Paid:        []*core.BoardOrder{},
+		Ready:       []*core.BoardOrder{},
+		Completed:   []*core.BoardOrder{},
+		GeneratedAt: now,
+	}
+	for _, order := range orders {
+		if age := now.Sub(order.StatusSince); age > 0 {
+			order.AgeSeconds = int(age / time.Second)
+		}
+		switch order.Status {
+		case core.OrderStatusPaid:
+			board.Paid = append(board.Paid, order)
+		case core.OrderStatusReady:
+			board.Ready = append(board.Ready, order)
+		case core.OrderStatusCompleted:
+			board.Completed = append(board.Completed, order)
+		}
+	}
+
+	// Orders arrive oldest in status first; the done column reads newest first
+	for i, j := 0, len(board.Completed)-1; i < j; i, j = i+1, j-1 {
+		board.Completed[i], board.Completed[j] = board.Completed[j], board.Completed[i]
+	}
+	if len(board.Completed) > maxBoardCompleted {
+		board.Completed = board.Completed[:maxBoardCompleted]
+	}
+	return board, nil
+}
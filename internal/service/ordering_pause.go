@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+// maxPauseMessageLength keeps the customer-facing pause message well inside one WhatsApp text
+const maxPauseMessageLength = 500
+
+// SetOrderingStatusStore wires the store behind the "bar paused" switch
+func (s *DashboardService) SetOrderingStatusStore(store core.OrderingStatusStore) {
+	s.orderingStatus = store
+}
+
+// GetOrderingStatus returns whether the bot is currently accepting checkouts
+func (s *DashboardService) GetOrderingStatus(ctx context.Context) (*core.OrderingStatus, error) {
+	if s.orderingStatus == nil {
+		return nil, fmt.Errorf("ordering pause not configured")
+	}
+	return s.orderingStatus.GetOrderingStatus(ctx)
+}
+
+// SetOrderingPaused pauses or resumes new checkouts straight away and tells connected dashboards.
+// message replaces the default "not taking orders" reply while paused; it's cleared on resume.
+func (s *DashboardService) SetOrderingPaused(ctx context.Context, paused bool, message string, actorUserID string) (*core.OrderingStatus, error) {
+	if s.orderingStatus == nil {
+		return nil, fmt.Errorf("ordering pause not configured")
+	}
+
+	message = strings.TrimSpace(message)
+	if len(message) > maxPauseMessageLength {
+		return nil, fmt.Errorf("message must be at most %d characters", maxPauseMessageLength)
+	}
+	if !paused {
+		message = ""
+	}
+
+	status := &core.OrderingStatus{
+		Paused:    paused,
+		Message:   message,
+		UpdatedBy: actorUserID,
+		UpdatedAt: s.clock.Now(),
+	}
+	if err := s.orderingStatus.SetOrderingStatus(ctx, status); err != nil {
+		return nil, err
+	}
+
+	s.eventBus.PublishSettingsUpdated(ctx, events.SettingsUpdatedPayload{Ordering: status})
+	return status, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// OTP delivery channels (OTP_CHANNEL)
+const (
+	OTPChannelWhatsApp = "whatsapp"
+	OTPChannelSMS      = "sms"
+	OTPChannelFallback = "fallback" // WhatsApp, then SMS if WhatsApp rejects the message
+	OTPChannelConsole  = "console"  // Logged only, for local development
+)
+
+// otpMessage is the login code message, the same on every channel
+func otpMessage(code string) string {
+	return fmt.Sprintf("Your Destination Cocktails Dashboard login code is: *%s*\n\nThis code expires in 5 minutes.", code)
+}
+
+// NewOTPSender returns the login code sender for channel. Without an SMS gateway the fallback
+// channel is WhatsApp only and the sms channel is refused.
+func NewOTPSender(channel string, whatsapp core.WhatsAppGateway, sms core.SMSGateway) (core.OTPSender, error) {
+	switch strings.ToLower(strings.TrimSpace(channel)) {
+	case OTPChannelWhatsApp:
+		return &routedOTPSender{messenger: &CriticalMessenger{whatsapp: whatsapp}, route: RouteWhatsApp}, nil
+	case OTPChannelSMS:
+		if sms == nil {
+			return nil, fmt.Errorf("OTP channel sms needs an SMS gateway (SMS_PROVIDER)")
+		}
+		return &routedOTPSender{messenger: &CriticalMessenger{sms: sms}, route: RouteSMS}, nil
+	case OTPChannelFallback, "":
+		if sms == nil {
+			return &routedOTPSender{messenger: &CriticalMessenger{whatsapp: whatsapp}, route: RouteWhatsApp}, nil
+		}
+		return &routedOTPSender{messenger: &CriticalMessenger{whatsapp: whatsapp, sms: sms}, route: RouteFallback}, nil
+	case OTPChannelConsole:
+		return consoleOTPSender{}, nil
+	default:
+		return nil, fmt.Errorf("invalid OTP channel %q: use whatsapp, sms, fallback or console", channel)
+	}
+}
+
+// routedOTPSender sends login codes over WhatsApp, SMS or both, like payment confirmations
+type routedOTPSender struct {
+	messenger *CriticalMessenger
+	route     MessageRoute
+}
+
+// SendOTP sends the login code along the sender's route
+func (s *routedOTPSender) SendOTP(ctx context.Context, phone string, code string) error {
+	return s.messenger.send(ctx, s.route, phone, otpMessage(code))
+}
+
+// consoleOTPSender logs login codes instead of sending them, so local logins need no WhatsApp or SMS account
+type consoleOTPSender struct{}
+
+// SendOTP logs the login code
+func (consoleOTPSender) SendOTP(ctx context.Context, phone string, code string) error {
+	log.Printf("🔑 Dashboard login code for %s: %s (OTP_CHANNEL=console, not sent)", phone, code)
+	return nil
+}
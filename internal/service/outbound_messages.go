@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// SetOutboundMessageStore wires the WhatsApp retry queue so the dashboard can inspect dead letters
+func (s *DashboardService) SetOutboundMessageStore(store core.OutboundMessageStore) {
+	s.outboundStore = store
+}
+
+// ListWhatsAppDeadLetters retrieves WhatsApp messages that failed after all retries, newest first
+func (s *DashboardService) ListWhatsAppDeadLetters(ctx context.Context, limit int) ([]*core.OutboundMessage, error) {
+	if s.outboundStore == nil {
+		return nil, fmt.Errorf("whatsapp retry queue not configured")
+	}
+	return s.outboundStore.ListDeadLetters(ctx, limit)
+}
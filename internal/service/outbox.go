@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/reporting"
+)
+
+const (
+	outboxBatchSize     = 50
+	outboxLease         = 2 * time.Minute // A claimed message is retried after this if its dispatcher dies
+	outboxRetryBase     = 15 * time.Second
+	outboxRetryMaxDelay = 10 * time.Minute
+)
+
+// OutboxHandler delivers one outbox message; an error schedules a retry
+type OutboxHandler func(ctx context.Context, message *core.OutboxMessage) error
+
+// OutboxDispatcher delivers the side effects queued in the outbox with order status changes: it
+// claims due messages, runs the handler registered for each topic and retries failures with
+// exponential backoff. A message is only marked sent after its handler succeeds, so a crash
+// mid-delivery can repeat it but never lose it. Safe to run on every replica.
+type OutboxDispatcher struct {
+	repo        core.OutboxRepository
+	handlers    map[string]OutboxHandler
+	clock       core.Clock
+	interval    time.Duration
+	maxAttempts int
+	wake        chan struct{}
+}
+
+// NewOutboxDispatcher creates a dispatcher that polls every interval (2s when not set) and gives up
+// on a message after maxAttempts deliveries (8 when not set)
+func NewOutboxDispatcher(repo core.OutboxRepository, interval time.Duration, maxAttempts int) *OutboxDispatcher {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+
+	return &OutboxDispatcher{
+		repo:        repo,
+		handlers:    make(map[string]OutboxHandler),
+		clock:       core.SystemClock{},
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Handle registers the handler for a topic. Call it before Run.
+func (d *OutboxDispatcher) Handle(topic string, handler OutboxHandler) {
+	d.handlers[topic] = handler
+}
+
+// Wake makes Run deliver now instead of at its next poll, e.g. right after a payment commits
+func (d *OutboxDispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due messages until ctx is cancelled
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// dispatchDue delivers batches of due messages until none are left
+func (d *OutboxDispatcher) dispatchDue(ctx context.Context) {
+	for {
+		messages, err := d.repo.ClaimDue(ctx, d.clock.Now(), outboxLease, outboxBatchSize)
+		if err != nil {
+			log.Printf("Error claiming outbox messages: %v", err)
+			return
+		}
+		for _, message := range messages {
+			d.deliver(ctx, message)
+		}
+		if len(messages) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (d *OutboxDispatcher) deliver(ctx context.Context, message *core.OutboxMessage) {
+	handler, ok := d.handlers[message.Topic]
+	if !ok {
+		d.fail(ctx, message, fmt.Errorf("no handler for outbox topic %s", message.Topic), false)
+		return
+	}
+
+	deliverCtx, cancel := context.WithTimeout(ctx, outboxLease/2)
+	err := handler(deliverCtx, message)
+	cancel()
+	if err != nil {
+		d.fail(ctx, message, err, message.Attempts < d.maxAttempts)
+		return
+	}
+
+	if err := d.repo.MarkSent(ctx, message.ID, d.clock.Now()); err != nil {
+		log.Printf("Delivered outbox message %s (%s) but failed to mark it sent: %v", message.ID, message.Topic, err)
+	}
+}
+
+// fail schedules a retry of a failed message, or gives up on it
+func (d *OutboxDispatcher) fail(ctx context.Context, message *core.OutboxMessage, cause error, retry bool) {
+	var retryAt *time.Time
+	if retry {
+		at := d.clock.Now().Add(outboxRetryDelay(message.Attempts))
+		retryAt = &at
+		log.Printf("Outbox message %s (%s, order %s) failed on attempt %d, retrying at %s: %v",
+			message.ID, message.Topic, message.OrderID, message.Attempts, at.Format(time.RFC3339), cause)
+	} else {
+		reporting.CaptureError(ctx, "outbox."+message.Topic,
+			fmt.Errorf("giving up on outbox message %s for order %s after %d attempts: %w", message.ID, message.OrderID, message.Attempts, cause))
+	}
+
+	if err := d.repo.MarkFailed(ctx, message.ID, cause.Error(), retryAt); err != nil {
+		log.Printf("Failed to record outbox message %s failure: %v", message.ID, err)
+	}
+}
+
+// outboxRetryDelay doubles the wait after each failed attempt: 15s, 30s, 1m, ... up to 10m
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMaxDelay {
+		delay = outboxRetryMaxDelay
+	}
+	return delay
+}
+
+// SetOutbox lets dashboard payment actions wake the outbox dispatcher once their side effects are queued
+func (s *DashboardService) SetOutbox(outbox *OutboxDispatcher) {
+	s.outbox = outbox
+}
+
+// wakeOutbox asks the dispatcher to deliver what the last status change queued
+func (s *DashboardService) wakeOutbox() {
+	if s.outbox != nil {
+		s.outbox.Wake()
+	}
+}
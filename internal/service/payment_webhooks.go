@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// defaultPaymentWebhookEvent is the Kopo Kopo event that confirms till payments
+const defaultPaymentWebhookEvent = "buygoods_transaction_received"
+
+// PaymentWebhookStatus is the subscription list plus whether payments actually reach this server
+type PaymentWebhookStatus struct {
+	CallbackURL        string                      `json:"callback_url"`
+	CallbackSubscribed bool                        `json:"callback_subscribed"`
+	Subscriptions      []*core.WebhookSubscription `json:"subscriptions"`
+}
+
+// SetPaymentWebhookSubscriptions wires the provider client used by the webhook subscription endpoints
+func (s *DashboardService) SetPaymentWebhookSubscriptions(subscriptions core.PaymentWebhookSubscriptions) {
+	s.paymentWebhooks = subscriptions
+}
+
+// ListPaymentWebhookSubscriptions retrieves the provider's webhook subscriptions
+func (s *DashboardService) ListPaymentWebhookSubscriptions(ctx context.Context) (*PaymentWebhookStatus, error) {
+	if s.paymentWebhooks == nil {
+		return nil, fmt.Errorf("payment webhook subscriptions not configured")
+	}
+
+	subscriptions, err := s.paymentWebhooks.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment webhook subscriptions: %w", err)
+	}
+
+	callbackURL := s.paymentWebhooks.CallbackURL()
+	return &PaymentWebhookStatus{
+		CallbackURL:        callbackURL,
+		CallbackSubscribed: hasCallbackSubscription(subscriptions, callbackURL),
+		Subscriptions:      subscriptions,
+	}, nil
+}
+
+// CreatePaymentWebhookSubscription subscribes a URL to a provider event; both default to the
+// configured callback URL and buygoods_transaction_received
+func (s *DashboardService) CreatePaymentWebhookSubscription(ctx context.Context, eventType string, callbackURL string) (*core.WebhookSubscription, error) {
+	if s.paymentWebhooks == nil {
+		return nil, fmt.Errorf("payment webhook subscriptions not configured")
+	}
+
+	eventType = strings.TrimSpace(eventType)
+	if eventType == "" {
+		eventType = defaultPaymentWebhookEvent
+	}
+	callbackURL = strings.TrimSpace(callbackURL)
+	if callbackURL == "" {
+		callbackURL = s.paymentWebhooks.CallbackURL()
+	}
+	if parsed, err := url.Parse(callbackURL); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid url: an https callback URL is required")
+	}
+
+	return s.paymentWebhooks.CreateWebhookSubscription(ctx, eventType, callbackURL)
+}
+
+// DeletePaymentWebhookSubscription removes a provider webhook subscription
+func (s *DashboardService) DeletePaymentWebhookSubscription(ctx context.Context, id string) error {
+	if s.paymentWebhooks == nil {
+		return fmt.Errorf("payment webhook subscriptions not configured")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("invalid subscription ID")
+	}
+	return s.paymentWebhooks.DeleteWebhookSubscription(ctx, id)
+}
+
+// CheckPaymentWebhookSubscription warns at startup when no subscription delivers payments to the
+// configured callback URL, since orders would then never be marked paid
+func (s *DashboardService) CheckPaymentWebhookSubscription(ctx context.Context) {
+	status, err := s.ListPaymentWebhookSubscriptions(ctx)
+	if err != nil {
+		log.Printf("WARNING: could not verify payment webhook subscriptions: %v", err)
+		return
+	}
+	if !status.CallbackSubscribed {
+		log.Printf("WARNING: no Kopo Kopo webhook subscription targets %s; payments will not mark orders paid. Create one via POST /api/admin/payments/webhook-subscriptions", status.CallbackURL)
+		return
+	}
+	log.Printf("✅ Kopo Kopo webhook subscription found for %s", status.CallbackURL)
+}
+
+// hasCallbackSubscription reports whether any subscription posts to callbackURL (ignoring a trailing slash)
+func hasCallbackSubscription(subscriptions []*core.WebhookSubscription, callbackURL string) bool {
+	target := strings.TrimSuffix(strings.TrimSpace(callbackURL), "/")
+	if target == "" {
+		return false
+	}
+	for _, subscription := range subscriptions {
+		if strings.EqualFold(strings.TrimSuffix(subscription.URL, "/"), target) {
+			return true
+		}
+	}
+	return false
+}
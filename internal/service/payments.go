@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// PaymentQuery holds the raw payment ledger filters accepted by the admin API
+type PaymentQuery struct {
+	Provider  string
+	Phone     string
+	Reference string
+	OrderID   string
+	Orphan    *bool
+	From      string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	To        string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	Limit     int
+}
+
+// SetPaymentRepository wires the payments ledger used by the payment endpoints
+func (s *DashboardService) SetPaymentRepository(paymentRepo core.PaymentRepository) {
+	s.paymentRepo = paymentRepo
+}
+
+// SetSTKAttemptRepository wires the STK push attempt log used by the order payment-attempts endpoint
+func (s *DashboardService) SetSTKAttemptRepository(stkAttemptRepo core.STKAttemptRepository) {
+	s.stkAttemptRepo = stkAttemptRepo
+}
+
+// GetOrderPaymentAttempts retrieves the STK pushes sent for an order and their outcomes, oldest first
+func (s *DashboardService) GetOrderPaymentAttempts(ctx context.Context, orderID string) ([]*core.STKAttempt, error) {
+	if s.stkAttemptRepo == nil {
+		return nil, fmt.Errorf("stk attempt log not configured")
+	}
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.stkAttemptRepo.ListByOrder(ctx, orderID)
+}
+
+// ListPayments retrieves ledger entries matching the query, newest first
+func (s *DashboardService) ListPayments(ctx context.Context, query PaymentQuery) ([]*core.Payment, error) {
+	if s.paymentRepo == nil {
+		return nil, fmt.Errorf("payments ledger not configured")
+	}
+
+	filter := core.PaymentFilter{
+		Provider:  strings.ToUpper(strings.TrimSpace(query.Provider)),
+		Phone:     strings.TrimSpace(query.Phone),
+		Reference: strings.TrimSpace(query.Reference),
+		OrderID:   strings.TrimSpace(query.OrderID),
+		Orphan:    query.Orphan,
+		Limit:     query.Limit,
+	}
+
+	loc := reportLocation()
+	if from := strings.TrimSpace(query.From); from != "" {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+		}
+		filter.From = &start
+	}
+	if to := strings.TrimSpace(query.To); to != "" {
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	return s.paymentRepo.List(ctx, filter)
+}
+
+// ListOrphanPayments retrieves confirmed payments that no order matched
+func (s *DashboardService) ListOrphanPayments(ctx context.Context, limit int) ([]*core.Payment, error) {
+	orphan := true
+	return s.ListPayments(ctx, PaymentQuery{Orphan: &orphan, Limit: limit})
+}
+
+// AttachPaymentToOrder manually matches an orphaned payment to an unpaid order,
+// flips the order to PAID and has the outbox send the customer their pickup code. The payment must cover
+// whatever is still owed, so a partially paid split bill can be settled this way too.
+func (s *DashboardService) AttachPaymentToOrder(ctx context.Context, paymentID string, orderID string, actorUserID string) (*core.Order, error) {
+	if s.paymentRepo == nil {
+		return nil, fmt.Errorf("payments ledger not configured")
+	}
+
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !payment.IsOrphan {
+		return nil, fmt.Errorf("payment is already matched to an order")
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != core.OrderStatusPending && order.Status != core.OrderStatusPartiallyPaid && order.Status != core.OrderStatusFailed {
+		return nil, fmt.Errorf("only PENDING, PARTIALLY_PAID or FAILED orders can be matched to a payment (order is %s)", order.Status)
+	}
+	if payment.Amount < order.AmountDue() {
+		return nil, fmt.Errorf("payment amount KES %.0f is less than the KES %.0f still due", payment.Amount, order.AmountDue())
+	}
+
+	attached, err := s.paymentRepo.AttachOrder(ctx, paymentID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !attached {
+		return nil, fmt.Errorf("payment is already matched to an order")
+	}
+
+	note := fmt.Sprintf("payment %s attached manually", paymentID)
+	application, err := s.orderRepo.ApplyPayment(ctx, orderID, payment.Phone, payment.Amount, payment.Reference, actorUserID, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark order paid: %w", err)
+	}
+
+	// The customer's confirmation, bar staff message and SSE event were queued in the outbox with the payment
+	order.Status = application.Status
+	order.AmountPaid = application.AmountPaid
+	s.wakeOutbox()
+
+	return order, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	pendingOrderExpiryPollInterval = time.Minute
+	pendingOrderExpiryBatchSize    = 50
+)
+
+// PendingOrderReaper cancels PENDING orders whose payment never completed, tells the customer and puts
+// the order's items back in their cart so checking out again is one tap
+type PendingOrderReaper struct {
+	orders      core.OrderRepository
+	bot         *BotService
+	clock       core.Clock
+	expireAfter time.Duration
+}
+
+// NewPendingOrderReaper creates the expiry job; orders unpaid expireAfter after checkout are cancelled
+// (30 minutes when not set)
+func NewPendingOrderReaper(orders core.OrderRepository, bot *BotService, expireAfter time.Duration) *PendingOrderReaper {
+	if expireAfter <= 0 {
+		expireAfter = 30 * time.Minute
+	}
+
+	return &PendingOrderReaper{
+		orders:      orders,
+		bot:         bot,
+		clock:       core.SystemClock{},
+		expireAfter: expireAfter,
+	}
+}
+
+// Run cancels expired orders every minute until ctx is cancelled. Safe to run on every replica.
+func (r *PendingOrderReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(pendingOrderExpiryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.expireDue(ctx)
+		}
+	}
+}
+
+func (r *PendingOrderReaper) expireDue(ctx context.Context) {
+	orders, err := r.orders.GetExpiredPending(ctx, r.clock.Now().Add(-r.expireAfter), pendingOrderExpiryBatchSize)
+	if err != nil {
+		log.Printf("Error loading expired pending orders: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		if err := r.expire(ctx, order); err != nil {
+			log.Printf("Error expiring pending order %s: %v", order.ID, err)
+		}
+	}
+}
+
+func (r *PendingOrderReaper) expire(ctx context.Context, order *core.Order) error {
+	note := fmt.Sprintf("payment not completed within %s", r.expireAfter)
+	cancelled, fromTab, err := r.orders.CancelExpiredPending(ctx, order.ID, note)
+	if err != nil || !cancelled {
+		return err // Not cancelled: paid meanwhile, or another replica got it
+	}
+	log.Printf("Pending order %s (#%s) expired unpaid after %s", order.ID, order.PickupCode, r.expireAfter)
+
+	// Reload for the items to put back in the cart
+	expired, err := r.orders.GetByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reload expired order: %w", err)
+	}
+	return r.bot.RestoreExpiredOrder(ctx, expired, fromTab)
+}
+
+// RestoreExpiredOrder clears an expired order from the customer's session, puts its items back in the
+// cart unless the customer has started a new one, and tells them with a Checkout button. Items of a tab
+// order stay on the tab instead, where they're unpaid again.
+func (b *BotService) RestoreExpiredOrder(ctx context.Context, order *core.Order, fromTab bool) error {
+	user, err := b.UserRepo.GetByID(ctx, order.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load customer of expired order: %w", err)
+	}
+	phone := user.PhoneNumber
+
+	restore := func(ctx context.Context) error {
+		session, err := b.Session.Get(ctx, phone)
+		if err != nil {
+			session = &core.Session{
+				State:    "START",
+				Cart:     []core.CartItem{},
+				Language: b.preferredLanguage(ctx, phone),
+			}
+		}
+
+		if session.PendingOrderID == order.ID {
+			session.PendingOrderID = ""
+		}
+		if len(session.Cart) == 0 && !fromTab {
+			session.Cart = expiredOrderCart(order)
+		}
+		if err := b.Session.Set(ctx, phone, session, b.sessionTTL(ctx)); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+
+		if fromTab {
+			return b.WhatsApp.SendText(ctx, phone, b.t(session, "payment.expired_tab", order.PickupCode))
+		}
+		buttons := []core.Button{
+			{
+				ID:    "checkout",
+				Title: b.t(session, "button.checkout"),
+			},
+		}
+		return b.WhatsApp.SendMenuButtons(ctx, phone, b.t(session, "payment.expired", order.PickupCode), buttons)
+	}
+
+	// The customer may be messaging the bot right now; don't let either write overwrite the other
+	if b.Locks != nil {
+		return b.Locks.Run(ctx, phone, restore)
+	}
+	return restore(ctx)
+}
+
+// expiredOrderCart turns an order's items back into cart items
+func expiredOrderCart(order *core.Order) []core.CartItem {
+	cart := make([]core.CartItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		cart = append(cart, core.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Name:      item.ProductName,
+			Price:     item.PriceAtTime,
+			Modifiers: item.Modifiers,
+		})
+	}
+	return cart
+}
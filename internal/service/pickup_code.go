@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// Pickup code formats
+const (
+	PickupCodeNumeric      = "numeric"
+	PickupCodeAlphanumeric = "alphanumeric"
+)
+
+const (
+	pickupCodeDigits = "0123456789"
+	// Uppercase letters and digits without look-alikes (0/O, 1/I) so codes read cleanly off a phone screen.
+	pickupCodeAlphanumeric = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	pickupCodeMinLength   = 4
+	pickupCodeMaxLength   = 8
+	pickupCodeMaxAttempts = 10
+)
+
+// PickupCodeGenerator issues random pickup codes that don't clash with any open order
+type PickupCodeGenerator struct {
+	orderRepo core.OrderRepository
+	alphabet  string
+	length    int
+}
+
+// NewPickupCodeGenerator creates a generator. format is PickupCodeNumeric or PickupCodeAlphanumeric;
+// length is clamped to 4-8 characters.
+func NewPickupCodeGenerator(orderRepo core.OrderRepository, format string, length int) *PickupCodeGenerator {
+	alphabet := pickupCodeDigits
+	if strings.EqualFold(strings.TrimSpace(format), PickupCodeAlphanumeric) {
+		alphabet = pickupCodeAlphanumeric
+	}
+
+	if length < pickupCodeMinLength {
+		length = pickupCodeMinLength
+	}
+	if length > pickupCodeMaxLength {
+		length = pickupCodeMaxLength
+	}
+
+	return &PickupCodeGenerator{
+		orderRepo: orderRepo,
+		alphabet:  alphabet,
+		length:    length,
+	}
+}
+
+// Generate returns a code not used by any PENDING, PAID or READY order
+func (g *PickupCodeGenerator) Generate(ctx context.Context) (string, error) {
+	for attempt := 0; attempt < pickupCodeMaxAttempts; attempt++ {
+		code, err := g.randomCode()
+		if err != nil {
+			return "", err
+		}
+
+		inUse, err := g.orderRepo.IsPickupCodeActive(ctx, code)
+		if err != nil {
+			return "", fmt.Errorf("failed to check pickup code: %w", err)
+		}
+		if !inUse {
+			return code, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate a unique pickup code after %d attempts", pickupCodeMaxAttempts)
+}
+
+func (g *PickupCodeGenerator) randomCode() (string, error) {
+	max := big.NewInt(int64(len(g.alphabet)))
+	code := make([]byte, g.length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate pickup code: %w", err)
+		}
+		code[i] = g.alphabet[n.Int64()]
+	}
+	return string(code), nil
+}
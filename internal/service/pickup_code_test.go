@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+)
+
+// pickupCodeLookup is an order repository that only answers pickup code checks: the first collisions
+// codes it's asked about are taken, and err fails every check
+type pickupCodeLookup struct {
+	core.OrderRepository
+	collisions int
+	err        error
+	checked    []string
+}
+
+func (r *pickupCodeLookup) IsPickupCodeActive(ctx context.Context, code string) (bool, error) {
+	r.checked = append(r.checked, code)
+	if r.err != nil {
+		return false, r.err
+	}
+	return r.collisions < 0 || len(r.checked) <= r.collisions, nil
+}
+
+func TestPickupCodeFormats(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		format   string
+		length   int
+		alphabet string
+		wantLen  int
+	}{
+		{name: "numeric", format: service.PickupCodeNumeric, length: 4, alphabet: "0123456789", wantLen: 4},
+		{name: "alphanumeric without look-alikes", format: " Alphanumeric ", length: 6, alphabet: "ABCDEFGHJKLMNPQRSTUVWXYZ23456789", wantLen: 6},
+		{name: "unknown format is numeric", format: "emoji", length: 5, alphabet: "0123456789", wantLen: 5},
+		{name: "short length clamped", format: service.PickupCodeNumeric, length: 1, alphabet: "0123456789", wantLen: 4},
+		{name: "long length clamped", format: service.PickupCodeAlphanumeric, length: 20, alphabet: "ABCDEFGHJKLMNPQRSTUVWXYZ23456789", wantLen: 8},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			generator := service.NewPickupCodeGenerator(&pickupCodeLookup{}, tc.format, tc.length)
+			for i := 0; i < 50; i++ {
+				code, err := generator.Generate(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(code) != tc.wantLen {
+					t.Fatalf("code %q has %d characters, want %d", code, len(code), tc.wantLen)
+				}
+				for _, r := range code {
+					if !strings.ContainsRune(tc.alphabet, r) {
+						t.Fatalf("code %q has %q, which isn't in %s", code, r, tc.alphabet)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPickupCodeRetriesActiveCodes(t *testing.T) {
+	orders := &pickupCodeLookup{collisions: 3}
+
+	code, err := service.NewPickupCodeGenerator(orders, service.PickupCodeNumeric, 4).Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders.checked) != 4 {
+		t.Fatalf("checked %d codes, want 4 (3 collisions, then a free one)", len(orders.checked))
+	}
+	if code != orders.checked[3] {
+		t.Errorf("returned %q, want the first free code %q", code, orders.checked[3])
+	}
+}
+
+func TestPickupCodeExhausted(t *testing.T) {
+	orders := &pickupCodeLookup{collisions: -1}
+
+	if code, err := service.NewPickupCodeGenerator(orders, service.PickupCodeNumeric, 4).Generate(context.Background()); err == nil {
+		t.Fatalf("generated %q with every code in use", code)
+	}
+	if len(orders.checked) != 10 {
+		t.Errorf("checked %d codes before giving up, want 10", len(orders.checked))
+	}
+}
+
+func TestPickupCodeLookupError(t *testing.T) {
+	lookupErr := errors.New("connection refused")
+	orders := &pickupCodeLookup{err: lookupErr}
+
+	_, err := service.NewPickupCodeGenerator(orders, service.PickupCodeNumeric, 4).Generate(context.Background())
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("error %v, want it to wrap %v", err, lookupErr)
+	}
+	if len(orders.checked) != 1 {
+		t.Errorf("checked %d codes, want to stop at the first failure", len(orders.checked))
+	}
+}
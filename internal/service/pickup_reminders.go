@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+const pickupReminderPollInterval = time.Minute
+
+// UncollectedOrderNotifier alerts bar staff about a READY order nobody has picked up
+type UncollectedOrderNotifier interface {
+	NotifyUncollectedOrder(ctx context.Context, order *core.Order, waiting time.Duration) error
+}
+
+// PickupReminder re-pings customers whose order is still READY and, after escalateAfter,
+// flags the order for bartender attention on the dashboard and over WhatsApp
+type PickupReminder struct {
+	orderRepo     core.OrderRepository
+	users         core.UserRepository
+	whatsapp      core.WhatsAppGateway
+	staff         UncollectedOrderNotifier
+	eventBus      *events.EventBus
+	i18n          *i18n.Bundle
+	remindAfter   []time.Duration
+	escalateAfter time.Duration
+}
+
+// NewPickupReminder creates the reminder job. remindAfter lists when to re-ping the customer
+// (measured from READY, e.g. 10m and 20m); escalateAfter is when staff are alerted.
+func NewPickupReminder(orderRepo core.OrderRepository, users core.UserRepository, whatsapp core.WhatsAppGateway, staff UncollectedOrderNotifier, eventBus *events.EventBus, remindAfter []time.Duration, escalateAfter time.Duration) *PickupReminder {
+	if len(remindAfter) == 0 {
+		remindAfter = []time.Duration{10 * time.Minute, 20 * time.Minute}
+	}
+	if escalateAfter <= 0 {
+		escalateAfter = 30 * time.Minute
+	}
+
+	return &PickupReminder{
+		orderRepo:     orderRepo,
+		users:         users,
+		whatsapp:      whatsapp,
+		staff:         staff,
+		eventBus:      eventBus,
+		i18n:          i18n.Default(),
+		remindAfter:   remindAfter,
+		escalateAfter: escalateAfter,
+	}
+}
+
+// Run checks READY orders every minute until ctx is cancelled. Safe to run on every replica.
+func (p *PickupReminder) Run(ctx context.Context) {
+	ticker := time.NewTicker(pickupReminderPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.checkUncollected(ctx)
+		}
+	}
+}
+
+func (p *PickupReminder) checkUncollected(ctx context.Context) {
+	earliest := p.escalateAfter
+	if p.remindAfter[0] < earliest {
+		earliest = p.remindAfter[0]
+	}
+
+	orders, err := p.orderRepo.GetUncollected(ctx, earliest)
+	if err != nil {
+		log.Printf("Error loading uncollected orders: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		p.remindCustomer(ctx, order)
+		p.escalate(ctx, order)
+	}
+}
+
+// remindCustomer sends the latest reminder that is due and not yet sent.
+// Reminders that were missed (e.g. during a restart) are skipped rather than sent back to back.
+func (p *PickupReminder) remindCustomer(ctx context.Context, order *core.Order) {
+	for i := len(p.remindAfter) - 1; i >= 0; i-- {
+		reminder := i + 1
+		if order.ReadyReminders >= reminder {
+			return
+		}
+
+		claimed, err := p.orderRepo.ClaimReadyReminder(ctx, order.ID, reminder, p.remindAfter[i])
+		if err != nil {
+			log.Printf("Error recording pickup reminder for order %s: %v", order.ID, err)
+			return
+		}
+		if !claimed {
+			continue // Not due yet, or another replica sent it
+		}
+
+		message := p.i18n.T(p.customerLanguage(ctx, order), "order.ready_reminder", order.PickupCode)
+		if err := p.whatsapp.SendText(ctx, order.CustomerPhone, message); err != nil {
+			log.Printf("Error sending pickup reminder for order %s: %v", order.ID, err)
+		}
+		return
+	}
+}
+
+func (p *PickupReminder) escalate(ctx context.Context, order *core.Order) {
+	claimed, err := p.orderRepo.ClaimPickupEscalation(ctx, order.ID, p.escalateAfter)
+	if err != nil {
+		log.Printf("Error escalating uncollected order %s: %v", order.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	log.Printf("Order %s (#%s) still uncollected after %s, alerting bar staff", order.ID, order.PickupCode, p.escalateAfter)
+
+	if p.eventBus != nil {
+		p.eventBus.PublishPickupOverdue(ctx, order)
+	}
+	if p.staff != nil {
+		if err := p.staff.NotifyUncollectedOrder(ctx, order, p.escalateAfter); err != nil {
+			log.Printf("Error alerting bar staff about order %s: %v", order.ID, err)
+		}
+	}
+}
+
+func (p *PickupReminder) customerLanguage(ctx context.Context, order *core.Order) string {
+	if order.UserID == "" {
+		return i18n.DefaultLanguage
+	}
+	user, err := p.users.GetByID(ctx, order.UserID)
+	if err != nil {
+		return i18n.DefaultLanguage
+	}
+	return i18n.Resolve(user.Language)
+}
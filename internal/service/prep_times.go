@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+const prepSLAPollInterval = 30 * time.Second
+
+// PrepSLAMonitor warns the dashboard when an order has waited in the PAID queue longer than the
+// preparation SLA, once per order
+type PrepSLAMonitor struct {
+	orderRepo core.OrderRepository
+	eventBus  *events.EventBus
+	sla       time.Duration
+}
+
+// NewPrepSLAMonitor creates the SLA job; orders still PAID sla after payment raise a prep_overdue event
+func NewPrepSLAMonitor(orderRepo core.OrderRepository, eventBus *events.EventBus, sla time.Duration) *PrepSLAMonitor {
+	return &PrepSLAMonitor{
+		orderRepo: orderRepo,
+		eventBus:  eventBus,
+		sla:       sla,
+	}
+}
+
+// Run checks PAID orders every 30 seconds until ctx is cancelled. Safe to run on every replica.
+func (m *PrepSLAMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(prepSLAPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkOverdue(ctx)
+		}
+	}
+}
+
+func (m *PrepSLAMonitor) checkOverdue(ctx context.Context) {
+	orders, err := m.orderRepo.GetPrepOverdue(ctx, m.sla)
+	if err != nil {
+		log.Printf("Error loading overdue paid orders: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		claimed, err := m.orderRepo.ClaimPrepSLAAlert(ctx, order.ID, m.sla)
+		if err != nil {
+			log.Printf("Error flagging overdue order %s: %v", order.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Made ready meanwhile, or another replica flagged it
+		}
+
+		log.Printf("Order %s (#%s) still PAID after the %s preparation SLA", order.ID, order.PickupCode, m.sla)
+		if m.eventBus != nil {
+			m.eventBus.PublishPrepOverdue(ctx, order, int(m.sla/time.Second))
+		}
+	}
+}
+
+// SetPrepSLA sets the preparation SLA prep time reports count breaches against; 0 leaves them uncounted
+func (s *DashboardService) SetPrepSLA(sla time.Duration) {
+	s.prepSLA = sla
+}
+
+// GetPrepTimeReport reports PAID→READY and READY→COMPLETED times for orders paid in from..to, or the
+// last 30 business days: average, median and 90th percentile overall, per business day and per
+// bartender who marked the orders ready
+func (s *DashboardService) GetPrepTimeReport(ctx context.Context, from string, to string) (*core.PrepTimeReport, error) {
+	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
+	start, end, err := businessDateRange(from, to, 30, s.clock.Now().In(loc), loc, startHour)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.analyticsRepo.GetPrepTimes(ctx, start.UTC(), end.UTC(), businessDayOffset(start, loc, startHour), s.prepSLA)
+	if err != nil {
+		return nil, err
+	}
+
+	roundPrepTimes(report.Overall)
+	for _, day := range report.Days {
+		roundPrepTimes(day)
+	}
+	for _, bartender := range report.Bartenders {
+		roundPrepTimes(bartender)
+	}
+	sort.SliceStable(report.Bartenders, func(i, j int) bool {
+		return report.Bartenders[i].AvgPaidToReady < report.Bartenders[j].AvgPaidToReady
+	})
+
+	report.SLASeconds = int(s.prepSLA / time.Second)
+	report.StartAt = start
+	report.EndAt = end
+	return report, nil
+}
+
+// roundPrepTimes rounds durations to whole seconds
+func roundPrepTimes(stats *core.PrepTimeStats) {
+	stats.AvgPaidToReady = math.Round(stats.AvgPaidToReady)
+	stats.P50PaidToReady = math.Round(stats.P50PaidToReady)
+	stats.P90PaidToReady = math.Round(stats.P90PaidToReady)
+	stats.AvgReadyToCompleted = math.Round(stats.AvgReadyToCompleted)
+	stats.P50ReadyToCompleted = math.Round(stats.P50ReadyToCompleted)
+	stats.P90ReadyToCompleted = math.Round(stats.P90ReadyToCompleted)
+}
+
+// GetStaffReport reports, per dashboard user, the orders created in from..to (or the last 30 business
+// days) they marked ready and completed, their average paid→ready time and the tips on those orders
+func (s *DashboardService) GetStaffReport(ctx context.Context, from string, to string) (*core.StaffReport, error) {
+	loc := reportLocation()
+	start, end, err := businessDateRange(from, to, 30, s.clock.Now().In(loc), loc, s.businessDayStartHour(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	staff, err := s.analyticsRepo.GetStaffPerformance(ctx, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range staff {
+		member.AvgHandlingSeconds = math.Round(member.AvgHandlingSeconds)
+		member.Tips = roundCents(member.Tips)
+	}
+
+	return &core.StaffReport{
+		Staff:   staff,
+		StartAt: start,
+		EndAt:   end,
+	}, nil
+}
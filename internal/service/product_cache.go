@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+// productCacheMaxEntries bounds the cached categories and search results each
+const productCacheMaxEntries = 500
+
+// ProductCache is a ProductRepository decorator that keeps the menu, categories and search results the
+// bot reads on every customer message in memory for a short TTL. Product, stock and price changes made
+// through it drop the cache immediately, and every replica running Run drops its cache on a
+// stock_updated, price_updated, product_archived, product_restocked or settings_updated event (the
+// menu.hide_out_of_stock setting changes which products are listed). Other reads and all writes go to the repository.
+type ProductCache struct {
+	core.ProductRepository
+	eventBus *events.EventBus
+	clock    core.Clock
+	ttl      time.Duration
+
+	mu         sync.RWMutex
+	menu       *cachedMenu
+	categories map[string]*cachedProducts
+	searches   map[string]*cachedProducts
+	generation int // Bumped on invalidation so a load that raced with it isn't cached
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+type cachedMenu struct {
+	menu     map[string][]*core.Product
+	loadedAt time.Time
+}
+
+type cachedProducts struct {
+	products []*core.Product
+	loadedAt time.Time
+}
+
+// NewProductCache wraps a product repository with a cache whose entries live for ttl
+func NewProductCache(repo core.ProductRepository, eventBus *events.EventBus, ttl time.Duration) *ProductCache {
+	return &ProductCache{
+		ProductRepository: repo,
+		eventBus:          eventBus,
+		clock:             core.SystemClock{},
+		ttl:               ttl,
+		categories:        make(map[string]*cachedProducts),
+		searches:          make(map[string]*cachedProducts),
+	}
+}
+
+// Run drops the cache whenever any replica announces a product, stock or price change, until ctx is done.
+// resync_required means some of those announcements were missed, so it drops the cache too.
+func (c *ProductCache) Run(ctx context.Context) {
+	for event := range c.eventBus.SubscribeInternal(ctx, "product-cache") {
+		switch event.Type {
+		case events.EventStockUpdated, events.EventPriceUpdated, events.EventProductArchived,
+			events.EventProductRestocked, events.EventSettingsUpdated, events.EventResyncRequired:
+			c.invalidate()
+		}
+	}
+}
+
+// CacheStats returns hit, miss and invalidation counts since startup
+func (c *ProductCache) CacheStats() (hits uint64, misses uint64, invalidations uint64) {
+	return c.hits.Load(), c.misses.Load(), c.invalidations.Load()
+}
+
+// GetMenu returns the active menu grouped by category, from the cache while it's fresh
+func (c *ProductCache) GetMenu(ctx context.Context) (map[string][]*core.Product, error) {
+	c.mu.RLock()
+	cached, generation := c.menu, c.generation
+	c.mu.RUnlock()
+
+	if cached != nil && c.fresh(cached.loadedAt) {
+		c.hits.Add(1)
+		return copyMenu(cached.menu), nil
+	}
+	c.misses.Add(1)
+
+	menu, err := c.ProductRepository.GetMenu(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.menu = &cachedMenu{menu: copyMenu(menu), loadedAt: c.clock.Now()}
+	}
+	c.mu.Unlock()
+
+	return menu, nil
+}
+
+// GetByCategory returns a category's active products, from the cache while they're fresh
+func (c *ProductCache) GetByCategory(ctx context.Context, category string) ([]*core.Product, error) {
+	return c.cachedList(ctx, c.categories, category, func() ([]*core.Product, error) {
+		return c.ProductRepository.GetByCategory(ctx, category)
+	})
+}
+
+// SearchProducts returns active products matching query, from the cache while the result is fresh
+func (c *ProductCache) SearchProducts(ctx context.Context, query string) ([]*core.Product, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+	return c.cachedList(ctx, c.searches, key, func() ([]*core.Product, error) {
+		return c.ProductRepository.SearchProducts(ctx, query)
+	})
+}
+
+// UpdateStock updates a product's stock and drops the cache
+func (c *ProductCache) UpdateStock(ctx context.Context, id string, quantity int) error {
+	defer c.invalidate()
+	return c.ProductRepository.UpdateStock(ctx, id, quantity)
+}
+
+// AdjustStock adds to or removes from a product's stock and drops the cache
+func (c *ProductCache) AdjustStock(ctx context.Context, id string, delta int, reason string, actor string) (*core.StockAdjustment, error) {
+	defer c.invalidate()
+	return c.ProductRepository.AdjustStock(ctx, id, delta, reason, actor)
+}
+
+// CorrectStock sets a product's stock and drops the cache
+func (c *ProductCache) CorrectStock(ctx context.Context, id string, quantity int, actor string) (*core.StockAdjustment, error) {
+	defer c.invalidate()
+	return c.ProductRepository.CorrectStock(ctx, id, quantity, actor)
+}
+
+// UpdatePrice updates a product's price and drops the cache
+func (c *ProductCache) UpdatePrice(ctx context.Context, id string, price float64, actor string) error {
+	defer c.invalidate()
+	return c.ProductRepository.UpdatePrice(ctx, id, price, actor)
+}
+
+// SetBottleSize sets a product's bottle size and drops the cache
+func (c *ProductCache) SetBottleSize(ctx context.Context, id string, bottleML int) error {
+	defer c.invalidate()
+	return c.ProductRepository.SetBottleSize(ctx, id, bottleML)
+}
+
+// SetCostPrice sets a product's cost price and drops the cache
+func (c *ProductCache) SetCostPrice(ctx context.Context, id string, cost float64) error {
+	defer c.invalidate()
+	return c.ProductRepository.SetCostPrice(ctx, id, cost)
+}
+
+// SetArchived archives or restores a product and drops the cache
+func (c *ProductCache) SetArchived(ctx context.Context, id string, archived bool) error {
+	defer c.invalidate()
+	return c.ProductRepository.SetArchived(ctx, id, archived)
+}
+
+// SetHideWhenSoldOut sets a product's sold-out visibility and drops the cache
+func (c *ProductCache) SetHideWhenSoldOut(ctx context.Context, id string, hide *bool) error {
+	defer c.invalidate()
+	return c.ProductRepository.SetHideWhenSoldOut(ctx, id, hide)
+}
+
+// UpsertByName imports products and drops the cache
+func (c *ProductCache) UpsertByName(ctx context.Context, products []*core.Product, actor string) (int, int, error) {
+	defer c.invalidate()
+	return c.ProductRepository.UpsertByName(ctx, products, actor)
+}
+
+// cachedList serves a product list keyed in entries, loading and caching it on a miss
+func (c *ProductCache) cachedList(ctx context.Context, entries map[string]*cachedProducts, key string, load func() ([]*core.Product, error)) ([]*core.Product, error) {
+	c.mu.RLock()
+	cached, generation := entries[key], c.generation
+	c.mu.RUnlock()
+
+	if cached != nil && c.fresh(cached.loadedAt) {
+		c.hits.Add(1)
+		return copyProducts(cached.products), nil
+	}
+	c.misses.Add(1)
+
+	products, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		if len(entries) >= productCacheMaxEntries {
+			c.makeRoom(entries)
+		}
+		entries[key] = &cachedProducts{products: copyProducts(products), loadedAt: c.clock.Now()}
+	}
+	c.mu.Unlock()
+
+	return products, nil
+}
+
+// makeRoom drops expired entries, or every entry when that frees nothing. Callers hold c.mu.
+func (c *ProductCache) makeRoom(entries map[string]*cachedProducts) {
+	for key, entry := range entries {
+		if !c.fresh(entry.loadedAt) {
+			delete(entries, key)
+		}
+	}
+	if len(entries) >= productCacheMaxEntries {
+		clear(entries)
+	}
+}
+
+func (c *ProductCache) fresh(loadedAt time.Time) bool {
+	return c.clock.Now().Sub(loadedAt) < c.ttl
+}
+
+// invalidate drops every cached entry so the next reads load the products table
+func (c *ProductCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.menu = nil
+	clear(c.categories)
+	clear(c.searches)
+	c.generation++
+	c.invalidations.Add(1)
+}
+
+// copyProducts copies each product so callers can't change what's cached
+func copyProducts(products []*core.Product) []*core.Product {
+	copied := make([]*core.Product, len(products))
+	for i, product := range products {
+		clone := *product
+		copied[i] = &clone
+	}
+	return copied
+}
+
+func copyMenu(menu map[string][]*core.Product) map[string][]*core.Product {
+	copied := make(map[string][]*core.Product, len(menu))
+	for category, products := range menu {
+		copied[category] = copyProducts(products)
+	}
+	return copied
+}
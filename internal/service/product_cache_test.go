@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/dumu-tech/destination-cocktails/internal/testkit"
+)
+
+func TestProductCacheDropsEverythingOnResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := events.NewEventBus()
+	products := testkit.NewProductRepository(testkit.NewFakeClock(testkit.Epoch), &testkit.SequenceIDGenerator{}, testkit.SampleMenu()...)
+	cache := service.NewProductCache(products, bus, time.Hour)
+	go cache.Run(ctx)
+	waitFor(t, func() bool { subscribers, _, _, _, _ := bus.SubscriberStats(); return subscribers == 1 })
+
+	if _, err := cache.GetMenu(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.GetMenu(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if hits, misses, _ := cache.CacheStats(); hits != 1 || misses != 1 {
+		t.Fatalf("%d hits and %d misses before the resync, want 1 and 1", hits, misses)
+	}
+
+	bus.Publish(ctx, events.EventResyncRequired, events.ResyncRequiredPayload{Reason: events.ResyncSlowClient, Missed: 3})
+	waitFor(t, func() bool { _, _, invalidations := cache.CacheStats(); return invalidations == 1 })
+
+	if _, err := cache.GetMenu(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, misses, _ := cache.CacheStats(); misses != 2 {
+		t.Errorf("menu served from the cache after resync_required (%d misses, want 2)", misses)
+	}
+}
+
+// waitFor polls until done reports true, failing the test after a second
+func waitFor(t *testing.T, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// productCSVHeader is shared by import and export so an exported file can be edited and re-imported
+var productCSVHeader = []string{"name", "price", "category", "stock", "description"}
+
+// Product import row actions
+const (
+	ProductImportCreate = "create"
+	ProductImportUpdate = "update"
+)
+
+// ProductImportRow is the outcome for one CSV line (line 1 is the header)
+type ProductImportRow struct {
+	Line     int     `json:"line"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Category string  `json:"category"`
+	Stock    int     `json:"stock"`
+	Action   string  `json:"action,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// ProductImportResult summarises an import; nothing is written when DryRun is set or any row is invalid
+type ProductImportResult struct {
+	DryRun   bool               `json:"dry_run"`
+	Applied  bool               `json:"applied"`
+	Inserted int                `json:"inserted"`
+	Updated  int                `json:"updated"`
+	Invalid  int                `json:"invalid"`
+	Rows     []ProductImportRow `json:"rows"`
+}
+
+// ImportProducts upserts products by name from a CSV with columns name, price, category, stock, description.
+// Rows are validated up front and the import is all-or-nothing.
+func (s *DashboardService) ImportProducts(ctx context.Context, data io.Reader, dryRun bool, actorUserID string) (*ProductImportResult, error) {
+	rows, products, err := parseProductCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProductImportResult{DryRun: dryRun, Rows: rows}
+
+	names := make([]string, 0, len(products))
+	for _, product := range products {
+		names = append(names, product.Name)
+	}
+	existing, err := s.productRepo.GetByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Rows {
+		row := &result.Rows[i]
+		if row.Error != "" {
+			result.Invalid++
+			continue
+		}
+		if product, ok := existing[row.Name]; ok && product.Category == core.BundleCategory {
+			row.Error = "combos are managed from the bundles endpoints"
+			result.Invalid++
+			continue
+		} else if ok {
+			row.Action = ProductImportUpdate
+			result.Updated++
+		} else {
+			row.Action = ProductImportCreate
+			result.Inserted++
+		}
+	}
+
+	if dryRun || result.Invalid > 0 {
+		return result, nil
+	}
+
+	inserted, updated, err := s.productRepo.UpsertByName(ctx, products, actorUserID)
+	if err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	result.Inserted = inserted
+	result.Updated = updated
+
+	actorName := s.adminUserName(ctx, actorUserID)
+	for _, product := range products {
+		// New products have no earlier level to fall from, so they never raise low_stock
+		oldQuantity := product.StockQuantity
+		if previous, ok := existing[product.Name]; ok {
+			oldQuantity = previous.StockQuantity
+		}
+		s.publishStockChange(ctx, core.StockAdjustment{ProductID: product.ID, OldQuantity: oldQuantity, NewQuantity: product.StockQuantity})
+		s.eventBus.PublishPriceUpdated(ctx, product.ID, product.Price, actorUserID, actorName)
+	}
+
+	return result, nil
+}
+
+// ExportProducts renders the current (non-archived) catalogue, minus combos, in the import CSV format
+func (s *DashboardService) ExportProducts(ctx context.Context) ([]byte, string, error) {
+	all, err := s.productRepo.GetAll(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Combos need components, which this format can't carry
+	products := make([]*core.Product, 0, len(all))
+	for _, product := range all {
+		if product.Category != core.BundleCategory {
+			products = append(products, product)
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Category != products[j].Category {
+			return products[i].Category < products[j].Category
+		}
+		return products[i].Name < products[j].Name
+	})
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(productCSVHeader); err != nil {
+		return nil, "", fmt.Errorf("failed to render CSV: %w", err)
+	}
+	for _, product := range products {
+		if err := writer.Write([]string{
+			product.Name,
+			formatCSVAmount(product.Price),
+			product.Category,
+			strconv.Itoa(product.StockQuantity),
+			product.Description,
+		}); err != nil {
+			return nil, "", fmt.Errorf("failed to render CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, "", fmt.Errorf("failed to render CSV: %w", err)
+	}
+
+	filename := fmt.Sprintf("products-%s.csv", s.clock.Now().In(reportLocation()).Format("2006-01-02"))
+	return buffer.Bytes(), filename, nil
+}
+
+// parseProductCSV reads the header (columns in any order, description optional) and validates each row.
+// Valid rows are returned as products in file order; invalid ones carry an Error.
+func parseProductCSV(data io.Reader) ([]ProductImportRow, []*core.Product, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("invalid CSV: file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		// Excel prepends a byte order mark to UTF-8 CSVs
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		columns[column] = i
+	}
+	for _, required := range productCSVHeader[:4] {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("invalid CSV: missing %q column (expected %s)", required, strings.Join(productCSVHeader, ","))
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []ProductImportRow
+	var products []*core.Product
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := ProductImportRow{
+			Line:     line,
+			Name:     field(record, "name"),
+			Category: field(record, "category"),
+		}
+		if row.Name == "" && row.Category == "" && field(record, "price") == "" && field(record, "stock") == "" {
+			continue // blank line
+		}
+
+		price, priceErr := strconv.ParseFloat(field(record, "price"), 64)
+		stock, stockErr := strconv.Atoi(field(record, "stock"))
+		row.Price = price
+		row.Stock = stock
+
+		switch {
+		case row.Name == "":
+			row.Error = "name is required"
+		case row.Category == "":
+			row.Error = "category is required"
+		case strings.EqualFold(row.Category, core.BundleCategory):
+			row.Error = "combos are managed from the bundles endpoints"
+		case priceErr != nil || price <= 0:
+			row.Error = "price must be a number greater than 0"
+		case stockErr != nil || stock < 0:
+			row.Error = "stock must be a whole number of 0 or more"
+		}
+		if previous, dup := seen[row.Name]; dup && row.Error == "" {
+			row.Error = fmt.Sprintf("duplicate of line %d", previous)
+		}
+
+		rows = append(rows, row)
+		if row.Error != "" {
+			continue
+		}
+		seen[row.Name] = line
+		products = append(products, &core.Product{
+			Name:          row.Name,
+			Description:   field(record, "description"),
+			Price:         price,
+			Category:      row.Category,
+			StockQuantity: stock,
+			IsActive:      true,
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("invalid CSV: no product rows")
+	}
+	return rows, products, nil
+}
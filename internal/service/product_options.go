@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// maxOptionLabelLength matches WhatsApp's reply button title limit
+const maxOptionLabelLength = 20
+
+// ProductOptionInput holds the fields for a new product option
+type ProductOptionInput struct {
+	GroupName  string
+	Label      string
+	PriceDelta float64
+	SortOrder  int
+}
+
+// ProductOptionUpdate holds optional fields for updating a product option
+type ProductOptionUpdate struct {
+	GroupName  *string
+	Label      *string
+	PriceDelta *float64
+	SortOrder  *int
+	IsActive   *bool
+}
+
+// SetProductOptionRepository wires the product options used by the option management endpoints
+func (s *DashboardService) SetProductOptionRepository(optionRepo core.ProductOptionRepository) {
+	s.optionRepo = optionRepo
+}
+
+// ListProductOptions retrieves all serving options for a product, including inactive ones
+func (s *DashboardService) ListProductOptions(ctx context.Context, productID string) ([]*core.ProductOption, error) {
+	if s.optionRepo == nil {
+		return nil, fmt.Errorf("product options not configured")
+	}
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.optionRepo.GetByProductID(ctx, productID)
+}
+
+// CreateProductOption adds a serving option (e.g. Size: Double, +KES 200) to a product
+func (s *DashboardService) CreateProductOption(ctx context.Context, productID string, input ProductOptionInput) (*core.ProductOption, error) {
+	if s.optionRepo == nil {
+		return nil, fmt.Errorf("product options not configured")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	option := &core.ProductOption{
+		ID:         s.ids.NewID(),
+		ProductID:  product.ID,
+		GroupName:  strings.TrimSpace(input.GroupName),
+		Label:      strings.TrimSpace(input.Label),
+		PriceDelta: input.PriceDelta,
+		SortOrder:  input.SortOrder,
+		IsActive:   true,
+		CreatedAt:  s.clock.Now(),
+	}
+	if err := validateProductOption(option, product); err != nil {
+		return nil, err
+	}
+
+	if err := s.optionRepo.Create(ctx, option); err != nil {
+		return nil, err
+	}
+
+	return option, nil
+}
+
+// UpdateProductOption applies a partial update to one of a product's options
+func (s *DashboardService) UpdateProductOption(ctx context.Context, productID string, optionID string, update ProductOptionUpdate) (*core.ProductOption, error) {
+	if s.optionRepo == nil {
+		return nil, fmt.Errorf("product options not configured")
+	}
+
+	option, err := s.optionRepo.GetByID(ctx, optionID)
+	if err != nil {
+		return nil, err
+	}
+	if option.ProductID != productID {
+		return nil, fmt.Errorf("product option not found")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.GroupName != nil {
+		option.GroupName = strings.TrimSpace(*update.GroupName)
+	}
+	if update.Label != nil {
+		option.Label = strings.TrimSpace(*update.Label)
+	}
+	if update.PriceDelta != nil {
+		option.PriceDelta = *update.PriceDelta
+	}
+	if update.SortOrder != nil {
+		option.SortOrder = *update.SortOrder
+	}
+	if update.IsActive != nil {
+		option.IsActive = *update.IsActive
+	}
+
+	if err := validateProductOption(option, product); err != nil {
+		return nil, err
+	}
+
+	if err := s.optionRepo.Update(ctx, option); err != nil {
+		return nil, err
+	}
+
+	return option, nil
+}
+
+// DeleteProductOption removes one of a product's options; existing orders keep the modifiers they were placed with
+func (s *DashboardService) DeleteProductOption(ctx context.Context, productID string, optionID string) error {
+	if s.optionRepo == nil {
+		return fmt.Errorf("product options not configured")
+	}
+
+	option, err := s.optionRepo.GetByID(ctx, optionID)
+	if err != nil {
+		return err
+	}
+	if option.ProductID != productID {
+		return fmt.Errorf("product option not found")
+	}
+
+	return s.optionRepo.Delete(ctx, optionID)
+}
+
+// validateProductOption checks an option fits in a WhatsApp button and can't make the product free or negative
+func validateProductOption(option *core.ProductOption, product *core.Product) error {
+	if option.GroupName == "" {
+		return fmt.Errorf("group name is required")
+	}
+	if option.Label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if utf8.RuneCountInString(option.Label) > maxOptionLabelLength {
+		return fmt.Errorf("label must be at most %d characters", maxOptionLabelLength)
+	}
+	if product.Price+option.PriceDelta < 0 {
+		return fmt.Errorf("price delta must not make the product price negative")
+	}
+	return nil
+}
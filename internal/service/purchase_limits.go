@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// purchaseLimitCacheTTL bounds how long another replica keeps enforcing a limit a manager changed
+	purchaseLimitCacheTTL = time.Minute
+	// maxPurchaseLimit keeps typos like 1000 for 10 out of the rules
+	maxPurchaseLimit = 500
+)
+
+// PurchaseLimiter enforces the per-order caps on categories and products (e.g. at most 2 bottles of
+// Spirits, 10 Shots). Limits are cached for a minute, since every cart change is checked.
+type PurchaseLimiter struct {
+	limits   core.PurchaseLimitRepository
+	products core.ProductRepository
+	clock    core.Clock
+
+	mu       sync.RWMutex
+	cached   []*core.PurchaseLimit
+	loadedAt time.Time
+}
+
+// PurchaseLimitBreach is a limit a cart goes over
+type PurchaseLimitBreach struct {
+	Limit    *core.PurchaseLimit
+	Quantity int // Units the cart holds under the limit
+}
+
+// NewPurchaseLimiter creates a limiter reading limits from limits and product categories from products
+func NewPurchaseLimiter(limits core.PurchaseLimitRepository, products core.ProductRepository) *PurchaseLimiter {
+	return &PurchaseLimiter{
+		limits:   limits,
+		products: products,
+		clock:    core.SystemClock{},
+	}
+}
+
+// Check returns the first limit cart goes over, or nil when it's within every limit
+func (l *PurchaseLimiter) Check(ctx context.Context, cart []core.CartItem) (*PurchaseLimitBreach, error) {
+	limits, err := l.load(ctx)
+	if err != nil || len(limits) == 0 {
+		return nil, err
+	}
+
+	byProduct := make(map[string]int)
+	for _, item := range cart {
+		byProduct[item.ProductID] += item.Quantity
+	}
+	byCategory := make(map[string]int)
+	for _, limit := range limits {
+		if limit.Scope != core.PurchaseLimitCategory {
+			continue
+		}
+		// Category totals are only needed when a category has a limit
+		for productID, quantity := range byProduct {
+			product, err := l.products.GetByID(ctx, productID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get product: %w", err)
+			}
+			byCategory[strings.ToLower(product.Category)] += quantity
+		}
+		break
+	}
+
+	for _, limit := range limits {
+		quantity := byProduct[limit.Target]
+		if limit.Scope == core.PurchaseLimitCategory {
+			quantity = byCategory[strings.ToLower(limit.Target)]
+		}
+		if quantity > limit.MaxQuantity {
+			return &PurchaseLimitBreach{Limit: limit, Quantity: quantity}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Invalidate drops the cached limits so the next check reads them again
+func (l *PurchaseLimiter) Invalidate() {
+	l.mu.Lock()
+	l.cached = nil
+	l.mu.Unlock()
+}
+
+func (l *PurchaseLimiter) load(ctx context.Context) ([]*core.PurchaseLimit, error) {
+	now := l.clock.Now()
+	l.mu.RLock()
+	cached, loadedAt := l.cached, l.loadedAt
+	l.mu.RUnlock()
+	if cached != nil && now.Sub(loadedAt) < purchaseLimitCacheTTL {
+		return cached, nil
+	}
+
+	limits, err := l.limits.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	l.cached, l.loadedAt = limits, now
+	l.mu.Unlock()
+	return limits, nil
+}
+
+// checkPurchaseLimits tells the customer when cart goes over a purchase limit; added is how many units
+// of the breaching line were just added, so the message can say how many more fit. It returns true
+// when the cart was refused.
+func (b *BotService) checkPurchaseLimits(ctx context.Context, phone string, session *core.Session, cart []core.CartItem, added int) (bool, error) {
+	if b.Limits == nil {
+		return false, nil
+	}
+	breach, err := b.Limits.Check(ctx, cart)
+	if err != nil {
+		return false, err
+	}
+	if breach == nil {
+		return false, nil
+	}
+
+	message := b.t(session, "limit.reached", breach.Limit.MaxQuantity, breach.Limit.Label)
+	if added > 0 {
+		if more := breach.Limit.MaxQuantity - (breach.Quantity - added); more > 0 {
+			message += b.t(session, "limit.add_fewer", more)
+		} else {
+			message += b.t(session, "limit.none_left")
+		}
+	} else {
+		message += b.t(session, "limit.checkout", breach.Quantity)
+	}
+	return true, b.WhatsApp.SendText(ctx, phone, message)
+}
+
+// SetPurchaseLimiter wires the purchase limits managed from the dashboard
+func (s *DashboardService) SetPurchaseLimiter(limiter *PurchaseLimiter) {
+	s.limiter = limiter
+}
+
+// PurchaseLimitInput is a limit as set from the admin API
+type PurchaseLimitInput struct {
+	Scope       string `json:"scope"`  // CATEGORY or PRODUCT
+	Target      string `json:"target"` // Category name, or product ID
+	MaxQuantity int    `json:"max_quantity"`
+}
+
+// ListPurchaseLimits retrieves every purchase limit
+func (s *DashboardService) ListPurchaseLimits(ctx context.Context) ([]*core.PurchaseLimit, error) {
+	if s.limiter == nil {
+		return nil, fmt.Errorf("purchase limits not configured")
+	}
+	return s.limiter.limits.List(ctx)
+}
+
+// SetPurchaseLimit creates a limit, or changes the max quantity of the existing one for the same target
+func (s *DashboardService) SetPurchaseLimit(ctx context.Context, input PurchaseLimitInput) (*core.PurchaseLimit, error) {
+	if s.limiter == nil {
+		return nil, fmt.Errorf("purchase limits not configured")
+	}
+
+	limit := &core.PurchaseLimit{
+		Scope:       strings.ToUpper(strings.TrimSpace(input.Scope)),
+		Target:      strings.TrimSpace(input.Target),
+		MaxQuantity: input.MaxQuantity,
+	}
+	if limit.Target == "" {
+		return nil, fmt.Errorf("target is required")
+	}
+	if limit.MaxQuantity < 1 || limit.MaxQuantity > maxPurchaseLimit {
+		return nil, fmt.Errorf("invalid max_quantity: must be between 1 and %d", maxPurchaseLimit)
+	}
+	switch limit.Scope {
+	case core.PurchaseLimitCategory:
+		limit.Label = limit.Target
+	case core.PurchaseLimitProduct:
+		product, err := s.productRepo.GetByID(ctx, limit.Target)
+		if err != nil {
+			return nil, err
+		}
+		limit.Label = product.Name
+	default:
+		return nil, fmt.Errorf("invalid scope: use CATEGORY or PRODUCT")
+	}
+
+	if err := s.limiter.limits.Upsert(ctx, limit); err != nil {
+		return nil, err
+	}
+	s.limiter.Invalidate()
+	return limit, nil
+}
+
+// DeletePurchaseLimit removes a purchase limit
+func (s *DashboardService) DeletePurchaseLimit(ctx context.Context, id string) error {
+	if s.limiter == nil {
+		return fmt.Errorf("purchase limits not configured")
+	}
+	if err := s.limiter.limits.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.limiter.Invalidate()
+	return nil
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// SupplierInput holds the fields for a new supplier
+type SupplierInput struct {
+	Name        string
+	PhoneNumber string
+	Email       string
+	Notes       string
+}
+
+// SupplierUpdate holds optional fields for updating a supplier
+type SupplierUpdate struct {
+	Name        *string
+	PhoneNumber *string
+	Email       *string
+	Notes       *string
+	IsActive    *bool
+}
+
+// PurchaseOrderItemInput is one product ordered from a supplier
+type PurchaseOrderItemInput struct {
+	ProductID string
+	Quantity  int
+	UnitCost  float64
+}
+
+// PurchaseOrderInput holds the fields for a new purchase order
+type PurchaseOrderInput struct {
+	SupplierID string
+	Reference  string
+	Notes      string
+	Items      []PurchaseOrderItemInput
+}
+
+// ReceivedItemInput corrects what arrived for one product; nil fields keep what was ordered
+type ReceivedItemInput struct {
+	ProductID        string
+	ReceivedQuantity *int
+	UnitCost         *float64
+}
+
+// SetPurchasingRepositories wires the suppliers and purchase orders used by the restock endpoints
+func (s *DashboardService) SetPurchasingRepositories(supplierRepo core.SupplierRepository, purchaseRepo core.PurchaseOrderRepository) {
+	s.supplierRepo = supplierRepo
+	s.purchaseRepo = purchaseRepo
+}
+
+// ListSuppliers retrieves every supplier, active ones first
+func (s *DashboardService) ListSuppliers(ctx context.Context) ([]*core.Supplier, error) {
+	if s.supplierRepo == nil {
+		return nil, fmt.Errorf("suppliers not configured")
+	}
+	return s.supplierRepo.GetAll(ctx)
+}
+
+// CreateSupplier adds a supplier
+func (s *DashboardService) CreateSupplier(ctx context.Context, input SupplierInput) (*core.Supplier, error) {
+	if s.supplierRepo == nil {
+		return nil, fmt.Errorf("suppliers not configured")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	phone := strings.TrimSpace(input.PhoneNumber)
+	if len(phone) > 20 {
+		return nil, fmt.Errorf("phone_number must be at most 20 characters")
+	}
+
+	now := s.clock.Now()
+	supplier := &core.Supplier{
+		ID:          s.ids.NewID(),
+		Name:        name,
+		PhoneNumber: phone,
+		Email:       strings.TrimSpace(input.Email),
+		Notes:       strings.TrimSpace(input.Notes),
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.supplierRepo.Create(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// UpdateSupplier applies a partial update to a supplier
+func (s *DashboardService) UpdateSupplier(ctx context.Context, id string, update SupplierUpdate) (*core.Supplier, error) {
+	if s.supplierRepo == nil {
+		return nil, fmt.Errorf("suppliers not configured")
+	}
+
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		supplier.Name = name
+	}
+	if update.PhoneNumber != nil {
+		phone := strings.TrimSpace(*update.PhoneNumber)
+		if len(phone) > 20 {
+			return nil, fmt.Errorf("phone_number must be at most 20 characters")
+		}
+		supplier.PhoneNumber = phone
+	}
+	if update.Email != nil {
+		supplier.Email = strings.TrimSpace(*update.Email)
+	}
+	if update.Notes != nil {
+		supplier.Notes = strings.TrimSpace(*update.Notes)
+	}
+	if update.IsActive != nil {
+		supplier.IsActive = *update.IsActive
+	}
+
+	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+		return nil, err
+	}
+	supplier.UpdatedAt = s.clock.Now()
+	return supplier, nil
+}
+
+// DeactivateSupplier hides a supplier from new purchase orders while keeping its order history
+func (s *DashboardService) DeactivateSupplier(ctx context.Context, id string) error {
+	inactive := false
+	_, err := s.UpdateSupplier(ctx, id, SupplierUpdate{IsActive: &inactive})
+	return err
+}
+
+// ListPurchaseOrders retrieves recent purchase orders, optionally only those in one status
+func (s *DashboardService) ListPurchaseOrders(ctx context.Context, status string, limit int) ([]*core.PurchaseOrder, error) {
+	if s.purchaseRepo == nil {
+		return nil, fmt.Errorf("purchase orders not configured")
+	}
+
+	poStatus := core.PurchaseOrderStatus(strings.ToUpper(strings.TrimSpace(status)))
+	switch poStatus {
+	case "", core.PurchaseOrderOrdered, core.PurchaseOrderReceived, core.PurchaseOrderCancelled:
+	default:
+		return nil, fmt.Errorf("invalid status %q: use ORDERED, RECEIVED or CANCELLED", status)
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.purchaseRepo.GetAll(ctx, poStatus, limit)
+}
+
+// GetPurchaseOrder retrieves a purchase order with its items
+func (s *DashboardService) GetPurchaseOrder(ctx context.Context, id string) (*core.PurchaseOrder, error) {
+	if s.purchaseRepo == nil {
+		return nil, fmt.Errorf("purchase orders not configured")
+	}
+	return s.purchaseRepo.GetByID(ctx, id)
+}
+
+// CreatePurchaseOrder records stock ordered from an active supplier
+func (s *DashboardService) CreatePurchaseOrder(ctx context.Context, input PurchaseOrderInput, actorUserID string) (*core.PurchaseOrder, error) {
+	if s.purchaseRepo == nil || s.supplierRepo == nil {
+		return nil, fmt.Errorf("purchase orders not configured")
+	}
+
+	supplier, err := s.supplierRepo.GetByID(ctx, strings.TrimSpace(input.SupplierID))
+	if err != nil {
+		return nil, err
+	}
+	if !supplier.IsActive {
+		return nil, fmt.Errorf("invalid supplier: %s is inactive", supplier.Name)
+	}
+	if len(input.Items) == 0 {
+		return nil, fmt.Errorf("at least one item is required")
+	}
+
+	items := make([]core.PurchaseOrderItem, 0, len(input.Items))
+	seen := make(map[string]struct{}, len(input.Items))
+	totalCost := 0.0
+	for _, item := range input.Items {
+		productID := strings.TrimSpace(item.ProductID)
+		if productID == "" {
+			return nil, fmt.Errorf("item product_id is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("item quantity must be greater than zero")
+		}
+		if item.UnitCost < 0 {
+			return nil, fmt.Errorf("item unit_cost must not be negative")
+		}
+		if _, dup := seen[productID]; dup {
+			return nil, fmt.Errorf("invalid item: product %s is listed twice", productID)
+		}
+		seen[productID] = struct{}{}
+
+		product, err := s.productRepo.GetByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, core.PurchaseOrderItem{
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			UnitCost:    item.UnitCost,
+		})
+		totalCost += float64(item.Quantity) * item.UnitCost
+	}
+
+	order := &core.PurchaseOrder{
+		ID:           s.ids.NewID(),
+		SupplierID:   supplier.ID,
+		SupplierName: supplier.Name,
+		Status:       core.PurchaseOrderOrdered,
+		Reference:    strings.TrimSpace(input.Reference),
+		Notes:        strings.TrimSpace(input.Notes),
+		Items:        items,
+		TotalCost:    roundCents(totalCost),
+		CreatedBy:    actorUserID,
+		CreatedAt:    s.clock.Now(),
+	}
+	if err := s.purchaseRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ReceivePurchaseOrder books a delivery into stock at its unit costs and pushes the new stock levels to the dashboard
+func (s *DashboardService) ReceivePurchaseOrder(ctx context.Context, id string, received []ReceivedItemInput, actorUserID string) (*core.PurchaseOrder, error) {
+	if s.purchaseRepo == nil {
+		return nil, fmt.Errorf("purchase orders not configured")
+	}
+
+	overrides := make([]core.PurchaseOrderItem, 0, len(received))
+	for _, item := range received {
+		if item.ReceivedQuantity != nil && *item.ReceivedQuantity < 0 {
+			return nil, fmt.Errorf("received_quantity must not be negative")
+		}
+		override := core.PurchaseOrderItem{
+			ProductID:        strings.TrimSpace(item.ProductID),
+			ReceivedQuantity: item.ReceivedQuantity,
+		}
+		if item.UnitCost != nil {
+			if *item.UnitCost <= 0 {
+				return nil, fmt.Errorf("unit_cost must be greater than zero")
+			}
+			override.UnitCost = *item.UnitCost
+		}
+		overrides = append(overrides, override)
+	}
+
+	adjustments, err := s.purchaseRepo.Receive(ctx, id, overrides, actorUserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, adjustment := range adjustments {
+		s.publishStockChange(ctx, adjustment)
+	}
+
+	return s.purchaseRepo.GetByID(ctx, id)
+}
+
+// CancelPurchaseOrder cancels a purchase order that hasn't been received
+func (s *DashboardService) CancelPurchaseOrder(ctx context.Context, id string) error {
+	if s.purchaseRepo == nil {
+		return fmt.Errorf("purchase orders not configured")
+	}
+	return s.purchaseRepo.Cancel(ctx, id)
+}
+
+// SetCostPrice records what one stock unit of a product costs the bar; zero clears it. Orders placed
+// from now on are costed at this price, until the next purchase order received replaces it.
+func (s *DashboardService) SetCostPrice(ctx context.Context, productID string, cost float64) error {
+	if cost < 0 {
+		return fmt.Errorf("cost_price must not be negative")
+	}
+	return s.productRepo.SetCostPrice(ctx, productID, roundCents(cost))
+}
+
+// GetMarginReport compares net sales with the cost recorded on each order item for from..to, or the
+// last 30 business days, per product and per category. Products with no cost data at all are left out
+// of the lists; items sold before their product had a cost are reported as uncosted revenue and left
+// out of the margin rather than counted as pure profit.
+func (s *DashboardService) GetMarginReport(ctx context.Context, from string, to string) (*core.MarginReport, error) {
+	loc := reportLocation()
+	start, end, err := businessDateRange(from, to, 30, s.clock.Now().In(loc), loc, s.businessDayStartHour(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	margins, err := s.analyticsRepo.GetProductMargins(ctx, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &core.MarginReport{
+		Products:   []*core.ProductMargin{},
+		Categories: []*core.CategoryMargin{},
+		StartAt:    start,
+		EndAt:      end,
+	}
+	categories := make(map[string]*core.CategoryMargin)
+	for _, product := range margins {
+		product.Revenue = roundCents(product.Revenue)
+		product.UncostedRevenue = roundCents(product.UncostedRevenue)
+		product.Cost = roundCents(product.Cost)
+		report.Revenue += product.Revenue
+		report.UncostedRevenue += product.UncostedRevenue
+
+		costedRevenue := product.Revenue - product.UncostedRevenue
+		if costedRevenue <= 0 && product.Cost == 0 {
+			report.UncostedProducts++
+			continue
+		}
+
+		product.GrossProfit = roundCents(costedRevenue - product.Cost)
+		product.MarginPercent = marginPercent(product.GrossProfit, costedRevenue)
+		product.NegativeMargin = product.GrossProfit < 0
+		report.Products = append(report.Products, product)
+		report.Cost += product.Cost
+		report.GrossProfit += product.GrossProfit
+
+		category, ok := categories[product.Category]
+		if !ok {
+			category = &core.CategoryMargin{Category: product.Category}
+			categories[product.Category] = category
+			report.Categories = append(report.Categories, category)
+		}
+		category.QuantitySold += product.QuantitySold
+		category.Revenue += product.Revenue
+		category.UncostedRevenue += product.UncostedRevenue
+		category.Cost += product.Cost
+		category.GrossProfit += product.GrossProfit
+	}
+
+	for _, category := range report.Categories {
+		category.Revenue = roundCents(category.Revenue)
+		category.UncostedRevenue = roundCents(category.UncostedRevenue)
+		category.Cost = roundCents(category.Cost)
+		category.GrossProfit = roundCents(category.GrossProfit)
+		category.MarginPercent = marginPercent(category.GrossProfit, category.Revenue-category.UncostedRevenue)
+		category.NegativeMargin = category.GrossProfit < 0
+	}
+
+	sort.SliceStable(report.Products, func(i, j int) bool {
+		return report.Products[i].GrossProfit > report.Products[j].GrossProfit
+	})
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		return report.Categories[i].GrossProfit > report.Categories[j].GrossProfit
+	})
+
+	report.Revenue = roundCents(report.Revenue)
+	report.UncostedRevenue = roundCents(report.UncostedRevenue)
+	report.Cost = roundCents(report.Cost)
+	report.GrossProfit = roundCents(report.GrossProfit)
+	report.MarginPercent = marginPercent(report.GrossProfit, report.Revenue-report.UncostedRevenue)
+	return report, nil
+}
+
+// roundCents rounds a shilling amount to two decimal places
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// marginPercent is gross profit as a percentage of costed revenue, to one decimal place
+func marginPercent(grossProfit float64, costedRevenue float64) float64 {
+	if costedRevenue <= 0 {
+		return 0
+	}
+	return math.Round(grossProfit/costedRevenue*1000) / 10
+}
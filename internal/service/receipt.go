@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ReceiptSender delivers itemized PDF receipts to customers as WhatsApp documents
+type ReceiptSender struct {
+	whatsapp core.WhatsAppGateway
+	i18n     *i18n.Bundle
+}
+
+// NewReceiptSender creates a receipt sender
+func NewReceiptSender(whatsapp core.WhatsAppGateway) *ReceiptSender {
+	return &ReceiptSender{
+		whatsapp: whatsapp,
+		i18n:     i18n.Default(),
+	}
+}
+
+// SendReceipt renders the order's receipt and sends it to the ordering customer
+func (r *ReceiptSender) SendReceipt(ctx context.Context, order *core.Order, lang string) error {
+	data, err := renderReceiptPDF(order, reportLocation())
+	if err != nil {
+		return err
+	}
+
+	caption := r.i18n.T(lang, "receipt.caption", order.PickupCode)
+	if err := r.whatsapp.SendDocument(ctx, order.CustomerPhone, receiptFilename(order), data, caption); err != nil {
+		return fmt.Errorf("failed to send receipt: %w", err)
+	}
+
+	log.Printf("Receipt for order %s sent to %s", order.ID, order.CustomerPhone)
+	return nil
+}
+
+// receiptStatuses are the paid order statuses a receipt can be printed for
+var receiptStatuses = map[core.OrderStatus]bool{
+	core.OrderStatusPaid:           true,
+	core.OrderStatusScheduled:      true,
+	core.OrderStatusReady:          true,
+	core.OrderStatusCompleted:      true,
+	core.OrderStatusOutForDelivery: true,
+	core.OrderStatusDelivered:      true,
+}
+
+// GenerateOrderReceipt renders a receipt for reprinting from the dashboard.
+// The payment reference comes from the payments ledger when the order doesn't carry one.
+func (s *DashboardService) GenerateOrderReceipt(ctx context.Context, orderID string) ([]byte, string, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !receiptStatuses[order.Status] {
+		return nil, "", fmt.Errorf("invalid order status: receipts are only available for paid orders (status %s)", order.Status)
+	}
+
+	if order.PaymentRef == "" && s.paymentRepo != nil {
+		payments, err := s.paymentRepo.List(ctx, core.PaymentFilter{OrderID: order.ID, Limit: 1})
+		if err == nil && len(payments) > 0 {
+			order.PaymentRef = payments[0].Reference
+		}
+	}
+
+	data, err := renderReceiptPDF(order, reportLocation())
+	if err != nil {
+		return nil, "", err
+	}
+	return data, receiptFilename(order), nil
+}
+
+func receiptFilename(order *core.Order) string {
+	return fmt.Sprintf("receipt-%s.pdf", order.PickupCode)
+}
+
+// renderReceiptPDF builds a one-page itemized receipt sized for phone screens
+func renderReceiptPDF(order *core.Order, loc *time.Location) ([]byte, error) {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: 80, Ht: 200},
+	})
+	pdf.SetMargins(5, 5, 5)
+	pdf.SetAutoPageBreak(true, 5)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Arial", "B", 13)
+	pdf.CellFormat(0, 7, "Destination Cocktails", "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(0, 5, "RECEIPT", "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 8, fmt.Sprintf("Pickup #%s", safeReportValue(order.PickupCode)), "", 1, "C", false, 0, "")
+	pdf.Ln(1)
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.CellFormat(0, 4, fmt.Sprintf("Date: %s", formatReportDateTime(order.CreatedAt, loc)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 4, fmt.Sprintf("Order: %s", order.ID), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 4, fmt.Sprintf("Payment: %s", safeReportValue(order.PaymentMethod)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 4, fmt.Sprintf("Reference: %s", safeReportValue(order.PaymentRef)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 4, fmt.Sprintf("Customer: %s", safeReportValue(order.CustomerPhone)), "", 1, "L", false, 0, "")
+	if order.ScheduledFor != nil {
+		pdf.CellFormat(0, 4, fmt.Sprintf("Pre-order for: %s", FormatScheduledTime(*order.ScheduledFor)), "", 1, "L", false, 0, "")
+	}
+	if order.IsDelivery() {
+		pdf.MultiCell(0, 4, tr("Deliver to: "+order.DeliveryAddress), "", "L", false)
+	}
+	if order.Notes != "" {
+		pdf.MultiCell(0, 4, tr("Notes: "+order.Notes), "", "L", false)
+	}
+	pdf.CellFormat(0, 2, "", "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+
+	pdf.SetFont("Arial", "B", 8)
+	pdf.CellFormat(40, 5, "Item", "", 0, "L", false, 0, "")
+	pdf.CellFormat(8, 5, "Qty", "", 0, "R", false, 0, "")
+	pdf.CellFormat(22, 5, "Amount", "", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 8)
+	for _, item := range order.Items {
+		name := safeReportValue(item.ProductName)
+		if len(item.Modifiers) > 0 {
+			name += " (" + core.FormatModifiers(item.Modifiers) + ")"
+		}
+
+		x, y := pdf.GetXY()
+		pdf.MultiCell(40, 4, tr(name), "", "L", false)
+		nameBottom := pdf.GetY()
+		pdf.SetXY(x+40, y)
+		pdf.CellFormat(8, 4, fmt.Sprintf("%d", item.Quantity), "", 0, "R", false, 0, "")
+		pdf.CellFormat(22, 4, formatKsh(item.PriceAtTime*float64(item.Quantity)), "", 1, "R", false, 0, "")
+		if nameBottom > pdf.GetY() {
+			pdf.SetY(nameBottom)
+		}
+	}
+
+	pdf.CellFormat(0, 2, "", "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+	if order.TaxAmount > 0 {
+		pdf.SetFont("Arial", "", 8)
+		pdf.CellFormat(35, 5, "Net (excl. VAT)", "", 0, "L", false, 0, "")
+		pdf.CellFormat(35, 5, formatKsh(order.TotalAmount-order.TipAmount-order.DeliveryFee-order.TaxAmount), "", 1, "R", false, 0, "")
+		pdf.CellFormat(35, 5, fmt.Sprintf("VAT %s", formatTaxRate(order.TaxRate)), "", 0, "L", false, 0, "")
+		pdf.CellFormat(35, 5, formatKsh(order.TaxAmount), "", 1, "R", false, 0, "")
+	}
+	if order.TipAmount > 0 {
+		pdf.SetFont("Arial", "", 8)
+		pdf.CellFormat(35, 5, "Tip", "", 0, "L", false, 0, "")
+		pdf.CellFormat(35, 5, formatKsh(order.TipAmount), "", 1, "R", false, 0, "")
+	}
+	if order.DeliveryFee > 0 {
+		pdf.SetFont("Arial", "", 8)
+		pdf.CellFormat(35, 5, "Delivery", "", 0, "L", false, 0, "")
+		pdf.CellFormat(35, 5, formatKsh(order.DeliveryFee), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(35, 7, "TOTAL", "", 0, "L", false, 0, "")
+	pdf.CellFormat(35, 7, formatKsh(order.TotalAmount), "", 1, "R", false, 0, "")
+	pdf.Ln(3)
+
+	pdf.SetFont("Arial", "", 8)
+	footer := "Show your pickup code to the bartender when collecting your drinks. Thank you!"
+	if order.IsDelivery() {
+		footer = "Your order will be delivered to the address above. Thank you!"
+	}
+	pdf.MultiCell(0, 4, footer, "", "C", false)
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, fmt.Errorf("failed to render receipt PDF: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// RecipeIngredientInput is one ingredient product and how much of it goes into a cocktail
+type RecipeIngredientInput struct {
+	ProductID string
+	Measure   float64
+	Unit      string
+}
+
+// SetRecipeRepository wires the cocktail recipes used by the recipe endpoints
+func (s *DashboardService) SetRecipeRepository(recipeRepo core.RecipeRepository) {
+	s.recipeRepo = recipeRepo
+}
+
+// ListRecipes retrieves every cocktail with a recipe and how many more can be made from bottle stock
+func (s *DashboardService) ListRecipes(ctx context.Context) ([]*core.Recipe, error) {
+	if s.recipeRepo == nil {
+		return nil, fmt.Errorf("recipes not configured")
+	}
+	return s.recipeRepo.GetAll(ctx)
+}
+
+// SetRecipe replaces what goes into a cocktail; an empty list removes its recipe
+func (s *DashboardService) SetRecipe(ctx context.Context, productID string, inputs []RecipeIngredientInput) (*core.Recipe, error) {
+	if s.recipeRepo == nil {
+		return nil, fmt.Errorf("recipes not configured")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Category == core.BundleCategory {
+		return nil, fmt.Errorf("invalid recipe: combos are made from their components")
+	}
+
+	ingredients, err := s.resolveRecipeIngredients(ctx, productID, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recipeRepo.SetIngredients(ctx, productID, ingredients); err != nil {
+		return nil, err
+	}
+
+	return &core.Recipe{
+		Product:     *product,
+		Ingredients: ingredients,
+		Available:   core.RecipeAvailability(ingredients),
+	}, nil
+}
+
+// SetBottleSize records how many ml one stock unit of an ingredient holds, so ml measures can be poured from it
+func (s *DashboardService) SetBottleSize(ctx context.Context, productID string, bottleML int) error {
+	if bottleML < 0 {
+		return fmt.Errorf("bottle_ml must not be negative")
+	}
+	return s.productRepo.SetBottleSize(ctx, productID, bottleML)
+}
+
+// resolveRecipeIngredients validates ingredient inputs and looks up each product's name and stock.
+// Ingredients can't have recipes of their own, so availability never has to recurse.
+func (s *DashboardService) resolveRecipeIngredients(ctx context.Context, cocktailID string, inputs []RecipeIngredientInput) ([]core.RecipeIngredient, error) {
+	ingredients := make([]core.RecipeIngredient, 0, len(inputs))
+	seen := make(map[string]struct{}, len(inputs))
+	for _, input := range inputs {
+		productID := strings.TrimSpace(input.ProductID)
+		if productID == "" {
+			return nil, fmt.Errorf("ingredient product_id is required")
+		}
+		if input.Measure <= 0 {
+			return nil, fmt.Errorf("ingredient measure must be greater than zero")
+		}
+		if productID == cocktailID {
+			return nil, fmt.Errorf("invalid ingredient: a cocktail can't contain itself")
+		}
+		if _, dup := seen[productID]; dup {
+			return nil, fmt.Errorf("invalid ingredient: product %s is listed twice", productID)
+		}
+		seen[productID] = struct{}{}
+
+		unit := core.RecipeUnit(strings.ToLower(strings.TrimSpace(input.Unit)))
+		switch unit {
+		case core.RecipeUnitML, core.RecipeUnitCount:
+		default:
+			return nil, fmt.Errorf("invalid ingredient unit %q: use ml or count", input.Unit)
+		}
+		if unit == core.RecipeUnitCount && input.Measure != math.Trunc(input.Measure) {
+			return nil, fmt.Errorf("invalid ingredient: count measures must be whole numbers")
+		}
+
+		product, err := s.productRepo.GetByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if product.Category == core.BundleCategory {
+			return nil, fmt.Errorf("invalid ingredient: %s is a combo", product.Name)
+		}
+		if unit == core.RecipeUnitML && product.BottleML <= 0 {
+			return nil, fmt.Errorf("invalid ingredient: set a bottle size for %s before measuring it in ml", product.Name)
+		}
+		if nested, err := s.recipeRepo.GetIngredients(ctx, productID); err != nil {
+			return nil, err
+		} else if len(nested) > 0 {
+			return nil, fmt.Errorf("invalid ingredient: %s has a recipe of its own", product.Name)
+		}
+
+		ingredients = append(ingredients, core.RecipeIngredient{
+			ProductID:     product.ID,
+			ProductName:   product.Name,
+			Measure:       input.Measure,
+			Unit:          unit,
+			StockQuantity: product.StockQuantity,
+			BottleML:      product.BottleML,
+			PouredML:      product.PouredML,
+		})
+	}
+
+	return ingredients, nil
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// reconciliationTimeTolerance is how far apart a statement row and a ledger payment without the same
+// reference can be and still match on amount
+const reconciliationTimeTolerance = 10 * time.Minute
+
+// Ways a statement row was matched to a ledger payment
+const (
+	ReconciliationMatchReference  = "reference"
+	ReconciliationMatchAmountTime = "amount_time"
+)
+
+// statementColumns lists the header names M-Pesa statements (org portal and Kopo Kopo exports) use for
+// each column the reconciliation reads, lowercased without punctuation
+var statementColumns = map[string][]string{
+	"reference": {"receipt no", "receipt", "transaction id", "reference"},
+	"time":      {"completion time", "transaction time", "date"},
+	"amount":    {"paid in", "amount"},
+	"status":    {"transaction status", "status"},
+	"party":     {"other party info", "sender", "details"},
+}
+
+// statementTimeLayouts are the timestamp formats seen in M-Pesa statement exports
+var statementTimeLayouts = []string{
+	"02-01-2006 15:04:05",
+	"2006-01-02 15:04:05",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"2006-01-02T15:04:05",
+	"02-01-2006 15:04",
+}
+
+// StatementTransaction is a payment received, as listed on the M-Pesa statement
+type StatementTransaction struct {
+	Line      int       `json:"line"` // CSV line number
+	Reference string    `json:"reference"`
+	Time      time.Time `json:"time"`
+	Amount    float64   `json:"amount"`
+	Party     string    `json:"party,omitempty"` // Payer phone and name as the statement shows them
+}
+
+// ReconciliationMatch pairs a statement row with the ledger payment it was matched to
+type ReconciliationMatch struct {
+	Statement        StatementTransaction `json:"statement"`
+	Payment          *core.Payment        `json:"payment"`
+	MatchedBy        string               `json:"matched_by"`        // reference or amount_time
+	AmountDifference float64              `json:"amount_difference"` // Statement amount less ledger amount; non-zero only for reference matches
+}
+
+// ReconciliationSkip is a statement row that isn't a completed payment received, or couldn't be read
+type ReconciliationSkip struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ReconciliationSummary holds the counts and totals for the top of the reconciliation screen
+type ReconciliationSummary struct {
+	StatementCount          int     `json:"statement_count"`
+	StatementTotal          float64 `json:"statement_total"`
+	MatchedCount            int     `json:"matched_count"`
+	MatchedTotal            float64 `json:"matched_total"`
+	MismatchedAmounts       int     `json:"mismatched_amounts"` // Matched by reference with a different amount
+	MissingInSystemCount    int     `json:"missing_in_system_count"`
+	MissingInSystemTotal    float64 `json:"missing_in_system_total"`
+	MissingInStatementCount int     `json:"missing_in_statement_count"`
+	MissingInStatementTotal float64 `json:"missing_in_statement_total"`
+	SkippedCount            int     `json:"skipped_count"`
+}
+
+// ReconciliationReport compares an M-Pesa statement with the payments ledger. Payments are compared for
+// the statement's whole days (From to To), in the bar's time zone.
+type ReconciliationReport struct {
+	From               time.Time              `json:"from"`
+	To                 time.Time              `json:"to"`
+	Summary            ReconciliationSummary  `json:"summary"`
+	Matched            []ReconciliationMatch  `json:"matched"`
+	MissingInSystem    []StatementTransaction `json:"missing_in_system"`    // On the statement, not in the ledger
+	MissingInStatement []*core.Payment        `json:"missing_in_statement"` // In the ledger, not on the statement
+	Skipped            []ReconciliationSkip   `json:"skipped"`
+}
+
+// ReconcileStatement matches an M-Pesa till statement CSV against the payments ledger: by receipt number
+// first, then by amount within ten minutes. Nothing is written.
+func (s *DashboardService) ReconcileStatement(ctx context.Context, data io.Reader) (*ReconciliationReport, error) {
+	if s.paymentRepo == nil {
+		return nil, fmt.Errorf("payments ledger not configured")
+	}
+
+	loc := reportLocation()
+	transactions, skipped, err := parseStatementCSV(data, loc)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("invalid statement: no completed payments received")
+	}
+
+	first, last := transactions[0].Time, transactions[0].Time
+	for _, transaction := range transactions {
+		if transaction.Time.Before(first) {
+			first = transaction.Time
+		}
+		if transaction.Time.After(last) {
+			last = transaction.Time
+		}
+	}
+	from := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	payments, err := s.paymentRepo.ListBetween(ctx, from.Add(-reconciliationTimeTolerance).UTC(), to.Add(reconciliationTimeTolerance).UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	report := reconcile(transactions, payments, from, to)
+	report.Skipped = skipped
+	report.Summary.SkippedCount = len(skipped)
+	return report, nil
+}
+
+// reconcile matches transactions to payments. Payments just outside [from, to) can match a row near
+// midnight but aren't reported missing, since the statement doesn't cover them.
+func reconcile(transactions []StatementTransaction, payments []*core.Payment, from time.Time, to time.Time) *ReconciliationReport {
+	report := &ReconciliationReport{
+		From:               from,
+		To:                 to,
+		Matched:            []ReconciliationMatch{},
+		MissingInSystem:    []StatementTransaction{},
+		MissingInStatement: []*core.Payment{},
+	}
+
+	used := make(map[string]bool, len(payments))
+	byReference := make(map[string]*core.Payment, len(payments))
+	for _, payment := range payments {
+		if reference := strings.ToUpper(strings.TrimSpace(payment.Reference)); reference != "" {
+			byReference[reference] = payment
+		}
+	}
+
+	var unmatched []StatementTransaction
+	for _, transaction := range transactions {
+		payment, ok := byReference[strings.ToUpper(transaction.Reference)]
+		if !ok || used[payment.ID] {
+			unmatched = append(unmatched, transaction)
+			continue
+		}
+		used[payment.ID] = true
+		report.Matched = append(report.Matched, ReconciliationMatch{
+			Statement:        transaction,
+			Payment:          payment,
+			MatchedBy:        ReconciliationMatchReference,
+			AmountDifference: roundCents(transaction.Amount - payment.Amount),
+		})
+	}
+
+	for _, transaction := range unmatched {
+		var closest *core.Payment
+		for _, payment := range payments {
+			if used[payment.ID] || math.Abs(payment.Amount-transaction.Amount) >= 0.005 {
+				continue
+			}
+			gap := payment.CreatedAt.Sub(transaction.Time).Abs()
+			if gap > reconciliationTimeTolerance {
+				continue
+			}
+			if closest == nil || gap < closest.CreatedAt.Sub(transaction.Time).Abs() {
+				closest = payment
+			}
+		}
+		if closest == nil {
+			report.MissingInSystem = append(report.MissingInSystem, transaction)
+			continue
+		}
+		used[closest.ID] = true
+		report.Matched = append(report.Matched, ReconciliationMatch{
+			Statement: transaction,
+			Payment:   closest,
+			MatchedBy: ReconciliationMatchAmountTime,
+		})
+	}
+
+	for _, payment := range payments {
+		if !used[payment.ID] && !payment.CreatedAt.Before(from) && payment.CreatedAt.Before(to) {
+			report.MissingInStatement = append(report.MissingInStatement, payment)
+		}
+	}
+
+	sort.SliceStable(report.Matched, func(i, j int) bool {
+		return report.Matched[i].Statement.Time.Before(report.Matched[j].Statement.Time)
+	})
+	sort.SliceStable(report.MissingInSystem, func(i, j int) bool {
+		return report.MissingInSystem[i].Time.Before(report.MissingInSystem[j].Time)
+	})
+
+	summary := &report.Summary
+	for _, transaction := range transactions {
+		summary.StatementCount++
+		summary.StatementTotal += transaction.Amount
+	}
+	for _, match := range report.Matched {
+		summary.MatchedCount++
+		summary.MatchedTotal += match.Statement.Amount
+		if match.AmountDifference != 0 {
+			summary.MismatchedAmounts++
+		}
+	}
+	for _, transaction := range report.MissingInSystem {
+		summary.MissingInSystemCount++
+		summary.MissingInSystemTotal += transaction.Amount
+	}
+	for _, payment := range report.MissingInStatement {
+		summary.MissingInStatementCount++
+		summary.MissingInStatementTotal += payment.Amount
+	}
+	summary.StatementTotal = roundCents(summary.StatementTotal)
+	summary.MatchedTotal = roundCents(summary.MatchedTotal)
+	summary.MissingInSystemTotal = roundCents(summary.MissingInSystemTotal)
+	summary.MissingInStatementTotal = roundCents(summary.MissingInStatementTotal)
+	return report
+}
+
+// parseStatementCSV finds the header row (portal exports start with a few lines about the account) and
+// reads the completed payments received. Withdrawals, charges and failed transactions are skipped.
+func parseStatementCSV(data io.Reader, loc *time.Location) ([]StatementTransaction, []ReconciliationSkip, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	var columns map[string]int
+	for columns == nil {
+		header, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("invalid statement: no header row with a receipt number column")
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid statement: %w", err)
+		}
+		columns = statementHeader(header)
+	}
+	for _, required := range []string{"reference", "time", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("invalid statement: missing %s column", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	transactions := []StatementTransaction{}
+	skipped := []ReconciliationSkip{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid statement: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		reference := field(record, "reference")
+		rawAmount := strings.ReplaceAll(field(record, "amount"), ",", "")
+		if reference == "" && rawAmount == "" {
+			continue // blank or footer line
+		}
+		if status := strings.ToLower(field(record, "status")); status != "" && status != "completed" && status != "success" {
+			skipped = append(skipped, ReconciliationSkip{Line: line, Reason: fmt.Sprintf("transaction status %s", field(record, "status"))})
+			continue
+		}
+		amount, err := strconv.ParseFloat(rawAmount, 64)
+		if rawAmount == "" || (err == nil && amount <= 0) {
+			skipped = append(skipped, ReconciliationSkip{Line: line, Reason: "not a payment received"})
+			continue
+		}
+		if err != nil {
+			skipped = append(skipped, ReconciliationSkip{Line: line, Reason: fmt.Sprintf("invalid amount %q", field(record, "amount"))})
+			continue
+		}
+		at, ok := parseStatementTime(field(record, "time"), loc)
+		if !ok {
+			skipped = append(skipped, ReconciliationSkip{Line: line, Reason: fmt.Sprintf("invalid time %q", field(record, "time"))})
+			continue
+		}
+		if reference == "" {
+			skipped = append(skipped, ReconciliationSkip{Line: line, Reason: "receipt number is missing"})
+			continue
+		}
+
+		transactions = append(transactions, StatementTransaction{
+			Line:      line,
+			Reference: strings.ToUpper(reference),
+			Time:      at,
+			Amount:    roundCents(amount),
+			Party:     field(record, "party"),
+		})
+	}
+	return transactions, skipped, nil
+}
+
+// statementHeader maps the columns of record to statementColumns keys; nil unless it has a reference column
+func statementHeader(record []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range record {
+		// Excel prepends a byte order mark to UTF-8 CSVs
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.NewReplacer(".", "", "_", " ").Replace(name)
+		for key, aliases := range statementColumns {
+			if _, ok := columns[key]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[key] = i
+					break
+				}
+			}
+		}
+	}
+	if _, ok := columns["reference"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+func parseStatementTime(value string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range statementTimeLayouts {
+		if at, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+const (
+	reservationPollInterval = time.Minute
+	reservationBatchSize    = 50
+	// reservationDepositWindow is how long a booking waits for its deposit before it's cancelled
+	reservationDepositWindow = 30 * time.Minute
+	// reservationLeadTime is how far ahead a booking has to be made
+	reservationLeadTime = time.Hour
+)
+
+// ReservationPolicy holds the deposits and limits for bookings made through the bot
+type ReservationPolicy struct {
+	TableDeposit float64 // KES charged by STK push to hold a table; zero books straight away
+	VIPDeposit   float64 // KES charged to hold a VIP booth; zero books straight away
+	MaxPartySize int
+	MaxDaysAhead int // How many days ahead a booking can be made
+	ReminderHour int // Bar-local hour the on-the-day reminder goes out
+}
+
+// ReservationService books tables and VIP booths for customers, takes deposits through the STK push
+// flow and sends an on-the-day reminder. Bookings waiting too long for their deposit are cancelled.
+type ReservationService struct {
+	reservations core.ReservationRepository
+	users        core.UserRepository
+	whatsapp     core.WhatsAppGateway
+	payment      core.PaymentGateway
+	policy       ReservationPolicy
+	i18n         *i18n.Bundle
+	clock        core.Clock
+}
+
+// NewReservationService creates the reservation service, filling in limits the policy leaves unset
+func NewReservationService(reservations core.ReservationRepository, users core.UserRepository, whatsapp core.WhatsAppGateway, payment core.PaymentGateway, policy ReservationPolicy) *ReservationService {
+	if policy.MaxPartySize <= 0 {
+		policy.MaxPartySize = 20
+	}
+	if policy.MaxDaysAhead <= 0 {
+		policy.MaxDaysAhead = 30
+	}
+	if policy.ReminderHour < 0 || policy.ReminderHour > 23 {
+		policy.ReminderHour = 12
+	}
+
+	return &ReservationService{
+		reservations: reservations,
+		users:        users,
+		whatsapp:     whatsapp,
+		payment:      payment,
+		policy:       policy,
+		i18n:         i18n.Default(),
+		clock:        core.SystemClock{},
+	}
+}
+
+// Policy returns the deposits and limits bookings are made with
+func (s *ReservationService) Policy() ReservationPolicy {
+	return s.policy
+}
+
+// Deposit is the amount charged to hold a booking of kind
+func (s *ReservationService) Deposit(kind string) float64 {
+	if kind == core.ReservationKindVIP {
+		return s.policy.VIPDeposit
+	}
+	return s.policy.TableDeposit
+}
+
+// Book creates a reservation for the customer messaging from phone. Without a deposit it's confirmed
+// straight away; otherwise it waits as PENDING_DEPOSIT while an STK push for the deposit goes out.
+func (s *ReservationService) Book(ctx context.Context, phone string, kind string, at time.Time, partySize int) (*core.Reservation, error) {
+	if kind != core.ReservationKindTable && kind != core.ReservationKindVIP {
+		return nil, fmt.Errorf("invalid reservation kind: use TABLE or VIP")
+	}
+	if partySize < 1 || partySize > s.policy.MaxPartySize {
+		return nil, fmt.Errorf("invalid party size: must be between 1 and %d", s.policy.MaxPartySize)
+	}
+	if at.Sub(s.clock.Now()) < reservationLeadTime {
+		return nil, fmt.Errorf("invalid reservation time: must be at least an hour ahead")
+	}
+
+	user, err := s.users.GetOrCreateByPhone(ctx, phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	reservation := &core.Reservation{
+		UserID:        user.ID,
+		CustomerPhone: phone,
+		Kind:          kind,
+		PartySize:     partySize,
+		ReservedFor:   at,
+		Status:        core.ReservationConfirmed,
+		DepositAmount: s.Deposit(kind),
+	}
+	if reservation.DepositAmount > 0 {
+		reservation.Status = core.ReservationPendingDeposit
+	}
+	if err := s.reservations.Create(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	if reservation.Status == core.ReservationPendingDeposit {
+		// The reservation ID rides in the STK push metadata, so the payment webhook finds it
+		if err := s.payment.InitiateSTKPush(ctx, reservation.ID, phone, reservation.DepositAmount); err != nil {
+			if cancelErr := s.reservations.UpdateStatus(ctx, reservation.ID, core.ReservationCancelled); cancelErr != nil {
+				log.Printf("Failed to cancel reservation %s after its deposit push failed: %v", reservation.ID, cancelErr)
+			}
+			return nil, fmt.Errorf("failed to request reservation deposit: %w", err)
+		}
+	}
+	return reservation, nil
+}
+
+// ApplyReservationPayment applies a payment webhook for a reservation deposit. It returns false when
+// reservationID isn't a reservation, so the webhook is matched to an order instead.
+func (s *ReservationService) ApplyReservationPayment(ctx context.Context, reservationID string, reference string, success bool) (bool, error) {
+	reservation, err := s.reservations.GetByID(ctx, reservationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+
+	lang := s.customerLanguage(ctx, reservation.CustomerPhone)
+	if !success {
+		if reservation.Status != core.ReservationPendingDeposit {
+			return true, nil
+		}
+		if err := s.reservations.UpdateStatus(ctx, reservation.ID, core.ReservationCancelled); err != nil {
+			return true, err
+		}
+		return true, s.whatsapp.SendText(ctx, reservation.CustomerPhone, s.i18n.T(lang, "reservation.deposit_failed"))
+	}
+
+	confirmed, err := s.reservations.ConfirmDeposit(ctx, reservation.ID, reference)
+	if err != nil || !confirmed {
+		return true, err
+	}
+	message := s.i18n.T(lang, "reservation.deposit_paid", reservation.DepositAmount, s.describe(lang, reservation))
+	return true, s.whatsapp.SendText(ctx, reservation.CustomerPhone, message)
+}
+
+// Run sends on-the-day reminders and cancels bookings whose deposit never came, every minute until ctx
+// is cancelled. Safe to run on every replica.
+func (s *ReservationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(reservationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.cancelUnpaid(ctx)
+			s.sendReminders(ctx)
+		}
+	}
+}
+
+// sendReminders reminds customers booked for later today, once the reminder hour has passed
+func (s *ReservationService) sendReminders(ctx context.Context) {
+	loc := reportLocation()
+	local := s.clock.Now().In(loc)
+	if local.Hour() < s.policy.ReminderHour {
+		return
+	}
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	reservations, err := s.reservations.ClaimReminders(ctx, dayStart.UTC(), dayStart.AddDate(0, 0, 1).UTC(), reservationBatchSize)
+	if err != nil {
+		log.Printf("Error claiming reservation reminders: %v", err)
+		return
+	}
+	for _, reservation := range reservations {
+		lang := s.customerLanguage(ctx, reservation.CustomerPhone)
+		message := s.i18n.T(lang, "reservation.reminder", s.describe(lang, reservation))
+		if err := s.whatsapp.SendText(ctx, reservation.CustomerPhone, message); err != nil {
+			log.Printf("Error sending reservation reminder %s: %v", reservation.ID, err)
+		}
+	}
+}
+
+// cancelUnpaid cancels bookings still waiting for their deposit and tells the customers
+func (s *ReservationService) cancelUnpaid(ctx context.Context) {
+	reservations, err := s.reservations.CancelUnpaid(ctx, s.clock.Now().Add(-reservationDepositWindow))
+	if err != nil {
+		log.Printf("Error cancelling unpaid reservations: %v", err)
+		return
+	}
+	for _, reservation := range reservations {
+		lang := s.customerLanguage(ctx, reservation.CustomerPhone)
+		if err := s.whatsapp.SendText(ctx, reservation.CustomerPhone, s.i18n.T(lang, "reservation.deposit_expired")); err != nil {
+			log.Printf("Error telling %s their reservation %s expired: %v", reservation.CustomerPhone, reservation.ID, err)
+		}
+	}
+}
+
+// SetStatus moves a reservation to status from the dashboard; customers are told when theirs is cancelled
+func (s *ReservationService) SetStatus(ctx context.Context, id string, status core.ReservationStatus) (*core.Reservation, error) {
+	switch status {
+	case core.ReservationConfirmed, core.ReservationSeated, core.ReservationNoShow, core.ReservationCancelled:
+	default:
+		return nil, fmt.Errorf("invalid status: use CONFIRMED, SEATED, NO_SHOW or CANCELLED")
+	}
+
+	reservation, err := s.reservations.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reservations.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+	previous := reservation.Status
+	reservation.Status = status
+	reservation.UpdatedAt = s.clock.Now()
+
+	if status == core.ReservationCancelled && previous != core.ReservationCancelled {
+		lang := s.customerLanguage(ctx, reservation.CustomerPhone)
+		if err := s.whatsapp.SendText(ctx, reservation.CustomerPhone, s.i18n.T(lang, "reservation.cancelled", s.describe(lang, reservation))); err != nil {
+			log.Printf("Failed to tell %s their reservation %s was cancelled: %v", reservation.CustomerPhone, reservation.ID, err)
+		}
+	}
+	return reservation, nil
+}
+
+// describe renders a booking for customer messages, e.g. "VIP booth for 6, Sat 4 Apr, 21:00"
+func (s *ReservationService) describe(lang string, reservation *core.Reservation) string {
+	what := s.i18n.T(lang, "reservation.kind_table")
+	if reservation.Kind == core.ReservationKindVIP {
+		what = s.i18n.T(lang, "reservation.kind_vip")
+	}
+	return s.i18n.T(lang, "reservation.summary", what, reservation.PartySize, FormatScheduledTime(reservation.ReservedFor))
+}
+
+func (s *ReservationService) customerLanguage(ctx context.Context, phone string) string {
+	if user, err := s.users.GetByPhone(ctx, phone); err == nil {
+		return i18n.Resolve(user.Language)
+	}
+	return i18n.DefaultLanguage
+}
+
+// SetReservationService wires table and VIP booth reservations
+func (s *DashboardService) SetReservationService(reservations *ReservationService) {
+	s.reservations = reservations
+}
+
+// ReservationQuery holds the raw reservation filters accepted by the admin API
+type ReservationQuery struct {
+	From   string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	To     string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	Status string
+	Limit  int
+}
+
+// ReservationDay is one day of the reservations calendar
+type ReservationDay struct {
+	Date         string              `json:"date"`   // YYYY-MM-DD, bar-local
+	Guests       int                 `json:"guests"` // Party sizes of the day's bookings that aren't cancelled
+	Reservations []*core.Reservation `json:"reservations"`
+}
+
+// ListReservations retrieves reservations for the query's date range, earliest first
+func (s *DashboardService) ListReservations(ctx context.Context, query ReservationQuery) ([]*core.Reservation, error) {
+	if s.reservations == nil {
+		return nil, fmt.Errorf("reservations not configured")
+	}
+
+	filter := core.ReservationFilter{
+		Status: core.ReservationStatus(strings.ToUpper(strings.TrimSpace(query.Status))),
+		Limit:  query.Limit,
+	}
+	loc := reportLocation()
+	if from := strings.TrimSpace(query.From); from != "" {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+		}
+		filter.From = &start
+	}
+	if to := strings.TrimSpace(query.To); to != "" {
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	return s.reservations.reservations.List(ctx, filter)
+}
+
+// GetReservationCalendar groups the reservations from from to to (YYYY-MM-DD, inclusive; the next
+// 7 days by default) by bar-local day, with every day in the range present
+func (s *DashboardService) GetReservationCalendar(ctx context.Context, from string, to string) ([]*ReservationDay, error) {
+	loc := reportLocation()
+	today := s.clock.Now().In(loc).Format("2006-01-02")
+	if strings.TrimSpace(from) == "" {
+		from = today
+	}
+	start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(from), loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+	}
+	if strings.TrimSpace(to) == "" {
+		to = start.AddDate(0, 0, 6).Format("2006-01-02")
+	}
+	end, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(to), loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+	}
+	if end.Before(start) || end.Sub(start) > 62*24*time.Hour {
+		return nil, fmt.Errorf("invalid date range: to must be after from and at most 62 days later")
+	}
+
+	reservations, err := s.ListReservations(ctx, ReservationQuery{From: start.Format("2006-01-02"), To: end.Format("2006-01-02"), Limit: 500})
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]*ReservationDay, 0)
+	byDate := make(map[string]*ReservationDay)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		entry := &ReservationDay{Date: day.Format("2006-01-02"), Reservations: []*core.Reservation{}}
+		days = append(days, entry)
+		byDate[entry.Date] = entry
+	}
+	for _, reservation := range reservations {
+		entry, ok := byDate[reservation.ReservedFor.In(loc).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		entry.Reservations = append(entry.Reservations, reservation)
+		if reservation.Status != core.ReservationCancelled {
+			entry.Guests += reservation.PartySize
+		}
+	}
+	return days, nil
+}
+
+// UpdateReservationStatus marks a reservation confirmed, seated, a no-show or cancelled
+func (s *DashboardService) UpdateReservationStatus(ctx context.Context, id string, status string) (*core.Reservation, error) {
+	if s.reservations == nil {
+		return nil, fmt.Errorf("reservations not configured")
+	}
+	return s.reservations.SetStatus(ctx, id, core.ReservationStatus(strings.ToUpper(strings.TrimSpace(status))))
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+const (
+	restockPollInterval = time.Minute
+	restockBatchSize    = 50
+)
+
+// RestockNotifier notices sold-out products coming back into stock, however the stock was replenished
+// (dashboard, stocktake, purchase order or import). Each one is announced to the dashboard with a
+// product_restocked event, and customers who asked to be notified get a message with an order button.
+type RestockNotifier struct {
+	products core.ProductRepository
+	alerts   core.RestockAlertRepository
+	users    core.UserRepository
+	whatsapp core.WhatsAppGateway
+	settings *SettingsService // Optional: the menu.restock_alerts switch
+	eventBus *events.EventBus
+	i18n     *i18n.Bundle
+}
+
+// NewRestockNotifier creates the restock job; settings may be nil
+func NewRestockNotifier(products core.ProductRepository, alerts core.RestockAlertRepository, users core.UserRepository, whatsapp core.WhatsAppGateway, settings *SettingsService, eventBus *events.EventBus) *RestockNotifier {
+	return &RestockNotifier{
+		products: products,
+		alerts:   alerts,
+		users:    users,
+		whatsapp: whatsapp,
+		settings: settings,
+		eventBus: eventBus,
+		i18n:     i18n.Default(),
+	}
+}
+
+// Run checks for restocked products every minute until ctx is cancelled. Safe to run on every replica.
+func (n *RestockNotifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(restockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.announceRestocked(ctx)
+		}
+	}
+}
+
+func (n *RestockNotifier) announceRestocked(ctx context.Context) {
+	products, err := n.products.ClaimRestocked(ctx, restockBatchSize)
+	if err != nil {
+		log.Printf("Error claiming restocked products: %v", err)
+		return
+	}
+
+	for _, product := range products {
+		n.eventBus.PublishProductRestocked(ctx, product.ID, product.Name, product.StockQuantity)
+		if n.settings != nil && !n.settings.Bool(ctx, SettingRestockAlerts) {
+			continue
+		}
+		if err := n.notifySubscribers(ctx, product); err != nil {
+			log.Printf("Error sending back-in-stock messages for %s: %v", product.Name, err)
+		}
+	}
+}
+
+// notifySubscribers messages everyone waiting on product; alerts are removed once claimed, so a failed
+// send isn't retried
+func (n *RestockNotifier) notifySubscribers(ctx context.Context, product *core.Product) error {
+	phones, err := n.alerts.Claim(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, phone := range phones {
+		lang := i18n.DefaultLanguage
+		if user, err := n.users.GetByPhone(ctx, phone); err == nil {
+			lang = i18n.Resolve(user.Language)
+		}
+		buttons := []core.Button{{ID: restockOrderPrefix + product.ID, Title: n.i18n.T(lang, "button.restock_order")}}
+		if err := n.whatsapp.SendMenuButtons(ctx, phone, n.i18n.T(lang, "restock.back", product.Name), buttons); err != nil {
+			log.Printf("Error telling %s that %s is back in stock: %v", phone, product.Name, err)
+		}
+	}
+	return nil
+}
+
+// SetProductSoldOutVisibility sets whether a product leaves the bot's menu while sold out; nil follows
+// the menu.hide_out_of_stock setting
+func (s *DashboardService) SetProductSoldOutVisibility(ctx context.Context, productID string, hide *bool) (*core.Product, error) {
+	if productID == "" {
+		return nil, fmt.Errorf("product ID is required")
+	}
+	if err := s.productRepo.SetHideWhenSoldOut(ctx, productID, hide); err != nil {
+		return nil, err
+	}
+	return s.productRepo.GetByID(ctx, productID)
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/i18n"
+)
+
+// Rider WhatsApp buttons; the order ID follows the prefix
+const (
+	RiderAcceptPrefix    = "rideraccept_"
+	RiderDeliveredPrefix = "riderdone_"
+)
+
+// RiderUpdate holds optional fields for updating a rider
+type RiderUpdate struct {
+	Name        *string
+	PhoneNumber *string
+	IsAvailable *bool
+	IsActive    *bool
+}
+
+// SetRiderRepository wires the riders roster used by the rider management endpoints
+func (s *DashboardService) SetRiderRepository(riderRepo core.RiderRepository) {
+	s.riderRepo = riderRepo
+}
+
+// ListRiders retrieves the full riders roster
+func (s *DashboardService) ListRiders(ctx context.Context) ([]*core.Rider, error) {
+	if s.riderRepo == nil {
+		return nil, fmt.Errorf("riders roster not configured")
+	}
+	return s.riderRepo.GetAll(ctx)
+}
+
+// CreateRider adds a rider to the roster
+func (s *DashboardService) CreateRider(ctx context.Context, name string, phone string, available bool) (*core.Rider, error) {
+	if s.riderRepo == nil {
+		return nil, fmt.Errorf("riders roster not configured")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	normalizedPhone, err := normalizeStaffPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	rider := &core.Rider{
+		ID:          s.ids.NewID(),
+		Name:        name,
+		PhoneNumber: normalizedPhone,
+		IsAvailable: available,
+		IsActive:    true,
+		CreatedAt:   s.clock.Now(),
+	}
+
+	if err := s.riderRepo.Create(ctx, rider); err != nil {
+		return nil, err
+	}
+
+	return rider, nil
+}
+
+// UpdateRider applies a partial update to a rider (name, phone, availability, active)
+func (s *DashboardService) UpdateRider(ctx context.Context, id string, update RiderUpdate) (*core.Rider, error) {
+	if s.riderRepo == nil {
+		return nil, fmt.Errorf("riders roster not configured")
+	}
+
+	rider, err := s.riderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		rider.Name = name
+	}
+
+	if update.PhoneNumber != nil {
+		normalizedPhone, err := normalizeStaffPhone(*update.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		rider.PhoneNumber = normalizedPhone
+	}
+
+	if update.IsAvailable != nil {
+		rider.IsAvailable = *update.IsAvailable
+	}
+
+	if update.IsActive != nil {
+		rider.IsActive = *update.IsActive
+		// Deactivated riders aren't offered deliveries.
+		if !rider.IsActive {
+			rider.IsAvailable = false
+		}
+	}
+
+	if err := s.riderRepo.Update(ctx, rider); err != nil {
+		return nil, err
+	}
+
+	return rider, nil
+}
+
+// DeactivateRider stops offering deliveries to a rider while keeping their delivery history intact
+func (s *DashboardService) DeactivateRider(ctx context.Context, id string) error {
+	inactive := false
+	_, err := s.UpdateRider(ctx, id, RiderUpdate{IsActive: &inactive})
+	return err
+}
+
+// RiderNotifier offers dispatched delivery orders to available riders on WhatsApp.
+// The first rider to tap "Accept" is assigned, and taps "Delivered" once the customer has the order.
+type RiderNotifier struct {
+	riderRepo core.RiderRepository
+	orderRepo core.OrderRepository
+	whatsapp  core.WhatsAppGateway
+	eventBus  *events.EventBus
+}
+
+// NewRiderNotifier creates a notifier for the riders roster
+func NewRiderNotifier(riderRepo core.RiderRepository, orderRepo core.OrderRepository, whatsapp core.WhatsAppGateway, eventBus *events.EventBus) *RiderNotifier {
+	return &RiderNotifier{
+		riderRepo: riderRepo,
+		orderRepo: orderRepo,
+		whatsapp:  whatsapp,
+		eventBus:  eventBus,
+	}
+}
+
+// NotifyDispatched offers an OUT_FOR_DELIVERY order to every available rider with an Accept button
+func (n *RiderNotifier) NotifyDispatched(ctx context.Context, order *core.Order) error {
+	riders, err := n.riderRepo.GetAvailable(ctx)
+	if err != nil {
+		return err
+	}
+	if len(riders) == 0 {
+		return fmt.Errorf("no riders available for order %s", order.ID)
+	}
+
+	message := "🛵 *Delivery Order*\n\n" + deliveryDetails(order) + "\n\nTap Accept to take it."
+	buttons := []core.Button{
+		{
+			ID:    RiderAcceptPrefix + order.ID,
+			Title: "Accept",
+		},
+	}
+
+	delivered := 0
+	var lastErr error
+	for _, rider := range riders {
+		if err := n.whatsapp.SendMenuButtons(ctx, rider.PhoneNumber, message, buttons); err != nil {
+			lastErr = err
+			log.Printf("Failed to offer order %s to rider %s: %v", order.ID, rider.Name, err)
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 && lastErr != nil {
+		return fmt.Errorf("failed to notify any available rider: %w", lastErr)
+	}
+	return nil
+}
+
+// AcceptDelivery assigns the first rider to tap "Accept", then tells the customer and the other riders
+func (n *RiderNotifier) AcceptDelivery(ctx context.Context, riderPhone string, orderID string) error {
+	rider, err := n.riderRepo.GetByPhone(ctx, riderPhone)
+	if err != nil || !rider.IsActive {
+		return n.whatsapp.SendText(ctx, riderPhone, "❌ Your number is not on the riders roster.")
+	}
+
+	order, err := n.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return n.whatsapp.SendText(ctx, riderPhone, "❌ Order not found")
+	}
+
+	assigned, err := n.orderRepo.AssignRider(ctx, orderID, rider.ID)
+	if err != nil {
+		return fmt.Errorf("failed to assign rider: %w", err)
+	}
+
+	if !assigned {
+		if order.RiderID == rider.ID {
+			return n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("ℹ️ You already have order #%s.", order.PickupCode))
+		}
+		if order.RiderID != "" {
+			takenBy := "another rider"
+			if owner, err := n.riderRepo.GetByID(ctx, order.RiderID); err == nil {
+				takenBy = owner.Name
+			}
+			return n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("ℹ️ Order #%s was already taken by %s.", order.PickupCode, takenBy))
+		}
+		return n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("ℹ️ Order #%s is not out for delivery (status %s).", order.PickupCode, order.Status))
+	}
+
+	// Reload for the assignment time in the SSE payload
+	if reloaded, err := n.orderRepo.GetByID(ctx, orderID); err == nil {
+		order = reloaded
+	} else {
+		order.RiderID = rider.ID
+	}
+
+	buttons := []core.Button{
+		{
+			ID:    RiderDeliveredPrefix + order.ID,
+			Title: "Delivered",
+		},
+	}
+	message := fmt.Sprintf("👍 You're delivering order #%s.\n\n%s\n\nTap Delivered once the customer has it.", order.PickupCode, deliveryDetails(order))
+	if err := n.whatsapp.SendMenuButtons(ctx, riderPhone, message, buttons); err != nil {
+		log.Printf("Failed to confirm delivery assignment to %s: %v", rider.Name, err)
+	}
+
+	customerMessage := i18n.Default().T(i18n.DefaultLanguage, "delivery.rider_assigned", rider.Name, rider.PhoneNumber, order.PickupCode)
+	if err := n.whatsapp.SendText(ctx, order.CustomerPhone, customerMessage); err != nil {
+		log.Printf("Failed to tell the customer about rider for order %s: %v", order.ID, err)
+	}
+
+	if others, err := n.riderRepo.GetAvailable(ctx); err == nil {
+		for _, other := range others {
+			if other.ID == rider.ID {
+				continue
+			}
+			if err := n.whatsapp.SendText(ctx, other.PhoneNumber, fmt.Sprintf("✋ Order #%s taken by %s.", order.PickupCode, rider.Name)); err != nil {
+				log.Printf("Failed to send taken notice to %s: %v", other.Name, err)
+			}
+		}
+	}
+
+	if n.eventBus != nil {
+		n.eventBus.PublishRiderAssigned(ctx, order)
+	}
+	return nil
+}
+
+// ConfirmDelivered marks an order DELIVERED when its assigned rider taps "Delivered"
+func (n *RiderNotifier) ConfirmDelivered(ctx context.Context, riderPhone string, orderID string) error {
+	rider, err := n.riderRepo.GetByPhone(ctx, riderPhone)
+	if err != nil || !rider.IsActive {
+		return n.whatsapp.SendText(ctx, riderPhone, "❌ Your number is not on the riders roster.")
+	}
+
+	order, err := n.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return n.whatsapp.SendText(ctx, riderPhone, "❌ Order not found")
+	}
+	if order.RiderID != rider.ID {
+		return n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("❌ Order #%s is not assigned to you.", order.PickupCode))
+	}
+	if order.Status == core.OrderStatusDelivered {
+		return n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("ℹ️ Order #%s is already marked delivered.", order.PickupCode))
+	}
+	if order.Status != core.OrderStatusOutForDelivery {
+		return n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("ℹ️ Order #%s is not out for delivery (status %s).", order.PickupCode, order.Status))
+	}
+
+	note := fmt.Sprintf("delivered by rider %s", rider.Name)
+	if err := n.orderRepo.UpdateStatusWithNote(ctx, orderID, core.OrderStatusDelivered, core.OrderActorWebhook, note); err != nil {
+		n.whatsapp.SendText(ctx, riderPhone, "❌ Failed to update order status")
+		return fmt.Errorf("failed to mark order delivered: %w", err)
+	}
+
+	if err := n.whatsapp.SendText(ctx, riderPhone, fmt.Sprintf("✅ Order #%s marked delivered. Thanks!", order.PickupCode)); err != nil {
+		log.Printf("Failed to confirm delivery to %s: %v", rider.Name, err)
+	}
+
+	return announceDelivered(ctx, n.whatsapp, n.eventBus, order)
+}
+
+// deliveryDetails is the pickup code, address, map link, customer and item count a rider needs
+func deliveryDetails(order *core.Order) string {
+	details := fmt.Sprintf("*Order #%s*\n*Deliver to:* %s\n", order.PickupCode, order.DeliveryAddress)
+	if order.DeliveryLocation != nil {
+		details += DeliveryMapsLink(*order.DeliveryLocation) + "\n"
+	}
+	details += fmt.Sprintf("*Customer:* %s\n*Items:* %d, paid KES %.0f", order.CustomerPhone, orderItemCount(order), order.TotalAmount)
+	return details
+}
+
+func orderItemCount(order *core.Order) int {
+	count := 0
+	for _, item := range order.Items {
+		count += item.Quantity
+	}
+	return count
+}
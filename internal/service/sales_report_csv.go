@@ -0,0 +1,98 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+var salesReportCSVHeader = []string{
+	"order_created_at",
+	"pickup_code",
+	"status",
+	"customer_phone",
+	"payment_method",
+	"payment_reference",
+	"order_total",
+	"order_vat",
+	"vat_rate",
+	"product",
+	"quantity",
+	"unit_price",
+	"line_total",
+	"line_vat",
+	"order_tip",
+	"order_delivery_fee",
+	"age_confirmed_at",
+}
+
+// renderSalesReportCSV renders one row per order item (order columns repeated) so the
+// export opens cleanly in spreadsheets. Orders without items get a single row.
+// order_tip, order_delivery_fee and age_confirmed_at (the customer's 18+ confirmation, for compliance)
+// come last so spreadsheets built on the earlier column layout keep working.
+func renderSalesReportCSV(report *core.SalesReport, loc *time.Location) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write(salesReportCSVHeader); err != nil {
+		return nil, fmt.Errorf("failed to render CSV: %w", err)
+	}
+
+	for _, order := range report.Orders {
+		orderColumns := []string{
+			order.CreatedAt.In(loc).Format("2006-01-02 15:04:05"),
+			order.PickupCode,
+			string(order.Status),
+			order.CustomerPhone,
+			order.PaymentMethod,
+			order.PaymentRef,
+			formatCSVAmount(order.TotalAmount),
+			formatCSVAmount(order.TaxAmount),
+			strconv.FormatFloat(order.TaxRate, 'f', -1, 64),
+		}
+		tip := formatCSVAmount(order.TipAmount)
+		deliveryFee := formatCSVAmount(order.DeliveryFee)
+		ageConfirmedAt := ""
+		if order.AgeConfirmedAt != nil {
+			ageConfirmedAt = order.AgeConfirmedAt.In(loc).Format("2006-01-02 15:04:05")
+		}
+
+		if len(order.Items) == 0 {
+			if err := writer.Write(append(orderColumns, "", "", "", "", "", tip, deliveryFee, ageConfirmedAt)); err != nil {
+				return nil, fmt.Errorf("failed to render CSV: %w", err)
+			}
+			continue
+		}
+
+		for _, item := range order.Items {
+			row := append(append([]string{}, orderColumns...),
+				item.ProductName,
+				strconv.Itoa(item.Quantity),
+				formatCSVAmount(item.PriceAtTime),
+				formatCSVAmount(item.PriceAtTime*float64(item.Quantity)),
+				formatCSVAmount(item.TaxAmount),
+				tip,
+				deliveryFee,
+				ageConfirmedAt,
+			)
+			if err := writer.Write(row); err != nil {
+				return nil, fmt.Errorf("failed to render CSV: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to render CSV: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func formatCSVAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -4,62 +4,79 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
-	_ "time/tzdata"
 
 	"github.com/dumu-tech/destination-cocktails/internal/core"
 	"github.com/jung-kurt/gofpdf"
 )
 
-const (
-	reportTimezoneName      = "Africa/Nairobi"
-	businessDayStartHourEAT = 7
-)
-
 var settledSalesStatuses = []core.OrderStatus{
 	core.OrderStatusPaid,
+	core.OrderStatusScheduled,
 	core.OrderStatusReady,
 	core.OrderStatusCompleted,
+	core.OrderStatusOutForDelivery,
+	core.OrderStatusDelivered,
 }
 
-// GenerateDailySalesReportPDF generates a PDF report for one operational business day.
+// Sales report export formats
+const (
+	ReportFormatPDF  = "pdf"
+	ReportFormatCSV  = "csv"
+	ReportFormatXLSX = "xlsx"
+)
+
+// GenerateDailySalesReport generates a report for one operational business day in the requested format (pdf, csv or xlsx).
 // Business day window: 07:00 EAT to next day 06:59:59 EAT.
-func (s *DashboardService) GenerateDailySalesReportPDF(ctx context.Context, businessDate string) ([]byte, string, error) {
+func (s *DashboardService) GenerateDailySalesReport(ctx context.Context, businessDate string, format string) ([]byte, string, error) {
+	format, err := normalizeReportFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+
 	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
 
-	var err error
-	targetDate, err := resolveBusinessDate(businessDate, loc)
+	targetDate, err := resolveBusinessDate(businessDate, s.clock.Now().In(loc), loc, startHour)
 	if err != nil {
 		return nil, "", err
 	}
 
-	startLocal, endLocal := businessDayWindow(targetDate, loc)
+	startLocal, endLocal := businessDayWindow(targetDate, loc, startHour)
 
 	report, err := s.buildSalesReport(ctx, "Daily Sales Report", targetDate.Format("2006-01-02"), startLocal, endLocal, loc)
 	if err != nil {
 		return nil, "", err
 	}
 
-	pdfBytes, err := renderSalesReportPDF(report, loc)
+	data, err := renderSalesReport(report, loc, format)
 	if err != nil {
 		return nil, "", err
 	}
 
-	filename := fmt.Sprintf("daily-sales-%s.pdf", targetDate.Format("2006-01-02"))
-	return pdfBytes, filename, nil
+	filename := fmt.Sprintf("daily-sales-%s.%s", targetDate.Format("2006-01-02"), format)
+	return data, filename, nil
 }
 
-// GenerateLast30DaysSalesReportPDF generates a PDF report for the previous 30 completed operational days.
+// GenerateLast30DaysSalesReport generates a report for the previous 30 completed operational days (pdf, csv or xlsx).
 // Window always ends on yesterday business date (not today's in-progress business date).
-func (s *DashboardService) GenerateLast30DaysSalesReportPDF(ctx context.Context) ([]byte, string, error) {
+func (s *DashboardService) GenerateLast30DaysSalesReport(ctx context.Context, format string) ([]byte, string, error) {
+	format, err := normalizeReportFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+
 	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
 
-	nowLocal := time.Now().In(loc)
-	currentBusinessDate := currentBusinessDateInLocation(nowLocal, loc)
+	nowLocal := s.clock.Now().In(loc)
+	currentBusinessDate := currentBusinessDateInLocation(nowLocal, loc, startHour)
 	endBusinessDate := currentBusinessDate.AddDate(0, 0, -1)
 
-	endStartLocal, endWindowLocal := businessDayWindow(endBusinessDate, loc)
+	endStartLocal, endWindowLocal := businessDayWindow(endBusinessDate, loc, startHour)
 	startLocal := endStartLocal.AddDate(0, 0, -29)
 
 	dateLabel := fmt.Sprintf("%s to %s", startLocal.Format("2006-01-02"), endBusinessDate.Format("2006-01-02"))
@@ -68,23 +85,48 @@ func (s *DashboardService) GenerateLast30DaysSalesReportPDF(ctx context.Context)
 		return nil, "", err
 	}
 
-	pdfBytes, err := renderSalesReportPDF(report, loc)
+	data, err := renderSalesReport(report, loc, format)
 	if err != nil {
 		return nil, "", err
 	}
 
-	filename := fmt.Sprintf("sales-30-days-%s.pdf", endBusinessDate.Format("2006-01-02"))
-	return pdfBytes, filename, nil
+	filename := fmt.Sprintf("sales-30-days-%s.%s", endBusinessDate.Format("2006-01-02"), format)
+	return data, filename, nil
+}
+
+// ReportContentType returns the HTTP Content-Type for a report format
+func ReportContentType(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case ReportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ReportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/pdf"
+	}
 }
 
-func reportLocation() *time.Location {
-	loc, err := time.LoadLocation(reportTimezoneName)
-	if err == nil {
-		return loc
+func normalizeReportFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		return ReportFormatPDF, nil
+	case ReportFormatPDF, ReportFormatCSV, ReportFormatXLSX:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid report format, expected pdf, csv or xlsx")
 	}
+}
 
-	// Fallback for minimal container images missing IANA zone files.
-	return time.FixedZone("EAT", 3*60*60)
+func renderSalesReport(report *core.SalesReport, loc *time.Location, format string) ([]byte, error) {
+	switch format {
+	case ReportFormatCSV:
+		return renderSalesReportCSV(report, loc)
+	case ReportFormatXLSX:
+		return renderSalesReportXLSX(report, loc)
+	default:
+		return renderSalesReportPDF(report, loc)
+	}
 }
 
 func (s *DashboardService) buildSalesReport(
@@ -100,9 +142,14 @@ func (s *DashboardService) buildSalesReport(
 		return nil, fmt.Errorf("failed to fetch report orders: %w", err)
 	}
 
+	// Tips belong to staff and delivery fees cover the rider, so neither counts as sales
 	totalRevenue := 0.0
+	totalTax := 0.0
+	totalTips := 0.0
 	for _, order := range orders {
-		totalRevenue += order.TotalAmount
+		totalRevenue += productSales(order)
+		totalTax += order.TaxAmount
+		totalTips += order.TipAmount
 	}
 
 	avgOrderValue := 0.0
@@ -125,13 +172,19 @@ func (s *DashboardService) buildSalesReport(
 		Title:               title,
 		DateLabel:           dateLabel,
 		Timezone:            reportTimezoneName,
-		BusinessDayStart:    "07:00",
+		BusinessDayStart:    startLocal.Format("15:04"),
 		StartAt:             startLocal,
 		EndAt:               endLocal,
-		GeneratedAt:         time.Now().In(loc),
+		GeneratedAt:         s.clock.Now().In(loc),
 		TotalRevenue:        totalRevenue,
 		OrderCount:          orderCount,
 		AverageOrderValue:   avgOrderValue,
+		TotalTax:            totalTax,
+		NetSales:            totalRevenue - totalTax,
+		TaxSummary:          summarizeTax(orders),
+		PaymentMethods:      summarizePaymentMethods(orders),
+		TotalTips:           totalTips,
+		StaffTips:           s.summarizeStaffTips(ctx, orders),
 		SettledStatusFilter: statusFilter,
 		Orders:              domainOrders,
 	}
@@ -139,41 +192,109 @@ func (s *DashboardService) buildSalesReport(
 	return report, nil
 }
 
-func resolveBusinessDate(dateString string, loc *time.Location) (time.Time, error) {
-	if strings.TrimSpace(dateString) == "" {
-		nowLocal := time.Now().In(loc)
-		return currentBusinessDateInLocation(nowLocal, loc), nil
+// productSales is what an order took for drinks: its total less any tip and delivery fee
+func productSales(order *core.Order) float64 {
+	return order.TotalAmount - order.TipAmount - order.DeliveryFee
+}
+
+// summarizeTax groups orders by the VAT rate they were charged at, lowest rate first
+func summarizeTax(orders []*core.Order) []core.TaxLine {
+	byRate := make(map[float64]*core.TaxLine)
+	for _, order := range orders {
+		line, ok := byRate[order.TaxRate]
+		if !ok {
+			line = &core.TaxLine{Rate: order.TaxRate}
+			byRate[order.TaxRate] = line
+		}
+		line.OrderCount++
+		line.GrossSales += productSales(order)
+		line.TaxAmount += order.TaxAmount
+		line.TaxableAmount += productSales(order) - order.TaxAmount
 	}
 
-	parsed, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(dateString), loc)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
+	summary := make([]core.TaxLine, 0, len(byRate))
+	for _, line := range byRate {
+		summary = append(summary, *line)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Rate < summary[j].Rate
+	})
+	return summary
+}
+
+// summarizePaymentMethods splits sales by how they were paid (M-Pesa, cash or card at the bar), largest first
+func summarizePaymentMethods(orders []*core.Order) []core.PaymentMethodLine {
+	byMethod := make(map[string]*core.PaymentMethodLine)
+	for _, order := range orders {
+		method := order.PaymentMethod
+		if method == "" {
+			method = string(core.PaymentMethodMpesa)
+		}
+		line, ok := byMethod[method]
+		if !ok {
+			line = &core.PaymentMethodLine{Method: method}
+			byMethod[method] = line
+		}
+		line.OrderCount++
+		line.Amount += productSales(order)
 	}
 
-	return parsed, nil
+	summary := make([]core.PaymentMethodLine, 0, len(byMethod))
+	for _, line := range byMethod {
+		summary = append(summary, *line)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Amount != summary[j].Amount {
+			return summary[i].Amount > summary[j].Amount
+		}
+		return summary[i].Method < summary[j].Method
+	})
+	return summary
 }
 
-func currentBusinessDateInLocation(nowLocal time.Time, loc *time.Location) time.Time {
-	reference := nowLocal
-	if reference.Hour() < businessDayStartHourEAT {
-		reference = reference.AddDate(0, 0, -1)
+// summarizeStaffTips totals tips by the bartender who accepted each order, largest first.
+// Tipped orders nobody accepted are grouped as "Unassigned".
+func (s *DashboardService) summarizeStaffTips(ctx context.Context, orders []*core.Order) []core.StaffTips {
+	byStaff := make(map[string]*core.StaffTips)
+	for _, order := range orders {
+		if order.TipAmount <= 0 {
+			continue
+		}
+		line, ok := byStaff[order.AcceptedByStaffID]
+		if !ok {
+			line = &core.StaffTips{StaffID: order.AcceptedByStaffID, StaffName: s.reportStaffName(ctx, order.AcceptedByStaffID)}
+			byStaff[order.AcceptedByStaffID] = line
+		}
+		line.OrderCount++
+		line.Amount += order.TipAmount
 	}
 
-	return time.Date(reference.Year(), reference.Month(), reference.Day(), 0, 0, 0, 0, loc)
+	summary := make([]core.StaffTips, 0, len(byStaff))
+	for _, line := range byStaff {
+		summary = append(summary, *line)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Amount != summary[j].Amount {
+			return summary[i].Amount > summary[j].Amount
+		}
+		return summary[i].StaffName < summary[j].StaffName
+	})
+	return summary
 }
 
-func businessDayWindow(businessDate time.Time, loc *time.Location) (time.Time, time.Time) {
-	start := time.Date(
-		businessDate.Year(),
-		businessDate.Month(),
-		businessDate.Day(),
-		businessDayStartHourEAT,
-		0,
-		0,
-		0,
-		loc,
-	)
-	return start, start.Add(24 * time.Hour)
+// reportStaffName resolves a bar staff ID to a name, falling back to the ID if the lookup fails
+func (s *DashboardService) reportStaffName(ctx context.Context, staffID string) string {
+	if staffID == "" {
+		return "Unassigned"
+	}
+	if s.barStaffRepo == nil {
+		return staffID
+	}
+	staff, err := s.barStaffRepo.GetByID(ctx, staffID)
+	if err != nil || staff.Name == "" {
+		return staffID
+	}
+	return staff.Name
 }
 
 func renderSalesReportPDF(report *core.SalesReport, loc *time.Location) ([]byte, error) {
@@ -203,8 +324,18 @@ func renderSalesReportPDF(report *core.SalesReport, loc *time.Location) ([]byte,
 	pdf.CellFormat(95, 7, fmt.Sprintf("Total Sales: %s", formatKsh(report.TotalRevenue)), "1", 0, "L", false, 0, "")
 	pdf.CellFormat(95, 7, fmt.Sprintf("Orders: %d", report.OrderCount), "1", 1, "L", false, 0, "")
 	pdf.CellFormat(190, 7, fmt.Sprintf("Average Order Value: %s", formatKsh(report.AverageOrderValue)), "1", 1, "L", false, 0, "")
+	pdf.CellFormat(95, 7, fmt.Sprintf("Net Sales (excl. VAT): %s", formatKsh(report.NetSales)), "1", 0, "L", false, 0, "")
+	pdf.CellFormat(95, 7, fmt.Sprintf("VAT: %s", formatKsh(report.TotalTax)), "1", 1, "L", false, 0, "")
+	pdf.CellFormat(190, 7, fmt.Sprintf("Staff Tips (not included in sales): %s", formatKsh(report.TotalTips)), "1", 1, "L", false, 0, "")
 	pdf.Ln(3)
 
+	if report.Comparison != nil {
+		renderComparisonPDF(pdf, report)
+	}
+	renderTaxSummaryPDF(pdf, report)
+	renderPaymentMethodsPDF(pdf, report)
+	renderStaffTipsPDF(pdf, report)
+
 	pdf.SetFont("Arial", "B", 11)
 	pdf.CellFormat(0, 7, "Order-Level Detail", "", 1, "L", false, 0, "")
 
@@ -227,7 +358,7 @@ func renderSalesReportPDF(report *core.SalesReport, loc *time.Location) ([]byte,
 
 			pdf.SetFont("Arial", "", 10)
 			pdf.MultiCell(0, 5, fmt.Sprintf("Phone: %s", safeReportValue(order.CustomerPhone)), "", "L", false)
-			pdf.MultiCell(0, 5, fmt.Sprintf("Total: %s | Payment: %s | Reference: %s", formatKsh(order.TotalAmount), safeReportValue(order.PaymentMethod), safeReportValue(order.PaymentRef)), "", "L", false)
+			pdf.MultiCell(0, 5, fmt.Sprintf("Total: %s | VAT: %s | Tip: %s | Payment: %s | Reference: %s", formatKsh(order.TotalAmount), formatKsh(order.TaxAmount), formatKsh(order.TipAmount), safeReportValue(order.PaymentMethod), safeReportValue(order.PaymentRef)), "", "L", false)
 
 			if len(order.Items) == 0 {
 				pdf.MultiCell(0, 5, "- No items found", "", "L", false)
@@ -258,6 +389,100 @@ func renderSalesReportPDF(report *core.SalesReport, loc *time.Location) ([]byte,
 	return buffer.Bytes(), nil
 }
 
+// renderTaxSummaryPDF prints VAT totals per rate in the layout used for filing returns
+func renderTaxSummaryPDF(pdf *gofpdf.Fpdf, report *core.SalesReport) {
+	ensurePageSpace(pdf, 30)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, "Tax Summary", "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(30, 7, "VAT Rate", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(25, 7, "Orders", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, "Gross Sales", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, "Taxable Value", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, "VAT", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	if len(report.TaxSummary) == 0 {
+		pdf.CellFormat(190, 7, "No taxable sales in this report range.", "1", 1, "L", false, 0, "")
+	}
+	for _, line := range report.TaxSummary {
+		pdf.CellFormat(30, 7, formatTaxRate(line.Rate), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 7, strconv.Itoa(line.OrderCount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 7, formatKsh(line.GrossSales), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 7, formatKsh(line.TaxableAmount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 7, formatKsh(line.TaxAmount), "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(55, 7, "Total", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(45, 7, formatKsh(report.TotalRevenue), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, formatKsh(report.NetSales), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, formatKsh(report.TotalTax), "1", 1, "R", false, 0, "")
+	pdf.Ln(3)
+}
+
+// renderPaymentMethodsPDF prints sales per payment method so cash and card can be reconciled against the till
+func renderPaymentMethodsPDF(pdf *gofpdf.Fpdf, report *core.SalesReport) {
+	ensurePageSpace(pdf, 30)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, "Sales by Payment Method", "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(100, 7, "Method", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(45, 7, "Orders", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, "Sales", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	if len(report.PaymentMethods) == 0 {
+		pdf.CellFormat(190, 7, "No sales in this report range.", "1", 1, "L", false, 0, "")
+	}
+	for _, line := range report.PaymentMethods {
+		pdf.CellFormat(100, 7, safeReportValue(line.Method), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(45, 7, strconv.Itoa(line.OrderCount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 7, formatKsh(line.Amount), "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(100, 7, "Total", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(45, 7, strconv.Itoa(report.OrderCount), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, formatKsh(report.TotalRevenue), "1", 1, "R", false, 0, "")
+	pdf.Ln(3)
+}
+
+// renderStaffTipsPDF prints tips per bartender so they can be paid out at the end of the shift
+func renderStaffTipsPDF(pdf *gofpdf.Fpdf, report *core.SalesReport) {
+	ensurePageSpace(pdf, 30)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, "Staff Tips", "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(100, 7, "Staff", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(45, 7, "Orders", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, "Tips", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	if len(report.StaffTips) == 0 {
+		pdf.CellFormat(190, 7, "No tips in this report range.", "1", 1, "L", false, 0, "")
+	}
+	tippedOrders := 0
+	for _, line := range report.StaffTips {
+		pdf.CellFormat(100, 7, safeReportValue(line.StaffName), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(45, 7, strconv.Itoa(line.OrderCount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 7, formatKsh(line.Amount), "1", 1, "R", false, 0, "")
+		tippedOrders += line.OrderCount
+	}
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(100, 7, "Total", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(45, 7, strconv.Itoa(tippedOrders), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, formatKsh(report.TotalTips), "1", 1, "R", false, 0, "")
+	pdf.Ln(3)
+}
+
 func ensurePageSpace(pdf *gofpdf.Fpdf, minSpace float64) {
 	pageWidth, pageHeight := pdf.GetPageSize()
 	leftMargin, _, rightMargin, bottomMargin := pdf.GetMargins()
@@ -284,3 +509,7 @@ func formatReportDateTime(value time.Time, loc *time.Location) string {
 func formatKsh(amount float64) string {
 	return fmt.Sprintf("Ksh %.2f", amount)
 }
+
+func formatTaxRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// maxReportMovers is how many products the weekly and monthly reports list as moving up and down
+const maxReportMovers = 5
+
+// GenerateWeeklySalesReport generates a report for the Monday-to-Sunday business week containing date
+// (default: the last completed week), compared with the week before, in the requested format (pdf, csv or xlsx).
+func (s *DashboardService) GenerateWeeklySalesReport(ctx context.Context, date string, format string) ([]byte, string, error) {
+	format, err := normalizeReportFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
+
+	target, err := resolveBusinessDate(date, s.clock.Now().In(loc), loc, startHour)
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(date) == "" {
+		target = target.AddDate(0, 0, -7)
+	}
+	weekStart := target.AddDate(0, 0, -((int(target.Weekday()) + 6) % 7))
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	previousStart := weekStart.AddDate(0, 0, -7)
+
+	report, err := s.buildComparedSalesReport(ctx, "Weekly Sales Report",
+		fmt.Sprintf("%s to %s", weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02")), weekStart, weekEnd.AddDate(0, 0, 1),
+		fmt.Sprintf("%s to %s", previousStart.Format("2006-01-02"), weekStart.AddDate(0, 0, -1).Format("2006-01-02")), previousStart,
+		loc, startHour)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := renderSalesReport(report, loc, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("weekly-sales-%s.%s", weekStart.Format("2006-01-02"), format)
+	return data, filename, nil
+}
+
+// GenerateMonthlySalesReport generates a report for the business days of month (YYYY-MM, default: the
+// last completed month), compared with the month before, in the requested format (pdf, csv or xlsx).
+func (s *DashboardService) GenerateMonthlySalesReport(ctx context.Context, month string, format string) ([]byte, string, error) {
+	format, err := normalizeReportFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	loc := reportLocation()
+	startHour := s.businessDayStartHour(ctx)
+
+	var monthStart time.Time
+	if month = strings.TrimSpace(month); month == "" {
+		current := currentBusinessDateInLocation(s.clock.Now().In(loc), loc, startHour)
+		monthStart = time.Date(current.Year(), current.Month()-1, 1, 0, 0, 0, 0, loc)
+	} else {
+		monthStart, err = time.ParseInLocation("2006-01", month, loc)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid month format, expected YYYY-MM")
+		}
+	}
+	previousStart := monthStart.AddDate(0, -1, 0)
+
+	report, err := s.buildComparedSalesReport(ctx, "Monthly Sales Report",
+		monthStart.Format("January 2006"), monthStart, monthStart.AddDate(0, 1, 0),
+		previousStart.Format("January 2006"), previousStart,
+		loc, startHour)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := renderSalesReport(report, loc, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("monthly-sales-%s.%s", monthStart.Format("2006-01"), format)
+	return data, filename, nil
+}
+
+// buildComparedSalesReport builds the report for business dates [from, until) and compares it with the
+// period from previousFrom up to from
+func (s *DashboardService) buildComparedSalesReport(
+	ctx context.Context,
+	title string,
+	dateLabel string,
+	from time.Time,
+	until time.Time,
+	previousLabel string,
+	previousFrom time.Time,
+	loc *time.Location,
+	startHour int,
+) (*core.SalesReport, error) {
+	startLocal, _ := businessDayWindow(from, loc, startHour)
+	endLocal, _ := businessDayWindow(until, loc, startHour)
+	previousStartLocal, _ := businessDayWindow(previousFrom, loc, startHour)
+
+	report, err := s.buildSalesReport(ctx, title, dateLabel, startLocal, endLocal, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.orderRepo.GetByDateRangeAndStatuses(ctx, previousStartLocal.UTC(), startLocal.UTC(), settledSalesStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comparison orders: %w", err)
+	}
+	report.Comparison = compareSales(report, previous, previousLabel)
+	return report, nil
+}
+
+// compareSales sets revenue, order count and average order value against the previous period's orders
+// and picks the products whose sales rose and fell the most
+func compareSales(report *core.SalesReport, previous []*core.Order, previousLabel string) *core.SalesComparison {
+	comparison := &core.SalesComparison{
+		PreviousLabel:      previousLabel,
+		PreviousOrderCount: len(previous),
+		MoversUp:           []core.ProductMover{},
+		MoversDown:         []core.ProductMover{},
+	}
+	for _, order := range previous {
+		comparison.PreviousRevenue += productSales(order)
+	}
+	if len(previous) > 0 {
+		comparison.PreviousAverageOrderValue = comparison.PreviousRevenue / float64(len(previous))
+	}
+	comparison.RevenueChange = percentChange(report.TotalRevenue, comparison.PreviousRevenue)
+	comparison.OrderCountChange = percentChange(float64(report.OrderCount), float64(comparison.PreviousOrderCount))
+	comparison.AverageOrderValueChange = percentChange(report.AverageOrderValue, comparison.PreviousAverageOrderValue)
+
+	movers := make(map[string]*core.ProductMover)
+	mover := func(item core.OrderItem) *core.ProductMover {
+		line, ok := movers[item.ProductID]
+		if !ok {
+			line = &core.ProductMover{ProductID: item.ProductID, ProductName: item.ProductName}
+			movers[item.ProductID] = line
+		}
+		return line
+	}
+	for _, order := range report.Orders {
+		for _, item := range order.Items {
+			line := mover(item)
+			line.Quantity += item.Quantity
+			line.Revenue += item.PriceAtTime * float64(item.Quantity)
+		}
+	}
+	for _, order := range previous {
+		for _, item := range order.Items {
+			line := mover(item)
+			line.PreviousQuantity += item.Quantity
+			line.PreviousRevenue += item.PriceAtTime * float64(item.Quantity)
+		}
+	}
+
+	for _, line := range movers {
+		line.Revenue = roundCents(line.Revenue)
+		line.PreviousRevenue = roundCents(line.PreviousRevenue)
+		line.RevenueChange = roundCents(line.Revenue - line.PreviousRevenue)
+		switch {
+		case line.RevenueChange > 0:
+			comparison.MoversUp = append(comparison.MoversUp, *line)
+		case line.RevenueChange < 0:
+			comparison.MoversDown = append(comparison.MoversDown, *line)
+		}
+	}
+	sort.Slice(comparison.MoversUp, func(i, j int) bool {
+		if comparison.MoversUp[i].RevenueChange != comparison.MoversUp[j].RevenueChange {
+			return comparison.MoversUp[i].RevenueChange > comparison.MoversUp[j].RevenueChange
+		}
+		return comparison.MoversUp[i].ProductName < comparison.MoversUp[j].ProductName
+	})
+	sort.Slice(comparison.MoversDown, func(i, j int) bool {
+		if comparison.MoversDown[i].RevenueChange != comparison.MoversDown[j].RevenueChange {
+			return comparison.MoversDown[i].RevenueChange < comparison.MoversDown[j].RevenueChange
+		}
+		return comparison.MoversDown[i].ProductName < comparison.MoversDown[j].ProductName
+	})
+	if len(comparison.MoversUp) > maxReportMovers {
+		comparison.MoversUp = comparison.MoversUp[:maxReportMovers]
+	}
+	if len(comparison.MoversDown) > maxReportMovers {
+		comparison.MoversDown = comparison.MoversDown[:maxReportMovers]
+	}
+	return comparison
+}
+
+// percentChange is the change from previous to current in percent, to one decimal; nil when previous is zero
+func percentChange(current float64, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round((current-previous)/previous*1000) / 10
+	return &change
+}
+
+// renderComparisonPDF prints the period-over-period deltas and top movers of a weekly or monthly report
+func renderComparisonPDF(pdf *gofpdf.Fpdf, report *core.SalesReport) {
+	comparison := report.Comparison
+	ensurePageSpace(pdf, 40)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, fmt.Sprintf("Compared with %s", comparison.PreviousLabel), "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(55, 7, "", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(50, 7, "This Period", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(50, 7, "Previous", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 7, "Change", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	rows := []struct {
+		label, current, previous string
+		change                   *float64
+	}{
+		{"Total Sales", formatKsh(report.TotalRevenue), formatKsh(comparison.PreviousRevenue), comparison.RevenueChange},
+		{"Orders", fmt.Sprint(report.OrderCount), fmt.Sprint(comparison.PreviousOrderCount), comparison.OrderCountChange},
+		{"Average Order Value", formatKsh(report.AverageOrderValue), formatKsh(comparison.PreviousAverageOrderValue), comparison.AverageOrderValueChange},
+	}
+	for _, row := range rows {
+		pdf.CellFormat(55, 7, row.label, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 7, row.current, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(50, 7, row.previous, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 7, formatPercentChange(row.change), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	renderMoversPDF(pdf, "Top Movers Up", comparison.MoversUp, "No product sold more than in the previous period.")
+	renderMoversPDF(pdf, "Top Movers Down", comparison.MoversDown, "No product sold less than in the previous period.")
+}
+
+// renderMoversPDF prints products with their units and sales in both periods
+func renderMoversPDF(pdf *gofpdf.Fpdf, title string, movers []core.ProductMover, empty string) {
+	ensurePageSpace(pdf, 30)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 7, title, "1", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(65, 7, "Product", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(30, 7, "Units", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(50, 7, "Sales", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(45, 7, "Change", "1", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	if len(movers) == 0 {
+		pdf.CellFormat(190, 7, empty, "1", 1, "L", false, 0, "")
+	}
+	for _, mover := range movers {
+		pdf.CellFormat(65, 7, safeReportValue(mover.ProductName), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 7, fmt.Sprintf("%d -> %d", mover.PreviousQuantity, mover.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(50, 7, formatKsh(mover.Revenue), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 7, fmt.Sprintf("%+.2f", mover.RevenueChange), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(3)
+}
+
+func formatPercentChange(change *float64) string {
+	if change == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", *change)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// renderSalesReportXLSX renders a workbook with a Summary sheet (totals, VAT per rate, payment methods,
+// staff tips and, for weekly and monthly reports, the comparison), an Orders sheet with one row per
+// order and an Items sheet with one row per order item. Orders and items share order_id so they can
+// be joined in a pivot table.
+func renderSalesReportXLSX(report *core.SalesReport, loc *time.Location) ([]byte, error) {
+	return renderXLSX([]xlsxSheet{
+		{Name: "Summary", Rows: salesSummaryRows(report)},
+		{Name: "Orders", Rows: salesOrderRows(report)},
+		{Name: "Items", Rows: salesItemRows(report)},
+	}, loc)
+}
+
+func salesSummaryRows(report *core.SalesReport) [][]interface{} {
+	rows := [][]interface{}{
+		{"Metric", "Value"},
+		{"Report", report.Title},
+		{"Business Date", report.DateLabel},
+		{"Range Start", report.StartAt},
+		{"Range End", report.EndAt},
+		{"Generated At", report.GeneratedAt},
+		{"Total Sales", report.TotalRevenue},
+		{"Orders", report.OrderCount},
+		{"Average Order Value", report.AverageOrderValue},
+		{"Net Sales (excl. VAT)", report.NetSales},
+		{"VAT", report.TotalTax},
+		{"Staff Tips (not in sales)", report.TotalTips},
+	}
+
+	if comparison := report.Comparison; comparison != nil {
+		rows = append(rows,
+			[]interface{}{},
+			[]interface{}{"Previous Period", comparison.PreviousLabel},
+			[]interface{}{"Previous Total Sales", comparison.PreviousRevenue},
+			[]interface{}{"Previous Orders", comparison.PreviousOrderCount},
+			[]interface{}{"Previous Average Order Value", comparison.PreviousAverageOrderValue},
+			[]interface{}{"Sales Change (%)", xlsxPercent(comparison.RevenueChange)},
+			[]interface{}{"Orders Change (%)", xlsxPercent(comparison.OrderCountChange)},
+			[]interface{}{"Average Order Value Change (%)", xlsxPercent(comparison.AverageOrderValueChange)},
+		)
+		for _, mover := range comparison.MoversUp {
+			rows = append(rows, []interface{}{"Top Mover Up: " + mover.ProductName, mover.RevenueChange})
+		}
+		for _, mover := range comparison.MoversDown {
+			rows = append(rows, []interface{}{"Top Mover Down: " + mover.ProductName, mover.RevenueChange})
+		}
+	}
+
+	rows = append(rows, []interface{}{})
+	for _, line := range report.TaxSummary {
+		rows = append(rows,
+			[]interface{}{"VAT " + formatTaxRate(line.Rate) + " Gross Sales", line.GrossSales},
+			[]interface{}{"VAT " + formatTaxRate(line.Rate) + " Taxable Value", line.TaxableAmount},
+			[]interface{}{"VAT " + formatTaxRate(line.Rate) + " VAT", line.TaxAmount},
+		)
+	}
+	for _, line := range report.PaymentMethods {
+		rows = append(rows, []interface{}{"Sales via " + safeReportValue(line.Method), line.Amount})
+	}
+	for _, line := range report.StaffTips {
+		rows = append(rows, []interface{}{"Tips for " + safeReportValue(line.StaffName), line.Amount})
+	}
+	return rows
+}
+
+func salesOrderRows(report *core.SalesReport) [][]interface{} {
+	rows := [][]interface{}{{
+		"order_id", "order_created_at", "pickup_code", "status", "customer_phone", "payment_method",
+		"payment_reference", "order_total", "order_vat", "vat_rate", "order_tip", "order_delivery_fee",
+		"items", "age_confirmed_at",
+	}}
+	for _, order := range report.Orders {
+		var ageConfirmedAt interface{}
+		if order.AgeConfirmedAt != nil {
+			ageConfirmedAt = *order.AgeConfirmedAt
+		}
+		items := 0
+		for _, item := range order.Items {
+			items += item.Quantity
+		}
+		rows = append(rows, []interface{}{
+			order.ID,
+			order.CreatedAt,
+			order.PickupCode,
+			string(order.Status),
+			order.CustomerPhone,
+			order.PaymentMethod,
+			order.PaymentRef,
+			order.TotalAmount,
+			order.TaxAmount,
+			order.TaxRate,
+			order.TipAmount,
+			order.DeliveryFee,
+			items,
+			ageConfirmedAt,
+		})
+	}
+	return rows
+}
+
+func salesItemRows(report *core.SalesReport) [][]interface{} {
+	rows := [][]interface{}{{
+		"order_id", "order_created_at", "pickup_code", "product_id", "product", "options",
+		"quantity", "unit_price", "line_total", "line_vat",
+	}}
+	for _, order := range report.Orders {
+		for _, item := range order.Items {
+			rows = append(rows, []interface{}{
+				order.ID,
+				order.CreatedAt,
+				order.PickupCode,
+				item.ProductID,
+				item.ProductName,
+				core.FormatModifiers(item.Modifiers),
+				item.Quantity,
+				item.PriceAtTime,
+				item.PriceAtTime * float64(item.Quantity),
+				item.TaxAmount,
+			})
+		}
+	}
+	return rows
+}
+
+// xlsxPercent is a percent change cell, empty when there was nothing to compare with
+func xlsxPercent(change *float64) interface{} {
+	if change == nil {
+		return nil
+	}
+	return *change
+}
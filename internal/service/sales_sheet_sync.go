@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// salesSheetPollInterval is how often the sync checks for a newly closed business day, and retries a
+// failed append
+const salesSheetPollInterval = 5 * time.Minute
+
+// SalesSheetSync appends each business day's summary row (date, revenue, orders, average order value,
+// top product) to the owner's spreadsheet once the day has closed, so the spreadsheet they already
+// keep fills itself in. Managers can also append a day from the dashboard.
+type SalesSheetSync struct {
+	dashboard *DashboardService // Builds the day's sales report
+	sheet     core.SpreadsheetGateway
+	syncs     core.SalesSheetSyncRepository
+	clock     core.Clock
+}
+
+// NewSalesSheetSync creates the spreadsheet sync
+func NewSalesSheetSync(dashboard *DashboardService, sheet core.SpreadsheetGateway, syncs core.SalesSheetSyncRepository) *SalesSheetSync {
+	return &SalesSheetSync{
+		dashboard: dashboard,
+		sheet:     sheet,
+		syncs:     syncs,
+		clock:     core.SystemClock{},
+	}
+}
+
+// Run appends the business day that closed last (at business.day_start_hour), once, checking every five
+// minutes until ctx is cancelled. Safe to run on every replica.
+func (s *SalesSheetSync) Run(ctx context.Context) {
+	ticker := time.NewTicker(salesSheetPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.syncClosedDay(ctx)
+		}
+	}
+}
+
+func (s *SalesSheetSync) syncClosedDay(ctx context.Context) {
+	loc := reportLocation()
+	closed := currentBusinessDateInLocation(s.clock.Now().In(loc), loc, s.dashboard.businessDayStartHour(ctx)).AddDate(0, 0, -1)
+	date := closed.Format("2006-01-02")
+
+	claimed, err := s.syncs.Claim(ctx, date)
+	if err != nil {
+		log.Printf("Error claiming sales sheet sync for %s: %v", date, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	if _, err := s.appendDay(ctx, closed); err != nil {
+		log.Printf("Error syncing %s sales to the spreadsheet: %v", date, err)
+		if err := s.syncs.Release(ctx, date); err != nil {
+			log.Printf("Error releasing sales sheet sync for %s: %v", date, err)
+		}
+	}
+}
+
+// SyncDay appends the summary row for businessDate (YYYY-MM-DD, default the last closed business day),
+// even if that day was synced before
+func (s *SalesSheetSync) SyncDay(ctx context.Context, businessDate string) (*core.DailySalesSummary, error) {
+	loc := reportLocation()
+	startHour := s.dashboard.businessDayStartHour(ctx)
+	current := currentBusinessDateInLocation(s.clock.Now().In(loc), loc, startHour)
+
+	target := current.AddDate(0, 0, -1)
+	if businessDate != "" {
+		parsed, err := resolveBusinessDate(businessDate, s.clock.Now().In(loc), loc, startHour)
+		if err != nil {
+			return nil, err
+		}
+		if !parsed.Before(current) {
+			return nil, fmt.Errorf("invalid date: the %s business day hasn't closed yet", parsed.Format("2006-01-02"))
+		}
+		target = parsed
+	}
+
+	summary, err := s.appendDay(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	// Recorded so the nightly run doesn't append the same day again
+	if _, err := s.syncs.Claim(ctx, summary.BusinessDate); err != nil {
+		log.Printf("Error recording sales sheet sync for %s: %v", summary.BusinessDate, err)
+	}
+	return summary, nil
+}
+
+func (s *SalesSheetSync) appendDay(ctx context.Context, businessDate time.Time) (*core.DailySalesSummary, error) {
+	summary, err := s.dashboard.dailySalesSummary(ctx, businessDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sheet.AppendRow(ctx, []interface{}{
+		summary.BusinessDate,
+		summary.Revenue,
+		summary.OrderCount,
+		summary.AverageOrderValue,
+		summary.TopProduct,
+	}); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// dailySalesSummary totals one business day from the same settled orders as the daily sales report;
+// the top product is the one with the most units sold
+func (s *DashboardService) dailySalesSummary(ctx context.Context, businessDate time.Time) (*core.DailySalesSummary, error) {
+	loc := reportLocation()
+	startLocal, endLocal := businessDayWindow(businessDate, loc, s.businessDayStartHour(ctx))
+	date := businessDate.Format("2006-01-02")
+
+	report, err := s.buildSalesReport(ctx, "Daily Sales Summary", date, startLocal, endLocal, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &core.DailySalesSummary{
+		BusinessDate:      date,
+		Revenue:           roundCents(report.TotalRevenue),
+		OrderCount:        report.OrderCount,
+		AverageOrderValue: roundCents(report.AverageOrderValue),
+	}
+	quantities := make(map[string]int)
+	for _, order := range report.Orders {
+		for _, item := range order.Items {
+			quantities[item.ProductName] += item.Quantity
+		}
+	}
+	for name, quantity := range quantities {
+		if quantity > summary.TopProductQuantity || (quantity == summary.TopProductQuantity && name < summary.TopProduct) {
+			summary.TopProduct = name
+			summary.TopProductQuantity = quantity
+		}
+	}
+	return summary, nil
+}
+
+// SetSalesSheetSync enables the manual spreadsheet sync; without it SyncSalesSheet fails
+func (s *DashboardService) SetSalesSheetSync(sync *SalesSheetSync) {
+	s.salesSheet = sync
+}
+
+// SyncSalesSheet appends a business day's summary row to the configured spreadsheet
+func (s *DashboardService) SyncSalesSheet(ctx context.Context, businessDate string) (*core.DailySalesSummary, error) {
+	if s.salesSheet == nil {
+		return nil, fmt.Errorf("google sheets sync not configured")
+	}
+	return s.salesSheet.SyncDay(ctx, businessDate)
+}
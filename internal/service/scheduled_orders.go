@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+const scheduledOrderPollInterval = time.Minute
+
+// PaidOrderNotifier sends a PAID order to the bar staff queue
+type PaidOrderNotifier interface {
+	NotifyPaidOrder(ctx context.Context, order *core.Order) error
+}
+
+// ScheduledOrderReleaser moves paid pre-orders from SCHEDULED to PAID leadTime before the customer's
+// chosen time, then notifies bar staff and the dashboard as for any newly paid order
+type ScheduledOrderReleaser struct {
+	orderRepo core.OrderRepository
+	staff     PaidOrderNotifier
+	eventBus  *events.EventBus
+	clock     core.Clock
+	leadTime  time.Duration
+}
+
+// NewScheduledOrderReleaser creates the release job; leadTime is how long before the chosen time
+// the bar gets the order (15 minutes when not set)
+func NewScheduledOrderReleaser(orderRepo core.OrderRepository, staff PaidOrderNotifier, eventBus *events.EventBus, leadTime time.Duration) *ScheduledOrderReleaser {
+	if leadTime <= 0 {
+		leadTime = 15 * time.Minute
+	}
+
+	return &ScheduledOrderReleaser{
+		orderRepo: orderRepo,
+		staff:     staff,
+		eventBus:  eventBus,
+		clock:     core.SystemClock{},
+		leadTime:  leadTime,
+	}
+}
+
+// Run releases due pre-orders every minute until ctx is cancelled. Safe to run on every replica.
+func (r *ScheduledOrderReleaser) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduledOrderPollInterval)
+	defer ticker.Stop()
+
+	r.releaseDue(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.releaseDue(ctx)
+		}
+	}
+}
+
+func (r *ScheduledOrderReleaser) releaseDue(ctx context.Context) {
+	orders, err := r.orderRepo.GetDueScheduled(ctx, r.clock.Now().Add(r.leadTime))
+	if err != nil {
+		log.Printf("Error loading due scheduled orders: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		r.release(ctx, order)
+	}
+}
+
+func (r *ScheduledOrderReleaser) release(ctx context.Context, order *core.Order) {
+	released, err := r.orderRepo.ReleaseScheduled(ctx, order.ID, "pre-order released to the bar")
+	if err != nil {
+		log.Printf("Error releasing scheduled order %s: %v", order.ID, err)
+		return
+	}
+	if !released {
+		return // Another replica released it
+	}
+
+	// Reload for the items the bar staff message lists
+	paid, err := r.orderRepo.GetByID(ctx, order.ID)
+	if err != nil {
+		log.Printf("Released scheduled order %s but failed to reload it: %v", order.ID, err)
+		order.Status = core.OrderStatusPaid
+		paid = order
+	}
+	log.Printf("Pre-order %s (#%s) for %s released to the bar", paid.ID, paid.PickupCode, FormatScheduledTime(*paid.ScheduledFor))
+
+	if r.staff != nil {
+		if err := r.staff.NotifyPaidOrder(ctx, paid); err != nil {
+			log.Printf("Error notifying bar staff about pre-order %s: %v", paid.ID, err)
+		}
+	}
+	if r.eventBus != nil {
+		r.eventBus.PublishNewOrder(ctx, paid)
+	}
+}
+
+// FormatScheduledTime shows a pre-order time in bar-local time, e.g. "Fri 2 Jan, 21:30"
+func FormatScheduledTime(t time.Time) string {
+	return t.In(reportLocation()).Format("Mon 2 Jan, 15:04")
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// sessionLockPoll is how often a message waiting for its phone's lock tries again
+const sessionLockPoll = 50 * time.Millisecond
+
+// SessionLocker processes one message per phone at a time across every replica, so rapid messages or
+// double-tapped buttons from one customer can't interleave and overwrite each other's session. Locks
+// expire after ttl in case their holder crashes; a message waits at most wait for one.
+type SessionLocker struct {
+	store core.SessionLockStore
+	ttl   time.Duration
+	wait  time.Duration
+
+	acquired  atomic.Uint64
+	contended atomic.Uint64
+	timeouts  atomic.Uint64
+	failures  atomic.Uint64
+	waited    atomic.Int64 // Nanoseconds spent waiting for held locks
+}
+
+// NewSessionLocker creates a locker whose locks last ttl and whose messages wait up to wait for one
+func NewSessionLocker(store core.SessionLockStore, ttl time.Duration, wait time.Duration) *SessionLocker {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	if wait <= 0 {
+		wait = 10 * time.Second
+	}
+	return &SessionLocker{store: store, ttl: ttl, wait: wait}
+}
+
+// LockStats returns lock counters since startup: locks taken, locks that had to be waited for,
+// waits that timed out, lock store failures and the total time spent waiting
+func (l *SessionLocker) LockStats() (acquired uint64, contended uint64, timeouts uint64, failures uint64, waited time.Duration) {
+	return l.acquired.Load(), l.contended.Load(), l.timeouts.Load(), l.failures.Load(), time.Duration(l.waited.Load())
+}
+
+// Run runs fn while holding phone's lock. When the lock is still held after the wait, or Redis fails,
+// fn runs without it: a message handled out of turn beats one that's lost.
+func (l *SessionLocker) Run(ctx context.Context, phone string, fn func(ctx context.Context) error) error {
+	token, ok, err := l.store.TryLock(ctx, phone, l.ttl)
+	if err == nil && !ok {
+		l.contended.Add(1)
+		token, ok, err = l.waitForLock(ctx, phone)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+	}
+
+	switch {
+	case err != nil:
+		l.failures.Add(1)
+		log.Printf("Session lock unavailable for %s, processing without it: %v", maskPhone(phone), err)
+		return fn(ctx)
+	case !ok:
+		l.timeouts.Add(1)
+		log.Printf("WARNING: session lock for %s still held after %s, processing without it", maskPhone(phone), l.wait)
+		return fn(ctx)
+	}
+
+	l.acquired.Add(1)
+	defer func() {
+		// Released even when the webhook's context is done, so the next message doesn't wait out the TTL
+		if err := l.store.Unlock(context.WithoutCancel(ctx), phone, token); err != nil {
+			log.Printf("Failed to release session lock for %s (it expires in %s): %v", maskPhone(phone), l.ttl, err)
+		}
+	}()
+	return fn(ctx)
+}
+
+// waitForLock retries the lock until it's free, the wait runs out or ctx is done
+func (l *SessionLocker) waitForLock(ctx context.Context, phone string) (string, bool, error) {
+	start := time.Now()
+	defer func() { l.waited.Add(int64(time.Since(start))) }()
+
+	deadline := time.NewTimer(l.wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(sessionLockPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		case <-deadline.C:
+			return "", false, nil
+		case <-ticker.C:
+			token, ok, err := l.store.TryLock(ctx, phone, l.ttl)
+			if err != nil || ok {
+				return token, ok, err
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/msisdn"
+)
+
+// CustomerSessionView is a customer's bot session as support staff see it
+type CustomerSessionView struct {
+	Phone        string        `json:"phone"` // WhatsApp ID, e.g. 254712345678
+	Session      *core.Session `json:"session"`
+	CartItems    int           `json:"cart_items"`
+	CartTotal    float64       `json:"cart_total"`
+	ExpiresIn    int           `json:"expires_in_seconds"` // -1 when the session has no TTL
+	PendingOrder *core.Order   `json:"pending_order,omitempty"`
+}
+
+// SetSessionStore wires the bot session store behind the admin session endpoints
+func (s *DashboardService) SetSessionStore(sessions core.SessionRepository, inspector core.SessionInspector) {
+	s.sessions = sessions
+	s.sessionReader = inspector
+}
+
+// GetCustomerSession returns a customer's bot session (state, cart, pending order) without extending it
+func (s *DashboardService) GetCustomerSession(ctx context.Context, phone string, actorUserID string) (*CustomerSessionView, error) {
+	if s.sessionReader == nil {
+		return nil, fmt.Errorf("sessions not configured")
+	}
+	waID, err := msisdn.WhatsAppID(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Bot session for %s viewed by admin %s", maskPhone(waID), actorUserID)
+	session, ttl, err := s.sessionReader.Inspect(ctx, waID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &CustomerSessionView{
+		Phone:     waID,
+		Session:   session,
+		ExpiresIn: -1,
+	}
+	if ttl > 0 {
+		view.ExpiresIn = int(ttl / time.Second)
+	}
+	for _, item := range session.Cart {
+		view.CartItems += item.Quantity
+		view.CartTotal += item.Price * float64(item.Quantity)
+	}
+	view.CartTotal = roundCents(view.CartTotal)
+
+	if session.PendingOrderID != "" {
+		if order, err := s.orderRepo.GetByID(ctx, session.PendingOrderID); err == nil {
+			view.PendingOrder = order
+		} else {
+			log.Printf("Failed to load pending order %s of session %s: %v", session.PendingOrderID, maskPhone(waID), err)
+		}
+	}
+	return view, nil
+}
+
+// ResetCustomerSession deletes a customer's bot session, so their next message starts over with an
+// empty cart. Orders already placed are left as they are.
+func (s *DashboardService) ResetCustomerSession(ctx context.Context, phone string, actorUserID string) error {
+	if s.sessions == nil {
+		return fmt.Errorf("sessions not configured")
+	}
+	waID, err := msisdn.WhatsAppID(phone)
+	if err != nil {
+		return err
+	}
+
+	if s.sessionReader != nil {
+		if _, _, err := s.sessionReader.Inspect(ctx, waID); err != nil {
+			return err
+		}
+	}
+	if err := s.sessions.Delete(ctx, waID); err != nil {
+		return err
+	}
+
+	log.Printf("Bot session for %s reset by admin %s", maskPhone(waID), actorUserID)
+	return nil
+}
+
+// maskPhone keeps a phone number's prefix and last three digits for logs, e.g. 254712***678
+func maskPhone(phone string) string {
+	if len(phone) <= 6 {
+		return "***"
+	}
+	return phone[:len(phone)-6] + "***" + phone[len(phone)-3:]
+}
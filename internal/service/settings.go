@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+// Runtime settings managers can change from the dashboard (GET/PATCH /api/admin/settings)
+const (
+	SettingOrderingPaused       = "ordering.paused"
+	SettingOrderingMessage      = "ordering.message"
+	SettingSafetyNetDelay       = "payment.safety_net_delay_seconds"
+	SettingSessionTTL           = "session.ttl_seconds"
+	SettingBusinessDayStartHour = "reports.business_day_start_hour"
+	SettingLowStockThreshold    = "inventory.low_stock_threshold"
+	SettingResetKeywords        = "bot.reset_keywords"
+	SettingSuggestionsEnabled   = "bot.suggestions_enabled"
+	SettingHideOutOfStock       = "menu.hide_out_of_stock" // Also read by the product repository's menu queries
+	SettingRestockAlerts        = "menu.restock_alerts"
+	SettingMpesaFeeBasisPoints  = "accounting.mpesa_fee_basis_points"
+)
+
+const (
+	settingTypeInt    = "int"
+	settingTypeBool   = "bool"
+	settingTypeString = "string"
+
+	// settingsCacheTTL bounds how stale a replica's cache gets if it misses a settings_updated event
+	settingsCacheTTL = time.Minute
+)
+
+// settingDefinition describes a setting's type, default and allowed values
+type settingDefinition struct {
+	Type        string
+	Default     string
+	Min         int // Inclusive bounds of an int setting
+	Max         int
+	MaxLength   int                      // Longest value of a string setting
+	Validate    func(value string) error // Extra checks on a string setting's trimmed value
+	Description string
+}
+
+var settingDefinitions = withBotCopySettings(map[string]settingDefinition{
+	SettingOrderingPaused: {
+		Type: settingTypeBool, Default: "false",
+		Description: "Stop the bot from accepting new checkouts",
+	},
+	SettingOrderingMessage: {
+		Type: settingTypeString, MaxLength: maxPauseMessageLength,
+		Description: "Shown to customers who try to check out while ordering is paused (empty uses the default)",
+	},
+	SettingSafetyNetDelay: {
+		Type: settingTypeInt, Default: "45", Min: 15, Max: 300,
+		Description: "Seconds after an STK push before a customer whose order is still pending gets a Retry button",
+	},
+	SettingSessionTTL: {
+		Type: settingTypeInt, Default: "7200", Min: 300, Max: 86400,
+		Description: "Seconds a bot conversation is kept after the customer's last message",
+	},
+	SettingBusinessDayStartHour: {
+		Type: settingTypeInt, Default: strconv.Itoa(defaultBusinessDayStartHourEAT), Min: 0, Max: 23,
+		Description: "Hour (EAT) a business day starts for sales reports and analytics",
+	},
+	SettingLowStockThreshold: {
+		Type: settingTypeInt, Default: strconv.Itoa(defaultLowStockThreshold), Min: 0, Max: 1000,
+		Description: "Stock level at or below which the dashboard flags a product as running low (a low_stock event is sent when stock falls to it)",
+	},
+	SettingResetKeywords: {
+		Type: settingTypeString, Default: defaultResetKeywords, MaxLength: 500, Validate: validateResetKeywords,
+		Description: "Comma-separated messages that restart the bot conversation from any step",
+	},
+	SettingSuggestionsEnabled: {
+		Type: settingTypeBool, Default: "true",
+		Description: "After a cocktail is added to the cart, suggest the chasers customers most often buy with it",
+	},
+	SettingHideOutOfStock: {
+		Type: settingTypeBool, Default: "false",
+		Description: "Leave sold-out products off the bot's menus and search until they're restocked (a product's own setting wins)",
+	},
+	SettingRestockAlerts: {
+		Type: settingTypeBool, Default: "true",
+		Description: "Offer \"Notify me\" on sold-out products and message those customers when the product is back in stock",
+	},
+	SettingMpesaFeeBasisPoints: {
+		Type: settingTypeInt, Default: "0", Min: 0, Max: 1000,
+		Description: "Charge on each M-Pesa payment in hundredths of a percent (50 = 0.5%), shown as fees in the accounting export",
+	},
+})
+
+// SettingView is one setting as managers see it: its current value, default and allowed range
+type SettingView struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"` // int, bool or string
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default"`
+	Min         *int        `json:"min,omitempty"`
+	Max         *int        `json:"max,omitempty"`
+	Description string      `json:"description"`
+	UpdatedBy   string      `json:"updated_by,omitempty"` // Empty while the default applies
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
+}
+
+// SettingsService serves runtime settings from an in-memory cache of the settings table.
+// Changes are announced with a settings_updated event, and every replica running Run drops its
+// cache when one arrives. It also stores the ordering pause switch (core.OrderingStatusStore).
+type SettingsService struct {
+	repo     core.SettingsRepository
+	eventBus *events.EventBus
+	clock    core.Clock
+	defaults map[string]string
+
+	mu         sync.RWMutex
+	cache      map[string]*core.Setting
+	loadedAt   time.Time
+	generation int // Bumped on invalidation so a load that raced with it isn't cached
+}
+
+// NewSettingsService creates a settings service using the built-in defaults
+func NewSettingsService(repo core.SettingsRepository, eventBus *events.EventBus) *SettingsService {
+	defaults := make(map[string]string, len(settingDefinitions))
+	for key, definition := range settingDefinitions {
+		defaults[key] = definition.Default
+	}
+
+	return &SettingsService{
+		repo:     repo,
+		eventBus: eventBus,
+		clock:    core.SystemClock{},
+		defaults: defaults,
+	}
+}
+
+// SetDefault replaces a setting's built-in default, e.g. with the deployment's SESSION_TTL
+func (s *SettingsService) SetDefault(key string, value string) error {
+	definition, ok := settingDefinitions[key]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if _, err := parseSettingValue(definition.Type, value); err != nil {
+		return fmt.Errorf("invalid default for %s: %w", key, err)
+	}
+	s.defaults[key] = value
+	return nil
+}
+
+// Run drops the cache whenever any replica announces a settings change, or on resync_required after
+// missing events, until ctx is done
+func (s *SettingsService) Run(ctx context.Context) {
+	for event := range s.eventBus.SubscribeInternal(ctx, "settings-cache") {
+		switch event.Type {
+		case events.EventSettingsUpdated, events.EventResyncRequired:
+			s.invalidate()
+		}
+	}
+}
+
+// Int returns an int setting, or its default when unset or the settings can't be read
+func (s *SettingsService) Int(ctx context.Context, key string) int {
+	value, _ := strconv.Atoi(s.value(ctx, key))
+	return value
+}
+
+// Bool returns a bool setting, or its default when unset or the settings can't be read
+func (s *SettingsService) Bool(ctx context.Context, key string) bool {
+	value, _ := strconv.ParseBool(s.value(ctx, key))
+	return value
+}
+
+// String returns a string setting, or its default when unset or the settings can't be read
+func (s *SettingsService) String(ctx context.Context, key string) string {
+	return s.value(ctx, key)
+}
+
+// Duration returns an int setting counted in seconds as a duration
+func (s *SettingsService) Duration(ctx context.Context, key string) time.Duration {
+	return time.Duration(s.Int(ctx, key)) * time.Second
+}
+
+// List returns every setting, sorted by key
+func (s *SettingsService) List(ctx context.Context) ([]SettingView, error) {
+	overrides, err := s.overrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(settingDefinitions))
+	for key := range settingDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	views := make([]SettingView, len(keys))
+	for i, key := range keys {
+		views[i] = s.view(key, overrides[key])
+	}
+	return views, nil
+}
+
+// Update validates and saves several settings at once (all or nothing), then announces the change.
+// Values may be JSON-decoded (float64, bool, string) or strings such as "45" and "true".
+func (s *SettingsService) Update(ctx context.Context, values map[string]interface{}, actorUserID string) ([]SettingView, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no settings to update")
+	}
+
+	now := s.clock.Now()
+	settings := make([]*core.Setting, 0, len(values))
+	for key, raw := range values {
+		definition, ok := settingDefinitions[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+		value, err := normalizeSettingValue(key, definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, &core.Setting{Key: key, Value: value, UpdatedBy: actorUserID, UpdatedAt: now})
+	}
+
+	if err := s.repo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	changed := make([]SettingView, len(settings))
+	for i, setting := range settings {
+		changed[i] = s.view(setting.Key, setting)
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Key < changed[j].Key })
+	s.eventBus.PublishSettingsUpdated(ctx, events.SettingsUpdatedPayload{Settings: changed})
+
+	return s.List(ctx)
+}
+
+// GetOrderingStatus reads the pause switch from the ordering.* settings
+func (s *SettingsService) GetOrderingStatus(ctx context.Context) (*core.OrderingStatus, error) {
+	overrides, err := s.overrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paused, _ := strconv.ParseBool(s.valueFrom(overrides, SettingOrderingPaused))
+	status := &core.OrderingStatus{
+		Paused:  paused,
+		Message: s.valueFrom(overrides, SettingOrderingMessage),
+	}
+	if setting := overrides[SettingOrderingPaused]; setting != nil {
+		status.UpdatedBy = setting.UpdatedBy
+		status.UpdatedAt = setting.UpdatedAt
+	}
+	return status, nil
+}
+
+// SetOrderingStatus saves the pause switch and its message together
+func (s *SettingsService) SetOrderingStatus(ctx context.Context, status *core.OrderingStatus) error {
+	err := s.repo.Upsert(ctx, []*core.Setting{
+		{Key: SettingOrderingPaused, Value: strconv.FormatBool(status.Paused), UpdatedBy: status.UpdatedBy, UpdatedAt: status.UpdatedAt},
+		{Key: SettingOrderingMessage, Value: status.Message, UpdatedBy: status.UpdatedBy, UpdatedAt: status.UpdatedAt},
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// value returns the current value of key as stored text
+func (s *SettingsService) value(ctx context.Context, key string) string {
+	overrides, err := s.overrides(ctx)
+	if err != nil {
+		log.Printf("Failed to load settings, using default for %s: %v", key, err)
+	}
+	return s.valueFrom(overrides, key)
+}
+
+// valueFrom returns the override of key when it's still valid, otherwise its default
+func (s *SettingsService) valueFrom(overrides map[string]*core.Setting, key string) string {
+	if setting := overrides[key]; setting != nil {
+		if _, err := parseSettingValue(settingDefinitions[key].Type, setting.Value); err == nil {
+			return setting.Value
+		}
+	}
+	return s.defaults[key]
+}
+
+// overrides returns the saved settings by key, reloading them when the cache is empty or stale
+func (s *SettingsService) overrides(ctx context.Context) (map[string]*core.Setting, error) {
+	s.mu.RLock()
+	cache, loadedAt, generation := s.cache, s.loadedAt, s.generation
+	s.mu.RUnlock()
+
+	if cache != nil && s.clock.Now().Sub(loadedAt) < settingsCacheTTL {
+		return cache, nil
+	}
+
+	settings, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache = make(map[string]*core.Setting, len(settings))
+	for _, setting := range settings {
+		cache[setting.Key] = setting
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.cache = cache
+		s.loadedAt = s.clock.Now()
+	}
+	s.mu.Unlock()
+
+	return cache, nil
+}
+
+// invalidate drops the cache so the next read loads the settings table
+func (s *SettingsService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = nil
+	s.generation++
+}
+
+// view builds the manager-facing form of a setting; override is nil while the default applies
+func (s *SettingsService) view(key string, override *core.Setting) SettingView {
+	definition := settingDefinitions[key]
+	value, _ := parseSettingValue(definition.Type, s.valueFrom(map[string]*core.Setting{key: override}, key))
+	defaultValue, _ := parseSettingValue(definition.Type, s.defaults[key])
+
+	view := SettingView{
+		Key:         key,
+		Type:        definition.Type,
+		Value:       value,
+		Default:     defaultValue,
+		Description: definition.Description,
+	}
+	if definition.Type == settingTypeInt {
+		view.Min, view.Max = &definition.Min, &definition.Max
+	}
+	if override != nil {
+		updatedAt := override.UpdatedAt
+		view.UpdatedBy = override.UpdatedBy
+		view.UpdatedAt = &updatedAt
+	}
+	return view
+}
+
+// parseSettingValue converts stored text to the setting's Go type
+func parseSettingValue(settingType string, value string) (interface{}, error) {
+	switch settingType {
+	case settingTypeInt:
+		return strconv.Atoi(value)
+	case settingTypeBool:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// normalizeSettingValue validates a new value for key and returns it as stored text
+func normalizeSettingValue(key string, definition settingDefinition, raw interface{}) (string, error) {
+	switch definition.Type {
+	case settingTypeInt:
+		var number int
+		switch value := raw.(type) {
+		case float64:
+			if value != math.Trunc(value) || math.Abs(value) > math.MaxInt32 {
+				return "", fmt.Errorf("%s must be a whole number", key)
+			}
+			number = int(value)
+		case string:
+			parsed, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return "", fmt.Errorf("%s must be a whole number", key)
+			}
+			number = parsed
+		default:
+			return "", fmt.Errorf("%s must be a whole number", key)
+		}
+		if number < definition.Min || number > definition.Max {
+			return "", fmt.Errorf("%s must be between %d and %d", key, definition.Min, definition.Max)
+		}
+		return strconv.Itoa(number), nil
+
+	case settingTypeBool:
+		switch value := raw.(type) {
+		case bool:
+			return strconv.FormatBool(value), nil
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return "", fmt.Errorf("%s must be true or false", key)
+			}
+			return strconv.FormatBool(parsed), nil
+		}
+		return "", fmt.Errorf("%s must be true or false", key)
+
+	default:
+		value, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("%s must be a string", key)
+		}
+		value = strings.TrimSpace(value)
+		if definition.MaxLength > 0 && len(value) > definition.MaxLength {
+			return "", fmt.Errorf("%s must be at most %d characters", key, definition.MaxLength)
+		}
+		if definition.Validate != nil {
+			if err := definition.Validate(value); err != nil {
+				return "", fmt.Errorf("%s %w", key, err)
+			}
+		}
+		return value, nil
+	}
+}
+
+// SetSettingsService wires the runtime settings behind /api/admin/settings and the report day start
+func (s *DashboardService) SetSettingsService(settings *SettingsService) {
+	s.settings = settings
+}
+
+// ListSettings returns every runtime setting with its current value
+func (s *DashboardService) ListSettings(ctx context.Context) ([]SettingView, error) {
+	if s.settings == nil {
+		return nil, fmt.Errorf("settings not configured")
+	}
+	return s.settings.List(ctx)
+}
+
+// UpdateSettings changes runtime settings and returns the full list
+func (s *DashboardService) UpdateSettings(ctx context.Context, values map[string]interface{}, actorUserID string) ([]SettingView, error) {
+	if s.settings == nil {
+		return nil, fmt.Errorf("settings not configured")
+	}
+	return s.settings.Update(ctx, values, actorUserID)
+}
+
+// businessDayStartHour is the hour (EAT) business days start for reports and analytics
+func (s *DashboardService) businessDayStartHour(ctx context.Context) int {
+	if s.settings == nil {
+		return defaultBusinessDayStartHourEAT
+	}
+	return s.settings.Int(ctx, SettingBusinessDayStartHour)
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// ShiftService clocks bartenders in and out, from the dashboard or by WhatsApp ("clock in", "clock out").
+// Clocking in puts the bartender on shift for paid-order notifications; clocking out takes them off
+// and sends managers the shift's sales.
+type ShiftService struct {
+	shifts   core.ShiftRepository
+	staff    core.BarStaffRepository
+	admins   core.AdminUserRepository
+	whatsapp core.WhatsAppGateway
+	clock    core.Clock
+	ids      core.IDGenerator
+}
+
+// NewShiftService creates the shift service
+func NewShiftService(shifts core.ShiftRepository, staff core.BarStaffRepository, admins core.AdminUserRepository, whatsapp core.WhatsAppGateway) *ShiftService {
+	return &ShiftService{
+		shifts:   shifts,
+		staff:    staff,
+		admins:   admins,
+		whatsapp: whatsapp,
+		clock:    core.SystemClock{},
+		ids:      core.UUIDGenerator{},
+	}
+}
+
+// ClockIn opens a shift for a bartender and puts them on shift
+func (s *ShiftService) ClockIn(ctx context.Context, staffID string, via string) (*core.Shift, error) {
+	staff, err := s.staff.GetByID(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if !staff.IsActive {
+		return nil, fmt.Errorf("bar staff member is inactive")
+	}
+
+	shift := &core.Shift{
+		ID:        s.ids.NewID(),
+		StaffID:   staff.ID,
+		StaffName: staff.Name,
+		StartedAt: s.clock.Now(),
+		OpenedVia: via,
+	}
+	if err := s.shifts.Open(ctx, shift); err != nil {
+		return nil, err
+	}
+
+	if !staff.IsOnShift {
+		staff.IsOnShift = true
+		if err := s.staff.Update(ctx, staff); err != nil {
+			log.Printf("Failed to put %s on shift after clock in: %v", staff.Name, err)
+		}
+	}
+	return shift, nil
+}
+
+// ClockOut closes a bartender's open shift, takes them off shift and sends managers the summary
+func (s *ShiftService) ClockOut(ctx context.Context, staffID string, via string) (*core.ShiftReport, error) {
+	shift, err := s.shifts.Close(ctx, staffID, s.clock.Now(), via)
+	if err != nil {
+		return nil, err
+	}
+
+	if staff, err := s.staff.GetByID(ctx, staffID); err == nil && staff.IsOnShift {
+		staff.IsOnShift = false
+		if err := s.staff.Update(ctx, staff); err != nil {
+			log.Printf("Failed to take %s off shift after clock out: %v", staff.Name, err)
+		}
+	}
+
+	report, err := s.report(ctx, shift)
+	if err != nil {
+		return nil, err
+	}
+	s.sendSummary(ctx, report)
+	return report, nil
+}
+
+// Report returns a shift with the sales made while it ran (up to now for a running shift)
+func (s *ShiftService) Report(ctx context.Context, shiftID string) (*core.ShiftReport, error) {
+	shift, err := s.shifts.GetByID(ctx, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(ctx, shift)
+}
+
+// List retrieves shifts, newest first
+func (s *ShiftService) List(ctx context.Context, filter core.ShiftFilter) ([]*core.Shift, error) {
+	return s.shifts.List(ctx, filter)
+}
+
+func (s *ShiftService) report(ctx context.Context, shift *core.Shift) (*core.ShiftReport, error) {
+	end := s.clock.Now()
+	if shift.EndedAt != nil {
+		end = *shift.EndedAt
+	}
+
+	sales, err := s.shifts.GetSales(ctx, shift.StartedAt, end, shift.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	sales.Revenue = roundCents(sales.Revenue)
+	sales.Tips = roundCents(sales.Tips)
+	sales.CashCollected = roundCents(sales.CashCollected)
+	sales.CardCollected = roundCents(sales.CardCollected)
+	sales.MpesaCollected = roundCents(sales.MpesaCollected)
+
+	return &core.ShiftReport{
+		Shift:    shift,
+		Sales:    sales,
+		Duration: int(end.Sub(shift.StartedAt) / time.Second),
+	}, nil
+}
+
+// sendSummary sends a closed shift's sales to every active manager
+func (s *ShiftService) sendSummary(ctx context.Context, report *core.ShiftReport) {
+	managers, err := s.admins.GetActiveByRole(ctx, core.AdminRoleManager)
+	if err != nil {
+		log.Printf("Failed to load managers for shift summary %s: %v", report.Shift.ID, err)
+		return
+	}
+
+	message := formatShiftSummary(report)
+	for _, manager := range managers {
+		if err := s.whatsapp.SendText(ctx, manager.PhoneNumber, message); err != nil {
+			log.Printf("Failed to send shift summary %s to %s: %v", report.Shift.ID, manager.Name, err)
+		}
+	}
+}
+
+// HandleCommand handles "clock in" and "clock out" from a bartender on the roster. It returns false
+// when the message isn't a shift command or the sender isn't bar staff, so the bot handles it instead.
+func (s *ShiftService) HandleCommand(ctx context.Context, phone string, message string) bool {
+	clockIn, ok := parseShiftCommand(message)
+	if !ok {
+		return false
+	}
+	staff, err := s.staff.GetByPhone(ctx, phone)
+	if err != nil || !staff.IsActive {
+		return false
+	}
+
+	var reply string
+	if clockIn {
+		shift, err := s.ClockIn(ctx, staff.ID, core.ShiftViaWhatsApp)
+		switch {
+		case err != nil && strings.Contains(err.Error(), "already clocked in"):
+			reply = "ℹ️ You're already clocked in. Send *clock out* at the end of your shift."
+		case err != nil:
+			log.Printf("Failed to clock in %s: %v", staff.Name, err)
+			reply = "❌ Couldn't clock you in, please try again or ask a manager."
+		default:
+			reply = fmt.Sprintf("🟢 Clocked in at %s. You'll get paid orders until you send *clock out*.",
+				shift.StartedAt.In(reportLocation()).Format("15:04"))
+		}
+	} else {
+		report, err := s.ClockOut(ctx, staff.ID, core.ShiftViaWhatsApp)
+		switch {
+		case err != nil && strings.Contains(err.Error(), "not clocked in"):
+			reply = "ℹ️ You're not clocked in."
+		case err != nil:
+			log.Printf("Failed to clock out %s: %v", staff.Name, err)
+			reply = "❌ Couldn't clock you out, please try again or ask a manager."
+		default:
+			reply = fmt.Sprintf("🔴 Clocked out after %s. Orders this shift: %d (%d accepted by you).",
+				formatShiftDuration(time.Duration(report.Duration)*time.Second), report.Sales.Orders, report.Sales.OrdersAccepted)
+		}
+	}
+
+	if err := s.whatsapp.SendText(ctx, phone, reply); err != nil {
+		log.Printf("Failed to reply to shift command from %s: %v", staff.Name, err)
+	}
+	return true
+}
+
+// parseShiftCommand recognises "clock in"/"clockin" and "clock out"/"clockout"
+func parseShiftCommand(message string) (clockIn bool, ok bool) {
+	switch strings.Join(strings.Fields(strings.ToLower(message)), " ") {
+	case "clock in", "clockin":
+		return true, true
+	case "clock out", "clockout":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func formatShiftSummary(report *core.ShiftReport) string {
+	loc := reportLocation()
+	shift := report.Shift
+	end := shift.StartedAt.Add(time.Duration(report.Duration) * time.Second)
+	sales := report.Sales
+
+	return fmt.Sprintf("🧾 *Shift closed: %s*\n%s – %s (%s)\n\n*Orders:* %d (%d accepted by %s)\n*Revenue:* KES %.0f\n*Cash:* KES %.0f\n*Card:* KES %.0f\n*M-Pesa:* KES %.0f\n*Tips:* KES %.0f",
+		shift.StaffName,
+		shift.StartedAt.In(loc).Format("Mon 15:04"), end.In(loc).Format("15:04"),
+		formatShiftDuration(time.Duration(report.Duration)*time.Second),
+		sales.Orders, sales.OrdersAccepted, shift.StaffName,
+		sales.Revenue, sales.CashCollected, sales.CardCollected, sales.MpesaCollected, sales.Tips)
+}
+
+// formatShiftDuration renders a shift length as "7h 45m"
+func formatShiftDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// SetShiftService wires bartender clock in/out and shift reports
+func (s *DashboardService) SetShiftService(shifts *ShiftService) {
+	s.shifts = shifts
+}
+
+// ShiftQuery holds the raw shift list filters accepted by the admin API
+type ShiftQuery struct {
+	StaffID string
+	From    string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	To      string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	Limit   int
+}
+
+// ListShifts retrieves shifts started in the query's date range, newest first
+func (s *DashboardService) ListShifts(ctx context.Context, query ShiftQuery) ([]*core.Shift, error) {
+	if s.shifts == nil {
+		return nil, fmt.Errorf("shifts not configured")
+	}
+
+	filter := core.ShiftFilter{StaffID: strings.TrimSpace(query.StaffID), Limit: query.Limit}
+	loc := reportLocation()
+	if from := strings.TrimSpace(query.From); from != "" {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+		}
+		filter.From = &start
+	}
+	if to := strings.TrimSpace(query.To); to != "" {
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	return s.shifts.List(ctx, filter)
+}
+
+// ClockIn clocks a bartender in from the dashboard
+func (s *DashboardService) ClockIn(ctx context.Context, staffID string) (*core.Shift, error) {
+	if s.shifts == nil {
+		return nil, fmt.Errorf("shifts not configured")
+	}
+	return s.shifts.ClockIn(ctx, staffID, core.ShiftViaDashboard)
+}
+
+// ClockOut clocks a bartender out from the dashboard and returns the closed shift's report
+func (s *DashboardService) ClockOut(ctx context.Context, staffID string) (*core.ShiftReport, error) {
+	if s.shifts == nil {
+		return nil, fmt.Errorf("shifts not configured")
+	}
+	return s.shifts.ClockOut(ctx, staffID, core.ShiftViaDashboard)
+}
+
+// GetShiftReport returns a shift's sales: orders, revenue, cash/card/M-Pesa collected and tips
+func (s *DashboardService) GetShiftReport(ctx context.Context, shiftID string) (*core.ShiftReport, error) {
+	if s.shifts == nil {
+		return nil, fmt.Errorf("shifts not configured")
+	}
+	return s.shifts.Report(ctx, shiftID)
+}
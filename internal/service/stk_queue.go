@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// SetSTKPushQueue wires the persistent STK push queue so the dashboard can inspect dead letters
+func (s *DashboardService) SetSTKPushQueue(queue core.STKPushQueue) {
+	s.stkQueue = queue
+}
+
+// ListSTKPushDeadLetters retrieves STK pushes that failed after all retries, newest first
+func (s *DashboardService) ListSTKPushDeadLetters(ctx context.Context, limit int) ([]*core.STKPushJob, error) {
+	if s.stkQueue == nil {
+		return nil, fmt.Errorf("stk push queue not configured")
+	}
+	return s.stkQueue.ListDeadLetters(ctx, limit)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// defaultLowStockThreshold applies when the inventory.low_stock_threshold setting isn't available
+const defaultLowStockThreshold = 5
+
+// stockAdjustmentDirections lists the reasons a manager can give for adjusting stock by a delta, with the
+// sign the delta must have (0 for either way). Setting an absolute number is a correction, done through
+// UpdateStock.
+var stockAdjustmentDirections = map[string]int{
+	core.StockAdjustmentSaleCorrection: 0,
+	core.StockAdjustmentBreakage:       -1,
+	core.StockAdjustmentRestock:        1,
+	core.StockAdjustmentTheft:          -1,
+}
+
+// AdjustStock adds delta to a product's stock (negative to remove), records it in stock_adjustments with
+// the reason and who made it, emits stock_updated (and low_stock) and returns the new balance
+func (s *DashboardService) AdjustStock(ctx context.Context, productID string, delta int, reason string, actorUserID string) (*core.StockAdjustment, error) {
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	direction, ok := stockAdjustmentDirections[reason]
+	if !ok {
+		return nil, fmt.Errorf("invalid reason, expected sale_correction, breakage, restock or theft")
+	}
+	if delta == 0 {
+		return nil, fmt.Errorf("delta is required")
+	}
+	if direction < 0 && delta > 0 {
+		return nil, fmt.Errorf("invalid delta: %s removes stock, so delta must be negative", reason)
+	}
+	if direction > 0 && delta < 0 {
+		return nil, fmt.Errorf("invalid delta: %s adds stock, so delta must be positive", reason)
+	}
+
+	adjustment, err := s.productRepo.AdjustStock(ctx, productID, delta, reason, actorUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishStockChange(ctx, *adjustment)
+	return adjustment, nil
+}
+
+// PublishOrderDrawdown is the outbox handler for order_paid.stock: it publishes the ingredient stock
+// changes recorded when the order was paid
+func (s *DashboardService) PublishOrderDrawdown(ctx context.Context, message *core.OutboxMessage) error {
+	if s.recipeRepo == nil {
+		return fmt.Errorf("recipes not configured")
+	}
+
+	adjustments, err := s.recipeRepo.GetOrderDrawdown(ctx, message.OrderID)
+	if err != nil {
+		return err
+	}
+	for _, adjustment := range adjustments {
+		s.publishStockChange(ctx, adjustment)
+	}
+	return nil
+}
+
+// publishStockChange emits stock_updated for an adjustment, and low_stock when it took the product from
+// above the inventory.low_stock_threshold setting to at or below it. Every stock change goes out through
+// here, so the dashboard sees manual, stocktake, purchase order, import and recipe changes alike.
+func (s *DashboardService) publishStockChange(ctx context.Context, adjustment core.StockAdjustment) {
+	s.eventBus.PublishStockUpdated(ctx, adjustment.ProductID, adjustment.NewQuantity)
+
+	threshold := s.lowStockThreshold(ctx)
+	if adjustment.OldQuantity > threshold && adjustment.NewQuantity <= threshold {
+		s.eventBus.PublishLowStock(ctx, adjustment.ProductID, adjustment.NewQuantity, threshold)
+	}
+}
+
+// lowStockThreshold is the stock level at or below which a product counts as running low
+func (s *DashboardService) lowStockThreshold(ctx context.Context) int {
+	if s.settings == nil {
+		return defaultLowStockThreshold
+	}
+	return s.settings.Int(ctx, SettingLowStockThreshold)
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+	"github.com/dumu-tech/destination-cocktails/internal/testkit"
+)
+
+// drawdownRecipes is a recipe repository holding only the stock each order's recipes took
+type drawdownRecipes struct {
+	core.RecipeRepository
+	drawdown map[string][]core.StockAdjustment
+}
+
+func (r drawdownRecipes) GetOrderDrawdown(ctx context.Context, orderID string) ([]core.StockAdjustment, error) {
+	return r.drawdown[orderID], nil
+}
+
+func TestOrderDrawdownPublishesStockEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := events.NewEventBus()
+	received := bus.Subscribe(ctx, "dashboard")
+	clock := testkit.NewFakeClock(testkit.Epoch)
+	ids := &testkit.SequenceIDGenerator{}
+	dashboard := service.NewDashboardService(nil, nil, testkit.NewProductRepository(clock, ids), testkit.NewOrderRepository(clock, ids), nil, testkit.NewWhatsAppGateway(), bus, "secret")
+	dashboard.SetRecipeRepository(drawdownRecipes{drawdown: map[string][]core.StockAdjustment{
+		"order-1": {
+			{ProductID: "rum", OldQuantity: 6, NewQuantity: 5, Reason: core.StockAdjustmentRecipe},  // Falls to the default threshold of 5
+			{ProductID: "soda", OldQuantity: 4, NewQuantity: 3, Reason: core.StockAdjustmentRecipe}, // Already low
+		},
+	}})
+
+	if err := dashboard.PublishOrderDrawdown(ctx, &core.OutboxMessage{Topic: core.OutboxTopicPaidStock, OrderID: "order-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		eventType events.EventType
+		productID string
+	}{
+		{events.EventStockUpdated, "rum"},
+		{events.EventLowStock, "rum"},
+		{events.EventStockUpdated, "soda"},
+	}
+	for _, w := range want {
+		event := <-received
+		var productID string
+		switch data := event.Data.(type) {
+		case events.StockUpdatedPayload:
+			productID = data.ProductID
+		case events.LowStockPayload:
+			productID = data.ProductID
+		}
+		if event.Type != w.eventType || productID != w.productID {
+			t.Fatalf("got %s for %q, want %s for %s", event.Type, productID, w.eventType, w.productID)
+		}
+	}
+	select {
+	case event := <-received:
+		t.Errorf("unexpected %s %+v", event.Type, event.Data)
+	default:
+	}
+}
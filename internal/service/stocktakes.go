@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// maxStocktakeNoteLength keeps the note to a line or two ("Friday close", "after delivery")
+const maxStocktakeNoteLength = 200
+
+// StocktakeCountInput is one product's counted quantity
+type StocktakeCountInput struct {
+	ProductID       string
+	CountedQuantity int
+}
+
+// SetStocktakeRepository wires the stocktakes used by the inventory count endpoints
+func (s *DashboardService) SetStocktakeRepository(stocktakeRepo core.StocktakeRepository) {
+	s.stocktakeRepo = stocktakeRepo
+}
+
+// StartStocktake opens a count session; only one can be open at a time
+func (s *DashboardService) StartStocktake(ctx context.Context, note string, actorUserID string) (*core.Stocktake, error) {
+	if s.stocktakeRepo == nil {
+		return nil, fmt.Errorf("stocktakes not configured")
+	}
+
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > maxStocktakeNoteLength {
+		return nil, fmt.Errorf("note must be at most %d characters", maxStocktakeNoteLength)
+	}
+
+	stocktake := &core.Stocktake{
+		ID:        s.ids.NewID(),
+		Status:    core.StocktakeOpen,
+		Note:      note,
+		StartedBy: actorUserID,
+		Lines:     []core.StocktakeLine{},
+		CreatedAt: s.clock.Now(),
+	}
+	if err := s.stocktakeRepo.Create(ctx, stocktake); err != nil {
+		return nil, err
+	}
+	return stocktake, nil
+}
+
+// ListStocktakes retrieves the most recent stocktakes, newest first
+func (s *DashboardService) ListStocktakes(ctx context.Context, limit int) ([]*core.Stocktake, error) {
+	if s.stocktakeRepo == nil {
+		return nil, fmt.Errorf("stocktakes not configured")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.stocktakeRepo.GetAll(ctx, limit)
+}
+
+// GetStocktake retrieves a stocktake with every line counted so far
+func (s *DashboardService) GetStocktake(ctx context.Context, id string) (*core.Stocktake, error) {
+	if s.stocktakeRepo == nil {
+		return nil, fmt.Errorf("stocktakes not configured")
+	}
+	return s.stocktakeRepo.GetByID(ctx, id)
+}
+
+// RecordStocktakeCounts saves counted quantities line by line; recounting a product replaces its line
+func (s *DashboardService) RecordStocktakeCounts(ctx context.Context, id string, counts []StocktakeCountInput, actorUserID string) ([]core.StocktakeLine, error) {
+	if s.stocktakeRepo == nil {
+		return nil, fmt.Errorf("stocktakes not configured")
+	}
+	if len(counts) == 0 {
+		return nil, fmt.Errorf("at least one count is required")
+	}
+	for _, count := range counts {
+		if strings.TrimSpace(count.ProductID) == "" {
+			return nil, fmt.Errorf("count product_id is required")
+		}
+		if count.CountedQuantity < 0 {
+			return nil, fmt.Errorf("counted_quantity must not be negative")
+		}
+	}
+
+	lines := make([]core.StocktakeLine, 0, len(counts))
+	for _, count := range counts {
+		line, err := s.stocktakeRepo.RecordCount(ctx, id, strings.TrimSpace(count.ProductID), count.CountedQuantity, actorUserID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, *line)
+	}
+	return lines, nil
+}
+
+// ApplyStocktake adjusts stock by each counted line's variance and closes the stocktake.
+// Every change is recorded in stock_adjustments and pushed to the dashboard as a stock update.
+func (s *DashboardService) ApplyStocktake(ctx context.Context, id string, actorUserID string) ([]core.StockAdjustment, error) {
+	if s.stocktakeRepo == nil {
+		return nil, fmt.Errorf("stocktakes not configured")
+	}
+
+	adjustments, err := s.stocktakeRepo.Apply(ctx, id, actorUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, adjustment := range adjustments {
+		s.publishStockChange(ctx, adjustment)
+	}
+	if adjustments == nil {
+		adjustments = []core.StockAdjustment{}
+	}
+	return adjustments, nil
+}
+
+// CancelStocktake abandons an open stocktake without changing stock
+func (s *DashboardService) CancelStocktake(ctx context.Context, id string) error {
+	if s.stocktakeRepo == nil {
+		return fmt.Errorf("stocktakes not configured")
+	}
+	return s.stocktakeRepo.Cancel(ctx, id)
+}
+
+// StocktakeVariance reports the lines whose count differed from system stock, valued at menu price,
+// with the biggest losses first
+func (s *DashboardService) StocktakeVariance(ctx context.Context, id string) (*core.StocktakeVariance, error) {
+	if s.stocktakeRepo == nil {
+		return nil, fmt.Errorf("stocktakes not configured")
+	}
+
+	stocktake, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &core.StocktakeVariance{
+		Stocktake:    stocktake,
+		Lines:        []core.StocktakeLine{},
+		LinesCounted: len(stocktake.Lines),
+	}
+	for _, line := range stocktake.Lines {
+		if line.Variance == 0 {
+			continue
+		}
+		report.Lines = append(report.Lines, line)
+
+		value := float64(line.Variance) * line.UnitPrice
+		if line.Variance < 0 {
+			report.UnitsShort -= line.Variance
+			report.ValueShort -= value
+		} else {
+			report.UnitsOver += line.Variance
+			report.ValueOver += value
+		}
+	}
+
+	sort.SliceStable(report.Lines, func(i, j int) bool {
+		return float64(report.Lines[i].Variance)*report.Lines[i].UnitPrice < float64(report.Lines[j].Variance)*report.Lines[j].UnitPrice
+	})
+
+	report.ValueShort = math.Round(report.ValueShort*100) / 100
+	report.ValueOver = math.Round(report.ValueOver*100) / 100
+	report.NetValue = math.Round((report.ValueOver-report.ValueShort)*100) / 100
+	// The lines are in the report already; don't send them twice
+	report.Stocktake.Lines = nil
+	return report, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+const (
+	// suggestionWindow is how far back orders count towards "people also add"
+	suggestionWindow = 90 * 24 * time.Hour
+	// suggestionCacheTTL bounds how often one product's co-purchases are counted
+	suggestionCacheTTL = time.Hour
+	// minCoPurchaseOrders keeps one-off combinations from being suggested
+	minCoPurchaseOrders = 3
+)
+
+// Suggester picks the products customers most often buy alongside another one, from settled orders
+// in the last 90 days. Counts are cached per product for an hour, since they change slowly and the
+// bot asks on every cocktail added.
+type Suggester struct {
+	analytics core.AnalyticsRepository
+	clock     core.Clock
+
+	mu    sync.RWMutex
+	cache map[string]*cachedSuggestions
+}
+
+type cachedSuggestions struct {
+	suggestions []*core.CoPurchase
+	loadedAt    time.Time
+}
+
+// NewSuggester creates a suggester reading co-purchases from analytics
+func NewSuggester(analytics core.AnalyticsRepository) *Suggester {
+	return &Suggester{
+		analytics: analytics,
+		clock:     core.SystemClock{},
+		cache:     make(map[string]*cachedSuggestions),
+	}
+}
+
+// Suggest returns up to limit products in category bought with productID in at least
+// minCoPurchaseOrders orders, most often first
+func (s *Suggester) Suggest(ctx context.Context, productID string, category string, limit int) ([]*core.CoPurchase, error) {
+	key := productID + "|" + category
+	now := s.clock.Now()
+
+	s.mu.RLock()
+	cached := s.cache[key]
+	s.mu.RUnlock()
+
+	if cached == nil || now.Sub(cached.loadedAt) >= suggestionCacheTTL {
+		purchases, err := s.analytics.GetCoPurchases(ctx, productID, category, now.Add(-suggestionWindow), maxListRows)
+		if err != nil {
+			return nil, err
+		}
+		suggestions := make([]*core.CoPurchase, 0, len(purchases))
+		for _, purchase := range purchases {
+			if purchase.Orders >= minCoPurchaseOrders {
+				suggestions = append(suggestions, purchase)
+			}
+		}
+
+		cached = &cachedSuggestions{suggestions: suggestions, loadedAt: now}
+		s.mu.Lock()
+		s.cache[key] = cached
+		s.mu.Unlock()
+	}
+
+	if len(cached.suggestions) < limit {
+		return cached.suggestions, nil
+	}
+	return cached.suggestions[:limit], nil
+}
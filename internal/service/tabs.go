@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// TabSummary is an open tab as shown on the dashboard, with its totals at menu prices
+type TabSummary struct {
+	*core.Tab
+	Total       float64 `json:"total"`        // Every item on the tab
+	UnpaidTotal float64 `json:"unpaid_total"` // Items not yet on a pending or paid order
+}
+
+// SetTabRepository enables the open tabs view
+func (s *DashboardService) SetTabRepository(tabRepo core.TabRepository) {
+	s.tabRepo = tabRepo
+}
+
+// ListOpenTabs returns every open tab by table number
+func (s *DashboardService) ListOpenTabs(ctx context.Context) ([]TabSummary, error) {
+	if s.tabRepo == nil {
+		return nil, fmt.Errorf("tabs not configured")
+	}
+
+	tabs, err := s.tabRepo.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]TabSummary, len(tabs))
+	for i, tab := range tabs {
+		summaries[i] = TabSummary{
+			Tab:         tab,
+			Total:       tabItemsSubtotal(tab.Items),
+			UnpaidTotal: tabItemsSubtotal(tab.UnpaidItems("")),
+		}
+	}
+	return summaries, nil
+}
+
+// CloseTab closes a tab, e.g. when the table has left; items nobody paid for stay listed on it
+func (s *DashboardService) CloseTab(ctx context.Context, tabID string) error {
+	if s.tabRepo == nil {
+		return fmt.Errorf("tabs not configured")
+	}
+	return s.tabRepo.Close(ctx, tabID)
+}
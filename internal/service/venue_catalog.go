@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// VenueCatalog is a ProductRepository decorator for the bot that limits the menu, category lists and
+// search results to the categories of the WhatsApp number the customer is messaging. Numbers without
+// categories, and the default number, offer the whole menu. Other reads and all writes pass through.
+type VenueCatalog struct {
+	core.ProductRepository
+	numbers *WhatsAppNumbers
+}
+
+// NewVenueCatalog wraps repo so each venue's number sees only its own categories
+func NewVenueCatalog(repo core.ProductRepository, numbers *WhatsAppNumbers) *VenueCatalog {
+	return &VenueCatalog{ProductRepository: repo, numbers: numbers}
+}
+
+// GetMenu returns the menu grouped by category, without the categories the venue doesn't offer
+func (c *VenueCatalog) GetMenu(ctx context.Context) (map[string][]*core.Product, error) {
+	menu, err := c.ProductRepository.GetMenu(ctx)
+	allowed := c.allowed(ctx)
+	if err != nil || allowed == nil {
+		return menu, err
+	}
+
+	filtered := make(map[string][]*core.Product, len(menu))
+	for category, products := range menu {
+		if allowed[strings.ToLower(category)] {
+			filtered[category] = products
+		}
+	}
+	return filtered, nil
+}
+
+// GetByCategory returns no products for a category the venue doesn't offer
+func (c *VenueCatalog) GetByCategory(ctx context.Context, category string) ([]*core.Product, error) {
+	if allowed := c.allowed(ctx); allowed != nil && !allowed[strings.ToLower(category)] {
+		return []*core.Product{}, nil
+	}
+	return c.ProductRepository.GetByCategory(ctx, category)
+}
+
+// SearchProducts drops matches in categories the venue doesn't offer
+func (c *VenueCatalog) SearchProducts(ctx context.Context, query string) ([]*core.Product, error) {
+	products, err := c.ProductRepository.SearchProducts(ctx, query)
+	allowed := c.allowed(ctx)
+	if err != nil || allowed == nil {
+		return products, err
+	}
+
+	filtered := make([]*core.Product, 0, len(products))
+	for _, product := range products {
+		if allowed[strings.ToLower(product.Category)] {
+			filtered = append(filtered, product)
+		}
+	}
+	return filtered, nil
+}
+
+// allowed returns the venue's categories, lowercased, or nil when the whole menu is offered
+func (c *VenueCatalog) allowed(ctx context.Context) map[string]bool {
+	categories := c.numbers.Categories(ctx)
+	if categories == nil {
+		return nil
+	}
+	allowed := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		allowed[strings.ToLower(category)] = true
+	}
+	return allowed
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// WebhookEventQuery holds the raw webhook archive filters accepted by the admin API
+type WebhookEventQuery struct {
+	Source         string // whatsapp or payment
+	SignatureValid string // true or false
+	StatusCode     int
+	Search         string // Substring of the raw body
+	From           string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	To             string // YYYY-MM-DD, inclusive (Africa/Nairobi)
+	Limit          int
+}
+
+// SetWebhookEventRepository wires the webhook archive read by the webhooks endpoint
+func (s *DashboardService) SetWebhookEventRepository(webhookRepo core.WebhookEventRepository) {
+	s.webhookRepo = webhookRepo
+}
+
+// ListWebhookEvents retrieves archived webhooks matching the query, newest first
+func (s *DashboardService) ListWebhookEvents(ctx context.Context, query WebhookEventQuery) ([]*core.WebhookEvent, error) {
+	if s.webhookRepo == nil {
+		return nil, fmt.Errorf("webhook archive not configured")
+	}
+
+	filter := core.WebhookEventFilter{
+		Source:     strings.ToLower(strings.TrimSpace(query.Source)),
+		StatusCode: query.StatusCode,
+		Search:     strings.TrimSpace(query.Search),
+		Limit:      query.Limit,
+	}
+	if filter.Source != "" && filter.Source != core.WebhookSourceWhatsApp && filter.Source != core.WebhookSourcePayment {
+		return nil, fmt.Errorf("invalid source: use whatsapp or payment")
+	}
+	if raw := strings.TrimSpace(query.SignatureValid); raw != "" {
+		valid, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature_valid: use true or false")
+		}
+		filter.SignatureValid = &valid
+	}
+
+	loc := reportLocation()
+	if from := strings.TrimSpace(query.From); from != "" {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for from: use YYYY-MM-DD")
+		}
+		filter.From = &start
+	}
+	if to := strings.TrimSpace(query.To); to != "" {
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format for to: use YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	return s.webhookRepo.List(ctx, filter)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/events"
+)
+
+// whatsAppNumbersCacheTTL bounds how stale a replica's numbers get if it misses a whatsapp_numbers_updated event
+const whatsAppNumbersCacheTTL = time.Minute
+
+// WhatsAppNumbers routes customers between the WhatsApp numbers pointed at this server. Inbound messages
+// carry the number they arrived on in their context (core.WithWhatsAppNumber); replies, and later messages to the same customer,
+// are sent with that number's token, and the bot offers only its venue's categories. With no numbers
+// registered everything goes through WHATSAPP_PHONE_NUMBER_ID as before, without touching the database.
+type WhatsAppNumbers struct {
+	repo     core.WhatsAppNumberRepository
+	eventBus *events.EventBus
+	clock    core.Clock
+
+	mu         sync.RWMutex
+	cache      map[string]*core.WhatsAppNumber
+	loadedAt   time.Time
+	generation int // Bumped on invalidation so a load that raced with it isn't cached
+}
+
+// NewWhatsAppNumbers creates the number directory
+func NewWhatsAppNumbers(repo core.WhatsAppNumberRepository, eventBus *events.EventBus) *WhatsAppNumbers {
+	return &WhatsAppNumbers{
+		repo:     repo,
+		eventBus: eventBus,
+		clock:    core.SystemClock{},
+	}
+}
+
+// Run drops the cache whenever any replica changes a number, or on resync_required after missing events,
+// until ctx is done
+func (n *WhatsAppNumbers) Run(ctx context.Context) {
+	for event := range n.eventBus.SubscribeInternal(ctx, "whatsapp-numbers-cache") {
+		switch event.Type {
+		case events.EventWhatsAppNumbersUpdated, events.EventResyncRequired:
+			n.invalidate()
+		}
+	}
+}
+
+// RouteInbound remembers phoneNumberID as the number phone messages, for messages sent to them outside a
+// conversation. False means the number is registered but inactive and the message should be ignored.
+func (n *WhatsAppNumbers) RouteInbound(ctx context.Context, phoneNumberID string, phone string) bool {
+	numbers := n.numbers(ctx)
+	if len(numbers) == 0 || phoneNumberID == "" {
+		return true
+	}
+
+	if number := numbers[phoneNumberID]; number != nil && !number.Active {
+		return false
+	}
+	// Unregistered numbers are recorded too, so a customer who moves back to the default number is answered from it
+	if err := n.repo.SetContactNumber(ctx, phone, phoneNumberID); err != nil {
+		log.Printf("Failed to record WhatsApp number for %s: %v", phone, err)
+	}
+	return true
+}
+
+// ResolveSender implements core.WhatsAppSenderResolver: the number the message being answered arrived on,
+// otherwise the number the customer last messaged, when it's registered and active
+func (n *WhatsAppNumbers) ResolveSender(ctx context.Context, to string) (string, string, bool) {
+	number := n.current(ctx, to)
+	if number == nil {
+		return "", "", false
+	}
+	return number.PhoneNumberID, number.AccessToken, true
+}
+
+// Categories returns the menu categories offered to the customer in ctx, or nil for the whole menu
+func (n *WhatsAppNumbers) Categories(ctx context.Context) []string {
+	number := n.current(ctx, "")
+	if number == nil || len(number.Categories) == 0 {
+		return nil
+	}
+	return number.Categories
+}
+
+// current returns the active registered number for ctx, falling back to the one phone last messaged
+func (n *WhatsAppNumbers) current(ctx context.Context, phone string) *core.WhatsAppNumber {
+	numbers := n.numbers(ctx)
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	phoneNumberID := core.WhatsAppNumberFrom(ctx)
+	if phoneNumberID == "" && phone != "" {
+		var err error
+		if phoneNumberID, err = n.repo.GetContactNumber(ctx, phone); err != nil {
+			log.Printf("Failed to look up WhatsApp number for %s: %v", phone, err)
+		}
+	}
+
+	number := numbers[phoneNumberID]
+	if number == nil || !number.Active {
+		return nil
+	}
+	return number
+}
+
+// List returns every registered number; access tokens are left out of the JSON
+func (n *WhatsAppNumbers) List(ctx context.Context) ([]*core.WhatsAppNumber, error) {
+	return n.repo.List(ctx)
+}
+
+// Save registers a number or updates it. An empty access token keeps the saved one.
+func (n *WhatsAppNumbers) Save(ctx context.Context, number *core.WhatsAppNumber, actorUserID string) (*core.WhatsAppNumber, error) {
+	number.PhoneNumberID = strings.TrimSpace(number.PhoneNumberID)
+	number.VenueName = strings.TrimSpace(number.VenueName)
+	number.AccessToken = strings.TrimSpace(number.AccessToken)
+	if number.PhoneNumberID == "" {
+		return nil, fmt.Errorf("phone_number_id is required")
+	}
+	if number.VenueName == "" {
+		return nil, fmt.Errorf("venue_name is required")
+	}
+
+	categories := make([]string, 0, len(number.Categories))
+	for _, category := range number.Categories {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	number.Categories = categories
+
+	existing, err := n.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, saved := range existing {
+		if saved.PhoneNumberID == number.PhoneNumberID {
+			number.CreatedAt = saved.CreatedAt
+			if number.AccessToken == "" {
+				number.AccessToken = saved.AccessToken
+			}
+		}
+	}
+	if number.AccessToken == "" {
+		return nil, fmt.Errorf("access_token is required")
+	}
+
+	number.UpdatedBy = actorUserID
+	if err := n.repo.Upsert(ctx, number); err != nil {
+		return nil, err
+	}
+	n.changed(ctx, number.PhoneNumberID)
+	return number, nil
+}
+
+// Delete unregisters a number; its customers are answered from the default number again
+func (n *WhatsAppNumbers) Delete(ctx context.Context, phoneNumberID string) error {
+	if err := n.repo.Delete(ctx, phoneNumberID); err != nil {
+		return err
+	}
+	n.changed(ctx, phoneNumberID)
+	return nil
+}
+
+// changed drops this replica's cache and tells the others to drop theirs
+func (n *WhatsAppNumbers) changed(ctx context.Context, phoneNumberID string) {
+	n.invalidate()
+	if n.eventBus != nil {
+		n.eventBus.PublishWhatsAppNumbersUpdated(ctx, phoneNumberID)
+	}
+}
+
+// numbers returns the registered numbers by phone_number_id, reloading them when the cache is empty or stale.
+// On a load error the last loaded numbers are kept.
+func (n *WhatsAppNumbers) numbers(ctx context.Context) map[string]*core.WhatsAppNumber {
+	n.mu.RLock()
+	cache, loadedAt, generation := n.cache, n.loadedAt, n.generation
+	n.mu.RUnlock()
+
+	if cache != nil && n.clock.Now().Sub(loadedAt) < whatsAppNumbersCacheTTL {
+		return cache
+	}
+
+	numbers, err := n.repo.List(ctx)
+	if err != nil {
+		log.Printf("Failed to load WhatsApp numbers: %v", err)
+		return cache
+	}
+	cache = make(map[string]*core.WhatsAppNumber, len(numbers))
+	for _, number := range numbers {
+		cache[number.PhoneNumberID] = number
+	}
+
+	n.mu.Lock()
+	if n.generation == generation {
+		n.cache = cache
+		n.loadedAt = n.clock.Now()
+	}
+	n.mu.Unlock()
+
+	return cache
+}
+
+// invalidate drops the cache so the next read loads the whatsapp_numbers table
+func (n *WhatsAppNumbers) invalidate() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.cache = nil
+	n.generation++
+}
+
+// SetWhatsAppNumbers sets the number directory managed from the dashboard
+func (s *DashboardService) SetWhatsAppNumbers(numbers *WhatsAppNumbers) {
+	s.whatsAppNumbers = numbers
+}
+
+// ListWhatsAppNumbers returns the registered WhatsApp numbers
+func (s *DashboardService) ListWhatsAppNumbers(ctx context.Context) ([]*core.WhatsAppNumber, error) {
+	if s.whatsAppNumbers == nil {
+		return []*core.WhatsAppNumber{}, nil
+	}
+	return s.whatsAppNumbers.List(ctx)
+}
+
+// SaveWhatsAppNumber registers or updates a WhatsApp number
+func (s *DashboardService) SaveWhatsAppNumber(ctx context.Context, number *core.WhatsAppNumber, actorUserID string) (*core.WhatsAppNumber, error) {
+	if s.whatsAppNumbers == nil {
+		return nil, fmt.Errorf("whatsapp numbers not configured")
+	}
+	return s.whatsAppNumbers.Save(ctx, number, actorUserID)
+}
+
+// DeleteWhatsAppNumber unregisters a WhatsApp number
+func (s *DashboardService) DeleteWhatsAppNumber(ctx context.Context, phoneNumberID string) error {
+	if s.whatsAppNumbers == nil {
+		return fmt.Errorf("whatsapp numbers not configured")
+	}
+	return s.whatsAppNumbers.Delete(ctx, phoneNumberID)
+}
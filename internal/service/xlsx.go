@@ -0,0 +1,184 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// xlsxSheet is one worksheet of an XLSX export; the first row is the header and is shown bold.
+// Cells are string, int, float64 (money, two decimals), time.Time (date and time) or nil (empty),
+// so Excel sums, sorts and pivots numbers and dates without retyping them.
+type xlsxSheet struct {
+	Name string // At most 31 characters
+	Rows [][]interface{}
+}
+
+// Cell styles defined in xlsxStyles
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleMoney
+	xlsxStyleDateTime
+	xlsxStyleHeader
+)
+
+// xlsxEpoch is day zero of Excel's date serial numbers
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+const xlsxContentTypesHead = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+// xlsxStyles defines the cell formats in xlsxStyle* order: default, #,##0.00, yyyy-mm-dd hh:mm and bold
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+
+// renderXLSX writes sheets as an Office Open XML workbook. Times are written in loc, since Excel
+// dates have no time zone.
+func renderXLSX(sheets []xlsxSheet, loc *time.Location) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	contentTypes := xlsxContentTypesHead
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`
+	workbookRels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`
+
+	for i, sheet := range sheets {
+		n := i + 1
+		contentTypes += fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n)
+		workbook += fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxEscape(sheet.Name), n, n)
+		workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", n, n)
+
+		data, err := renderXLSXSheet(sheet, loc)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeXLSXPart(archive, fmt.Sprintf("xl/worksheets/sheet%d.xml", n), data); err != nil {
+			return nil, err
+		}
+	}
+	contentTypes += "</Types>"
+	workbook += "</sheets></workbook>"
+	workbookRels += "</Relationships>"
+
+	parts := []struct{ name, data string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		if err := writeXLSXPart(archive, part.name, []byte(part.data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to render XLSX: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func renderXLSXSheet(sheet xlsxSheet, loc *time.Location) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for r, row := range sheet.Rows {
+		fmt.Fprintf(&buffer, `<row r="%d">`, r+1)
+		for c, value := range row {
+			ref := xlsxColumn(c) + strconv.Itoa(r+1)
+			style := xlsxStyleDefault
+			if r == 0 {
+				style = xlsxStyleHeader
+			}
+
+			switch v := value.(type) {
+			case nil:
+				continue
+			case string:
+				fmt.Fprintf(&buffer, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xlsxEscape(v))
+			case int:
+				fmt.Fprintf(&buffer, `<c r="%s" s="%d"><v>%d</v></c>`, ref, style, v)
+			case float64:
+				if style == xlsxStyleDefault {
+					style = xlsxStyleMoney
+				}
+				fmt.Fprintf(&buffer, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+			case time.Time:
+				if style == xlsxStyleDefault {
+					style = xlsxStyleDateTime
+				}
+				fmt.Fprintf(&buffer, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(xlsxSerial(v, loc), 'f', -1, 64))
+			default:
+				return nil, fmt.Errorf("failed to render XLSX: unsupported cell type %T", value)
+			}
+		}
+		buffer.WriteString("</row>")
+	}
+
+	buffer.WriteString("</sheetData></worksheet>")
+	return buffer.Bytes(), nil
+}
+
+func writeXLSXPart(archive *zip.Writer, name string, data []byte) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to render XLSX: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to render XLSX: %w", err)
+	}
+	return nil
+}
+
+// xlsxSerial converts t to an Excel date serial number (days since 1899-12-30) on loc's wall clock
+func xlsxSerial(t time.Time, loc *time.Location) float64 {
+	local := t.In(loc)
+	wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+	return wall.Sub(xlsxEpoch).Hours() / 24
+}
+
+// xlsxColumn converts a zero-based column index to its letters (0 → A, 26 → AA)
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func xlsxEscape(value string) string {
+	var buffer bytes.Buffer
+	_ = xml.EscapeText(&buffer, []byte(value))
+	return buffer.String()
+}
@@ -0,0 +1,131 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// AdminUserRepository is an in-memory core.AdminUserRepository
+type AdminUserRepository struct {
+	mu    sync.Mutex
+	users map[string]*core.AdminUser // Keyed by ID
+	clock core.Clock
+	ids   core.IDGenerator
+}
+
+// NewAdminUserRepository creates a repository holding users
+func NewAdminUserRepository(clock core.Clock, ids core.IDGenerator, users ...*core.AdminUser) *AdminUserRepository {
+	r := &AdminUserRepository{users: make(map[string]*core.AdminUser), clock: clock, ids: ids}
+	for _, user := range users {
+		r.Create(context.Background(), user)
+	}
+	return r
+}
+
+// GetByID retrieves an admin user by ID
+func (r *AdminUserRepository) GetByID(ctx context.Context, id string) (*core.AdminUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("admin user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// GetByPhone retrieves an admin user by phone number
+func (r *AdminUserRepository) GetByPhone(ctx context.Context, phone string) (*core.AdminUser, error) {
+	users := r.matching(func(u *core.AdminUser) bool { return u.PhoneNumber == phone })
+	if len(users) == 0 {
+		return nil, fmt.Errorf("admin user not found")
+	}
+	return users[0], nil
+}
+
+// GetAll retrieves every admin user, oldest first
+func (r *AdminUserRepository) GetAll(ctx context.Context) ([]*core.AdminUser, error) {
+	return r.matching(func(u *core.AdminUser) bool { return true }), nil
+}
+
+// GetActiveByRole retrieves the active admin users with role
+func (r *AdminUserRepository) GetActiveByRole(ctx context.Context, role string) ([]*core.AdminUser, error) {
+	return r.matching(func(u *core.AdminUser) bool { return u.IsActive && u.Role == role }), nil
+}
+
+// Create stores a new admin user
+func (r *AdminUserRepository) Create(ctx context.Context, user *core.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = r.ids.NewID()
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = r.clock.Now()
+	}
+	stored := *user
+	r.users[stored.ID] = &stored
+	return nil
+}
+
+// Update replaces an admin user's name, role and active flag
+func (r *AdminUserRepository) Update(ctx context.Context, user *core.AdminUser) error {
+	return r.update(user.ID, func(u *core.AdminUser) {
+		u.Name = user.Name
+		u.Role = user.Role
+		u.IsActive = user.IsActive
+	})
+}
+
+// UpdatePINHash stores a bartender's PIN hash
+func (r *AdminUserRepository) UpdatePINHash(ctx context.Context, id string, pinHash string) error {
+	return r.update(id, func(u *core.AdminUser) { u.PinHash = pinHash })
+}
+
+// BumpTokenVersion invalidates every token issued to the user
+func (r *AdminUserRepository) BumpTokenVersion(ctx context.Context, id string) error {
+	return r.update(id, func(u *core.AdminUser) { u.TokenVersion++ })
+}
+
+// IsActive reports whether an active admin user has the phone number
+func (r *AdminUserRepository) IsActive(ctx context.Context, phone string) (bool, error) {
+	return len(r.matching(func(u *core.AdminUser) bool { return u.IsActive && u.PhoneNumber == phone })) > 0, nil
+}
+
+func (r *AdminUserRepository) matching(keep func(u *core.AdminUser) bool) []*core.AdminUser {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []*core.AdminUser
+	for _, user := range r.users {
+		if keep(user) {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+	return users
+}
+
+func (r *AdminUserRepository) update(id string, apply func(u *core.AdminUser)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return fmt.Errorf("admin user not found")
+	}
+	apply(user)
+	return nil
+}
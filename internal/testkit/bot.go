@@ -0,0 +1,114 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+)
+
+// Epoch is where a Bot's clock starts
+var Epoch = time.Date(2026, time.January, 2, 20, 0, 0, 0, time.UTC)
+
+// Bot is a BotService wired to in-memory fakes, with a frozen clock and sequential IDs
+type Bot struct {
+	Service  *service.BotService
+	Products *ProductRepository
+	Sessions *SessionRepository
+	Orders   *OrderRepository
+	Users    *UserRepository
+	Tabs     *TabRepository // Not wired into Service; set Service.Tabs to enable group tabs
+	WhatsApp *WhatsAppGateway
+	Payment  *PaymentGateway
+	Clock    *FakeClock
+	IDs      *SequenceIDGenerator
+}
+
+// NewBot creates a bot whose menu holds products. Fields on Service (Tax, TipsEnabled, ...)
+// can be changed before the first message.
+func NewBot(products ...*core.Product) *Bot {
+	clock := NewFakeClock(Epoch)
+	ids := &SequenceIDGenerator{}
+
+	bot := &Bot{
+		Products: NewProductRepository(clock, ids, products...),
+		Sessions: NewSessionRepository(),
+		Orders:   NewOrderRepository(clock, ids),
+		Users:    NewUserRepository(clock, ids),
+		WhatsApp: NewWhatsAppGateway(),
+		Payment:  NewPaymentGateway(),
+		Clock:    clock,
+		IDs:      ids,
+	}
+	bot.Tabs = NewTabRepository(bot.Orders, clock, ids)
+	bot.Service = service.NewBotService(bot.Products, bot.Sessions, bot.WhatsApp, bot.Payment, bot.Orders, bot.Users)
+	bot.Service.Clock = clock
+	bot.Service.IDs = ids
+	return bot
+}
+
+// Send delivers a typed text message from phone
+func (b *Bot) Send(ctx context.Context, phone string, message string) error {
+	return b.Service.HandleIncomingMessage(ctx, phone, message, "text")
+}
+
+// Tap delivers a button or list reply from phone; id is the button or row ID
+func (b *Bot) Tap(ctx context.Context, phone string, id string) error {
+	return b.Service.HandleIncomingMessage(ctx, phone, id, "interactive")
+}
+
+// Submit delivers a checkout form submission from phone, as the webhook would for an nfm_reply.
+// The flow token is taken from the latest form sent to phone.
+func (b *Bot) Submit(ctx context.Context, phone string, fields map[string]string) error {
+	reply := map[string]string{}
+	for key, value := range fields {
+		reply[key] = value
+	}
+	if _, ok := reply["flow_token"]; !ok {
+		sent := b.WhatsApp.Sent(phone)
+		for i := len(sent) - 1; i >= 0; i-- {
+			if sent[i].Kind == KindFlow {
+				reply["flow_token"] = sent[i].Flow.Token
+				break
+			}
+		}
+	}
+
+	payload, err := json.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	return b.Service.HandleIncomingMessage(ctx, phone, string(payload), service.FlowReplyMessageType)
+}
+
+// ShareLocation delivers a location pin from phone, as the webhook would for a location message
+func (b *Bot) ShareLocation(ctx context.Context, phone string, location service.SharedLocation) error {
+	payload, err := json.Marshal(location)
+	if err != nil {
+		return err
+	}
+	return b.Service.HandleIncomingMessage(ctx, phone, string(payload), service.LocationMessageType)
+}
+
+// State returns the session state for phone, or "" when there is no session
+func (b *Bot) State(ctx context.Context, phone string) string {
+	session, err := b.Sessions.Get(ctx, phone)
+	if err != nil {
+		return ""
+	}
+	return session.State
+}
+
+// ConfirmPayment applies the latest STK push for orderID as a successful M-Pesa callback would
+func (b *Bot) ConfirmPayment(ctx context.Context, orderID string, reference string) (*core.PaymentApplication, error) {
+	pushes := b.Payment.Pushes()
+	for i := len(pushes) - 1; i >= 0; i-- {
+		if pushes[i].OrderID == orderID {
+			return b.Orders.ApplyPayment(ctx, orderID, pushes[i].Phone, pushes[i].Amount, reference, core.OrderActorWebhook, "payment confirmed")
+		}
+	}
+	return nil, fmt.Errorf("no STK push for order %s", orderID)
+}
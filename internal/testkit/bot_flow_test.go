@@ -0,0 +1,94 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+func TestBotFlowScenarios(t *testing.T) {
+	for _, scenario := range BotFlowScenarios() {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if err := scenario.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestBotFlowPaidOrder(t *testing.T) {
+	ctx := context.Background()
+	bot := NewBot(SampleMenu()...)
+
+	for _, step := range []struct{ send, tap string }{
+		{send: "hi"},
+		{tap: "Beer"},
+		{send: "Tusker"},
+		{send: "2"},
+		{tap: "checkout"},
+		{tap: "pay_self"},
+	} {
+		var err error
+		if step.tap != "" {
+			err = bot.Tap(ctx, CustomerPhone, step.tap)
+		} else {
+			err = bot.Send(ctx, CustomerPhone, step.send)
+		}
+		if err != nil {
+			t.Fatalf("%+v: %v", step, err)
+		}
+	}
+
+	if !bot.WhatsApp.Contains(CustomerPhone, "Tusker") {
+		t.Errorf("no message to the customer mentions Tusker: %s", describeReplies(bot.WhatsApp.Sent(CustomerPhone)))
+	}
+	if !bot.WhatsApp.Contains(CustomerPhone, "KES 600") {
+		t.Errorf("no message to the customer shows the KES 600 total: %s", describeReplies(bot.WhatsApp.Sent(CustomerPhone)))
+	}
+
+	orders := bot.Orders.All()
+	if len(orders) != 1 {
+		t.Fatalf("%d orders created, want 1", len(orders))
+	}
+	order := orders[0]
+	if order.Status != core.OrderStatusPending || order.TotalAmount != 600 || order.CustomerPhone != CustomerPhone {
+		t.Fatalf("order %s for %.2f from %s, want PENDING for 600.00 from %s", order.Status, order.TotalAmount, order.CustomerPhone, CustomerPhone)
+	}
+	pushes := bot.Payment.Pushes()
+	if len(pushes) != 1 || pushes[0].OrderID != order.ID || pushes[0].Amount != 600 {
+		t.Fatalf("STK pushes %+v, want one for order %s of 600", pushes, order.ID)
+	}
+
+	application, err := bot.ConfirmPayment(ctx, order.ID, "QK12345")
+	if err != nil {
+		t.Fatalf("confirm payment: %v", err)
+	}
+	if application.Status != core.OrderStatusPaid || application.AmountPaid != 600 || application.Duplicate {
+		t.Errorf("payment application %+v, want PAID with 600 paid", application)
+	}
+
+	paid, err := bot.Orders.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.Status != core.OrderStatusPaid || paid.AmountPaid != 600 || paid.PaymentRef != "QK12345" {
+		t.Errorf("order after payment %s, %.2f paid, ref %q; want PAID, 600.00, QK12345", paid.Status, paid.AmountPaid, paid.PaymentRef)
+	}
+	shares, err := bot.Orders.GetPaymentShares(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 1 || shares[0].Status != core.PaymentSharePaid || shares[0].Reference != "QK12345" {
+		t.Errorf("payment shares %+v, want one PAID share with reference QK12345", shares)
+	}
+
+	// The provider retries callbacks; the same reference must not count twice
+	retry, err := bot.ConfirmPayment(ctx, order.ID, "QK12345")
+	if err != nil {
+		t.Fatalf("retried callback: %v", err)
+	}
+	if !retry.Duplicate || retry.AmountPaid != 600 {
+		t.Errorf("retried callback %+v, want a duplicate with 600 paid", retry)
+	}
+}
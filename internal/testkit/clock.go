@@ -0,0 +1,54 @@
+package testkit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FakeClock is a settable core.Clock for deterministic tests
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a FakeClock frozen at now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the frozen time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to t
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// SequenceIDGenerator is a deterministic core.IDGenerator for tests.
+// IDs are valid UUID strings (00000000-0000-0000-0000-000000000001, ...) so they fit uuid columns.
+type SequenceIDGenerator struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewID returns the next ID in the sequence
+func (g *SequenceIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.next++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", g.next)
+}
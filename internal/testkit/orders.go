@@ -0,0 +1,754 @@
+package testkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// paidInFullTolerance absorbs float rounding when comparing the amount paid to the order total
+const paidInFullTolerance = 0.005
+
+// activePickupStatuses hold their pickup code until the order is collected or dropped
+var activePickupStatuses = map[core.OrderStatus]bool{
+	core.OrderStatusPending:        true,
+	core.OrderStatusPartiallyPaid:  true,
+	core.OrderStatusAwaitingCash:   true,
+	core.OrderStatusScheduled:      true,
+	core.OrderStatusPaid:           true,
+	core.OrderStatusReady:          true,
+	core.OrderStatusOutForDelivery: true,
+}
+
+// OrderRepository is an in-memory core.OrderRepository.
+// Status changes, split shares and payments follow the Postgres repository's rules.
+type OrderRepository struct {
+	mu      sync.Mutex
+	orders  map[string]*core.Order
+	shares  map[string][]*core.PaymentShare // Keyed by order ID, oldest first
+	history map[string][]*core.OrderStatusChange
+	clock   core.Clock
+	ids     core.IDGenerator
+}
+
+// NewOrderRepository creates an empty order repository
+func NewOrderRepository(clock core.Clock, ids core.IDGenerator) *OrderRepository {
+	return &OrderRepository{
+		orders:  make(map[string]*core.Order),
+		shares:  make(map[string][]*core.PaymentShare),
+		history: make(map[string][]*core.OrderStatusChange),
+		clock:   clock,
+		ids:     ids,
+	}
+}
+
+// All returns every order, oldest first. Orders created at the same instant of the frozen clock are
+// in creation order, as sequential IDs sort that way.
+func (r *OrderRepository) All() []*core.Order {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orders := r.matching(func(o *core.Order) bool { return true })
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+	return orders
+}
+
+// CreateOrder stores the order with its items and split bill shares
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *core.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if order.ID == "" {
+		order.ID = r.ids.NewID()
+	}
+	if _, exists := r.orders[order.ID]; exists {
+		return fmt.Errorf("failed to create order: duplicate id %s", order.ID)
+	}
+
+	stored := copyOrder(order)
+	stored.PaymentShares = nil
+	r.orders[order.ID] = stored
+
+	for _, share := range order.PaymentShares {
+		share.OrderID = order.ID
+		r.addShare(share)
+	}
+
+	r.recordStatusChange(order.ID, "", order.Status, core.OrderActorSystem, "order created")
+	return nil
+}
+
+// GetByID retrieves an order by its ID
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*core.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+	return copyOrder(order), nil
+}
+
+// GetByUserID retrieves a user's orders, newest first
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID string) ([]*core.Order, error) {
+	return r.newestFirst(func(o *core.Order) bool { return o.UserID == userID }, 0), nil
+}
+
+// GetByPhone retrieves the orders for a customer phone, newest first
+func (r *OrderRepository) GetByPhone(ctx context.Context, phone string) ([]*core.Order, error) {
+	return r.newestFirst(func(o *core.Order) bool { return o.CustomerPhone == phone }, 0), nil
+}
+
+// GetByDateRangeAndStatuses retrieves orders created in [start, end), oldest first; no statuses means all
+func (r *OrderRepository) GetByDateRangeAndStatuses(ctx context.Context, start time.Time, end time.Time, statuses []core.OrderStatus) ([]*core.Order, error) {
+	wanted := make(map[core.OrderStatus]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return !o.CreatedAt.Before(start) && o.CreatedAt.Before(end) && (len(wanted) == 0 || wanted[o.Status])
+	}, 0)
+	for i, j := 0, len(orders)-1; i < j; i, j = i+1, j-1 {
+		orders[i], orders[j] = orders[j], orders[i]
+	}
+	return orders, nil
+}
+
+// UpdateStatus changes an order's status as the system
+func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, status core.OrderStatus) error {
+	return r.UpdateStatusWithNote(ctx, id, status, core.OrderActorSystem, "")
+}
+
+// UpdateStatusWithActor changes an order's status on behalf of a dashboard user
+func (r *OrderRepository) UpdateStatusWithActor(ctx context.Context, id string, status core.OrderStatus, actorUserID string) error {
+	return r.UpdateStatusWithNote(ctx, id, status, actorUserID, "")
+}
+
+// UpdateStatusWithNote changes an order's status and records the transition
+func (r *OrderRepository) UpdateStatusWithNote(ctx context.Context, id string, status core.OrderStatus, actor string, note string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return fmt.Errorf("order not found")
+	}
+
+	from := order.Status
+	order.Status = status
+	now := r.clock.Now()
+	switch status {
+	case core.OrderStatusReady:
+		order.ReadyAt = &now
+		if isAdminActor(actor) {
+			order.ReadyByUserID = actor
+		}
+	case core.OrderStatusCompleted, core.OrderStatusDelivered:
+		order.CompletedAt = &now
+		if isAdminActor(actor) {
+			order.CompletedByUserID = actor
+		}
+	}
+
+	if from != status {
+		r.recordStatusChange(id, from, status, actor, note)
+	}
+	return nil
+}
+
+// GetStatusHistory retrieves an order's status changes, oldest first
+func (r *OrderRepository) GetStatusHistory(ctx context.Context, orderID string) ([]*core.OrderStatusChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := make([]*core.OrderStatusChange, len(r.history[orderID]))
+	for i, change := range r.history[orderID] {
+		copied := *change
+		history[i] = &copied
+	}
+	return history, nil
+}
+
+// MarkAccepted records the first bar staff member to accept an order
+func (r *OrderRepository) MarkAccepted(ctx context.Context, id string, staffID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.AcceptedByStaffID != "" {
+		return false, nil
+	}
+	now := r.clock.Now()
+	order.AcceptedByStaffID = staffID
+	order.AcceptedAt = &now
+	return true, nil
+}
+
+// AssignRider records the first rider to accept an OUT_FOR_DELIVERY order
+func (r *OrderRepository) AssignRider(ctx context.Context, id string, riderID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.RiderID != "" || order.Status != core.OrderStatusOutForDelivery {
+		return false, nil
+	}
+	now := r.clock.Now()
+	order.RiderID = riderID
+	order.RiderAssignedAt = &now
+	return true, nil
+}
+
+// Search retrieves orders newest first, narrowed by the given filter
+func (r *OrderRepository) Search(ctx context.Context, filter core.OrderFilter) ([]*core.Order, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	phoneDigits := lastNineDigits(filter.Phone)
+
+	return r.newestFirst(func(o *core.Order) bool {
+		switch {
+		case filter.Status != "" && string(o.Status) != filter.Status,
+			filter.PickupCode != "" && !strings.Contains(strings.ToLower(o.PickupCode), strings.ToLower(filter.PickupCode)),
+			phoneDigits != "" && !strings.Contains(o.CustomerPhone, phoneDigits),
+			filter.PaymentMethod != "" && o.PaymentMethod != filter.PaymentMethod,
+			filter.MinAmount != nil && o.TotalAmount < *filter.MinAmount,
+			filter.MaxAmount != nil && o.TotalAmount > *filter.MaxAmount,
+			filter.From != nil && o.CreatedAt.Before(*filter.From),
+			filter.To != nil && !o.CreatedAt.Before(*filter.To):
+			return false
+		}
+		return true
+	}, limit), nil
+}
+
+// GetCompletedHistory retrieves completed orders, most recently completed first
+func (r *OrderRepository) GetCompletedHistory(ctx context.Context, pickupCode string, phone string, limit int) ([]*core.Order, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	phoneDigits := lastNineDigits(phone)
+
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return o.Status == core.OrderStatusCompleted &&
+			(pickupCode == "" || strings.Contains(strings.ToLower(o.PickupCode), strings.ToLower(pickupCode))) &&
+			(phoneDigits == "" || lastNineDigits(o.CustomerPhone) == phoneDigits)
+	}, 0)
+	sort.SliceStable(orders, func(i, j int) bool {
+		a, b := orders[i].CompletedAt, orders[j].CompletedAt
+		return a != nil && (b == nil || a.After(*b))
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// FindPendingByPhoneAndAmount finds the most recent PENDING order for the phone (any Kenyan format) and amount
+func (r *OrderRepository) FindPendingByPhoneAndAmount(ctx context.Context, phone string, amount float64) (*core.Order, error) {
+	digits := lastNineDigits(phone)
+	return r.newestPending(func(o *core.Order) bool {
+		return o.TotalAmount == amount && (o.CustomerPhone == phone || strings.HasSuffix(o.CustomerPhone, digits))
+	}), nil
+}
+
+// FindPendingByHashedPhoneAndAmount matches a buygoods webhook's SHA256 phone hash against recent PENDING orders
+func (r *OrderRepository) FindPendingByHashedPhoneAndAmount(ctx context.Context, hashedPhone string, amount float64) (*core.Order, error) {
+	if hashedPhone == "" {
+		return nil, nil
+	}
+	cutoff := r.clock.Now().Add(-30 * time.Minute)
+	return r.newestPending(func(o *core.Order) bool {
+		return o.TotalAmount == amount && o.CreatedAt.After(cutoff) && matchesHashedPhone(o.CustomerPhone, hashedPhone)
+	}), nil
+}
+
+// FindPendingByAmount finds the most recent PENDING order for amount created in the last 30 minutes
+func (r *OrderRepository) FindPendingByAmount(ctx context.Context, amount float64) (*core.Order, error) {
+	cutoff := r.clock.Now().Add(-30 * time.Minute)
+	return r.newestPending(func(o *core.Order) bool {
+		return o.TotalAmount == amount && o.CreatedAt.After(cutoff)
+	}), nil
+}
+
+// IsPickupCodeActive reports whether an open order holds the code
+func (r *OrderRepository) IsPickupCodeActive(ctx context.Context, code string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, order := range r.orders {
+		if order.PickupCode == code && activePickupStatuses[order.Status] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetUncollected retrieves READY orders that have waited at least readyFor and haven't been escalated
+func (r *OrderRepository) GetUncollected(ctx context.Context, readyFor time.Duration) ([]*core.Order, error) {
+	due := r.clock.Now().Add(-readyFor)
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return o.Status == core.OrderStatusReady && o.PickupEscalatedAt == nil && o.ReadyAt != nil && !o.ReadyAt.After(due)
+	}, 0)
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].ReadyAt.Before(*orders[j].ReadyAt) })
+	return orders, nil
+}
+
+// ClaimReadyReminder records pickup reminder n once the order has been READY for readyFor
+func (r *OrderRepository) ClaimReadyReminder(ctx context.Context, id string, reminder int, readyFor time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || !r.readyFor(order, readyFor) || order.ReadyReminders >= reminder {
+		return false, nil
+	}
+	order.ReadyReminders = reminder
+	return true, nil
+}
+
+// ClaimPickupEscalation flags an order still READY after readyFor for bar staff attention
+func (r *OrderRepository) ClaimPickupEscalation(ctx context.Context, id string, readyFor time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || !r.readyFor(order, readyFor) || order.PickupEscalatedAt != nil {
+		return false, nil
+	}
+	now := r.clock.Now()
+	order.PickupEscalatedAt = &now
+	return true, nil
+}
+
+// GetPrepOverdue retrieves PAID orders that have waited at least paidFor and haven't been flagged, oldest first
+func (r *OrderRepository) GetPrepOverdue(ctx context.Context, paidFor time.Duration) ([]*core.Order, error) {
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return o.PrepSLAAlertedAt == nil && r.paidFor(o, paidFor)
+	}, 0)
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].CreatedAt.Before(orders[j].CreatedAt) })
+	return orders, nil
+}
+
+// ClaimPrepSLAAlert flags an order still PAID after paidFor
+func (r *OrderRepository) ClaimPrepSLAAlert(ctx context.Context, id string, paidFor time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || !r.paidFor(order, paidFor) || order.PrepSLAAlertedAt != nil {
+		return false, nil
+	}
+	now := r.clock.Now()
+	order.PrepSLAAlertedAt = &now
+	return true, nil
+}
+
+// GetDueScheduled retrieves SCHEDULED orders wanted at or before dueBy, soonest first
+func (r *OrderRepository) GetDueScheduled(ctx context.Context, dueBy time.Time) ([]*core.Order, error) {
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return o.Status == core.OrderStatusScheduled && o.ScheduledFor != nil && !o.ScheduledFor.After(dueBy)
+	}, 0)
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].ScheduledFor.Before(*orders[j].ScheduledFor) })
+	return orders, nil
+}
+
+// ReleaseScheduled moves a SCHEDULED order to PAID; false when it was already released
+func (r *OrderRepository) ReleaseScheduled(ctx context.Context, id string, note string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.Status != core.OrderStatusScheduled {
+		return false, nil
+	}
+	order.Status = core.OrderStatusPaid
+	r.recordStatusChange(id, core.OrderStatusScheduled, core.OrderStatusPaid, core.OrderActorSystem, note)
+	return true, nil
+}
+
+// GetExpiredPending retrieves PENDING orders with nothing paid created before createdBefore, oldest first
+func (r *OrderRepository) GetExpiredPending(ctx context.Context, createdBefore time.Time, limit int) ([]*core.Order, error) {
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return o.Status == core.OrderStatusPending && o.AmountPaid == 0 && o.CreatedAt.Before(createdBefore)
+	}, 0)
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].CreatedAt.Before(orders[j].CreatedAt) })
+	if limit > 0 && len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// CancelExpiredPending moves an unpaid PENDING order to CANCELLED; false when it was paid or already cancelled.
+// The fake keeps no tab items, so fromTab is always false.
+func (r *OrderRepository) CancelExpiredPending(ctx context.Context, id string, note string) (bool, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.Status != core.OrderStatusPending || order.AmountPaid != 0 {
+		return false, false, nil
+	}
+	order.Status = core.OrderStatusCancelled
+	r.recordStatusChange(id, core.OrderStatusPending, core.OrderStatusCancelled, core.OrderActorSystem, note)
+	return true, false, nil
+}
+
+// FailPaymentShare marks the PENDING split share matching a failed payment as FAILED
+func (r *OrderRepository) FailPaymentShare(ctx context.Context, orderID string, phone string, amount float64) (*core.PaymentShare, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	share := matchPaymentShare(r.sharesWithStatus(orderID, core.PaymentSharePending), phone, amount)
+	if share == nil {
+		return nil, fmt.Errorf("payment share not found")
+	}
+	share.Status = core.PaymentShareFailed
+	copied := *share
+	return &copied, nil
+}
+
+// ResetPaymentShare puts a FAILED share back to PENDING so its prompt can be resent
+func (r *OrderRepository) ResetPaymentShare(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, shares := range r.shares {
+		for _, share := range shares {
+			if share.ID == id && share.Status == core.PaymentShareFailed {
+				share.Status = core.PaymentSharePending
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("payment share not found")
+}
+
+// GetPaymentShares retrieves an order's split bill shares, oldest first
+func (r *OrderRepository) GetPaymentShares(ctx context.Context, orderID string) ([]*core.PaymentShare, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyShares(r.shares[orderID]), nil
+}
+
+// GetBoard retrieves PAID and READY orders plus those COMPLETED since completedSince, longest in status first
+func (r *OrderRepository) GetBoard(ctx context.Context, completedSince time.Time) ([]*core.BoardOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var board []*core.BoardOrder
+	for _, order := range r.orders {
+		switch {
+		case order.Status == core.OrderStatusPaid, order.Status == core.OrderStatusReady,
+			order.Status == core.OrderStatusCompleted && order.CompletedAt != nil && !order.CompletedAt.Before(completedSince):
+		default:
+			continue
+		}
+
+		since := order.CreatedAt
+		for _, change := range r.history[order.ID] {
+			if change.ToStatus == order.Status {
+				since = change.CreatedAt
+			}
+		}
+		entry := &core.BoardOrder{
+			ID:                order.ID,
+			PickupCode:        order.PickupCode,
+			TableNumber:       order.TableNumber,
+			Status:            order.Status,
+			Notes:             order.Notes,
+			IsDelivery:        order.IsDelivery(),
+			AcceptedByStaffID: order.AcceptedByStaffID,
+			Items:             make([]core.BoardItem, len(order.Items)),
+			StatusSince:       since,
+			CreatedAt:         order.CreatedAt,
+		}
+		for i, item := range order.Items {
+			entry.Items[i] = core.BoardItem{ProductName: item.ProductName, Quantity: item.Quantity, Modifiers: item.Modifiers}
+		}
+		board = append(board, entry)
+	}
+	sort.SliceStable(board, func(i, j int) bool { return board[i].StatusSince.Before(board[j].StatusSince) })
+	return board, nil
+}
+
+// ApplyPayment records a confirmed payment against the order and recomputes its status
+func (r *OrderRepository) ApplyPayment(ctx context.Context, orderID string, phone string, amount float64, reference string, actor string, note string) (*core.PaymentApplication, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	if order.Status == core.OrderStatusCancelled && !r.cancelledByExpiry(orderID) {
+		return &core.PaymentApplication{AmountPaid: order.AmountPaid, Status: order.Status, RefundRequired: true}, nil
+	}
+
+	// Webhook retries carry the same reference; count it once
+	if reference != "" {
+		for _, share := range r.shares[orderID] {
+			if share.Reference == reference {
+				copied := *share
+				return &core.PaymentApplication{Share: &copied, AmountPaid: order.AmountPaid, Status: order.Status, Duplicate: true}, nil
+			}
+		}
+	}
+
+	now := r.clock.Now()
+	open := append(r.sharesWithStatus(orderID, core.PaymentSharePending), r.sharesWithStatus(orderID, core.PaymentShareFailed)...)
+	share := matchPaymentShare(open, phone, amount)
+	if share == nil {
+		// addShare stores a copy, so the new share goes in complete
+		share = &core.PaymentShare{OrderID: orderID, Phone: phone, Status: core.PaymentSharePaid, Amount: amount, Reference: reference, PaidAt: &now, CreatedAt: now}
+		r.addShare(share)
+	}
+	share.Status = core.PaymentSharePaid
+	share.Amount = amount
+	share.Reference = reference
+	share.PaidAt = &now
+
+	paid := math.Round((order.AmountPaid+amount)*100) / 100
+	from := order.Status
+	status := from
+	switch from {
+	case core.OrderStatusPending, core.OrderStatusPartiallyPaid, core.OrderStatusFailed, core.OrderStatusCancelled:
+		if paid >= order.TotalAmount-paidInFullTolerance {
+			status = core.OrderStatusPaid
+			if order.ScheduledFor != nil && order.ScheduledFor.After(now) {
+				status = core.OrderStatusScheduled
+			}
+		} else {
+			status = core.OrderStatusPartiallyPaid
+		}
+	}
+
+	order.AmountPaid = paid
+	order.Status = status
+	if order.PaymentRef == "" {
+		order.PaymentRef = reference
+	}
+	if status != from {
+		r.recordStatusChange(orderID, from, status, actor, note)
+	}
+
+	copied := *share
+	return &core.PaymentApplication{Share: &copied, AmountPaid: paid, Status: status}, nil
+}
+
+// ConfirmBarPayment moves an AWAITING_CASH order to PAID in full with the method staff collected
+func (r *OrderRepository) ConfirmBarPayment(ctx context.Context, orderID string, method core.PaymentMethod, actor string, note string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[orderID]
+	if !ok {
+		return fmt.Errorf("order not found")
+	}
+	if order.Status != core.OrderStatusAwaitingCash {
+		return fmt.Errorf("%w (order is %s)", core.ErrOrderNotAwaitingCash, order.Status)
+	}
+
+	order.Status = core.OrderStatusPaid
+	order.PaymentMethod = string(method)
+	order.AmountPaid = order.TotalAmount
+	r.recordStatusChange(orderID, core.OrderStatusAwaitingCash, core.OrderStatusPaid, actor, note)
+	return nil
+}
+
+// cancelledByExpiry reports whether the order's last status change was the expiry job cancelling it; callers hold r.mu
+func (r *OrderRepository) cancelledByExpiry(orderID string) bool {
+	history := r.history[orderID]
+	if len(history) == 0 {
+		return false
+	}
+	last := history[len(history)-1]
+	return last.ToStatus == core.OrderStatusCancelled && last.FromStatus == core.OrderStatusPending && last.Actor == core.OrderActorSystem
+}
+
+// recordStatusChange appends to the order's history; callers hold r.mu
+func (r *OrderRepository) recordStatusChange(orderID string, from core.OrderStatus, to core.OrderStatus, actor string, note string) {
+	if actor == "" {
+		actor = core.OrderActorSystem
+	}
+	r.history[orderID] = append(r.history[orderID], &core.OrderStatusChange{
+		ID:         r.ids.NewID(),
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Note:       note,
+		CreatedAt:  r.clock.Now(),
+	})
+}
+
+// addShare stores a split share; callers hold r.mu
+func (r *OrderRepository) addShare(share *core.PaymentShare) {
+	if share.ID == "" {
+		share.ID = r.ids.NewID()
+	}
+	if share.Status == "" {
+		share.Status = core.PaymentSharePending
+	}
+	if share.CreatedAt.IsZero() {
+		share.CreatedAt = r.clock.Now()
+	}
+	stored := *share
+	r.shares[share.OrderID] = append(r.shares[share.OrderID], &stored)
+}
+
+// sharesWithStatus returns the order's stored shares in a status; callers hold r.mu
+func (r *OrderRepository) sharesWithStatus(orderID string, status string) []*core.PaymentShare {
+	var shares []*core.PaymentShare
+	for _, share := range r.shares[orderID] {
+		if share.Status == status {
+			shares = append(shares, share)
+		}
+	}
+	return shares
+}
+
+// readyFor reports whether the order has been READY for at least d; callers hold r.mu
+func (r *OrderRepository) readyFor(order *core.Order, d time.Duration) bool {
+	return order.Status == core.OrderStatusReady && order.ReadyAt != nil && !order.ReadyAt.After(r.clock.Now().Add(-d))
+}
+
+// paidFor reports whether the order is PAID and was first paid at least d ago; callers hold r.mu
+func (r *OrderRepository) paidFor(order *core.Order, d time.Duration) bool {
+	if order.Status != core.OrderStatusPaid {
+		return false
+	}
+	for _, change := range r.history[order.ID] {
+		if change.ToStatus == core.OrderStatusPaid {
+			return !change.CreatedAt.After(r.clock.Now().Add(-d))
+		}
+	}
+	return false
+}
+
+// matching returns copies of the orders match accepts; callers hold r.mu
+func (r *OrderRepository) matching(match func(o *core.Order) bool) []*core.Order {
+	var orders []*core.Order
+	for _, order := range r.orders {
+		if match(order) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	return orders
+}
+
+// newestFirst returns copies of matching orders, newest first, capped at limit when it's positive
+func (r *OrderRepository) newestFirst(match func(o *core.Order) bool, limit int) []*core.Order {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orders := r.matching(match)
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].CreatedAt.After(orders[j].CreatedAt) })
+	if limit > 0 && len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders
+}
+
+// newestPending returns the newest matching PENDING order, or nil when none match
+func (r *OrderRepository) newestPending(match func(o *core.Order) bool) *core.Order {
+	orders := r.newestFirst(func(o *core.Order) bool {
+		return o.Status == core.OrderStatusPending && match(o)
+	}, 1)
+	if len(orders) == 0 {
+		return nil
+	}
+	return orders[0]
+}
+
+// matchPaymentShare picks the share a payment belongs to: same phone and amount, then same phone,
+// then same amount
+func matchPaymentShare(shares []*core.PaymentShare, phone string, amount float64) *core.PaymentShare {
+	digits := lastNineDigits(phone)
+	samePhone := func(share *core.PaymentShare) bool {
+		return digits != "" && lastNineDigits(share.Phone) == digits
+	}
+	sameAmount := func(share *core.PaymentShare) bool {
+		return math.Abs(share.Amount-amount) < paidInFullTolerance
+	}
+
+	for _, matches := range []func(share *core.PaymentShare) bool{
+		func(share *core.PaymentShare) bool { return samePhone(share) && sameAmount(share) },
+		samePhone,
+		sameAmount,
+	} {
+		for _, share := range shares {
+			if matches(share) {
+				return share
+			}
+		}
+	}
+	return nil
+}
+
+// matchesHashedPhone compares a stored phone against a SHA256 hash in the formats Kopo Kopo may have hashed
+func matchesHashedPhone(phone string, hashedPhone string) bool {
+	digits := lastNineDigits(phone)
+	for _, candidate := range []string{phone, digits, "0" + digits, "254" + digits, "+254" + digits} {
+		sum := sha256.Sum256([]byte(candidate))
+		if strings.EqualFold(hex.EncodeToString(sum[:]), hashedPhone) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastNineDigits keeps the subscriber part of a Kenyan number so 07.., 2547.. and +2547.. compare equal
+func lastNineDigits(phone string) string {
+	var digits strings.Builder
+	for _, char := range phone {
+		if char >= '0' && char <= '9' {
+			digits.WriteRune(char)
+		}
+	}
+	if s := digits.String(); len(s) > 9 {
+		return s[len(s)-9:]
+	}
+	return digits.String()
+}
+
+// isAdminActor reports whether actor is a dashboard user rather than the system or a webhook
+func isAdminActor(actor string) bool {
+	return actor != "" && actor != core.OrderActorSystem && actor != core.OrderActorWebhook
+}
+
+func copyOrder(order *core.Order) *core.Order {
+	copied := *order
+	copied.Items = append([]core.OrderItem(nil), order.Items...)
+	copied.PaymentShares = copyShares(order.PaymentShares)
+	return &copied
+}
+
+func copyShares(shares []*core.PaymentShare) []*core.PaymentShare {
+	if shares == nil {
+		return nil
+	}
+	copied := make([]*core.PaymentShare, len(shares))
+	for i, share := range shares {
+		c := *share
+		copied[i] = &c
+	}
+	return copied
+}
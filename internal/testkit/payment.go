@@ -0,0 +1,76 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// STKPush is one payment prompt the bot asked PaymentGateway to send
+type STKPush struct {
+	OrderID string
+	Phone   string
+	Amount  float64
+}
+
+// PaymentGateway is a core.PaymentGateway that records STK pushes instead of calling Kopo Kopo.
+// Webhooks are accepted as JSON-encoded core.PaymentWebhook values; see Webhook.
+type PaymentGateway struct {
+	mu     sync.Mutex
+	pushes []STKPush
+	err    error
+}
+
+// NewPaymentGateway creates a recording payment gateway
+func NewPaymentGateway() *PaymentGateway {
+	return &PaymentGateway{}
+}
+
+// FailWith makes later STK pushes fail with err, as when the queue is full (nil restores them)
+func (g *PaymentGateway) FailWith(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.err = err
+}
+
+// Pushes returns the STK pushes sent so far, oldest first
+func (g *PaymentGateway) Pushes() []STKPush {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]STKPush(nil), g.pushes...)
+}
+
+// InitiateSTKPush records the push
+func (g *PaymentGateway) InitiateSTKPush(ctx context.Context, orderID string, phone string, amount float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return g.err
+	}
+	g.pushes = append(g.pushes, STKPush{OrderID: orderID, Phone: phone, Amount: amount})
+	return nil
+}
+
+// VerifyWebhook accepts every signature
+func (g *PaymentGateway) VerifyWebhook(ctx context.Context, signature string, payload []byte) bool {
+	return true
+}
+
+// ProcessWebhook decodes a payload built by Webhook
+func (g *PaymentGateway) ProcessWebhook(ctx context.Context, payload []byte) (*core.PaymentWebhook, error) {
+	var webhook core.PaymentWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// Webhook builds the payload of a payment callback for ProcessWebhook
+func Webhook(webhook core.PaymentWebhook) []byte {
+	payload, _ := json.Marshal(webhook)
+	return payload
+}
@@ -0,0 +1,315 @@
+// Package testkit provides in-memory implementations of the core ports so bot and payment
+// flows can be driven end to end without Postgres, Redis, WhatsApp or Kopo Kopo.
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// ProductRepository is an in-memory core.ProductRepository.
+// Queries follow the Postgres repository: only active, unarchived products are on the menu, less
+// sold-out ones set to hide (there are no runtime settings here, so only the product's own flag counts).
+type ProductRepository struct {
+	mu       sync.Mutex
+	products map[string]*core.Product
+	history  []*core.PriceChange
+	soldOut  map[string]bool // Products ClaimRestocked has seen out of stock
+	clock    core.Clock
+	ids      core.IDGenerator
+}
+
+// NewProductRepository creates a product repository holding copies of products.
+// Products without an ID are given one from ids.
+func NewProductRepository(clock core.Clock, ids core.IDGenerator, products ...*core.Product) *ProductRepository {
+	r := &ProductRepository{products: make(map[string]*core.Product), soldOut: make(map[string]bool), clock: clock, ids: ids}
+	for _, product := range products {
+		r.Add(product)
+	}
+	return r
+}
+
+// Add stores a copy of product, replacing any product with the same ID
+func (r *ProductRepository) Add(product *core.Product) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *product
+	if stored.ID == "" {
+		stored.ID = r.ids.NewID()
+		product.ID = stored.ID
+	}
+	r.products[stored.ID] = &stored
+}
+
+// GetByID retrieves a product by its ID
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*core.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return nil, fmt.Errorf("product not found")
+	}
+	copied := *product
+	return &copied, nil
+}
+
+// GetByCategory retrieves the menu products in a category
+func (r *ProductRepository) GetByCategory(ctx context.Context, category string) ([]*core.Product, error) {
+	return r.filter(func(p *core.Product) bool { return listed(p) && p.Category == category }), nil
+}
+
+// GetAll retrieves all menu products
+func (r *ProductRepository) GetAll(ctx context.Context) ([]*core.Product, error) {
+	return r.filter(onMenu), nil
+}
+
+// GetMenu retrieves menu products grouped by category, sorted by name
+func (r *ProductRepository) GetMenu(ctx context.Context) (map[string][]*core.Product, error) {
+	menu := make(map[string][]*core.Product)
+	for _, product := range r.filter(listed) {
+		menu[product.Category] = append(menu[product.Category], product)
+	}
+	return menu, nil
+}
+
+// UpdateStock sets a product's stock quantity
+func (r *ProductRepository) UpdateStock(ctx context.Context, id string, quantity int) error {
+	return r.update(id, func(p *core.Product) { p.StockQuantity = quantity })
+}
+
+// AdjustStock adds delta to a product's stock; nothing is recorded besides the new quantity
+func (r *ProductRepository) AdjustStock(ctx context.Context, id string, delta int, reason string, actor string) (*core.StockAdjustment, error) {
+	return r.changeStock(id, reason, func(current int) (int, error) {
+		if current+delta < 0 {
+			return 0, fmt.Errorf("invalid adjustment: only %d in stock", current)
+		}
+		return current + delta, nil
+	})
+}
+
+// CorrectStock sets a product's stock, like UpdateStock, and returns the change
+func (r *ProductRepository) CorrectStock(ctx context.Context, id string, quantity int, actor string) (*core.StockAdjustment, error) {
+	return r.changeStock(id, core.StockAdjustmentCorrection, func(int) (int, error) { return quantity, nil })
+}
+
+// UpdatePrice sets a product's price and records the change
+func (r *ProductRepository) UpdatePrice(ctx context.Context, id string, price float64, actor string) error {
+	return r.setPrice(id, price, actor, core.PriceChangeManual)
+}
+
+// GetPriceHistory retrieves a product's price changes, newest first
+func (r *ProductRepository) GetPriceHistory(ctx context.Context, id string, limit int) ([]*core.PriceChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var history []*core.PriceChange
+	for i := len(r.history) - 1; i >= 0 && len(history) < limit; i-- {
+		if r.history[i].ProductID == id {
+			change := *r.history[i]
+			history = append(history, &change)
+		}
+	}
+	return history, nil
+}
+
+func (r *ProductRepository) setPrice(id string, price float64, actor string, source string) error {
+	var oldPrice float64
+	if err := r.update(id, func(p *core.Product) {
+		oldPrice = p.Price
+		p.Price = price
+	}); err != nil {
+		return err
+	}
+	if oldPrice == price {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, &core.PriceChange{
+		ID:        r.ids.NewID(),
+		ProductID: id,
+		OldPrice:  oldPrice,
+		NewPrice:  price,
+		Actor:     actor,
+		Source:    source,
+		CreatedAt: r.clock.Now(),
+	})
+	return nil
+}
+
+// SetBottleSize sets how many ml one stock unit of a product holds
+func (r *ProductRepository) SetBottleSize(ctx context.Context, id string, bottleML int) error {
+	return r.update(id, func(p *core.Product) { p.BottleML = bottleML })
+}
+
+// SetCostPrice sets what one stock unit of a product costs the bar
+func (r *ProductRepository) SetCostPrice(ctx context.Context, id string, cost float64) error {
+	return r.update(id, func(p *core.Product) { p.CostPrice = cost })
+}
+
+// SearchProducts finds menu products whose name contains query (case-insensitive)
+func (r *ProductRepository) SearchProducts(ctx context.Context, query string) ([]*core.Product, error) {
+	query = strings.ToLower(query)
+	return r.filter(func(p *core.Product) bool {
+		return listed(p) && strings.Contains(strings.ToLower(p.Name), query)
+	}), nil
+}
+
+// GetArchived retrieves archived products
+func (r *ProductRepository) GetArchived(ctx context.Context) ([]*core.Product, error) {
+	return r.filter(func(p *core.Product) bool { return p.ArchivedAt != nil }), nil
+}
+
+// SetArchived archives (and deactivates) or restores a product
+func (r *ProductRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	return r.update(id, func(p *core.Product) {
+		p.IsActive = !archived
+		if !archived {
+			p.ArchivedAt = nil
+		} else if p.ArchivedAt == nil {
+			now := r.clock.Now()
+			p.ArchivedAt = &now
+		}
+	})
+}
+
+// GetByNames retrieves products whose name exactly matches one of names, keyed by name
+func (r *ProductRepository) GetByNames(ctx context.Context, names []string) (map[string]*core.Product, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	products := make(map[string]*core.Product, len(names))
+	for _, product := range r.filter(func(p *core.Product) bool { return wanted[p.Name] }) {
+		products[product.Name] = product
+	}
+	return products, nil
+}
+
+// UpsertByName inserts new products and updates existing ones matched by name
+func (r *ProductRepository) UpsertByName(ctx context.Context, products []*core.Product, actor string) (int, int, error) {
+	inserted, updated := 0, 0
+	for _, product := range products {
+		existing, err := r.GetByNames(ctx, []string{product.Name})
+		if err != nil {
+			return inserted, updated, err
+		}
+
+		if match, ok := existing[product.Name]; ok {
+			product.ID = match.ID
+			if err := r.setPrice(match.ID, product.Price, actor, core.PriceChangeImport); err != nil {
+				return inserted, updated, err
+			}
+			if err := r.update(match.ID, func(p *core.Product) {
+				p.Category = product.Category
+				p.StockQuantity = product.StockQuantity
+				p.Description = product.Description
+			}); err != nil {
+				return inserted, updated, err
+			}
+			updated++
+			continue
+		}
+
+		product.IsActive = true
+		r.Add(product)
+		inserted++
+	}
+	return inserted, updated, nil
+}
+
+// SetHideWhenSoldOut sets whether a product leaves the menu while sold out
+func (r *ProductRepository) SetHideWhenSoldOut(ctx context.Context, id string, hide *bool) error {
+	return r.update(id, func(p *core.Product) { p.HideWhenSoldOut = hide })
+}
+
+// ClaimRestocked marks menu products that are out of stock and returns up to limit that were marked
+// and have stock again, by name
+func (r *ProductRepository) ClaimRestocked(ctx context.Context, limit int) ([]*core.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var restocked []*core.Product
+	for id, product := range r.products {
+		switch {
+		case !onMenu(product):
+		case product.StockQuantity <= 0:
+			r.soldOut[id] = true
+		case r.soldOut[id]:
+			copied := *product
+			restocked = append(restocked, &copied)
+		}
+	}
+	sort.Slice(restocked, func(i, j int) bool { return restocked[i].Name < restocked[j].Name })
+	if len(restocked) > limit {
+		restocked = restocked[:limit]
+	}
+	for _, product := range restocked {
+		delete(r.soldOut, product.ID)
+	}
+	return restocked, nil
+}
+
+func (r *ProductRepository) update(id string, apply func(p *core.Product)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return fmt.Errorf("product not found")
+	}
+	apply(product)
+	return nil
+}
+
+func (r *ProductRepository) changeStock(id string, reason string, next func(current int) (int, error)) (*core.StockAdjustment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return nil, fmt.Errorf("product not found")
+	}
+	newQuantity, err := next(product.StockQuantity)
+	if err != nil {
+		return nil, err
+	}
+	adjustment := &core.StockAdjustment{ProductID: id, OldQuantity: product.StockQuantity, NewQuantity: newQuantity, Reason: reason}
+	product.StockQuantity = newQuantity
+	return adjustment, nil
+}
+
+// filter returns copies of matching products sorted by name
+func (r *ProductRepository) filter(match func(p *core.Product) bool) []*core.Product {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := make([]*core.Product, 0, len(r.products))
+	for _, product := range r.products {
+		if match(product) {
+			copied := *product
+			products = append(products, &copied)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Name < products[j].Name })
+	return products
+}
+
+func onMenu(p *core.Product) bool {
+	return p.IsActive && p.ArchivedAt == nil
+}
+
+// listed reports whether a menu product is shown to customers: sold-out products set to hide aren't
+func listed(p *core.Product) bool {
+	hide := p.HideWhenSoldOut != nil && *p.HideWhenSoldOut
+	return onMenu(p) && !(hide && p.StockQuantity <= 0)
+}
@@ -0,0 +1,437 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+	"github.com/dumu-tech/destination-cocktails/internal/service"
+)
+
+// CustomerPhone is the WhatsApp number scenarios chat from unless they set their own
+const CustomerPhone = "254712345678"
+
+// TablematePhone is a second customer for scenarios with more than one person chatting
+const TablematePhone = "254733000222"
+
+// Step is one customer message in a Scenario and what the bot should do in reply.
+// Exactly one of Send, Tap, Submit or Pay is set; the Want fields left empty aren't checked.
+type Step struct {
+	Send   string                  // Typed text
+	Tap    string                  // Button or list row ID
+	Submit map[string]string       // Checkout form fields, see Bot.Submit
+	Locate *service.SharedLocation // Shared location pin
+	Pay    bool                    // Confirm the latest order's STK push, as the M-Pesa callback would
+	From   string                  // Sender of this step when not the scenario's phone, e.g. a tablemate
+
+	WantErr    bool
+	WantState  string // Session state after the step
+	WantText   string // Contained in one of the replies
+	WantChoice string // Offered as a button or list row by the last reply
+}
+
+// Scenario drives a conversation through a fresh Bot and checks the outcome.
+// Table-driven tests run each scenario with Run; BotFlowScenarios lists the core ordering flows.
+type Scenario struct {
+	Name     string
+	Phone    string // Defaults to CustomerPhone
+	Products []*core.Product
+	Setup    func(bot *Bot) // Optional: change the bot or its fakes before the first message
+	Steps    []Step
+
+	WantOrders      int              // Orders created
+	WantOrderStatus core.OrderStatus // Status of the latest order, when set
+	WantTotal       float64          // Total of the latest order, when set
+	WantTable       string           // Table number of the latest order, when set
+	WantNotes       string           // Special instructions on the latest order, when set
+	WantPushes      []STKPush        // STK pushes, OrderID not compared
+}
+
+// Run plays the scenario and returns the first mismatch
+func (s Scenario) Run(ctx context.Context) error {
+	phone := s.Phone
+	if phone == "" {
+		phone = CustomerPhone
+	}
+
+	products := make([]*core.Product, len(s.Products))
+	for i, product := range s.Products {
+		copied := *product
+		products[i] = &copied
+	}
+	bot := NewBot(products...)
+	if s.Setup != nil {
+		s.Setup(bot)
+	}
+
+	for i, step := range s.Steps {
+		from := phone
+		if step.From != "" {
+			from = step.From
+		}
+		if err := s.runStep(ctx, bot, from, step); err != nil {
+			return fmt.Errorf("%s: step %d (%s): %w", s.Name, i+1, step.describe(), err)
+		}
+	}
+
+	if err := s.checkOutcome(bot); err != nil {
+		return fmt.Errorf("%s: %w", s.Name, err)
+	}
+	return nil
+}
+
+func (s Scenario) runStep(ctx context.Context, bot *Bot, phone string, step Step) error {
+	sentBefore := len(bot.WhatsApp.Sent(phone))
+
+	var err error
+	switch {
+	case step.Pay:
+		orders := bot.Orders.All()
+		if len(orders) == 0 {
+			return errors.New("no order to pay")
+		}
+		_, err = bot.ConfirmPayment(ctx, orders[len(orders)-1].ID, fmt.Sprintf("REF%d", len(bot.Payment.Pushes())))
+	case step.Tap != "":
+		err = bot.Tap(ctx, phone, step.Tap)
+	case step.Submit != nil:
+		err = bot.Submit(ctx, phone, step.Submit)
+	case step.Locate != nil:
+		err = bot.ShareLocation(ctx, phone, *step.Locate)
+	default:
+		err = bot.Send(ctx, phone, step.Send)
+	}
+
+	if step.WantErr != (err != nil) {
+		return fmt.Errorf("got error %v, want error: %t", err, step.WantErr)
+	}
+
+	if step.WantState != "" {
+		if state := bot.State(ctx, phone); state != step.WantState {
+			return fmt.Errorf("state %q, want %q", state, step.WantState)
+		}
+	}
+
+	replies := bot.WhatsApp.Sent(phone)[sentBefore:]
+	if step.WantText != "" && !slices.ContainsFunc(replies, func(m SentMessage) bool {
+		return strings.Contains(m.Text, step.WantText)
+	}) {
+		return fmt.Errorf("no reply contains %q (got %s)", step.WantText, describeReplies(replies))
+	}
+	if step.WantChoice != "" {
+		if len(replies) == 0 {
+			return fmt.Errorf("no reply, want choice %q", step.WantChoice)
+		}
+		if choices := replies[len(replies)-1].Choices(); !slices.Contains(choices, step.WantChoice) {
+			return fmt.Errorf("last reply offers %v, want %q", choices, step.WantChoice)
+		}
+	}
+	return nil
+}
+
+func (s Scenario) checkOutcome(bot *Bot) error {
+	orders := bot.Orders.All()
+	if len(orders) != s.WantOrders {
+		return fmt.Errorf("%d orders created, want %d", len(orders), s.WantOrders)
+	}
+	if len(orders) > 0 {
+		latest := orders[len(orders)-1]
+		if s.WantOrderStatus != "" && latest.Status != s.WantOrderStatus {
+			return fmt.Errorf("order status %s, want %s", latest.Status, s.WantOrderStatus)
+		}
+		if s.WantTotal != 0 && latest.TotalAmount != s.WantTotal {
+			return fmt.Errorf("order total %.2f, want %.2f", latest.TotalAmount, s.WantTotal)
+		}
+		if s.WantTable != "" && latest.TableNumber != s.WantTable {
+			return fmt.Errorf("order table %q, want %q", latest.TableNumber, s.WantTable)
+		}
+		if s.WantNotes != "" && latest.Notes != s.WantNotes {
+			return fmt.Errorf("order notes %q, want %q", latest.Notes, s.WantNotes)
+		}
+	}
+
+	pushes := bot.Payment.Pushes()
+	if len(pushes) != len(s.WantPushes) {
+		return fmt.Errorf("%d STK pushes, want %d", len(pushes), len(s.WantPushes))
+	}
+	for i, want := range s.WantPushes {
+		if pushes[i].Phone != want.Phone || pushes[i].Amount != want.Amount {
+			return fmt.Errorf("STK push %d to %s for %.2f, want %s for %.2f",
+				i+1, pushes[i].Phone, pushes[i].Amount, want.Phone, want.Amount)
+		}
+	}
+	return nil
+}
+
+func (step Step) describe() string {
+	switch {
+	case step.Pay:
+		return "pay"
+	case step.From != "" && step.Tap != "":
+		return step.From + " taps " + step.Tap
+	case step.From != "" && step.Submit == nil:
+		return fmt.Sprintf("%s sends %q", step.From, step.Send)
+	case step.Tap != "":
+		return "tap " + step.Tap
+	case step.Submit != nil:
+		return fmt.Sprintf("submit %v", step.Submit)
+	case step.Locate != nil:
+		return fmt.Sprintf("share location %.5f,%.5f", step.Locate.Latitude, step.Locate.Longitude)
+	default:
+		return fmt.Sprintf("send %q", step.Send)
+	}
+}
+
+func describeReplies(replies []SentMessage) string {
+	texts := make([]string, len(replies))
+	for i, reply := range replies {
+		texts[i] = fmt.Sprintf("%s %q", reply.Kind, reply.Text)
+	}
+	return "[" + strings.Join(texts, ", ") + "]"
+}
+
+// SampleMenu is a small menu for scenarios: two cocktails (one sold out) and a beer
+func SampleMenu() []*core.Product {
+	return []*core.Product{
+		{ID: "10000000-0000-0000-0000-000000000001", Name: "Mojito", Category: "Cocktails", Price: 800, StockQuantity: 10, IsActive: true},
+		{ID: "10000000-0000-0000-0000-000000000002", Name: "Margarita", Category: "Cocktails", Price: 900, StockQuantity: 0, IsActive: true},
+		{ID: "10000000-0000-0000-0000-000000000003", Name: "Tusker", Category: "Beer", Price: 300, StockQuantity: 3, IsActive: true},
+	}
+}
+
+// BotFlowScenarios covers browse → select → quantity → checkout → payment and its main detours
+func BotFlowScenarios() []Scenario {
+	browseToCheckout := []Step{
+		{Send: "hi", WantState: "BROWSING", WantChoice: "Cocktails"},
+		{Tap: "Cocktails", WantState: "SELECTING_PRODUCT", WantText: "Mojito"},
+		{Send: "2", WantState: "QUANTITY", WantText: "Mojito"}, // Sorted A-Z: Margarita, Mojito
+		{Send: "2", WantState: "CONFIRM_ORDER", WantText: "KES 1600", WantChoice: "checkout"},
+		{Tap: "checkout", WantState: "CONFIRM_ORDER", WantChoice: "pay_self"},
+	}
+	steps := func(extra ...Step) []Step {
+		return append(slices.Clone(browseToCheckout), extra...)
+	}
+
+	return []Scenario{
+		{
+			Name:            "pay with own number",
+			Products:        SampleMenu(),
+			Steps:           steps(Step{Tap: "pay_self", WantState: "START"}, Step{Pay: true}),
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusPaid,
+			WantTotal:       1600,
+			WantPushes:      []STKPush{{Phone: CustomerPhone, Amount: 1600}},
+		},
+		{
+			Name:     "pay with another number",
+			Products: SampleMenu(),
+			Steps: steps(
+				Step{Tap: "pay_other", WantState: "WAITING_FOR_PAYMENT_PHONE"},
+				Step{Send: "12345", WantState: "WAITING_FOR_PAYMENT_PHONE", WantText: "valid phone number"},
+				Step{Send: "0722 000 111", WantState: "START"},
+			),
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusPending,
+			WantPushes:      []STKPush{{Phone: "+254722000111", Amount: 1600}},
+		},
+		{
+			Name:     "add more before checkout",
+			Products: SampleMenu(),
+			Steps: []Step{
+				{Send: "hi", WantState: "BROWSING"},
+				{Tap: "Cocktails", WantState: "SELECTING_PRODUCT"},
+				{Send: "mojito", WantState: "QUANTITY"},
+				{Send: "1", WantState: "CONFIRM_ORDER", WantChoice: "add_more"},
+				{Tap: "add_more", WantState: "BROWSING", WantChoice: "Beer"},
+				{Tap: "Beer", WantState: "SELECTING_PRODUCT", WantText: "Tusker"},
+				{Send: "1", WantState: "QUANTITY"},
+				{Send: "2", WantState: "CONFIRM_ORDER", WantText: "KES 1400"},
+				{Tap: "checkout", WantChoice: "pay_self"},
+				{Tap: "pay_self", WantState: "START"},
+			},
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusPending,
+			WantTotal:       1400,
+			WantPushes:      []STKPush{{Phone: CustomerPhone, Amount: 1400}},
+		},
+		{
+			Name:     "search then order",
+			Products: SampleMenu(),
+			Steps: []Step{
+				{Send: "tusk", WantState: "SELECTING_PRODUCT", WantText: "Tusker"},
+				{Send: "1", WantState: "QUANTITY"},
+				{Send: "1", WantState: "CONFIRM_ORDER", WantText: "KES 300"},
+			},
+		},
+		{
+			Name:     "out of stock and short stock",
+			Products: SampleMenu(),
+			Steps: []Step{
+				{Send: "hi", WantState: "BROWSING"},
+				{Tap: "Cocktails", WantState: "SELECTING_PRODUCT"},
+				{Send: "Margarita", WantState: "SELECTING_PRODUCT", WantText: "out of stock"},
+				{Send: "menu", WantState: "BROWSING"},
+				{Tap: "Beer", WantState: "SELECTING_PRODUCT"},
+				{Send: "Tusker", WantState: "QUANTITY"},
+				{Send: "two", WantState: "QUANTITY", WantText: "valid number"},
+				{Send: "5", WantState: "QUANTITY", WantText: "only 3 available"},
+			},
+		},
+		{
+			Name:     "order notes",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Service.NotesEnabled = true
+			},
+			Steps: []Step{
+				{Send: "tusk", WantState: "SELECTING_PRODUCT"},
+				{Send: "1", WantState: "QUANTITY"},
+				{Send: "1", WantState: "CONFIRM_ORDER"},
+				{Tap: "checkout", WantState: "ORDER_NOTES", WantChoice: "notes_skip"},
+				{Send: strings.Repeat("ice ", 60), WantState: "ORDER_NOTES", WantText: "a bit long"},
+				{Send: "  No ice,\n extra lime ", WantState: "CONFIRM_ORDER", WantChoice: "pay_self"},
+				{Tap: "pay_self", WantState: "START"},
+			},
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusPending,
+			WantNotes:       "No ice, extra lime",
+			WantPushes:      []STKPush{{Phone: CustomerPhone, Amount: 300}},
+		},
+		{
+			Name:     "pre-order for later",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Service.PreOrders = true // Epoch is 23:00 in Nairobi
+			},
+			Steps: []Step{
+				{Send: "tusk", WantState: "SELECTING_PRODUCT"},
+				{Send: "1", WantState: "QUANTITY"},
+				{Send: "1", WantState: "CONFIRM_ORDER"},
+				{Tap: "checkout", WantState: "ORDER_TIME", WantChoice: "schedule_later"},
+				{Tap: "schedule_later", WantState: "ORDER_TIME", WantText: "9pm"},
+				{Send: "25:00", WantState: "ORDER_TIME", WantText: "time like"},
+				{Send: "23:05", WantState: "ORDER_TIME", WantText: "15 minutes"},
+				{Send: "1pm", WantState: "ORDER_TIME", WantText: "12 hours"},
+				{Send: "at 11:45pm tonight", WantState: "CONFIRM_ORDER", WantText: "23:45", WantChoice: "pay_self"},
+				{Tap: "pay_self", WantState: "START"},
+				{Pay: true},
+			},
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusScheduled,
+			WantTotal:       300,
+			WantPushes:      []STKPush{{Phone: CustomerPhone, Amount: 300}},
+		},
+		{
+			Name:     "delivery to a shared location",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Service.Delivery = true
+				bot.Service.DeliveryFee = 200
+			},
+			Steps: []Step{
+				{Locate: &service.SharedLocation{Latitude: -1.2921, Longitude: 36.8219}, WantText: "Menu"},
+				{Send: "tusk", WantState: "SELECTING_PRODUCT"},
+				{Send: "1", WantState: "QUANTITY"},
+				{Send: "1", WantState: "CONFIRM_ORDER"},
+				{Tap: "checkout", WantState: "FULFILMENT", WantText: "KES 200", WantChoice: "fulfil_delivery"},
+				{Tap: "fulfil_delivery", WantState: "DELIVERY_ADDRESS"},
+				{Send: "x", WantState: "DELIVERY_ADDRESS", WantText: "couldn't use"},
+				{Locate: &service.SharedLocation{Latitude: -1.2921, Longitude: 36.8219, Name: "Kilimani Court"}, WantState: "CONFIRM_ORDER", WantText: "Kilimani Court", WantChoice: "pay_self"},
+				{Tap: "pay_self", WantState: "START"},
+				{Pay: true},
+			},
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusPaid,
+			WantTotal:       500,
+			WantPushes:      []STKPush{{Phone: CustomerPhone, Amount: 500}},
+		},
+		{
+			Name:     "group tab",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Service.Tabs = bot.Tabs
+				_ = bot.Tabs.Create(context.Background(), &core.Tab{TableNumber: "12", JoinCode: "K7M2QX", OpenedBy: TablematePhone})
+			},
+			Steps: []Step{
+				{Send: "join NOSUCH", WantText: "NOSUCH"},
+				{Send: "tab", WantChoice: "tab_join"},
+				{Tap: "tab_join", WantState: "TAB_JOIN_CODE"},
+				{Send: "k7m2qx", WantState: "BROWSING", WantText: "table 12"},
+				{Tap: "Beer", WantState: "SELECTING_PRODUCT"},
+				{Send: "Tusker", WantState: "QUANTITY"},
+				{Send: "2", WantState: "CONFIRM_ORDER", WantChoice: "tab_add"},
+				{Tap: "tab_add", WantState: "START", WantText: "KES 600"},
+				{From: TablematePhone, Send: "tusk", WantState: "SELECTING_PRODUCT"},
+				{From: TablematePhone, Send: "1", WantState: "QUANTITY"},
+				{From: TablematePhone, Send: "1", WantState: "CONFIRM_ORDER", WantChoice: "tab_add"},
+				{From: TablematePhone, Tap: "tab_add", WantText: "KES 900"},
+				{Tap: "tab_leave", WantText: "KES 600"},
+				{Tap: "tab_pay_mine", WantState: "CONFIRM_ORDER", WantChoice: "pay_self"},
+				{Tap: "pay_self", WantState: "START"},
+				{Pay: true},
+				{From: TablematePhone, Tap: "tab_pay_all", WantState: "CONFIRM_ORDER", WantText: "KES 300"},
+				{From: TablematePhone, Tap: "pay_self", WantState: "START"},
+				{Tap: "tab_leave", WantText: "left the tab"},
+			},
+			WantOrders:      2,
+			WantOrderStatus: core.OrderStatusPending,
+			WantTotal:       300,
+			WantTable:       "12",
+			WantPushes:      []STKPush{{Phone: CustomerPhone, Amount: 600}, {Phone: TablematePhone, Amount: 300}},
+		},
+		{
+			Name:     "payment system busy",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Payment.FailWith(errors.New("stk queue full"))
+			},
+			Steps:           steps(Step{Tap: "pay_self", WantErr: true, WantState: "CONFIRM_ORDER", WantText: "Payment system busy"}),
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusFailed,
+		},
+		{
+			Name:     "checkout form",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Service.CheckoutFlowID = "checkout-flow"
+			},
+			Steps: []Step{
+				{Send: "tusk", WantState: "SELECTING_PRODUCT"},
+				{Send: "1", WantState: "QUANTITY", WantText: "Order Form"},
+				{Submit: map[string]string{"quantity": "5", "payment_phone": "0722000111"}, WantState: "QUANTITY", WantText: "only 3 available"},
+				{Submit: map[string]string{"quantity": "2", "payment_phone": "12345"}, WantState: "QUANTITY", WantText: "doesn't look right"},
+				{Submit: map[string]string{"quantity": "2", "table_number": "7", "payment_phone": "0722 000 111"}, WantState: "CONFIRM_ORDER", WantText: "KES 600"},
+				{Submit: map[string]string{"quantity": "1", "payment_phone": "0722000111"}, WantState: "CONFIRM_ORDER", WantText: "earlier selection"},
+				{Tap: "checkout", WantChoice: "pay_form"},
+				{Tap: "pay_form", WantState: "START"},
+			},
+			WantOrders:      1,
+			WantOrderStatus: core.OrderStatusPending,
+			WantTotal:       600,
+			WantTable:       "7",
+			WantPushes:      []STKPush{{Phone: "+254722000111", Amount: 600}},
+		},
+		{
+			Name:     "checkout form unavailable",
+			Products: SampleMenu(),
+			Setup: func(bot *Bot) {
+				bot.Service.CheckoutFlowID = "checkout-flow"
+				bot.WhatsApp.FailFlows(errors.New("flows not enabled"))
+			},
+			Steps: []Step{
+				{Send: "tusk", WantState: "SELECTING_PRODUCT"},
+				{Send: "1", WantState: "QUANTITY", WantText: "How many"},
+				{Send: "1", WantState: "CONFIRM_ORDER", WantText: "KES 300"},
+			},
+		},
+		{
+			Name:     "expired session on button tap",
+			Products: SampleMenu(),
+			Steps: []Step{
+				{Tap: "checkout", WantState: "BROWSING", WantChoice: "Cocktails"},
+			},
+		},
+	}
+}
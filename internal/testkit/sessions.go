@@ -0,0 +1,86 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// SessionRepository is an in-memory core.SessionRepository. Sessions are stored as JSON, like
+// the Redis repository, so the bot never shares a *core.Session between messages. TTLs are ignored.
+type SessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string][]byte
+}
+
+// NewSessionRepository creates an empty session repository
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{sessions: make(map[string][]byte)}
+}
+
+// Get retrieves the session for phone
+func (r *SessionRepository) Get(ctx context.Context, phone string) (*core.Session, error) {
+	r.mu.Lock()
+	data, ok := r.sessions[phone]
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("session not found")
+	}
+
+	var session core.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// Set stores the session for phone
+func (r *SessionRepository) Set(ctx context.Context, phone string, session *core.Session, ttl int) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[phone] = data
+	return nil
+}
+
+// Delete removes the session for phone
+func (r *SessionRepository) Delete(ctx context.Context, phone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, phone)
+	return nil
+}
+
+// UpdateStep updates the state of an existing session
+func (r *SessionRepository) UpdateStep(ctx context.Context, phone string, step string) error {
+	session, err := r.Get(ctx, phone)
+	if err != nil {
+		return err
+	}
+	session.State = step
+	return r.Set(ctx, phone, session, 0)
+}
+
+// UpdateCart replaces the cart of an existing session with the JSON-encoded cartItems
+func (r *SessionRepository) UpdateCart(ctx context.Context, phone string, cartItems string) error {
+	session, err := r.Get(ctx, phone)
+	if err != nil {
+		return err
+	}
+
+	var cart []core.CartItem
+	if cartItems != "" {
+		if err := json.Unmarshal([]byte(cartItems), &cart); err != nil {
+			return fmt.Errorf("failed to unmarshal cart: %w", err)
+		}
+	}
+	session.Cart = cart
+	return r.Set(ctx, phone, session, 0)
+}
@@ -0,0 +1,235 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// TabRepository is an in-memory core.TabRepository.
+// Item order statuses are read from orders, as the Postgres repository joins them.
+type TabRepository struct {
+	mu     sync.Mutex
+	tabs   map[string]*core.Tab
+	orders *OrderRepository
+	clock  core.Clock
+	ids    core.IDGenerator
+}
+
+// NewTabRepository creates an empty tab repository whose items are paid through orders
+func NewTabRepository(orders *OrderRepository, clock core.Clock, ids core.IDGenerator) *TabRepository {
+	return &TabRepository{tabs: make(map[string]*core.Tab), orders: orders, clock: clock, ids: ids}
+}
+
+// Create opens a tab with its opener as the first member
+func (r *TabRepository) Create(ctx context.Context, tab *core.Tab) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	if tab.ID == "" {
+		tab.ID = r.ids.NewID()
+	}
+	if tab.Status == "" {
+		tab.Status = core.TabStatusOpen
+	}
+	tab.CreatedAt = now
+	tab.Members = []core.TabMember{{Phone: tab.OpenedBy, JoinedAt: now}}
+	tab.Items = []core.TabItem{}
+
+	stored := *tab
+	stored.Members = slices.Clone(tab.Members)
+	stored.Items = []core.TabItem{}
+	r.tabs[stored.ID] = &stored
+	return nil
+}
+
+// GetByID retrieves a tab with its members and items
+func (r *TabRepository) GetByID(ctx context.Context, id string) (*core.Tab, error) {
+	r.mu.Lock()
+	tab, ok := r.tabs[id]
+	var copied *core.Tab
+	if ok {
+		copied = r.copyTab(tab)
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("tab not found")
+	}
+	return r.withOrderStatuses(ctx, copied), nil
+}
+
+// GetOpenByJoinCode retrieves the open tab using a join code, or nil when there is none
+func (r *TabRepository) GetOpenByJoinCode(ctx context.Context, code string) (*core.Tab, error) {
+	return r.firstOpen(ctx, func(tab *core.Tab) bool { return tab.JoinCode == code }), nil
+}
+
+// GetOpenByMember retrieves the open tab a phone is on, or nil when there is none
+func (r *TabRepository) GetOpenByMember(ctx context.Context, phone string) (*core.Tab, error) {
+	return r.firstOpen(ctx, func(tab *core.Tab) bool { return tab.HasMember(phone) }), nil
+}
+
+// ListOpen retrieves every open tab with members and items, by table number
+func (r *TabRepository) ListOpen(ctx context.Context) ([]*core.Tab, error) {
+	tabs := r.open(func(tab *core.Tab) bool { return true })
+	sort.SliceStable(tabs, func(i, j int) bool {
+		if tabs[i].TableNumber != tabs[j].TableNumber {
+			return tabs[i].TableNumber < tabs[j].TableNumber
+		}
+		return tabs[i].CreatedAt.Before(tabs[j].CreatedAt)
+	})
+	for i, tab := range tabs {
+		tabs[i] = r.withOrderStatuses(ctx, tab)
+	}
+	return tabs, nil
+}
+
+// AddMember puts a phone on a tab; joining twice is a no-op
+func (r *TabRepository) AddMember(ctx context.Context, tabID string, phone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tab, ok := r.tabs[tabID]
+	if !ok {
+		return fmt.Errorf("tab not found")
+	}
+	if !tab.HasMember(phone) {
+		tab.Members = append(tab.Members, core.TabMember{Phone: phone, JoinedAt: r.clock.Now()})
+	}
+	return nil
+}
+
+// RemoveMember takes a phone off a tab and returns how many members are left
+func (r *TabRepository) RemoveMember(ctx context.Context, tabID string, phone string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tab, ok := r.tabs[tabID]
+	if !ok {
+		return 0, fmt.Errorf("tab not found")
+	}
+	tab.Members = slices.DeleteFunc(tab.Members, func(member core.TabMember) bool { return member.Phone == phone })
+	return len(tab.Members), nil
+}
+
+// AddItems puts cart items on a tab under the member who added them
+func (r *TabRepository) AddItems(ctx context.Context, tabID string, phone string, items []core.CartItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tab, ok := r.tabs[tabID]
+	if !ok {
+		return fmt.Errorf("tab not found")
+	}
+	now := r.clock.Now()
+	for _, item := range items {
+		tab.Items = append(tab.Items, core.TabItem{
+			ID:        r.ids.NewID(),
+			TabID:     tabID,
+			Phone:     phone,
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			Modifiers: slices.Clone(item.Modifiers),
+			CreatedAt: now,
+		})
+	}
+	return nil
+}
+
+// ClaimItems puts the unpaid items among itemIDs on orderID and returns how many were claimed
+func (r *TabRepository) ClaimItems(ctx context.Context, tabID string, itemIDs []string, orderID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tab, ok := r.tabs[tabID]
+	if !ok {
+		return 0, nil
+	}
+	claimed := 0
+	for i := range tab.Items {
+		item := &tab.Items[i]
+		if !slices.Contains(itemIDs, item.ID) || !r.unpaid(ctx, item.OrderID) {
+			continue
+		}
+		item.OrderID = orderID
+		claimed++
+	}
+	return claimed, nil
+}
+
+// Close stops a tab taking drinks or members
+func (r *TabRepository) Close(ctx context.Context, tabID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tab, ok := r.tabs[tabID]
+	if !ok || tab.Status != core.TabStatusOpen {
+		return fmt.Errorf("open tab not found")
+	}
+	closedAt := r.clock.Now()
+	tab.Status = core.TabStatusClosed
+	tab.ClosedAt = &closedAt
+	return nil
+}
+
+func (r *TabRepository) firstOpen(ctx context.Context, match func(tab *core.Tab) bool) *core.Tab {
+	tabs := r.open(match)
+	if len(tabs) == 0 {
+		return nil
+	}
+	sort.SliceStable(tabs, func(i, j int) bool { return tabs[i].CreatedAt.After(tabs[j].CreatedAt) })
+	return r.withOrderStatuses(ctx, tabs[0])
+}
+
+func (r *TabRepository) open(match func(tab *core.Tab) bool) []*core.Tab {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var tabs []*core.Tab
+	for _, tab := range r.tabs {
+		if tab.Status == core.TabStatusOpen && match(tab) {
+			tabs = append(tabs, r.copyTab(tab))
+		}
+	}
+	return tabs
+}
+
+// withOrderStatuses fills in each item's order status; called without r.mu held
+func (r *TabRepository) withOrderStatuses(ctx context.Context, tab *core.Tab) *core.Tab {
+	for i := range tab.Items {
+		tab.Items[i].OrderStatus = ""
+		if tab.Items[i].OrderID == "" {
+			continue
+		}
+		if order, err := r.orders.GetByID(ctx, tab.Items[i].OrderID); err == nil {
+			tab.Items[i].OrderStatus = order.Status
+		}
+	}
+	return tab
+}
+
+// unpaid reports whether an item on orderID can be claimed: no order, a missing one, or one that failed
+func (r *TabRepository) unpaid(ctx context.Context, orderID string) bool {
+	if orderID == "" {
+		return true
+	}
+	order, err := r.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return true
+	}
+	return order.Status == core.OrderStatusFailed || order.Status == core.OrderStatusCancelled
+}
+
+func (r *TabRepository) copyTab(tab *core.Tab) *core.Tab {
+	copied := *tab
+	copied.Members = slices.Clone(tab.Members)
+	copied.Items = slices.Clone(tab.Items)
+	return &copied
+}
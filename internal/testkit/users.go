@@ -0,0 +1,122 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// UserRepository is an in-memory core.UserRepository
+type UserRepository struct {
+	mu    sync.Mutex
+	users map[string]*core.User // Keyed by ID
+	clock core.Clock
+	ids   core.IDGenerator
+}
+
+// NewUserRepository creates an empty user repository
+func NewUserRepository(clock core.Clock, ids core.IDGenerator) *UserRepository {
+	return &UserRepository{users: make(map[string]*core.User), clock: clock, ids: ids}
+}
+
+// GetByPhone retrieves a user by phone number
+func (r *UserRepository) GetByPhone(ctx context.Context, phone string) (*core.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.PhoneNumber == phone {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+// Create stores a new user; the language defaults to English
+func (r *UserRepository) Create(ctx context.Context, user *core.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *user
+	if stored.ID == "" {
+		stored.ID = r.ids.NewID()
+		user.ID = stored.ID
+	}
+	if stored.Language == "" {
+		stored.Language = "en"
+	}
+	r.users[stored.ID] = &stored
+	return nil
+}
+
+// GetOrCreateByPhone retrieves a user by phone or creates one if not found
+func (r *UserRepository) GetOrCreateByPhone(ctx context.Context, phone string) (*core.User, error) {
+	if user, err := r.GetByPhone(ctx, phone); err == nil {
+		return user, nil
+	}
+
+	user := &core.User{PhoneNumber: phone, CreatedAt: r.clock.Now()}
+	if err := r.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*core.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// UpdateLanguage stores a user's preferred bot language
+func (r *UserRepository) UpdateLanguage(ctx context.Context, id string, language string) error {
+	return r.update(id, func(u *core.User) { u.Language = language })
+}
+
+// SetCartRemindersOptOut records whether a customer wants abandoned cart reminders
+func (r *UserRepository) SetCartRemindersOptOut(ctx context.Context, id string, optOut bool) error {
+	return r.update(id, func(u *core.User) { u.CartRemindersOptOut = optOut })
+}
+
+// SetMarketingOptIn records whether a customer consents to broadcast offers
+func (r *UserRepository) SetMarketingOptIn(ctx context.Context, id string, optIn bool) error {
+	now := r.clock.Now()
+	return r.update(id, func(u *core.User) {
+		u.MarketingOptIn = optIn
+		if optIn {
+			u.MarketingOptInAt = &now
+		}
+	})
+}
+
+// ConfirmAge stamps the customer's 18+ confirmation; a second call keeps the first time
+func (r *UserRepository) ConfirmAge(ctx context.Context, id string) error {
+	now := r.clock.Now()
+	return r.update(id, func(u *core.User) {
+		if u.AgeConfirmedAt == nil {
+			u.AgeConfirmedAt = &now
+		}
+	})
+}
+
+func (r *UserRepository) update(id string, apply func(u *core.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return fmt.Errorf("user not found")
+	}
+	apply(user)
+	return nil
+}
@@ -0,0 +1,172 @@
+package testkit
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/dumu-tech/destination-cocktails/internal/core"
+)
+
+// Message kinds recorded by WhatsAppGateway, one per gateway method
+const (
+	KindText         = "text"
+	KindMenu         = "menu"
+	KindCategoryList = "category_list"
+	KindProductList  = "product_list"
+	KindButtons      = "buttons"
+	KindListRows     = "list_rows"
+	KindDocument     = "document"
+	KindFlow         = "flow"
+)
+
+// SentMessage is one message the bot sent through WhatsAppGateway
+type SentMessage struct {
+	Kind       string
+	Phone      string
+	Text       string // Body text, list header or document caption
+	Buttons    []core.Button
+	Rows       []core.ListRow
+	Categories []string
+	Products   []*core.Product
+	Filename   string
+	Document   []byte
+	Flow       core.Flow
+}
+
+// Choices lists the IDs a customer could tap in reply: button IDs, list row IDs or categories
+func (m SentMessage) Choices() []string {
+	var choices []string
+	for _, button := range m.Buttons {
+		choices = append(choices, button.ID)
+	}
+	for _, row := range m.Rows {
+		choices = append(choices, row.ID)
+	}
+	return append(choices, m.Categories...)
+}
+
+// WhatsAppGateway is a core.WhatsAppGateway that records every message instead of sending it.
+// Like the Cloud API client it also sends interactive lists (SendListRows) and Flows (SendFlow).
+type WhatsAppGateway struct {
+	mu      sync.Mutex
+	sent    []SentMessage
+	err     error
+	flowErr error
+}
+
+// NewWhatsAppGateway creates a recording gateway
+func NewWhatsAppGateway() *WhatsAppGateway {
+	return &WhatsAppGateway{}
+}
+
+// FailWith makes every later send return err (nil restores sending)
+func (g *WhatsAppGateway) FailWith(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.err = err
+}
+
+// FailFlows makes later SendFlow calls fail with err, as when Flows isn't enabled for the number
+func (g *WhatsAppGateway) FailFlows(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.flowErr = err
+}
+
+// Sent returns the messages sent to phone, oldest first; an empty phone returns every message
+func (g *WhatsAppGateway) Sent(phone string) []SentMessage {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var sent []SentMessage
+	for _, message := range g.sent {
+		if phone == "" || message.Phone == phone {
+			sent = append(sent, message)
+		}
+	}
+	return sent
+}
+
+// Last returns the latest message sent to phone
+func (g *WhatsAppGateway) Last(phone string) (SentMessage, bool) {
+	sent := g.Sent(phone)
+	if len(sent) == 0 {
+		return SentMessage{}, false
+	}
+	return sent[len(sent)-1], true
+}
+
+// Contains reports whether any message to phone includes text in its body
+func (g *WhatsAppGateway) Contains(phone string, text string) bool {
+	for _, message := range g.Sent(phone) {
+		if strings.Contains(message.Text, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset forgets the recorded messages
+func (g *WhatsAppGateway) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sent = nil
+}
+
+// SendText records a text message
+func (g *WhatsAppGateway) SendText(ctx context.Context, phone string, message string) error {
+	return g.record(SentMessage{Kind: KindText, Phone: phone, Text: message})
+}
+
+// SendMenu records a product menu
+func (g *WhatsAppGateway) SendMenu(ctx context.Context, phone string, products []*core.Product) error {
+	return g.record(SentMessage{Kind: KindMenu, Phone: phone, Products: products})
+}
+
+// SendCategoryList records a category list
+func (g *WhatsAppGateway) SendCategoryList(ctx context.Context, phone string, categories []string) error {
+	return g.record(SentMessage{Kind: KindCategoryList, Phone: phone, Categories: append([]string(nil), categories...)})
+}
+
+// SendProductList records a product list for a category
+func (g *WhatsAppGateway) SendProductList(ctx context.Context, phone string, category string, products []*core.Product) error {
+	return g.record(SentMessage{Kind: KindProductList, Phone: phone, Text: category, Products: products})
+}
+
+// SendMenuButtons records a message with quick reply buttons
+func (g *WhatsAppGateway) SendMenuButtons(ctx context.Context, phone string, text string, buttons []core.Button) error {
+	return g.record(SentMessage{Kind: KindButtons, Phone: phone, Text: text, Buttons: append([]core.Button(nil), buttons...)})
+}
+
+// SendListRows records an interactive list message
+func (g *WhatsAppGateway) SendListRows(ctx context.Context, phone string, text string, buttonLabel string, rows []core.ListRow) error {
+	return g.record(SentMessage{Kind: KindListRows, Phone: phone, Text: text, Rows: append([]core.ListRow(nil), rows...)})
+}
+
+// SendDocument records a document such as a receipt
+func (g *WhatsAppGateway) SendDocument(ctx context.Context, phone string, filename string, data []byte, caption string) error {
+	return g.record(SentMessage{Kind: KindDocument, Phone: phone, Text: caption, Filename: filename, Document: data})
+}
+
+// SendFlow records a WhatsApp Flows form
+func (g *WhatsAppGateway) SendFlow(ctx context.Context, phone string, flow core.Flow) error {
+	g.mu.Lock()
+	flowErr := g.flowErr
+	g.mu.Unlock()
+	if flowErr != nil {
+		return flowErr
+	}
+	return g.record(SentMessage{Kind: KindFlow, Phone: phone, Text: flow.Body, Flow: flow})
+}
+
+func (g *WhatsAppGateway) record(message SentMessage) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return g.err
+	}
+	g.sent = append(g.sent, message)
+	return nil
+}
@@ -0,0 +1,32 @@
+-- Migration: 012_create_bar_staff_roster.sql
+-- Description: Bar staff roster for paid-order notifications and order acceptance tracking
+-- Created: 2026-02-24
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS bar_staff (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(20) UNIQUE NOT NULL,
+    is_on_shift BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    last_notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bar_staff_on_shift ON bar_staff(is_active, is_on_shift);
+
+-- Record which bartender accepted a paid order from the WhatsApp notification.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS accepted_by_staff_id UUID REFERENCES bar_staff(id),
+    ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_accepted_by_staff_id ON orders(accepted_by_staff_id);
+
+-- Carry over the legacy single BAR_STAFF_PHONE recipient so notifications keep flowing.
+INSERT INTO bar_staff (name, phone_number, is_on_shift, is_active)
+VALUES ('Bar Staff', '254735537873', true, true)
+ON CONFLICT (phone_number) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- Migration: 013_create_payments_ledger.sql
+-- Description: Ledger of every webhook-confirmed payment, matched or orphaned
+-- Created: 2026-02-25
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider VARCHAR(50) NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    hashed_phone VARCHAR(128) NOT NULL DEFAULT '',
+    payer_name VARCHAR(255) NOT NULL DEFAULT '',
+    amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(10) NOT NULL DEFAULT 'KES',
+    status VARCHAR(50) NOT NULL DEFAULT '',
+    order_id UUID REFERENCES orders(id),
+    is_orphan BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Webhook retries carry the same reference; record each transaction once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_reference
+    ON payments(provider, reference)
+    WHERE reference <> '';
+
+CREATE INDEX IF NOT EXISTS idx_payments_created_at ON payments(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments(order_id);
+CREATE INDEX IF NOT EXISTS idx_payments_orphan ON payments(is_orphan) WHERE is_orphan = true;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Migration: 014_widen_pickup_code.sql
+-- Description: Allow longer / alphanumeric pickup codes (PICKUP_CODE_LENGTH up to 8)
+-- Created: 2026-02-26
+
+BEGIN;
+
+ALTER TABLE orders ALTER COLUMN pickup_code TYPE VARCHAR(8);
+
+COMMIT;
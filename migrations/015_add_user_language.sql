@@ -0,0 +1,9 @@
+-- Migration: 015_add_user_language.sql
+-- Description: Store each customer's preferred bot language (en, sw)
+-- Created: 2026-02-27
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(5) NOT NULL DEFAULT 'en';
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Migration: 016_add_otp_attempts.sql
+-- Description: Track failed verification attempts per OTP code (brute-force protection)
+-- Created: 2026-03-02
+
+BEGIN;
+
+ALTER TABLE otp_codes ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+
+-- Per-phone request throttling counts recent codes by phone + created_at
+CREATE INDEX IF NOT EXISTS idx_otp_codes_phone_created_at ON otp_codes(phone_number, created_at);
+
+COMMIT;
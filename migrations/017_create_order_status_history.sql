@@ -0,0 +1,20 @@
+-- Migration: 017_create_order_status_history.sql
+-- Description: Record every order status transition (who, when, why) for dispute investigation
+-- Created: 2026-03-03
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    -- Admin user ID for dashboard actions, otherwise 'system' or 'webhook'
+    actor VARCHAR(64) NOT NULL DEFAULT 'system',
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, created_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Migration: 018_add_orders_status_created_at_index.sql
+-- Description: Composite index for report range queries (status IN (...) AND created_at window)
+-- Created: 2026-03-04
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at ON orders(status, created_at);
+
+COMMIT;
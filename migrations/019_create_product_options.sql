@@ -0,0 +1,26 @@
+-- Migration: 019_create_product_options.sql
+-- Description: Serving options per product (size, ice, mixer) and the choices stored on each order item
+-- Created: 2026-03-05
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS product_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    group_name VARCHAR(50) NOT NULL,
+    label VARCHAR(20) NOT NULL, -- WhatsApp reply buttons allow 20 characters
+    price_delta DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_options_product
+    ON product_options(product_id, sort_order);
+
+-- Snapshot of the options chosen for the item, e.g. [{"group":"Size","label":"Double","price_delta":200}].
+-- Stored as JSON so later edits to product_options don't rewrite past orders.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS modifiers JSONB;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Migration: 020_create_bundle_items.sql
+-- Description: Combo products (e.g. "Gin + 2 Tonics") and the component products they are made from
+-- Created: 2026-03-06
+
+BEGIN;
+
+-- A combo is a regular product in the "Combos" category with its own price;
+-- bundle_items lists what goes into one combo.
+CREATE TABLE IF NOT EXISTS bundle_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bundle_product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_product_id UUID NOT NULL REFERENCES products(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bundle_product_id, component_product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_items_component ON bundle_items(component_product_id);
+
+-- Snapshot of the components in one unit of a combo order item, e.g.
+-- [{"product_id":"...","product_name":"Gordon's Gin","quantity":1}].
+-- The order item itself keeps the combo product so receipts show the combo name.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS components JSONB;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Migration: 021_add_order_tax.sql
+-- Description: VAT charged on each order and order item, for receipts and tax returns
+-- Created: 2026-03-07
+
+BEGIN;
+
+-- tax_rate is the VAT percentage in force when the order was placed (e.g. 16.00),
+-- so historical receipts and returns don't change when the configured rate does.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Migration: 022_add_user_reminder_opt_out.sql
+-- Description: Let customers opt out of abandoned cart reminders
+-- Created: 2026-03-08
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS cart_reminders_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Migration: 023_add_pickup_reminders.sql
+-- Description: Track pickup reminders for READY orders and escalation to bar staff
+-- Created: 2026-03-09
+
+BEGIN;
+
+-- How many "your order is waiting" reminders the customer has been sent
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS ready_reminders_sent SMALLINT NOT NULL DEFAULT 0;
+-- Set when an uncollected order was flagged to bar staff
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pickup_escalated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_ready_at ON orders(ready_at) WHERE status = 'READY';
+
+COMMIT;
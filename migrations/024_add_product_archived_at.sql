@@ -0,0 +1,15 @@
+-- Migration: 024_add_product_archived_at.sql
+-- Description: Soft-delete products via archived_at instead of the 'Archived' category workaround
+-- Created: 2026-03-10
+
+BEGIN;
+
+-- Archived products are hidden from the menu and search but kept so order_items still join
+ALTER TABLE products ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+-- Backfill products archived by earlier migrations (e.g. 002 Cognac)
+UPDATE products
+SET archived_at = COALESCE(updated_at, CURRENT_TIMESTAMP)
+WHERE category = 'Archived' AND is_active = false AND archived_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Migration: 025_add_admin_token_version.sql
+-- Description: Per-admin token version so deactivation or a role change invalidates issued JWTs immediately
+-- Created: 2026-03-11
+
+BEGIN;
+
+-- Copied into every access token as "ver"; bumping it makes older tokens fail AuthMiddleware
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Migration: 026_create_stk_attempts.sql
+-- Description: One row per STK push sent to Kopo Kopo, with the incoming payment ID used to match its callback
+-- Created: 2026-03-12
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS stk_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    amount DECIMAL(10, 2) NOT NULL,
+    -- SENT (accepted by Kopo Kopo), REJECTED (never reached the customer), then SUCCESS or FAILED from the callback
+    status VARCHAR(20) NOT NULL,
+    payment_request_id VARCHAR(100) NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    http_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stk_attempts_order_id ON stk_attempts(order_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_stk_attempts_payment_request_id
+    ON stk_attempts(payment_request_id)
+    WHERE payment_request_id <> '';
+
+COMMIT;
@@ -0,0 +1,35 @@
+-- Migration: 027_add_order_payment_shares.sql
+-- Description: Track multiple payments per order (split bills) and the running amount paid
+-- Created: 2026-03-13
+
+BEGIN;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS amount_paid DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Orders confirmed before this migration were paid in full by a single payment
+UPDATE orders
+SET amount_paid = total_amount
+WHERE amount_paid = 0
+  AND status IN ('PAID', 'READY', 'COMPLETED');
+
+CREATE TABLE IF NOT EXISTS order_payment_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    amount DECIMAL(10, 2) NOT NULL,
+    -- PENDING (split share waiting for its payer), PAID or FAILED
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payment_shares_order_id ON order_payment_shares(order_id, created_at);
+
+-- A payment reference is applied to an order at most once, so webhook retries can't double count
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_payment_shares_reference
+    ON order_payment_shares(order_id, reference)
+    WHERE reference <> '';
+
+COMMIT;
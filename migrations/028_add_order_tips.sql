@@ -0,0 +1,9 @@
+-- Migration: 028_add_order_tips.sql
+-- Description: Tip chosen at checkout, charged with the order but kept out of product revenue
+-- Created: 2026-03-14
+
+BEGIN;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tip_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+COMMIT;
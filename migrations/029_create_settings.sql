@@ -0,0 +1,16 @@
+-- Migration: 029_create_settings.sql
+-- Description: Runtime settings managers can change from the dashboard without a redeploy
+-- Created: 2026-03-15
+
+BEGIN;
+
+-- One row per overridden setting; settings without a row use their built-in default.
+-- Values are stored as text and typed by the application (e.g. '45', 'true').
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by VARCHAR(100) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
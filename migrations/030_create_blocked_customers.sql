@@ -0,0 +1,21 @@
+-- Migration: 030_create_blocked_customers.sql
+-- Description: Per-customer blocklist, plus phones flagged automatically after repeated failed payments
+-- Created: 2026-03-16
+
+BEGIN;
+
+-- One row per phone (stored as +2547xxxxxxxx).
+-- BLOCKED phones are ignored or declined by the bot; FLAGGED phones are only listed for a manager to review.
+CREATE TABLE IF NOT EXISTS blocked_customers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    phone VARCHAR(20) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'BLOCKED' CHECK (status IN ('BLOCKED', 'FLAGGED')),
+    reason TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocked_customers_status ON blocked_customers(status);
+
+COMMIT;
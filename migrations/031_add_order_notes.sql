@@ -0,0 +1,9 @@
+-- Migration: 031_add_order_notes.sql
+-- Description: Special instructions the customer added before checkout (e.g. "no ice", "extra lime")
+-- Created: 2026-03-16
+
+BEGIN;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- Migration: 032_create_tabs.sql
+-- Description: Shared tabs that several customers at one table add drinks to and pay together or share by share
+-- Created: 2026-03-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tabs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    table_number VARCHAR(20) NOT NULL,
+    join_code VARCHAR(8) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CLOSED')),
+    opened_by VARCHAR(20) NOT NULL, -- WhatsApp phone of the customer who started the tab
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+-- Join codes are only shared while a tab is open, so closed tabs can reuse them
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tabs_open_join_code ON tabs(join_code) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_tabs_status ON tabs(status);
+
+CREATE TABLE IF NOT EXISTS tab_members (
+    tab_id UUID NOT NULL REFERENCES tabs(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tab_id, phone)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tab_members_phone ON tab_members(phone);
+
+-- order_id is set when a member checks the item out. It has no foreign key: it is written just before the
+-- order is inserted, and an item whose order is missing, FAILED or CANCELLED counts as unpaid again.
+CREATE TABLE IF NOT EXISTS tab_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tab_id UUID NOT NULL REFERENCES tabs(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL, -- Member who added the item
+    product_id UUID NOT NULL REFERENCES products(id),
+    name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price DECIMAL(10, 2) NOT NULL, -- Unit price including serving option adjustments
+    modifiers JSONB,
+    order_id UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tab_items_tab_id ON tab_items(tab_id);
+CREATE INDEX IF NOT EXISTS idx_tab_items_order_id ON tab_items(order_id);
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Migration: 033_add_order_scheduled_for.sql
+-- Description: Pre-orders for later pickup; paid orders wait in SCHEDULED until shortly before scheduled_for
+-- Created: 2026-03-16
+
+BEGIN;
+
+-- NULL for orders wanted straight away
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMP;
+
+-- The scheduler polls for SCHEDULED orders coming due
+CREATE INDEX IF NOT EXISTS idx_orders_scheduled_for ON orders(scheduled_for) WHERE status = 'SCHEDULED';
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Migration: 034_add_order_delivery.sql
+-- Description: Delivery orders; the address or shared location, the delivery fee, and OUT_FOR_DELIVERY/DELIVERED statuses
+-- Created: 2026-03-16
+
+BEGIN;
+
+-- Empty for orders collected at the bar
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_address TEXT NOT NULL DEFAULT '';
+
+-- Set when the customer shared a WhatsApp location pin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_latitude DOUBLE PRECISION;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_longitude DOUBLE PRECISION;
+
+-- Included in total_amount; like tips it carries no VAT and isn't product revenue
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_fee DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+COMMIT;
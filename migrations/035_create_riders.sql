@@ -0,0 +1,26 @@
+-- Migration: 035_create_riders.sql
+-- Description: Delivery riders roster; available riders are offered dispatched orders and the first to accept is assigned
+-- Created: 2026-03-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS riders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(20) UNIQUE NOT NULL,
+    is_available BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_riders_available ON riders(is_active, is_available);
+
+-- Record which rider accepted a delivery order from the WhatsApp notification.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS rider_id UUID REFERENCES riders(id),
+    ADD COLUMN IF NOT EXISTS rider_assigned_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_rider_id ON orders(rider_id);
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- Migration: 036_create_recipes.sql
+-- Description: Cocktail recipes measured from bottle stock; paid cocktail orders deduct their ingredients
+-- Created: 2026-03-16
+
+BEGIN;
+
+-- One row per ingredient in a cocktail, e.g. Mojito -> White Rum 50 ml, Soda 1 count.
+CREATE TABLE IF NOT EXISTS recipes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cocktail_product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    ingredient_product_id UUID NOT NULL REFERENCES products(id),
+    measure DECIMAL(10, 2) NOT NULL CHECK (measure > 0),
+    unit VARCHAR(10) NOT NULL CHECK (unit IN ('ml', 'count')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (cocktail_product_id, ingredient_product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipes_ingredient ON recipes(ingredient_product_id);
+
+-- stock_quantity stays in whole bottles; poured_ml is what has been poured from the open one.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS bottle_ml INTEGER CHECK (bottle_ml > 0),
+    ADD COLUMN IF NOT EXISTS poured_ml DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Set once an order's ingredients have been deducted so no payment path deducts twice.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stock_deducted_at TIMESTAMP;
+
+COMMIT;
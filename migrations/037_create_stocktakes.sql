@@ -0,0 +1,47 @@
+-- Migration: 037_create_stocktakes.sql
+-- Description: Stocktakes (physical counts against system stock) and an audit trail of stock adjustments
+-- Created: 2026-03-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS stocktakes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'APPLIED', 'CANCELLED')),
+    note TEXT,
+    started_by VARCHAR(64) NOT NULL,
+    applied_by VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP
+);
+
+-- Counting happens in one stocktake at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stocktakes_one_open ON stocktakes(status) WHERE status = 'OPEN';
+
+-- system_quantity is the stock when the line was counted; variance = counted - system.
+CREATE TABLE IF NOT EXISTS stocktake_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stocktake_id UUID NOT NULL REFERENCES stocktakes(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    system_quantity INTEGER NOT NULL,
+    counted_quantity INTEGER NOT NULL CHECK (counted_quantity >= 0),
+    variance INTEGER NOT NULL,
+    counted_by VARCHAR(64) NOT NULL,
+    counted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (stocktake_id, product_id)
+);
+
+-- Every stock change a stocktake applies, with the quantities before and after.
+CREATE TABLE IF NOT EXISTS stock_adjustments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id),
+    stocktake_id UUID REFERENCES stocktakes(id),
+    old_quantity INTEGER NOT NULL,
+    new_quantity INTEGER NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_product ON stock_adjustments(product_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,54 @@
+-- Migration: 038_create_purchase_orders.sql
+-- Description: Suppliers, purchase orders received into stock, and cost prices for gross-margin reporting
+-- Created: 2026-03-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS suppliers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(20),
+    email VARCHAR(255),
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_id UUID NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'ORDERED' CHECK (status IN ('ORDERED', 'RECEIVED', 'CANCELLED')),
+    reference VARCHAR(100),
+    notes TEXT,
+    total_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_by VARCHAR(64) NOT NULL,
+    received_by VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    received_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status, created_at DESC);
+
+-- received_quantity is filled in when the delivery arrives; it can be short of quantity.
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_cost DECIMAL(10, 2) NOT NULL CHECK (unit_cost >= 0),
+    received_quantity INTEGER,
+    UNIQUE (purchase_order_id, product_id)
+);
+
+-- Latest received cost per stock unit.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price DECIMAL(10, 2);
+
+-- Cost of one unit when the order was placed (from a recipe or combo components when the product
+-- has no cost of its own); NULL when it couldn't be costed.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_cost DECIMAL(10, 2);
+
+ALTER TABLE stock_adjustments ADD COLUMN IF NOT EXISTS purchase_order_id UUID REFERENCES purchase_orders(id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Migration: 039_create_price_history.sql
+-- Description: Audit trail of product price changes (who changed what, and from where)
+-- Created: 2026-03-16
+
+BEGIN;
+
+-- actor is the admin user ID from the JWT; source is 'manual' (price endpoint) or 'import' (CSV upload).
+CREATE TABLE IF NOT EXISTS price_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id),
+    old_price DECIMAL(10, 2) NOT NULL,
+    new_price DECIMAL(10, 2) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'import')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_product ON price_history(product_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- Migration: 040_create_audit_logs.sql
+-- Description: Audit log of mutating admin API requests (who changed what, from where)
+-- Created: 2026-03-16
+
+BEGIN;
+
+-- One row per POST/PUT/PATCH/DELETE under /api/admin. route is the matched pattern
+-- (e.g. /api/admin/products/:id/price) and entity_id its :id; changes holds the JSON
+-- request body with secrets (PINs, OTPs, tokens) redacted.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor VARCHAR(64) NOT NULL,
+    actor_name VARCHAR(255),
+    role VARCHAR(20),
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    entity_type VARCHAR(64),
+    entity_id VARCHAR(64),
+    status_code INTEGER NOT NULL,
+    changes JSONB,
+    ip VARCHAR(64),
+    request_id VARCHAR(128),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity_type, entity_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,50 @@
+-- Migration: 041_create_broadcasts.sql
+-- Description: Marketing consent on users, and opt-in broadcast campaigns with per-recipient delivery status
+-- Created: 2026-03-17
+
+BEGIN;
+
+-- Customers opt in with "subscribe" and out with "unsubscribe"; only opted-in customers get broadcasts
+ALTER TABLE users ADD COLUMN IF NOT EXISTS marketing_opt_in BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS marketing_opt_in_at TIMESTAMP;
+
+-- segment is 'all', 'recent' (a settled order in the last segment_days) or 'lapsed' (ordered before,
+-- but not in the last segment_days). template_name, when set, sends an approved WhatsApp template with
+-- message as its body parameter instead of a plain text message.
+CREATE TABLE IF NOT EXISTS broadcast_campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    template_name VARCHAR(255),
+    template_language VARCHAR(10),
+    segment VARCHAR(20) NOT NULL CHECK (segment IN ('all', 'recent', 'lapsed')),
+    segment_days INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'QUEUED' CHECK (status IN ('QUEUED', 'SENDING', 'COMPLETED', 'CANCELLED')),
+    total_recipients INTEGER NOT NULL DEFAULT 0,
+    created_by VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_campaigns_created_at ON broadcast_campaigns(created_at DESC);
+
+-- The audience is resolved when the campaign is created. SKIPPED recipients opted out before their
+-- turn; a SENDING row whose sender stopped before confirming is marked FAILED rather than resent.
+CREATE TABLE IF NOT EXISTS broadcast_recipients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL REFERENCES broadcast_campaigns(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    phone VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENDING', 'SENT', 'FAILED', 'SKIPPED', 'CANCELLED')),
+    error TEXT,
+    claimed_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (campaign_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_recipients_pending ON broadcast_recipients(status, created_at) WHERE status IN ('PENDING', 'SENDING');
+CREATE INDEX IF NOT EXISTS idx_broadcast_recipients_campaign ON broadcast_recipients(campaign_id, status);
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Migration: 042_create_order_feedback.sql
+-- Description: Post-order feedback: a 1-5 rating and optional comment asked after an order is completed
+-- Created: 2026-03-18
+
+BEGIN;
+
+-- One row per order asked for feedback, created when the request is sent (so each order is
+-- asked once, whichever replica gets there first). rating stays NULL until the customer answers.
+CREATE TABLE IF NOT EXISTS order_feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    customer_phone VARCHAR(20) NOT NULL,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_feedback_phone ON order_feedback(customer_phone, requested_at DESC);
+CREATE INDEX IF NOT EXISTS idx_order_feedback_rated_at ON order_feedback(rated_at) WHERE rating IS NOT NULL;
+
+COMMIT;
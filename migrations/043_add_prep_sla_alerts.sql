@@ -0,0 +1,13 @@
+-- Migration: 043_add_prep_sla_alerts.sql
+-- Description: Track which PAID orders were flagged for waiting longer than the preparation SLA
+-- Created: 2026-03-18
+
+BEGIN;
+
+-- Set when an order waiting in the PAID queue past PREP_SLA was flagged on the dashboard
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS prep_sla_alerted_at TIMESTAMP;
+
+-- Preparation time reports look up when each order was paid
+CREATE INDEX IF NOT EXISTS idx_order_status_history_to_status ON order_status_history(order_id, to_status, created_at);
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Migration: 044_create_shifts.sql
+-- Description: Bartender shifts (clock in/out) for shift-scoped sales reports and close-of-shift summaries
+-- Created: 2026-03-19
+
+BEGIN;
+
+-- opened_via/closed_via record where the clock in/out came from: 'dashboard' or 'whatsapp'
+CREATE TABLE IF NOT EXISTS shifts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bar_staff_id UUID NOT NULL REFERENCES bar_staff(id),
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP,
+    opened_via VARCHAR(20) NOT NULL DEFAULT 'dashboard',
+    closed_via VARCHAR(20),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+-- A bartender can only be clocked in once at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shifts_one_open ON shifts(bar_staff_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_shifts_started_at ON shifts(started_at);
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- Migration: 045_add_product_search_trgm.sql
+-- Description: Trigram index for typo-tolerant, ranked product search
+-- Created: 2026-03-20
+
+BEGIN;
+
+-- pg_trgm ships with Postgres but creating it may need extra privileges on managed databases.
+-- Without it product search keeps using LIKE, so a missing extension isn't an error here.
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+EXCEPTION WHEN insufficient_privilege OR undefined_file THEN
+    RAISE NOTICE 'pg_trgm unavailable (%), product search falls back to LIKE', SQLERRM;
+END $$;
+
+-- Serves both the similarity matches and the LIKE '%query%' substring matches
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm') THEN
+        CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING gin (LOWER(name) gin_trgm_ops);
+    END IF;
+END $$;
+
+COMMIT;
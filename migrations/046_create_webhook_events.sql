@@ -0,0 +1,29 @@
+-- Migration: 046_create_webhook_events.sql
+-- Description: Archive of raw inbound WhatsApp and payment webhooks for debugging and replay
+-- Created: 2026-03-21
+
+BEGIN;
+
+-- One row per POST to /api/webhooks/whatsapp or /api/webhooks/payment, including ones refused
+-- by signature verification. signature_valid is NULL when the source isn't verified (no
+-- WhatsApp app secret); status_code and response are what the endpoint answered. Replays
+-- re-run processing on body and bump replay_count.
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source VARCHAR(20) NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
+    body TEXT NOT NULL,
+    signature_valid BOOLEAN,
+    status_code INTEGER NOT NULL,
+    response TEXT,
+    ip VARCHAR(64),
+    request_id VARCHAR(128),
+    replay_count INTEGER NOT NULL DEFAULT 0,
+    last_replayed_at TIMESTAMP,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_received_at ON webhook_events(received_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_source ON webhook_events(source, received_at DESC);
+
+COMMIT;
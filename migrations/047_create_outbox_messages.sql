@@ -0,0 +1,27 @@
+-- Migration: 047_create_outbox_messages.sql
+-- Description: Transactional outbox for the side effects of an order being paid
+-- Created: 2026-03-22
+
+BEGIN;
+
+-- Written in the same transaction that moves an order to PAID or SCHEDULED, one row per side
+-- effect (customer confirmation, bar staff notification, dashboard event), so a crash after the
+-- commit can't lose them. The dispatcher claims PENDING rows whose next_attempt_at has passed,
+-- pushing next_attempt_at out while it works so other replicas skip them, then marks them SENT
+-- or schedules a retry; rows out of attempts become FAILED.
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    topic VARCHAR(50) NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_order ON outbox_messages(order_id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- Migration: 048_create_favorites.sql
+-- Description: Baskets customers save as favorites after paying, to reorder in one tap
+-- Created: 2026-03-23
+
+BEGIN;
+
+-- Items hold the cart lines as paid (product, quantity, serving options); a reorder prices them
+-- from the current menu and skips products that are off the menu or out of stock.
+CREATE TABLE IF NOT EXISTS favorites (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(60) NOT NULL,
+    items JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favorites_user_name ON favorites(user_id, LOWER(name));
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Migration: 049_create_reservations.sql
+-- Description: Table and VIP booth reservations booked through the bot
+-- Created: 2026-03-24
+
+BEGIN;
+
+-- A reservation with a deposit stays PENDING_DEPOSIT until its STK push is paid (the payment
+-- webhook's metadata carries the reservation ID); unpaid ones are cancelled after a while.
+-- reminded_at is set by the reminder job so each booking gets one on-the-day reminder.
+CREATE TABLE IF NOT EXISTS reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    customer_phone VARCHAR(20) NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'TABLE',
+    party_size INTEGER NOT NULL CHECK (party_size > 0),
+    reserved_for TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'CONFIRMED',
+    deposit_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    deposit_reference VARCHAR(100),
+    deposit_paid_at TIMESTAMP,
+    reminded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_reserved_for ON reservations(reserved_for);
+CREATE INDEX IF NOT EXISTS idx_reservations_pending ON reservations(created_at) WHERE status = 'PENDING_DEPOSIT';
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Migration: 050_add_age_confirmation.sql
+-- Description: Record customers' 18+ confirmation and copy it onto their orders
+-- Created: 2026-03-25
+
+BEGIN;
+
+-- Set once when the customer confirms they're 18 or older; the bot won't take orders until then
+ALTER TABLE users ADD COLUMN IF NOT EXISTS age_confirmed_at TIMESTAMP;
+
+-- The customer's confirmation in force when the order was placed, for compliance exports
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS age_confirmed_at TIMESTAMP;
+
+COMMIT;
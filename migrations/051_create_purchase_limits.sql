@@ -0,0 +1,20 @@
+-- Migration: 051_create_purchase_limits.sql
+-- Description: Per-order quantity caps on menu categories and products
+-- Created: 2026-03-26
+
+BEGIN;
+
+-- scope is 'CATEGORY' (target is the category name, matched case-insensitively) or 'PRODUCT'
+-- (target is the product ID). The bot refuses to add past max_quantity and checks again at checkout.
+CREATE TABLE IF NOT EXISTS purchase_limits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scope VARCHAR(20) NOT NULL,
+    target VARCHAR(100) NOT NULL,
+    max_quantity INTEGER NOT NULL CHECK (max_quantity > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchase_limits_scope_target ON purchase_limits(scope, LOWER(target));
+
+COMMIT;
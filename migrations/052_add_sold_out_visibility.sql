@@ -0,0 +1,24 @@
+-- Migration: 052_add_sold_out_visibility.sql
+-- Description: Hide sold-out products from the bot's menu and tell subscribed customers when they're back
+-- Created: 2026-03-27
+
+BEGIN;
+
+-- hide_when_sold_out overrides the menu.hide_out_of_stock setting for one product; NULL follows it.
+-- sold_out_at is set by the restock job while a product has no stock and cleared once it's replenished,
+-- which is when the back-in-stock event and messages go out.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS hide_when_sold_out BOOLEAN;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sold_out_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_products_sold_out ON products(sold_out_at) WHERE sold_out_at IS NOT NULL;
+
+-- Customers who tapped "Notify me" on a sold-out product; rows are removed once they've been told.
+CREATE TABLE IF NOT EXISTS restock_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, phone)
+);
+
+COMMIT;
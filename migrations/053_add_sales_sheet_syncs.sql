@@ -0,0 +1,14 @@
+-- Migration: 053_add_sales_sheet_syncs.sql
+-- Description: Business days whose summary row was appended to the owner's Google Sheet
+-- Created: 2026-03-28
+
+BEGIN;
+
+-- A row is claimed before the append so only one replica writes each day, and removed again when the
+-- append fails so the next run retries it.
+CREATE TABLE IF NOT EXISTS sales_sheet_syncs (
+    business_date DATE PRIMARY KEY,
+    synced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Migration: 054_create_whatsapp_numbers.sql
+-- Description: WhatsApp numbers served by this server with their own tokens and menu categories, and the number each customer last messaged
+-- Created: 2026-03-29
+
+BEGIN;
+
+-- Numbers not listed here (normally just WHATSAPP_PHONE_NUMBER_ID) use the token from the environment
+-- and offer the whole menu.
+CREATE TABLE IF NOT EXISTS whatsapp_numbers (
+    phone_number_id VARCHAR(50) PRIMARY KEY,
+    display_phone_number VARCHAR(30) NOT NULL DEFAULT '',
+    venue_name VARCHAR(100) NOT NULL,
+    access_token TEXT NOT NULL,
+    categories JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Receipts, reminders and staff alerts sent outside a conversation go out from the number the customer
+-- last messaged.
+CREATE TABLE IF NOT EXISTS whatsapp_contacts (
+    phone VARCHAR(20) PRIMARY KEY,
+    phone_number_id VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
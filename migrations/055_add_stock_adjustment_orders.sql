@@ -0,0 +1,12 @@
+-- Migration: 055_add_stock_adjustment_orders.sql
+-- Description: Record recipe drawdown in stock_adjustments against the order that poured it
+-- Created: 2026-03-30
+
+BEGIN;
+
+-- Set on "recipe" adjustments: the ingredients taken out of stock when the order was paid.
+ALTER TABLE stock_adjustments ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES orders(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_order ON stock_adjustments(order_id) WHERE order_id IS NOT NULL;
+
+COMMIT;